@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"encoding/json"
+	"strings"
+	"text/template"
+)
+
+// Functions available in the templates of the alert forwarder destinations
+var alertForwarderTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join":    strings.Join,
+	"toUpper": strings.ToUpper,
+	"toLower": strings.ToLower,
+}
+
+// Parses the template of an alert forwarder destination. Shared by the webhook and the forwarder so
+// that templates accepted by the webhook can be rendered
+func ParseAlertForwarderTemplate(name string, text string) (*template.Template, error) {
+	return template.New(name).Funcs(alertForwarderTemplateFuncs).Option("missingkey=zero").Parse(text)
+}
@@ -7,7 +7,9 @@ type AlertmanagerConfigGlobal struct {
 type AlertmanagerConfigRoute struct {
 	Receiver       string                    `json:"receiver,omitempty"`
 	Match          map[string]string         `json:"match,omitempty"`
+	MatchRe        map[string]string         `json:"match_re,omitempty"`
 	RepeatInterval string                    `json:"repeat_interval,omitempty"`
+	Continue       bool                      `json:"continue,omitempty"`
 	Routes         []AlertmanagerConfigRoute `json:"routes,omitempty"`
 }
 
@@ -15,8 +17,13 @@ type PagerDutyConfig struct {
 	ServiceKey string `json:"service_key"`
 }
 
+type HttpConfig struct {
+	BearerTokenFile string `json:"bearer_token_file,omitempty"`
+}
+
 type WebhookConfig struct {
-	Url string `json:"url"`
+	Url        string      `json:"url"`
+	HttpConfig *HttpConfig `json:"http_config,omitempty"`
 }
 
 type AlertmanagerConfigReceiver struct {
@@ -25,8 +32,18 @@ type AlertmanagerConfigReceiver struct {
 	WebhookConfigs   []WebhookConfig   `json:"webhook_configs,omitempty"`
 }
 
+type AlertmanagerConfigInhibitRule struct {
+	SourceMatch   map[string]string `json:"source_match,omitempty"`
+	SourceMatchRe map[string]string `json:"source_match_re,omitempty"`
+	TargetMatch   map[string]string `json:"target_match,omitempty"`
+	TargetMatchRe map[string]string `json:"target_match_re,omitempty"`
+	Equal         []string          `json:"equal,omitempty"`
+}
+
 type AlertmanagerConfigRoot struct {
-	Global    *AlertmanagerConfigGlobal    `json:"global,omitempty"`
-	Route     *AlertmanagerConfigRoute     `json:"route,omitempty"`
-	Receivers []AlertmanagerConfigReceiver `json:"receivers,omitempty"`
+	Global       *AlertmanagerConfigGlobal       `json:"global,omitempty"`
+	Route        *AlertmanagerConfigRoute        `json:"route,omitempty"`
+	Receivers    []AlertmanagerConfigReceiver    `json:"receivers,omitempty"`
+	InhibitRules []AlertmanagerConfigInhibitRule `json:"inhibit_rules,omitempty"`
+	Templates    []string                        `json:"templates,omitempty"`
 }
@@ -17,6 +17,26 @@ type RepositoryInfo struct {
 type GrafanaIndex struct {
 	Dashboards             []string           `json:"dashboards"`
 	DashboardLabelSelector *v13.LabelSelector `json:"dashboardLabelSelector,omitempty"`
+	// Put dashboards into a folder named after the directory path they are located in
+	FoldersFromDirectories bool                 `json:"foldersFromDirectories,omitempty"`
+	Folders                []GrafanaFolderIndex `json:"folders,omitempty"`
+	Plugins                []GrafanaPlugin      `json:"plugins,omitempty"`
+}
+
+type GrafanaFolderIndex struct {
+	Name string `json:"name"`
+	// Paths of the dashboards in this folder, as listed in the dashboards of the index
+	Dashboards  []string                  `json:"dashboards,omitempty"`
+	Permissions []GrafanaFolderPermission `json:"permissions,omitempty"`
+}
+
+// Either a team or a role is granted a permission on a folder
+type GrafanaFolderPermission struct {
+	Team string `json:"team,omitempty"`
+	// Viewer or Editor
+	Role string `json:"role,omitempty"`
+	// View, Edit or Admin
+	Permission string `json:"permission"`
 }
 
 type DexConfig struct {
@@ -78,6 +98,8 @@ type AlertmanagerIndex struct {
 	PagerDutySecretNamespace      string `json:"pagerDutySecretNamespace"`
 	DeadmansSnitchSecretName      string `json:"deadmansSnitchSecretName"`
 	DeadmansSnitchSecretNamespace string `json:"deadmansSnitchSecretNamespace"`
+	// Notification template files (*.tmpl)
+	Templates []string `json:"templates,omitempty"`
 }
 
 type PrometheusIndex struct {
@@ -96,6 +118,8 @@ type PrometheusIndex struct {
 	RuleNamespaceSelector           *v13.LabelSelector `json:"ruleNamespaceSelector,omitempty"`
 	ProbeLabelSelector              *v13.LabelSelector `json:"probeSelector,omitempty"`
 	ProbeNamespaceSelector          *v13.LabelSelector `json:"probeNamespaceSelector,omitempty"`
+	// Promtool unit test files of the rules. Their rule_files reference entries of rules
+	RuleTests []string `json:"rule_tests,omitempty"`
 }
 
 type PromtailIndex struct {
@@ -105,11 +129,17 @@ type PromtailIndex struct {
 	DaemonSetLabelSelector *v13.LabelSelector `json:"daemonSetLabelSelector,omitempty"`
 }
 
+type LokiIndex struct {
+	// Rule files in the format of the Loki ruler
+	Rules []string `json:"rules,omitempty"`
+}
+
 type RepositoryConfig struct {
 	Grafana      *GrafanaIndex        `json:"grafana,omitempty"`
 	Prometheus   *PrometheusIndex     `json:"prometheus,omitempty"`
 	Alertmanager *AlertmanagerIndex   `json:"alertmanager,omitempty"`
 	Promtail     *PromtailIndex       `json:"promtail,omitempty"`
+	Loki         *LokiIndex           `json:"loki,omitempty"`
 	Observatoria []ObservatoriumIndex `json:"observatoria,omitempty"`
 }
 
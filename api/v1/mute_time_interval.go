@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// MuteWindows are the recurring windows of a mute time interval
+// +kubebuilder:object:generate=false
+type MuteWindows struct {
+	weekdays map[time.Weekday]bool
+	// Minutes after midnight, start and end are equal for whole days
+	start, end int
+	location   *time.Location
+}
+
+func ParseMuteWindows(interval *MuteTimeInterval) (*MuteWindows, error) {
+	result := &MuteWindows{
+		weekdays: map[time.Weekday]bool{},
+		location: time.UTC,
+	}
+
+	for _, weekdays := range interval.Weekdays {
+		bounds := strings.SplitN(strings.ToLower(weekdays), ":", 2)
+		from, ok := weekdayNames[bounds[0]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday: %v", weekdays)
+		}
+		to := from
+		if len(bounds) == 2 {
+			to, ok = weekdayNames[bounds[1]]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday: %v", weekdays)
+			}
+		}
+		// Ranges wrap around the end of the week, e.g. saturday:sunday
+		for day := from; ; day = (day + 1) % 7 {
+			result.weekdays[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	if len(interval.Weekdays) == 0 {
+		for _, day := range weekdayNames {
+			result.weekdays[day] = true
+		}
+	}
+
+	if (interval.StartTime == "") != (interval.EndTime == "") {
+		return nil, fmt.Errorf("start and end time have to be set together")
+	}
+	if interval.StartTime != "" {
+		var err error
+		result.start, err = parseMuteTime(interval.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		result.end, err = parseMuteTime(interval.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		if result.start == result.end {
+			return nil, fmt.Errorf("start and end time are equal, leave both empty for whole days")
+		}
+	}
+
+	if interval.TimeZone != "" {
+		var err error
+		result.location, err = time.LoadLocation(interval.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %v: %v", interval.TimeZone, err)
+		}
+	}
+	return result, nil
+}
+
+func parseMuteTime(value string) (int, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time %v, expected HH:MM", value)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, fmt.Errorf("invalid time %v, expected HH:MM", value)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time %v, expected HH:MM", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// Returns the window of the day in the time zone of the windows, if the day has one
+func (w *MuteWindows) window(day time.Time) (time.Time, time.Time, bool) {
+	if !w.weekdays[day.Weekday()] {
+		return time.Time{}, time.Time{}, false
+	}
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, w.start, 0, 0, w.location)
+	end := time.Date(day.Year(), day.Month(), day.Day(), 0, w.end, 0, 0, w.location)
+	if w.end <= w.start {
+		end = time.Date(day.Year(), day.Month(), day.Day()+1, 0, w.end, 0, 0, w.location)
+	}
+	return start, end, true
+}
+
+// Next returns the window that contains t, or the first window after t. Consecutive windows are
+// joined for up to a week, e.g. a weekend of two whole days is a single window
+func (w *MuteWindows) Next(t time.Time) (time.Time, time.Time) {
+	local := t.In(w.location)
+	var start, end time.Time
+	// Windows of the previous day may end after midnight
+	for i := -1; i <= 7; i++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+i, 0, 0, 0, 0, w.location)
+		s, e, ok := w.window(day)
+		if !ok {
+			continue
+		}
+		if start.IsZero() {
+			if e.After(t) {
+				start, end = s, e
+			}
+			continue
+		}
+		if !s.Equal(end) || e.Sub(start) > 7*24*time.Hour {
+			break
+		}
+		end = e
+	}
+	return start.UTC(), end.UTC()
+}
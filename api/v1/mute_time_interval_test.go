@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMuteWindows_Next(t *testing.T) {
+	// 2021-08-04 is a Wednesday
+	wednesday := time.Date(2021, 8, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		interval  MuteTimeInterval
+		from      time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "next window of the day",
+			interval:  MuteTimeInterval{StartTime: "18:00", EndTime: "20:00"},
+			from:      wednesday,
+			wantStart: time.Date(2021, 8, 4, 18, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 4, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "window containing the time",
+			interval:  MuteTimeInterval{StartTime: "11:00", EndTime: "13:00"},
+			from:      wednesday,
+			wantStart: time.Date(2021, 8, 4, 11, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 4, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "window of the day ended, day rollover",
+			interval:  MuteTimeInterval{StartTime: "08:00", EndTime: "10:00"},
+			from:      wednesday,
+			wantStart: time.Date(2021, 8, 5, 8, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 5, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "window of the previous day ends after midnight",
+			interval:  MuteTimeInterval{StartTime: "22:00", EndTime: "06:00"},
+			from:      time.Date(2021, 8, 4, 3, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2021, 8, 3, 22, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 4, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "whole days of a weekend are joined",
+			interval:  MuteTimeInterval{Weekdays: []string{"saturday:sunday"}},
+			from:      wednesday,
+			wantStart: time.Date(2021, 8, 7, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// Windows that ended before the time are not joined
+			name:      "joined window containing the time",
+			interval:  MuteTimeInterval{Weekdays: []string{"saturday", "sunday"}},
+			from:      time.Date(2021, 8, 8, 10, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2021, 8, 8, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "ranges wrap around the end of the week",
+			interval:  MuteTimeInterval{Weekdays: []string{"sunday:monday"}, StartTime: "09:00", EndTime: "10:00"},
+			from:      wednesday,
+			wantStart: time.Date(2021, 8, 8, 9, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 8, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "overnight windows of consecutive days with a gap are not joined",
+			interval:  MuteTimeInterval{Weekdays: []string{"friday:saturday"}, StartTime: "18:00", EndTime: "08:00"},
+			from:      wednesday,
+			wantStart: time.Date(2021, 8, 6, 18, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 7, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "every day is joined for up to a week",
+			interval:  MuteTimeInterval{},
+			from:      wednesday,
+			wantStart: time.Date(2021, 8, 4, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "month rollover",
+			interval:  MuteTimeInterval{Weekdays: []string{"monday"}},
+			from:      time.Date(2021, 7, 31, 12, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "year rollover",
+			interval:  MuteTimeInterval{Weekdays: []string{"saturday"}, StartTime: "22:00", EndTime: "02:00"},
+			from:      time.Date(2021, 12, 31, 12, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2022, 1, 1, 22, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2022, 1, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "days and times in the time zone",
+			interval:  MuteTimeInterval{Weekdays: []string{"monday:friday"}, StartTime: "09:00", EndTime: "17:00", TimeZone: "Europe/Berlin"},
+			from:      time.Date(2021, 8, 7, 12, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2021, 8, 9, 7, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 9, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "weekday in the time zone differs from UTC",
+			interval:  MuteTimeInterval{Weekdays: []string{"saturday"}, TimeZone: "Asia/Tokyo"},
+			from:      time.Date(2021, 8, 6, 16, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2021, 8, 6, 15, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 8, 7, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "daylight saving time ends in the window",
+			interval:  MuteTimeInterval{Weekdays: []string{"sunday"}, StartTime: "01:00", EndTime: "04:00", TimeZone: "Europe/Berlin"},
+			from:      time.Date(2021, 10, 30, 12, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2021, 10, 30, 23, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2021, 10, 31, 3, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, err := ParseMuteWindows(&tt.interval)
+			if err != nil {
+				t.Fatalf("ParseMuteWindows() error = %v", err)
+			}
+			start, end := windows.Next(tt.from)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("Next() = %v - %v, want %v - %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParseMuteWindows(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval MuteTimeInterval
+		wantErr  bool
+	}{
+		{name: "whole days", interval: MuteTimeInterval{Weekdays: []string{"Saturday:Sunday"}}},
+		{name: "unknown weekday", interval: MuteTimeInterval{Weekdays: []string{"sat"}}, wantErr: true},
+		{name: "start without end", interval: MuteTimeInterval{StartTime: "08:00"}, wantErr: true},
+		{name: "equal start and end", interval: MuteTimeInterval{StartTime: "08:00", EndTime: "08:00"}, wantErr: true},
+		{name: "invalid time", interval: MuteTimeInterval{StartTime: "24:00", EndTime: "08:00"}, wantErr: true},
+		{name: "unknown time zone", interval: MuteTimeInterval{TimeZone: "Mars/Olympus"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMuteWindows(&tt.interval)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseMuteWindows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"sort"
+	"time"
+
 	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,16 +34,42 @@ type ObservabilityStageStatus string
 
 type ObservabilityAuthType string
 
+type InstallPlanApprovalPolicy string
+
+type SubscriptionState string
+
+type UIAccessType string
+
+// ComponentMode controls whether a component is installed by the operator
+type ComponentMode string
+
 const (
-	GrafanaInstallation      ObservabilityStageName = "Grafana"
-	GrafanaConfiguration     ObservabilityStageName = "GrafanaConfiguration"
-	PrometheusInstallation   ObservabilityStageName = "Prometheus"
-	PrometheusConfiguration  ObservabilityStageName = "PrometheusConfiguration"
-	Csv                      ObservabilityStageName = "Csv"
-	TokenRequest             ObservabilityStageName = "TokenRequest"
-	PromtailInstallation     ObservabilityStageName = "PromtailInstallation"
-	AlertmanagerInstallation ObservabilityStageName = "AlertmanagerInstallation"
-	Configuration            ObservabilityStageName = "configuration"
+	GrafanaInstallation           ObservabilityStageName = "Grafana"
+	GrafanaConfiguration          ObservabilityStageName = "GrafanaConfiguration"
+	PrometheusInstallation        ObservabilityStageName = "Prometheus"
+	PrometheusConfiguration       ObservabilityStageName = "PrometheusConfiguration"
+	Csv                           ObservabilityStageName = "Csv"
+	TokenRequest                  ObservabilityStageName = "TokenRequest"
+	CapabilityDetection           ObservabilityStageName = "CapabilityDetection"
+	PromtailInstallation          ObservabilityStageName = "PromtailInstallation"
+	AlertmanagerInstallation      ObservabilityStageName = "AlertmanagerInstallation"
+	Configuration                 ObservabilityStageName = "configuration"
+	TenantVerification            ObservabilityStageName = "TenantVerification"
+	TracingInstallation           ObservabilityStageName = "TracingInstallation"
+	AlertForwarderInstallation    ObservabilityStageName = "AlertForwarderInstallation"
+	AlertTicketingInstallation    ObservabilityStageName = "AlertTicketingInstallation"
+	AlertHistoryInstallation      ObservabilityStageName = "AlertHistoryInstallation"
+	PrometheusAdapterInstallation ObservabilityStageName = "PrometheusAdapterInstallation"
+	SelfMonitoringConfiguration   ObservabilityStageName = "SelfMonitoringConfiguration"
+	InternalTLS                   ObservabilityStageName = "InternalTLS"
+	StackVerification             ObservabilityStageName = "StackVerification"
+	ExternalSecretSync            ObservabilityStageName = "ExternalSecretSync"
+	DatasourceHealthCheck         ObservabilityStageName = "DatasourceHealthCheck"
+	ConsoleIntegration            ObservabilityStageName = "ConsoleIntegration"
+	VersionCheck                  ObservabilityStageName = "VersionCheck"
+	ProfilesConfiguration         ObservabilityStageName = "ProfilesConfiguration"
+	AccessConfiguration           ObservabilityStageName = "AccessConfiguration"
+	Migration                     ObservabilityStageName = "Migration"
 )
 
 const (
@@ -54,6 +83,172 @@ const (
 	AuthTypeRedhat ObservabilityAuthType = "redhat"
 )
 
+const (
+	// OLM approves install plans
+	InstallPlanApprovalAutomatic InstallPlanApprovalPolicy = "Automatic"
+	// Install plans have to be approved by the user
+	InstallPlanApprovalManual InstallPlanApprovalPolicy = "Manual"
+	// The operator approves install plans and rolls back to the previous CSV if an upgrade fails
+	InstallPlanApprovalRollback InstallPlanApprovalPolicy = "Rollback"
+)
+
+const (
+	// Expose the UIs with OpenShift Routes
+	UIAccessRoute UIAccessType = "Route"
+	// Expose the UIs with Ingresses, e.g. when a different ingress controller is used
+	UIAccessIngress UIAccessType = "Ingress"
+)
+
+const (
+	// Installed and configured by the operator
+	ComponentManaged ComponentMode = "Managed"
+	// Installed by someone else. The operator does not install the component, but still provides
+	// its configuration, e.g. dashboards, rules and the Alertmanager config
+	ComponentExternal ComponentMode = "External"
+	// Neither installed nor configured. Removed if the operator installed it before
+	ComponentDisabled ComponentMode = "Disabled"
+)
+
+// Supported types of log metrics
+const (
+	LogMetricCounter   = "counter"
+	LogMetricHistogram = "histogram"
+)
+
+// Roles of Grafana API keys
+const (
+	GrafanaRoleViewer = "Viewer"
+	GrafanaRoleEditor = "Editor"
+	GrafanaRoleAdmin  = "Admin"
+)
+
+// Supported types of Grafana contact points
+const (
+	GrafanaContactPointEmail = "email"
+	GrafanaContactPointSlack = "slack"
+)
+
+const (
+	SubscriptionHealthy          SubscriptionState = "Healthy"
+	SubscriptionInstalling       SubscriptionState = "Installing"
+	SubscriptionApprovalRequired SubscriptionState = "ApprovalRequired"
+	SubscriptionFailed           SubscriptionState = "Failed"
+	SubscriptionRolledBack       SubscriptionState = "RolledBack"
+	// The install plan is not approved because the upgrade is not in the supported version matrix
+	SubscriptionBlocked SubscriptionState = "Blocked"
+	// The install plan is approved in the next upgrade window
+	SubscriptionDeferred SubscriptionState = "Deferred"
+)
+
+// Components of which the image can be overridden in spec.imageOverrides
+const (
+	ImagePrometheus             = "prometheus"
+	ImageAlertmanager           = "alertmanager"
+	ImageGrafana                = "grafana"
+	ImageOAuthProxy             = "oauth-proxy"
+	ImageOAuth2Proxy            = "oauth2-proxy"
+	ImageBlackboxExporter       = "blackbox-exporter"
+	ImagePromtail               = "promtail"
+	ImageTokenRefresher         = "token-refresher"
+	ImagePrometheusCatalogIndex = "prometheus-catalog-index"
+	ImageGrafanaCatalogIndex    = "grafana-catalog-index"
+	// Image with the Grafana plugins for disconnected clusters. Replaces the plugin downloads
+	ImageGrafanaPluginBundle  = "grafana-plugin-bundle"
+	ImageTempo                = "tempo"
+	ImageKubeRbacProxy        = "kube-rbac-proxy"
+	ImagePromLabelProxy       = "prom-label-proxy"
+	ImageThanos               = "thanos"
+	ImageGrafanaImageRenderer = "grafana-image-renderer"
+	ImagePrometheusAdapter    = "prometheus-adapter"
+	// Defaults to the image of the operator, which runs the alert forwarder
+	ImageAlertForwarder = "alert-forwarder"
+	// Defaults to the image of the operator, which runs the alert ticketing bridge
+	ImageAlertTicketing = "alert-ticketing"
+	// Defaults to the image of the operator, which runs the alert history
+	ImageAlertHistory = "alert-history"
+)
+
+// Workload profiles of spec.profiles
+const (
+	ProfileKafka        = "kafka"
+	ProfilePostgres     = "postgres"
+	ProfileRedis        = "redis"
+	ProfileNginxIngress = "nginx-ingress"
+)
+
+// Components of which the resource requirements can be set in spec.resources
+const (
+	ResourcesGrafana            = "grafana"
+	ResourcesGrafanaOperator    = "grafana-operator"
+	ResourcesPrometheus         = "prometheus"
+	ResourcesPrometheusOperator = "prometheus-operator"
+	ResourcesAlertmanager       = "alertmanager"
+	ResourcesPromtail           = "promtail"
+	ResourcesTokenRefresher     = "token-refresher"
+	ResourcesTempo              = "tempo"
+)
+
+// Condition types reported in the status of the Observability CR
+const (
+	AlertmanagerConfigLoaded = "AlertmanagerConfigLoaded"
+	ObservatoriumTenantReady = "ObservatoriumTenantReady"
+	RemoteWriteDegraded      = "RemoteWriteDegraded"
+	// An upgrade of the Prometheus operator is pending but not supported on this cluster
+	PrometheusOperatorUpgradeBlocked = "PrometheusOperatorUpgradeBlocked"
+	// Reconciliation of the CR or some of its stages is paused by annotation
+	Paused = "Paused"
+	// The certificates for spec.tls.internal are issued
+	InternalTLSReady = "InternalTLSReady"
+	// APIs of optional CRDs are not served, the stages skip the resources of them
+	Degraded = "Degraded"
+	// Certificates or credentials of the stack expire within the expiry monitoring window
+	CredentialsExpiring = "CredentialsExpiring"
+	// All checks of the last stack verification passed
+	StackVerified = "StackVerified"
+	// The datasources provisioned by the operator answered the last health check
+	DatasourcesHealthy = "DatasourcesHealthy"
+	// The secrets of spec.externalSecrets are materialized and their last refresh succeeded
+	ExternalSecretsReady = "ExternalSecretsReady"
+	// Upgrades or rollouts are held back until the next upgrade window
+	RolloutsDeferred = "RolloutsDeferred"
+	// The Loki rules of the last sync were pushed to the ruler
+	LogRulesSynced = "LogRulesSynced"
+	// A component runs a version other than the one the operator expects
+	VersionSkew = "VersionSkew"
+	// The alerting rules of the last sync conform to the rule policy
+	RulePolicyCompliant = "RulePolicyCompliant"
+	// No alerting rule of the last sync duplicates a rule of the platform monitoring
+	PlatformRulesDistinct = "PlatformRulesDistinct"
+)
+
+// Reasons of the events emitted on the Observability CR
+const (
+	EventStageFailed          = "StageFailed"
+	EventInstallationComplete = "InstallationComplete"
+	EventCleanupInProgress    = "CleanupInProgress"
+	EventCleanupFailed        = "CleanupFailed"
+	EventCleanupComplete      = "CleanupComplete"
+	EventConfigFetchFailed    = "ConfigFetchFailed"
+	EventConfigSynced         = "ConfigSynced"
+	EventComponentInstalled   = "ComponentInstalled"
+	EventComponentUpgraded    = "ComponentUpgraded"
+	EventUpgradeFailed        = "UpgradeFailed"
+	EventUpgradeRolledBack    = "UpgradeRolledBack"
+	EventUpgradeBlocked       = "UpgradeBlocked"
+	EventPluginRejected       = "PluginRejected"
+	EventDriftDetected        = "DriftDetected"
+	EventReportFailed         = "ReportFailed"
+	EventRuleTestFailed       = "RuleTestFailed"
+	EventTenantSuspended      = "TenantSuspended"
+	EventConfigConflict       = "ConfigConflict"
+	// A configuration snapshot other than the last one was applied
+	EventConfigRevisionApplied = "ConfigRevisionApplied"
+	// The spec of a workload or of a Prometheus, Alertmanager or Grafana CR changed
+	EventComponentRolledOut = "ComponentRolledOut"
+	// An orphaned CSV, failed install plan or leftover catalog source was deleted
+	EventOLMResourcePruned = "OLMResourcePruned"
+)
+
 type Storage struct {
 	PrometheusStorageSpec *prometheusv1.StorageSpec `json:"prometheus,omitempty"`
 }
@@ -85,6 +280,1171 @@ type SelfContained struct {
 	PrometheusOperatorResourceRequirement v1.ResourceRequirements  `json:"prometheusOperatorResourceRequirement,omitempty"`
 	GrafanaResourceRequirement            *v1.ResourceRequirements `json:"grafanaResourceRequirement,omitempty"`
 	GrafanaOperatorResourceRequirement    v1.ResourceRequirements  `json:"grafanaOperatorResourceRequirement,omitempty"`
+	// Size of the Prometheus volume. Can only be increased, existing volumes are expanded in place
+	PrometheusStorageRequest string `json:"prometheusStorageRequest,omitempty"`
+	// Maximum number of bytes of blocks kept by Prometheus, e.g. 200GB
+	PrometheusRetentionSize string `json:"prometheusRetentionSize,omitempty"`
+	// Storage class of the Prometheus volume. Must allow volume expansion for resizing to work
+	PrometheusStorageClass *string `json:"prometheusStorageClass,omitempty"`
+	// Interval between scrapes, e.g. 30s
+	PrometheusScrapeInterval string `json:"prometheusScrapeInterval,omitempty"`
+	// Interval between rule evaluations, e.g. 30s
+	PrometheusEvaluationInterval string `json:"prometheusEvaluationInterval,omitempty"`
+	// Maximum number of samples a single query can load into memory
+	PrometheusQueryMaxSamples *int32 `json:"prometheusQueryMaxSamples,omitempty"`
+	// Maximum time a query may take before being aborted, e.g. 2m
+	PrometheusQueryTimeout string `json:"prometheusQueryTimeout,omitempty"`
+	// Enable compression of the Prometheus write-ahead log
+	PrometheusWALCompression *bool `json:"prometheusWalCompression,omitempty"`
+	// How the Prometheus and Alertmanager UIs are exposed
+	UIAccess *UIAccess `json:"uiAccess,omitempty"`
+	// URL under which Alertmanager is reachable, used in the links of notifications. Defaults to
+	// the Alertmanager route or ingress
+	AlertManagerExternalURL string `json:"alertManagerExternalURL,omitempty"`
+	// Alertmanagers in other clusters to form a cluster with, as host:port of their mesh port
+	AlertManagerPeers []string `json:"alertManagerPeers,omitempty"`
+	// Address other peers reach this Alertmanager on, as host:port. Required if the pod IP is not
+	// routable from the other clusters
+	AlertManagerClusterAdvertiseAddress string `json:"alertManagerClusterAdvertiseAddress,omitempty"`
+	// What Promtail tails in addition to the container logs
+	Logs *PromtailLogs `json:"logs,omitempty"`
+	// Number of Prometheus instances the pod monitor targets are distributed over. With more than
+	// one shard, Thanos Query provides a single query endpoint
+	PrometheusShards *int32 `json:"prometheusShards,omitempty"`
+	// server or agent. Agents are meant for edge clusters that only forward their metrics
+	PrometheusMode PrometheusRunMode `json:"prometheusMode,omitempty"`
+	// Log the queries of Prometheus and report the slow ones
+	QueryLogging *PrometheusQueryLogging `json:"queryLogging,omitempty"`
+}
+
+// PrometheusQueryLogging enables the query log of Prometheus. A Promtail sidecar turns the log into
+// metrics, which a generated dashboard shows next to the slowest queries
+type PrometheusQueryLogging struct {
+	// Queries evaluated in more time are counted as slow, by query. Defaults to 10s
+	SlowQueryThreshold string `json:"slowQueryThreshold,omitempty"`
+}
+
+// PromtailLogs selects the logs collected by Promtail
+type PromtailLogs struct {
+	// Namespaces of which the pod logs are tailed. Takes precedence over the namespace selectors of
+	// the configuration repositories
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Only the logs of pods matching the selector are tailed
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// Scrape the systemd journal of the nodes
+	Journal bool `json:"journal,omitempty"`
+	// Log files on the nodes. The file name may contain globs, e.g. /var/log/audit/*.log
+	HostPaths []string `json:"hostPaths,omitempty"`
+}
+
+// UIAccess configures how the Prometheus and Alertmanager UIs are exposed. The UIs are always
+// fronted by an OAuth proxy that requires users to log in with their OpenShift account, or with the
+// OIDC provider on other distributions
+type UIAccess struct {
+	// Route or Ingress. If empty, Routes are used when the cluster supports them and Ingresses otherwise
+	Type UIAccessType `json:"type,omitempty"`
+	// Host names of the UIs. Required for Ingresses, assigned by the router for Routes if empty
+	PrometheusHost   string `json:"prometheusHost,omitempty"`
+	AlertmanagerHost string `json:"alertmanagerHost,omitempty"`
+	GrafanaHost      string `json:"grafanaHost,omitempty"`
+	// Secret of type kubernetes.io/tls with a certificate for the hosts. Routes use the certificate
+	// of the router and Ingresses the default certificate of the ingress controller if empty
+	TLSSecret        string  `json:"tlsSecret,omitempty"`
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Additional annotations of the Ingresses, e.g. for cert-manager or external-dns
+	IngressAnnotations map[string]string `json:"ingressAnnotations,omitempty"`
+	// Groups and users allowed to access the UIs. If any are set, access requires permission to get
+	// the Prometheus or Alertmanager service instead of permission to get all namespaces
+	Groups []string `json:"groups,omitempty"`
+	Users  []string `json:"users,omitempty"`
+	// OIDC provider the users log in with on other distributions than OpenShift. Required there
+	OIDC *UIAccessOIDC `json:"oidc,omitempty"`
+}
+
+// UIAccessOIDC is the client of the OAuth proxies at an OIDC provider. Access is limited to the
+// groups of the groups claim, users can't be listed
+type UIAccessOIDC struct {
+	IssuerURL string `json:"issuerUrl"`
+	// Secret with the clientId and clientSecret keys. The redirect url of the client is
+	// https://<host>/oauth2/callback for every host of the UIs
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+type OLM struct {
+	// Approval policy for the install plans of the Prometheus and Grafana operator subscriptions:
+	// Automatic (default), Manual or Rollback
+	InstallPlanApproval InstallPlanApprovalPolicy `json:"installPlanApproval,omitempty"`
+}
+
+type ConfigRollout struct {
+	// Ring of the cluster, e.g. canary or stable. The configuration repositories are synced at the
+	// revision that is released to this ring
+	Ring string `json:"ring"`
+}
+
+// InhibitRule mutes alerts matching the target matchers while an alert matching the source
+// matchers is firing. Both alerts must have the same values for the equal labels
+type InhibitRule struct {
+	// Label values the source alert must have
+	SourceMatch map[string]string `json:"sourceMatch,omitempty"`
+	// Regular expressions the source alert labels must match
+	SourceMatchRegex map[string]string `json:"sourceMatchRegex,omitempty"`
+	// Label values the muted alerts must have
+	TargetMatch map[string]string `json:"targetMatch,omitempty"`
+	// Regular expressions the muted alert labels must match
+	TargetMatchRegex map[string]string `json:"targetMatchRegex,omitempty"`
+	Equal            []string          `json:"equal,omitempty"`
+}
+
+// Types of the alert forwarder destinations
+const (
+	AlertForwarderTypeHTTP        = "http"
+	AlertForwarderTypeEventBridge = "eventbridge"
+	AlertForwarderTypeGoogleChat  = "googlechat"
+)
+
+// AlertForwarderEventBridge puts the notifications as events on an Amazon EventBridge event bus
+type AlertForwarderEventBridge struct {
+	Region string `json:"region"`
+	// Name or ARN of the event bus. Defaults to the default event bus
+	EventBus string `json:"eventBus,omitempty"`
+	// Source of the events. Defaults to observability-operator
+	Source string `json:"source,omitempty"`
+	// Secret with the access_key and secret_key keys of an IAM user allowed to put events
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// AlertForwarderDestination receives the alerts routed to it in the generated Alertmanager config
+type AlertForwarderDestination struct {
+	// Unique name, also used in the name of the Alertmanager receiver
+	Name string `json:"name"`
+	// http, eventbridge or googlechat
+	Type string `json:"type"`
+	// Secret with the url in the url key, for the http and googlechat types
+	URLSecret string `json:"urlSecret,omitempty"`
+	// Go template rendered with the Alertmanager webhook payload. It is the request body for the
+	// http type, the message text for the googlechat type and the event detail, which has to be
+	// JSON, for the eventbridge type. The http and eventbridge types send the payload as is if empty
+	Template string `json:"template,omitempty"`
+	// Labels alerts must have to be forwarded to the destination. All alerts are forwarded if empty
+	Match       map[string]string          `json:"match,omitempty"`
+	EventBridge *AlertForwarderEventBridge `json:"eventBridge,omitempty"`
+}
+
+// AlertForwarder is an HTTP receiver run by the operator that forwards Alertmanager notifications to
+// destinations Alertmanager doesn't support natively
+type AlertForwarder struct {
+	Destinations []AlertForwarderDestination `json:"destinations"`
+}
+
+const (
+	AlertTicketingTypeServiceNow = "servicenow"
+	AlertTicketingTypeJira       = "jira"
+)
+
+// AlertTicketingServiceNow are the settings of the servicenow type
+type AlertTicketingServiceNow struct {
+	// Table the records are created in. Defaults to incident
+	Table string `json:"table,omitempty"`
+	// Fields set when the alert resolves, rendered like the fields of the ticket. Defaults to
+	// state 6 (Resolved) with a close code and notes
+	ResolveFields map[string]string `json:"resolveFields,omitempty"`
+}
+
+// AlertTicketingJira are the settings of the jira type
+type AlertTicketingJira struct {
+	// Key of the project the issues are created in
+	Project string `json:"project"`
+	// Defaults to Task
+	IssueType string `json:"issueType,omitempty"`
+	// Name of the transition applied when the alert resolves. Defaults to Done
+	ResolveTransition string `json:"resolveTransition,omitempty"`
+}
+
+// AlertTicketing is a bridge run by the operator that opens a ServiceNow incident or Jira issue for
+// every firing alert routed to it, and resolves it when the alert resolves. Tickets are looked up
+// by the fingerprint of the alert, an alert that is still firing or fires again while its ticket
+// is open doesn't open another one
+type AlertTicketing struct {
+	// servicenow or jira
+	Type string `json:"type"`
+	// Url of the instance, e.g. https://example.service-now.com or https://example.atlassian.net
+	URL string `json:"url"`
+	// Secret with the username and password keys. The password of Jira Cloud is an API token
+	CredentialsSecret string `json:"credentialsSecret"`
+	// Labels alerts must have to open tickets. All alerts open tickets if empty
+	Match map[string]string `json:"match,omitempty"`
+	// Fields of the incident or issue, by API name. The values are Go templates rendered with the
+	// alert, values that render to a JSON object or array are sent as JSON, e.g. a Jira priority
+	// of {"name": "High"}. Defaults to a summary and description of the alert
+	Fields map[string]string `json:"fields,omitempty"`
+	// Resolve the ticket when the alert resolves. Defaults to true
+	AutoResolve *bool                     `json:"autoResolve,omitempty"`
+	ServiceNow  *AlertTicketingServiceNow `json:"serviceNow,omitempty"`
+	Jira        *AlertTicketingJira       `json:"jira,omitempty"`
+}
+
+// AlertHistory records the state transitions of all alerts on a volume, so that they can be looked
+// up long after Alertmanager forgot them. The transitions are served by a query API and shown in a
+// Grafana dashboard
+type AlertHistory struct {
+	// Days the transitions are kept. Defaults to 90
+	RetentionDays int `json:"retentionDays,omitempty"`
+	// Size of the volume, e.g. 5Gi. Defaults to 1Gi
+	Size         string  `json:"size,omitempty"`
+	StorageClass *string `json:"storageClass,omitempty"`
+}
+
+// AlertTemplates configures the Alertmanager notification templates of the CR
+type AlertTemplates struct {
+	// ConfigMaps in the namespace of the CR with matching labels, every key is a template file
+	ConfigMapSelector *metav1.LabelSelector `json:"configMapSelector,omitempty"`
+}
+
+type Alerting struct {
+	// Inhibit rules added to the generated Alertmanager config
+	InhibitRules []InhibitRule   `json:"inhibitRules,omitempty"`
+	Forwarder    *AlertForwarder `json:"forwarder,omitempty"`
+	Ticketing    *AlertTicketing `json:"ticketing,omitempty"`
+	History      *AlertHistory   `json:"history,omitempty"`
+	// Notification templates, in addition to those of the configuration repositories
+	Templates *AlertTemplates `json:"templates,omitempty"`
+	// Recurring windows in which the notifications of matching alerts are muted, e.g. outside of
+	// business hours
+	MuteTimeIntervals []MuteTimeInterval `json:"muteTimeIntervals,omitempty"`
+}
+
+// MuteTimeInterval mutes the notifications of matching alerts during recurring windows. The
+// Alertmanager releases supported by the operator predate mute_time_intervals, so the operator
+// creates a silence for the current or next window
+type MuteTimeInterval struct {
+	// Unique name, used in the comment of the silences
+	Name string `json:"name"`
+	// Alerts with all of these labels are muted, e.g. severity: warning
+	Match map[string]string `json:"match"`
+	// Days of the week as names or ranges, e.g. saturday or monday:friday. Every day if empty
+	Weekdays []string `json:"weekdays,omitempty"`
+	// Start and end of the window on those days as HH:MM. An end before the start ends the window
+	// on the next day, e.g. 18:00 to 08:00. Whole days if both are empty
+	StartTime string `json:"startTime,omitempty"`
+	EndTime   string `json:"endTime,omitempty"`
+	// IANA time zone of the days and times, e.g. Europe/Berlin. UTC if empty
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// MuteTimeIntervalStatus is the silence of the current or next window of a mute time interval
+type MuteTimeIntervalStatus struct {
+	Name      string `json:"name"`
+	SilenceID string `json:"silenceId,omitempty"`
+	// Hash of the interval the silence was created for
+	Hash     string `json:"hash,omitempty"`
+	StartsAt int64  `json:"startsAt,omitempty"`
+	EndsAt   int64  `json:"endsAt,omitempty"`
+	// Error of the last attempt to create the silence, retried on the next reconcile
+	Error string `json:"error,omitempty"`
+}
+
+// TracingStorage configures where traces are stored. Without the Tempo operator a monolithic
+// Tempo with a local volume is deployed, with the operator a TempoStack backed by object storage
+type TracingStorage struct {
+	// Size of the Tempo volume, e.g. 10Gi
+	Size         string  `json:"size,omitempty"`
+	StorageClass *string `json:"storageClass,omitempty"`
+	// How long traces are kept, e.g. 48h
+	Retention string `json:"retention,omitempty"`
+	// Secret with the object storage credentials of the TempoStack. Required with the Tempo operator
+	ObjectStorageSecret string `json:"objectStorageSecret,omitempty"`
+	// Type of the object storage: s3, gcs or azure
+	ObjectStorageType string `json:"objectStorageType,omitempty"`
+}
+
+type Tracing struct {
+	// Tempo is only installed if storage is set
+	Storage *TracingStorage `json:"storage,omitempty"`
+}
+
+// UserWorkloadMonitoring uses the user workload monitoring of OpenShift instead of a Prometheus
+// installed by the operator
+type UserWorkloadMonitoring struct {
+	// Url of Thanos Querier, the Grafana datasource. Defaults to the Thanos Querier of openshift-monitoring
+	ThanosQuerierURL string `json:"thanosQuerierUrl,omitempty"`
+}
+
+// Components selects which components the operator installs. All components are managed by default
+type Components struct {
+	Prometheus     ComponentMode `json:"prometheus,omitempty"`
+	Alertmanager   ComponentMode `json:"alertmanager,omitempty"`
+	Grafana        ComponentMode `json:"grafana,omitempty"`
+	Promtail       ComponentMode `json:"promtail,omitempty"`
+	TokenRefresher ComponentMode `json:"tokenRefresher,omitempty"`
+}
+
+// PrometheusRunMode selects what the managed Prometheus is used for
+type PrometheusRunMode string
+
+const (
+	// Prometheus stores, queries and alerts on the collected metrics
+	PrometheusRunModeServer PrometheusRunMode = "server"
+	// Prometheus only forwards the collected metrics to Observatorium, without rules, alerting and
+	// local querying. Grafana and Alertmanager are disabled unless set in the components
+	PrometheusRunModeAgent PrometheusRunMode = "agent"
+)
+
+// MergeStrategy decides which configuration source provides a resource that several sources define
+type MergeStrategy string
+
+const (
+	// The source with the highest priority provides the resource
+	MergeFirstWins MergeStrategy = "FirstWins"
+	// The source with the lowest priority provides the resource
+	MergeLastWins MergeStrategy = "LastWins"
+	// The sync fails until the conflict is resolved in the sources
+	MergeErrorOnConflict MergeStrategy = "ErrorOnConflict"
+)
+
+// Kinds of the secrets of which the expiry is monitored
+const (
+	// Serving certificates of the oauth proxies and the tenant query proxy, and the certificates
+	// of spec.tls.internal and the Promtail clients
+	CredentialKindCertificate = "Certificate"
+	// CA certificates clients verify servers with
+	CredentialKindCABundle = "CABundle"
+	// Observatorium tokens fetched by the operator
+	CredentialKindObservatoriumToken = "ObservatoriumToken"
+	// Secrets referenced from the repository indexes, e.g. Dead Man's Snitch and PagerDuty
+	// credentials. They only have an expiry if annotated with one
+	CredentialKindCredentials = "Credentials"
+)
+
+// ExpiryMonitoring configures the reporting of expiring certificates and credentials
+type ExpiryMonitoring struct {
+	// How long before their expiry certificates and credentials are reported, e.g. 336h. Defaults
+	// to 720h. Observatorium tokens are refreshed by the operator and only reported once their
+	// refresh is overdue
+	Window string `json:"window,omitempty"`
+}
+
+// StackVerificationSpec periodically checks that the stack works end to end: a synthetic metric recorded
+// by Prometheus is sent by remote write, a test alert is routed by Alertmanager to a receiver
+// without notifications and the datasources of Grafana answer queries
+type StackVerificationSpec struct {
+	// Time between verifications, 15m if empty
+	Interval string `json:"interval,omitempty"`
+}
+
+// UpgradeWindow restricts when upgrades and rollouts that restart pods are applied: OLM install
+// plans, and changes of the images, sidecars, volumes and resources of Prometheus, Alertmanager,
+// Grafana and Promtail. Everything else, e.g. rules, dashboards and alerting config, is applied
+// immediately. Without schedule and windows rollouts are only held back during freezes
+type UpgradeWindow struct {
+	// Cron expression of the start of the windows, e.g. 0 2 * * 6. Times are in UTC
+	Schedule string `json:"schedule,omitempty"`
+	// Length of the windows of the schedule, e.g. 4h
+	Duration string `json:"duration,omitempty"`
+	// Recurring windows by weekday and time
+	Windows []UpgradeWindowRange `json:"windows,omitempty"`
+	// Periods in which nothing is rolled out, not even during a window
+	Freezes []ChangeFreeze `json:"freezes,omitempty"`
+}
+
+// UpgradeWindowRange is a recurring window, with the same fields as the mute time intervals
+type UpgradeWindowRange struct {
+	// Days of the week as names or ranges, e.g. saturday or monday:friday. Every day if empty
+	Weekdays []string `json:"weekdays,omitempty"`
+	// Start and end of the window on those days as HH:MM. Whole days if both are empty
+	StartTime string `json:"startTime,omitempty"`
+	EndTime   string `json:"endTime,omitempty"`
+	// IANA time zone of the days and times, e.g. Europe/Berlin. UTC if empty
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ChangeFreeze is a one-off period without rollouts, e.g. a release or holiday freeze
+type ChangeFreeze struct {
+	// Start and end as RFC 3339 timestamps
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Reported in the RolloutsDeferred condition
+	Reason string `json:"reason,omitempty"`
+}
+
+// DeferredRollout is a change held back until the next upgrade window
+type DeferredRollout struct {
+	// Component or subscription, e.g. prometheus
+	Component string `json:"component"`
+	// The change that is held back, e.g. the install plan or the pod template
+	Change string `json:"change"`
+	// Time the change was first deferred
+	Since int64 `json:"since"`
+}
+
+// Kinds of the secret stores of the External Secrets Operator
+const (
+	SecretStoreKind        = "SecretStore"
+	ClusterSecretStoreKind = "ClusterSecretStore"
+)
+
+// ExternalSecret is a secret materialized by the External Secrets Operator, e.g. from Vault. The
+// secret is created in the namespace of the CR and referenced by its name like any other secret
+type ExternalSecret struct {
+	// Name of the secret
+	Name string `json:"name"`
+	// Store the data is read from
+	StoreRef ExternalSecretStoreRef `json:"storeRef"`
+	// How often the data is read again, 1h if empty. Changes of the secret trigger a new sync
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+	// Keys of the secret
+	Data []ExternalSecretData `json:"data"`
+}
+
+// ExternalSecretStoreRef is a SecretStore in the namespace of the CR or a ClusterSecretStore
+type ExternalSecretStoreRef struct {
+	Name string `json:"name"`
+	// SecretStore or ClusterSecretStore, SecretStore if empty
+	Kind string `json:"kind,omitempty"`
+}
+
+// ExternalSecretData maps a key of the secret to a secret of the store
+type ExternalSecretData struct {
+	// Key in the secret, e.g. token
+	SecretKey string `json:"secretKey"`
+	// Path of the secret in the store, e.g. observability/pagerduty for a Vault KV engine
+	RemoteKey string `json:"remoteKey"`
+	// Property of the secret in the store, the whole secret if empty
+	Property string `json:"property,omitempty"`
+}
+
+// Checks of the stack verification
+const (
+	VerificationCheckRemoteWrite = "RemoteWrite"
+	VerificationCheckAlerting    = "Alerting"
+	VerificationCheckGrafana     = "GrafanaDatasources"
+)
+
+// Results of the checks of the stack verification. Checks of components that are not managed or
+// configured are skipped
+const (
+	VerificationPassed  = "Passed"
+	VerificationFailed  = "Failed"
+	VerificationSkipped = "Skipped"
+)
+
+// ConfigMerge selects the merge strategy per kind of resource. Dashboards conflict on their name,
+// uid or folder and title, rules on their name and Alertmanager routes on the id of the repository
+// index. All kinds default to FirstWins
+type ConfigMerge struct {
+	Dashboards         MergeStrategy `json:"dashboards,omitempty"`
+	Rules              MergeStrategy `json:"rules,omitempty"`
+	AlertmanagerRoutes MergeStrategy `json:"alertmanagerRoutes,omitempty"`
+}
+
+type RulePolicyAction string
+
+const (
+	// Non-conforming rules are dropped from their PrometheusRule
+	RulePolicyReject RulePolicyAction = "Reject"
+	// Missing labels and annotations are filled with the defaults and the remaining violations are
+	// added to the rules as an annotation
+	RulePolicyAnnotate RulePolicyAction = "Annotate"
+)
+
+// Annotation listing the violations of a rule applied by the Annotate action
+const RulePolicyViolationsAnnotation = "policy_violations"
+
+// RulePolicy enforces conventions on the alerting rules synced from the configuration sources.
+// Recording rules are not checked
+type RulePolicy struct {
+	// Labels every alerting rule must have, e.g. severity and team
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+	// Values of the severity label. Severities are normalized to the spelling listed here, compared
+	// case insensitively and after resolving the aliases
+	AllowedSeverities []string `json:"allowedSeverities,omitempty"`
+	// Aliases of severities, e.g. warn: warning
+	SeverityAliases map[string]string `json:"severityAliases,omitempty"`
+	// Alerting rules must have a runbook_url annotation
+	RequireRunbookUrl bool `json:"requireRunbookUrl,omitempty"`
+	// Reject or Annotate non-conforming rules. Defaults to Reject
+	Action RulePolicyAction `json:"action,omitempty"`
+	// Labels set on rules missing them by the Annotate action
+	DefaultLabels map[string]string `json:"defaultLabels,omitempty"`
+	// Runbook set on rules missing one by the Annotate action
+	DefaultRunbookUrl string `json:"defaultRunbookUrl,omitempty"`
+}
+
+type RuleDeduplicationStrategy string
+
+const (
+	// Duplicates are only reported in the status
+	RuleDeduplicationReport RuleDeduplicationStrategy = "Report"
+	// Duplicates are dropped from their PrometheusRule
+	RuleDeduplicationSuppress RuleDeduplicationStrategy = "Suppress"
+	// Duplicates get the duplicate_of label and Alertmanager routes them to a receiver without
+	// notifications, the alerts stay visible in Prometheus and Alertmanager
+	RuleDeduplicationRelabel RuleDeduplicationStrategy = "Relabel"
+)
+
+// Label set on duplicates by the Relabel strategy, the value is the platform rule
+const DuplicateOfLabel = "duplicate_of"
+
+// RuleDeduplication detects alerting rules of the configuration sources that the platform monitoring
+// of OpenShift already evaluates, so that the same condition doesn't page twice
+type RuleDeduplication struct {
+	// Report, Suppress or Relabel duplicates. Defaults to Report
+	Strategy RuleDeduplicationStrategy `json:"strategy,omitempty"`
+	// Rules with the name of a platform alert are duplicates regardless of their expression. By
+	// default the expressions must match as well, ignoring whitespace
+	MatchAlertNameOnly bool `json:"matchAlertNameOnly,omitempty"`
+}
+
+// ConfigApply limits the load the dashboards, rules and pod monitors of the configuration sources put
+// on the API server. Large sets are applied over several reconciles
+type ConfigApply struct {
+	// Resources applied per batch. Defaults to 50
+	BatchSize int `json:"batchSize,omitempty"`
+	// Resources of a batch applied in parallel. Defaults to 4
+	Concurrency int `json:"concurrency,omitempty"`
+	// Requests per second to the API server. Defaults to 20
+	RequestsPerSecond int `json:"requestsPerSecond,omitempty"`
+	// Time spent applying batches per reconcile, e.g. 1m. The next reconcile continues with the
+	// remaining batches. Defaults to 1m
+	MaxDuration string `json:"maxDuration,omitempty"`
+}
+
+// ConfigResourceKind is a kind of resource synced from the configuration sources
+type ConfigResourceKind string
+
+const (
+	ConfigKindDashboard      ConfigResourceKind = "Dashboard"
+	ConfigKindPrometheusRule ConfigResourceKind = "PrometheusRule"
+	ConfigKindPodMonitor     ConfigResourceKind = "PodMonitor"
+	ConfigKindLokiRule       ConfigResourceKind = "LokiRule"
+	ConfigKindAlertTemplate  ConfigResourceKind = "AlertTemplate"
+)
+
+// ConfigurationSource restricts the resources synced from a configuration source, so that clusters
+// sharing a repository each take a subset of it. Resources that stop matching are deleted
+type ConfigurationSource struct {
+	// Name of the configuration secret
+	Name string `json:"name"`
+	// Kinds of resources synced from the source, all kinds if empty. Rule tests are synced with the rules
+	Kinds []ConfigResourceKind `json:"kinds,omitempty"`
+	// Only rules and pod monitors with matching labels are synced
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Only rules with matching labels in their groups are synced, e.g. team=payments. Groups and rule
+	// files left without rules are not synced
+	RuleSelector *metav1.LabelSelector `json:"ruleSelector,omitempty"`
+}
+
+// SyncsKind returns whether resources of the kind are synced from the source
+func (in *ConfigurationSource) SyncsKind(kind ConfigResourceKind) bool {
+	if len(in.Kinds) == 0 {
+		return true
+	}
+	for _, k := range in.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// LogMetric is a metric Promtail derives from the container logs, exposed as promtail_custom_<name>
+type LogMetric struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// counter or histogram
+	Type string `json:"type"`
+	// Regular expression matched against the log line. Counters count the matching lines. Named
+	// groups can be used as labels, histograms observe the group named value
+	Regex string `json:"regex,omitempty"`
+	// Field of JSON log lines. Counters count the lines having the field, histograms observe its value
+	JSONField string `json:"jsonField,omitempty"`
+	// Named groups of the regex or fields of the JSON log line added as labels to the metric
+	Labels []string `json:"labels,omitempty"`
+	// Upper bounds of the histogram buckets, e.g. ["0.1", "1", "10"]
+	Buckets []string `json:"buckets,omitempty"`
+}
+
+type Logs struct {
+	Metrics []LogMetric `json:"metrics,omitempty"`
+	// Loki push endpoints Promtail ships the logs to in addition to Observatorium, e.g. a local Loki
+	// during a migration or a test environment
+	Clients []PromtailClient `json:"clients,omitempty"`
+	// Loki tenants of the logs of the tailed namespaces, the first tenant matching a namespace
+	// applies. Logs of other namespaces are shipped to the tenant of the clients
+	Tenants []LogTenant `json:"tenants,omitempty"`
+	// Namespace label whose value is the tenant of the namespaces no tenant matches, e.g. team
+	TenantNamespaceLabel string `json:"tenantNamespaceLabel,omitempty"`
+	// Only tail the namespaces that have a tenant
+	OnlyTenantNamespaces bool `json:"onlyTenantNamespaces,omitempty"`
+	// Loki recording and alerting rules, in addition to those of the configuration repositories
+	Rules *LogRules `json:"rules,omitempty"`
+	// Keeps the read positions and the logs Promtail could not ship yet on disk, so that they survive
+	// restarts of the Promtail pods
+	Buffering *LogBuffering `json:"buffering,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=HostPath;PersistentVolumeClaim
+type LogBufferingStorage string
+
+const (
+	LogBufferingHostPath              LogBufferingStorage = "HostPath"
+	LogBufferingPersistentVolumeClaim LogBufferingStorage = "PersistentVolumeClaim"
+)
+
+// LogBuffering configures where Promtail stores its positions files and its write ahead log. Every
+// Promtail pod writes to its own directory, named after the configuration repository and the node
+type LogBuffering struct {
+	// HostPath or PersistentVolumeClaim. Defaults to HostPath
+	Storage LogBufferingStorage `json:"storage,omitempty"`
+	// Directory on the nodes for the HostPath storage. Defaults to /var/lib/observability-promtail
+	HostPath string `json:"hostPath,omitempty"`
+	// Size of the claim for the PersistentVolumeClaim storage, e.g. 5Gi. Defaults to 1Gi. The claim is
+	// shared by all nodes, so the storage class must support ReadWriteMany
+	Size         string  `json:"size,omitempty"`
+	StorageClass *string `json:"storageClass,omitempty"`
+	// How long logs are buffered and retried while the Loki endpoints are unreachable, e.g. 4h.
+	// Older logs are dropped. Defaults to 1h
+	Retention string `json:"retention,omitempty"`
+}
+
+// LogRules configures the Loki rules the operator pushes to the ruler
+type LogRules struct {
+	// ConfigMaps in the namespace of the CR with matching labels, every key is a rule file in the
+	// format of the Loki ruler
+	ConfigMapSelector *metav1.LabelSelector `json:"configMapSelector,omitempty"`
+	// Ruler the rules are pushed to. Defaults to the Loki API of spec.observatorium.tenant
+	Ruler *LokiRuler `json:"ruler,omitempty"`
+}
+
+// LokiRuler is the rules API of a Loki ruler
+type LokiRuler struct {
+	// Url of the Loki API, e.g. http://loki.loki.svc:3100
+	URL string `json:"url"`
+	// Sent in the X-Scope-OrgID header to multi-tenant Lokis
+	Tenant string `json:"tenant,omitempty"`
+	// Secret with a bearer token in the token key
+	TokenSecret string `json:"tokenSecret,omitempty"`
+	// Skip the verification of the server certificate
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// LogTenant assigns the logs of namespaces to a tenant. Promtail sends the tenant in the
+// X-Scope-OrgID header of the pushes
+type LogTenant struct {
+	Tenant string `json:"tenant"`
+	// Names of the namespaces
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Namespaces matching the selector
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// PromtailClient is an additional Loki push endpoint of Promtail
+type PromtailClient struct {
+	// Unique name, used in the names of the volumes of the secrets
+	Name string `json:"name"`
+	// Push url, e.g. http://loki.loki.svc:3100/loki/api/v1/push
+	URL string `json:"url"`
+	// Sent in the X-Scope-OrgID header to multi-tenant Lokis
+	Tenant string `json:"tenant,omitempty"`
+	// Secret with a bearer token in the token key
+	TokenSecret string `json:"tokenSecret,omitempty"`
+	// Secret with the CA certificate of the server in the ca.crt key
+	CASecret string `json:"caSecret,omitempty"`
+	// Secret of type kubernetes.io/tls with a client certificate
+	CertSecret string `json:"certSecret,omitempty"`
+	// Skip the verification of the server certificate
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// GrafanaSmtp configures the mail server Grafana sends email notifications through
+type GrafanaSmtp struct {
+	// Mail server as host:port
+	Host string `json:"host"`
+	// Secret with the user and password keys. Optional if the server does not require authentication
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+	FromAddress       string `json:"fromAddress"`
+	FromName          string `json:"fromName,omitempty"`
+	// Skip the verification of the server certificate
+	SkipVerify bool `json:"skipVerify,omitempty"`
+}
+
+// GrafanaContactPoint is a notification channel of Grafana alerts
+type GrafanaContactPoint struct {
+	// Unique name, also used in the uid of the notification channel
+	Name string `json:"name"`
+	// email or slack
+	Type string `json:"type"`
+	// Email addresses to notify, for the email type
+	Addresses []string `json:"addresses,omitempty"`
+	// Secret with the webhook url in the url key, for the slack type
+	SlackWebhookSecret string `json:"slackWebhookSecret,omitempty"`
+	// Slack channel to post to, overrides the channel of the webhook
+	SlackChannel string `json:"slackChannel,omitempty"`
+	// Notify about all alerts, also those that don't reference the contact point
+	Default bool `json:"default,omitempty"`
+	// Resend notifications of alerts that keep firing at this interval, e.g. 4h
+	ReminderInterval string `json:"reminderInterval,omitempty"`
+}
+
+// GrafanaExternal is an existing Grafana instance the operator configures through its HTTP API
+// instead of installing Grafana
+type GrafanaExternal struct {
+	// Base url of Grafana, e.g. https://grafana.example.com
+	URL string `json:"url"`
+	// Secret with an API token with the Admin role in the token key
+	TokenSecret string `json:"tokenSecret"`
+	// Organization the datasource and dashboards are created in. Defaults to the organization of the token
+	OrgID int64 `json:"orgId,omitempty"`
+	// Url of Prometheus as reachable from Grafana. Defaults to the in-cluster Prometheus service
+	DatasourceURL string `json:"datasourceUrl,omitempty"`
+	// Name of the datasource, referenced by the dashboards. Defaults to Prometheus
+	DatasourceName string `json:"datasourceName,omitempty"`
+}
+
+type Grafana struct {
+	Smtp          *GrafanaSmtp          `json:"smtp,omitempty"`
+	ContactPoints []GrafanaContactPoint `json:"contactPoints,omitempty"`
+	External      *GrafanaExternal      `json:"external,omitempty"`
+	// Organizations created in Grafana. Organizations removed from the CR are not deleted
+	Organizations []GrafanaOrganization `json:"organizations,omitempty"`
+	Teams         []GrafanaTeam         `json:"teams,omitempty"`
+	APIKeys       []GrafanaAPIKey       `json:"apiKeys,omitempty"`
+	ImageRenderer *GrafanaImageRenderer `json:"imageRenderer,omitempty"`
+	Auth          *GrafanaAuth          `json:"auth,omitempty"`
+	// Keep the Grafana database across restarts, by default it is lost with the pod
+	Persistence *GrafanaPersistence `json:"persistence,omitempty"`
+	// Operational events written to Grafana as annotations
+	Annotations *GrafanaAnnotations `json:"annotations,omitempty"`
+	// Health checks of the datasources provisioned by the operator, on by default
+	DatasourceHealth *GrafanaDatasourceHealth `json:"datasourceHealth,omitempty"`
+	// Sharing of dashboards outside of Grafana, everything is allowed by default
+	Sharing *GrafanaSharing `json:"sharing,omitempty"`
+}
+
+// GrafanaSharing controls the ways dashboards of the managed Grafana can be shared
+type GrafanaSharing struct {
+	// Disable snapshots of dashboards, local and external
+	DisableSnapshots bool `json:"disableSnapshots,omitempty"`
+	// Disable public dashboards, which can be viewed without logging in
+	DisablePublicDashboards bool `json:"disablePublicDashboards,omitempty"`
+	// Disable publishing snapshots to an external server and embedding panels in other sites
+	DisableExternalSharing bool `json:"disableExternalSharing,omitempty"`
+	// Internal server external snapshots are published to instead of snapshots.raintank.io
+	SnapshotServer *GrafanaSnapshotServer `json:"snapshotServer,omitempty"`
+}
+
+type GrafanaSnapshotServer struct {
+	// Url of the server, e.g. https://snapshots.example.com
+	Url string `json:"url"`
+	// Name of the server shown in the share dialog
+	Name string `json:"name,omitempty"`
+}
+
+// GrafanaDatasourceHealth queries the datasources provisioned by the operator through the Grafana
+// API. Broken datasources of the managed Grafana are provisioned again
+type GrafanaDatasourceHealth struct {
+	// Time between the checks, defaults to 5m
+	Interval string `json:"interval,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// GrafanaAnnotationSource is a kind of operational event that can be annotated in Grafana
+type GrafanaAnnotationSource string
+
+const (
+	// A new configuration revision or a rollback was applied
+	GrafanaAnnotationConfig GrafanaAnnotationSource = "Config"
+	// The operator rolled out a component
+	GrafanaAnnotationRollout GrafanaAnnotationSource = "Rollout"
+	// OLM installed, upgraded or rolled back the CSV of an operator
+	GrafanaAnnotationUpgrade GrafanaAnnotationSource = "Upgrade"
+)
+
+// GrafanaAnnotations are organization wide annotations tagged observability-operator and the
+// reason of the event, shown by annotation queries of the Grafana datasource filtering by tag
+type GrafanaAnnotations struct {
+	// Events that are annotated, all if empty
+	Sources []GrafanaAnnotationSource `json:"sources,omitempty"`
+}
+
+// GrafanaAnnotation is an event waiting to be written to Grafana
+type GrafanaAnnotation struct {
+	// Time of the event in milliseconds
+	Time   int64  `json:"time"`
+	Reason string `json:"reason"`
+	Text   string `json:"text"`
+}
+
+// Supported types of external Grafana databases
+const (
+	GrafanaDatabasePostgres = "postgres"
+	GrafanaDatabaseMySQL    = "mysql"
+)
+
+// GrafanaPersistence keeps the annotations, snapshots, users and API keys stored in the Grafana
+// database, either in SQLite on a volume or in an external database. Only one of both can be set
+type GrafanaPersistence struct {
+	Volume   *GrafanaVolume   `json:"volume,omitempty"`
+	Database *GrafanaDatabase `json:"database,omitempty"`
+}
+
+// GrafanaVolume is the persistent volume of the SQLite database of Grafana
+type GrafanaVolume struct {
+	// Size of the volume, e.g. 1Gi
+	Size         string  `json:"size"`
+	StorageClass *string `json:"storageClass,omitempty"`
+}
+
+// GrafanaDatabase is an external PostgreSQL or MySQL database
+type GrafanaDatabase struct {
+	// postgres or mysql
+	Type string `json:"type"`
+	// Database server as host:port
+	Host string `json:"host"`
+	// Name of the database
+	Name string `json:"name"`
+	// Secret with the user and password keys
+	CredentialsSecret string `json:"credentialsSecret"`
+	// SSL mode of postgres: disable, require or verify-full. Defaults to disable
+	SSLMode string `json:"sslMode,omitempty"`
+}
+
+// GrafanaAuth configures how users log into the managed Grafana
+type GrafanaAuth struct {
+	// Log in with the users of an LDAP server or Active Directory, e.g. on clusters without the
+	// OpenShift OAuth server. Anonymous access is disabled
+	LDAP *GrafanaLDAP `json:"ldap,omitempty"`
+}
+
+type GrafanaLDAP struct {
+	// LDAP server as host:port
+	Server string `json:"server"`
+	// Connect with LDAPS
+	UseSSL bool `json:"useSSL,omitempty"`
+	// Upgrade the connection with STARTTLS
+	StartTLS bool `json:"startTLS,omitempty"`
+	// Skip the verification of the server certificate
+	SkipVerify bool `json:"skipVerify,omitempty"`
+	// Secret with the bindDN and password keys of the user that searches the directory
+	BindSecret string `json:"bindSecret"`
+	// Filter of the user search, %s is replaced with the login. Defaults to (sAMAccountName=%s)
+	SearchFilter string `json:"searchFilter,omitempty"`
+	// Base DNs of the user search, e.g. dc=example,dc=com
+	SearchBaseDNs []string `json:"searchBaseDNs"`
+	// Roles of the members of LDAP groups in the main organization. If set, users without a matching
+	// group can't log in, otherwise all users are viewers
+	GroupMappings []GrafanaLDAPGroupMapping `json:"groupMappings,omitempty"`
+}
+
+type GrafanaLDAPGroupMapping struct {
+	// DN of the group, or * for all users
+	GroupDN string `json:"groupDN"`
+	// Viewer, Editor or Admin
+	Role string `json:"role"`
+	// Make the members Grafana server admins
+	GrafanaAdmin bool `json:"grafanaAdmin,omitempty"`
+}
+
+// GrafanaImageRenderer runs the grafana-image-renderer next to Grafana, so alert notifications and
+// exports can include panel images
+type GrafanaImageRenderer struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+type GrafanaOrganization struct {
+	Name string `json:"name"`
+}
+
+// GrafanaTeam is a team created in Grafana. Teams removed from the CR are not deleted
+type GrafanaTeam struct {
+	Name string `json:"name"`
+	// One of the organizations of the CR, the main organization if empty
+	Organization string `json:"organization,omitempty"`
+	// Logins or emails of users of the organization. Members not listed are removed from the team
+	Members []string `json:"members,omitempty"`
+}
+
+// GrafanaAPIKey is an API key created in Grafana and written to a secret in the namespace of the CR,
+// e.g. for automation that uploads dashboards. Keys removed from the CR are deleted with their secret
+type GrafanaAPIKey struct {
+	// Unique per organization, prefixed with observability- in Grafana
+	Name string `json:"name"`
+	// One of the organizations of the CR, the main organization if empty
+	Organization string `json:"organization,omitempty"`
+	// Viewer, Editor or Admin
+	Role string `json:"role"`
+	// Secret the key is written to, in the token key
+	Secret string `json:"secret"`
+	// Lifetime of the key, e.g. 720h. Expired keys are replaced. Keys don't expire if empty
+	TTL string `json:"ttl,omitempty"`
+}
+
+// GrafanaPlugin is a plugin installed into Grafana, pinned to a version
+type GrafanaPlugin struct {
+	// Plugin id on grafana.com, e.g. grafana-piechart-panel
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// sha256 of the plugin archive. Plugins with a different checksum are not installed
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// QueryProxy is a query frontend of Prometheus that only returns the metrics of one tenant. The
+// tenant is passed as a query parameter named after the tenant label, and callers need permission
+// to get pods.metrics.k8s.io in the namespace of the same name
+type QueryProxy struct {
+	// Label that identifies the tenant of a series, namespace if empty
+	TenantLabel string `json:"tenantLabel,omitempty"`
+}
+
+// Backup ships the Prometheus TSDB blocks to object storage, where a Thanos Store gateway keeps
+// them queryable, and periodically exports the Grafana dashboards to the same bucket
+type Backup struct {
+	// Secret in the namespace of the CR with the Thanos object storage config in the objstore.yml
+	// key. Grafana backups require a bucket of type S3.
+	ObjectStorageSecret string `json:"objectStorageSecret"`
+	// Time between Grafana backups, 24h if empty
+	GrafanaInterval string `json:"grafanaInterval,omitempty"`
+	// Short lived credentials of a cloud identity for the Thanos sidecars and the Thanos Store
+	// gateway instead of the static keys of the object storage config
+	Identity *ObjectStorageIdentity `json:"identity,omitempty"`
+}
+
+type ObjectStorageIdentityProvider string
+
+const (
+	// IAM roles for service accounts
+	ObjectStorageIdentityAWS ObjectStorageIdentityProvider = "aws"
+	// Workload Identity Federation
+	ObjectStorageIdentityGCP ObjectStorageIdentityProvider = "gcp"
+	// Azure AD Workload Identity
+	ObjectStorageIdentityAzure ObjectStorageIdentityProvider = "azure"
+)
+
+// ObjectStorageIdentity federates the service account of Prometheus with a cloud identity. A
+// projected service account token is mounted into the pods and exchanged for short lived
+// credentials by the object storage clients, the object storage config must not contain keys
+type ObjectStorageIdentity struct {
+	// aws, gcp or azure
+	Provider ObjectStorageIdentityProvider `json:"provider"`
+	// ARN of the IAM role to assume. Required for aws
+	RoleARN string `json:"roleArn,omitempty"`
+	// Email of the Google service account to impersonate. Required for gcp
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// Workload identity pool provider, e.g.
+	// //iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/pool/providers/provider.
+	// Required for gcp
+	WorkloadIdentityProvider string `json:"workloadIdentityProvider,omitempty"`
+	// Client id of the managed identity or application and its tenant. Required for azure
+	ClientID string `json:"clientId,omitempty"`
+	TenantID string `json:"tenantId,omitempty"`
+	// Lifetime of the projected token in seconds, 3600 if empty
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+}
+
+// TLS secures the traffic of the stack
+// Drift configures how out of band changes of the resources managed by the operator are handled.
+// They are always reported in status.drift and as events
+type Drift struct {
+	// Kinds of resources, e.g. ConfigMap or Deployment, of which out of band changes are reported
+	// but not reverted
+	ReportOnly []string `json:"reportOnly,omitempty"`
+}
+
+// DriftedResource is a resource managed by the operator that was changed by someone else
+type DriftedResource struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Changed fields, e.g. spec.replicas
+	Fields []string `json:"fields"`
+	// Field managers that changed the fields, usually the client, e.g. kubectl-edit
+	Managers []string `json:"managers,omitempty"`
+	// False in report-only mode
+	Reverted bool  `json:"reverted"`
+	Time     int64 `json:"time"`
+}
+
+// Report renders a Grafana dashboard with the image renderer on a schedule and sends it by email or
+// uploads it to object storage
+type Report struct {
+	// Unique name, used in the email subject and the object key
+	Name string `json:"name"`
+	// Uid of the dashboard
+	Dashboard string `json:"dashboard"`
+	// Cron expression in UTC, e.g. 0 8 * * 1 for Mondays at 8:00
+	Schedule string `json:"schedule"`
+	// pdf or png, pdf if empty
+	Format string `json:"format,omitempty"`
+	// Time range of the dashboard up to now, e.g. 7d. Defaults to the time range saved with the dashboard
+	TimeRange string `json:"timeRange,omitempty"`
+	// Email addresses the report is sent to through the SMTP server of spec.grafana.smtp
+	Recipients []string `json:"recipients,omitempty"`
+	// Secret with the Thanos object storage config in the objstore.yml key the report is uploaded
+	// to, of type S3
+	ObjectStorageSecret string `json:"objectStorageSecret,omitempty"`
+}
+
+// PrometheusAdapter serves the custom metrics API from the managed Prometheus, so horizontal pod
+// autoscalers can scale on the metrics collected by the stack. The API is cluster wide, only one
+// stack per cluster can enable the adapter
+type PrometheusAdapter struct {
+	// Config map in the namespace of the CR with the prometheus-adapter rules in the config.yaml key
+	RulesConfigMap string `json:"rulesConfigMap"`
+}
+
+// Console adds the stack to the OpenShift console when the console API is available
+type Console struct {
+	// Links to the routes of Grafana, Prometheus and Alertmanager in the application menu. Enabled if
+	// not set
+	Links *bool `json:"links,omitempty"`
+	// Dashboard of the pods of the stack in the monitoring dashboards of the console, queried from the
+	// cluster monitoring. Enabled if not set
+	Dashboard *bool `json:"dashboard,omitempty"`
+	// Dynamic console plugin that shows the status of the stack. Cluster admins enable it in the
+	// console operator config
+	Plugin *ConsolePlugin `json:"plugin,omitempty"`
+}
+
+// ConsolePlugin is served by a deployment of the given image, which serves the plugin assets over
+// TLS on port 9443. The certificate is issued by the service CA
+type ConsolePlugin struct {
+	Image string `json:"image"`
+}
+
+// SelfMonitoring is the monitoring of the components of the stack by its own Prometheus
+type SelfMonitoring struct {
+	// Alerts on the health of Prometheus, Alertmanager, Grafana, Promtail and the token refresher.
+	// Enabled if not set
+	Alerts *bool `json:"alerts,omitempty"`
+}
+
+// TenantQuota limits the series and samples Prometheus ingests from the pod monitors, service
+// monitors and probes of a namespace. Usage is recorded per namespace and alerted on when a quota
+// is exceeded. Retention can't be set per tenant, Prometheus applies it to the whole TSDB
+type TenantQuota struct {
+	Namespace string `json:"namespace"`
+	// Active series of the targets in the namespace
+	MaxSeries int64 `json:"maxSeries,omitempty"`
+	// Samples per second ingested from the targets in the namespace
+	MaxSamplesPerSecond int64 `json:"maxSamplesPerSecond,omitempty"`
+	// Stop scraping the namespace for an hour when it exceeds a quota, instead of only alerting.
+	// Requires namespace selectors for the monitors, the namespace is excluded by its
+	// kubernetes.io/metadata.name label
+	Enforce bool `json:"enforce,omitempty"`
+}
+
+// TenantQuotaStatus is the usage of a tenant quota as recorded by Prometheus
+type TenantQuotaStatus struct {
+	Namespace        string `json:"namespace"`
+	Series           int64  `json:"series"`
+	SamplesPerSecond int64  `json:"samplesPerSecond"`
+	Exceeded         bool   `json:"exceeded"`
+	// Time the scraping of the namespace was stopped, 0 if it is scraped
+	SuspendedSince int64 `json:"suspendedSince,omitempty"`
+}
+
+// ReportStatus is the last scheduled run of a report
+type ReportStatus struct {
+	Name string `json:"name"`
+	// Time of the last run, or when the report was added
+	Time int64 `json:"time"`
+	// Error of the last delivery, retried until it succeeds
+	Error string `json:"error,omitempty"`
+}
+
+type Networking struct {
+	// IPv4, IPv6 or both, the first one being the primary family. Services are single-stack with
+	// one family and require dual-stack with two. Defaults to the cluster default
+	IPFamilies []v1.IPFamily `json:"ipFamilies,omitempty"`
+}
+
+type TLS struct {
+	// Issue certificates with cert-manager and require mutual TLS for the connections of Grafana
+	// and Prometheus to Prometheus and Alertmanager. Promtail presents a client certificate to the
+	// log endpoints. Requires cert-manager
+	Internal bool `json:"internal,omitempty"`
+}
+
+// TokenRefresher configures the token refreshers that authenticate the remote writes and log pushes
+// of Observatoria using sso.redhat.com
+type TokenRefresher struct {
+	// Pods of every token refresher, 1 if empty. Ignored with autoscaling
+	Replicas    *int32                     `json:"replicas,omitempty"`
+	Autoscaling *TokenRefresherAutoscaling `json:"autoscaling,omitempty"`
+	// Timeout of the remote writes and log pushes through the token refreshers, e.g. 30s
+	RequestTimeout string `json:"requestTimeout,omitempty"`
+	// Retry delays of Prometheus and Promtail while the token refreshers fail, e.g. because
+	// sso.redhat.com is unavailable. The delay doubles with every failed request up to the maximum,
+	// so the clients back off instead of hammering the token refreshers
+	Backoff *TokenRefresherBackoff `json:"backoff,omitempty"`
+}
+
+// TokenRefresherAutoscaling scales the token refreshers with a HorizontalPodAutoscaler on their CPU
+// utilization
+type TokenRefresherAutoscaling struct {
+	// 1 if empty
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	MaxReplicas int32  `json:"maxReplicas"`
+	// Average CPU utilization in percent of the requests, 80 if empty. Requires CPU requests in the
+	// token-refresher resources
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+}
+
+type TokenRefresherBackoff struct {
+	// Delay after the first failure, e.g. 1s
+	MinBackoff string `json:"minBackoff,omitempty"`
+	// Upper bound of the delay, e.g. 5m
+	MaxBackoff string `json:"maxBackoff,omitempty"`
+}
+
+// FleetTelemetry periodically sends a health snapshot of the stack to a central endpoint
+type FleetTelemetry struct {
+	// URL the snapshots are POSTed to
+	Endpoint string `json:"endpoint"`
+	// Secret in the namespace of the CR with the bearer token in the token key
+	TokenSecret string `json:"tokenSecret,omitempty"`
+	// Time between reports, 15m if empty
+	Interval string `json:"interval,omitempty"`
+}
+
+type ObservatoriumTenant struct {
+	// URL of the Observatorium API gateway
+	Gateway string `json:"gateway"`
+	// Name of the tenant
+	Tenant string `json:"tenant"`
+	// URL of the OIDC issuer of the tenant
+	OIDCIssuerUrl string `json:"oidcIssuerUrl"`
+	// Secret with the clientId and clientSecret of a client with admin scope for the tenant
+	CredentialsSecret string `json:"credentialsSecret"`
+	// Register the tenant in the tenants configuration of an Observatorium API in the same cluster
+	Registration *ObservatoriumTenantRegistration `json:"registration,omitempty"`
+}
+
+// ObservatoriumTenantRegistration is the tenants configuration of the Observatorium API the tenant is
+// added to, with the OIDC client of the credentials secret
+type ObservatoriumTenantRegistration struct {
+	// Namespace of the Observatorium API
+	Namespace string `json:"namespace"`
+	// Secret with the tenants configuration of the Observatorium API
+	Secret string `json:"secret"`
+	// Key of the tenants configuration in the secret, tenants.yaml by default
+	Key string `json:"key,omitempty"`
+	// Id of the tenant, generated when the tenant is registered if empty
+	Id string `json:"id,omitempty"`
+	// Rate limits of the tenant, per endpoint of the Observatorium API
+	RateLimits []ObservatoriumRateLimit `json:"rateLimits,omitempty"`
+}
+
+type ObservatoriumRateLimit struct {
+	// Regular expression of the paths the limit applies to
+	Endpoint string `json:"endpoint"`
+	// Requests allowed per window
+	Limit int `json:"limit"`
+	// Duration of the window, e.g. 1s
+	Window string `json:"window"`
+}
+
+type Observatorium struct {
+	Tenant *ObservatoriumTenant `json:"tenant,omitempty"`
 }
 
 // ObservabilitySpec defines the desired state of Observability
@@ -101,6 +1461,338 @@ type ObservabilitySpec struct {
 	AlertManagerDefaultName string                `json:"alertManagerDefaultName,omitempty"`
 	PrometheusDefaultName   string                `json:"prometheusDefaultName,omitempty"`
 	GrafanaDefaultName      string                `json:"grafanaDefaultName,omitempty"`
+	OLM                     *OLM                  `json:"olm,omitempty"`
+	// Images to use instead of the defaults, keyed by component, e.g. for mirrored registries.
+	// Images can be referenced by tag or by digest.
+	ImageOverrides map[string]string `json:"imageOverrides,omitempty"`
+	// Resource requirements keyed by component. Take precedence over the resource requirements
+	// in selfContained
+	Resources     map[string]v1.ResourceRequirements `json:"resources,omitempty"`
+	Observatorium *Observatorium                     `json:"observatorium,omitempty"`
+	ConfigRollout *ConfigRollout                     `json:"configRollout,omitempty"`
+	// Grafana plugins to install. Take precedence over plugins of the same name in the
+	// configuration repositories
+	GrafanaPlugins []GrafanaPlugin `json:"grafanaPlugins,omitempty"`
+	Alerting       *Alerting       `json:"alerting,omitempty"`
+	Tracing        *Tracing        `json:"tracing,omitempty"`
+	QueryProxy     *QueryProxy     `json:"queryProxy,omitempty"`
+	FleetTelemetry *FleetTelemetry `json:"fleetTelemetry,omitempty"`
+	TokenRefresher *TokenRefresher `json:"tokenRefresher,omitempty"`
+	// Grafana notifications
+	Grafana *Grafana `json:"grafana,omitempty"`
+	// Metrics derived from the logs collected by Promtail
+	Logs *Logs `json:"logs,omitempty"`
+	// Install, externally manage or disable the individual components
+	Components *Components `json:"components,omitempty"`
+	// Configure the user workload monitoring of OpenShift instead of installing Prometheus
+	UserWorkloadMonitoring *UserWorkloadMonitoring `json:"userWorkloadMonitoring,omitempty"`
+	// Values substituted for ${NAME} placeholders in the resources fetched from the configuration
+	// repositories
+	ConfigValues map[string]string `json:"configValues,omitempty"`
+	// Backup of the Grafana dashboards and the Prometheus TSDB to object storage
+	Backup *Backup `json:"backup,omitempty"`
+	TLS    *TLS    `json:"tls,omitempty"`
+	// Run FIPS validated images and restrict TLS to FIPS approved ciphers, for clusters installed
+	// in FIPS mode. Features without a FIPS compliant implementation are rejected
+	FIPSMode bool `json:"fipsMode,omitempty"`
+	// IP families of the services and listen addresses, for IPv6-only and dual-stack clusters
+	Networking *Networking `json:"networking,omitempty"`
+	Drift      *Drift      `json:"drift,omitempty"`
+	// Dashboards rendered and delivered on a schedule
+	Reports           []Report           `json:"reports,omitempty"`
+	PrometheusAdapter *PrometheusAdapter `json:"prometheusAdapter,omitempty"`
+	SelfMonitoring    *SelfMonitoring    `json:"selfMonitoring,omitempty"`
+	// Limits on the metrics ingested from the targets of a namespace
+	TenantQuotas []TenantQuota `json:"tenantQuotas,omitempty"`
+	// How resources defined by several configuration sources are merged
+	ConfigMerge *ConfigMerge `json:"configMerge,omitempty"`
+	// Resources synced from a configuration source, by the name of its configuration secret. Sources
+	// without an entry are synced completely
+	ConfigurationSources []ConfigurationSource `json:"configurationSources,omitempty"`
+	// How fast the resources of the configuration sources are applied
+	ConfigApply *ConfigApply `json:"configApply,omitempty"`
+	// Conventions enforced on the rules of the configuration sources
+	RulePolicy *RulePolicy `json:"rulePolicy,omitempty"`
+	// Handling of rules that duplicate rules of the platform monitoring
+	RuleDeduplication *RuleDeduplication `json:"ruleDeduplication,omitempty"`
+	// When expiring certificates and credentials are reported
+	ExpiryMonitoring *ExpiryMonitoring `json:"expiryMonitoring,omitempty"`
+	// Verify the stack end to end after it was reconciled
+	StackVerification *StackVerificationSpec `json:"stackVerification,omitempty"`
+	// Secrets materialized by the External Secrets Operator before the stack is reconciled
+	ExternalSecrets []ExternalSecret `json:"externalSecrets,omitempty"`
+	// When upgrades and rollouts that restart pods are applied
+	UpgradeWindow *UpgradeWindow `json:"upgradeWindow,omitempty"`
+	// Integration of the stack into the OpenShift console
+	Console *Console `json:"console,omitempty"`
+	// Built-in pod monitors, alerts and dashboards for common workloads, one of kafka, postgres,
+	// redis or nginx-ingress
+	Profiles []string `json:"profiles,omitempty"`
+	// Groups granted access to the stack in the namespace of the CR
+	Access *Access `json:"access,omitempty"`
+}
+
+// Access binds groups to the observability-viewer and observability-editor cluster roles in the
+// namespace of the CR
+type Access struct {
+	// Can see the dashboards, rules and monitors, and use the Prometheus and Alertmanager UIs,
+	// including silencing alerts
+	ViewerGroups []string `json:"viewerGroups,omitempty"`
+	// Can in addition create, edit and delete rules, monitors and dashboards
+	EditorGroups []string `json:"editorGroups,omitempty"`
+}
+
+// SubscriptionStatus is the health of one of the OLM subscriptions managed by the operator
+type SubscriptionStatus struct {
+	Name  string            `json:"name"`
+	State SubscriptionState `json:"state,omitempty"`
+	// Last CSV of the subscription that was installed successfully
+	InstalledCSV string `json:"installedCSV,omitempty"`
+	// CSV that failed to install and was rolled back. Install plans for it are no longer approved
+	FailedCSV string `json:"failedCSV,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// GrafanaSharingStatus is the sharing policy rendered into the configuration of the managed Grafana
+type GrafanaSharingStatus struct {
+	Snapshots        bool   `json:"snapshots"`
+	PublicDashboards bool   `json:"publicDashboards"`
+	ExternalSharing  bool   `json:"externalSharing"`
+	SnapshotServer   string `json:"snapshotServer,omitempty"`
+}
+
+// PrunedResource is an OLM resource of a previous operator version that was deleted because
+// nothing refers to it anymore
+type PrunedResource struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Time   int64  `json:"time"`
+}
+
+// ReferencedSecret is a secret holding credentials that are rendered into the configuration
+// of the stack, e.g. PagerDuty keys or Observatorium client secrets
+type ReferencedSecret struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Hash of the secret data at the time of the last sync
+	Hash string `json:"hash,omitempty"`
+}
+
+// ClusterCapabilities are the APIs and platform features detected on the cluster. Reconcilers
+// consult them to choose between the OpenShift and the plain Kubernetes code paths
+type ClusterCapabilities struct {
+	// Operator Lifecycle Manager is installed
+	OLM bool `json:"olm"`
+	// OpenShift Route API is available
+	Routes bool `json:"routes"`
+	// Prometheus operator CRDs are installed
+	PrometheusOperator bool `json:"prometheusOperator"`
+	// Grafana operator CRDs are installed
+	GrafanaOperator bool `json:"grafanaOperator"`
+	// Tempo operator CRDs are installed
+	TempoOperator bool `json:"tempoOperator,omitempty"`
+	// cert-manager CRDs are installed
+	CertManager bool `json:"certManager,omitempty"`
+	// External Secrets Operator CRDs are installed
+	ExternalSecretsOperator bool `json:"externalSecretsOperator,omitempty"`
+	// OpenShift console API is available
+	Console bool `json:"console,omitempty"`
+	// Version of OpenShift, empty on other distributions
+	OpenShiftVersion string `json:"openshiftVersion,omitempty"`
+	// Lowest kubelet version of the nodes
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// Time of the last detection
+	LastDetected int64 `json:"lastDetected,omitempty"`
+}
+
+func (in *ClusterCapabilities) IsOpenShift() bool {
+	return in.OpenShiftVersion != ""
+}
+
+// ConfigRevision is the revision of a configuration repository the stack is running
+type ConfigRevision struct {
+	// Name of the configuration secret of the repository
+	Name string `json:"name"`
+	// Tag or branch, empty for the default branch
+	Revision string `json:"revision,omitempty"`
+	Ring     string `json:"ring,omitempty"`
+	// Hash of the content fetched from the repository
+	Hash string `json:"hash,omitempty"`
+}
+
+// ResourceRecommendation compares the p95 usage of a component with its configured requests
+type ResourceRecommendation struct {
+	// Component as used in spec.resources
+	Component string `json:"component"`
+	// Requests configured for the component
+	Requests v1.ResourceList `json:"requests,omitempty"`
+	// p95 of the usage of the busiest container of the component over the recommendation window
+	Usage v1.ResourceList `json:"usage,omitempty"`
+	// Suggested requests, the p95 usage plus headroom
+	Recommended v1.ResourceList `json:"recommended,omitempty"`
+}
+
+// InvalidDashboard is a dashboard of a configuration repository that failed validation and
+// was not applied
+type InvalidDashboard struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ComponentVersion is the running version of a component of the stack
+type ComponentVersion struct {
+	// Component, e.g. prometheus or grafana-operator
+	Name string `json:"name"`
+	// Versions of the running pods or of the installed CSV, more than one during a rollout
+	Versions []string `json:"versions,omitempty"`
+	// Version the operator expects, empty if another operator chooses it
+	Expected string `json:"expected,omitempty"`
+	// Why the running version is not the expected one
+	Skew string `json:"skew,omitempty"`
+	// Time the skew was first detected
+	SkewSince int64 `json:"skewSince,omitempty"`
+}
+
+// RulePolicyViolation is an alerting rule that does not conform to spec.rulePolicy
+type RulePolicyViolation struct {
+	// Name of the PrometheusRule
+	Rule  string `json:"rule"`
+	Group string `json:"group"`
+	Alert string `json:"alert"`
+	// Conventions the rule violates
+	Violations []string         `json:"violations"`
+	Action     RulePolicyAction `json:"action"`
+}
+
+// DuplicateRule is an alerting rule of the configuration sources that duplicates a rule of the
+// platform monitoring
+type DuplicateRule struct {
+	// Name of the PrometheusRule
+	Rule  string `json:"rule"`
+	Group string `json:"group"`
+	Alert string `json:"alert"`
+	// Namespace and name of the PrometheusRule of the platform monitoring
+	PlatformRule string                    `json:"platformRule"`
+	Strategy     RuleDeduplicationStrategy `json:"strategy"`
+}
+
+// InvalidLogRule is a Loki rule file that failed validation and was not pushed to the ruler
+type InvalidLogRule struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// InvalidAlertTemplate is a notification template file that failed to parse and was not added to
+// the Alertmanager config
+type InvalidAlertTemplate struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+type RuleTestResultType string
+
+const (
+	RuleTestPassed  RuleTestResultType = "Passed"
+	RuleTestFailed  RuleTestResultType = "Failed"
+	RuleTestPending RuleTestResultType = "Pending"
+)
+
+// RuleTestResult is the outcome of a rule unit test file of a configuration repository. The rules
+// covered by a test file are only applied once it passed
+type RuleTestResult struct {
+	// Path of the test file in the repository
+	Name string `json:"name"`
+	// Rules covered by the test file
+	Rules  []string           `json:"rules,omitempty"`
+	Result RuleTestResultType `json:"result"`
+	// Output of promtool if the tests failed
+	Message string `json:"message,omitempty"`
+}
+
+// DashboardConflict is a dashboard that was not applied because a dashboard of a source that wins
+// the conflict has the same name, uid or title
+type DashboardConflict struct {
+	Name string `json:"name"`
+	// Configuration secret the dashboard is from
+	Source string `json:"source,omitempty"`
+	// Field both dashboards have in common: name, uid or title
+	Field string `json:"field"`
+	// Dashboard that was applied instead
+	Winner       string `json:"winner"`
+	WinnerSource string `json:"winnerSource,omitempty"`
+}
+
+// CredentialExpiry is the expiry of a secret the stack depends on
+type CredentialExpiry struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Time the earliest certificate of the secret or the token expires
+	Expires int64 `json:"expires,omitempty"`
+	// Set if the secret could not be parsed
+	Error string `json:"error,omitempty"`
+}
+
+// StackVerificationCheck is the result of a check of the last stack verification
+type StackVerificationCheck struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	// Why the check failed or was skipped
+	Message string `json:"message,omitempty"`
+	// Time the check last passed
+	LastPassed int64 `json:"lastPassed,omitempty"`
+}
+
+// StackVerificationStatus is the result of the last stack verification
+type StackVerificationStatus struct {
+	LastRun int64                    `json:"lastRun"`
+	Checks  []StackVerificationCheck `json:"checks,omitempty"`
+}
+
+// MigrationStatus is the progress of adopting the resources created by the v2 operator
+type MigrationStatus struct {
+	// Resources of the v2 operator found in the namespace of the CR
+	Detected int `json:"detected"`
+	// Resources adopted so far, they are kept as they are and no longer owned by the v2 CR
+	Adopted int `json:"adopted"`
+	// Fields of the spec translated from the v2 resources, as spec paths
+	TranslatedFields []string `json:"translatedFields,omitempty"`
+	// Time all detected resources were adopted, or the stage found none
+	Completed int64 `json:"completed,omitempty"`
+}
+
+// GrafanaDatasourceHealthStatus is the result of the last datasource health check
+type GrafanaDatasourceHealthStatus struct {
+	LastCheck int64 `json:"lastCheck"`
+	// Datasources that failed the last check, with the error
+	Unhealthy []string `json:"unhealthy,omitempty"`
+	// Number of repairs. Every repair increases the version of the provisioned datasources
+	Repairs    int64 `json:"repairs,omitempty"`
+	LastRepair int64 `json:"lastRepair,omitempty"`
+}
+
+// ConfigApplyProgress is the progress of applying the dashboards, rules or pod monitors of the
+// configuration sources
+type ConfigApplyProgress struct {
+	// GrafanaDashboard, PrometheusRule or PodMonitor
+	Kind string `json:"kind"`
+	// Hash of the requested resources. The apply starts over when they change
+	Revision string `json:"revision"`
+	Applied  int    `json:"applied"`
+	Total    int    `json:"total"`
+}
+
+// ConfigConflict is a rule or Alertmanager route defined by more than one configuration source
+type ConfigConflict struct {
+	// Rule or AlertmanagerRoute
+	Kind string `json:"kind"`
+	// Name of the rule or id of the repository index
+	Name string `json:"name"`
+	// Configuration secrets defining the resource, in the order of their priority
+	Sources []string `json:"sources"`
+	// Configuration secret the resource is applied from, empty with ErrorOnConflict
+	Applied string `json:"applied,omitempty"`
 }
 
 // ObservabilityStatus defines the observed state of Observability
@@ -111,6 +1803,93 @@ type ObservabilityStatus struct {
 	TokenExpires int64                    `json:"tokenExpires,omitempty"`
 	ClusterID    string                   `json:"clusterId,omitempty"`
 	LastSynced   int64                    `json:"lastSynced,omitempty"`
+	// Hash of the last Alertmanager config that was verified to be loaded
+	AlertmanagerConfigHash string               `json:"alertmanagerConfigHash,omitempty"`
+	Conditions             []metav1.Condition   `json:"conditions,omitempty"`
+	Subscriptions          []SubscriptionStatus `json:"subscriptions,omitempty"`
+	// Most recently pruned CSVs, install plans and catalog sources
+	PrunedOLMResources []PrunedResource `json:"prunedOLMResources,omitempty"`
+	// Time of the last Observatorium tenant verification
+	ObservatoriumTenantLastChecked int64 `json:"observatoriumTenantLastChecked,omitempty"`
+	// Secrets referenced by the last sync. A change to any of them triggers a new sync
+	ReferencedSecrets []ReferencedSecret   `json:"referencedSecrets,omitempty"`
+	Capabilities      *ClusterCapabilities `json:"capabilities,omitempty"`
+	// Right-sizing recommendations based on the usage recorded by Prometheus
+	ResourceRecommendations []ResourceRecommendation `json:"resourceRecommendations,omitempty"`
+	// Time the recommendations were last computed, successfully or not
+	ResourceRecommendationsUpdated int64 `json:"resourceRecommendationsUpdated,omitempty"`
+	// Revisions of the configuration repositories applied by the last sync
+	ConfigRevisions []ConfigRevision `json:"configRevisions,omitempty"`
+	// Id of the config snapshot applied by the last sync
+	ConfigSnapshot string `json:"configSnapshot,omitempty"`
+	// Id of the config snapshot that was rolled back to, empty when syncing from the repositories
+	ConfigRollback string `json:"configRollback,omitempty"`
+	// Value of the resync annotation handled by the last sync
+	ResyncRequested string `json:"resyncRequested,omitempty"`
+	// Hash of the spec applied by the last sync and when the configuration was last applied. Syncs
+	// that find the repositories and the spec unchanged skip applying it
+	ConfigSpecHash string `json:"configSpecHash,omitempty"`
+	ConfigApplied  int64  `json:"configApplied,omitempty"`
+	// Grafana plugins of which the checksum was verified, as name:version:checksum
+	VerifiedGrafanaPlugins []string `json:"verifiedGrafanaPlugins,omitempty"`
+	// Time of the last successful fleet telemetry report
+	FleetTelemetryReported int64 `json:"fleetTelemetryReported,omitempty"`
+	// Names of the contact points provisioned in Grafana
+	GrafanaContactPoints []string `json:"grafanaContactPoints,omitempty"`
+	// Dashboards skipped by the last sync because they failed validation
+	InvalidDashboards []InvalidDashboard `json:"invalidDashboards,omitempty"`
+	// Dashboards skipped by the last sync because they conflict with a dashboard of a source that
+	// wins the conflict
+	DashboardConflicts []DashboardConflict `json:"dashboardConflicts,omitempty"`
+	// Rules and Alertmanager routes defined by more than one configuration source
+	ConfigConflicts []ConfigConflict `json:"configConflicts,omitempty"`
+	// Progress of the last apply of the configuration, per kind
+	ConfigApplyProgress []ConfigApplyProgress `json:"configApplyProgress,omitempty"`
+	// Silences of the mute time intervals
+	MuteTimeIntervals []MuteTimeIntervalStatus `json:"muteTimeIntervals,omitempty"`
+	// Id of the last Grafana backup and when it was taken
+	GrafanaBackup     string `json:"grafanaBackup,omitempty"`
+	GrafanaBackupTime int64  `json:"grafanaBackupTime,omitempty"`
+	// Id of the Grafana backup that was last restored
+	GrafanaRestoredBackup string `json:"grafanaRestoredBackup,omitempty"`
+	// Sharing features enabled in the managed Grafana
+	GrafanaSharing *GrafanaSharingStatus `json:"grafanaSharing,omitempty"`
+	// Most recent out of band changes of managed resources, one entry per resource
+	Drift []DriftedResource `json:"drift,omitempty"`
+	// Last run of the reports
+	Reports []ReportStatus `json:"reports,omitempty"`
+	// Kinds of optional CRDs the stages need but the cluster does not serve
+	MissingAPIs []string `json:"missingAPIs,omitempty"`
+	// Upgrades and rollouts held back until the next upgrade window
+	DeferredRollouts []DeferredRollout `json:"deferredRollouts,omitempty"`
+	// Value of the rollout annotation handled by the last sync
+	RolloutRequested string `json:"rolloutRequested,omitempty"`
+	// Rule unit tests of the last sync
+	RuleTests []RuleTestResult `json:"ruleTests,omitempty"`
+	// Workload profiles applied by the last reconcile, as name:version
+	AppliedProfiles []string `json:"appliedProfiles,omitempty"`
+	// Alerting rules of the last sync that violate the rule policy
+	RulePolicyViolations []RulePolicyViolation `json:"rulePolicyViolations,omitempty"`
+	// Alerting rules of the last sync that duplicate rules of the platform monitoring
+	DuplicateRules []DuplicateRule `json:"duplicateRules,omitempty"`
+	// Loki rule files skipped by the last sync because they failed validation
+	InvalidLogRules []InvalidLogRule `json:"invalidLogRules,omitempty"`
+	// Alertmanager templates skipped by the last sync because they failed to parse
+	InvalidAlertTemplates []InvalidAlertTemplate `json:"invalidAlertTemplates,omitempty"`
+	// Usage of the tenant quotas
+	TenantQuotas []TenantQuotaStatus `json:"tenantQuotas,omitempty"`
+	// Expiry of the certificates and credentials of the stack
+	CredentialExpiries []CredentialExpiry `json:"credentialExpiries,omitempty"`
+	// Events not yet written to Grafana as annotations
+	PendingGrafanaAnnotations []GrafanaAnnotation `json:"pendingGrafanaAnnotations,omitempty"`
+	// Result of the last stack verification
+	StackVerification *StackVerificationStatus `json:"stackVerification,omitempty"`
+	// Result of the last datasource health check
+	DatasourceHealth *GrafanaDatasourceHealthStatus `json:"datasourceHealth,omitempty"`
+	// Running versions of the components of the stack
+	ComponentVersions []ComponentVersion `json:"componentVersions,omitempty"`
+	// Progress of the migration from the v2 operator, empty if no v2 resources were found
+	Migration *MigrationStatus `json:"migration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -134,6 +1913,15 @@ type ObservabilityList struct {
 	Items           []Observability `json:"items"`
 }
 
+func (in *ObservabilityStatus) IsReferencedSecret(namespace string, name string) bool {
+	for _, secret := range in.ReferencedSecrets {
+		if secret.Namespace == namespace && secret.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (in *Observability) ExternalSyncDisabled() bool {
 	return in.Spec.SelfContained != nil && in.Spec.SelfContained.DisableRepoSync != nil && *in.Spec.SelfContained.DisableRepoSync
 }
@@ -162,6 +1950,339 @@ func (in *Observability) SelfSignedCerts() bool {
 	return in.Spec.SelfContained != nil && in.Spec.SelfContained.SelfSignedCerts != nil && *in.Spec.SelfContained.SelfSignedCerts
 }
 
+func (in *Observability) GetInstallPlanApproval() InstallPlanApprovalPolicy {
+	if in.Spec.OLM != nil && in.Spec.OLM.InstallPlanApproval != "" {
+		return in.Spec.OLM.InstallPlanApproval
+	}
+	return InstallPlanApprovalAutomatic
+}
+
+// Returns the configured way of exposing the UIs, empty if it should be detected
+func (in *Observability) GetUIAccessType() UIAccessType {
+	if in.Spec.SelfContained != nil && in.Spec.SelfContained.UIAccess != nil {
+		return in.Spec.SelfContained.UIAccess.Type
+	}
+	return ""
+}
+
+func (in *Observability) HasUIAccessRBAC() bool {
+	if in.HasAccessGroups() {
+		return true
+	}
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.UIAccess != nil &&
+		(len(in.Spec.SelfContained.UIAccess.Groups) > 0 || len(in.Spec.SelfContained.UIAccess.Users) > 0)
+}
+
+func (in *Observability) HasAccessGroups() bool {
+	return in.Spec.Access != nil && (len(in.Spec.Access.ViewerGroups) > 0 || len(in.Spec.Access.EditorGroups) > 0)
+}
+
+// Returns the resource requirements configured for a component in spec.resources
+func (in *Observability) GetResources(component string) (v1.ResourceRequirements, bool) {
+	resources, ok := in.Spec.Resources[component]
+	return resources, ok
+}
+
+func (in *ObservabilityStatus) GetConfigRevision(name string) *ConfigRevision {
+	for i := range in.ConfigRevisions {
+		if in.ConfigRevisions[i].Name == name {
+			return &in.ConfigRevisions[i]
+		}
+	}
+	return nil
+}
+
+// Records whether an API needed by a stage is missing. The list is kept sorted
+func (in *ObservabilityStatus) SetMissingAPI(api string, missing bool) {
+	var result []string
+	for _, existing := range in.MissingAPIs {
+		if existing != api {
+			result = append(result, existing)
+		}
+	}
+	if missing {
+		result = append(result, api)
+		sort.Strings(result)
+	}
+	in.MissingAPIs = result
+}
+
+// Records the change of a component that is held back, an empty change removes the entry. The
+// time of the first deferral is kept while the component stays deferred
+func (in *ObservabilityStatus) SetDeferredRollout(component string, change string) {
+	var result []DeferredRollout
+	since := time.Now().Unix()
+	for _, existing := range in.DeferredRollouts {
+		if existing.Component == component {
+			since = existing.Since
+			continue
+		}
+		result = append(result, existing)
+	}
+	if change != "" {
+		result = append(result, DeferredRollout{Component: component, Change: change, Since: since})
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].Component < result[j].Component
+		})
+	}
+	in.DeferredRollouts = result
+}
+
+func (in *ObservabilityStatus) GetComponentVersion(name string) *ComponentVersion {
+	for i := range in.ComponentVersions {
+		if in.ComponentVersions[i].Name == name {
+			return &in.ComponentVersions[i]
+		}
+	}
+	return nil
+}
+
+func (in *ObservabilityStatus) GetConfigApplyProgress(kind string) *ConfigApplyProgress {
+	for i := range in.ConfigApplyProgress {
+		if in.ConfigApplyProgress[i].Kind == kind {
+			return &in.ConfigApplyProgress[i]
+		}
+	}
+	return nil
+}
+
+func (in *ObservabilityStatus) GetSubscriptionStatus(name string) *SubscriptionStatus {
+	for i := range in.Subscriptions {
+		if in.Subscriptions[i].Name == name {
+			return &in.Subscriptions[i]
+		}
+	}
+	return nil
+}
+
+func (in *Observability) BackupEnabled() bool {
+	return in.Spec.Backup != nil && in.Spec.Backup.ObjectStorageSecret != ""
+}
+
+func (in *Observability) InternalTLSEnabled() bool {
+	return in.Spec.TLS != nil && in.Spec.TLS.Internal
+}
+
+// Returns true if out of band changes of resources of the kind are not reverted
+func (in *Observability) IsDriftReportOnly(kind string) bool {
+	if in.Spec.Drift == nil {
+		return false
+	}
+	for _, k := range in.Spec.Drift.ReportOnly {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the IP families from spec.networking, nil for the cluster default
+func (in *Observability) IPFamilies() []v1.IPFamily {
+	if in.Spec.Networking != nil {
+		return in.Spec.Networking.IPFamilies
+	}
+	return nil
+}
+
+func (in *Observability) FIPSModeEnabled() bool {
+	return in.Spec.FIPSMode
+}
+
+func (in *Observability) AlertForwarderEnabled() bool {
+	return in.Spec.Alerting != nil && in.Spec.Alerting.Forwarder != nil && len(in.Spec.Alerting.Forwarder.Destinations) > 0
+}
+
+func (in *Observability) AlertTicketingEnabled() bool {
+	return in.Spec.Alerting != nil && in.Spec.Alerting.Ticketing != nil
+}
+
+func (in *Observability) AlertHistoryEnabled() bool {
+	return in.Spec.Alerting != nil && in.Spec.Alerting.History != nil
+}
+
+// AutoResolveEnabled returns true unless resolving the tickets is turned off
+func (in *AlertTicketing) AutoResolveEnabled() bool {
+	return in.AutoResolve == nil || *in.AutoResolve
+}
+
+// ConsoleLinksEnabled returns true unless the console links are turned off
+func (in *Observability) ConsoleLinksEnabled() bool {
+	return in.Spec.Console == nil || in.Spec.Console.Links == nil || *in.Spec.Console.Links
+}
+
+// ConsoleDashboardEnabled returns true unless the console dashboard is turned off
+func (in *Observability) ConsoleDashboardEnabled() bool {
+	return in.Spec.Console == nil || in.Spec.Console.Dashboard == nil || *in.Spec.Console.Dashboard
+}
+
+func (in *Observability) ConsolePluginEnabled() bool {
+	return in.Spec.Console != nil && in.Spec.Console.Plugin != nil
+}
+
+func (in *Observability) PrometheusAdapterEnabled() bool {
+	return in.Spec.PrometheusAdapter != nil
+}
+
+// SelfMonitoringAlertsEnabled returns true unless the alerts are turned off. They are evaluated by
+// the managed Prometheus
+func (in *Observability) SelfMonitoringAlertsEnabled() bool {
+	if in.PrometheusMode() != ComponentManaged || in.PrometheusAgentEnabled() {
+		return false
+	}
+	return in.Spec.SelfMonitoring == nil || in.Spec.SelfMonitoring.Alerts == nil || *in.Spec.SelfMonitoring.Alerts
+}
+
+func (in *Observability) HasObservatoriumTenant() bool {
+	return in.Spec.Observatorium != nil && in.Spec.Observatorium.Tenant != nil
+}
+
+func (in *Observability) TracingEnabled() bool {
+	return in.Spec.Tracing != nil && in.Spec.Tracing.Storage != nil
+}
+
+func (in *Observability) PrometheusMode() ComponentMode {
+	if in.Spec.Components != nil && in.Spec.Components.Prometheus != "" {
+		return in.Spec.Components.Prometheus
+	}
+	if in.UserWorkloadMonitoringEnabled() {
+		return ComponentExternal
+	}
+	return ComponentManaged
+}
+
+// PrometheusAgentEnabled returns true if the managed Prometheus only forwards metrics
+func (in *Observability) PrometheusAgentEnabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.PrometheusMode == PrometheusRunModeAgent
+}
+
+func (in *Observability) QueryLoggingEnabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.QueryLogging != nil
+}
+
+// UserWorkloadMonitoringEnabled returns true if metrics are collected by the user workload
+// monitoring of OpenShift
+func (in *Observability) UserWorkloadMonitoringEnabled() bool {
+	return in.Spec.UserWorkloadMonitoring != nil
+}
+
+func (in *Observability) AlertmanagerMode() ComponentMode {
+	if in.Spec.Components != nil && in.Spec.Components.Alertmanager != "" {
+		return in.Spec.Components.Alertmanager
+	}
+	if in.PrometheusAgentEnabled() {
+		return ComponentDisabled
+	}
+	return ComponentManaged
+}
+
+func (in *Observability) DashboardMergeStrategy() MergeStrategy {
+	if in.Spec.ConfigMerge != nil && in.Spec.ConfigMerge.Dashboards != "" {
+		return in.Spec.ConfigMerge.Dashboards
+	}
+	return MergeFirstWins
+}
+
+func (in *Observability) RuleMergeStrategy() MergeStrategy {
+	if in.Spec.ConfigMerge != nil && in.Spec.ConfigMerge.Rules != "" {
+		return in.Spec.ConfigMerge.Rules
+	}
+	return MergeFirstWins
+}
+
+// Returns the filters of the configuration source, nil if all of its resources are synced
+func (in *Observability) GetConfigurationSource(name string) *ConfigurationSource {
+	for i, source := range in.Spec.ConfigurationSources {
+		if source.Name == name {
+			return &in.Spec.ConfigurationSources[i]
+		}
+	}
+	return nil
+}
+
+func (in *Observability) AlertmanagerRouteMergeStrategy() MergeStrategy {
+	if in.Spec.ConfigMerge != nil && in.Spec.ConfigMerge.AlertmanagerRoutes != "" {
+		return in.Spec.ConfigMerge.AlertmanagerRoutes
+	}
+	return MergeFirstWins
+}
+
+func (in *Observability) GrafanaMode() ComponentMode {
+	if in.Spec.Components != nil && in.Spec.Components.Grafana != "" {
+		return in.Spec.Components.Grafana
+	}
+	if in.GrafanaExternal() {
+		return ComponentExternal
+	}
+	if in.PrometheusAgentEnabled() {
+		return ComponentDisabled
+	}
+	return ComponentManaged
+}
+
+// GrafanaExternal returns true if an existing Grafana is configured through its HTTP API
+func (in *Observability) GrafanaExternal() bool {
+	return in.Spec.Grafana != nil && in.Spec.Grafana.External != nil
+}
+
+func (in *Observability) GrafanaImageRendererEnabled() bool {
+	return in.Spec.Grafana != nil && in.Spec.Grafana.ImageRenderer != nil && in.Spec.Grafana.ImageRenderer.Enabled
+}
+
+func (in *Observability) GrafanaVolumeEnabled() bool {
+	return in.Spec.Grafana != nil && in.Spec.Grafana.Persistence != nil && in.Spec.Grafana.Persistence.Volume != nil
+}
+
+func (in *Observability) GrafanaDatabaseEnabled() bool {
+	return in.Spec.Grafana != nil && in.Spec.Grafana.Persistence != nil && in.Spec.Grafana.Persistence.Database != nil
+}
+
+// GrafanaAnnotationSourceEnabled returns true if events of the source are annotated in Grafana
+func (in *Observability) GrafanaAnnotationSourceEnabled(source GrafanaAnnotationSource) bool {
+	if in.Spec.Grafana == nil || in.Spec.Grafana.Annotations == nil {
+		return false
+	}
+	if len(in.Spec.Grafana.Annotations.Sources) == 0 {
+		return true
+	}
+	for _, enabled := range in.Spec.Grafana.Annotations.Sources {
+		if enabled == source {
+			return true
+		}
+	}
+	return false
+}
+
+// DatasourceHealthCheckEnabled returns true unless the checks are turned off or Grafana is not
+// managed by the operator
+func (in *Observability) DatasourceHealthCheckEnabled() bool {
+	if in.Spec.Grafana != nil && in.Spec.Grafana.DatasourceHealth != nil && in.Spec.Grafana.DatasourceHealth.Disabled {
+		return false
+	}
+	return in.GrafanaMode() == ComponentManaged || in.GrafanaExternal()
+}
+
+func (in *Observability) StackVerificationEnabled() bool {
+	return in.Spec.StackVerification != nil
+}
+
+func (in *Observability) GrafanaLDAPEnabled() bool {
+	return in.Spec.Grafana != nil && in.Spec.Grafana.Auth != nil && in.Spec.Grafana.Auth.LDAP != nil
+}
+
+func (in *Observability) PromtailMode() ComponentMode {
+	if in.Spec.Components != nil && in.Spec.Components.Promtail != "" {
+		return in.Spec.Components.Promtail
+	}
+	return ComponentManaged
+}
+
+func (in *Observability) TokenRefresherMode() ComponentMode {
+	if in.Spec.Components != nil && in.Spec.Components.TokenRefresher != "" {
+		return in.Spec.Components.TokenRefresher
+	}
+	return ComponentManaged
+}
+
 func (in *Observability) HasAlertmanagerConfigSecret() (bool, string) {
 	if in.Spec.SelfContained != nil && in.Spec.SelfContained.AlertManagerConfigSecret != "" {
 		return true, in.Spec.SelfContained.AlertManagerConfigSecret
@@ -18,16 +18,108 @@ package v1
 
 import (
 	"errors"
+	"fmt"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"net"
+	"net/mail"
+	"net/url"
+	"path"
+	"regexp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // log is for logging in this package.
 var observabilitylog = logf.Log.WithName("observability-resource")
 
+// Image references by tag or digest, e.g. registry.example.com/prometheus/prometheus@sha256:<digest>
+var imageRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._\-/]*(:[0-9]+/[a-zA-Z0-9._\-/]+)?(:[\w][\w.\-]{0,127})?(@sha256:[a-f0-9]{64})?$`)
+
+var imageComponents = []string{
+	ImagePrometheus,
+	ImageAlertmanager,
+	ImageGrafana,
+	ImageOAuthProxy,
+	ImageOAuth2Proxy,
+	ImageBlackboxExporter,
+	ImagePromtail,
+	ImageTokenRefresher,
+	ImagePrometheusCatalogIndex,
+	ImageGrafanaCatalogIndex,
+	ImageGrafanaPluginBundle,
+	ImageTempo,
+	ImageKubeRbacProxy,
+	ImagePromLabelProxy,
+	ImageThanos,
+	ImageAlertForwarder,
+	ImageAlertTicketing,
+	ImageAlertHistory,
+}
+
+var resourcesComponents = []string{
+	ResourcesGrafana,
+	ResourcesGrafanaOperator,
+	ResourcesPrometheus,
+	ResourcesPrometheusOperator,
+	ResourcesAlertmanager,
+	ResourcesPromtail,
+	ResourcesTokenRefresher,
+	ResourcesTempo,
+}
+
+// Hex encoded sha256 digests of Grafana plugin archives
+var checksumRegex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// Prefixed with alert-forwarder- in the Alertmanager receiver name and used in the url path
+var alertForwarderNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Prefixed with observability- in the name of the key in Grafana
+var grafanaAPIKeyNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Prefixed with observability- in the notification channel uid, which Grafana limits to 40 characters
+var contactPointNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,24}[a-z0-9])?$`)
+
+// Used in the volume names of the secrets of the client, e.g. client-<name>-token
+var promtailClientNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,48}[a-z0-9])?$`)
+
+// Loki tenant ids, which are limited to 150 characters
+var logTenantRegex = regexp.MustCompile(`^[a-zA-Z0-9!._*'()-]{1,150}$`)
+
+// Names of log metrics and their labels
+var metricNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Names of config values, as referenced by ${NAME} placeholders
+var configValueNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Prefix of the placeholders of the values provided by the operator
+const ConfigValueReservedPrefix = "OBSERVABILITY_"
+
+// Labels of the log streams of the Promtail config
+var logStreamLabels = map[string]bool{
+	"namespace":      true,
+	"instance":       true,
+	"container_name": true,
+	"nodename":       true,
+	"job":            true,
+	"filename":       true,
+	"unit":           true,
+}
+
+// Durations as accepted by Prometheus, e.g. 30s or 1h
+var prometheusDurationRegex = regexp.MustCompile("^[0-9]+(((ms)|y|w|d|h|m|s)){1}$")
+
+func IsValidPrometheusDuration(duration string) bool {
+	return prometheusDurationRegex.MatchString(duration)
+}
+
 func (in *Observability) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(in).
@@ -36,7 +128,7 @@ func (in *Observability) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// +kubebuilder:webhook:verbs=update,path=/validate-observability-redhat-com-v1-observability,mutating=false,failurePolicy=fail,groups=observability.redhat.com,resources=observabilities,versions=v1,name=vobservability.kb.io
+// +kubebuilder:webhook:verbs=create;update,path=/validate-observability-redhat-com-v1-observability,mutating=false,failurePolicy=fail,groups=observability.redhat.com,resources=observabilities,versions=v1,name=vobservability.kb.io
 
 var _ webhook.Validator = &Observability{}
 
@@ -44,14 +136,496 @@ var _ webhook.Validator = &Observability{}
 func (in *Observability) ValidateCreate() error {
 	observabilitylog.Info("validate create", "name", in.Name)
 
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil
+	err := in.validatePrometheusTuning()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateOLM()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateUIAccess()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateImageOverrides()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaPlugins()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateAlerting()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateTracing()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateFleetTelemetry()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateTokenRefresher()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateAlertmanagerCluster()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaNotifications()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogs()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogMetrics()
+	if err != nil {
+		return err
+	}
+
+	err = in.validatePromtailClients()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogTenants()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogRules()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogBuffering()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaExternal()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaAccess()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaLDAP()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaPersistence()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaAnnotations()
+	if err != nil {
+		return err
+	}
+	err = in.validateGrafanaSharing()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateUserWorkloadMonitoring()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateComponents()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConfigValues()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateBackup()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateReports()
+	if err != nil {
+		return err
+	}
+
+	err = in.validatePrometheusAdapter()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateTenantQuotas()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConfigMerge()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConfigurationSources()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConfigApply()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateRulePolicy()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateRuleDeduplication()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateProfiles()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateAccess()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateMuteTimeIntervals()
+	if err != nil {
+		return err
+	}
+
+	err = in.validatePrometheusAgentMode()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateExpiryMonitoring()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateStackVerification()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateExternalSecrets()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateQueryLogging()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateUpgradeWindow()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateDatasourceHealth()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConsole()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateFIPSMode()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateNetworking()
+	if err != nil {
+		return err
+	}
+
+	return in.validateResources()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (in *Observability) ValidateUpdate(old runtime.Object) error {
 	observabilitylog.Info("validate update", "name", in.Name)
 
+	err := in.validatePrometheusTuning()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateOLM()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateUIAccess()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateImageOverrides()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaPlugins()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateAlerting()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateTracing()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateFleetTelemetry()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateTokenRefresher()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateAlertmanagerCluster()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaNotifications()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogs()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogMetrics()
+	if err != nil {
+		return err
+	}
+
+	err = in.validatePromtailClients()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogTenants()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogRules()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateLogBuffering()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaExternal()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaAccess()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaLDAP()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaPersistence()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateGrafanaAnnotations()
+	if err != nil {
+		return err
+	}
+	err = in.validateGrafanaSharing()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateUserWorkloadMonitoring()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateComponents()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConfigValues()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateBackup()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateReports()
+	if err != nil {
+		return err
+	}
+
+	err = in.validatePrometheusAdapter()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateTenantQuotas()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConfigMerge()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConfigurationSources()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConfigApply()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateRulePolicy()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateRuleDeduplication()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateProfiles()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateAccess()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateMuteTimeIntervals()
+	if err != nil {
+		return err
+	}
+
+	err = in.validatePrometheusAgentMode()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateExpiryMonitoring()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateStackVerification()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateExternalSecrets()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateQueryLogging()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateUpgradeWindow()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateDatasourceHealth()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateConsole()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateFIPSMode()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateNetworking()
+	if err != nil {
+		return err
+	}
+
+	err = in.validateResources()
+	if err != nil {
+		return err
+	}
+
 	// For each value the following cannot be done
 	// unset it if it's already present
 	//	// set it if it's not set - the default kafka entry is already used
@@ -108,6 +682,1482 @@ func (in *Observability) ValidateUpdate(old runtime.Object) error {
 		strings.Compare(old.(*Observability).Spec.PrometheusDefaultName, in.Spec.PrometheusDefaultName) != 0 {
 		return errors.New("cannot update PrometheusDefaultName after cr creation")
 	}
+
+	//PrometheusStorageRequest
+	if old.(*Observability).Spec.SelfContained != nil &&
+		old.(*Observability).Spec.SelfContained.PrometheusStorageRequest != "" &&
+		in.Spec.SelfContained != nil &&
+		in.Spec.SelfContained.PrometheusStorageRequest != "" {
+		newSize, err := resource.ParseQuantity(in.Spec.SelfContained.PrometheusStorageRequest)
+		if err != nil {
+			return errors.New("invalid PrometheusStorageRequest")
+		}
+		// An invalid old value can always be replaced
+		oldSize, err := resource.ParseQuantity(old.(*Observability).Spec.SelfContained.PrometheusStorageRequest)
+		if err == nil && newSize.Cmp(oldSize) < 0 {
+			return errors.New("cannot decrease PrometheusStorageRequest, volumes can only be expanded")
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validatePrometheusTuning() error {
+	if in.Spec.SelfContained == nil {
+		return nil
+	}
+
+	if in.Spec.SelfContained.PrometheusScrapeInterval != "" &&
+		!IsValidPrometheusDuration(in.Spec.SelfContained.PrometheusScrapeInterval) {
+		return errors.New("invalid PrometheusScrapeInterval")
+	}
+
+	if in.Spec.SelfContained.PrometheusEvaluationInterval != "" &&
+		!IsValidPrometheusDuration(in.Spec.SelfContained.PrometheusEvaluationInterval) {
+		return errors.New("invalid PrometheusEvaluationInterval")
+	}
+
+	if in.Spec.SelfContained.PrometheusQueryTimeout != "" &&
+		!IsValidPrometheusDuration(in.Spec.SelfContained.PrometheusQueryTimeout) {
+		return errors.New("invalid PrometheusQueryTimeout")
+	}
+
+	if in.Spec.SelfContained.PrometheusQueryMaxSamples != nil &&
+		*in.Spec.SelfContained.PrometheusQueryMaxSamples <= 0 {
+		return errors.New("PrometheusQueryMaxSamples must be greater than zero")
+	}
+
+	if in.Spec.SelfContained.PrometheusShards != nil &&
+		*in.Spec.SelfContained.PrometheusShards <= 0 {
+		return errors.New("PrometheusShards must be greater than zero")
+	}
+
+	return nil
+}
+
+func (in *Observability) validateOLM() error {
+	switch in.GetInstallPlanApproval() {
+	case InstallPlanApprovalAutomatic, InstallPlanApprovalManual, InstallPlanApprovalRollback:
+		return nil
+	default:
+		return errors.New("invalid InstallPlanApproval, must be one of Automatic, Manual or Rollback")
+	}
+}
+
+func (in *Observability) validateUIAccess() error {
+	if in.Spec.SelfContained == nil || in.Spec.SelfContained.UIAccess == nil {
+		return nil
+	}
+
+	access := in.Spec.SelfContained.UIAccess
+	if access.OIDC != nil {
+		if access.OIDC.IssuerURL == "" || access.OIDC.CredentialsSecret == "" {
+			return errors.New("IssuerURL and CredentialsSecret are required when logging in to the UIs with OIDC")
+		}
+		if len(access.Users) > 0 {
+			return errors.New("Users are not supported when logging in to the UIs with OIDC, access is limited with Groups")
+		}
+	}
+
+	switch in.GetUIAccessType() {
+	case "", UIAccessRoute:
+		return nil
+	case UIAccessIngress:
+		if access.PrometheusHost == "" || access.AlertmanagerHost == "" || access.GrafanaHost == "" {
+			return errors.New("PrometheusHost, AlertmanagerHost and GrafanaHost are required when exposing the UIs with an Ingress")
+		}
+		return nil
+	default:
+		return errors.New("invalid UIAccess type, must be one of Route or Ingress")
+	}
+}
+
+func (in *Observability) validateImageOverrides() error {
+	for component, image := range in.Spec.ImageOverrides {
+		known := false
+		for _, c := range imageComponents {
+			if c == component {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown component in ImageOverrides: %v", component)
+		}
+		if !imageRegex.MatchString(image) {
+			return fmt.Errorf("invalid image for component %v: %v", component, image)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateGrafanaPlugins() error {
+	names := map[string]bool{}
+	for _, plugin := range in.Spec.GrafanaPlugins {
+		if plugin.Name == "" || plugin.Version == "" {
+			return fmt.Errorf("grafana plugins require a name and a version")
+		}
+		if names[plugin.Name] {
+			return fmt.Errorf("duplicate grafana plugin: %v", plugin.Name)
+		}
+		names[plugin.Name] = true
+		if plugin.Checksum != "" && !checksumRegex.MatchString(plugin.Checksum) {
+			return fmt.Errorf("invalid checksum for grafana plugin %v, expected a sha256 hex digest", plugin.Name)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateAlerting() error {
+	if in.Spec.Alerting == nil {
+		return nil
+	}
+	for i, rule := range in.Spec.Alerting.InhibitRules {
+		if len(rule.SourceMatch)+len(rule.SourceMatchRegex) == 0 || len(rule.TargetMatch)+len(rule.TargetMatchRegex) == 0 {
+			return fmt.Errorf("inhibit rule %v requires source and target matchers", i)
+		}
+		for _, matchers := range []map[string]string{rule.SourceMatchRegex, rule.TargetMatchRegex} {
+			for label, expr := range matchers {
+				_, err := regexp.Compile(expr)
+				if err != nil {
+					return fmt.Errorf("invalid regex for label %v in inhibit rule %v: %v", label, i, err)
+				}
+			}
+		}
+	}
+	if in.Spec.Alerting.Forwarder != nil {
+		err := in.validateAlertForwarder()
+		if err != nil {
+			return err
+		}
+	}
+	if in.Spec.Alerting.Ticketing != nil {
+		err := in.validateAlertTicketing()
+		if err != nil {
+			return err
+		}
+	}
+	if in.Spec.Alerting.Templates != nil && in.Spec.Alerting.Templates.ConfigMapSelector != nil {
+		_, err := metav1.LabelSelectorAsSelector(in.Spec.Alerting.Templates.ConfigMapSelector)
+		if err != nil {
+			return fmt.Errorf("invalid alert templates config map selector: %v", err)
+		}
+	}
+	if in.Spec.Alerting.History != nil {
+		return in.validateAlertHistory()
+	}
+	return nil
+}
+
+func (in *Observability) validateAlertHistory() error {
+	history := in.Spec.Alerting.History
+	if history.RetentionDays < 0 {
+		return errors.New("alert history retention days can't be negative")
+	}
+	if history.Size != "" {
+		_, err := resource.ParseQuantity(history.Size)
+		if err != nil {
+			return fmt.Errorf("invalid alert history size: %v", history.Size)
+		}
+	}
+	if in.AlertmanagerMode() != ComponentManaged {
+		return errors.New("alert history requires a managed alertmanager")
+	}
+	return nil
+}
+
+func (in *Observability) validateAlertTicketing() error {
+	ticketing := in.Spec.Alerting.Ticketing
+	switch ticketing.Type {
+	case AlertTicketingTypeServiceNow:
+		if ticketing.Jira != nil {
+			return errors.New("jira settings require the jira ticketing type")
+		}
+	case AlertTicketingTypeJira:
+		if ticketing.Jira == nil || ticketing.Jira.Project == "" {
+			return errors.New("jira ticketing requires a project")
+		}
+		if ticketing.ServiceNow != nil {
+			return errors.New("servicenow settings require the servicenow ticketing type")
+		}
+	default:
+		return fmt.Errorf("unsupported ticketing type: %v", ticketing.Type)
+	}
+
+	u, err := url.Parse(ticketing.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid ticketing url: %v", ticketing.URL)
+	}
+	if ticketing.CredentialsSecret == "" {
+		return errors.New("ticketing requires a credentials secret")
+	}
+
+	fields := map[string]string{}
+	for name, text := range ticketing.Fields {
+		fields[name] = text
+	}
+	if ticketing.ServiceNow != nil {
+		for name, text := range ticketing.ServiceNow.ResolveFields {
+			fields["resolve "+name] = text
+		}
+	}
+	for name, text := range fields {
+		_, err := ParseAlertForwarderTemplate(name, text)
+		if err != nil {
+			return fmt.Errorf("invalid template for ticketing field %v: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateAlertForwarder() error {
+	names := map[string]bool{}
+	for _, destination := range in.Spec.Alerting.Forwarder.Destinations {
+		if !alertForwarderNameRegex.MatchString(destination.Name) {
+			return fmt.Errorf("invalid alert forwarder destination name: %v", destination.Name)
+		}
+		if names[destination.Name] {
+			return fmt.Errorf("duplicate alert forwarder destination: %v", destination.Name)
+		}
+		names[destination.Name] = true
+
+		switch destination.Type {
+		case AlertForwarderTypeHTTP, AlertForwarderTypeGoogleChat:
+			if destination.URLSecret == "" {
+				return fmt.Errorf("alert forwarder destination %v requires a url secret", destination.Name)
+			}
+		case AlertForwarderTypeEventBridge:
+			eventBridge := destination.EventBridge
+			if eventBridge == nil || eventBridge.Region == "" || eventBridge.CredentialsSecret == "" {
+				return fmt.Errorf("alert forwarder destination %v requires an eventbridge region and credentials secret", destination.Name)
+			}
+		default:
+			return fmt.Errorf("unsupported type for alert forwarder destination %v: %v", destination.Name, destination.Type)
+		}
+
+		_, err := ParseAlertForwarderTemplate(destination.Name, destination.Template)
+		if err != nil {
+			return fmt.Errorf("invalid template for alert forwarder destination %v: %v", destination.Name, err)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateTracing() error {
+	if !in.TracingEnabled() {
+		return nil
+	}
+
+	storage := in.Spec.Tracing.Storage
+	if storage.Size != "" {
+		_, err := resource.ParseQuantity(storage.Size)
+		if err != nil {
+			return fmt.Errorf("invalid tracing storage size: %v", storage.Size)
+		}
+	}
+	if storage.Retention != "" {
+		_, err := time.ParseDuration(storage.Retention)
+		if err != nil {
+			return fmt.Errorf("invalid tracing retention: %v", storage.Retention)
+		}
+	}
+	switch storage.ObjectStorageType {
+	case "", "s3", "gcs", "azure":
+		return nil
+	default:
+		return errors.New("invalid tracing object storage type, must be one of s3, gcs or azure")
+	}
+}
+
+func (in *Observability) validateFleetTelemetry() error {
+	telemetry := in.Spec.FleetTelemetry
+	if telemetry == nil {
+		return nil
+	}
+
+	endpoint, err := url.ParseRequestURI(telemetry.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return fmt.Errorf("invalid fleet telemetry endpoint: %v", telemetry.Endpoint)
+	}
+	if telemetry.Interval != "" {
+		_, err = time.ParseDuration(telemetry.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid fleet telemetry interval: %v", telemetry.Interval)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateTokenRefresher() error {
+	refresher := in.Spec.TokenRefresher
+	if refresher == nil {
+		return nil
+	}
+
+	if refresher.Replicas != nil && *refresher.Replicas < 1 {
+		return fmt.Errorf("invalid token refresher replicas: %v", *refresher.Replicas)
+	}
+	if autoscaling := refresher.Autoscaling; autoscaling != nil {
+		minReplicas := int32(1)
+		if autoscaling.MinReplicas != nil {
+			minReplicas = *autoscaling.MinReplicas
+		}
+		if minReplicas < 1 || autoscaling.MaxReplicas < minReplicas {
+			return fmt.Errorf("invalid token refresher autoscaling: %v to %v replicas", minReplicas, autoscaling.MaxReplicas)
+		}
+		if target := autoscaling.TargetCPUUtilization; target != nil && (*target < 1 || *target > 100) {
+			return fmt.Errorf("invalid token refresher target cpu utilization: %v", *target)
+		}
+	}
+
+	// Used by Prometheus and Promtail, so the durations must be valid for both
+	durations := []string{refresher.RequestTimeout}
+	if refresher.Backoff != nil {
+		durations = append(durations, refresher.Backoff.MinBackoff, refresher.Backoff.MaxBackoff)
+	}
+	for _, duration := range durations {
+		if duration == "" {
+			continue
+		}
+		_, err := time.ParseDuration(duration)
+		if err != nil || !IsValidPrometheusDuration(duration) {
+			return fmt.Errorf("invalid token refresher duration: %v", duration)
+		}
+	}
+	if backoff := refresher.Backoff; backoff != nil && backoff.MinBackoff != "" && backoff.MaxBackoff != "" {
+		minBackoff, _ := time.ParseDuration(backoff.MinBackoff)
+		maxBackoff, _ := time.ParseDuration(backoff.MaxBackoff)
+		if minBackoff > maxBackoff {
+			return fmt.Errorf("token refresher min backoff %v exceeds max backoff %v", backoff.MinBackoff, backoff.MaxBackoff)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateAlertmanagerCluster() error {
+	if in.Spec.SelfContained == nil {
+		return nil
+	}
+
+	selfContained := in.Spec.SelfContained
+	if selfContained.AlertManagerExternalURL != "" {
+		externalUrl, err := url.ParseRequestURI(selfContained.AlertManagerExternalURL)
+		if err != nil || externalUrl.Host == "" {
+			return fmt.Errorf("invalid AlertManagerExternalURL: %v", selfContained.AlertManagerExternalURL)
+		}
+	}
+
+	addresses := selfContained.AlertManagerPeers
+	if selfContained.AlertManagerClusterAdvertiseAddress != "" {
+		addresses = append(addresses, selfContained.AlertManagerClusterAdvertiseAddress)
+	}
+	for _, address := range addresses {
+		_, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return fmt.Errorf("invalid alertmanager cluster address, expected host:port: %v", address)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateGrafanaNotifications() error {
+	if in.Spec.Grafana == nil {
+		return nil
+	}
+
+	if smtp := in.Spec.Grafana.Smtp; smtp != nil {
+		_, _, err := net.SplitHostPort(smtp.Host)
+		if err != nil {
+			return fmt.Errorf("invalid grafana smtp host, expected host:port: %v", smtp.Host)
+		}
+		if smtp.FromAddress == "" {
+			return fmt.Errorf("grafana smtp requires a from address")
+		}
+	}
+
+	names := map[string]bool{}
+	for _, contactPoint := range in.Spec.Grafana.ContactPoints {
+		if !contactPointNameRegex.MatchString(contactPoint.Name) {
+			return fmt.Errorf("invalid grafana contact point name: %v", contactPoint.Name)
+		}
+		if names[contactPoint.Name] {
+			return fmt.Errorf("duplicate grafana contact point: %v", contactPoint.Name)
+		}
+		names[contactPoint.Name] = true
+
+		switch contactPoint.Type {
+		case GrafanaContactPointEmail:
+			if in.Spec.Grafana.Smtp == nil {
+				return fmt.Errorf("grafana contact point %v requires smtp", contactPoint.Name)
+			}
+			if len(contactPoint.Addresses) == 0 {
+				return fmt.Errorf("grafana contact point %v has no addresses", contactPoint.Name)
+			}
+		case GrafanaContactPointSlack:
+			if contactPoint.SlackWebhookSecret == "" {
+				return fmt.Errorf("grafana contact point %v has no slack webhook secret", contactPoint.Name)
+			}
+		default:
+			return fmt.Errorf("unsupported type of grafana contact point %v: %v", contactPoint.Name, contactPoint.Type)
+		}
+
+		if contactPoint.ReminderInterval != "" {
+			_, err := time.ParseDuration(contactPoint.ReminderInterval)
+			if err != nil {
+				return fmt.Errorf("invalid reminder interval of grafana contact point %v: %v", contactPoint.Name, contactPoint.ReminderInterval)
+			}
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateLogs() error {
+	if in.Spec.SelfContained == nil || in.Spec.SelfContained.Logs == nil {
+		return nil
+	}
+
+	logs := in.Spec.SelfContained.Logs
+	for _, selector := range []*metav1.LabelSelector{logs.NamespaceSelector, logs.PodSelector} {
+		_, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return fmt.Errorf("invalid logs selector: %v", err)
+		}
+	}
+
+	// The directories of the host paths are mounted into Promtail
+	for _, hostPath := range logs.HostPaths {
+		dir := path.Dir(hostPath)
+		if !path.IsAbs(hostPath) || dir == "/" || strings.ContainsAny(dir, "*?[") {
+			return fmt.Errorf("invalid logs host path, expected an absolute path with globs only in the file name: %v", hostPath)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateLogMetrics() error {
+	if in.Spec.Logs == nil {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, metric := range in.Spec.Logs.Metrics {
+		if !metricNameRegex.MatchString(metric.Name) {
+			return fmt.Errorf("invalid log metric name: %v", metric.Name)
+		}
+		if names[metric.Name] {
+			return fmt.Errorf("duplicate log metric: %v", metric.Name)
+		}
+		names[metric.Name] = true
+
+		if metric.Type != LogMetricCounter && metric.Type != LogMetricHistogram {
+			return fmt.Errorf("unsupported type of log metric %v: %v", metric.Name, metric.Type)
+		}
+		if (metric.Regex == "") == (metric.JSONField == "") {
+			return fmt.Errorf("log metric %v requires either a regex or a json field", metric.Name)
+		}
+
+		// Labels and the observed value are taken from the named groups of the regex
+		if metric.Regex != "" {
+			expression, err := regexp.Compile(metric.Regex)
+			if err != nil {
+				return fmt.Errorf("invalid regex of log metric %v: %v", metric.Name, err)
+			}
+			groups := map[string]bool{}
+			for _, group := range expression.SubexpNames() {
+				groups[group] = true
+			}
+			for _, label := range metric.Labels {
+				if !groups[label] {
+					return fmt.Errorf("label %v of log metric %v is not a named group of the regex", label, metric.Name)
+				}
+			}
+			if metric.Type == LogMetricHistogram && !groups["value"] {
+				return fmt.Errorf("regex of histogram log metric %v has no group named value", metric.Name)
+			}
+		}
+
+		// The labels are removed from the log stream after the metric is recorded, so they must not
+		// replace the labels Promtail adds to every stream
+		for _, label := range metric.Labels {
+			if !metricNameRegex.MatchString(label) || logStreamLabels[label] || strings.HasPrefix(label, "strimzi_io_") {
+				return fmt.Errorf("invalid label of log metric %v: %v", metric.Name, label)
+			}
+		}
+
+		if metric.Type == LogMetricHistogram && len(metric.Buckets) == 0 {
+			return fmt.Errorf("histogram log metric %v has no buckets", metric.Name)
+		}
+		for _, bucket := range metric.Buckets {
+			_, err := strconv.ParseFloat(bucket, 64)
+			if err != nil {
+				return fmt.Errorf("invalid bucket of log metric %v: %v", metric.Name, bucket)
+			}
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validatePromtailClients() error {
+	if in.Spec.Logs == nil {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, c := range in.Spec.Logs.Clients {
+		if !promtailClientNameRegex.MatchString(c.Name) {
+			return fmt.Errorf("invalid promtail client name: %v", c.Name)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("duplicate promtail client: %v", c.Name)
+		}
+		names[c.Name] = true
+
+		u, err := url.ParseRequestURI(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid url of promtail client %v: %v", c.Name, c.URL)
+		}
+		if u.Scheme == "http" && (c.CASecret != "" || c.CertSecret != "" || c.InsecureSkipVerify) {
+			return fmt.Errorf("promtail client %v has tls settings but no https url", c.Name)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateLogTenants() error {
+	if in.Spec.Logs == nil {
+		return nil
+	}
+
+	logs := in.Spec.Logs
+	for _, tenant := range logs.Tenants {
+		if !logTenantRegex.MatchString(tenant.Tenant) || tenant.Tenant == "." || tenant.Tenant == ".." {
+			return fmt.Errorf("invalid log tenant: %v", tenant.Tenant)
+		}
+		if len(tenant.Namespaces) == 0 && tenant.NamespaceSelector == nil {
+			return fmt.Errorf("log tenant %v requires namespaces or a namespace selector", tenant.Tenant)
+		}
+		_, err := metav1.LabelSelectorAsSelector(tenant.NamespaceSelector)
+		if err != nil {
+			return fmt.Errorf("invalid namespace selector of log tenant %v: %v", tenant.Tenant, err)
+		}
+	}
+
+	if logs.TenantNamespaceLabel != "" {
+		if errs := validation.IsQualifiedName(logs.TenantNamespaceLabel); len(errs) > 0 {
+			return fmt.Errorf("invalid tenant namespace label %v: %v", logs.TenantNamespaceLabel, strings.Join(errs, ", "))
+		}
+	}
+	if logs.OnlyTenantNamespaces && len(logs.Tenants) == 0 && logs.TenantNamespaceLabel == "" {
+		return fmt.Errorf("only tailing the namespaces of log tenants requires tenants or a tenant namespace label")
+	}
+	return nil
+}
+
+func (in *Observability) validateLogRules() error {
+	if in.Spec.Logs == nil || in.Spec.Logs.Rules == nil {
+		return nil
+	}
+
+	rules := in.Spec.Logs.Rules
+	if _, err := metav1.LabelSelectorAsSelector(rules.ConfigMapSelector); err != nil {
+		return fmt.Errorf("invalid config map selector of log rules: %v", err)
+	}
+	if rules.Ruler == nil {
+		if in.Spec.Observatorium == nil || in.Spec.Observatorium.Tenant == nil {
+			return fmt.Errorf("log rules require a ruler or an observatorium tenant")
+		}
+		return nil
+	}
+	u, err := url.ParseRequestURI(rules.Ruler.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid url of the loki ruler: %v", rules.Ruler.URL)
+	}
+	if u.Scheme == "http" && rules.Ruler.InsecureSkipVerify {
+		return fmt.Errorf("loki ruler has tls settings but no https url")
+	}
+	return nil
+}
+
+func (in *Observability) validateLogBuffering() error {
+	if in.Spec.Logs == nil || in.Spec.Logs.Buffering == nil {
+		return nil
+	}
+
+	buffering := in.Spec.Logs.Buffering
+	switch buffering.Storage {
+	case "", LogBufferingHostPath:
+		if buffering.HostPath != "" && (!path.IsAbs(buffering.HostPath) || path.Clean(buffering.HostPath) == "/") {
+			return fmt.Errorf("invalid log buffering host path, expected an absolute directory: %v", buffering.HostPath)
+		}
+		if buffering.Size != "" || buffering.StorageClass != nil {
+			return errors.New("log buffering size and storage class require the PersistentVolumeClaim storage")
+		}
+	case LogBufferingPersistentVolumeClaim:
+		if buffering.HostPath != "" {
+			return errors.New("log buffering host path requires the HostPath storage")
+		}
+		if buffering.Size != "" {
+			if _, err := resource.ParseQuantity(buffering.Size); err != nil {
+				return fmt.Errorf("invalid log buffering size: %v", buffering.Size)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported log buffering storage: %v", buffering.Storage)
+	}
+
+	if buffering.Retention != "" {
+		retention, err := time.ParseDuration(buffering.Retention)
+		if err != nil || retention <= 0 {
+			return fmt.Errorf("invalid log buffering retention: %v", buffering.Retention)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateGrafanaExternal() error {
+	if !in.GrafanaExternal() {
+		return nil
+	}
+
+	external := in.Spec.Grafana.External
+	if _, err := url.ParseRequestURI(external.URL); err != nil {
+		return fmt.Errorf("invalid external grafana url: %v", external.URL)
+	}
+	if external.TokenSecret == "" {
+		return fmt.Errorf("external grafana requires a token secret")
+	}
+	if external.DatasourceURL != "" {
+		if _, err := url.ParseRequestURI(external.DatasourceURL); err != nil {
+			return fmt.Errorf("invalid external grafana datasource url: %v", external.DatasourceURL)
+		}
+	}
+	if in.GrafanaMode() != ComponentExternal {
+		return fmt.Errorf("external grafana requires the grafana component to be External")
+	}
+	// SMTP is part of the Grafana configuration file, which is not managed for an external Grafana
+	if in.Spec.Grafana.Smtp != nil {
+		return fmt.Errorf("grafana smtp can't be configured for an external grafana")
+	}
+	if in.GrafanaImageRendererEnabled() {
+		return fmt.Errorf("the grafana image renderer can't be deployed for an external grafana")
+	}
+	if in.GrafanaLDAPEnabled() {
+		return fmt.Errorf("grafana ldap can't be configured for an external grafana")
+	}
+	return nil
+}
+
+func (in *Observability) validateGrafanaAccess() error {
+	if in.Spec.Grafana == nil {
+		return nil
+	}
+	external := in.Spec.Grafana.External != nil
+
+	organizations := map[string]bool{}
+	for _, organization := range in.Spec.Grafana.Organizations {
+		if external {
+			return errors.New("grafana organizations can't be created in an external grafana")
+		}
+		if organization.Name == "" {
+			return errors.New("grafana organizations require a name")
+		}
+		if organizations[organization.Name] {
+			return fmt.Errorf("duplicate grafana organization: %v", organization.Name)
+		}
+		organizations[organization.Name] = true
+	}
+
+	// External Grafana tokens are limited to their own organization
+	validOrganization := func(organization string) bool {
+		return organization == "" || (!external && organizations[organization])
+	}
+
+	teams := map[string]bool{}
+	for _, team := range in.Spec.Grafana.Teams {
+		if team.Name == "" {
+			return errors.New("grafana teams require a name")
+		}
+		if !validOrganization(team.Organization) {
+			return fmt.Errorf("unknown organization of grafana team %v: %v", team.Name, team.Organization)
+		}
+		key := team.Organization + "/" + team.Name
+		if teams[key] {
+			return fmt.Errorf("duplicate grafana team: %v", team.Name)
+		}
+		teams[key] = true
+	}
+
+	keys := map[string]bool{}
+	secrets := map[string]bool{}
+	for _, apiKey := range in.Spec.Grafana.APIKeys {
+		if !grafanaAPIKeyNameRegex.MatchString(apiKey.Name) {
+			return fmt.Errorf("invalid grafana api key name: %v", apiKey.Name)
+		}
+		if !validOrganization(apiKey.Organization) {
+			return fmt.Errorf("unknown organization of grafana api key %v: %v", apiKey.Name, apiKey.Organization)
+		}
+		key := apiKey.Organization + "/" + apiKey.Name
+		if keys[key] {
+			return fmt.Errorf("duplicate grafana api key: %v", apiKey.Name)
+		}
+		keys[key] = true
+
+		switch apiKey.Role {
+		case GrafanaRoleViewer, GrafanaRoleEditor, GrafanaRoleAdmin:
+		default:
+			return fmt.Errorf("invalid role of grafana api key %v, must be one of Viewer, Editor or Admin", apiKey.Name)
+		}
+		if apiKey.Secret == "" {
+			return fmt.Errorf("grafana api key %v requires a secret", apiKey.Name)
+		}
+		if secrets[apiKey.Secret] {
+			return fmt.Errorf("secret %v is used by more than one grafana api key", apiKey.Secret)
+		}
+		secrets[apiKey.Secret] = true
+		if apiKey.TTL != "" {
+			ttl, err := time.ParseDuration(apiKey.TTL)
+			if err != nil || ttl < time.Second {
+				return fmt.Errorf("invalid ttl of grafana api key %v: %v", apiKey.Name, apiKey.TTL)
+			}
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateGrafanaLDAP() error {
+	if !in.GrafanaLDAPEnabled() {
+		return nil
+	}
+
+	ldap := in.Spec.Grafana.Auth.LDAP
+	if _, port, err := net.SplitHostPort(ldap.Server); err != nil || port == "" {
+		return fmt.Errorf("invalid grafana ldap server, must be host:port: %v", ldap.Server)
+	}
+	if ldap.UseSSL && ldap.StartTLS {
+		return errors.New("grafana ldap can use either ssl or starttls")
+	}
+	if ldap.BindSecret == "" {
+		return errors.New("grafana ldap requires a bind secret")
+	}
+	if len(ldap.SearchBaseDNs) == 0 {
+		return errors.New("grafana ldap requires at least one search base dn")
+	}
+	for _, mapping := range ldap.GroupMappings {
+		if mapping.GroupDN == "" {
+			return errors.New("grafana ldap group mappings require a group dn")
+		}
+		switch mapping.Role {
+		case GrafanaRoleViewer, GrafanaRoleEditor, GrafanaRoleAdmin:
+		default:
+			return fmt.Errorf("invalid role of grafana ldap group %v, must be one of Viewer, Editor or Admin", mapping.GroupDN)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateGrafanaPersistence() error {
+	if in.Spec.Grafana == nil || in.Spec.Grafana.Persistence == nil {
+		return nil
+	}
+
+	persistence := in.Spec.Grafana.Persistence
+	if persistence.Volume != nil && persistence.Database != nil {
+		return errors.New("grafana persistence can use either a volume or a database")
+	}
+	if persistence.Volume != nil {
+		if _, err := resource.ParseQuantity(persistence.Volume.Size); err != nil {
+			return fmt.Errorf("invalid grafana volume size: %v", persistence.Volume.Size)
+		}
+	}
+	if persistence.Database != nil {
+		database := persistence.Database
+		if database.Type != GrafanaDatabasePostgres && database.Type != GrafanaDatabaseMySQL {
+			return fmt.Errorf("invalid grafana database type, must be postgres or mysql: %v", database.Type)
+		}
+		if _, port, err := net.SplitHostPort(database.Host); err != nil || port == "" {
+			return fmt.Errorf("invalid grafana database host, must be host:port: %v", database.Host)
+		}
+		if database.Name == "" || database.CredentialsSecret == "" {
+			return errors.New("grafana database requires a name and a credentials secret")
+		}
+		switch database.SSLMode {
+		case "", "disable", "require", "verify-full":
+		default:
+			return fmt.Errorf("invalid grafana database ssl mode: %v", database.SSLMode)
+		}
+		if database.SSLMode != "" && database.Type != GrafanaDatabasePostgres {
+			return errors.New("grafana database ssl mode is only supported with postgres")
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateGrafanaAnnotations() error {
+	if in.Spec.Grafana == nil || in.Spec.Grafana.Annotations == nil {
+		return nil
+	}
+	if in.GrafanaMode() != ComponentManaged && !in.GrafanaExternal() {
+		return errors.New("grafana annotations require a managed grafana or grafana.external")
+	}
+	for _, source := range in.Spec.Grafana.Annotations.Sources {
+		switch source {
+		case GrafanaAnnotationConfig, GrafanaAnnotationRollout, GrafanaAnnotationUpgrade:
+		default:
+			return fmt.Errorf("invalid grafana annotation source, must be Config, Rollout or Upgrade: %v", source)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateGrafanaSharing() error {
+	if in.Spec.Grafana == nil || in.Spec.Grafana.Sharing == nil {
+		return nil
+	}
+	sharing := in.Spec.Grafana.Sharing
+	if in.GrafanaMode() != ComponentManaged {
+		return errors.New("grafana sharing requires a managed grafana")
+	}
+	if sharing.SnapshotServer == nil {
+		return nil
+	}
+	if sharing.DisableSnapshots || sharing.DisableExternalSharing {
+		return errors.New("grafana snapshot server requires snapshots and external sharing")
+	}
+	u, err := url.ParseRequestURI(sharing.SnapshotServer.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid grafana snapshot server url: %v", sharing.SnapshotServer.Url)
+	}
+	return nil
+}
+
+func (in *Observability) validateUserWorkloadMonitoring() error {
+	if !in.UserWorkloadMonitoringEnabled() {
+		return nil
+	}
+
+	if thanosQuerier := in.Spec.UserWorkloadMonitoring.ThanosQuerierURL; thanosQuerier != "" {
+		if _, err := url.ParseRequestURI(thanosQuerier); err != nil {
+			return fmt.Errorf("invalid thanos querier url: %v", thanosQuerier)
+		}
+	}
+	if in.PrometheusMode() != ComponentExternal {
+		return fmt.Errorf("user workload monitoring requires the prometheus component to be External")
+	}
+	return nil
+}
+
+func (in *Observability) validateComponents() error {
+	if in.Spec.Components == nil {
+		return nil
+	}
+
+	components := in.Spec.Components
+	for name, mode := range map[string]ComponentMode{
+		"prometheus":     components.Prometheus,
+		"alertmanager":   components.Alertmanager,
+		"grafana":        components.Grafana,
+		"promtail":       components.Promtail,
+		"tokenRefresher": components.TokenRefresher,
+	} {
+		if mode != "" && mode != ComponentManaged && mode != ComponentExternal && mode != ComponentDisabled {
+			return fmt.Errorf("invalid mode of component %v: %v", name, mode)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateConfigValues() error {
+	for name := range in.Spec.ConfigValues {
+		if !configValueNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid config value name: %v", name)
+		}
+		if strings.HasPrefix(name, ConfigValueReservedPrefix) {
+			return fmt.Errorf("config value %v uses the reserved prefix %v", name, ConfigValueReservedPrefix)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateBackup() error {
+	backup := in.Spec.Backup
+	if backup == nil {
+		return nil
+	}
+
+	if backup.ObjectStorageSecret == "" {
+		return fmt.Errorf("backup requires an object storage secret")
+	}
+	if backup.GrafanaInterval != "" {
+		interval, err := time.ParseDuration(backup.GrafanaInterval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid grafana backup interval: %v", backup.GrafanaInterval)
+		}
+	}
+	return validateObjectStorageIdentity(backup.Identity)
+}
+
+func validateObjectStorageIdentity(identity *ObjectStorageIdentity) error {
+	if identity == nil {
+		return nil
+	}
+
+	switch identity.Provider {
+	case ObjectStorageIdentityAWS:
+		if identity.RoleARN == "" {
+			return errors.New("aws object storage identity requires a role arn")
+		}
+	case ObjectStorageIdentityGCP:
+		if identity.ServiceAccount == "" || identity.WorkloadIdentityProvider == "" {
+			return errors.New("gcp object storage identity requires a service account and a workload identity provider")
+		}
+	case ObjectStorageIdentityAzure:
+		if identity.ClientID == "" || identity.TenantID == "" {
+			return errors.New("azure object storage identity requires a client id and a tenant id")
+		}
+	default:
+		return fmt.Errorf("invalid object storage identity provider: %v", identity.Provider)
+	}
+
+	// The kubelet doesn't issue tokens for less than 10 minutes
+	if identity.ExpirationSeconds != nil && *identity.ExpirationSeconds < 600 {
+		return fmt.Errorf("invalid object storage identity token expiration: %v", *identity.ExpirationSeconds)
+	}
+	return nil
+}
+
+// Grafana time range relative to now, e.g. 7d
+var reportTimeRangeRegex = regexp.MustCompile(`^[0-9]+[smhdwMy]$`)
+
+func (in *Observability) validateReports() error {
+	names := map[string]bool{}
+	for _, report := range in.Spec.Reports {
+		if report.Name == "" {
+			return errors.New("reports require a name")
+		}
+		if names[report.Name] {
+			return fmt.Errorf("duplicate report: %v", report.Name)
+		}
+		names[report.Name] = true
+
+		if report.Dashboard == "" {
+			return fmt.Errorf("report %v requires a dashboard", report.Name)
+		}
+		if _, err := ParseSchedule(report.Schedule); err != nil {
+			return fmt.Errorf("report %v: %v", report.Name, err)
+		}
+		if report.Format != "" && report.Format != "pdf" && report.Format != "png" {
+			return fmt.Errorf("invalid format of report %v, must be one of pdf or png: %v", report.Name, report.Format)
+		}
+		if report.TimeRange != "" && !reportTimeRangeRegex.MatchString(report.TimeRange) {
+			return fmt.Errorf("invalid time range of report %v: %v", report.Name, report.TimeRange)
+		}
+		if len(report.Recipients) == 0 && report.ObjectStorageSecret == "" {
+			return fmt.Errorf("report %v requires recipients or an object storage secret", report.Name)
+		}
+		for _, recipient := range report.Recipients {
+			if _, err := mail.ParseAddress(recipient); err != nil {
+				return fmt.Errorf("invalid recipient of report %v: %v", report.Name, recipient)
+			}
+		}
+		if len(report.Recipients) > 0 && (in.Spec.Grafana == nil || in.Spec.Grafana.Smtp == nil) {
+			return fmt.Errorf("report %v requires grafana smtp to send emails", report.Name)
+		}
+		// An external Grafana is expected to have its own image renderer
+		if !in.GrafanaImageRendererEnabled() && !in.GrafanaExternal() {
+			return fmt.Errorf("report %v requires the grafana image renderer", report.Name)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateTenantQuotas() error {
+	namespaces := map[string]bool{}
+	for _, quota := range in.Spec.TenantQuotas {
+		if quota.Namespace == "" {
+			return errors.New("tenant quotas require a namespace")
+		}
+		if namespaces[quota.Namespace] {
+			return fmt.Errorf("duplicate tenant quota: %v", quota.Namespace)
+		}
+		namespaces[quota.Namespace] = true
+
+		if quota.MaxSeries < 0 || quota.MaxSamplesPerSecond < 0 || (quota.MaxSeries == 0 && quota.MaxSamplesPerSecond == 0) {
+			return fmt.Errorf("tenant quota %v requires a positive max series or max samples per second", quota.Namespace)
+		}
+		// The namespace of the CR is never suspended, the stack monitors itself through it
+		if quota.Enforce && quota.Namespace == in.Namespace {
+			return fmt.Errorf("tenant quota of the namespace of the observability stack can't be enforced")
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateConfigMerge() error {
+	if in.Spec.ConfigMerge == nil {
+		return nil
+	}
+
+	merge := in.Spec.ConfigMerge
+	for kind, strategy := range map[string]MergeStrategy{
+		"dashboards":         merge.Dashboards,
+		"rules":              merge.Rules,
+		"alertmanagerRoutes": merge.AlertmanagerRoutes,
+	} {
+		if strategy != "" && strategy != MergeFirstWins && strategy != MergeLastWins && strategy != MergeErrorOnConflict {
+			return fmt.Errorf("invalid merge strategy of %v: %v", kind, strategy)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateConfigurationSources() error {
+	names := map[string]bool{}
+	for _, source := range in.Spec.ConfigurationSources {
+		if source.Name == "" {
+			return fmt.Errorf("configuration source requires the name of its configuration secret")
+		}
+		if names[source.Name] {
+			return fmt.Errorf("duplicate configuration source %v", source.Name)
+		}
+		names[source.Name] = true
+
+		for _, kind := range source.Kinds {
+			if kind != ConfigKindDashboard && kind != ConfigKindPrometheusRule && kind != ConfigKindPodMonitor && kind != ConfigKindLokiRule &&
+				kind != ConfigKindAlertTemplate {
+				return fmt.Errorf("invalid kind of configuration source %v: %v", source.Name, kind)
+			}
+		}
+		for _, selector := range []*metav1.LabelSelector{source.Selector, source.RuleSelector} {
+			if selector == nil {
+				continue
+			}
+			_, err := metav1.LabelSelectorAsSelector(selector)
+			if err != nil {
+				return fmt.Errorf("invalid selector of configuration source %v: %v", source.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateConfigApply() error {
+	if in.Spec.ConfigApply == nil {
+		return nil
+	}
+
+	apply := in.Spec.ConfigApply
+	if apply.BatchSize < 0 || apply.Concurrency < 0 || apply.RequestsPerSecond < 0 {
+		return errors.New("config apply batch size, concurrency and requests per second can't be negative")
+	}
+	if apply.MaxDuration != "" {
+		duration, err := time.ParseDuration(apply.MaxDuration)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid config apply max duration: %v", apply.MaxDuration)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateRuleDeduplication() error {
+	if in.Spec.RuleDeduplication == nil {
+		return nil
+	}
+	switch in.Spec.RuleDeduplication.Strategy {
+	case "", RuleDeduplicationReport, RuleDeduplicationSuppress, RuleDeduplicationRelabel:
+	default:
+		return fmt.Errorf("invalid rule deduplication strategy, must be Report, Suppress or Relabel: %v", in.Spec.RuleDeduplication.Strategy)
+	}
+	if in.Spec.RuleDeduplication.Strategy == RuleDeduplicationRelabel && in.AlertmanagerMode() == ComponentDisabled {
+		return errors.New("the Relabel rule deduplication strategy requires alertmanager")
+	}
+	return nil
+}
+
+func (in *Observability) validateRulePolicy() error {
+	if in.Spec.RulePolicy == nil {
+		return nil
+	}
+
+	policy := in.Spec.RulePolicy
+	if policy.Action != "" && policy.Action != RulePolicyReject && policy.Action != RulePolicyAnnotate {
+		return fmt.Errorf("invalid rule policy action: %v", policy.Action)
+	}
+	for _, label := range policy.RequiredLabels {
+		if !metricNameRegex.MatchString(label) {
+			return fmt.Errorf("invalid required label of the rule policy: %v", label)
+		}
+	}
+
+	severities := map[string]bool{}
+	for _, severity := range policy.AllowedSeverities {
+		if severity == "" || severities[strings.ToLower(severity)] {
+			return fmt.Errorf("empty or duplicate severity in the rule policy: %v", severity)
+		}
+		severities[strings.ToLower(severity)] = true
+	}
+	for alias, severity := range policy.SeverityAliases {
+		if alias == "" || (len(severities) > 0 && !severities[strings.ToLower(severity)]) {
+			return fmt.Errorf("severity alias %v must resolve to an allowed severity: %v", alias, severity)
+		}
+	}
+
+	if policy.Action != RulePolicyAnnotate && (len(policy.DefaultLabels) > 0 || policy.DefaultRunbookUrl != "") {
+		return errors.New("default labels and runbook of the rule policy require the Annotate action")
+	}
+	for label := range policy.DefaultLabels {
+		if !metricNameRegex.MatchString(label) {
+			return fmt.Errorf("invalid default label of the rule policy: %v", label)
+		}
+	}
+	if severity, ok := policy.DefaultLabels["severity"]; ok && len(severities) > 0 && !severities[strings.ToLower(severity)] {
+		return fmt.Errorf("default severity of the rule policy is not allowed: %v", severity)
+	}
+	if policy.DefaultRunbookUrl != "" {
+		if _, err := url.ParseRequestURI(policy.DefaultRunbookUrl); err != nil {
+			return fmt.Errorf("invalid default runbook url of the rule policy: %v", policy.DefaultRunbookUrl)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateProfiles() error {
+	names := map[string]bool{}
+	for _, profile := range in.Spec.Profiles {
+		switch profile {
+		case ProfileKafka, ProfilePostgres, ProfileRedis, ProfileNginxIngress:
+		default:
+			return fmt.Errorf("unknown profile: %v", profile)
+		}
+		if names[profile] {
+			return fmt.Errorf("duplicate profile: %v", profile)
+		}
+		names[profile] = true
+	}
+	if len(in.Spec.Profiles) > 0 && in.PrometheusMode() == ComponentDisabled {
+		return errors.New("profiles require prometheus")
+	}
+	return nil
+}
+
+func (in *Observability) validateAccess() error {
+	if in.Spec.Access == nil {
+		return nil
+	}
+
+	for _, groups := range [][]string{in.Spec.Access.ViewerGroups, in.Spec.Access.EditorGroups} {
+		names := map[string]bool{}
+		for _, group := range groups {
+			if strings.TrimSpace(group) == "" {
+				return errors.New("access groups can't be empty")
+			}
+			if names[group] {
+				return fmt.Errorf("duplicate access group: %v", group)
+			}
+			names[group] = true
+		}
+	}
+	return nil
+}
+
+// Agents keep no blocks to query, evaluate no rules and send no alerts, so everything built on top
+// of the local metrics is rejected
+func (in *Observability) validatePrometheusAgentMode() error {
+	if in.Spec.SelfContained == nil || in.Spec.SelfContained.PrometheusMode == "" {
+		return nil
+	}
+
+	mode := in.Spec.SelfContained.PrometheusMode
+	if mode != PrometheusRunModeServer && mode != PrometheusRunModeAgent {
+		return fmt.Errorf("invalid prometheus mode, must be server or agent: %v", mode)
+	}
+	if mode != PrometheusRunModeAgent {
+		return nil
+	}
+
+	if in.PrometheusMode() != ComponentManaged {
+		return errors.New("prometheus agent mode requires a managed prometheus")
+	}
+	if in.ObservatoriumDisabled() {
+		return errors.New("prometheus agent mode requires observatorium to forward the metrics to")
+	}
+	if in.AlertmanagerMode() == ComponentManaged || in.GrafanaMode() == ComponentManaged {
+		return errors.New("prometheus agent mode does not support a managed alertmanager or grafana")
+	}
+	if in.Spec.SelfContained.PrometheusShards != nil && *in.Spec.SelfContained.PrometheusShards > 1 {
+		return errors.New("prometheus agent mode does not support shards")
+	}
+	if in.BackupEnabled() || in.PrometheusAdapterEnabled() || len(in.Spec.TenantQuotas) > 0 {
+		return errors.New("prometheus agent mode does not support backups, the prometheus adapter or tenant quotas")
+	}
+	return nil
+}
+
+func (in *Observability) validateExpiryMonitoring() error {
+	if in.Spec.ExpiryMonitoring == nil || in.Spec.ExpiryMonitoring.Window == "" {
+		return nil
+	}
+
+	window, err := time.ParseDuration(in.Spec.ExpiryMonitoring.Window)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid expiry monitoring window: %v", in.Spec.ExpiryMonitoring.Window)
+	}
+	return nil
+}
+
+// Verifications query Prometheus, Alertmanager and Grafana, they don't run more often than every minute
+func (in *Observability) validateStackVerification() error {
+	if in.Spec.StackVerification == nil || in.Spec.StackVerification.Interval == "" {
+		return nil
+	}
+
+	interval, err := time.ParseDuration(in.Spec.StackVerification.Interval)
+	if err != nil || interval < time.Minute {
+		return fmt.Errorf("invalid stack verification interval, expected at least 1m: %v", in.Spec.StackVerification.Interval)
+	}
+	return nil
+}
+
+func (in *Observability) validateDatasourceHealth() error {
+	if in.Spec.Grafana == nil || in.Spec.Grafana.DatasourceHealth == nil || in.Spec.Grafana.DatasourceHealth.Interval == "" {
+		return nil
+	}
+
+	interval, err := time.ParseDuration(in.Spec.Grafana.DatasourceHealth.Interval)
+	if err != nil || interval < time.Minute {
+		return fmt.Errorf("invalid datasource health check interval, expected at least 1m: %v", in.Spec.Grafana.DatasourceHealth.Interval)
+	}
+	return nil
+}
+
+// The plugin has no default image
+func (in *Observability) validateConsole() error {
+	if in.ConsolePluginEnabled() && in.Spec.Console.Plugin.Image == "" {
+		return errors.New("console plugin requires an image")
+	}
+	return nil
+}
+
+// Agents don't evaluate queries
+func (in *Observability) validateQueryLogging() error {
+	if !in.QueryLoggingEnabled() {
+		return nil
+	}
+	if in.PrometheusAgentEnabled() {
+		return errors.New("query logging is not supported in prometheus agent mode")
+	}
+
+	threshold := in.Spec.SelfContained.QueryLogging.SlowQueryThreshold
+	if threshold == "" {
+		return nil
+	}
+	duration, err := time.ParseDuration(threshold)
+	if err != nil || duration <= 0 {
+		return fmt.Errorf("invalid slow query threshold: %v", threshold)
+	}
+	return nil
+}
+
+func (in *Observability) validateUpgradeWindow() error {
+	window := in.Spec.UpgradeWindow
+	if window == nil {
+		return nil
+	}
+
+	if (window.Schedule == "") != (window.Duration == "") {
+		return errors.New("schedule and duration of the upgrade window have to be set together")
+	}
+	if window.Schedule != "" {
+		if _, err := ParseSchedule(window.Schedule); err != nil {
+			return fmt.Errorf("invalid upgrade window schedule: %v", err)
+		}
+		duration, err := time.ParseDuration(window.Duration)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid upgrade window duration: %v", window.Duration)
+		}
+	}
+	for i, window := range window.Windows {
+		_, err := ParseMuteWindows(&MuteTimeInterval{
+			Weekdays:  window.Weekdays,
+			StartTime: window.StartTime,
+			EndTime:   window.EndTime,
+			TimeZone:  window.TimeZone,
+		})
+		if err != nil {
+			return fmt.Errorf("invalid upgrade window %v: %v", i, err)
+		}
+	}
+	for _, freeze := range window.Freezes {
+		start, err := time.Parse(time.RFC3339, freeze.Start)
+		if err != nil {
+			return fmt.Errorf("invalid start of change freeze: %v", freeze.Start)
+		}
+		end, err := time.Parse(time.RFC3339, freeze.End)
+		if err != nil {
+			return fmt.Errorf("invalid end of change freeze: %v", freeze.End)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("change freeze ends before it starts: %v", freeze.Start)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateExternalSecrets() error {
+	names := map[string]bool{}
+	for _, secret := range in.Spec.ExternalSecrets {
+		if errs := validation.IsDNS1123Subdomain(secret.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid external secret name %v: %v", secret.Name, strings.Join(errs, ", "))
+		}
+		if names[secret.Name] {
+			return fmt.Errorf("duplicate external secret: %v", secret.Name)
+		}
+		names[secret.Name] = true
+
+		if secret.StoreRef.Name == "" {
+			return fmt.Errorf("external secret %v requires a store", secret.Name)
+		}
+		if kind := secret.StoreRef.Kind; kind != "" && kind != SecretStoreKind && kind != ClusterSecretStoreKind {
+			return fmt.Errorf("invalid store kind of external secret %v: %v", secret.Name, kind)
+		}
+		if secret.RefreshInterval != "" {
+			interval, err := time.ParseDuration(secret.RefreshInterval)
+			if err != nil || interval < 0 {
+				return fmt.Errorf("invalid refresh interval of external secret %v: %v", secret.Name, secret.RefreshInterval)
+			}
+		}
+
+		if len(secret.Data) == 0 {
+			return fmt.Errorf("external secret %v has no data", secret.Name)
+		}
+		keys := map[string]bool{}
+		for _, data := range secret.Data {
+			if data.RemoteKey == "" {
+				return fmt.Errorf("key %v of external secret %v requires a remote key", data.SecretKey, secret.Name)
+			}
+			if errs := validation.IsConfigMapKey(data.SecretKey); len(errs) > 0 {
+				return fmt.Errorf("invalid key %v of external secret %v: %v", data.SecretKey, secret.Name, strings.Join(errs, ", "))
+			}
+			if keys[data.SecretKey] {
+				return fmt.Errorf("duplicate key %v of external secret %v", data.SecretKey, secret.Name)
+			}
+			keys[data.SecretKey] = true
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateMuteTimeIntervals() error {
+	if in.Spec.Alerting == nil || len(in.Spec.Alerting.MuteTimeIntervals) == 0 {
+		return nil
+	}
+	// The silences are created through the oauth proxy of the managed Alertmanager
+	if in.AlertmanagerMode() != ComponentManaged {
+		return errors.New("mute time intervals require a managed alertmanager")
+	}
+
+	names := map[string]bool{}
+	for _, interval := range in.Spec.Alerting.MuteTimeIntervals {
+		if interval.Name == "" {
+			return errors.New("mute time intervals require a name")
+		}
+		if names[interval.Name] {
+			return fmt.Errorf("duplicate mute time interval: %v", interval.Name)
+		}
+		names[interval.Name] = true
+
+		// Without labels every alert would be muted, including the dead man's switch
+		if len(interval.Match) == 0 {
+			return fmt.Errorf("mute time interval %v requires labels to match", interval.Name)
+		}
+		_, err := ParseMuteWindows(&interval)
+		if err != nil {
+			return fmt.Errorf("invalid mute time interval %v: %v", interval.Name, err)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validatePrometheusAdapter() error {
+	if !in.PrometheusAdapterEnabled() {
+		return nil
+	}
+	if in.Spec.PrometheusAdapter.RulesConfigMap == "" {
+		return errors.New("prometheus adapter requires a rules config map")
+	}
+	// The adapter queries the Prometheus of the stack
+	if in.PrometheusMode() != ComponentManaged {
+		return errors.New("prometheus adapter requires a managed prometheus")
+	}
+	return nil
+}
+
+// Features of which the components have no FIPS validated default image need an image override in FIPS mode
+func (in *Observability) validateFIPSMode() error {
+	if !in.FIPSModeEnabled() {
+		return nil
+	}
+
+	if len(in.Spec.GrafanaPlugins) > 0 {
+		return errors.New("grafana plugins can't be installed in FIPS mode")
+	}
+
+	features := []struct {
+		name      string
+		component string
+		enabled   bool
+	}{
+		{"promtail", ImagePromtail, in.PromtailMode() == ComponentManaged},
+		{"token refresher", ImageTokenRefresher, in.TokenRefresherMode() == ComponentManaged && !in.ObservatoriumDisabled()},
+		{"blackbox exporter", ImageBlackboxExporter, !in.BlackboxExporterDisabled()},
+		{"tracing", ImageTempo, in.TracingEnabled()},
+		{"query proxy", ImagePromLabelProxy, in.Spec.QueryProxy != nil},
+		{"grafana image renderer", ImageGrafanaImageRenderer, in.GrafanaImageRendererEnabled()},
+	}
+	for _, feature := range features {
+		if !feature.enabled {
+			continue
+		}
+		if _, ok := in.Spec.ImageOverrides[feature.component]; !ok {
+			return fmt.Errorf("%v is not FIPS compliant, disable it or override the %v image with a FIPS validated one", feature.name, feature.component)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateNetworking() error {
+	families := in.IPFamilies()
+	if len(families) > 2 {
+		return errors.New("at most two ip families can be configured")
+	}
+	if len(families) == 2 && families[0] == families[1] {
+		return fmt.Errorf("duplicate ip family: %v", families[0])
+	}
+	for _, family := range families {
+		if family != v1.IPv4Protocol && family != v1.IPv6Protocol {
+			return fmt.Errorf("invalid ip family, must be one of IPv4 or IPv6: %v", family)
+		}
+	}
+	return nil
+}
+
+func (in *Observability) validateResources() error {
+	for component, resources := range in.Spec.Resources {
+		known := false
+		for _, c := range resourcesComponents {
+			if c == component {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown component in Resources: %v", component)
+		}
+		for name, request := range resources.Requests {
+			limit, ok := resources.Limits[name]
+			if ok && request.Cmp(limit) > 0 {
+				return fmt.Errorf("%v request of component %v exceeds its limit", name, component)
+			}
+		}
+	}
 	return nil
 }
 
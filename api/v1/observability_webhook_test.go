@@ -1,6 +1,8 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"testing"
@@ -16,6 +18,7 @@ func TestObservability_ValidateUpdate(t *testing.T) {
 	type args struct {
 		old runtime.Object
 	}
+	var zero int32 = 0
 	tests := []struct {
 		name    string
 		fields  fields
@@ -186,6 +189,1616 @@ func TestObservability_ValidateUpdate(t *testing.T) {
 			}},
 			wantErr: false,
 		},
+		{
+			name: "PrometheusStorageRequest - error if decreased",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{PrometheusStorageRequest: "100Gi"},
+				},
+			},
+			args: args{old: &Observability{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{PrometheusStorageRequest: "250Gi"},
+				},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "PrometheusStorageRequest - no error if increased",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{PrometheusStorageRequest: "300Gi"},
+				},
+			},
+			args: args{old: &Observability{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{PrometheusStorageRequest: "250Gi"},
+				},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "PrometheusScrapeInterval - error if not a valid duration",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{PrometheusScrapeInterval: "30 seconds"},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "PrometheusScrapeInterval - no error if a valid duration",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{PrometheusScrapeInterval: "30s"},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "ImageOverrides - no error for images referenced by digest",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ImageOverrides: map[string]string{
+						ImagePromtail: "mirror.example.com:5000/integreatly/promtail@sha256:9f1b4e3a6fa2b3a7d8c8a1f5e5d2b7c6a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "ImageOverrides - error for unknown components",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ImageOverrides: map[string]string{
+						"loki": "grafana/loki:2.2.0",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Resources - error if a request exceeds its limit",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Resources: map[string]corev1.ResourceRequirements{
+						ResourcesPromtail: {
+							Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+							Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "GrafanaPlugins - error if the checksum is not a sha256 digest",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					GrafanaPlugins: []GrafanaPlugin{
+						{Name: "grafana-piechart-panel", Version: "1.6.1", Checksum: "md5:abc"},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Alerting - error if an inhibit rule has no target matchers",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						InhibitRules: []InhibitRule{
+							{SourceMatch: map[string]string{"alertname": "KafkaBrokerDown"}},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "AlertmanagerCluster - error if a peer has no port",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						AlertManagerExternalURL: "https://alertmanager.example.com",
+						AlertManagerPeers:       []string{"alertmanager.cluster-b.example.com"},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Grafana - error if an email contact point is configured without smtp",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						ContactPoints: []GrafanaContactPoint{
+							{Name: "oncall", Type: GrafanaContactPointEmail, Addresses: []string{"oncall@example.com"}},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Logs - error if a host path has globs in the directory",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						Logs: &PromtailLogs{
+							HostPaths: []string{"/var/log/*/audit.log"},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Logs - error if a histogram regex has no value group",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Metrics: []LogMetric{
+							{
+								Name:    "request_duration_seconds",
+								Type:    LogMetricHistogram,
+								Regex:   `took=[0-9.]+`,
+								Buckets: []string{"0.1", "1"},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Components - error if the mode is unknown",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Components: &Components{
+						Grafana: "Unmanaged",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Grafana - error if the external grafana is managed",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						External: &GrafanaExternal{
+							URL:         "https://grafana.example.com",
+							TokenSecret: "grafana-token",
+						},
+					},
+					Components: &Components{
+						Grafana: ComponentManaged,
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "UserWorkloadMonitoring - error if prometheus is managed",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					UserWorkloadMonitoring: &UserWorkloadMonitoring{},
+					Components: &Components{
+						Prometheus: ComponentManaged,
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "PrometheusShards - error if not positive",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						PrometheusShards: &zero,
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "ConfigValues - no error for valid names",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ConfigValues: map[string]string{
+						"ENVIRONMENT": "staging",
+						"region_1":    "eu-west-1",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "ConfigValues - error if reserved prefix",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ConfigValues: map[string]string{
+						"OBSERVABILITY_CLUSTER_ID": "test",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "ConfigValues - error if invalid name",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ConfigValues: map[string]string{
+						"my-value": "test",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Backup - error if no object storage secret",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Backup: &Backup{
+						GrafanaInterval: "12h",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Backup - error if invalid grafana interval",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Backup: &Backup{
+						ObjectStorageSecret: "backup-bucket",
+						GrafanaInterval:     "daily",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Backup - no error if aws identity",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Backup: &Backup{
+						ObjectStorageSecret: "backup-bucket",
+						Identity: &ObjectStorageIdentity{
+							Provider: ObjectStorageIdentityAWS,
+							RoleARN:  "arn:aws:iam::123456789012:role/observability-backup",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "Backup - error if gcp identity without workload identity provider",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Backup: &Backup{
+						ObjectStorageSecret: "backup-bucket",
+						Identity: &ObjectStorageIdentity{
+							Provider:       ObjectStorageIdentityGCP,
+							ServiceAccount: "thanos@project.iam.gserviceaccount.com",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "AlertForwarder - valid destinations",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						Forwarder: &AlertForwarder{
+							Destinations: []AlertForwarderDestination{
+								{
+									Name:      "chat",
+									Type:      AlertForwarderTypeGoogleChat,
+									URLSecret: "chat-webhook",
+									Template:  "{{ range .Alerts }}{{ .Labels.alertname | toUpper }}{{ end }}",
+								},
+								{
+									Name: "events",
+									Type: AlertForwarderTypeEventBridge,
+									EventBridge: &AlertForwarderEventBridge{
+										Region:            "eu-west-1",
+										CredentialsSecret: "eventbridge-credentials",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "AlertForwarder - error if eventbridge without region",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						Forwarder: &AlertForwarder{
+							Destinations: []AlertForwarderDestination{
+								{
+									Name: "events",
+									Type: AlertForwarderTypeEventBridge,
+									EventBridge: &AlertForwarderEventBridge{
+										CredentialsSecret: "eventbridge-credentials",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "AlertForwarder - error if invalid template",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						Forwarder: &AlertForwarder{
+							Destinations: []AlertForwarderDestination{
+								{
+									Name:      "hook",
+									Type:      AlertForwarderTypeHTTP,
+									URLSecret: "hook-url",
+									Template:  "{{ .Alerts | unknown }}",
+								},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "FIPSMode - error if promtail has no FIPS image",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					FIPSMode: true,
+					Components: &Components{
+						TokenRefresher: ComponentDisabled,
+					},
+					ImageOverrides: map[string]string{
+						ImageBlackboxExporter: "registry.example.com/blackbox-exporter:fips",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "FIPSMode - no error if components are disabled or overridden",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					FIPSMode: true,
+					Components: &Components{
+						Promtail:       ComponentDisabled,
+						TokenRefresher: ComponentDisabled,
+					},
+					ImageOverrides: map[string]string{
+						ImageBlackboxExporter: "registry.example.com/blackbox-exporter:fips",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "FIPSMode - error if grafana plugins",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					FIPSMode: true,
+					GrafanaPlugins: []GrafanaPlugin{
+						{Name: "grafana-piechart-panel", Version: "1.6.1"},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "FIPSMode - error if grafana image renderer has no FIPS image",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					FIPSMode: true,
+					Components: &Components{
+						Promtail:       ComponentDisabled,
+						TokenRefresher: ComponentDisabled,
+					},
+					ImageOverrides: map[string]string{
+						ImageBlackboxExporter: "registry.example.com/blackbox-exporter:fips",
+					},
+					Grafana: &Grafana{
+						ImageRenderer: &GrafanaImageRenderer{Enabled: true},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Grafana - error if image renderer with external grafana",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Components: &Components{
+						Grafana: ComponentExternal,
+					},
+					Grafana: &Grafana{
+						External: &GrafanaExternal{
+							URL:         "https://grafana.example.com",
+							TokenSecret: "grafana-token",
+						},
+						ImageRenderer: &GrafanaImageRenderer{Enabled: true},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Reports - no error if valid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						Smtp: &GrafanaSmtp{
+							Host:        "smtp.example.com:587",
+							FromAddress: "grafana@example.com",
+						},
+						ImageRenderer: &GrafanaImageRenderer{Enabled: true},
+					},
+					Reports: []Report{
+						{
+							Name:       "capacity",
+							Dashboard:  "capacity-planning",
+							Schedule:   "0 8 * * 1",
+							TimeRange:  "7d",
+							Recipients: []string{"management@example.com"},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "Reports - error if invalid schedule",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						ImageRenderer: &GrafanaImageRenderer{Enabled: true},
+					},
+					Reports: []Report{
+						{
+							Name:                "capacity",
+							Dashboard:           "capacity-planning",
+							Schedule:            "0 25 * * *",
+							ObjectStorageSecret: "reports-bucket",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Reports - error if recipients without smtp",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						ImageRenderer: &GrafanaImageRenderer{Enabled: true},
+					},
+					Reports: []Report{
+						{
+							Name:       "capacity",
+							Dashboard:  "capacity-planning",
+							Schedule:   "0 8 * * 1",
+							Recipients: []string{"management@example.com"},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Reports - error if image renderer disabled",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Reports: []Report{
+						{
+							Name:                "capacity",
+							Dashboard:           "capacity-planning",
+							Schedule:            "0 8 * * 1",
+							ObjectStorageSecret: "reports-bucket",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "PrometheusAdapter - error if no rules config map",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					PrometheusAdapter: &PrometheusAdapter{},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "PrometheusAdapter - error if prometheus is external",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Components: &Components{
+						Prometheus: ComponentExternal,
+					},
+					PrometheusAdapter: &PrometheusAdapter{
+						RulesConfigMap: "adapter-rules",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "GrafanaLDAP - error if server has no port",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						Auth: &GrafanaAuth{
+							LDAP: &GrafanaLDAP{
+								Server:        "ldap.example.com",
+								BindSecret:    "ldap-bind",
+								SearchBaseDNs: []string{"dc=example,dc=com"},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "GrafanaLDAP - error if group mapping has an invalid role",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						Auth: &GrafanaAuth{
+							LDAP: &GrafanaLDAP{
+								Server:        "ldap.example.com:636",
+								UseSSL:        true,
+								BindSecret:    "ldap-bind",
+								SearchBaseDNs: []string{"dc=example,dc=com"},
+								GroupMappings: []GrafanaLDAPGroupMapping{
+									{
+										GroupDN: "cn=admins,dc=example,dc=com",
+										Role:    "Owner",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Logs - no error if a promtail client has a tenant and token",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Clients: []PromtailClient{
+							{
+								Name:        "local-loki",
+								URL:         "http://loki.loki.svc:3100/loki/api/v1/push",
+								Tenant:      "kafka",
+								TokenSecret: "loki-token",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "Logs - error if a promtail client without https has a ca",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Clients: []PromtailClient{
+							{
+								Name:     "local-loki",
+								URL:      "http://loki.loki.svc:3100/loki/api/v1/push",
+								CASecret: "loki-ca",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "TokenRefresher - no error if autoscaling with backoff",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					TokenRefresher: &TokenRefresher{
+						Autoscaling: &TokenRefresherAutoscaling{
+							MaxReplicas: 3,
+						},
+						RequestTimeout: "30s",
+						Backoff: &TokenRefresherBackoff{
+							MinBackoff: "1s",
+							MaxBackoff: "5m",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "TokenRefresher - error if min backoff exceeds max backoff",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					TokenRefresher: &TokenRefresher{
+						Backoff: &TokenRefresherBackoff{
+							MinBackoff: "10m",
+							MaxBackoff: "5m",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "TenantQuotas - no error if enforced quota",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					TenantQuotas: []TenantQuota{
+						{
+							Namespace: "team-a",
+							MaxSeries: 100000,
+							Enforce:   true,
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "TenantQuotas - error if duplicate namespace",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					TenantQuotas: []TenantQuota{
+						{
+							Namespace: "team-a",
+							MaxSeries: 100000,
+						},
+						{
+							Namespace:           "team-a",
+							MaxSamplesPerSecond: 5000,
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "ConfigMerge - no error if valid strategies",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ConfigMerge: &ConfigMerge{
+						Dashboards: MergeLastWins,
+						Rules:      MergeErrorOnConflict,
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "ConfigMerge - error if invalid strategy",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ConfigMerge: &ConfigMerge{
+						AlertmanagerRoutes: "Merge",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "MuteTimeIntervals - no error if business hours window",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						MuteTimeIntervals: []MuteTimeInterval{
+							{
+								Name:      "outside-business-hours",
+								Match:     map[string]string{"severity": "warning"},
+								Weekdays:  []string{"monday:friday"},
+								StartTime: "18:00",
+								EndTime:   "08:00",
+								TimeZone:  "Europe/Berlin",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "MuteTimeIntervals - error if no labels to match",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						MuteTimeIntervals: []MuteTimeInterval{
+							{
+								Name:     "weekend",
+								Weekdays: []string{"saturday:sunday"},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "MuteTimeIntervals - error if invalid time zone",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						MuteTimeIntervals: []MuteTimeInterval{
+							{
+								Name:      "nights",
+								Match:     map[string]string{"severity": "warning"},
+								StartTime: "20:00",
+								EndTime:   "06:00",
+								TimeZone:  "Europe/Nowhere",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "GrafanaPersistence - no error if postgres database",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						Persistence: &GrafanaPersistence{
+							Database: &GrafanaDatabase{
+								Type:              GrafanaDatabasePostgres,
+								Host:              "grafana-db:5432",
+								Name:              "grafana",
+								CredentialsSecret: "grafana-db-credentials",
+								SSLMode:           "require",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "GrafanaPersistence - error if volume and database",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						Persistence: &GrafanaPersistence{
+							Volume: &GrafanaVolume{
+								Size: "1Gi",
+							},
+							Database: &GrafanaDatabase{
+								Type:              GrafanaDatabaseMySQL,
+								Host:              "grafana-db:3306",
+								Name:              "grafana",
+								CredentialsSecret: "grafana-db-credentials",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "PrometheusMode - no error if agent",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						PrometheusMode: PrometheusRunModeAgent,
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "PrometheusMode - error if agent with managed grafana",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						PrometheusMode: PrometheusRunModeAgent,
+					},
+					Components: &Components{
+						Grafana: ComponentManaged,
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "ExpiryMonitoring - error if window is invalid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ExpiryMonitoring: &ExpiryMonitoring{
+						Window: "14d",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "StackVerification - error if interval is too short",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					StackVerification: &StackVerificationSpec{
+						Interval: "30s",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "ExternalSecrets - error if store kind is invalid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ExternalSecrets: []ExternalSecret{
+						{
+							Name:     "pagerduty",
+							StoreRef: ExternalSecretStoreRef{Name: "vault", Kind: "VaultStore"},
+							Data:     []ExternalSecretData{{SecretKey: "PAGERDUTY_KEY", RemoteKey: "observability/pagerduty"}},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "ExternalSecrets - error if key is duplicated",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ExternalSecrets: []ExternalSecret{
+						{
+							Name:     "observatorium",
+							StoreRef: ExternalSecretStoreRef{Name: "vault", Kind: ClusterSecretStoreKind},
+							Data: []ExternalSecretData{
+								{SecretKey: "client-id", RemoteKey: "observability/observatorium", Property: "id"},
+								{SecretKey: "client-id", RemoteKey: "observability/observatorium", Property: "secret"},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "QueryLogging - error in agent mode",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						PrometheusMode: PrometheusRunModeAgent,
+						QueryLogging:   &PrometheusQueryLogging{},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "UpgradeWindow - error if schedule has no duration",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					UpgradeWindow: &UpgradeWindow{
+						Schedule: "0 2 * * 6",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "UpgradeWindow - error if freeze ends before it starts",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					UpgradeWindow: &UpgradeWindow{
+						Windows: []UpgradeWindowRange{
+							{Weekdays: []string{"saturday:sunday"}, TimeZone: "Europe/Berlin"},
+						},
+						Freezes: []ChangeFreeze{
+							{Start: "2026-12-31T00:00:00Z", End: "2026-12-20T00:00:00Z"},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "AlertTicketing - error if jira has no project",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						Ticketing: &AlertTicketing{
+							Type:              AlertTicketingTypeJira,
+							URL:               "https://example.atlassian.net",
+							CredentialsSecret: "jira",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "AlertTicketing - error if field template is invalid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						Ticketing: &AlertTicketing{
+							Type:              AlertTicketingTypeServiceNow,
+							URL:               "https://example.service-now.com",
+							CredentialsSecret: "servicenow",
+							Fields: map[string]string{
+								"short_description": "{{ .Labels.alertname",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "DatasourceHealth - error if interval is too short",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						DatasourceHealth: &GrafanaDatasourceHealth{
+							Interval: "10s",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "ConfigurationSources - error if kind is invalid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ConfigurationSources: []ConfigurationSource{
+						{
+							Name:  "shared-repository",
+							Kinds: []ConfigResourceKind{ConfigKindPrometheusRule, "ServiceMonitor"},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "ConfigurationSources - error if source is duplicated",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ConfigurationSources: []ConfigurationSource{
+						{
+							Name: "shared-repository",
+						},
+						{
+							Name: "shared-repository",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Console - error if plugin has no image",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Console: &Console{
+						Plugin: &ConsolePlugin{},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "ConfigApply - error if max duration is invalid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ConfigApply: &ConfigApply{
+						BatchSize:   100,
+						MaxDuration: "-1m",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "LogRules - error if there is neither a ruler nor an observatorium tenant",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Rules: &LogRules{
+							ConfigMapSelector: &v12.LabelSelector{
+								MatchLabels: map[string]string{"loki-rules": "true"},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "LogRules - no error if the ruler has an https url",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Rules: &LogRules{
+							Ruler: &LokiRuler{
+								URL:                "https://loki.loki.svc:3100",
+								Tenant:             "kafka",
+								InsecureSkipVerify: true,
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "RulePolicy - error if an alias resolves to a severity that is not allowed",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					RulePolicy: &RulePolicy{
+						RequiredLabels:    []string{"severity", "team"},
+						AllowedSeverities: []string{"critical", "warning"},
+						SeverityAliases:   map[string]string{"crit": "critical", "page": "pager"},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "RulePolicy - error if defaults are set without the Annotate action",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					RulePolicy: &RulePolicy{
+						RequireRunbookUrl: true,
+						DefaultRunbookUrl: "https://runbooks.example.com/default",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "RulePolicy - no error if the defaults conform to the policy",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					RulePolicy: &RulePolicy{
+						RequiredLabels:    []string{"severity", "team"},
+						AllowedSeverities: []string{"critical", "warning", "info"},
+						SeverityAliases:   map[string]string{"warn": "warning"},
+						RequireRunbookUrl: true,
+						Action:            RulePolicyAnnotate,
+						DefaultLabels:     map[string]string{"severity": "Warning", "team": "observability"},
+						DefaultRunbookUrl: "https://runbooks.example.com/default",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "Profiles - error if a profile is unknown",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Profiles: []string{ProfileKafka, "mysql"},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Profiles - no error if the profiles are known",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Profiles: []string{ProfileKafka, ProfilePostgres, ProfileRedis, ProfileNginxIngress},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "AlertHistory - error if the size is invalid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						History: &AlertHistory{
+							RetentionDays: 30,
+							Size:          "five gigs",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "AlertHistory - no error with a retention and size",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						History: &AlertHistory{
+							RetentionDays: 30,
+							Size:          "5Gi",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "LogBuffering - no error with a claim and retention",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Buffering: &LogBuffering{
+							Storage:   LogBufferingPersistentVolumeClaim,
+							Size:      "5Gi",
+							Retention: "4h",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "LogBuffering - error if a host path has a size",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Buffering: &LogBuffering{
+							HostPath: "/var/lib/promtail",
+							Size:     "5Gi",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "LogBuffering - error if the retention is invalid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Buffering: &LogBuffering{
+							Retention: "a day",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "Access - no error with viewer and editor groups",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Access: &Access{
+						ViewerGroups: []string{"developers", "support"},
+						EditorGroups: []string{"platform"},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "Access - error if a group is listed twice",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Access: &Access{
+						ViewerGroups: []string{"developers", "developers"},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "AlertTemplates - error if the config map selector is invalid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Alerting: &Alerting{
+						Templates: &AlertTemplates{
+							ConfigMapSelector: &v12.LabelSelector{
+								MatchExpressions: []v12.LabelSelectorRequirement{
+									{Key: "templates", Operator: "Matches"},
+								},
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "AlertTemplates - no error if a source syncs templates",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ConfigurationSources: []ConfigurationSource{
+						{
+							Name:  "team-config",
+							Kinds: []ConfigResourceKind{ConfigKindAlertTemplate},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "GrafanaSharing - error if the snapshot server is set with external sharing disabled",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						Sharing: &GrafanaSharing{
+							DisableExternalSharing: true,
+							SnapshotServer: &GrafanaSnapshotServer{
+								Url: "https://snapshots.example.com",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "GrafanaSharing - no error if snapshots and public dashboards are disabled",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						Sharing: &GrafanaSharing{
+							DisableSnapshots:        true,
+							DisablePublicDashboards: true,
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "RuleDeduplication - error if the strategy is unknown",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					RuleDeduplication: &RuleDeduplication{
+						Strategy: "Drop",
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "RuleDeduplication - no error if duplicates are relabeled",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					RuleDeduplication: &RuleDeduplication{
+						Strategy:           RuleDeduplicationRelabel,
+						MatchAlertNameOnly: true,
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "GrafanaAnnotations - error if source is invalid",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						Annotations: &GrafanaAnnotations{
+							Sources: []GrafanaAnnotationSource{GrafanaAnnotationConfig, "Deploy"},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "LogTenants - error if tenant has no namespaces",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Tenants: []LogTenant{
+							{
+								Tenant: "team-a",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "LogTenants - no error if tenants match namespaces",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Logs: &Logs{
+						Tenants: []LogTenant{
+							{
+								Tenant:     "team-a",
+								Namespaces: []string{"team-a"},
+							},
+							{
+								Tenant: "team-b",
+								NamespaceSelector: &v12.LabelSelector{
+									MatchLabels: map[string]string{"team": "b"},
+								},
+							},
+						},
+						OnlyTenantNamespaces: true,
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "Networking - no error if dual-stack",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Networking: &Networking{
+						IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "Networking - error if duplicate ip family",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Networking: &Networking{
+						IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv4Protocol},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "GrafanaAccess - error if api key of unknown organization",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						APIKeys: []GrafanaAPIKey{
+							{
+								Name:         "dashboard-ci",
+								Organization: "team-a",
+								Role:         GrafanaRoleEditor,
+								Secret:       "grafana-dashboard-ci",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "GrafanaAccess - error if organizations with external grafana",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Components: &Components{
+						Grafana: ComponentExternal,
+					},
+					Grafana: &Grafana{
+						External: &GrafanaExternal{
+							URL:         "https://grafana.example.com",
+							TokenSecret: "grafana-token",
+						},
+						Organizations: []GrafanaOrganization{
+							{Name: "team-a"},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "GrafanaAccess - no error if team and api key in organization",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Grafana: &Grafana{
+						Organizations: []GrafanaOrganization{
+							{Name: "team-a"},
+						},
+						Teams: []GrafanaTeam{
+							{Name: "sre", Organization: "team-a", Members: []string{"alice@example.com"}},
+						},
+						APIKeys: []GrafanaAPIKey{
+							{
+								Name:         "dashboard-ci",
+								Organization: "team-a",
+								Role:         GrafanaRoleEditor,
+								Secret:       "grafana-dashboard-ci",
+								TTL:          "720h",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "UIAccess - error if ingress without hosts",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						UIAccess: &UIAccess{
+							Type:      UIAccessIngress,
+							TLSSecret: "ui-tls",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "UIAccess - no error if ingress with hosts and certificate",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						UIAccess: &UIAccess{
+							Type:             UIAccessIngress,
+							PrometheusHost:   "prometheus.example.com",
+							AlertmanagerHost: "alertmanager.example.com",
+							GrafanaHost:      "grafana.example.com",
+							TLSSecret:        "ui-tls",
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
+		{
+			name: "UIAccess - error if oidc without credentials",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						UIAccess: &UIAccess{
+							OIDC: &UIAccessOIDC{IssuerURL: "https://sso.example.com/auth/realms/sre"},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "UIAccess - error if oidc with users",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						UIAccess: &UIAccess{
+							Users: []string{"user@example.com"},
+							OIDC: &UIAccessOIDC{
+								IssuerURL:         "https://sso.example.com/auth/realms/sre",
+								CredentialsSecret: "ui-oidc",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: true,
+		},
+		{
+			name: "UIAccess - no error if oidc with groups",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						UIAccess: &UIAccess{
+							Groups: []string{"sre"},
+							OIDC: &UIAccessOIDC{
+								IssuerURL:         "https://sso.example.com/auth/realms/sre",
+								CredentialsSecret: "ui-oidc",
+							},
+						},
+					},
+				},
+			},
+			args:    args{old: &Observability{}},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,103 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Only the ObservabilityDefaults of this name are read by the operator
+const ObservabilityDefaultsName = "cluster"
+
+// ObservabilityDefaultsSpec defines the values inherited by all Observability CRs of the cluster that
+// don't set them
+type ObservabilityDefaultsSpec struct {
+	// Images keyed by component, merged with the image overrides of the CRs
+	ImageOverrides map[string]string `json:"imageOverrides,omitempty"`
+	// Resource requirements keyed by component, merged with the resources of the CRs
+	Resources map[string]v1.ResourceRequirements `json:"resources,omitempty"`
+	// Storage class of the Prometheus volume
+	PrometheusStorageClass *string `json:"prometheusStorageClass,omitempty"`
+	// Retention of the Prometheus TSDB, e.g. 45d
+	// +kubebuilder:validation:Pattern=`^[0-9]+((ms)|y|w|d|h|m|s)$`
+	Retention string `json:"retention,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// ObservabilityDefaults is the Schema for the observabilitydefaults API
+type ObservabilityDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ObservabilityDefaultsSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ObservabilityDefaultsList contains a list of ObservabilityDefaults
+type ObservabilityDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ObservabilityDefaults `json:"items"`
+}
+
+// ApplyDefaults sets the fields of the CR that are not set to the cluster defaults. Only the copy
+// used by the stages is changed, the CR itself must not be updated with the result.
+func (in *Observability) ApplyDefaults(defaults *ObservabilityDefaultsSpec) {
+	for component, image := range defaults.ImageOverrides {
+		if _, ok := in.Spec.ImageOverrides[component]; ok {
+			continue
+		}
+		if in.Spec.ImageOverrides == nil {
+			in.Spec.ImageOverrides = map[string]string{}
+		}
+		in.Spec.ImageOverrides[component] = image
+	}
+
+	for component, resources := range defaults.Resources {
+		if _, ok := in.Spec.Resources[component]; ok {
+			continue
+		}
+		if in.Spec.Resources == nil {
+			in.Spec.Resources = map[string]v1.ResourceRequirements{}
+		}
+		in.Spec.Resources[component] = *resources.DeepCopy()
+	}
+
+	// The storage class can also be set in the storage spec of the CR
+	hasStorageClass := in.Spec.SelfContained != nil && in.Spec.SelfContained.PrometheusStorageClass != nil ||
+		in.Spec.Storage != nil && in.Spec.Storage.PrometheusStorageSpec != nil &&
+			in.Spec.Storage.PrometheusStorageSpec.VolumeClaimTemplate.Spec.StorageClassName != nil
+	if defaults.PrometheusStorageClass != nil && !hasStorageClass {
+		if in.Spec.SelfContained == nil {
+			in.Spec.SelfContained = &SelfContained{}
+		}
+		storageClass := *defaults.PrometheusStorageClass
+		in.Spec.SelfContained.PrometheusStorageClass = &storageClass
+	}
+
+	if in.Spec.Retention == "" {
+		in.Spec.Retention = defaults.Retention
+	}
+}
+
+func init() {
+	SchemeBuilder.Register(&ObservabilityDefaults{}, &ObservabilityDefaultsList{})
+}
@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a cron expression with the five fields minute, hour, day of month, month and day of
+// week. Fields are *, values, ranges or steps and lists of them, e.g. 0 8 * * 1-5 or */15 * * * *.
+// Times are in UTC.
+// +kubebuilder:object:generate=false
+type Schedule struct {
+	minutes, hours, days, months, weekdays map[int]bool
+	// Like cron, a day matches either the day of month or the day of week if both are restricted
+	anyDay, anyWeekday bool
+}
+
+var scheduleFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	// 7 is Sunday as well
+	{"day of week", 0, 7},
+}
+
+func ParseSchedule(spec string) (*Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(scheduleFields) {
+		return nil, fmt.Errorf("invalid schedule %v: expected %v fields", spec, len(scheduleFields))
+	}
+
+	var values []map[int]bool
+	for i, field := range fields {
+		value, err := parseScheduleField(field, scheduleFields[i].min, scheduleFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v in schedule %v: %v", scheduleFields[i].name, spec, err)
+		}
+		values = append(values, value)
+	}
+	if values[4][7] {
+		values[4][0] = true
+	}
+
+	return &Schedule{
+		minutes:    values[0],
+		hours:      values[1],
+		days:       values[2],
+		months:     values[3],
+		weekdays:   values[4],
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+func parseScheduleField(field string, min int, max int) (map[int]bool, error) {
+	result := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		i := strings.Index(part, "/")
+		if i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step: %v", part)
+			}
+			part = part[:i]
+		}
+
+		from, to := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			from, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value: %v", part)
+			}
+			// A step without a range starts at the value, e.g. 5/15
+			to = from
+			if i >= 0 {
+				to = max
+			}
+			if len(bounds) == 2 {
+				to, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid value: %v", part)
+				}
+			}
+		}
+		if from < min || to > max || from > to {
+			return nil, fmt.Errorf("out of range: %v", part)
+		}
+
+		for value := from; value <= to; value += step {
+			result[value] = true
+		}
+	}
+	return result, nil
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	day := s.days[t.Day()]
+	weekday := s.weekdays[int(t.Weekday())]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// Next returns the first time after t that matches the schedule, or the zero time if there is none
+// within the next five years, e.g. for February 30
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(5, 0, 0)
+	for t.Before(end) {
+		switch {
+		case !s.months[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !s.hours[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+		case !s.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		from     time.Time
+		want     time.Time
+	}{
+		{
+			name:     "every minute",
+			schedule: "* * * * *",
+			from:     time.Date(2021, 8, 4, 10, 7, 30, 0, time.UTC),
+			want:     time.Date(2021, 8, 4, 10, 8, 0, 0, time.UTC),
+		},
+		{
+			name:     "step",
+			schedule: "*/15 * * * *",
+			from:     time.Date(2021, 8, 4, 10, 7, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 4, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:     "step is strictly after an exact match",
+			schedule: "*/15 * * * *",
+			from:     time.Date(2021, 8, 4, 10, 15, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 4, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "step starting at a value",
+			schedule: "5/20 * * * *",
+			from:     time.Date(2021, 8, 4, 10, 26, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 4, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "step of a range",
+			schedule: "0 8-18/4 * * *",
+			from:     time.Date(2021, 8, 4, 17, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 5, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day rollover",
+			schedule: "30 6 * * *",
+			from:     time.Date(2021, 8, 4, 7, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 5, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "month rollover",
+			schedule: "0 0 1 * *",
+			from:     time.Date(2021, 1, 31, 12, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "year rollover",
+			schedule: "30 23 * * *",
+			from:     time.Date(2021, 12, 31, 23, 45, 0, 0, time.UTC),
+			want:     time.Date(2022, 1, 1, 23, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "months without the day are skipped",
+			schedule: "0 0 31 * *",
+			from:     time.Date(2021, 4, 15, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap day",
+			schedule: "0 0 29 2 *",
+			from:     time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day that never exists",
+			schedule: "0 0 30 2 *",
+			from:     time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:     time.Time{},
+		},
+		{
+			name:     "day of week only",
+			schedule: "0 8 * * 1-5",
+			from:     time.Date(2021, 8, 7, 12, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 9, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "sunday as 7",
+			schedule: "0 0 * * 7",
+			from:     time.Date(2021, 8, 7, 12, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week, the day of month matches first",
+			schedule: "0 8 10 * 5",
+			from:     time.Date(2021, 8, 7, 12, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 10, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week, the day of week matches first",
+			schedule: "0 8 10 * 5",
+			from:     time.Date(2021, 8, 10, 9, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 13, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month with any day of week",
+			schedule: "0 8 10 * *",
+			from:     time.Date(2021, 8, 10, 9, 0, 0, 0, time.UTC),
+			want:     time.Date(2021, 9, 10, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "lists",
+			schedule: "0,30 9,17 * * *",
+			from:     time.Date(2021, 8, 4, 9, 30, 0, 0, time.UTC),
+			want:     time.Date(2021, 8, 4, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "times in other zones are matched in UTC",
+			schedule: "0 2 * * *",
+			from:     time.Date(2021, 8, 7, 3, 30, 0, 0, berlin),
+			want:     time.Date(2021, 8, 7, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of week is matched in UTC",
+			schedule: "0 23 * * 5",
+			from:     time.Date(2021, 8, 7, 0, 30, 0, 0, berlin),
+			want:     time.Date(2021, 8, 6, 23, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.schedule)
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		wantErr  bool
+	}{
+		{name: "weekday names", schedule: "*/5 8-18 1,15 * mon-fri", wantErr: true},
+		{name: "ranges and steps", schedule: "*/5 8-18 1,15 * 1-5", wantErr: false},
+		{name: "too few fields", schedule: "0 8 * *", wantErr: true},
+		{name: "minute out of range", schedule: "60 * * * *", wantErr: true},
+		{name: "day of month out of range", schedule: "0 0 0 * *", wantErr: true},
+		{name: "reversed range", schedule: "0 18-8 * * *", wantErr: true},
+		{name: "zero step", schedule: "*/0 * * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchedule(tt.schedule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
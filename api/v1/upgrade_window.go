@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"fmt"
+	"time"
+)
+
+// Returns the freeze that contains t, if any
+func (in *UpgradeWindow) activeFreeze(t time.Time) (*ChangeFreeze, time.Time) {
+	for i, freeze := range in.Freezes {
+		start, err := time.Parse(time.RFC3339, freeze.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, freeze.End)
+		if err != nil {
+			continue
+		}
+		if !t.Before(start) && t.Before(end) {
+			return &in.Freezes[i], end
+		}
+	}
+	return nil, time.Time{}
+}
+
+// Returns whether t is in a window of the schedule or of the recurring windows, and the start of
+// the next window if it is not. Invalid windows are rejected by the webhook and never open
+func (in *UpgradeWindow) windowOpen(t time.Time) (bool, time.Time) {
+	if in.Schedule == "" && len(in.Windows) == 0 {
+		return true, time.Time{}
+	}
+
+	var next time.Time
+	earliest := func(start time.Time) {
+		if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+
+	if in.Schedule != "" {
+		schedule, err := ParseSchedule(in.Schedule)
+		duration, durationErr := time.ParseDuration(in.Duration)
+		if err == nil && durationErr == nil {
+			// The window started within the last duration if the schedule matched since then
+			start := schedule.Next(t.Add(-duration))
+			if !start.IsZero() && !start.After(t) {
+				return true, time.Time{}
+			}
+			earliest(schedule.Next(t))
+		}
+	}
+
+	for _, window := range in.Windows {
+		windows, err := ParseMuteWindows(&MuteTimeInterval{
+			Weekdays:  window.Weekdays,
+			StartTime: window.StartTime,
+			EndTime:   window.EndTime,
+			TimeZone:  window.TimeZone,
+		})
+		if err != nil {
+			continue
+		}
+		start, end := windows.Next(t)
+		if !start.After(t) && end.After(t) {
+			return true, time.Time{}
+		}
+		earliest(start)
+	}
+	return false, next
+}
+
+// Open returns whether upgrades and rollouts are allowed at t. If they are not, it also returns
+// why and when the next window opens, the time is zero if that is unknown
+func (in *UpgradeWindow) Open(t time.Time) (bool, string, time.Time) {
+	// Freezes can be consecutive or overlap, the next window opens after all of them
+	reason := ""
+	for i := 0; i <= len(in.Freezes); i++ {
+		freeze, end := in.activeFreeze(t)
+		if freeze == nil {
+			break
+		}
+		if reason == "" {
+			reason = "change freeze"
+			if freeze.Reason != "" {
+				reason = fmt.Sprintf("change freeze: %v", freeze.Reason)
+			}
+		}
+		t = end
+	}
+
+	open, next := in.windowOpen(t)
+	if reason == "" {
+		if open {
+			return true, "", time.Time{}
+		}
+		return false, "outside of the upgrade window", next
+	}
+	if open {
+		next = t
+	}
+	return false, reason, next
+}
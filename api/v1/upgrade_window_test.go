@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpgradeWindow_Open(t *testing.T) {
+	// Windows of 4h every Saturday at 02:00, 2021-08-07 is a Saturday
+	saturdays := UpgradeWindow{Schedule: "0 2 * * 6", Duration: "4h"}
+
+	tests := []struct {
+		name       string
+		window     UpgradeWindow
+		at         time.Time
+		wantOpen   bool
+		wantReason string
+		wantNext   time.Time
+	}{
+		{
+			name:     "no restrictions",
+			window:   UpgradeWindow{},
+			at:       time.Date(2021, 8, 4, 12, 0, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+		{
+			name:     "in a window of the schedule",
+			window:   saturdays,
+			at:       time.Date(2021, 8, 7, 3, 0, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+		{
+			name:     "start of a window of the schedule",
+			window:   saturdays,
+			at:       time.Date(2021, 8, 7, 2, 0, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+		{
+			name:       "end of a window of the schedule",
+			window:     saturdays,
+			at:         time.Date(2021, 8, 7, 6, 0, 0, 0, time.UTC),
+			wantReason: "outside of the upgrade window",
+			wantNext:   time.Date(2021, 8, 14, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "before a window of the schedule",
+			window:     saturdays,
+			at:         time.Date(2021, 8, 4, 12, 0, 0, 0, time.UTC),
+			wantReason: "outside of the upgrade window",
+			wantNext:   time.Date(2021, 8, 7, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "window of the schedule across midnight and the month",
+			window:     UpgradeWindow{Schedule: "0 22 31 * *", Duration: "6h"},
+			at:         time.Date(2021, 8, 1, 5, 0, 0, 0, time.UTC),
+			wantReason: "outside of the upgrade window",
+			wantNext:   time.Date(2021, 8, 31, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "window of the schedule started in the previous month",
+			window:   UpgradeWindow{Schedule: "0 22 31 * *", Duration: "6h"},
+			at:       time.Date(2021, 8, 1, 3, 0, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+		{
+			name: "recurring window in the time zone",
+			window: UpgradeWindow{Windows: []UpgradeWindowRange{
+				{Weekdays: []string{"saturday"}, StartTime: "01:00", EndTime: "05:00", TimeZone: "Europe/Berlin"},
+			}},
+			at:       time.Date(2021, 8, 6, 23, 30, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+		{
+			name: "earliest of the schedule and the recurring windows",
+			window: UpgradeWindow{Schedule: "0 2 * * 6", Duration: "4h", Windows: []UpgradeWindowRange{
+				{Weekdays: []string{"thursday"}, StartTime: "20:00", EndTime: "22:00"},
+			}},
+			at:         time.Date(2021, 8, 4, 12, 0, 0, 0, time.UTC),
+			wantReason: "outside of the upgrade window",
+			wantNext:   time.Date(2021, 8, 5, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "freeze without windows",
+			window: UpgradeWindow{Freezes: []ChangeFreeze{
+				{Start: "2021-08-01T00:00:00Z", End: "2021-08-05T00:00:00Z", Reason: "release"},
+			}},
+			at:         time.Date(2021, 8, 4, 12, 0, 0, 0, time.UTC),
+			wantReason: "change freeze: release",
+			wantNext:   time.Date(2021, 8, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "freeze without reason",
+			window: UpgradeWindow{Freezes: []ChangeFreeze{
+				{Start: "2021-08-01T00:00:00Z", End: "2021-08-05T00:00:00Z"},
+			}},
+			at:         time.Date(2021, 8, 4, 12, 0, 0, 0, time.UTC),
+			wantReason: "change freeze",
+			wantNext:   time.Date(2021, 8, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "end of a freeze",
+			window: UpgradeWindow{Freezes: []ChangeFreeze{
+				{Start: "2021-08-01T00:00:00Z", End: "2021-08-05T00:00:00Z"},
+			}},
+			at:       time.Date(2021, 8, 5, 0, 0, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+		{
+			name: "consecutive freezes",
+			window: UpgradeWindow{Freezes: []ChangeFreeze{
+				{Start: "2021-08-05T00:00:00Z", End: "2021-08-10T00:00:00Z", Reason: "holidays"},
+				{Start: "2021-08-01T00:00:00Z", End: "2021-08-05T00:00:00Z", Reason: "release"},
+			}},
+			at:         time.Date(2021, 8, 4, 12, 0, 0, 0, time.UTC),
+			wantReason: "change freeze: release",
+			wantNext:   time.Date(2021, 8, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overlapping freezes",
+			window: UpgradeWindow{Freezes: []ChangeFreeze{
+				{Start: "2021-08-01T00:00:00Z", End: "2021-08-06T00:00:00Z", Reason: "release"},
+				{Start: "2021-08-04T00:00:00Z", End: "2021-08-10T00:00:00Z", Reason: "holidays"},
+			}},
+			at:         time.Date(2021, 8, 4, 12, 0, 0, 0, time.UTC),
+			wantReason: "change freeze: release",
+			wantNext:   time.Date(2021, 8, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "freeze in a window",
+			window: UpgradeWindow{Schedule: "0 2 * * 6", Duration: "4h", Freezes: []ChangeFreeze{
+				{Start: "2021-08-07T01:00:00Z", End: "2021-08-07T03:00:00Z", Reason: "incident"},
+			}},
+			at:         time.Date(2021, 8, 7, 2, 30, 0, 0, time.UTC),
+			wantReason: "change freeze: incident",
+			wantNext:   time.Date(2021, 8, 7, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "freeze lasting until after the window",
+			window: UpgradeWindow{Schedule: "0 2 * * 6", Duration: "4h", Freezes: []ChangeFreeze{
+				{Start: "2021-08-06T00:00:00Z", End: "2021-08-07T06:00:00Z", Reason: "release"},
+			}},
+			at:         time.Date(2021, 8, 7, 3, 0, 0, 0, time.UTC),
+			wantReason: "change freeze: release",
+			wantNext:   time.Date(2021, 8, 14, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "invalid freezes are ignored",
+			window: UpgradeWindow{Freezes: []ChangeFreeze{
+				{Start: "2021-08-01", End: "2021-08-10"},
+			}},
+			at:       time.Date(2021, 8, 4, 12, 0, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, reason, next := tt.window.Open(tt.at)
+			if open != tt.wantOpen || reason != tt.wantReason || !next.Equal(tt.wantNext) {
+				t.Errorf("Open() = %v, %v, %v, want %v, %v, %v", open, reason, next, tt.wantOpen, tt.wantReason, tt.wantNext)
+			}
+		})
+	}
+}
@@ -29,79 +29,54 @@ import (
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AlertmanagerConfigGlobal) DeepCopyInto(out *AlertmanagerConfigGlobal) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigGlobal.
-func (in *AlertmanagerConfigGlobal) DeepCopy() *AlertmanagerConfigGlobal {
-	if in == nil {
-		return nil
-	}
-	out := new(AlertmanagerConfigGlobal)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AlertmanagerConfigReceiver) DeepCopyInto(out *AlertmanagerConfigReceiver) {
+func (in *Access) DeepCopyInto(out *Access) {
 	*out = *in
-	if in.PagerDutyConfigs != nil {
-		in, out := &in.PagerDutyConfigs, &out.PagerDutyConfigs
-		*out = make([]PagerDutyConfig, len(*in))
+	if in.ViewerGroups != nil {
+		in, out := &in.ViewerGroups, &out.ViewerGroups
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.WebhookConfigs != nil {
-		in, out := &in.WebhookConfigs, &out.WebhookConfigs
-		*out = make([]WebhookConfig, len(*in))
+	if in.EditorGroups != nil {
+		in, out := &in.EditorGroups, &out.EditorGroups
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigReceiver.
-func (in *AlertmanagerConfigReceiver) DeepCopy() *AlertmanagerConfigReceiver {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Access.
+func (in *Access) DeepCopy() *Access {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertmanagerConfigReceiver)
+	out := new(Access)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AlertmanagerConfigRoot) DeepCopyInto(out *AlertmanagerConfigRoot) {
+func (in *AlertForwarder) DeepCopyInto(out *AlertForwarder) {
 	*out = *in
-	if in.Global != nil {
-		in, out := &in.Global, &out.Global
-		*out = new(AlertmanagerConfigGlobal)
-		**out = **in
-	}
-	if in.Route != nil {
-		in, out := &in.Route, &out.Route
-		*out = new(AlertmanagerConfigRoute)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Receivers != nil {
-		in, out := &in.Receivers, &out.Receivers
-		*out = make([]AlertmanagerConfigReceiver, len(*in))
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]AlertForwarderDestination, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigRoot.
-func (in *AlertmanagerConfigRoot) DeepCopy() *AlertmanagerConfigRoot {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertForwarder.
+func (in *AlertForwarder) DeepCopy() *AlertForwarder {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertmanagerConfigRoot)
+	out := new(AlertForwarder)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AlertmanagerConfigRoute) DeepCopyInto(out *AlertmanagerConfigRoute) {
+func (in *AlertForwarderDestination) DeepCopyInto(out *AlertForwarderDestination) {
 	*out = *in
 	if in.Match != nil {
 		in, out := &in.Match, &out.Match
@@ -110,260 +85,3038 @@ func (in *AlertmanagerConfigRoute) DeepCopyInto(out *AlertmanagerConfigRoute) {
 			(*out)[key] = val
 		}
 	}
-	if in.Routes != nil {
-		in, out := &in.Routes, &out.Routes
-		*out = make([]AlertmanagerConfigRoute, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.EventBridge != nil {
+		in, out := &in.EventBridge, &out.EventBridge
+		*out = new(AlertForwarderEventBridge)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigRoute.
-func (in *AlertmanagerConfigRoute) DeepCopy() *AlertmanagerConfigRoute {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertForwarderDestination.
+func (in *AlertForwarderDestination) DeepCopy() *AlertForwarderDestination {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertmanagerConfigRoute)
+	out := new(AlertForwarderDestination)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AlertmanagerIndex) DeepCopyInto(out *AlertmanagerIndex) {
+func (in *AlertForwarderEventBridge) DeepCopyInto(out *AlertForwarderEventBridge) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerIndex.
-func (in *AlertmanagerIndex) DeepCopy() *AlertmanagerIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertForwarderEventBridge.
+func (in *AlertForwarderEventBridge) DeepCopy() *AlertForwarderEventBridge {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertmanagerIndex)
+	out := new(AlertForwarderEventBridge)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DexConfig) DeepCopyInto(out *DexConfig) {
+func (in *AlertHistory) DeepCopyInto(out *AlertHistory) {
 	*out = *in
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DexConfig.
-func (in *DexConfig) DeepCopy() *DexConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertHistory.
+func (in *AlertHistory) DeepCopy() *AlertHistory {
 	if in == nil {
 		return nil
 	}
-	out := new(DexConfig)
+	out := new(AlertHistory)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaIndex) DeepCopyInto(out *GrafanaIndex) {
+func (in *AlertTemplates) DeepCopyInto(out *AlertTemplates) {
 	*out = *in
-	if in.Dashboards != nil {
-		in, out := &in.Dashboards, &out.Dashboards
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.DashboardLabelSelector != nil {
-		in, out := &in.DashboardLabelSelector, &out.DashboardLabelSelector
+	if in.ConfigMapSelector != nil {
+		in, out := &in.ConfigMapSelector, &out.ConfigMapSelector
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaIndex.
-func (in *GrafanaIndex) DeepCopy() *GrafanaIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertTemplates.
+func (in *AlertTemplates) DeepCopy() *AlertTemplates {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaIndex)
+	out := new(AlertTemplates)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Observability) DeepCopyInto(out *Observability) {
+func (in *AlertTicketing) DeepCopyInto(out *AlertTicketing) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AutoResolve != nil {
+		in, out := &in.AutoResolve, &out.AutoResolve
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServiceNow != nil {
+		in, out := &in.ServiceNow, &out.ServiceNow
+		*out = new(AlertTicketingServiceNow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Jira != nil {
+		in, out := &in.Jira, &out.Jira
+		*out = new(AlertTicketingJira)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observability.
-func (in *Observability) DeepCopy() *Observability {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertTicketing.
+func (in *AlertTicketing) DeepCopy() *AlertTicketing {
 	if in == nil {
 		return nil
 	}
-	out := new(Observability)
+	out := new(AlertTicketing)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Observability) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertTicketingJira) DeepCopyInto(out *AlertTicketingJira) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertTicketingJira.
+func (in *AlertTicketingJira) DeepCopy() *AlertTicketingJira {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(AlertTicketingJira)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservabilityList) DeepCopyInto(out *ObservabilityList) {
+func (in *AlertTicketingServiceNow) DeepCopyInto(out *AlertTicketingServiceNow) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Observability, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.ResolveFields != nil {
+		in, out := &in.ResolveFields, &out.ResolveFields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityList.
-func (in *ObservabilityList) DeepCopy() *ObservabilityList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertTicketingServiceNow.
+func (in *AlertTicketingServiceNow) DeepCopy() *AlertTicketingServiceNow {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservabilityList)
+	out := new(AlertTicketingServiceNow)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ObservabilityList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservabilitySpec) DeepCopyInto(out *ObservabilitySpec) {
+func (in *Alerting) DeepCopyInto(out *Alerting) {
 	*out = *in
-	if in.ConfigurationSelector != nil {
-		in, out := &in.ConfigurationSelector, &out.ConfigurationSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Storage != nil {
-		in, out := &in.Storage, &out.Storage
-		*out = new(Storage)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
+	if in.InhibitRules != nil {
+		in, out := &in.InhibitRules, &out.InhibitRules
+		*out = make([]InhibitRule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(corev1.Affinity)
+	if in.Forwarder != nil {
+		in, out := &in.Forwarder, &out.Forwarder
+		*out = new(AlertForwarder)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.SelfContained != nil {
-		in, out := &in.SelfContained, &out.SelfContained
-		*out = new(SelfContained)
+	if in.Ticketing != nil {
+		in, out := &in.Ticketing, &out.Ticketing
+		*out = new(AlertTicketing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = new(AlertHistory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = new(AlertTemplates)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MuteTimeIntervals != nil {
+		in, out := &in.MuteTimeIntervals, &out.MuteTimeIntervals
+		*out = make([]MuteTimeInterval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilitySpec.
-func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Alerting.
+func (in *Alerting) DeepCopy() *Alerting {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservabilitySpec)
+	out := new(Alerting)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservabilityStatus) DeepCopyInto(out *ObservabilityStatus) {
+func (in *AlertmanagerConfigGlobal) DeepCopyInto(out *AlertmanagerConfigGlobal) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityStatus.
-func (in *ObservabilityStatus) DeepCopy() *ObservabilityStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigGlobal.
+func (in *AlertmanagerConfigGlobal) DeepCopy() *AlertmanagerConfigGlobal {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservabilityStatus)
+	out := new(AlertmanagerConfigGlobal)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservatoriumIndex) DeepCopyInto(out *ObservatoriumIndex) {
+func (in *AlertmanagerConfigInhibitRule) DeepCopyInto(out *AlertmanagerConfigInhibitRule) {
 	*out = *in
-	if in.DexConfig != nil {
-		in, out := &in.DexConfig, &out.DexConfig
-		*out = new(DexConfig)
-		**out = **in
+	if in.SourceMatch != nil {
+		in, out := &in.SourceMatch, &out.SourceMatch
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.RedhatSsoConfig != nil {
-		in, out := &in.RedhatSsoConfig, &out.RedhatSsoConfig
-		*out = new(RedhatSsoConfig)
-		**out = **in
+	if in.SourceMatchRe != nil {
+		in, out := &in.SourceMatchRe, &out.SourceMatchRe
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetMatch != nil {
+		in, out := &in.TargetMatch, &out.TargetMatch
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetMatchRe != nil {
+		in, out := &in.TargetMatchRe, &out.TargetMatchRe
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Equal != nil {
+		in, out := &in.Equal, &out.Equal
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservatoriumIndex.
-func (in *ObservatoriumIndex) DeepCopy() *ObservatoriumIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigInhibitRule.
+func (in *AlertmanagerConfigInhibitRule) DeepCopy() *AlertmanagerConfigInhibitRule {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservatoriumIndex)
+	out := new(AlertmanagerConfigInhibitRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PagerDutyConfig) DeepCopyInto(out *PagerDutyConfig) {
+func (in *AlertmanagerConfigReceiver) DeepCopyInto(out *AlertmanagerConfigReceiver) {
 	*out = *in
+	if in.PagerDutyConfigs != nil {
+		in, out := &in.PagerDutyConfigs, &out.PagerDutyConfigs
+		*out = make([]PagerDutyConfig, len(*in))
+		copy(*out, *in)
+	}
+	if in.WebhookConfigs != nil {
+		in, out := &in.WebhookConfigs, &out.WebhookConfigs
+		*out = make([]WebhookConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyConfig.
-func (in *PagerDutyConfig) DeepCopy() *PagerDutyConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigReceiver.
+func (in *AlertmanagerConfigReceiver) DeepCopy() *AlertmanagerConfigReceiver {
 	if in == nil {
 		return nil
 	}
-	out := new(PagerDutyConfig)
+	out := new(AlertmanagerConfigReceiver)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PrometheusIndex) DeepCopyInto(out *PrometheusIndex) {
+func (in *AlertmanagerConfigRoot) DeepCopyInto(out *AlertmanagerConfigRoot) {
 	*out = *in
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.PodMonitors != nil {
-		in, out := &in.PodMonitors, &out.PodMonitors
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.Global != nil {
+		in, out := &in.Global, &out.Global
+		*out = new(AlertmanagerConfigGlobal)
+		**out = **in
 	}
-	if in.PodMonitorLabelSelector != nil {
-		in, out := &in.PodMonitorLabelSelector, &out.PodMonitorLabelSelector
-		*out = new(metav1.LabelSelector)
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(AlertmanagerConfigRoute)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.PodMonitorNamespaceSelector != nil {
+	if in.Receivers != nil {
+		in, out := &in.Receivers, &out.Receivers
+		*out = make([]AlertmanagerConfigReceiver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InhibitRules != nil {
+		in, out := &in.InhibitRules, &out.InhibitRules
+		*out = make([]AlertmanagerConfigInhibitRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigRoot.
+func (in *AlertmanagerConfigRoot) DeepCopy() *AlertmanagerConfigRoot {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerConfigRoot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerConfigRoute) DeepCopyInto(out *AlertmanagerConfigRoute) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchRe != nil {
+		in, out := &in.MatchRe, &out.MatchRe
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]AlertmanagerConfigRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigRoute.
+func (in *AlertmanagerConfigRoute) DeepCopy() *AlertmanagerConfigRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerConfigRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerIndex) DeepCopyInto(out *AlertmanagerIndex) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerIndex.
+func (in *AlertmanagerIndex) DeepCopy() *AlertmanagerIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Backup) DeepCopyInto(out *Backup) {
+	*out = *in
+	if in.Identity != nil {
+		in, out := &in.Identity, &out.Identity
+		*out = new(ObjectStorageIdentity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
+func (in *Backup) DeepCopy() *Backup {
+	if in == nil {
+		return nil
+	}
+	out := new(Backup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeFreeze) DeepCopyInto(out *ChangeFreeze) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeFreeze.
+func (in *ChangeFreeze) DeepCopy() *ChangeFreeze {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeFreeze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapabilities) DeepCopyInto(out *ClusterCapabilities) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapabilities.
+func (in *ClusterCapabilities) DeepCopy() *ClusterCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentVersion) DeepCopyInto(out *ComponentVersion) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentVersion.
+func (in *ComponentVersion) DeepCopy() *ComponentVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Components) DeepCopyInto(out *Components) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Components.
+func (in *Components) DeepCopy() *Components {
+	if in == nil {
+		return nil
+	}
+	out := new(Components)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigApply) DeepCopyInto(out *ConfigApply) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigApply.
+func (in *ConfigApply) DeepCopy() *ConfigApply {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigApply)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigApplyProgress) DeepCopyInto(out *ConfigApplyProgress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigApplyProgress.
+func (in *ConfigApplyProgress) DeepCopy() *ConfigApplyProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigApplyProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigConflict) DeepCopyInto(out *ConfigConflict) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigConflict.
+func (in *ConfigConflict) DeepCopy() *ConfigConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMerge) DeepCopyInto(out *ConfigMerge) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMerge.
+func (in *ConfigMerge) DeepCopy() *ConfigMerge {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMerge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigRevision) DeepCopyInto(out *ConfigRevision) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRevision.
+func (in *ConfigRevision) DeepCopy() *ConfigRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigRollout) DeepCopyInto(out *ConfigRollout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRollout.
+func (in *ConfigRollout) DeepCopy() *ConfigRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationSource) DeepCopyInto(out *ConfigurationSource) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]ConfigResourceKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleSelector != nil {
+		in, out := &in.RuleSelector, &out.RuleSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSource.
+func (in *ConfigurationSource) DeepCopy() *ConfigurationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Console) DeepCopyInto(out *Console) {
+	*out = *in
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Dashboard != nil {
+		in, out := &in.Dashboard, &out.Dashboard
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(ConsolePlugin)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Console.
+func (in *Console) DeepCopy() *Console {
+	if in == nil {
+		return nil
+	}
+	out := new(Console)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsolePlugin) DeepCopyInto(out *ConsolePlugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsolePlugin.
+func (in *ConsolePlugin) DeepCopy() *ConsolePlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsolePlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialExpiry) DeepCopyInto(out *CredentialExpiry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialExpiry.
+func (in *CredentialExpiry) DeepCopy() *CredentialExpiry {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialExpiry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardConflict) DeepCopyInto(out *DashboardConflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardConflict.
+func (in *DashboardConflict) DeepCopy() *DashboardConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeferredRollout) DeepCopyInto(out *DeferredRollout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeferredRollout.
+func (in *DeferredRollout) DeepCopy() *DeferredRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(DeferredRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DexConfig) DeepCopyInto(out *DexConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DexConfig.
+func (in *DexConfig) DeepCopy() *DexConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DexConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Drift) DeepCopyInto(out *Drift) {
+	*out = *in
+	if in.ReportOnly != nil {
+		in, out := &in.ReportOnly, &out.ReportOnly
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Drift.
+func (in *Drift) DeepCopy() *Drift {
+	if in == nil {
+		return nil
+	}
+	out := new(Drift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftedResource) DeepCopyInto(out *DriftedResource) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedResource.
+func (in *DriftedResource) DeepCopy() *DriftedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DuplicateRule) DeepCopyInto(out *DuplicateRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DuplicateRule.
+func (in *DuplicateRule) DeepCopy() *DuplicateRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DuplicateRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExpiryMonitoring) DeepCopyInto(out *ExpiryMonitoring) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpiryMonitoring.
+func (in *ExpiryMonitoring) DeepCopy() *ExpiryMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(ExpiryMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecret) DeepCopyInto(out *ExternalSecret) {
+	*out = *in
+	out.StoreRef = in.StoreRef
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make([]ExternalSecretData, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecret.
+func (in *ExternalSecret) DeepCopy() *ExternalSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretData) DeepCopyInto(out *ExternalSecretData) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretData.
+func (in *ExternalSecretData) DeepCopy() *ExternalSecretData {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretStoreRef) DeepCopyInto(out *ExternalSecretStoreRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretStoreRef.
+func (in *ExternalSecretStoreRef) DeepCopy() *ExternalSecretStoreRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretStoreRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetTelemetry) DeepCopyInto(out *FleetTelemetry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetTelemetry.
+func (in *FleetTelemetry) DeepCopy() *FleetTelemetry {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetTelemetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Grafana) DeepCopyInto(out *Grafana) {
+	*out = *in
+	if in.Smtp != nil {
+		in, out := &in.Smtp, &out.Smtp
+		*out = new(GrafanaSmtp)
+		**out = **in
+	}
+	if in.ContactPoints != nil {
+		in, out := &in.ContactPoints, &out.ContactPoints
+		*out = make([]GrafanaContactPoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(GrafanaExternal)
+		**out = **in
+	}
+	if in.Organizations != nil {
+		in, out := &in.Organizations, &out.Organizations
+		*out = make([]GrafanaOrganization, len(*in))
+		copy(*out, *in)
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]GrafanaTeam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.APIKeys != nil {
+		in, out := &in.APIKeys, &out.APIKeys
+		*out = make([]GrafanaAPIKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageRenderer != nil {
+		in, out := &in.ImageRenderer, &out.ImageRenderer
+		*out = new(GrafanaImageRenderer)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(GrafanaAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(GrafanaPersistence)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = new(GrafanaAnnotations)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatasourceHealth != nil {
+		in, out := &in.DatasourceHealth, &out.DatasourceHealth
+		*out = new(GrafanaDatasourceHealth)
+		**out = **in
+	}
+	if in.Sharing != nil {
+		in, out := &in.Sharing, &out.Sharing
+		*out = new(GrafanaSharing)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Grafana.
+func (in *Grafana) DeepCopy() *Grafana {
+	if in == nil {
+		return nil
+	}
+	out := new(Grafana)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAPIKey) DeepCopyInto(out *GrafanaAPIKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAPIKey.
+func (in *GrafanaAPIKey) DeepCopy() *GrafanaAPIKey {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAPIKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAnnotation) DeepCopyInto(out *GrafanaAnnotation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAnnotation.
+func (in *GrafanaAnnotation) DeepCopy() *GrafanaAnnotation {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAnnotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAnnotations) DeepCopyInto(out *GrafanaAnnotations) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]GrafanaAnnotationSource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAnnotations.
+func (in *GrafanaAnnotations) DeepCopy() *GrafanaAnnotations {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAnnotations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAuth) DeepCopyInto(out *GrafanaAuth) {
+	*out = *in
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(GrafanaLDAP)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAuth.
+func (in *GrafanaAuth) DeepCopy() *GrafanaAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaContactPoint) DeepCopyInto(out *GrafanaContactPoint) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaContactPoint.
+func (in *GrafanaContactPoint) DeepCopy() *GrafanaContactPoint {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaContactPoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatabase) DeepCopyInto(out *GrafanaDatabase) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatabase.
+func (in *GrafanaDatabase) DeepCopy() *GrafanaDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceHealth) DeepCopyInto(out *GrafanaDatasourceHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceHealth.
+func (in *GrafanaDatasourceHealth) DeepCopy() *GrafanaDatasourceHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceHealthStatus) DeepCopyInto(out *GrafanaDatasourceHealthStatus) {
+	*out = *in
+	if in.Unhealthy != nil {
+		in, out := &in.Unhealthy, &out.Unhealthy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceHealthStatus.
+func (in *GrafanaDatasourceHealthStatus) DeepCopy() *GrafanaDatasourceHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaExternal) DeepCopyInto(out *GrafanaExternal) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaExternal.
+func (in *GrafanaExternal) DeepCopy() *GrafanaExternal {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaExternal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderIndex) DeepCopyInto(out *GrafanaFolderIndex) {
+	*out = *in
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]GrafanaFolderPermission, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderIndex.
+func (in *GrafanaFolderIndex) DeepCopy() *GrafanaFolderIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderPermission) DeepCopyInto(out *GrafanaFolderPermission) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderPermission.
+func (in *GrafanaFolderPermission) DeepCopy() *GrafanaFolderPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaImageRenderer) DeepCopyInto(out *GrafanaImageRenderer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaImageRenderer.
+func (in *GrafanaImageRenderer) DeepCopy() *GrafanaImageRenderer {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaImageRenderer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaIndex) DeepCopyInto(out *GrafanaIndex) {
+	*out = *in
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DashboardLabelSelector != nil {
+		in, out := &in.DashboardLabelSelector, &out.DashboardLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Folders != nil {
+		in, out := &in.Folders, &out.Folders
+		*out = make([]GrafanaFolderIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]GrafanaPlugin, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaIndex.
+func (in *GrafanaIndex) DeepCopy() *GrafanaIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLDAP) DeepCopyInto(out *GrafanaLDAP) {
+	*out = *in
+	if in.SearchBaseDNs != nil {
+		in, out := &in.SearchBaseDNs, &out.SearchBaseDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupMappings != nil {
+		in, out := &in.GroupMappings, &out.GroupMappings
+		*out = make([]GrafanaLDAPGroupMapping, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLDAP.
+func (in *GrafanaLDAP) DeepCopy() *GrafanaLDAP {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLDAP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLDAPGroupMapping) DeepCopyInto(out *GrafanaLDAPGroupMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLDAPGroupMapping.
+func (in *GrafanaLDAPGroupMapping) DeepCopy() *GrafanaLDAPGroupMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLDAPGroupMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaOrganization) DeepCopyInto(out *GrafanaOrganization) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaOrganization.
+func (in *GrafanaOrganization) DeepCopy() *GrafanaOrganization {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaOrganization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPersistence) DeepCopyInto(out *GrafanaPersistence) {
+	*out = *in
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(GrafanaVolume)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(GrafanaDatabase)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPersistence.
+func (in *GrafanaPersistence) DeepCopy() *GrafanaPersistence {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPersistence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaPlugin) DeepCopyInto(out *GrafanaPlugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaPlugin.
+func (in *GrafanaPlugin) DeepCopy() *GrafanaPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaSharing) DeepCopyInto(out *GrafanaSharing) {
+	*out = *in
+	if in.SnapshotServer != nil {
+		in, out := &in.SnapshotServer, &out.SnapshotServer
+		*out = new(GrafanaSnapshotServer)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSharing.
+func (in *GrafanaSharing) DeepCopy() *GrafanaSharing {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaSharing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaSharingStatus) DeepCopyInto(out *GrafanaSharingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSharingStatus.
+func (in *GrafanaSharingStatus) DeepCopy() *GrafanaSharingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaSharingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaSmtp) DeepCopyInto(out *GrafanaSmtp) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSmtp.
+func (in *GrafanaSmtp) DeepCopy() *GrafanaSmtp {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaSmtp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaSnapshotServer) DeepCopyInto(out *GrafanaSnapshotServer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSnapshotServer.
+func (in *GrafanaSnapshotServer) DeepCopy() *GrafanaSnapshotServer {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaSnapshotServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaTeam) DeepCopyInto(out *GrafanaTeam) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTeam.
+func (in *GrafanaTeam) DeepCopy() *GrafanaTeam {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaTeam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaVolume) DeepCopyInto(out *GrafanaVolume) {
+	*out = *in
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaVolume.
+func (in *GrafanaVolume) DeepCopy() *GrafanaVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HttpConfig) DeepCopyInto(out *HttpConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpConfig.
+func (in *HttpConfig) DeepCopy() *HttpConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HttpConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InhibitRule) DeepCopyInto(out *InhibitRule) {
+	*out = *in
+	if in.SourceMatch != nil {
+		in, out := &in.SourceMatch, &out.SourceMatch
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SourceMatchRegex != nil {
+		in, out := &in.SourceMatchRegex, &out.SourceMatchRegex
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetMatch != nil {
+		in, out := &in.TargetMatch, &out.TargetMatch
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetMatchRegex != nil {
+		in, out := &in.TargetMatchRegex, &out.TargetMatchRegex
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Equal != nil {
+		in, out := &in.Equal, &out.Equal
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InhibitRule.
+func (in *InhibitRule) DeepCopy() *InhibitRule {
+	if in == nil {
+		return nil
+	}
+	out := new(InhibitRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InvalidAlertTemplate) DeepCopyInto(out *InvalidAlertTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvalidAlertTemplate.
+func (in *InvalidAlertTemplate) DeepCopy() *InvalidAlertTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(InvalidAlertTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InvalidDashboard) DeepCopyInto(out *InvalidDashboard) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvalidDashboard.
+func (in *InvalidDashboard) DeepCopy() *InvalidDashboard {
+	if in == nil {
+		return nil
+	}
+	out := new(InvalidDashboard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InvalidLogRule) DeepCopyInto(out *InvalidLogRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvalidLogRule.
+func (in *InvalidLogRule) DeepCopy() *InvalidLogRule {
+	if in == nil {
+		return nil
+	}
+	out := new(InvalidLogRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogBuffering) DeepCopyInto(out *LogBuffering) {
+	*out = *in
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogBuffering.
+func (in *LogBuffering) DeepCopy() *LogBuffering {
+	if in == nil {
+		return nil
+	}
+	out := new(LogBuffering)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogMetric) DeepCopyInto(out *LogMetric) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogMetric.
+func (in *LogMetric) DeepCopy() *LogMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(LogMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogRules) DeepCopyInto(out *LogRules) {
+	*out = *in
+	if in.ConfigMapSelector != nil {
+		in, out := &in.ConfigMapSelector, &out.ConfigMapSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ruler != nil {
+		in, out := &in.Ruler, &out.Ruler
+		*out = new(LokiRuler)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogRules.
+func (in *LogRules) DeepCopy() *LogRules {
+	if in == nil {
+		return nil
+	}
+	out := new(LogRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogTenant) DeepCopyInto(out *LogTenant) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogTenant.
+func (in *LogTenant) DeepCopy() *LogTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(LogTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Logs) DeepCopyInto(out *Logs) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]LogMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Clients != nil {
+		in, out := &in.Clients, &out.Clients
+		*out = make([]PromtailClient, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]LogTenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = new(LogRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Buffering != nil {
+		in, out := &in.Buffering, &out.Buffering
+		*out = new(LogBuffering)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Logs.
+func (in *Logs) DeepCopy() *Logs {
+	if in == nil {
+		return nil
+	}
+	out := new(Logs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LokiIndex) DeepCopyInto(out *LokiIndex) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiIndex.
+func (in *LokiIndex) DeepCopy() *LokiIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(LokiIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LokiRuler) DeepCopyInto(out *LokiRuler) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiRuler.
+func (in *LokiRuler) DeepCopy() *LokiRuler {
+	if in == nil {
+		return nil
+	}
+	out := new(LokiRuler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
+	*out = *in
+	if in.TranslatedFields != nil {
+		in, out := &in.TranslatedFields, &out.TranslatedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
+func (in *MigrationStatus) DeepCopy() *MigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MuteTimeInterval) DeepCopyInto(out *MuteTimeInterval) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Weekdays != nil {
+		in, out := &in.Weekdays, &out.Weekdays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MuteTimeInterval.
+func (in *MuteTimeInterval) DeepCopy() *MuteTimeInterval {
+	if in == nil {
+		return nil
+	}
+	out := new(MuteTimeInterval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MuteTimeIntervalStatus) DeepCopyInto(out *MuteTimeIntervalStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MuteTimeIntervalStatus.
+func (in *MuteTimeIntervalStatus) DeepCopy() *MuteTimeIntervalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MuteTimeIntervalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Networking) DeepCopyInto(out *Networking) {
+	*out = *in
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Networking.
+func (in *Networking) DeepCopy() *Networking {
+	if in == nil {
+		return nil
+	}
+	out := new(Networking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OLM) DeepCopyInto(out *OLM) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OLM.
+func (in *OLM) DeepCopy() *OLM {
+	if in == nil {
+		return nil
+	}
+	out := new(OLM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStorageIdentity) DeepCopyInto(out *ObjectStorageIdentity) {
+	*out = *in
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorageIdentity.
+func (in *ObjectStorageIdentity) DeepCopy() *ObjectStorageIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStorageIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Observability) DeepCopyInto(out *Observability) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observability.
+func (in *Observability) DeepCopy() *Observability {
+	if in == nil {
+		return nil
+	}
+	out := new(Observability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Observability) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityDefaults) DeepCopyInto(out *ObservabilityDefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityDefaults.
+func (in *ObservabilityDefaults) DeepCopy() *ObservabilityDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ObservabilityDefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityDefaultsList) DeepCopyInto(out *ObservabilityDefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ObservabilityDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityDefaultsList.
+func (in *ObservabilityDefaultsList) DeepCopy() *ObservabilityDefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityDefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ObservabilityDefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityDefaultsSpec) DeepCopyInto(out *ObservabilityDefaultsSpec) {
+	*out = *in
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[string]corev1.ResourceRequirements, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.PrometheusStorageClass != nil {
+		in, out := &in.PrometheusStorageClass, &out.PrometheusStorageClass
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityDefaultsSpec.
+func (in *ObservabilityDefaultsSpec) DeepCopy() *ObservabilityDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityList) DeepCopyInto(out *ObservabilityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Observability, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityList.
+func (in *ObservabilityList) DeepCopy() *ObservabilityList {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ObservabilityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilitySpec) DeepCopyInto(out *ObservabilitySpec) {
+	*out = *in
+	if in.ConfigurationSelector != nil {
+		in, out := &in.ConfigurationSelector, &out.ConfigurationSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(Storage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SelfContained != nil {
+		in, out := &in.SelfContained, &out.SelfContained
+		*out = new(SelfContained)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OLM != nil {
+		in, out := &in.OLM, &out.OLM
+		*out = new(OLM)
+		**out = **in
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[string]corev1.ResourceRequirements, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Observatorium != nil {
+		in, out := &in.Observatorium, &out.Observatorium
+		*out = new(Observatorium)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigRollout != nil {
+		in, out := &in.ConfigRollout, &out.ConfigRollout
+		*out = new(ConfigRollout)
+		**out = **in
+	}
+	if in.GrafanaPlugins != nil {
+		in, out := &in.GrafanaPlugins, &out.GrafanaPlugins
+		*out = make([]GrafanaPlugin, len(*in))
+		copy(*out, *in)
+	}
+	if in.Alerting != nil {
+		in, out := &in.Alerting, &out.Alerting
+		*out = new(Alerting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(Tracing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryProxy != nil {
+		in, out := &in.QueryProxy, &out.QueryProxy
+		*out = new(QueryProxy)
+		**out = **in
+	}
+	if in.FleetTelemetry != nil {
+		in, out := &in.FleetTelemetry, &out.FleetTelemetry
+		*out = new(FleetTelemetry)
+		**out = **in
+	}
+	if in.TokenRefresher != nil {
+		in, out := &in.TokenRefresher, &out.TokenRefresher
+		*out = new(TokenRefresher)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(Grafana)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(Logs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = new(Components)
+		**out = **in
+	}
+	if in.UserWorkloadMonitoring != nil {
+		in, out := &in.UserWorkloadMonitoring, &out.UserWorkloadMonitoring
+		*out = new(UserWorkloadMonitoring)
+		**out = **in
+	}
+	if in.ConfigValues != nil {
+		in, out := &in.ConfigValues, &out.ConfigValues
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(Backup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLS)
+		**out = **in
+	}
+	if in.Networking != nil {
+		in, out := &in.Networking, &out.Networking
+		*out = new(Networking)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(Drift)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = make([]Report, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrometheusAdapter != nil {
+		in, out := &in.PrometheusAdapter, &out.PrometheusAdapter
+		*out = new(PrometheusAdapter)
+		**out = **in
+	}
+	if in.SelfMonitoring != nil {
+		in, out := &in.SelfMonitoring, &out.SelfMonitoring
+		*out = new(SelfMonitoring)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TenantQuotas != nil {
+		in, out := &in.TenantQuotas, &out.TenantQuotas
+		*out = make([]TenantQuota, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMerge != nil {
+		in, out := &in.ConfigMerge, &out.ConfigMerge
+		*out = new(ConfigMerge)
+		**out = **in
+	}
+	if in.ConfigurationSources != nil {
+		in, out := &in.ConfigurationSources, &out.ConfigurationSources
+		*out = make([]ConfigurationSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigApply != nil {
+		in, out := &in.ConfigApply, &out.ConfigApply
+		*out = new(ConfigApply)
+		**out = **in
+	}
+	if in.RulePolicy != nil {
+		in, out := &in.RulePolicy, &out.RulePolicy
+		*out = new(RulePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleDeduplication != nil {
+		in, out := &in.RuleDeduplication, &out.RuleDeduplication
+		*out = new(RuleDeduplication)
+		**out = **in
+	}
+	if in.ExpiryMonitoring != nil {
+		in, out := &in.ExpiryMonitoring, &out.ExpiryMonitoring
+		*out = new(ExpiryMonitoring)
+		**out = **in
+	}
+	if in.StackVerification != nil {
+		in, out := &in.StackVerification, &out.StackVerification
+		*out = new(StackVerificationSpec)
+		**out = **in
+	}
+	if in.ExternalSecrets != nil {
+		in, out := &in.ExternalSecrets, &out.ExternalSecrets
+		*out = make([]ExternalSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpgradeWindow != nil {
+		in, out := &in.UpgradeWindow, &out.UpgradeWindow
+		*out = new(UpgradeWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Console != nil {
+		in, out := &in.Console, &out.Console
+		*out = new(Console)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = new(Access)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilitySpec.
+func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityStatus) DeepCopyInto(out *ObservabilityStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Subscriptions != nil {
+		in, out := &in.Subscriptions, &out.Subscriptions
+		*out = make([]SubscriptionStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrunedOLMResources != nil {
+		in, out := &in.PrunedOLMResources, &out.PrunedOLMResources
+		*out = make([]PrunedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReferencedSecrets != nil {
+		in, out := &in.ReferencedSecrets, &out.ReferencedSecrets
+		*out = make([]ReferencedSecret, len(*in))
+		copy(*out, *in)
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(ClusterCapabilities)
+		**out = **in
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = make([]ResourceRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigRevisions != nil {
+		in, out := &in.ConfigRevisions, &out.ConfigRevisions
+		*out = make([]ConfigRevision, len(*in))
+		copy(*out, *in)
+	}
+	if in.VerifiedGrafanaPlugins != nil {
+		in, out := &in.VerifiedGrafanaPlugins, &out.VerifiedGrafanaPlugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GrafanaContactPoints != nil {
+		in, out := &in.GrafanaContactPoints, &out.GrafanaContactPoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InvalidDashboards != nil {
+		in, out := &in.InvalidDashboards, &out.InvalidDashboards
+		*out = make([]InvalidDashboard, len(*in))
+		copy(*out, *in)
+	}
+	if in.DashboardConflicts != nil {
+		in, out := &in.DashboardConflicts, &out.DashboardConflicts
+		*out = make([]DashboardConflict, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigConflicts != nil {
+		in, out := &in.ConfigConflicts, &out.ConfigConflicts
+		*out = make([]ConfigConflict, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigApplyProgress != nil {
+		in, out := &in.ConfigApplyProgress, &out.ConfigApplyProgress
+		*out = make([]ConfigApplyProgress, len(*in))
+		copy(*out, *in)
+	}
+	if in.MuteTimeIntervals != nil {
+		in, out := &in.MuteTimeIntervals, &out.MuteTimeIntervals
+		*out = make([]MuteTimeIntervalStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.GrafanaSharing != nil {
+		in, out := &in.GrafanaSharing, &out.GrafanaSharing
+		*out = new(GrafanaSharingStatus)
+		**out = **in
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]DriftedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = make([]ReportStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingAPIs != nil {
+		in, out := &in.MissingAPIs, &out.MissingAPIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeferredRollouts != nil {
+		in, out := &in.DeferredRollouts, &out.DeferredRollouts
+		*out = make([]DeferredRollout, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuleTests != nil {
+		in, out := &in.RuleTests, &out.RuleTests
+		*out = make([]RuleTestResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedProfiles != nil {
+		in, out := &in.AppliedProfiles, &out.AppliedProfiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RulePolicyViolations != nil {
+		in, out := &in.RulePolicyViolations, &out.RulePolicyViolations
+		*out = make([]RulePolicyViolation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DuplicateRules != nil {
+		in, out := &in.DuplicateRules, &out.DuplicateRules
+		*out = make([]DuplicateRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.InvalidLogRules != nil {
+		in, out := &in.InvalidLogRules, &out.InvalidLogRules
+		*out = make([]InvalidLogRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.InvalidAlertTemplates != nil {
+		in, out := &in.InvalidAlertTemplates, &out.InvalidAlertTemplates
+		*out = make([]InvalidAlertTemplate, len(*in))
+		copy(*out, *in)
+	}
+	if in.TenantQuotas != nil {
+		in, out := &in.TenantQuotas, &out.TenantQuotas
+		*out = make([]TenantQuotaStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialExpiries != nil {
+		in, out := &in.CredentialExpiries, &out.CredentialExpiries
+		*out = make([]CredentialExpiry, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingGrafanaAnnotations != nil {
+		in, out := &in.PendingGrafanaAnnotations, &out.PendingGrafanaAnnotations
+		*out = make([]GrafanaAnnotation, len(*in))
+		copy(*out, *in)
+	}
+	if in.StackVerification != nil {
+		in, out := &in.StackVerification, &out.StackVerification
+		*out = new(StackVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatasourceHealth != nil {
+		in, out := &in.DatasourceHealth, &out.DatasourceHealth
+		*out = new(GrafanaDatasourceHealthStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentVersions != nil {
+		in, out := &in.ComponentVersions, &out.ComponentVersions
+		*out = make([]ComponentVersion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(MigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityStatus.
+func (in *ObservabilityStatus) DeepCopy() *ObservabilityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Observatorium) DeepCopyInto(out *Observatorium) {
+	*out = *in
+	if in.Tenant != nil {
+		in, out := &in.Tenant, &out.Tenant
+		*out = new(ObservatoriumTenant)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observatorium.
+func (in *Observatorium) DeepCopy() *Observatorium {
+	if in == nil {
+		return nil
+	}
+	out := new(Observatorium)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservatoriumIndex) DeepCopyInto(out *ObservatoriumIndex) {
+	*out = *in
+	if in.DexConfig != nil {
+		in, out := &in.DexConfig, &out.DexConfig
+		*out = new(DexConfig)
+		**out = **in
+	}
+	if in.RedhatSsoConfig != nil {
+		in, out := &in.RedhatSsoConfig, &out.RedhatSsoConfig
+		*out = new(RedhatSsoConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservatoriumIndex.
+func (in *ObservatoriumIndex) DeepCopy() *ObservatoriumIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservatoriumIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservatoriumRateLimit) DeepCopyInto(out *ObservatoriumRateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservatoriumRateLimit.
+func (in *ObservatoriumRateLimit) DeepCopy() *ObservatoriumRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservatoriumRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservatoriumTenant) DeepCopyInto(out *ObservatoriumTenant) {
+	*out = *in
+	if in.Registration != nil {
+		in, out := &in.Registration, &out.Registration
+		*out = new(ObservatoriumTenantRegistration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservatoriumTenant.
+func (in *ObservatoriumTenant) DeepCopy() *ObservatoriumTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservatoriumTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservatoriumTenantRegistration) DeepCopyInto(out *ObservatoriumTenantRegistration) {
+	*out = *in
+	if in.RateLimits != nil {
+		in, out := &in.RateLimits, &out.RateLimits
+		*out = make([]ObservatoriumRateLimit, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservatoriumTenantRegistration.
+func (in *ObservatoriumTenantRegistration) DeepCopy() *ObservatoriumTenantRegistration {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservatoriumTenantRegistration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyConfig) DeepCopyInto(out *PagerDutyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyConfig.
+func (in *PagerDutyConfig) DeepCopy() *PagerDutyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusAdapter) DeepCopyInto(out *PrometheusAdapter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusAdapter.
+func (in *PrometheusAdapter) DeepCopy() *PrometheusAdapter {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusAdapter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusIndex) DeepCopyInto(out *PrometheusIndex) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodMonitors != nil {
+		in, out := &in.PodMonitors, &out.PodMonitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitorLabelSelector != nil {
+		in, out := &in.PodMonitorLabelSelector, &out.PodMonitorLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitorNamespaceSelector != nil {
+		in, out := &in.PodMonitorNamespaceSelector, &out.PodMonitorNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitorLabelSelector != nil {
+		in, out := &in.ServiceMonitorLabelSelector, &out.ServiceMonitorLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitorNamespaceSelector != nil {
+		in, out := &in.ServiceMonitorNamespaceSelector, &out.ServiceMonitorNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleLabelSelector != nil {
+		in, out := &in.RuleLabelSelector, &out.RuleLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleNamespaceSelector != nil {
+		in, out := &in.RuleNamespaceSelector, &out.RuleNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeLabelSelector != nil {
+		in, out := &in.ProbeLabelSelector, &out.ProbeLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeNamespaceSelector != nil {
+		in, out := &in.ProbeNamespaceSelector, &out.ProbeNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleTests != nil {
+		in, out := &in.RuleTests, &out.RuleTests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusIndex.
+func (in *PrometheusIndex) DeepCopy() *PrometheusIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusQueryLogging) DeepCopyInto(out *PrometheusQueryLogging) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusQueryLogging.
+func (in *PrometheusQueryLogging) DeepCopy() *PrometheusQueryLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusQueryLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromtailClient) DeepCopyInto(out *PromtailClient) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromtailClient.
+func (in *PromtailClient) DeepCopy() *PromtailClient {
+	if in == nil {
+		return nil
+	}
+	out := new(PromtailClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromtailIndex) DeepCopyInto(out *PromtailIndex) {
+	*out = *in
+	if in.NamespaceLabelSelector != nil {
+		in, out := &in.NamespaceLabelSelector, &out.NamespaceLabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DaemonSetLabelSelector != nil {
+		in, out := &in.DaemonSetLabelSelector, &out.DaemonSetLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromtailIndex.
+func (in *PromtailIndex) DeepCopy() *PromtailIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(PromtailIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromtailLogs) DeepCopyInto(out *PromtailLogs) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostPaths != nil {
+		in, out := &in.HostPaths, &out.HostPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromtailLogs.
+func (in *PromtailLogs) DeepCopy() *PromtailLogs {
+	if in == nil {
+		return nil
+	}
+	out := new(PromtailLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrunedResource) DeepCopyInto(out *PrunedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrunedResource.
+func (in *PrunedResource) DeepCopy() *PrunedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PrunedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryProxy) DeepCopyInto(out *QueryProxy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryProxy.
+func (in *QueryProxy) DeepCopy() *QueryProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedhatSsoConfig) DeepCopyInto(out *RedhatSsoConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedhatSsoConfig.
+func (in *RedhatSsoConfig) DeepCopy() *RedhatSsoConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RedhatSsoConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReferencedSecret) DeepCopyInto(out *ReferencedSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReferencedSecret.
+func (in *ReferencedSecret) DeepCopy() *ReferencedSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ReferencedSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteWriteIndex) DeepCopyInto(out *RemoteWriteIndex) {
+	*out = *in
+	if in.QueueConfig != nil {
+		in, out := &in.QueueConfig, &out.QueueConfig
+		*out = new(monitoringv1.QueueConfig)
+		**out = **in
+	}
+	if in.WriteRelabelConfigs != nil {
+		in, out := &in.WriteRelabelConfigs, &out.WriteRelabelConfigs
+		*out = make([]monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteWriteIndex.
+func (in *RemoteWriteIndex) DeepCopy() *RemoteWriteIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteWriteIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Report) DeepCopyInto(out *Report) {
+	*out = *in
+	if in.Recipients != nil {
+		in, out := &in.Recipients, &out.Recipients
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Report.
+func (in *Report) DeepCopy() *Report {
+	if in == nil {
+		return nil
+	}
+	out := new(Report)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportStatus) DeepCopyInto(out *ReportStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportStatus.
+func (in *ReportStatus) DeepCopy() *ReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConfig) DeepCopyInto(out *RepositoryConfig) {
+	*out = *in
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(GrafanaIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alertmanager != nil {
+		in, out := &in.Alertmanager, &out.Alertmanager
+		*out = new(AlertmanagerIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Promtail != nil {
+		in, out := &in.Promtail, &out.Promtail
+		*out = new(PromtailIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(LokiIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Observatoria != nil {
+		in, out := &in.Observatoria, &out.Observatoria
+		*out = make([]ObservatoriumIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfig.
+func (in *RepositoryConfig) DeepCopy() *RepositoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryIndex) DeepCopyInto(out *RepositoryIndex) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(corev1.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(RepositoryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryIndex.
+func (in *RepositoryIndex) DeepCopy() *RepositoryIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryInfo) DeepCopyInto(out *RepositoryInfo) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(corev1.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryInfo.
+func (in *RepositoryInfo) DeepCopy() *RepositoryInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Recommended != nil {
+		in, out := &in.Recommended, &out.Recommended
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleDeduplication) DeepCopyInto(out *RuleDeduplication) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleDeduplication.
+func (in *RuleDeduplication) DeepCopy() *RuleDeduplication {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleDeduplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulePolicy) DeepCopyInto(out *RulePolicy) {
+	*out = *in
+	if in.RequiredLabels != nil {
+		in, out := &in.RequiredLabels, &out.RequiredLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedSeverities != nil {
+		in, out := &in.AllowedSeverities, &out.AllowedSeverities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SeverityAliases != nil {
+		in, out := &in.SeverityAliases, &out.SeverityAliases
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DefaultLabels != nil {
+		in, out := &in.DefaultLabels, &out.DefaultLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulePolicy.
+func (in *RulePolicy) DeepCopy() *RulePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RulePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulePolicyViolation) DeepCopyInto(out *RulePolicyViolation) {
+	*out = *in
+	if in.Violations != nil {
+		in, out := &in.Violations, &out.Violations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulePolicyViolation.
+func (in *RulePolicyViolation) DeepCopy() *RulePolicyViolation {
+	if in == nil {
+		return nil
+	}
+	out := new(RulePolicyViolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTestResult) DeepCopyInto(out *RuleTestResult) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTestResult.
+func (in *RuleTestResult) DeepCopy() *RuleTestResult {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTestResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfContained) DeepCopyInto(out *SelfContained) {
+	*out = *in
+	if in.DisableRepoSync != nil {
+		in, out := &in.DisableRepoSync, &out.DisableRepoSync
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableObservatorium != nil {
+		in, out := &in.DisableObservatorium, &out.DisableObservatorium
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisablePagerDuty != nil {
+		in, out := &in.DisablePagerDuty, &out.DisablePagerDuty
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableDeadmansSnitch != nil {
+		in, out := &in.DisableDeadmansSnitch, &out.DisableDeadmansSnitch
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableBlackboxExporter != nil {
+		in, out := &in.DisableBlackboxExporter, &out.DisableBlackboxExporter
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SelfSignedCerts != nil {
+		in, out := &in.SelfSignedCerts, &out.SelfSignedCerts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OverrideSelectors != nil {
+		in, out := &in.OverrideSelectors, &out.OverrideSelectors
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FederatedMetrics != nil {
+		in, out := &in.FederatedMetrics, &out.FederatedMetrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodMonitorLabelSelector != nil {
+		in, out := &in.PodMonitorLabelSelector, &out.PodMonitorLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitorNamespaceSelector != nil {
 		in, out := &in.PodMonitorNamespaceSelector, &out.PodMonitorNamespaceSelector
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
@@ -398,299 +3151,447 @@ func (in *PrometheusIndex) DeepCopyInto(out *PrometheusIndex) {
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GrafanaDashboardLabelSelector != nil {
+		in, out := &in.GrafanaDashboardLabelSelector, &out.GrafanaDashboardLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.AlertManagerResourceRequirement.DeepCopyInto(&out.AlertManagerResourceRequirement)
+	in.PrometheusResourceRequirement.DeepCopyInto(&out.PrometheusResourceRequirement)
+	in.PrometheusOperatorResourceRequirement.DeepCopyInto(&out.PrometheusOperatorResourceRequirement)
+	if in.GrafanaResourceRequirement != nil {
+		in, out := &in.GrafanaResourceRequirement, &out.GrafanaResourceRequirement
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	in.GrafanaOperatorResourceRequirement.DeepCopyInto(&out.GrafanaOperatorResourceRequirement)
+	if in.PrometheusStorageClass != nil {
+		in, out := &in.PrometheusStorageClass, &out.PrometheusStorageClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.PrometheusQueryMaxSamples != nil {
+		in, out := &in.PrometheusQueryMaxSamples, &out.PrometheusQueryMaxSamples
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PrometheusWALCompression != nil {
+		in, out := &in.PrometheusWALCompression, &out.PrometheusWALCompression
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UIAccess != nil {
+		in, out := &in.UIAccess, &out.UIAccess
+		*out = new(UIAccess)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AlertManagerPeers != nil {
+		in, out := &in.AlertManagerPeers, &out.AlertManagerPeers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(PromtailLogs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusShards != nil {
+		in, out := &in.PrometheusShards, &out.PrometheusShards
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueryLogging != nil {
+		in, out := &in.QueryLogging, &out.QueryLogging
+		*out = new(PrometheusQueryLogging)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfContained.
+func (in *SelfContained) DeepCopy() *SelfContained {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfContained)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfMonitoring) DeepCopyInto(out *SelfMonitoring) {
+	*out = *in
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfMonitoring.
+func (in *SelfMonitoring) DeepCopy() *SelfMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackVerificationCheck) DeepCopyInto(out *StackVerificationCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackVerificationCheck.
+func (in *StackVerificationCheck) DeepCopy() *StackVerificationCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(StackVerificationCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackVerificationSpec) DeepCopyInto(out *StackVerificationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackVerificationSpec.
+func (in *StackVerificationSpec) DeepCopy() *StackVerificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StackVerificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackVerificationStatus) DeepCopyInto(out *StackVerificationStatus) {
+	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]StackVerificationCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackVerificationStatus.
+func (in *StackVerificationStatus) DeepCopy() *StackVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StackVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Storage) DeepCopyInto(out *Storage) {
+	*out = *in
+	if in.PrometheusStorageSpec != nil {
+		in, out := &in.PrometheusStorageSpec, &out.PrometheusStorageSpec
+		*out = new(monitoringv1.StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Storage.
+func (in *Storage) DeepCopy() *Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(Storage)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusIndex.
-func (in *PrometheusIndex) DeepCopy() *PrometheusIndex {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatus.
+func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PrometheusIndex)
+	out := new(SubscriptionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromtailIndex) DeepCopyInto(out *PromtailIndex) {
+func (in *TLS) DeepCopyInto(out *TLS) {
 	*out = *in
-	if in.NamespaceLabelSelector != nil {
-		in, out := &in.NamespaceLabelSelector, &out.NamespaceLabelSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.DaemonSetLabelSelector != nil {
-		in, out := &in.DaemonSetLabelSelector, &out.DaemonSetLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromtailIndex.
-func (in *PromtailIndex) DeepCopy() *PromtailIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLS.
+func (in *TLS) DeepCopy() *TLS {
 	if in == nil {
 		return nil
 	}
-	out := new(PromtailIndex)
+	out := new(TLS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RedhatSsoConfig) DeepCopyInto(out *RedhatSsoConfig) {
+func (in *TenantQuota) DeepCopyInto(out *TenantQuota) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedhatSsoConfig.
-func (in *RedhatSsoConfig) DeepCopy() *RedhatSsoConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuota.
+func (in *TenantQuota) DeepCopy() *TenantQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(RedhatSsoConfig)
+	out := new(TenantQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemoteWriteIndex) DeepCopyInto(out *RemoteWriteIndex) {
+func (in *TenantQuotaStatus) DeepCopyInto(out *TenantQuotaStatus) {
 	*out = *in
-	if in.QueueConfig != nil {
-		in, out := &in.QueueConfig, &out.QueueConfig
-		*out = new(monitoringv1.QueueConfig)
-		**out = **in
-	}
-	if in.WriteRelabelConfigs != nil {
-		in, out := &in.WriteRelabelConfigs, &out.WriteRelabelConfigs
-		*out = make([]monitoringv1.RelabelConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteWriteIndex.
-func (in *RemoteWriteIndex) DeepCopy() *RemoteWriteIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuotaStatus.
+func (in *TenantQuotaStatus) DeepCopy() *TenantQuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RemoteWriteIndex)
+	out := new(TenantQuotaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryConfig) DeepCopyInto(out *RepositoryConfig) {
+func (in *TokenRefresher) DeepCopyInto(out *TokenRefresher) {
 	*out = *in
-	if in.Grafana != nil {
-		in, out := &in.Grafana, &out.Grafana
-		*out = new(GrafanaIndex)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Prometheus != nil {
-		in, out := &in.Prometheus, &out.Prometheus
-		*out = new(PrometheusIndex)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Alertmanager != nil {
-		in, out := &in.Alertmanager, &out.Alertmanager
-		*out = new(AlertmanagerIndex)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Promtail != nil {
-		in, out := &in.Promtail, &out.Promtail
-		*out = new(PromtailIndex)
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(TokenRefresherAutoscaling)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Observatoria != nil {
-		in, out := &in.Observatoria, &out.Observatoria
-		*out = make([]ObservatoriumIndex, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(TokenRefresherBackoff)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfig.
-func (in *RepositoryConfig) DeepCopy() *RepositoryConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenRefresher.
+func (in *TokenRefresher) DeepCopy() *TokenRefresher {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryConfig)
+	out := new(TokenRefresher)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryIndex) DeepCopyInto(out *RepositoryIndex) {
+func (in *TokenRefresherAutoscaling) DeepCopyInto(out *TokenRefresherAutoscaling) {
 	*out = *in
-	if in.Source != nil {
-		in, out := &in.Source, &out.Source
-		*out = new(corev1.Secret)
-		(*in).DeepCopyInto(*out)
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
 	}
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = new(RepositoryConfig)
-		(*in).DeepCopyInto(*out)
+	if in.TargetCPUUtilization != nil {
+		in, out := &in.TargetCPUUtilization, &out.TargetCPUUtilization
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryIndex.
-func (in *RepositoryIndex) DeepCopy() *RepositoryIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenRefresherAutoscaling.
+func (in *TokenRefresherAutoscaling) DeepCopy() *TokenRefresherAutoscaling {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryIndex)
+	out := new(TokenRefresherAutoscaling)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryInfo) DeepCopyInto(out *RepositoryInfo) {
+func (in *TokenRefresherBackoff) DeepCopyInto(out *TokenRefresherBackoff) {
 	*out = *in
-	if in.Source != nil {
-		in, out := &in.Source, &out.Source
-		*out = new(corev1.Secret)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryInfo.
-func (in *RepositoryInfo) DeepCopy() *RepositoryInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenRefresherBackoff.
+func (in *TokenRefresherBackoff) DeepCopy() *TokenRefresherBackoff {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryInfo)
+	out := new(TokenRefresherBackoff)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SelfContained) DeepCopyInto(out *SelfContained) {
+func (in *Tracing) DeepCopyInto(out *Tracing) {
 	*out = *in
-	if in.DisableRepoSync != nil {
-		in, out := &in.DisableRepoSync, &out.DisableRepoSync
-		*out = new(bool)
-		**out = **in
-	}
-	if in.DisableObservatorium != nil {
-		in, out := &in.DisableObservatorium, &out.DisableObservatorium
-		*out = new(bool)
-		**out = **in
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(TracingStorage)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.DisablePagerDuty != nil {
-		in, out := &in.DisablePagerDuty, &out.DisablePagerDuty
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tracing.
+func (in *Tracing) DeepCopy() *Tracing {
+	if in == nil {
+		return nil
 	}
-	if in.DisableDeadmansSnitch != nil {
-		in, out := &in.DisableDeadmansSnitch, &out.DisableDeadmansSnitch
-		*out = new(bool)
+	out := new(Tracing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TracingStorage) DeepCopyInto(out *TracingStorage) {
+	*out = *in
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
 		**out = **in
 	}
-	if in.DisableBlackboxExporter != nil {
-		in, out := &in.DisableBlackboxExporter, &out.DisableBlackboxExporter
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingStorage.
+func (in *TracingStorage) DeepCopy() *TracingStorage {
+	if in == nil {
+		return nil
 	}
-	if in.SelfSignedCerts != nil {
-		in, out := &in.SelfSignedCerts, &out.SelfSignedCerts
-		*out = new(bool)
+	out := new(TracingStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UIAccess) DeepCopyInto(out *UIAccess) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
 		**out = **in
 	}
-	if in.OverrideSelectors != nil {
-		in, out := &in.OverrideSelectors, &out.OverrideSelectors
-		*out = new(bool)
-		**out = **in
+	if in.IngressAnnotations != nil {
+		in, out := &in.IngressAnnotations, &out.IngressAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.FederatedMetrics != nil {
-		in, out := &in.FederatedMetrics, &out.FederatedMetrics
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.PodMonitorLabelSelector != nil {
-		in, out := &in.PodMonitorLabelSelector, &out.PodMonitorLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PodMonitorNamespaceSelector != nil {
-		in, out := &in.PodMonitorNamespaceSelector, &out.PodMonitorNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ServiceMonitorLabelSelector != nil {
-		in, out := &in.ServiceMonitorLabelSelector, &out.ServiceMonitorLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ServiceMonitorNamespaceSelector != nil {
-		in, out := &in.ServiceMonitorNamespaceSelector, &out.ServiceMonitorNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(UIAccessOIDC)
+		**out = **in
 	}
-	if in.RuleLabelSelector != nil {
-		in, out := &in.RuleLabelSelector, &out.RuleLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UIAccess.
+func (in *UIAccess) DeepCopy() *UIAccess {
+	if in == nil {
+		return nil
 	}
-	if in.RuleNamespaceSelector != nil {
-		in, out := &in.RuleNamespaceSelector, &out.RuleNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	out := new(UIAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UIAccessOIDC) DeepCopyInto(out *UIAccessOIDC) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UIAccessOIDC.
+func (in *UIAccessOIDC) DeepCopy() *UIAccessOIDC {
+	if in == nil {
+		return nil
 	}
-	if in.ProbeLabelSelector != nil {
-		in, out := &in.ProbeLabelSelector, &out.ProbeLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	out := new(UIAccessOIDC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeWindow) DeepCopyInto(out *UpgradeWindow) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]UpgradeWindowRange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.ProbeNamespaceSelector != nil {
-		in, out := &in.ProbeNamespaceSelector, &out.ProbeNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.Freezes != nil {
+		in, out := &in.Freezes, &out.Freezes
+		*out = make([]ChangeFreeze, len(*in))
+		copy(*out, *in)
 	}
-	if in.GrafanaDashboardLabelSelector != nil {
-		in, out := &in.GrafanaDashboardLabelSelector, &out.GrafanaDashboardLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeWindow.
+func (in *UpgradeWindow) DeepCopy() *UpgradeWindow {
+	if in == nil {
+		return nil
 	}
-	in.AlertManagerResourceRequirement.DeepCopyInto(&out.AlertManagerResourceRequirement)
-	in.PrometheusResourceRequirement.DeepCopyInto(&out.PrometheusResourceRequirement)
-	in.PrometheusOperatorResourceRequirement.DeepCopyInto(&out.PrometheusOperatorResourceRequirement)
-	if in.GrafanaResourceRequirement != nil {
-		in, out := &in.GrafanaResourceRequirement, &out.GrafanaResourceRequirement
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	out := new(UpgradeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeWindowRange) DeepCopyInto(out *UpgradeWindowRange) {
+	*out = *in
+	if in.Weekdays != nil {
+		in, out := &in.Weekdays, &out.Weekdays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	in.GrafanaOperatorResourceRequirement.DeepCopyInto(&out.GrafanaOperatorResourceRequirement)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfContained.
-func (in *SelfContained) DeepCopy() *SelfContained {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeWindowRange.
+func (in *UpgradeWindowRange) DeepCopy() *UpgradeWindowRange {
 	if in == nil {
 		return nil
 	}
-	out := new(SelfContained)
+	out := new(UpgradeWindowRange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Storage) DeepCopyInto(out *Storage) {
+func (in *UserWorkloadMonitoring) DeepCopyInto(out *UserWorkloadMonitoring) {
 	*out = *in
-	if in.PrometheusStorageSpec != nil {
-		in, out := &in.PrometheusStorageSpec, &out.PrometheusStorageSpec
-		*out = new(monitoringv1.StorageSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Storage.
-func (in *Storage) DeepCopy() *Storage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserWorkloadMonitoring.
+func (in *UserWorkloadMonitoring) DeepCopy() *UserWorkloadMonitoring {
 	if in == nil {
 		return nil
 	}
-	out := new(Storage)
+	out := new(UserWorkloadMonitoring)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -698,6 +3599,11 @@ func (in *Storage) DeepCopy() *Storage {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
 	*out = *in
+	if in.HttpConfig != nil {
+		in, out := &in.HttpConfig, &out.HttpConfig
+		*out = new(HttpConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfig.
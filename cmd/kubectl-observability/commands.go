@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func formatTime(unix int64) string {
+	if unix == 0 {
+		return "never"
+	}
+	t := time.Unix(unix, 0)
+	return fmt.Sprintf("%v (%v ago)", t.UTC().Format(time.RFC3339), time.Since(t).Round(time.Second))
+}
+
+func runStatus(ctx context.Context, o *options) error {
+	cr, err := getObservability(ctx, o)
+	if err != nil {
+		return err
+	}
+	s := cr.Status
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Observability:\t%v/%v\n", cr.Namespace, cr.Name)
+	fmt.Fprintf(w, "Stage:\t%v (%v)\n", s.Stage, s.StageStatus)
+	if s.LastMessage != "" {
+		fmt.Fprintf(w, "Last message:\t%v\n", s.LastMessage)
+	}
+	fmt.Fprintf(w, "Last synced:\t%v\n", formatTime(s.LastSynced))
+	fmt.Fprintf(w, "Config applied:\t%v\n", formatTime(s.ConfigApplied))
+	for _, progress := range s.ConfigApplyProgress {
+		if progress.Applied < progress.Total {
+			fmt.Fprintf(w, "Config apply:\t%v %v/%v\n", progress.Kind, progress.Applied, progress.Total)
+		}
+	}
+	if s.ConfigSnapshot != "" {
+		fmt.Fprintf(w, "Config snapshot:\t%v\n", s.ConfigSnapshot)
+	}
+	if s.ConfigRollback != "" {
+		fmt.Fprintf(w, "Rolled back to:\t%v\n", s.ConfigRollback)
+	}
+	for _, revision := range s.ConfigRevisions {
+		ref := revision.Revision
+		if ref == "" {
+			ref = "default branch"
+		}
+		fmt.Fprintf(w, "Config revision:\t%v at %v (%v)\n", revision.Name, ref, revision.Hash)
+	}
+	for _, version := range s.ComponentVersions {
+		versions := strings.Join(version.Versions, ", ")
+		if version.Skew != "" {
+			versions = fmt.Sprintf("%v (%v)", versions, version.Skew)
+		}
+		fmt.Fprintf(w, "Version of %v:\t%v\n", version.Name, versions)
+	}
+	if paused := cr.Annotations[controllers.PausedAnnotation]; paused != "" {
+		fmt.Fprintf(w, "Paused:\t%v\n", paused)
+	}
+	if stages := cr.Annotations[controllers.PausedStagesAnnotation]; stages != "" {
+		fmt.Fprintf(w, "Paused stages:\t%v\n", stages)
+	}
+	if len(s.MissingAPIs) > 0 {
+		fmt.Fprintf(w, "Missing APIs:\t%v\n", strings.Join(s.MissingAPIs, ", "))
+	}
+	if len(s.Drift) > 0 {
+		fmt.Fprintf(w, "Drifted resources:\t%v\n", len(s.Drift))
+	}
+	w.Flush()
+
+	if len(s.Conditions) == 0 {
+		return nil
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CONDITION\tSTATUS\tREASON\tSINCE\tMESSAGE")
+	for _, condition := range s.Conditions {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", condition.Type, condition.Status, condition.Reason,
+			time.Since(condition.LastTransitionTime.Time).Round(time.Second), condition.Message)
+	}
+	return w.Flush()
+}
+
+// The diagnostics endpoint authenticates the callers with their bearer token
+func runStages(ctx context.Context, o *options) error {
+	if o.diagnosticsURL == "" {
+		return fmt.Errorf("stages requires --diagnostics-url")
+	}
+	token := o.token
+	if token == "" {
+		token = o.config.BearerToken
+	}
+	if token == "" {
+		return fmt.Errorf("the kubeconfig has no bearer token, stages requires --token")
+	}
+
+	endpoint := fmt.Sprintf("%v/debug/reconciles?namespace=%v", strings.TrimSuffix(o.diagnosticsURL, "/"), url.QueryEscape(o.namespace))
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("diagnostics endpoint returned %v: %v", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var dump struct {
+		Queue      controllers.QueueDiagnostics `json:"queue"`
+		Reconciles []controllers.CRDiagnostics  `json:"reconciles"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&dump)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Work queue depth %v, unfinished work %vs\n", dump.Queue.Depth, dump.Queue.UnfinishedWorkSeconds)
+	for _, cr := range dump.Reconciles {
+		if len(o.args) > 0 && cr.Name != o.args[0] {
+			continue
+		}
+		fmt.Printf("\n%v/%v: %v completed runs", cr.Namespace, cr.Name, cr.CompletedRuns)
+		if cr.Running {
+			fmt.Printf(", running %v since %v", cr.Stage, time.Since(cr.LastStarted).Round(time.Second))
+		}
+		fmt.Println()
+
+		// Stages in the order they ran
+		var stages []apiv1.ObservabilityStageName
+		for stage := range cr.Stages {
+			stages = append(stages, stage)
+		}
+		sort.Slice(stages, func(i, j int) bool {
+			return cr.Stages[stages[i]].LastRun.Before(cr.Stages[stages[j]].LastRun)
+		})
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "STAGE\tSTATUS\tLAST RUN\tDURATION\tLAST ERROR")
+		for _, stage := range stages {
+			d := cr.Stages[stage]
+			lastError := d.LastError
+			if d.LastErrorTime != nil {
+				lastError = fmt.Sprintf("%v ago: %v", time.Since(*d.LastErrorTime).Round(time.Second), d.LastError)
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v ago\t%v\t%v\n", stage, d.Status, time.Since(d.LastRun).Round(time.Second), d.Duration, lastError)
+		}
+		w.Flush()
+	}
+	return nil
+}
+
+func runResync(ctx context.Context, o *options) error {
+	cr, err := getObservability(ctx, o)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(cr.DeepCopy())
+	setAnnotation(cr, configuration.ResyncAnnotation, time.Now().UTC().Format(time.RFC3339))
+	err = o.client.Patch(ctx, cr, patch)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("resync of %v/%v requested\n", cr.Namespace, cr.Name)
+	return nil
+}
+
+func runPause(ctx context.Context, o *options) error {
+	cr, err := getObservability(ctx, o)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(cr.DeepCopy())
+	if o.stages != "" {
+		setAnnotation(cr, controllers.PausedStagesAnnotation, o.stages)
+	} else {
+		setAnnotation(cr, controllers.PausedAnnotation, "true")
+	}
+	err = o.client.Patch(ctx, cr, patch)
+	if err != nil {
+		return err
+	}
+	if o.stages != "" {
+		fmt.Printf("stages %v of %v/%v paused\n", o.stages, cr.Namespace, cr.Name)
+	} else {
+		fmt.Printf("%v/%v paused\n", cr.Namespace, cr.Name)
+	}
+	return nil
+}
+
+func runResume(ctx context.Context, o *options) error {
+	cr, err := getObservability(ctx, o)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(cr.DeepCopy())
+	delete(cr.Annotations, controllers.PausedAnnotation)
+	delete(cr.Annotations, controllers.PausedStagesAnnotation)
+	err = o.client.Patch(ctx, cr, patch)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v/%v resumed\n", cr.Namespace, cr.Name)
+	return nil
+}
+
+func setAnnotation(cr *apiv1.Observability, key string, value string) {
+	if cr.Annotations == nil {
+		cr.Annotations = map[string]string{}
+	}
+	cr.Annotations[key] = value
+}
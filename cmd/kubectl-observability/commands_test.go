@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRunStatus(t *testing.T) {
+	cr := newObservability("stack")
+	cr.Annotations = map[string]string{controllers.PausedStagesAnnotation: "Grafana"}
+	cr.Status = apiv1.ObservabilityStatus{
+		Stage:          apiv1.Configuration,
+		StageStatus:    apiv1.ResultFailed,
+		LastMessage:    "repository unreachable",
+		ConfigSnapshot: "abc",
+		ConfigRevisions: []apiv1.ConfigRevision{
+			{Name: "staging", Hash: "1234"},
+			{Name: "production", Revision: "v1.2.0", Hash: "5678"},
+		},
+		MissingAPIs: []string{"monitoring.coreos.com/v1"},
+	}
+
+	output, err := captureOutput(t, func() error {
+		return runStatus(context.Background(), newOptions(nil, cr))
+	})
+	if err != nil {
+		t.Fatalf("runStatus() error = %v", err)
+	}
+	// Lines without the padding of the columns
+	lines := map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		lines[strings.Join(strings.Fields(line), " ")] = true
+	}
+	for _, want := range []string{
+		"Observability: observability/stack",
+		"Stage: configuration (failed)",
+		"Last message: repository unreachable",
+		"Last synced: never",
+		"Config snapshot: abc",
+		"Config revision: staging at default branch (1234)",
+		"Config revision: production at v1.2.0 (5678)",
+		"Paused stages: Grafana",
+		"Missing APIs: monitoring.coreos.com/v1",
+	} {
+		if !lines[want] {
+			t.Errorf("runStatus() output has no line %q:\n%v", want, output)
+		}
+	}
+}
+
+func TestRunStages(t *testing.T) {
+	lastRun := time.Now().Add(-time.Minute)
+	lastError := lastRun.Add(-time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("invalid token\n"))
+			return
+		}
+		if r.URL.Path != "/debug/reconciles" || r.URL.Query().Get("namespace") != namespace {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"queue": controllers.QueueDiagnostics{Depth: 2, UnfinishedWorkSeconds: 1.5},
+			"reconciles": []controllers.CRDiagnostics{
+				{
+					Namespace:     namespace,
+					Name:          "stack",
+					CompletedRuns: 3,
+					Stages: map[apiv1.ObservabilityStageName]*controllers.StageDiagnostics{
+						apiv1.Configuration: {Status: apiv1.ResultSuccess, LastRun: lastRun, Duration: "2s"},
+						apiv1.GrafanaInstallation: {Status: apiv1.ResultFailed, LastRun: lastRun.Add(-time.Second), Duration: "1s",
+							LastError: "deployment not ready", LastErrorTime: &lastError},
+					},
+				},
+				{Namespace: namespace, Name: "other", CompletedRuns: 1},
+			},
+		})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		o          *options
+		wantErr    string
+		wantOutput []string
+	}{
+		{
+			name: "stages of the CR",
+			o: &options{namespace: namespace, diagnosticsURL: server.URL + "/", config: &rest.Config{BearerToken: "token"},
+				args: []string{"stack"}},
+			wantOutput: []string{
+				"Work queue depth 2, unfinished work 1.5s\n",
+				"\nobservability/stack: 3 completed runs\n",
+				"Grafana        failed   1m1s ago  1s        1h1m0s ago: deployment not ready\n" +
+					"configuration  success  1m0s ago  2s        \n",
+			},
+		},
+		{
+			name:       "token of the flag",
+			o:          &options{namespace: namespace, diagnosticsURL: server.URL, config: &rest.Config{}, token: "token"},
+			wantOutput: []string{"observability/stack: 3 completed runs\n", "observability/other: 1 completed runs\n"},
+		},
+		{
+			name:    "without the diagnostics endpoint",
+			o:       &options{namespace: namespace, config: &rest.Config{BearerToken: "token"}},
+			wantErr: "stages requires --diagnostics-url",
+		},
+		{
+			name:    "without a token",
+			o:       &options{namespace: namespace, diagnosticsURL: server.URL, config: &rest.Config{}},
+			wantErr: "the kubeconfig has no bearer token, stages requires --token",
+		},
+		{
+			name:    "rejected token",
+			o:       &options{namespace: namespace, diagnosticsURL: server.URL, token: "wrong"},
+			wantErr: "diagnostics endpoint returned 401 Unauthorized: invalid token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := captureOutput(t, func() error {
+				return runStages(context.Background(), tt.o)
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("runStages() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runStages() error = %v", err)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(output, want) {
+					t.Errorf("runStages() output has no %q:\n%v", want, output)
+				}
+			}
+			if len(tt.o.args) > 0 && strings.Contains(output, "other") {
+				t.Errorf("runStages() output has stages of other CRs:\n%v", output)
+			}
+		})
+	}
+}
+
+// Client that records the merge patches, the fake client does not remove the fields set to null
+type patchRecorder struct {
+	client.Client
+	patches [][]byte
+}
+
+func (c *patchRecorder) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	c.patches = append(c.patches, data)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestAnnotationCommands(t *testing.T) {
+	tests := []struct {
+		name        string
+		run         command
+		stages      string
+		annotations map[string]string
+		// Annotations set by the patch, resync sets the current time
+		wantSet     map[string]string
+		wantRemoved []string
+		wantOutput  string
+	}{
+		{
+			name:       "pause",
+			run:        runPause,
+			wantSet:    map[string]string{controllers.PausedAnnotation: "true"},
+			wantOutput: "observability/stack paused\n",
+		},
+		{
+			name:       "pause stages",
+			run:        runPause,
+			stages:     "Grafana,Prometheus",
+			wantSet:    map[string]string{controllers.PausedStagesAnnotation: "Grafana,Prometheus"},
+			wantOutput: "stages Grafana,Prometheus of observability/stack paused\n",
+		},
+		{
+			name: "resume",
+			run:  runResume,
+			annotations: map[string]string{
+				controllers.PausedAnnotation:       "true",
+				controllers.PausedStagesAnnotation: "Grafana",
+				"other":                            "kept",
+			},
+			wantRemoved: []string{controllers.PausedAnnotation, controllers.PausedStagesAnnotation},
+			wantOutput:  "observability/stack resumed\n",
+		},
+		{
+			name:       "resync",
+			run:        runResync,
+			wantSet:    map[string]string{configuration.ResyncAnnotation: ""},
+			wantOutput: "resync of observability/stack requested\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := newObservability("stack")
+			cr.Annotations = tt.annotations
+			o := newOptions(nil, cr)
+			o.stages = tt.stages
+			recorder := &patchRecorder{Client: o.client}
+			o.client = recorder
+
+			output, err := captureOutput(t, func() error {
+				return tt.run(context.Background(), o)
+			})
+			if err != nil {
+				t.Fatalf("%v error = %v", tt.name, err)
+			}
+			if output != tt.wantOutput {
+				t.Errorf("%v output = %q, want %q", tt.name, output, tt.wantOutput)
+			}
+			if len(recorder.patches) != 1 {
+				t.Fatalf("%v patches = %v, want 1", tt.name, len(recorder.patches))
+			}
+
+			var patch struct {
+				Metadata struct {
+					Annotations map[string]*string `json:"annotations"`
+				} `json:"metadata"`
+			}
+			err = json.Unmarshal(recorder.patches[0], &patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			annotations := patch.Metadata.Annotations
+			if len(annotations) != len(tt.wantSet)+len(tt.wantRemoved) {
+				t.Errorf("%v patch = %s", tt.name, recorder.patches[0])
+			}
+			for key, want := range tt.wantSet {
+				value := annotations[key]
+				switch {
+				case value == nil:
+					t.Errorf("%v patch = %s, want %v set", tt.name, recorder.patches[0], key)
+				case key == configuration.ResyncAnnotation:
+					if _, err := time.Parse(time.RFC3339, *value); err != nil {
+						t.Errorf("%v patch = %s, want the time of the resync", tt.name, recorder.patches[0])
+					}
+				case *value != want:
+					t.Errorf("%v patch = %s, want %v=%v", tt.name, recorder.patches[0], key, want)
+				}
+			}
+			for _, key := range tt.wantRemoved {
+				if value, ok := annotations[key]; !ok || value != nil {
+					t.Errorf("%v patch = %s, want %v removed", tt.name, recorder.patches[0], key)
+				}
+			}
+		})
+	}
+}
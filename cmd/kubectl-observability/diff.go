@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Lines of unchanged content printed around a change
+const diffContext = 3
+
+// Compares two config snapshots, given by their ids. Without ids the snapshot applied by the last
+// sync is compared to the snapshot before it
+func runDiff(ctx context.Context, o *options) error {
+	list := &v1.SecretList{}
+	err := o.client.List(ctx, list, &client.ListOptions{
+		LabelSelector: configuration.GetConfigSnapshotSelector(),
+		Namespace:     o.namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Oldest first
+	snapshots := list.Items
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreationTimestamp.Before(&snapshots[j].CreationTimestamp)
+	})
+	find := func(id string) (int, error) {
+		for i, secret := range snapshots {
+			if secret.Name == configuration.ConfigSnapshotPrefix+id {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("config snapshot %v does not exist", id)
+	}
+
+	var from, to int
+	switch len(o.args) {
+	case 0:
+		cr, err := getObservability(ctx, o)
+		if err != nil {
+			return err
+		}
+		if cr.Status.ConfigSnapshot == "" {
+			return fmt.Errorf("%v/%v has not applied a config snapshot", cr.Namespace, cr.Name)
+		}
+		to, err = find(cr.Status.ConfigSnapshot)
+		if err != nil {
+			return err
+		}
+		if to == 0 {
+			return fmt.Errorf("config snapshot %v has no predecessor", cr.Status.ConfigSnapshot)
+		}
+		from = to - 1
+	case 2:
+		from, err = find(o.args[0])
+		if err != nil {
+			return err
+		}
+		to, err = find(o.args[1])
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("diff requires no or two snapshot ids")
+	}
+
+	before, err := configuration.GetConfigSnapshotResources(&snapshots[from])
+	if err != nil {
+		return err
+	}
+	after, err := configuration.GetConfigSnapshotResources(&snapshots[to])
+	if err != nil {
+		return err
+	}
+
+	fromId := strings.TrimPrefix(snapshots[from].Name, configuration.ConfigSnapshotPrefix)
+	toId := strings.TrimPrefix(snapshots[to].Name, configuration.ConfigSnapshotPrefix)
+	keys := map[string]bool{}
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	changed := 0
+	for _, key := range sorted {
+		old, hadOld := before[key]
+		current, hasCurrent := after[key]
+		if hadOld && hasCurrent && string(old) == string(current) {
+			continue
+		}
+		changed++
+		fmt.Printf("--- %v %v\n+++ %v %v\n", fromId, key, toId, key)
+		fmt.Print(diffLines(string(old), string(current)))
+	}
+	if changed == 0 {
+		fmt.Printf("config snapshots %v and %v are identical\n", fromId, toId)
+	}
+	return nil
+}
+
+// Returns the changed block of the content with some context. The unchanged lines at the start and
+// the end are skipped, which is enough to spot what changed in a resource without a full diff
+func diffLines(old string, current string) string {
+	a := splitLines(old)
+	b := splitLines(current)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	start := prefix - diffContext
+	if start < 0 {
+		start = 0
+	}
+	endA := len(a) - suffix
+	endB := len(b) - suffix
+	trailing := suffix
+	if trailing > diffContext {
+		trailing = diffContext
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "@@ -%v,%v +%v,%v @@\n", start+1, endA+trailing-start, start+1, endB+trailing-start)
+	for _, line := range a[start:prefix] {
+		fmt.Fprintf(&result, " %v\n", line)
+	}
+	for _, line := range a[prefix:endA] {
+		fmt.Fprintf(&result, "-%v\n", line)
+	}
+	for _, line := range b[prefix:endB] {
+		fmt.Fprintf(&result, "+%v\n", line)
+	}
+	for _, line := range a[endA : endA+trailing] {
+		fmt.Fprintf(&result, " %v\n", line)
+	}
+	return result.String()
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Config snapshot secret with the given resources, created the given minutes after the first one
+func newSnapshot(t *testing.T, id string, minutes int, resources map[string]string) *corev1.Secret {
+	snapshot := map[string]map[string][]byte{"resources": {}}
+	for key, content := range resources {
+		snapshot["resources"][key] = []byte(content)
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err = writer.Write(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              configuration.ConfigSnapshotPrefix + id,
+			Namespace:         namespace,
+			Labels:            map[string]string{"managed-by": "observability-operator", "purpose": "config-snapshot"},
+			CreationTimestamp: metav1.NewTime(time.Date(2021, 8, 1, 0, minutes, 0, 0, time.UTC)),
+		},
+		Data: map[string][]byte{configuration.ConfigSnapshotKey: compressed.Bytes()},
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	rules := "groups:\n- name: a\n- name: b\n"
+	snapshots := []runtime.Object{
+		newSnapshot(t, "second", 1, map[string]string{"repo/rules.yaml": rules + "- name: c\n", "repo/new.yaml": "new\n"}),
+		newSnapshot(t, "first", 0, map[string]string{"repo/rules.yaml": rules, "repo/removed.yaml": "removed\n"}),
+		newSnapshot(t, "copy", 2, map[string]string{"repo/rules.yaml": rules, "repo/removed.yaml": "removed\n"}),
+	}
+	applied := func(snapshot string) *runtime.Object {
+		cr := newObservability("stack")
+		cr.Status.ConfigSnapshot = snapshot
+		var object runtime.Object = cr
+		return &object
+	}
+	changes := "--- first repo/new.yaml\n+++ second repo/new.yaml\n@@ -1,0 +1,1 @@\n+new\n" +
+		"--- first repo/removed.yaml\n+++ second repo/removed.yaml\n@@ -1,1 +1,0 @@\n-removed\n" +
+		"--- first repo/rules.yaml\n+++ second repo/rules.yaml\n@@ -1,3 +1,4 @@\n groups:\n - name: a\n - name: b\n+- name: c\n"
+
+	tests := []struct {
+		name       string
+		args       []string
+		cr         *runtime.Object
+		wantErr    string
+		wantOutput string
+	}{
+		{
+			name:       "applied snapshot and its predecessor",
+			cr:         applied("second"),
+			wantOutput: changes,
+		},
+		{
+			name:       "snapshots of the arguments",
+			args:       []string{"first", "second"},
+			wantOutput: changes,
+		},
+		{
+			name:       "identical snapshots",
+			args:       []string{"first", "copy"},
+			wantOutput: "config snapshots first and copy are identical\n",
+		},
+		{
+			name:    "first snapshot applied",
+			cr:      applied("first"),
+			wantErr: "config snapshot first has no predecessor",
+		},
+		{
+			name:    "no snapshot applied",
+			cr:      applied(""),
+			wantErr: "observability/stack has not applied a config snapshot",
+		},
+		{
+			name:    "unknown snapshot",
+			args:    []string{"first", "third"},
+			wantErr: "config snapshot third does not exist",
+		},
+		{
+			name:    "single snapshot",
+			args:    []string{"first"},
+			wantErr: "diff requires no or two snapshot ids",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := snapshots
+			if tt.cr != nil {
+				objects = append([]runtime.Object{*tt.cr}, snapshots...)
+			}
+			output, err := captureOutput(t, func() error {
+				return runDiff(context.Background(), newOptions(tt.args, objects...))
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("runDiff() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runDiff() error = %v", err)
+			}
+			if output != tt.wantOutput {
+				t.Errorf("runDiff() output =\n%v\nwant\n%v", output, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		current string
+		want    string
+	}{
+		{
+			name:    "changed line with context",
+			old:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			current: "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want:    "@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:    "change at the start",
+			old:     "1\n2\n",
+			current: "one\n2\n",
+			want:    "@@ -1,2 +1,2 @@\n-1\n+one\n 2\n",
+		},
+		{
+			name:    "appended lines",
+			old:     "1\n2\n",
+			current: "1\n2\n3\n4\n",
+			want:    "@@ -1,2 +1,4 @@\n 1\n 2\n+3\n+4\n",
+		},
+		{
+			name:    "new content",
+			current: "1\n",
+			want:    "@@ -1,0 +1,1 @@\n+1\n",
+		},
+		{
+			name: "removed content",
+			old:  "1\n2",
+			want: "@@ -1,2 +1,0 @@\n-1\n-2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.old, tt.current); got != tt.want {
+				t.Errorf("diffLines() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-observability inspects and troubleshoots the stacks of the operator. Installed on the
+// PATH it is run as kubectl observability <command>.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const usage = `Inspect and troubleshoot the stacks of the observability operator.
+
+Usage:
+  kubectl observability <command> [flags] [name]
+
+Commands:
+  status   Stage, conditions, sync and pause state of the CR
+  stages   Status, duration and last error of every stage, read from the diagnostics endpoint
+  diff     Changes between two config snapshots, by default the applied one and the one created before it
+  resync   Sync the configuration without waiting for the resync period
+  pause    Stop reconciling the stack, or only the stages given with --stages
+  resume   Reconcile the stack and all its stages again
+
+The name of the CR can be omitted if the namespace has only one. Flags go before the name.
+
+Flags:
+`
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiv1.AddToScheme(scheme))
+}
+
+// options of all commands, parsed from the flags
+type options struct {
+	namespace      string
+	config         *rest.Config
+	client         client.Client
+	args           []string
+	diagnosticsURL string
+	token          string
+	stages         string
+}
+
+type command func(ctx context.Context, o *options) error
+
+var commands = map[string]command{
+	"status": runStatus,
+	"stages": runStages,
+	"diff":   runDiff,
+	"resync": runResync,
+	"pause":  runPause,
+	"resume": runResume,
+}
+
+func main() {
+	flags := flag.NewFlagSet("kubectl-observability", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	o := &options{}
+	var kubeconfig string
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file, the default loading rules of kubectl apply if empty.")
+	flags.StringVar(&o.namespace, "n", "", "Namespace of the CR, the namespace of the current context if empty.")
+	flags.StringVar(&o.diagnosticsURL, "diagnostics-url", "", "URL of the diagnostics endpoint of the operator, "+
+		"e.g. http://localhost:8083 with a port-forward to the leader.")
+	flags.StringVar(&o.token, "token", "", "Bearer token for the diagnostics endpoint, the token of the kubeconfig if empty.")
+	flags.StringVar(&o.stages, "stages", "", "Comma separated list of the stages to pause.")
+
+	if len(os.Args) < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %v\n\n", os.Args[1])
+		flags.Usage()
+		os.Exit(2)
+	}
+	flags.Parse(os.Args[2:])
+	o.args = flags.Args()
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+
+	var err error
+	if o.namespace == "" {
+		o.namespace, _, err = clientConfig.Namespace()
+		exitOnError(err)
+	}
+	o.config, err = clientConfig.ClientConfig()
+	exitOnError(err)
+	o.client, err = client.New(o.config, client.Options{Scheme: scheme})
+	exitOnError(err)
+
+	exitOnError(run(context.Background(), o))
+}
+
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Returns the CR named in the arguments, or the only CR of the namespace
+func getObservability(ctx context.Context, o *options) (*apiv1.Observability, error) {
+	if len(o.args) > 0 {
+		cr := &apiv1.Observability{}
+		err := o.client.Get(ctx, client.ObjectKey{Namespace: o.namespace, Name: o.args[0]}, cr)
+		return cr, err
+	}
+
+	list := &apiv1.ObservabilityList{}
+	err := o.client.List(ctx, list, client.InNamespace(o.namespace))
+	if err != nil {
+		return nil, err
+	}
+	switch len(list.Items) {
+	case 0:
+		return nil, fmt.Errorf("no observability CR in namespace %v", o.namespace)
+	case 1:
+		return &list.Items[0], nil
+	}
+
+	var names []string
+	for _, cr := range list.Items {
+		names = append(names, cr.Name)
+	}
+	return nil, fmt.Errorf("namespace %v has several observability CRs, one of %v is required", o.namespace, strings.Join(names, ", "))
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const namespace = "observability"
+
+func newObservability(name string) *apiv1.Observability {
+	return &apiv1.Observability{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+}
+
+func newOptions(args []string, objects ...runtime.Object) *options {
+	return &options{
+		namespace: namespace,
+		client:    fake.NewFakeClientWithScheme(scheme, objects...),
+		args:      args,
+	}
+}
+
+// Runs the command and returns what it printed
+func captureOutput(t *testing.T, run func() error) (string, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	output := make(chan []byte)
+	go func() {
+		data, _ := ioutil.ReadAll(reader)
+		output <- data
+	}()
+	err = run()
+	writer.Close()
+	return string(<-output), err
+}
+
+func TestGetObservability(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		objects  []runtime.Object
+		wantName string
+		wantErr  string
+	}{
+		{
+			name:     "named CR",
+			args:     []string{"second"},
+			objects:  []runtime.Object{newObservability("first"), newObservability("second")},
+			wantName: "second",
+		},
+		{
+			name:    "named CR does not exist",
+			args:    []string{"third"},
+			objects: []runtime.Object{newObservability("first")},
+			wantErr: `observabilities.observability.redhat.com "third" not found`,
+		},
+		{
+			name:     "only CR of the namespace",
+			objects:  []runtime.Object{newObservability("first")},
+			wantName: "first",
+		},
+		{
+			name:    "no CR in the namespace",
+			wantErr: "no observability CR in namespace observability",
+		},
+		{
+			name:    "several CRs in the namespace",
+			objects: []runtime.Object{newObservability("first"), newObservability("second")},
+			wantErr: "namespace observability has several observability CRs, one of first, second is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr, err := getObservability(context.Background(), newOptions(tt.args, tt.objects...))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("getObservability() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getObservability() error = %v", err)
+			}
+			if cr.Name != tt.wantName {
+				t.Errorf("getObservability() = %v, want %v", cr.Name, tt.wantName)
+			}
+		})
+	}
+}
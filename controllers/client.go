@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Kinds that exist in large numbers on big clusters. The informers of the cache would list and watch
+// all of them in the cluster, so they are read from the API server instead, by name or with selectors.
+var uncachedObjects = []runtime.Object{
+	&appsv1.Deployment{},
+	&appsv1.StatefulSet{},
+	&appsv1.DaemonSet{},
+	&v1.ConfigMap{},
+	&v1.PersistentVolumeClaim{},
+	&v1.Pod{},
+	&batchv1.Job{},
+	&v1alpha1.ClusterServiceVersion{},
+}
+
+// Creates the client of the manager, which reads the uncached kinds from the API server
+func NewClient(cache cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
+	c, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	uncached := map[schema.GroupKind]bool{}
+	for _, obj := range uncachedObjects {
+		gvk, err := apiutil.GVKForObject(obj, options.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		uncached[gvk.GroupKind()] = true
+	}
+
+	return &client.DelegatingClient{
+		Reader: &scopedReader{
+			cached:   &client.DelegatingReader{CacheReader: cache, ClientReader: c},
+			api:      c,
+			scheme:   options.Scheme,
+			uncached: uncached,
+		},
+		Writer:       c,
+		StatusClient: c,
+	}, nil
+}
+
+type scopedReader struct {
+	cached   client.Reader
+	api      client.Reader
+	scheme   *runtime.Scheme
+	uncached map[schema.GroupKind]bool
+}
+
+func (r *scopedReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return r.getReader(obj).Get(ctx, key, obj)
+}
+
+func (r *scopedReader) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	return r.getReader(list).List(ctx, list, opts...)
+}
+
+func (r *scopedReader) getReader(obj runtime.Object) client.Reader {
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return r.cached
+	}
+
+	// Lists have the kind of their items
+	kind := schema.GroupKind{Group: gvk.Group, Kind: strings.TrimSuffix(gvk.Kind, "List")}
+	if r.uncached[kind] {
+		return r.api
+	}
+	return r.cached
+}
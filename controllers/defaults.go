@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Sets the fields the CR leaves empty to the cluster wide defaults of the platform admins, if there are any
+func (r *ObservabilityReconciler) applyDefaults(ctx context.Context, obs *apiv1.Observability) error {
+	defaults := &apiv1.ObservabilityDefaults{}
+	err := r.Get(ctx, client.ObjectKey{Name: apiv1.ObservabilityDefaultsName}, defaults)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	obs.ApplyDefaults(&defaults.Spec)
+	return nil
+}
+
+// Enqueue all CRs when the defaults change, they are inherited by every CR of the cluster
+func (r *ObservabilityReconciler) mapDefaults(o handler.MapObject) []reconcile.Request {
+	if o.Meta.GetName() != apiv1.ObservabilityDefaultsName {
+		return nil
+	}
+
+	list := &apiv1.ObservabilityList{}
+	err := r.List(context.Background(), list)
+	if err != nil {
+		r.Log.Error(err, "error listing observability CRs for defaults")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, obs := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: obs.Namespace,
+				Name:      obs.Name,
+			},
+		})
+	}
+	return requests
+}
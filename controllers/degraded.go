@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Flip the Degraded condition depending on the missing APIs recorded by the stages. CRs that never
+// missed an API don't get the condition
+func setDegradedCondition(s *apiv1.ObservabilityStatus) {
+	if len(s.MissingAPIs) > 0 {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    apiv1.Degraded,
+			Status:  metav1.ConditionTrue,
+			Reason:  "MissingAPIs",
+			Message: fmt.Sprintf("APIs not served by the cluster, their resources are skipped: %v", strings.Join(s.MissingAPIs, ", ")),
+		})
+		return
+	}
+
+	if meta.FindStatusCondition(s.Conditions, apiv1.Degraded) != nil {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:   apiv1.Degraded,
+			Status: metav1.ConditionFalse,
+			Reason: "AllAPIsServed",
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	grafanav1alpha1 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Resources the stages depend on. A change reconciles the Observability CRs in the namespace of the
+// resource right away, instead of at the next requeue.
+type dependent struct {
+	gvk      schema.GroupVersionKind
+	resource string
+	// Restricts the informer to the resources of the operator, deployments for example are not cached
+	// for the whole cluster
+	selector string
+	// Resources created by OLM don't carry the labels of the operator. They are only watched in the
+	// namespaces of the reconciled CRs
+	namespaced bool
+	// Updates that require a reconcile, all other updates are ignored
+	predicate predicate.Predicate
+}
+
+var managedSelector = labels.SelectorFromSet(map[string]string{utils.ManagedByLabel: utils.ManagedByValue}).String()
+
+var dependents = []dependent{
+	{
+		gvk:       v1alpha1.SchemeGroupVersion.WithKind("Subscription"),
+		resource:  "subscriptions",
+		selector:  managedSelector,
+		predicate: predicate.GenerationChangedPredicate{},
+	},
+	{
+		// CSVs are created by OLM, the copies of cluster wide operators in every namespace are left out
+		gvk:        v1alpha1.SchemeGroupVersion.WithKind("ClusterServiceVersion"),
+		resource:   "clusterserviceversions",
+		selector:   "!olm.copiedFrom",
+		namespaced: true,
+		predicate:  phaseChangedPredicate{},
+	},
+	{
+		gvk:       appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		resource:  "deployments",
+		selector:  managedSelector,
+		predicate: predicate.GenerationChangedPredicate{},
+	},
+	{
+		// The deployments of the Prometheus and the Grafana operator, created by OLM for their CSVs
+		gvk:        appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		resource:   "deployments",
+		selector:   "olm.owner.kind=ClusterServiceVersion",
+		namespaced: true,
+		predicate:  predicate.GenerationChangedPredicate{},
+	},
+	{
+		gvk:       grafanav1alpha1.SchemeGroupVersion.WithKind("Grafana"),
+		resource:  "grafanas",
+		selector:  managedSelector,
+		predicate: predicate.GenerationChangedPredicate{},
+	},
+	{
+		gvk:       grafanav1alpha1.SchemeGroupVersion.WithKind("GrafanaDashboard"),
+		resource:  "grafanadashboards",
+		selector:  managedSelector,
+		predicate: predicate.GenerationChangedPredicate{},
+	},
+	{
+		gvk:       prometheusv1.SchemeGroupVersion.WithKind("Prometheus"),
+		resource:  "prometheuses",
+		selector:  managedSelector,
+		predicate: predicate.GenerationChangedPredicate{},
+	},
+}
+
+// Informer of a dependent that is started once its API is served. The CRDs of the Grafana and the
+// Prometheus operator for example are only created by OLM after the operator started.
+type dependentInformer struct {
+	dependent
+	informer toolscache.SharedIndexInformer
+	mapper   meta.RESTMapper
+}
+
+func (d *dependentInformer) Start(stop <-chan struct{}) error {
+	err := wait.PollImmediateUntil(time.Minute, func() (bool, error) {
+		_, err := d.mapper.RESTMapping(d.gvk.GroupKind(), d.gvk.Version)
+		return err == nil, nil
+	}, stop)
+	if err != nil {
+		// Stopped before the API was served
+		return nil
+	}
+
+	d.informer.Run(stop)
+	return nil
+}
+
+// Creates the informers of the dependents that are watched in all namespaces and adds them to the
+// manager
+func newDependentInformers(mgr manager.Manager, dynamicClient dynamic.Interface) ([]*dependentInformer, error) {
+	var result []*dependentInformer
+	for _, d := range dependents {
+		if d.namespaced {
+			continue
+		}
+		informer, err := addDependentInformer(mgr, dynamicClient, d, metav1.NamespaceAll)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, informer)
+	}
+	return result, nil
+}
+
+func addDependentInformer(mgr manager.Manager, dynamicClient dynamic.Interface, d dependent, namespace string) (*dependentInformer, error) {
+	selector := d.selector
+	informer := dynamicinformer.NewFilteredDynamicInformer(dynamicClient, d.gvk.GroupVersion().WithResource(d.resource),
+		namespace, 0, toolscache.Indexers{}, func(options *metav1.ListOptions) {
+			options.LabelSelector = selector
+		})
+
+	dependentInformer := &dependentInformer{
+		dependent: d,
+		informer:  informer.Informer(),
+		mapper:    mgr.GetRESTMapper(),
+	}
+	err := mgr.Add(dependentInformer)
+	if err != nil {
+		return nil, err
+	}
+	return dependentInformer, nil
+}
+
+// Watches of the namespaced dependents, started when the first CR of a namespace is reconciled.
+// They keep running after the CRs of the namespace are deleted
+type namespacedDependents struct {
+	mu            sync.Mutex
+	mgr           manager.Manager
+	controller    controller.Controller
+	dynamicClient dynamic.Interface
+	handler       handler.EventHandler
+	namespaces    map[string]bool
+}
+
+func (n *namespacedDependents) watch(namespace string) error {
+	if n == nil {
+		return nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.namespaces[namespace] {
+		return nil
+	}
+
+	for _, d := range dependents {
+		if !d.namespaced {
+			continue
+		}
+		informer, err := addDependentInformer(n.mgr, n.dynamicClient, d, namespace)
+		if err != nil {
+			return err
+		}
+		err = n.controller.Watch(&source.Informer{Informer: informer.informer}, n.handler, informer.predicate)
+		if err != nil {
+			return err
+		}
+	}
+	n.namespaces[namespace] = true
+	return nil
+}
+
+func (r *ObservabilityReconciler) mapDependent(o handler.MapObject) []reconcile.Request {
+	list := &apiv1.ObservabilityList{}
+	err := r.List(context.Background(), list, client.InNamespace(o.Meta.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "error listing observability CRs for dependent", "namespace", o.Meta.GetNamespace(), "name", o.Meta.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, obs := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: obs.Namespace,
+				Name:      obs.Name,
+			},
+		})
+	}
+	return requests
+}
+
+// The phase of a CSV changes when the operator is installed, fails or is replaced
+type phaseChangedPredicate struct {
+	predicate.Funcs
+}
+
+func (phaseChangedPredicate) Update(e event.UpdateEvent) bool {
+	oldObject, ok := e.ObjectOld.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+	newObject, ok := e.ObjectNew.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+
+	oldPhase, _, _ := unstructured.NestedString(oldObject.Object, "status", "phase")
+	newPhase, _, _ := unstructured.NestedString(newObject.Object, "status", "phase")
+	return oldPhase != newPhase
+}
@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Number of resources kept in status.drift
+const maxDriftedResources = 20
+
+// Kinds of which spec changes roll out a component
+var rolloutKinds = map[string]bool{
+	"Deployment":   true,
+	"StatefulSet":  true,
+	"DaemonSet":    true,
+	"Prometheus":   true,
+	"Alertmanager": true,
+	"Grafana":      true,
+}
+
+// Fields of the metadata that are compared, the others are maintained by the API server
+var driftMetadataFields = []string{"labels", "annotations"}
+
+// The API server records updates without a field manager under the name of the binary, taken from
+// the user agent of the client. Applied resources are managed by utils.FieldManager.
+var operatorFieldManager = filepath.Base(os.Args[0])
+
+// Client of the stages that compares updates and applies of managed resources with the live resource.
+// Fields last written by another field manager were changed out of band and are reported before they
+// are overwritten, or left as they are in report-only mode.
+type driftClient struct {
+	client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	log      logr.Logger
+	cr       *apiv1.Observability
+	status   *apiv1.ObservabilityStatus
+	// Guards the status, the configuration stage applies resources concurrently
+	mu sync.Mutex
+}
+
+func (c *driftClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	// The CR itself is owned by the user
+	if _, ok := obj.(*apiv1.Observability); ok {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+
+	if !c.reconcileDrift(ctx, obj, false) {
+		return nil
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *driftClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	// The group version kind is part of the applied configuration, but typed objects don't carry it
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	if !c.reconcileDrift(ctx, obj, true) {
+		return nil
+	}
+
+	generation := c.getRolloutGeneration(ctx, obj, gvk.Kind)
+	err = c.Client.Patch(ctx, obj, patch, opts...)
+	if err != nil {
+		return err
+	}
+	if accessor, err := meta.Accessor(obj); err == nil && generation > 0 && accessor.GetGeneration() > generation {
+		c.recorder.Eventf(c.cr, v1.EventTypeNormal, apiv1.EventComponentRolledOut, "rolled out %v %v", gvk.Kind, accessor.GetName())
+	}
+	return nil
+}
+
+// Returns the generation of an existing workload or component CR before it is applied, 0 for
+// other kinds. Its spec changed if the applied resource has a newer generation
+func (c *driftClient) getRolloutGeneration(ctx context.Context, obj runtime.Object, kind string) int64 {
+	if !rolloutKinds[kind] {
+		return 0
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return 0
+	}
+	live := obj.DeepCopyObject()
+	err = c.Client.Get(ctx, client.ObjectKey{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}, live)
+	if err != nil {
+		return 0
+	}
+	liveAccessor, err := meta.Accessor(live)
+	if err != nil {
+		return 0
+	}
+	return liveAccessor.GetGeneration()
+}
+
+// Reports the drift of a resource and returns false if it must not be written. Applied
+// configurations only contain the fields set by the operator, the others are not compared.
+func (c *driftClient) reconcileDrift(ctx context.Context, obj runtime.Object, applied bool) bool {
+	drifted, err := c.detectDrift(ctx, obj, applied)
+	if err != nil {
+		// Detection is best effort and never blocks reconciliation
+		c.log.V(1).Info("error detecting drift", "error", err.Error())
+	}
+	if drifted == nil {
+		return true
+	}
+
+	drifted.Reverted = !c.cr.IsDriftReportOnly(drifted.Kind)
+	c.recordDrift(*drifted)
+	return drifted.Reverted
+}
+
+func (c *driftClient) detectDrift(ctx context.Context, obj runtime.Object, applied bool) (*apiv1.DriftedResource, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	live := obj.DeepCopyObject()
+	err = c.Client.Get(ctx, client.ObjectKey{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}, live)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	liveAccessor, err := meta.Accessor(live)
+	if err != nil {
+		return nil, err
+	}
+
+	liveFields, err := toFields(live)
+	if err != nil {
+		return nil, err
+	}
+	desiredFields, err := toFields(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths [][]string
+	for key := range mergeKeys(liveFields, desiredFields) {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			liveMetadata, _ := liveFields[key].(map[string]interface{})
+			desiredMetadata, _ := desiredFields[key].(map[string]interface{})
+			// Labels and annotations added by others, e.g. the revision of deployments, are kept by
+			// most resources and not reported
+			for _, field := range driftMetadataFields {
+				desired, _ := desiredMetadata[field].(map[string]interface{})
+				for _, path := range diffFields(liveMetadata[field], desiredMetadata[field], []string{key, field}, applied) {
+					if len(path) < 3 {
+						continue
+					}
+					if _, ok := desired[path[2]]; ok {
+						paths = append(paths, path)
+					}
+				}
+			}
+		default:
+			paths = append(paths, diffFields(liveFields[key], desiredFields[key], []string{key}, applied)...)
+		}
+	}
+
+	fields := map[string]bool{}
+	managers := map[string]bool{}
+	for _, path := range paths {
+		for _, manager := range getFieldManagers(liveAccessor, path) {
+			fields[strings.Join(path, ".")] = true
+			managers[manager] = true
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return &apiv1.DriftedResource{
+		Kind:     gvk.Kind,
+		Name:     accessor.GetName(),
+		Fields:   sortedKeys(fields),
+		Managers: sortedKeys(managers),
+		Time:     time.Now().Unix(),
+	}, nil
+}
+
+func (c *driftClient) recordDrift(drifted apiv1.DriftedResource) {
+	action := "reverting"
+	if !drifted.Reverted {
+		action = "not reverting, report-only"
+	}
+	c.log.Info("managed resource changed out of band", "kind", drifted.Kind, "name", drifted.Name, "fields", drifted.Fields, "managers", drifted.Managers, "reverted", drifted.Reverted)
+	c.recorder.Eventf(c.cr, v1.EventTypeWarning, apiv1.EventDriftDetected, "%v %v was changed by %v (%v), %v",
+		drifted.Kind, drifted.Name, strings.Join(drifted.Managers, ", "), strings.Join(drifted.Fields, ", "), action)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := []apiv1.DriftedResource{drifted}
+	for _, existing := range c.status.Drift {
+		if existing.Kind == drifted.Kind && existing.Name == drifted.Name {
+			continue
+		}
+		if len(result) == maxDriftedResources {
+			break
+		}
+		result = append(result, existing)
+	}
+	c.status.Drift = result
+}
+
+// Returns the paths of the fields that differ, down to the first list. Fields missing from a sparse
+// desired state are not managed and don't differ.
+func diffFields(live interface{}, desired interface{}, path []string, sparse bool) [][]string {
+	if reflect.DeepEqual(live, desired) || (sparse && desired == nil) {
+		return nil
+	}
+
+	liveMap, liveIsMap := live.(map[string]interface{})
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	if !liveIsMap || !desiredIsMap {
+		return [][]string{path}
+	}
+
+	var result [][]string
+	for key := range mergeKeys(liveMap, desiredMap) {
+		child := append(append([]string{}, path...), key)
+		result = append(result, diffFields(liveMap[key], desiredMap[key], child, sparse)...)
+	}
+	return result
+}
+
+// Returns the field managers other than the operator that own the field or fields below it
+func getFieldManagers(obj metav1.Object, path []string) []string {
+	var result []string
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == operatorFieldManager || entry.Manager == utils.FieldManager || entry.FieldsV1 == nil {
+			continue
+		}
+
+		fields := map[string]interface{}{}
+		err := json.Unmarshal(entry.FieldsV1.Raw, &fields)
+		if err != nil {
+			continue
+		}
+
+		owned := true
+		for _, name := range path {
+			child, ok := fields["f:"+name].(map[string]interface{})
+			if !ok {
+				owned = false
+				break
+			}
+			fields = child
+		}
+		if owned {
+			result = append(result, entry.Manager)
+		}
+	}
+	return result
+}
+
+func toFields(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+func mergeKeys(a map[string]interface{}, b map[string]interface{}) map[string]bool {
+	result := map[string]bool{}
+	for key := range a {
+		result[key] = true
+	}
+	for key := range b {
+		result[key] = true
+	}
+	return result
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var result []string
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	FleetTelemetryDefaultInterval = 15 * time.Minute
+	FleetTelemetryTokenKey        = "token"
+)
+
+var fleetTelemetryClient = &http.Client{Timeout: 10 * time.Second}
+
+type fleetTelemetryCondition struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Compact health snapshot of a stack, sent to the fleet telemetry endpoint
+type fleetTelemetryReport struct {
+	ClusterID   string                         `json:"clusterId"`
+	Namespace   string                         `json:"namespace"`
+	Name        string                         `json:"name"`
+	Timestamp   int64                          `json:"timestamp"`
+	Stage       apiv1.ObservabilityStageName   `json:"stage"`
+	StageStatus apiv1.ObservabilityStageStatus `json:"stageStatus"`
+	LastMessage string                         `json:"lastMessage,omitempty"`
+	// Prometheus and Alertmanager versions and the installed CSV of each subscription
+	Versions   map[string]string         `json:"versions"`
+	Conditions []fleetTelemetryCondition `json:"conditions,omitempty"`
+	// Remote write health as reported by the RemoteWriteDegraded condition
+	RemoteWriteDegraded bool `json:"remoteWriteDegraded"`
+	// Firing alerts by severity, empty if Prometheus is not available
+	FiringAlerts   map[string]int `json:"firingAlerts"`
+	ConfigSnapshot string         `json:"configSnapshot,omitempty"`
+}
+
+func getFleetTelemetryInterval(cr *apiv1.Observability) time.Duration {
+	interval, err := time.ParseDuration(cr.Spec.FleetTelemetry.Interval)
+	if err != nil || interval <= 0 {
+		return FleetTelemetryDefaultInterval
+	}
+	return interval
+}
+
+// Count the firing alerts of the stack's Prometheus by severity
+func countFiringAlerts(cr *apiv1.Observability) (map[string]int, error) {
+	resp, err := fleetTelemetryClient.Get(fmt.Sprintf("http://prometheus-operated.%s:9090/api/v1/alerts", cr.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from prometheus: %v", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := struct {
+		Data struct {
+			Alerts []struct {
+				Labels map[string]string `json:"labels"`
+				State  string            `json:"state"`
+			} `json:"alerts"`
+		} `json:"data"`
+	}{}
+	err = json.Unmarshal(body, &alerts)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, alert := range alerts.Data.Alerts {
+		if alert.State == "firing" {
+			counts[alert.Labels["severity"]]++
+		}
+	}
+	return counts, nil
+}
+
+func getFleetTelemetryReport(log logr.Logger, cr *apiv1.Observability, s *apiv1.ObservabilityStatus) *fleetTelemetryReport {
+	report := &fleetTelemetryReport{
+		ClusterID:   s.ClusterID,
+		Namespace:   cr.Namespace,
+		Name:        cr.Name,
+		Timestamp:   time.Now().Unix(),
+		Stage:       s.Stage,
+		StageStatus: s.StageStatus,
+		LastMessage: s.LastMessage,
+		Versions: map[string]string{
+			"prometheus":   model.GetPrometheusVersion(cr),
+			"alertmanager": model.GetAlertmanagerVersion(cr),
+		},
+		RemoteWriteDegraded: meta.IsStatusConditionTrue(s.Conditions, apiv1.RemoteWriteDegraded),
+		FiringAlerts:        map[string]int{},
+		ConfigSnapshot:      s.ConfigSnapshot,
+	}
+
+	for _, subscription := range s.Subscriptions {
+		report.Versions[subscription.Name] = subscription.InstalledCSV
+	}
+
+	for _, condition := range s.Conditions {
+		report.Conditions = append(report.Conditions, fleetTelemetryCondition{
+			Type:   condition.Type,
+			Status: string(condition.Status),
+			Reason: condition.Reason,
+		})
+	}
+
+	counts, err := countFiringAlerts(cr)
+	if err != nil {
+		log.V(1).Info("firing alerts not included in fleet telemetry", "error", err.Error())
+	} else {
+		report.FiringAlerts = counts
+	}
+
+	return report
+}
+
+// Send a health snapshot to the fleet telemetry endpoint. Runs after the stages, so failing stages
+// are reported too. Failures are logged and retried on the next reconcile.
+func (r *ObservabilityReconciler) reportFleetTelemetry(ctx context.Context, log logr.Logger, cr *apiv1.Observability, s *apiv1.ObservabilityStatus) {
+	if cr.Spec.FleetTelemetry == nil {
+		return
+	}
+
+	if s.FleetTelemetryReported != 0 {
+		lastReported := time.Unix(s.FleetTelemetryReported, 0)
+		if time.Now().Before(lastReported.Add(getFleetTelemetryInterval(cr))) {
+			return
+		}
+	}
+
+	body, err := json.Marshal(getFleetTelemetryReport(log, cr, s))
+	if err != nil {
+		log.Error(err, "error encoding fleet telemetry report")
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, cr.Spec.FleetTelemetry.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		log.Error(err, "error creating fleet telemetry request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if cr.Spec.FleetTelemetry.TokenSecret != "" {
+		secret := &v1.Secret{}
+		err = r.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: cr.Spec.FleetTelemetry.TokenSecret}, secret)
+		if err != nil {
+			log.Error(err, "error reading fleet telemetry token", "secret", cr.Spec.FleetTelemetry.TokenSecret)
+			return
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", secret.Data[FleetTelemetryTokenKey]))
+	}
+
+	resp, err := fleetTelemetryClient.Do(req)
+	if err != nil {
+		log.Error(err, "error sending fleet telemetry report")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		log.Info("fleet telemetry endpoint rejected the report", "status", resp.StatusCode)
+		return
+	}
+
+	s.FleetTelemetryReported = time.Now().Unix()
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Number of annotations kept in status.pendingGrafanaAnnotations while Grafana is unavailable
+const maxPendingGrafanaAnnotations = 50
+
+// Event reasons annotated per source
+var grafanaAnnotationReasons = map[string]apiv1.GrafanaAnnotationSource{
+	apiv1.EventConfigRevisionApplied: apiv1.GrafanaAnnotationConfig,
+	apiv1.EventComponentRolledOut:    apiv1.GrafanaAnnotationRollout,
+	apiv1.EventComponentInstalled:    apiv1.GrafanaAnnotationUpgrade,
+	apiv1.EventComponentUpgraded:     apiv1.GrafanaAnnotationUpgrade,
+	apiv1.EventUpgradeRolledBack:     apiv1.GrafanaAnnotationUpgrade,
+}
+
+// Recorder of the stages that also queues the events selected in spec.grafana.annotations in the
+// status. The configuration stage writes them to Grafana, events of later stages are written
+// during the next reconcile.
+type annotatingRecorder struct {
+	record.EventRecorder
+	cr     *apiv1.Observability
+	status *apiv1.ObservabilityStatus
+}
+
+func (r *annotatingRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.queue(object, reason, message)
+}
+
+func (r *annotatingRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	r.queue(object, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *annotatingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	r.queue(object, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// Only events of the CR are annotated, the oldest pending annotations are dropped first
+func (r *annotatingRecorder) queue(object runtime.Object, reason string, text string) {
+	if object != r.cr {
+		return
+	}
+	source, ok := grafanaAnnotationReasons[reason]
+	if !ok || !r.cr.GrafanaAnnotationSourceEnabled(source) {
+		return
+	}
+
+	pending := append(r.status.PendingGrafanaAnnotations, apiv1.GrafanaAnnotation{
+		Time:   time.Now().UnixNano() / int64(time.Millisecond),
+		Reason: reason,
+		Text:   text,
+	})
+	if len(pending) > maxPendingGrafanaAnnotations {
+		pending = pending[len(pending)-maxPendingGrafanaAnnotations:]
+	}
+	r.status.PendingGrafanaAnnotations = pending
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// Stage a CR is waiting on and when it started waiting
+type stageProgress struct {
+	stage apiv1.ObservabilityStageName
+	since time.Time
+}
+
+// Every reconcile run gets an ID to correlate the log lines of its stages
+func newReconcileId() string {
+	return rand.String(8)
+}
+
+// Returns how long a CR has been waiting on a stage. Resets when the CR moves on to another stage.
+func (r *ObservabilityReconciler) waitingSince(key types.NamespacedName, stage apiv1.ObservabilityStageName) time.Duration {
+	if r.progress == nil {
+		r.progress = map[types.NamespacedName]stageProgress{}
+	}
+
+	progress, ok := r.progress[key]
+	if !ok || progress.stage != stage {
+		progress = stageProgress{stage: stage, since: time.Now()}
+		r.progress[key] = progress
+	}
+	return time.Since(progress.since).Round(time.Second)
+}
+
+func (r *ObservabilityReconciler) resetProgress(key types.NamespacedName) {
+	delete(r.progress, key)
+}
+
+// Log the outcome of a stage. Successful stages are only logged at debug level.
+func logStageResult(log logr.Logger, stage apiv1.ObservabilityStageName, status apiv1.ObservabilityStageStatus, duration time.Duration) {
+	if status == apiv1.ResultSuccess {
+		log.V(1).Info("stage reconciled", "stage", stage, "result", status, "duration", duration.String())
+		return
+	}
+	log.Info("stage reconciled", "stage", stage, "result", status, "duration", duration.String())
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AccessViewerName = "observability-viewer"
+	AccessEditorName = "observability-editor"
+)
+
+func getAccessLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+	}
+}
+
+// The cluster roles are shared by the CRs of all namespaces and bound per namespace
+func GetAccessViewerClusterRole() *v13.ClusterRole {
+	return &v13.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   AccessViewerName,
+			Labels: getAccessLabels(),
+		},
+	}
+}
+
+func GetAccessEditorClusterRole() *v13.ClusterRole {
+	return &v13.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   AccessEditorName,
+			Labels: getAccessLabels(),
+		},
+	}
+}
+
+func GetAccessViewerRoleBinding(cr *v1.Observability) *v13.RoleBinding {
+	return &v13.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AccessViewerName,
+			Namespace: cr.Namespace,
+			Labels:    getAccessLabels(),
+		},
+	}
+}
+
+func GetAccessEditorRoleBinding(cr *v1.Observability) *v13.RoleBinding {
+	return &v13.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AccessEditorName,
+			Namespace: cr.Namespace,
+			Labels:    getAccessLabels(),
+		},
+	}
+}
+
+func GetAccessGroupSubjects(groups []string) []v13.Subject {
+	var subjects []v13.Subject
+	for _, group := range groups {
+		subjects = append(subjects, v13.Subject{
+			Kind:     v13.GroupKind,
+			APIGroup: v13.GroupName,
+			Name:     group,
+		})
+	}
+	return subjects
+}
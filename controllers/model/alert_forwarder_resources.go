@@ -0,0 +1,82 @@
+package model
+
+import (
+	"fmt"
+	"os"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AlertForwarderName = "observability-alert-forwarder"
+	AlertForwarderPort = 9095
+	// Path the notifications are posted to, followed by the name of the destination
+	AlertForwarderAlertsPath = "/alerts/"
+	AlertForwarderConfigKey  = "config.yaml"
+	AlertForwarderConfigPath = "/etc/alert-forwarder"
+	// Image of the operator, which runs the alert forwarder. Determined from the operator pod at startup
+	// if not set
+	OperatorImageEnv = "OPERATOR_IMAGE"
+)
+
+// The alert forwarder runs the operator image unless overridden
+func GetAlertForwarderImage(cr *v1.Observability) string {
+	return GetImage(cr, v1.ImageAlertForwarder, os.Getenv(OperatorImageEnv))
+}
+
+// Url of a destination, used in the webhook config of the Alertmanager receiver
+func GetAlertForwarderUrl(cr *v1.Observability, destination string) string {
+	return fmt.Sprintf("http://%v.%v.svc:%d%v%v", AlertForwarderName, cr.Namespace, AlertForwarderPort,
+		AlertForwarderAlertsPath, destination)
+}
+
+// Name of the Alertmanager receiver of a destination
+func GetAlertForwarderReceiverName(destination string) string {
+	return fmt.Sprintf("alert-forwarder-%v", destination)
+}
+
+func getAlertForwarderLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+		"app":        AlertForwarderName,
+	}
+}
+
+func GetAlertForwarderSelectorLabels() map[string]string {
+	return map[string]string{
+		"app": AlertForwarderName,
+	}
+}
+
+func GetAlertForwarderSecret(cr *v1.Observability) *v14.Secret {
+	return &v14.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      fmt.Sprintf("%v-config", AlertForwarderName),
+			Namespace: cr.Namespace,
+			Labels:    getAlertForwarderLabels(),
+		},
+	}
+}
+
+func GetAlertForwarderDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      AlertForwarderName,
+			Namespace: cr.Namespace,
+			Labels:    getAlertForwarderLabels(),
+		},
+	}
+}
+
+func GetAlertForwarderService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      AlertForwarderName,
+			Namespace: cr.Namespace,
+			Labels:    getAlertForwarderLabels(),
+		},
+	}
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	v1alpha12 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AlertHistoryName = "observability-alert-history"
+	AlertHistoryPort = 9098
+	// Path the notifications are posted to
+	AlertHistoryAlertsPath        = "/alerts"
+	AlertHistoryDataPath          = "/var/lib/alert-history"
+	AlertHistoryReceiver          = "alert-history"
+	AlertHistoryDefaultStorage    = "1Gi"
+	AlertHistoryDefaultRetention  = 90
+	AlertHistoryDatasourceName    = "Alert History"
+	AlertHistoryDatasourceType    = "grafana-simple-json-datasource"
+	AlertHistoryDatasourceVersion = "1.4.2"
+	AlertHistoryDashboardName     = "alert-history"
+)
+
+// The history runs the operator image unless overridden
+func GetAlertHistoryImage(cr *v1.Observability) string {
+	return GetImage(cr, v1.ImageAlertHistory, os.Getenv(OperatorImageEnv))
+}
+
+func getAlertHistoryBaseUrl(cr *v1.Observability) string {
+	return fmt.Sprintf("http://%v.%v.svc:%d", AlertHistoryName, cr.Namespace, AlertHistoryPort)
+}
+
+// Url of the history, used in the webhook config of the Alertmanager receiver
+func GetAlertHistoryUrl(cr *v1.Observability) string {
+	return getAlertHistoryBaseUrl(cr) + AlertHistoryAlertsPath
+}
+
+func GetAlertHistoryStorageSize(cr *v1.Observability) string {
+	if cr.AlertHistoryEnabled() && cr.Spec.Alerting.History.Size != "" {
+		return cr.Spec.Alerting.History.Size
+	}
+	return AlertHistoryDefaultStorage
+}
+
+func GetAlertHistoryRetentionDays(cr *v1.Observability) int {
+	if cr.AlertHistoryEnabled() && cr.Spec.Alerting.History.RetentionDays > 0 {
+		return cr.Spec.Alerting.History.RetentionDays
+	}
+	return AlertHistoryDefaultRetention
+}
+
+func getAlertHistoryLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+		"app":        AlertHistoryName,
+	}
+}
+
+func GetAlertHistorySelectorLabels() map[string]string {
+	return map[string]string{
+		"app": AlertHistoryName,
+	}
+}
+
+// The volume is kept when the history is turned off and only deleted with the CR
+func GetAlertHistoryPersistentVolumeClaim(cr *v1.Observability) *v14.PersistentVolumeClaim {
+	return &v14.PersistentVolumeClaim{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      AlertHistoryName,
+			Namespace: cr.Namespace,
+			Labels:    getAlertHistoryLabels(),
+		},
+	}
+}
+
+func GetAlertHistoryDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      AlertHistoryName,
+			Namespace: cr.Namespace,
+			Labels:    getAlertHistoryLabels(),
+		},
+	}
+}
+
+func GetAlertHistoryService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      AlertHistoryName,
+			Namespace: cr.Namespace,
+			Labels:    getAlertHistoryLabels(),
+		},
+	}
+}
+
+func GetAlertHistoryDatasource(cr *v1.Observability) *v1alpha12.GrafanaDataSource {
+	return &v1alpha12.GrafanaDataSource{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      AlertHistoryName,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Url of the SimpleJSON API of the history
+func GetAlertHistoryDatasourceUrl(cr *v1.Observability) string {
+	return getAlertHistoryBaseUrl(cr) + "/grafana"
+}
+
+func GetAlertHistoryDashboard(cr *v1.Observability) *v1alpha12.GrafanaDashboard {
+	return &v1alpha12.GrafanaDashboard{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      AlertHistoryDashboardName,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// The dashboard shows the alerts firing at the end of the time range and all transitions within
+// it, and marks the transitions on the graphs as annotations
+func GetAlertHistoryDashboardJson() (string, error) {
+	table := func(id int, title string, target string, y int) map[string]interface{} {
+		return map[string]interface{}{
+			"id":         id,
+			"type":       "table",
+			"title":      title,
+			"datasource": AlertHistoryDatasourceName,
+			"gridPos":    map[string]int{"x": 0, "y": y, "w": 24, "h": 10},
+			"targets": []interface{}{
+				map[string]string{"refId": "A", "target": target, "type": "table"},
+			},
+		}
+	}
+
+	dashboard, err := json.Marshal(map[string]interface{}{
+		"uid":           AlertHistoryDashboardName,
+		"title":         "Alert History",
+		"tags":          []string{"alerts"},
+		"version":       1,
+		"schemaVersion": 27,
+		"refresh":       "1m",
+		"time":          map[string]string{"from": "now-7d", "to": "now"},
+		"annotations": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"name":       "Alert transitions",
+					"datasource": AlertHistoryDatasourceName,
+					"query":      "transitions",
+					"enable":     true,
+					"iconColor":  "rgba(255, 96, 96, 1)",
+				},
+			},
+		},
+		"panels": []interface{}{
+			table(1, "Firing at the end of the range", "firing", 0),
+			table(2, "Transitions", "transitions", 10),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(dashboard), nil
+}
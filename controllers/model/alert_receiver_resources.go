@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v14 "k8s.io/api/core/v1"
+	v15 "k8s.io/api/networking/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The alert forwarder, the ticketing bridge and the alert history receive the notifications of
+// Alertmanager. Alertmanager authenticates with a bearer token of a secret it mounts, and a network
+// policy keeps other pods from reaching the receivers
+const (
+	AlertReceiverTokenKey = "token"
+	// Path the receivers mount their token secret at
+	AlertReceiverTokenPath = "/etc/alert-receiver-token"
+	// Alertmanager mounts the secrets of its CR below this directory
+	alertmanagerSecretsPath = "/etc/alertmanager/secrets"
+)
+
+func GetAlertReceiverTokenSecret(cr *v1.Observability, receiver string) *v14.Secret {
+	return &v14.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      fmt.Sprintf("%v-token", receiver),
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+				"app":        receiver,
+			},
+		},
+	}
+}
+
+func GetAlertReceiverNetworkPolicy(cr *v1.Observability, receiver string) *v15.NetworkPolicy {
+	return &v15.NetworkPolicy{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      fmt.Sprintf("%v-network-policy", receiver),
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Http config of the webhook of a receiver, with the token the receiver expects
+func GetAlertReceiverHttpConfig(cr *v1.Observability, receiver string) *v1.HttpConfig {
+	return &v1.HttpConfig{
+		BearerTokenFile: fmt.Sprintf("%v/%v/%v", alertmanagerSecretsPath, GetAlertReceiverTokenSecret(cr, receiver).Name, AlertReceiverTokenKey),
+	}
+}
+
+// Labels the Prometheus operator sets on the pods of the Alertmanager of the CR
+func GetAlertmanagerPodLabels(cr *v1.Observability) map[string]string {
+	return map[string]string{
+		"alertmanager": GetDefaultNameAlertmanager(cr),
+	}
+}
+
+// Labels the Grafana operator sets on the pods of the managed Grafana, which queries the history
+func GetGrafanaPodLabels() map[string]string {
+	return map[string]string{
+		"app": "grafana",
+	}
+}
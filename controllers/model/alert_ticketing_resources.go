@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"os"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AlertTicketingName = "observability-alert-ticketing"
+	AlertTicketingPort = 9096
+	// Path the notifications are posted to
+	AlertTicketingAlertsPath = "/alerts"
+	AlertTicketingConfigKey  = "config.yaml"
+	AlertTicketingConfigPath = "/etc/alert-ticketing"
+	AlertTicketingReceiver   = "alert-ticketing"
+)
+
+// The ticketing bridge runs the operator image unless overridden
+func GetAlertTicketingImage(cr *v1.Observability) string {
+	return GetImage(cr, v1.ImageAlertTicketing, os.Getenv(OperatorImageEnv))
+}
+
+// Url of the bridge, used in the webhook config of the Alertmanager receiver
+func GetAlertTicketingUrl(cr *v1.Observability) string {
+	return fmt.Sprintf("http://%v.%v.svc:%d%v", AlertTicketingName, cr.Namespace, AlertTicketingPort, AlertTicketingAlertsPath)
+}
+
+func getAlertTicketingLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+		"app":        AlertTicketingName,
+	}
+}
+
+func GetAlertTicketingSelectorLabels() map[string]string {
+	return map[string]string{
+		"app": AlertTicketingName,
+	}
+}
+
+func GetAlertTicketingSecret(cr *v1.Observability) *v14.Secret {
+	return &v14.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      fmt.Sprintf("%v-config", AlertTicketingName),
+			Namespace: cr.Namespace,
+			Labels:    getAlertTicketingLabels(),
+		},
+	}
+}
+
+func GetAlertTicketingDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      AlertTicketingName,
+			Namespace: cr.Namespace,
+			Labels:    getAlertTicketingLabels(),
+		},
+	}
+}
+
+func GetAlertTicketingService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      AlertTicketingName,
+			Namespace: cr.Namespace,
+			Labels:    getAlertTicketingLabels(),
+		},
+	}
+}
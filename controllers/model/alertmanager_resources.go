@@ -10,6 +10,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Port of the Alertmanager mesh, the default of the Prometheus operator
+const AlertmanagerClusterPort = 9094
+
 func GetDefaultNameAlertmanager(cr *v1.Observability) string {
 	if cr.Spec.SelfContained != nil && cr.Spec.AlertManagerDefaultName != "" {
 		return cr.Spec.AlertManagerDefaultName
@@ -46,15 +49,12 @@ func GetAlertmanagerRoute(cr *v1.Observability) *routev1.Route {
 
 func GetAlertmanagerServiceAccount(cr *v1.Observability) *v13.ServiceAccount {
 	route := GetAlertmanagerRoute(cr)
-	redirect := fmt.Sprintf("{\"kind\":\"OAuthRedirectReference\",\"apiVersion\":\"v1\",\"reference\":{\"kind\":\"Route\",\"name\":\"%s\"}}", route.Name)
 
 	return &v13.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      GetDefaultNameAlertmanager(cr),
-			Namespace: cr.Namespace,
-			Annotations: map[string]string{
-				"serviceaccounts.openshift.io/oauth-redirectreference.primary": redirect,
-			},
+			Name:        GetDefaultNameAlertmanager(cr),
+			Namespace:   cr.Namespace,
+			Annotations: getOAuthRedirectAnnotations(cr, route.Name, GetAlertmanagerHost(cr)),
 		},
 	}
 }
@@ -116,12 +116,48 @@ func GetAlertmanagerService(cr *v1.Observability) *v13.Service {
 
 func GetAlertmanagerVersion(cr *v1.Observability) string {
 	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.AlertManagerVersion != "" {
+		if release := GetInstalledPrometheusOperatorRelease(cr); release != nil {
+			return limitToOperatorVersion(cr.Spec.SelfContained.AlertManagerVersion, release.AlertmanagerVersion)
+		}
 		return cr.Spec.SelfContained.AlertManagerVersion
 	}
 	return ""
 }
 
+// The URL set in the CR wins over the route or ingress host, which may not be known yet
+func GetAlertmanagerExternalURL(cr *v1.Observability, host string) string {
+	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.AlertManagerExternalURL != "" {
+		return cr.Spec.SelfContained.AlertManagerExternalURL
+	}
+	if host == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%v", host)
+}
+
+func GetAlertmanagerPeers(cr *v1.Observability) []string {
+	if cr.Spec.SelfContained != nil {
+		return cr.Spec.SelfContained.AlertManagerPeers
+	}
+	return nil
+}
+
+// The mesh only detects private IPv4 addresses to advertise, so with IPv6 as the primary family
+// the pod ip from the downward api is advertised
+func GetAlertmanagerClusterAdvertiseAddress(cr *v1.Observability) string {
+	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.AlertManagerClusterAdvertiseAddress != "" {
+		return cr.Spec.SelfContained.AlertManagerClusterAdvertiseAddress
+	}
+	if IsIPv6Primary(cr) {
+		return fmt.Sprintf("[$(%v)]:%d", PodIPEnv, AlertmanagerClusterPort)
+	}
+	return ""
+}
+
 func GetAlertmanagerResourceRequirement(cr *v1.Observability) v13.ResourceRequirements {
+	if resources, ok := cr.GetResources(v1.ResourcesAlertmanager); ok {
+		return resources
+	}
 	if cr.Spec.SelfContained != nil {
 		return cr.Spec.SelfContained.AlertManagerResourceRequirement
 	}
@@ -0,0 +1,244 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Key of the Thanos object storage config in the backup secret
+	BackupObjectStorageKey       = "objstore.yml"
+	GrafanaBackupDefaultInterval = 24 * time.Hour
+	// Prefix of the Grafana backups in the bucket, Thanos ignores objects outside of block directories
+	GrafanaBackupPrefix = "grafana-backups/"
+	ThanosStoreName     = "prometheus-thanos-store"
+	ThanosStoreGrpcPort = 10901
+	ThanosStoreHttpPort = 10902
+	// Projected service account token exchanged for the credentials of the object storage identity
+	ObjectStorageTokenVolume = "object-storage-token"
+	ObjectStorageTokenDir    = "/var/run/secrets/object-storage/serviceaccount"
+	ObjectStorageTokenFile   = "token"
+	// Credential configuration of the Google client libraries for Workload Identity Federation
+	ObjectStorageCredentialsVolume = "object-storage-credentials"
+	ObjectStorageCredentialsDir    = "/etc/object-storage"
+	ObjectStorageCredentialsKey    = "credentials.json"
+	ObjectStorageDefaultExpiration = 3600
+)
+
+func GetBackupGrafanaInterval(cr *v1.Observability) time.Duration {
+	interval, err := time.ParseDuration(cr.Spec.Backup.GrafanaInterval)
+	if err != nil || interval <= 0 {
+		return GrafanaBackupDefaultInterval
+	}
+	return interval
+}
+
+func getThanosStoreLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+		"app":        ThanosStoreName,
+	}
+}
+
+func GetThanosStoreSelectorLabels() map[string]string {
+	return map[string]string{
+		"app": ThanosStoreName,
+	}
+}
+
+func GetThanosStoreDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      ThanosStoreName,
+			Namespace: cr.Namespace,
+			Labels:    getThanosStoreLabels(),
+		},
+	}
+}
+
+func GetThanosStoreService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      ThanosStoreName,
+			Namespace: cr.Namespace,
+			Labels:    getThanosStoreLabels(),
+		},
+	}
+}
+
+// Returns the identity the Thanos components authenticate to object storage with, nil for the
+// static keys of the object storage config
+func GetObjectStorageIdentity(cr *v1.Observability) *v1.ObjectStorageIdentity {
+	if !cr.BackupEnabled() {
+		return nil
+	}
+	return cr.Spec.Backup.Identity
+}
+
+// Annotations of the Prometheus service account, so that the pod identity webhooks of managed
+// clusters recognize the identity as well
+func GetObjectStorageIdentityAnnotations(cr *v1.Observability) map[string]string {
+	identity := GetObjectStorageIdentity(cr)
+	if identity == nil {
+		return nil
+	}
+
+	switch identity.Provider {
+	case v1.ObjectStorageIdentityAWS:
+		return map[string]string{
+			"eks.amazonaws.com/role-arn": identity.RoleARN,
+		}
+	case v1.ObjectStorageIdentityGCP:
+		return map[string]string{
+			"iam.gke.io/gcp-service-account": identity.ServiceAccount,
+		}
+	case v1.ObjectStorageIdentityAzure:
+		return map[string]string{
+			"azure.workload.identity/client-id": identity.ClientID,
+			"azure.workload.identity/tenant-id": identity.TenantID,
+		}
+	}
+	return nil
+}
+
+func getObjectStorageTokenAudience(identity *v1.ObjectStorageIdentity) string {
+	switch identity.Provider {
+	case v1.ObjectStorageIdentityGCP:
+		return fmt.Sprintf("https:%v", identity.WorkloadIdentityProvider)
+	case v1.ObjectStorageIdentityAzure:
+		return "api://AzureADTokenExchange"
+	default:
+		return "sts.amazonaws.com"
+	}
+}
+
+func GetObjectStorageCredentialsConfigMap(cr *v1.Observability) *v14.ConfigMap {
+	return &v14.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "object-storage-credentials",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// External account credentials that exchange the projected token at the Google STS and impersonate
+// the service account
+func GetObjectStorageGCPCredentials(identity *v1.ObjectStorageIdentity) (string, error) {
+	credentials := map[string]interface{}{
+		"type":               "external_account",
+		"audience":           identity.WorkloadIdentityProvider,
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url":          "https://sts.googleapis.com/v1/token",
+		"service_account_impersonation_url": fmt.Sprintf("https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%v:generateAccessToken",
+			identity.ServiceAccount),
+		"credential_source": map[string]interface{}{
+			"file": fmt.Sprintf("%v/%v", ObjectStorageTokenDir, ObjectStorageTokenFile),
+		},
+	}
+	result, err := json.Marshal(credentials)
+	return string(result), err
+}
+
+// Volumes of the pods of the Thanos components with the projected token and the credential
+// configuration
+func GetObjectStorageIdentityVolumes(cr *v1.Observability) []v14.Volume {
+	identity := GetObjectStorageIdentity(cr)
+	if identity == nil {
+		return nil
+	}
+
+	var expiration int64 = ObjectStorageDefaultExpiration
+	if identity.ExpirationSeconds != nil {
+		expiration = *identity.ExpirationSeconds
+	}
+	volumes := []v14.Volume{
+		{
+			Name: ObjectStorageTokenVolume,
+			VolumeSource: v14.VolumeSource{
+				Projected: &v14.ProjectedVolumeSource{
+					Sources: []v14.VolumeProjection{
+						{
+							ServiceAccountToken: &v14.ServiceAccountTokenProjection{
+								Audience:          getObjectStorageTokenAudience(identity),
+								ExpirationSeconds: &expiration,
+								Path:              ObjectStorageTokenFile,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if identity.Provider == v1.ObjectStorageIdentityGCP {
+		volumes = append(volumes, v14.Volume{
+			Name: ObjectStorageCredentialsVolume,
+			VolumeSource: v14.VolumeSource{
+				ConfigMap: &v14.ConfigMapVolumeSource{
+					LocalObjectReference: v14.LocalObjectReference{
+						Name: GetObjectStorageCredentialsConfigMap(cr).Name,
+					},
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+func GetObjectStorageIdentityVolumeMounts(cr *v1.Observability) []v14.VolumeMount {
+	identity := GetObjectStorageIdentity(cr)
+	if identity == nil {
+		return nil
+	}
+
+	mounts := []v14.VolumeMount{
+		{
+			Name:      ObjectStorageTokenVolume,
+			MountPath: ObjectStorageTokenDir,
+			ReadOnly:  true,
+		},
+	}
+	if identity.Provider == v1.ObjectStorageIdentityGCP {
+		mounts = append(mounts, v14.VolumeMount{
+			Name:      ObjectStorageCredentialsVolume,
+			MountPath: ObjectStorageCredentialsDir,
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// The default credential chains of the AWS, Google and Azure SDKs used by Thanos pick up the
+// identity from these variables
+func GetObjectStorageIdentityEnv(cr *v1.Observability) []v14.EnvVar {
+	identity := GetObjectStorageIdentity(cr)
+	if identity == nil {
+		return nil
+	}
+
+	tokenFile := fmt.Sprintf("%v/%v", ObjectStorageTokenDir, ObjectStorageTokenFile)
+	switch identity.Provider {
+	case v1.ObjectStorageIdentityAWS:
+		return []v14.EnvVar{
+			{Name: "AWS_ROLE_ARN", Value: identity.RoleARN},
+			{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: tokenFile},
+		}
+	case v1.ObjectStorageIdentityGCP:
+		return []v14.EnvVar{
+			{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: fmt.Sprintf("%v/%v", ObjectStorageCredentialsDir, ObjectStorageCredentialsKey)},
+		}
+	case v1.ObjectStorageIdentityAzure:
+		return []v14.EnvVar{
+			{Name: "AZURE_CLIENT_ID", Value: identity.ClientID},
+			{Name: "AZURE_TENANT_ID", Value: identity.TenantID},
+			{Name: "AZURE_FEDERATED_TOKEN_FILE", Value: tokenFile},
+			{Name: "AZURE_AUTHORITY_HOST", Value: "https://login.microsoftonline.com/"},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+const (
+	defaultConfigApplyBatchSize         = 50
+	defaultConfigApplyConcurrency       = 4
+	defaultConfigApplyRequestsPerSecond = 20
+	defaultConfigApplyMaxDuration       = time.Minute
+)
+
+func GetConfigApplyBatchSize(cr *v1.Observability) int {
+	if cr.Spec.ConfigApply != nil && cr.Spec.ConfigApply.BatchSize > 0 {
+		return cr.Spec.ConfigApply.BatchSize
+	}
+	return defaultConfigApplyBatchSize
+}
+
+func GetConfigApplyConcurrency(cr *v1.Observability) int {
+	if cr.Spec.ConfigApply != nil && cr.Spec.ConfigApply.Concurrency > 0 {
+		return cr.Spec.ConfigApply.Concurrency
+	}
+	return defaultConfigApplyConcurrency
+}
+
+func GetConfigApplyRequestsPerSecond(cr *v1.Observability) int {
+	if cr.Spec.ConfigApply != nil && cr.Spec.ConfigApply.RequestsPerSecond > 0 {
+		return cr.Spec.ConfigApply.RequestsPerSecond
+	}
+	return defaultConfigApplyRequestsPerSecond
+}
+
+// A batch that was started is always completed, the duration can be exceeded by one batch
+func GetConfigApplyMaxDuration(cr *v1.Observability) time.Duration {
+	if cr.Spec.ConfigApply != nil && cr.Spec.ConfigApply.MaxDuration != "" {
+		duration, err := time.ParseDuration(cr.Spec.ConfigApply.MaxDuration)
+		if err == nil && duration > 0 {
+			return duration
+		}
+	}
+	return defaultConfigApplyMaxDuration
+}
@@ -0,0 +1,183 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// Section of the application menu of the console that lists the links of all stacks
+	ConsoleLinkSection = "Observability"
+	// The console shows the dashboards of the config maps in this namespace that have the label
+	ConsoleDashboardNamespace = "openshift-config-managed"
+	ConsoleDashboardLabel     = "console.openshift.io/dashboard"
+	ConsolePluginPort         = 9443
+	// Links and dashboards are cluster wide or outside of the namespace of the CR, this label tells
+	// the stacks apart
+	consoleNamespaceLabel = "observability-operator/namespace"
+)
+
+var ConsolePluginGroupVersionKind = schema.GroupVersionKind{
+	Group:   "console.openshift.io",
+	Version: "v1alpha1",
+	Kind:    "ConsolePlugin",
+}
+
+// Created by the Grafana operator on OpenShift
+func GetGrafanaRoute(cr *v1.Observability) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "grafana-route",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func getConsoleLabels(cr *v1.Observability) map[string]string {
+	return map[string]string{
+		"managed-by":          "observability-operator",
+		consoleNamespaceLabel: cr.Namespace,
+	}
+}
+
+func GetConsoleLinkSelector(cr *v1.Observability) labels.Selector {
+	return labels.SelectorFromSet(getConsoleLabels(cr))
+}
+
+// Console links are cluster scoped, they are named after the namespace of the stack
+func GetConsoleLink(cr *v1.Observability, component string) *consolev1.ConsoleLink {
+	return &consolev1.ConsoleLink{
+		ObjectMeta: v12.ObjectMeta{
+			Name:   fmt.Sprintf("observability-%s-%s", cr.Namespace, component),
+			Labels: getConsoleLabels(cr),
+		},
+	}
+}
+
+func GetConsoleDashboard(cr *v1.Observability) *v14.ConfigMap {
+	return &v14.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      fmt.Sprintf("observability-stack-%s", cr.Namespace),
+			Namespace: ConsoleDashboardNamespace,
+		},
+	}
+}
+
+func GetConsoleDashboardLabels(cr *v1.Observability) map[string]string {
+	result := getConsoleLabels(cr)
+	result[ConsoleDashboardLabel] = "true"
+	return result
+}
+
+// Dashboard of the pods of the stack. The console queries the cluster monitoring, so it only uses
+// the metrics of kube-state-metrics and the kubelets
+func GetConsoleDashboardJson(cr *v1.Observability) (string, error) {
+	namespace := fmt.Sprintf(`namespace="%s"`, cr.Namespace)
+	graph := func(id int, title string, y int, unit string, expr string, legend string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":      id,
+			"type":    "graph",
+			"title":   title,
+			"gridPos": map[string]int{"x": 12 * ((id - 1) % 2), "y": y, "w": 12, "h": 8},
+			"targets": []map[string]interface{}{
+				{
+					"expr":         expr,
+					"legendFormat": legend,
+					"refId":        "A",
+				},
+			},
+			"yaxes": []map[string]interface{}{
+				{"format": unit, "show": true},
+				{"format": "short", "show": false},
+			},
+		}
+	}
+
+	dashboard := map[string]interface{}{
+		"uid":           fmt.Sprintf("observability-stack-%s", cr.Namespace),
+		"title":         fmt.Sprintf("Observability Stack / %s", cr.Namespace),
+		"tags":          []string{"observability-operator"},
+		"schemaVersion": 27,
+		"time":          map[string]string{"from": "now-1h", "to": "now"},
+		"panels": []map[string]interface{}{
+			graph(1, "Pods not ready", 0, "short",
+				fmt.Sprintf(`sum by (pod) (kube_pod_status_ready{%s,condition="false"})`, namespace), "{{pod}}"),
+			graph(2, "Container restarts", 0, "short",
+				fmt.Sprintf(`sum by (container) (increase(kube_pod_container_status_restarts_total{%s}[10m]))`, namespace), "{{container}}"),
+			graph(3, "CPU usage", 8, "short",
+				fmt.Sprintf(`sum by (pod) (rate(container_cpu_usage_seconds_total{%s,container!="",container!="POD"}[5m]))`, namespace), "{{pod}}"),
+			graph(4, "Memory usage", 8, "bytes",
+				fmt.Sprintf(`sum by (pod) (container_memory_working_set_bytes{%s,container!="",container!="POD"})`, namespace), "{{pod}}"),
+		},
+	}
+
+	bytes, err := json.Marshal(dashboard)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+func getConsolePluginName(cr *v1.Observability) string {
+	return fmt.Sprintf("observability-%s", cr.Namespace)
+}
+
+func GetConsolePluginSelectorLabels() map[string]string {
+	return map[string]string{
+		"app": "observability-console-plugin",
+	}
+}
+
+func GetConsolePluginDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-console-plugin",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// The service CA issues the serving certificate of the plugin into the secret of the same name
+func GetConsolePluginService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-console-plugin",
+			Namespace: cr.Namespace,
+			Annotations: map[string]string{
+				"service.beta.openshift.io/serving-cert-secret-name": "observability-console-plugin",
+			},
+		},
+	}
+}
+
+// Cluster scoped, named after the namespace. This is the name cluster admins enable in the console
+// operator config
+func GetConsolePlugin(cr *v1.Observability) *unstructured.Unstructured {
+	plugin := &unstructured.Unstructured{}
+	plugin.SetGroupVersionKind(ConsolePluginGroupVersionKind)
+	plugin.SetName(getConsolePluginName(cr))
+	return plugin
+}
+
+func GetConsolePluginSpec(cr *v1.Observability) map[string]interface{} {
+	service := GetConsolePluginService(cr)
+	return map[string]interface{}{
+		"displayName": fmt.Sprintf("Observability stack in %s", cr.Namespace),
+		"service": map[string]interface{}{
+			"name":      service.Name,
+			"namespace": service.Namespace,
+			"port":      int64(ConsolePluginPort),
+			"basePath":  "/",
+		},
+	}
+}
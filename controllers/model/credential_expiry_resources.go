@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Recorded by the generated rule, one series per secret with the expiry as value
+	CredentialExpiryRecord  = "observability_credential_expiry_timestamp_seconds"
+	AlertCredentialExpiring = "CredentialExpiringSoon"
+	// Annotation on referenced secrets with the time their credentials expire, in RFC 3339
+	CredentialExpiresAnnotation = "observability-operator/expires-at"
+	defaultExpiryWindow         = 30 * 24 * time.Hour
+)
+
+func GetCredentialExpiryRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-credential-expiry",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetExpiryWindow(cr *v1.Observability) time.Duration {
+	if cr.Spec.ExpiryMonitoring != nil && cr.Spec.ExpiryMonitoring.Window != "" {
+		window, err := time.ParseDuration(cr.Spec.ExpiryMonitoring.Window)
+		if err == nil && window > 0 {
+			return window
+		}
+	}
+	return defaultExpiryWindow
+}
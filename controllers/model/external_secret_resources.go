@@ -0,0 +1,77 @@
+package model
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const defaultExternalSecretRefresh = "1h"
+
+// The External Secrets Operator API is not vendored, external secrets are managed as unstructured objects
+var ExternalSecretGroupVersionKind = schema.GroupVersionKind{
+	Group:   "external-secrets.io",
+	Version: "v1beta1",
+	Kind:    "ExternalSecret",
+}
+
+func GetExternalSecretLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+		"app":        "external-secret",
+	}
+}
+
+// Selects the external secrets of the operator, including those removed from the CR
+func GetExternalSecretSelector() labels.Selector {
+	return labels.SelectorFromSet(GetExternalSecretLabels())
+}
+
+// The external secret has the name of the secret it materializes
+func GetExternalSecret(cr *v1.Observability, name string) *unstructured.Unstructured {
+	secret := &unstructured.Unstructured{}
+	secret.SetGroupVersionKind(ExternalSecretGroupVersionKind)
+	secret.SetName(name)
+	secret.SetNamespace(cr.Namespace)
+	return secret
+}
+
+// Spec of the external secret. The secret is owned by the external secret and deleted with it
+func GetExternalSecretSpec(secret v1.ExternalSecret) map[string]interface{} {
+	refresh := secret.RefreshInterval
+	if refresh == "" {
+		refresh = defaultExternalSecretRefresh
+	}
+	kind := secret.StoreRef.Kind
+	if kind == "" {
+		kind = v1.SecretStoreKind
+	}
+
+	var data []interface{}
+	for _, d := range secret.Data {
+		remoteRef := map[string]interface{}{
+			"key": d.RemoteKey,
+		}
+		if d.Property != "" {
+			remoteRef["property"] = d.Property
+		}
+		data = append(data, map[string]interface{}{
+			"secretKey": d.SecretKey,
+			"remoteRef": remoteRef,
+		})
+	}
+
+	return map[string]interface{}{
+		"refreshInterval": refresh,
+		"secretStoreRef": map[string]interface{}{
+			"name": secret.StoreRef.Name,
+			"kind": kind,
+		},
+		"target": map[string]interface{}{
+			"name":           secret.Name,
+			"creationPolicy": "Owner",
+		},
+		"data": data,
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+// FIPS validated images of the components, built with the RHEL crypto libraries. Components
+// missing here need an image override in FIPS mode, which the webhook enforces
+var fipsImages = map[string]string{
+	v1.ImagePrometheus:        "registry.redhat.io/openshift4/ose-prometheus:v4.8",
+	v1.ImageAlertmanager:      "registry.redhat.io/openshift4/ose-prometheus-alertmanager:v4.8",
+	v1.ImageGrafana:           "registry.redhat.io/rhel8/grafana:7",
+	v1.ImageOAuthProxy:        "registry.redhat.io/openshift4/ose-oauth-proxy:v4.8",
+	v1.ImageKubeRbacProxy:     "registry.redhat.io/openshift4/ose-kube-rbac-proxy:v4.8",
+	v1.ImageThanos:            "registry.redhat.io/openshift4/ose-thanos-rhel8:v4.8",
+	v1.ImagePrometheusAdapter: "registry.redhat.io/openshift4/ose-prometheus-adapter:v4.8",
+}
+
+// TLS 1.2 cipher suites approved by FIPS 140-2. They rule out older TLS versions, and the TLS 1.3
+// suites are compliant
+var fipsCipherSuites = []string{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+}
+
+// Returns the kube-rbac-proxy arguments restricting its TLS server to FIPS approved ciphers. The
+// other components get them from the crypto libraries of their FIPS validated images
+func GetKubeRbacProxyFIPSArgs(cr *v1.Observability) []string {
+	if !cr.FIPSModeEnabled() {
+		return nil
+	}
+	return []string{fmt.Sprintf("--tls-cipher-suites=%v", strings.Join(fipsCipherSuites, ","))}
+}
@@ -1,17 +1,35 @@
 package model
 
 import (
+	"sort"
+	"time"
+
 	v1alpha12 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
 	v13 "github.com/operator-framework/api/pkg/operators/v1"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	v14 "k8s.io/api/core/v1"
 	v15 "k8s.io/api/rbac/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Key of the API token in the secret referenced in spec.grafana.external.tokenSecret
+const GrafanaExternalTokenKey = "token"
+
+const (
+	// API keys with this name prefix are managed by the operator
+	GrafanaAPIKeyPrefix = "observability-"
+	// Label of the secrets with the API keys, the value is the name of the key
+	GrafanaAPIKeyLabel = "observability.redhat.com/grafana-api-key"
+	// Key of the API key in its secret
+	GrafanaAPIKeyTokenKey = "token"
+)
+
 var defaultGrafanaLabelSelectors = map[string]string{"app": "strimzi"}
 
+const defaultDatasourceHealthInterval = 5 * time.Minute
+
 func GetDefaultNameGrafana(cr *v1.Observability) string {
 	if cr.Spec.SelfContained != nil && cr.Spec.GrafanaDefaultName != "" {
 		return cr.Spec.GrafanaDefaultName
@@ -46,6 +64,16 @@ func GetGrafanaOperatorGroup(cr *v1.Observability) *v13.OperatorGroup {
 	}
 }
 
+// Created by OLM from the CSV of the Grafana operator
+func GetGrafanaOperatorDeployment(cr *v1.Observability) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "grafana-operator",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
 func GetGrafanaProxySecret(cr *v1.Observability) *v14.Secret {
 	return &v14.Secret{
 		ObjectMeta: v12.ObjectMeta{
@@ -80,6 +108,200 @@ func GetGrafanaCr(cr *v1.Observability) *v1alpha12.Grafana {
 	}
 }
 
+func GetGrafanaPluginsConfigMap(cr *v1.Observability) *v14.ConfigMap {
+	return &v14.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "grafana-plugins",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Grafana reads the SMTP credentials from the environment
+func GetGrafanaSmtpSecret(cr *v1.Observability) *v14.Secret {
+	return &v14.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "grafana-smtp",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Grafana reads the url of the image renderer from the environment
+func GetGrafanaImageRendererConfigMap(cr *v1.Observability) *v14.ConfigMap {
+	return &v14.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "grafana-image-renderer",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Grafana reads the settings of the sharing policy that are not part of the Grafana CR from the
+// environment
+func GetGrafanaSharingConfigMap(cr *v1.Observability) *v14.ConfigMap {
+	return &v14.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "grafana-sharing",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Grafana reads the LDAP configuration from a file, which is mounted from this secret
+func GetGrafanaLDAPSecret(cr *v1.Observability) *v14.Secret {
+	return &v14.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "grafana-ldap",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Grafana reads the credentials of the external database from the environment
+func GetGrafanaDatabaseSecret(cr *v1.Observability) *v14.Secret {
+	return &v14.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "grafana-database",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetGrafanaLDAP(cr *v1.Observability) *v1.GrafanaLDAP {
+	if cr.GrafanaLDAPEnabled() {
+		return cr.Spec.Grafana.Auth.LDAP
+	}
+	return nil
+}
+
+func GetGrafanaSmtp(cr *v1.Observability) *v1.GrafanaSmtp {
+	if cr.Spec.Grafana != nil {
+		return cr.Spec.Grafana.Smtp
+	}
+	return nil
+}
+
+func GetGrafanaExternal(cr *v1.Observability) *v1.GrafanaExternal {
+	if cr.Spec.Grafana != nil {
+		return cr.Spec.Grafana.External
+	}
+	return nil
+}
+
+// Url of Prometheus in the Grafana datasource
+func GetGrafanaDatasourceUrl(cr *v1.Observability) string {
+	external := GetGrafanaExternal(cr)
+	if external != nil && external.DatasourceURL != "" {
+		return external.DatasourceURL
+	}
+	if cr.UserWorkloadMonitoringEnabled() {
+		return GetThanosQuerierUrl(cr)
+	}
+	if UsesInternalTLSDatasource(cr) {
+		return GetPrometheusInternalTLSUrl(cr)
+	}
+	return GetPrometheusQueryUrl(cr)
+}
+
+func GetGrafanaDatasourceName(cr *v1.Observability) string {
+	external := GetGrafanaExternal(cr)
+	if external != nil && external.DatasourceName != "" {
+		return external.DatasourceName
+	}
+	return "Prometheus"
+}
+
+// Names of the datasources provisioned by the operator that dashboards can reference
+func GetGrafanaDatasourceNames(cr *v1.Observability) []string {
+	names := []string{GetGrafanaDatasourceName(cr)}
+	if cr.TracingEnabled() {
+		names = append(names, TempoDatasourceName)
+	}
+	if cr.AlertHistoryEnabled() {
+		names = append(names, AlertHistoryDatasourceName)
+	}
+	return names
+}
+
+func GetGrafanaContactPoints(cr *v1.Observability) []v1.GrafanaContactPoint {
+	if cr.Spec.Grafana != nil {
+		return cr.Spec.Grafana.ContactPoints
+	}
+	return nil
+}
+
+func GetGrafanaOrganizations(cr *v1.Observability) []v1.GrafanaOrganization {
+	if cr.Spec.Grafana != nil {
+		return cr.Spec.Grafana.Organizations
+	}
+	return nil
+}
+
+func GetGrafanaTeams(cr *v1.Observability) []v1.GrafanaTeam {
+	if cr.Spec.Grafana != nil {
+		return cr.Spec.Grafana.Teams
+	}
+	return nil
+}
+
+func GetGrafanaAPIKeys(cr *v1.Observability) []v1.GrafanaAPIKey {
+	if cr.Spec.Grafana != nil {
+		return cr.Spec.Grafana.APIKeys
+	}
+	return nil
+}
+
+func GetGrafanaAPIKeySecret(cr *v1.Observability, apiKey v1.GrafanaAPIKey) *v14.Secret {
+	return &v14.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      apiKey.Secret,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by":       "observability-operator",
+				GrafanaAPIKeyLabel: apiKey.Name,
+			},
+		},
+	}
+}
+
+// Returns the Grafana plugins requested by the CR and the repositories, sorted by name. The CR
+// takes precedence, then the first repository requesting a plugin.
+func GetGrafanaPlugins(cr *v1.Observability, indexes []v1.RepositoryIndex) []v1.GrafanaPlugin {
+	plugins := map[string]v1.GrafanaPlugin{}
+	for _, plugin := range cr.Spec.GrafanaPlugins {
+		plugins[plugin.Name] = plugin
+	}
+
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Grafana == nil {
+			continue
+		}
+		for _, plugin := range index.Config.Grafana.Plugins {
+			if _, ok := plugins[plugin.Name]; !ok {
+				plugins[plugin.Name] = plugin
+			}
+		}
+	}
+
+	// The history dashboard needs the SimpleJSON datasource, unless another version was requested
+	if _, ok := plugins[AlertHistoryDatasourceType]; !ok && cr.AlertHistoryEnabled() {
+		plugins[AlertHistoryDatasourceType] = v1.GrafanaPlugin{
+			Name:    AlertHistoryDatasourceType,
+			Version: AlertHistoryDatasourceVersion,
+		}
+	}
+
+	var result []v1.GrafanaPlugin
+	for _, plugin := range plugins {
+		result = append(result, plugin)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
 func GetGrafanaDatasource(cr *v1.Observability) *v1alpha12.GrafanaDataSource {
 	return &v1alpha12.GrafanaDataSource{
 		ObjectMeta: v12.ObjectMeta{
@@ -89,6 +311,24 @@ func GetGrafanaDatasource(cr *v1.Observability) *v1alpha12.GrafanaDataSource {
 	}
 }
 
+// Every repair of the datasource health check provisions the datasources with a new version
+func GetGrafanaDatasourceVersion(cr *v1.Observability) int {
+	if cr.Status.DatasourceHealth != nil {
+		return 1 + int(cr.Status.DatasourceHealth.Repairs)
+	}
+	return 1
+}
+
+func GetDatasourceHealthInterval(cr *v1.Observability) time.Duration {
+	if cr.Spec.Grafana != nil && cr.Spec.Grafana.DatasourceHealth != nil && cr.Spec.Grafana.DatasourceHealth.Interval != "" {
+		interval, err := time.ParseDuration(cr.Spec.Grafana.DatasourceHealth.Interval)
+		if err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return defaultDatasourceHealthInterval
+}
+
 func GetGrafanaDashboardLabelSelectors(cr *v1.Observability, indexes []v1.RepositoryIndex) *v12.LabelSelector {
 	// if selfcontained is set override default
 	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.GrafanaDashboardLabelSelector != nil {
@@ -112,6 +352,9 @@ func GetGrafanaDashboardLabelSelectors(cr *v1.Observability, indexes []v1.Reposi
 	}
 }
 func GetGrafanaResourceRequirement(cr *v1.Observability) *v14.ResourceRequirements {
+	if resources, ok := cr.GetResources(v1.ResourcesGrafana); ok {
+		return &resources
+	}
 	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.GrafanaResourceRequirement != nil {
 		return cr.Spec.SelfContained.GrafanaResourceRequirement
 	}
@@ -119,6 +362,9 @@ func GetGrafanaResourceRequirement(cr *v1.Observability) *v14.ResourceRequiremen
 }
 
 func GetGrafanaOperatorResourceRequirement(cr *v1.Observability) v14.ResourceRequirements {
+	if resources, ok := cr.GetResources(v1.ResourcesGrafanaOperator); ok {
+		return resources
+	}
 	if cr.Spec.SelfContained != nil {
 		return cr.Spec.SelfContained.GrafanaOperatorResourceRequirement
 	}
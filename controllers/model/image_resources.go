@@ -0,0 +1,56 @@
+package model
+
+import (
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+const (
+	OAuthProxyImage             = "quay.io/openshift/origin-oauth-proxy:4.8"
+	OAuth2ProxyImage            = "quay.io/oauth2-proxy/oauth2-proxy:v7.2.1"
+	BlackboxExporterImage       = "quay.io/prometheus/blackbox-exporter:v0.19.0"
+	PromtailImage               = "quay.io/integreatly/promtail:latest"
+	TokenRefresherImage         = "quay.io/rhoas/mk-token-refresher"
+	PrometheusCatalogIndexImage = "quay.io/integreatly/custom-prometheus-index:1.0.0"
+	GrafanaCatalogIndexImage    = "quay.io/rhoas/grafana-operator-index:v3.10.4"
+	KubeRbacProxyImage          = "quay.io/brancz/kube-rbac-proxy:v0.11.0"
+	PromLabelProxyImage         = "quay.io/prometheuscommunity/prom-label-proxy:v0.3.0"
+	ThanosImage                 = "quay.io/thanos/thanos:v0.17.2"
+	GrafanaImageRendererImage   = "docker.io/grafana/grafana-image-renderer:3.0.1"
+	PrometheusAdapterImage      = "k8s.gcr.io/prometheus-adapter/prometheus-adapter:v0.9.1"
+)
+
+// Returns the image override for a component from spec.imageOverrides. In FIPS mode components
+// default to their FIPS validated image
+func GetImageOverride(cr *v1.Observability, component string) (string, bool) {
+	image, ok := cr.Spec.ImageOverrides[component]
+	if ok && image != "" {
+		return image, true
+	}
+	if cr.FIPSModeEnabled() {
+		image, ok = fipsImages[component]
+		return image, ok
+	}
+	return "", false
+}
+
+// Returns the image to use for a component. Overrides are used as they are, so they can
+// reference images by digest as required by ImageContentSourcePolicy mirrors.
+func GetImage(cr *v1.Observability, component string, defaultImage string) string {
+	if image, ok := GetImageOverride(cr, component); ok {
+		return image
+	}
+	return defaultImage
+}
+
+// Returns the tag of an image, the digest for images referenced by digest and latest without either
+func GetImageVersion(image string) string {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
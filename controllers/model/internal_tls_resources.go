@@ -0,0 +1,176 @@
+package model
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/core/v1"
+	v14 "k8s.io/api/rbac/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The cert-manager API is not vendored, issuers and certificates are managed as unstructured objects
+var (
+	IssuerGroupVersionKind = schema.GroupVersionKind{
+		Group:   "cert-manager.io",
+		Version: "v1",
+		Kind:    "Issuer",
+	}
+	CertificateGroupVersionKind = schema.GroupVersionKind{
+		Group:   "cert-manager.io",
+		Version: "v1",
+		Kind:    "Certificate",
+	}
+)
+
+// Components with a certificate issued by the internal CA
+const (
+	InternalTLSPrometheus   = "prometheus"
+	InternalTLSAlertmanager = "alertmanager"
+	InternalTLSGrafana      = "grafana"
+	InternalTLSPromtail     = "promtail"
+)
+
+const (
+	InternalTLSCAName = "observability-internal-ca"
+	// Port of the kube-rbac-proxy sidecars of Prometheus and Alertmanager that require client certificates.
+	// Alertmanager uses 9094 for its mesh
+	InternalTLSPort     = 9096
+	InternalTLSPortName = "mtls"
+	// Subresource of the Prometheus and Alertmanager services the client certificate users are granted
+	InternalTLSSubresource = "mtls"
+)
+
+var InternalTLSComponents = []string{
+	InternalTLSPrometheus,
+	InternalTLSAlertmanager,
+	InternalTLSGrafana,
+	InternalTLSPromtail,
+}
+
+// Internal TLS is only used if cert-manager is installed, otherwise the InternalTLSReady condition
+// reports why it is not
+func IsInternalTLSEnabled(cr *v1.Observability) bool {
+	return cr.InternalTLSEnabled() && cr.Status.Capabilities != nil && cr.Status.Capabilities.CertManager
+}
+
+func getInternalTLSObject(cr *v1.Observability, gvk schema.GroupVersionKind, name string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	object.SetName(name)
+	object.SetNamespace(cr.Namespace)
+	return object
+}
+
+// Self signed issuer of the CA certificate
+func GetInternalTLSSelfSignedIssuer(cr *v1.Observability) *unstructured.Unstructured {
+	return getInternalTLSObject(cr, IssuerGroupVersionKind, "observability-selfsigned")
+}
+
+func GetInternalTLSCACertificate(cr *v1.Observability) *unstructured.Unstructured {
+	return getInternalTLSObject(cr, CertificateGroupVersionKind, InternalTLSCAName)
+}
+
+// Issuer of the component certificates, signing with the CA certificate
+func GetInternalTLSCAIssuer(cr *v1.Observability) *unstructured.Unstructured {
+	return getInternalTLSObject(cr, IssuerGroupVersionKind, InternalTLSCAName)
+}
+
+func GetInternalTLSCertificate(cr *v1.Observability, component string) *unstructured.Unstructured {
+	return getInternalTLSObject(cr, CertificateGroupVersionKind, GetInternalTLSSecretName(component))
+}
+
+// Secret with the tls.crt, tls.key and ca.crt keys of a component
+func GetInternalTLSSecretName(component string) string {
+	return fmt.Sprintf("observability-%v-tls", component)
+}
+
+func GetInternalTLSSecret(cr *v1.Observability, component string) *v13.Secret {
+	return &v13.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      GetInternalTLSSecretName(component),
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Common name of a component certificate, the user kube-rbac-proxy authorizes. The system: prefix
+// is reserved, so the name can't be taken by a user of the cluster
+func GetInternalTLSUser(component string) string {
+	return fmt.Sprintf("system:observability:%v", component)
+}
+
+// Service names of the servers, clients only have a common name
+func GetInternalTLSDNSNames(cr *v1.Observability, component string) []string {
+	var service string
+	switch component {
+	case InternalTLSPrometheus:
+		service = GetPrometheusService(cr).Name
+	case InternalTLSAlertmanager:
+		service = GetAlertmanagerService(cr).Name
+	case InternalTLSGrafana:
+		// Serving certificate of the Grafana proxy on other distributions than OpenShift
+		service = "grafana-service"
+	default:
+		return nil
+	}
+	return []string{
+		service,
+		fmt.Sprintf("%v.%v.svc", service, cr.Namespace),
+		fmt.Sprintf("%v.%v.svc.cluster.local", service, cr.Namespace),
+	}
+}
+
+func GetInternalTLSRole(cr *v1.Observability) *v14.Role {
+	return &v14.Role{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-internal-tls",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetInternalTLSRoleBinding(cr *v1.Observability) *v14.RoleBinding {
+	return &v14.RoleBinding{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-internal-tls",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetInternalTLSProxyConfigMap(cr *v1.Observability) *v13.ConfigMap {
+	return &v13.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-internal-tls-proxy",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Config of the kube-rbac-proxy in front of a service. Clients are authorized to call the service
+// if they are allowed to get (GET requests) or create (POST requests) the mtls subresource of it
+func GetInternalTLSProxyConfig(cr *v1.Observability, service string) string {
+	return fmt.Sprintf(`authorization:
+  resourceAttributes:
+    apiVersion: v1
+    resource: services
+    subresource: %v
+    namespace: %v
+    name: %v
+`, InternalTLSSubresource, cr.Namespace, service)
+}
+
+// Url of Prometheus through the kube-rbac-proxy sidecar that requires a client certificate
+func GetPrometheusInternalTLSUrl(cr *v1.Observability) string {
+	return fmt.Sprintf("https://%v.%v.svc:%d", GetPrometheusService(cr).Name, cr.Namespace, InternalTLSPort)
+}
+
+// Grafana queries Prometheus through the sidecar unless another query endpoint is configured. With
+// Thanos, Grafana queries Thanos Query, which is not covered by internal TLS
+func UsesInternalTLSDatasource(cr *v1.Observability) bool {
+	return IsInternalTLSEnabled(cr) && GetGrafanaExternal(cr) == nil && !cr.UserWorkloadMonitoringEnabled() &&
+		!IsThanosEnabled(cr)
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// Label of the resources adopted from the v2 operator
+	MigratedLabel = "observability-operator/migrated-from"
+	MigratedFrom  = "v2"
+)
+
+// Resources the v2 operator created for a CR, by the names it gave them. The v3 operator kept the
+// names, the resources are updated in place by the stages once adopted. Losing any of them on
+// upgrade loses data or access: the volumes of Prometheus and Alertmanager go with their CRs
+func GetV2Resources(cr *v1.Observability) []runtime.Object {
+	return []runtime.Object{
+		GetPrometheus(cr),
+		GetAlertmanagerCr(cr),
+		GetGrafanaCr(cr),
+		GetPrometheusProxySecret(cr),
+		GetAlertmanagerProxySecret(cr),
+		GetGrafanaProxySecret(cr),
+	}
+}
+
+// Returns true if one of the resources of GetV2Resources is still owned by the CR. The v2 operator
+// made the CR the owner of its resources, the garbage collector would delete them with the v2 CR.
+// The v3 operator never sets owner references to the CR, its resources of the same name are left
+// alone
+func IsV2Resource(object metav1.Object) bool {
+	if IsMigrated(object) {
+		return false
+	}
+	for _, owner := range object.GetOwnerReferences() {
+		if IsObservabilityOwner(owner) {
+			return true
+		}
+	}
+	return false
+}
+
+func IsObservabilityOwner(owner metav1.OwnerReference) bool {
+	return owner.Kind == "Observability" && owner.APIVersion == v1.GroupVersion.String()
+}
+
+// Returns true if the object was adopted from the v2 operator
+func IsMigrated(object metav1.Object) bool {
+	return object.GetLabels()[MigratedLabel] != ""
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsV2Resource(t *testing.T) {
+	observabilityOwner := metav1.OwnerReference{APIVersion: "observability.redhat.com/v1", Kind: "Observability", Name: "observability-stack"}
+	tests := []struct {
+		name   string
+		object metav1.ObjectMeta
+		want   bool
+	}{
+		{
+			name:   "owned by an Observability CR",
+			object: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{observabilityOwner}},
+			want:   true,
+		},
+		{
+			name: "already adopted",
+			object: metav1.ObjectMeta{
+				Labels:          map[string]string{MigratedLabel: MigratedFrom},
+				OwnerReferences: []metav1.OwnerReference{observabilityOwner},
+			},
+			want: false,
+		},
+		{
+			name:   "created by the v3 operator",
+			object: metav1.ObjectMeta{Labels: map[string]string{"managed-by": "observability-operator"}},
+			want:   false,
+		},
+		{
+			name:   "labelled like the v2 operator but not owned by the CR",
+			object: metav1.ObjectMeta{Labels: map[string]string{"app.kubernetes.io/managed-by": "observability-operator"}},
+			want:   false,
+		},
+		{
+			name: "owned by another kind",
+			object: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "monitoring.coreos.com/v1", Kind: "Prometheus", Name: "kafka-prometheus"},
+			}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsV2Resource(&tt.object); got != tt.want {
+				t.Errorf("IsV2Resource() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"net"
+	"strconv"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/api/core/v1"
+)
+
+// Environment variable with the ip of the pod, set from the downward api
+const PodIPEnv = "POD_IP"
+
+// Returns true if IPv6 is the primary family in spec.networking
+func IsIPv6Primary(cr *v1.Observability) bool {
+	families := cr.IPFamilies()
+	return len(families) > 0 && families[0] == v12.IPv6Protocol
+}
+
+// Returns the ip family policy of the services, empty for the cluster default
+func GetIPFamilyPolicy(cr *v1.Observability) string {
+	switch len(cr.IPFamilies()) {
+	case 1:
+		return "SingleStack"
+	case 2:
+		return "RequireDualStack"
+	default:
+		return ""
+	}
+}
+
+// Returns the host servers bind to on all interfaces. The IPv6 wildcard accepts IPv4 connections
+// too on dual-stack pods. Loopback addresses stay IPv4, pods have 127.0.0.1 on IPv6-only clusters
+// as well
+func getListenHost(cr *v1.Observability) string {
+	if IsIPv6Primary(cr) {
+		return "::"
+	}
+	return "0.0.0.0"
+}
+
+func GetListenAddress(cr *v1.Observability, port int) string {
+	return net.JoinHostPort(getListenHost(cr), strconv.Itoa(port))
+}
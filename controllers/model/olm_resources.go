@@ -0,0 +1,25 @@
+package model
+
+import (
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+// With the manual and the rollback policy the subscriptions require approval, so that
+// the operator (or the user) decides which install plans are applied
+// With an upgrade window the install plans are approved by the operator, in the window
+func GetSubscriptionInstallPlanApproval(cr *v1.Observability) v1alpha1.Approval {
+	if cr.GetInstallPlanApproval() == v1.InstallPlanApprovalAutomatic && cr.Spec.UpgradeWindow == nil {
+		return v1alpha1.ApprovalAutomatic
+	}
+	return v1alpha1.ApprovalManual
+}
+
+// After a rollback the subscription starts at the last CSV that was installed successfully
+func GetSubscriptionStartingCSV(s *v1.ObservabilityStatus, subscription string, defaultCSV string) string {
+	status := s.GetSubscriptionStatus(subscription)
+	if status != nil && status.FailedCSV != "" && status.InstalledCSV != "" {
+		return status.InstalledCSV
+	}
+	return defaultCSV
+}
@@ -0,0 +1,230 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1alpha12 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// Label of the resources generated for a workload profile, the value is the name of the profile
+	ProfileLabel = "observability-operator/profile"
+	// Annotation with the version of the profile the resources were generated from
+	ProfileVersionAnnotation = "observability-operator/profile-version"
+	profileResourcePrefix    = "generated-profile-"
+)
+
+// Profile bundles the pod monitor, the alerts and the dashboard of a common workload. The version is
+// raised whenever the bundle changes
+type Profile struct {
+	Name    string
+	Version string
+	// Pods of the workload in any namespace, and the named port serving their metrics
+	PodSelector v12.LabelSelector
+	Port        string
+	Rules       []prometheusv1.Rule
+	Dashboard   string
+	Panels      []ProfilePanel
+}
+
+// ProfilePanel is a time series panel of the dashboard of a profile
+type ProfilePanel struct {
+	Title string
+	Expr  string
+	// Grafana unit of the values, e.g. bytes or percentunit
+	Unit string
+}
+
+func profileRule(alert string, expr string, duration string, severity string, message string) prometheusv1.Rule {
+	return prometheusv1.Rule{
+		Alert: alert,
+		Expr:  intstr.FromString(expr),
+		For:   duration,
+		Labels: map[string]string{
+			"severity": severity,
+		},
+		Annotations: map[string]string{
+			"message": message,
+		},
+	}
+}
+
+func GetProfile(name string) *Profile {
+	switch name {
+	case v1.ProfileKafka:
+		// Kafka brokers deployed by Strimzi with the JMX exporter enabled
+		return &Profile{
+			Name:    v1.ProfileKafka,
+			Version: "1",
+			PodSelector: v12.LabelSelector{
+				MatchLabels: map[string]string{"strimzi.io/kind": "Kafka"},
+			},
+			Port: "tcp-prometheus",
+			Rules: []prometheusv1.Rule{
+				profileRule("KafkaUnderReplicatedPartitions",
+					`sum by (namespace, pod) (kafka_server_replicamanager_underreplicatedpartitions) > 0`, "10m", "warning",
+					"Kafka broker {{ $labels.pod }} has under replicated partitions."),
+				profileRule("KafkaOfflinePartitions",
+					`sum by (namespace) (kafka_controller_kafkacontroller_offlinepartitionscount) > 0`, "5m", "critical",
+					"Kafka cluster in {{ $labels.namespace }} has offline partitions."),
+			},
+			Dashboard: "Kafka",
+			Panels: []ProfilePanel{
+				{Title: "Messages in", Expr: `sum by (namespace, pod) (rate(kafka_server_brokertopicmetrics_messagesin_total[5m]))`},
+				{Title: "Bytes in", Expr: `sum by (namespace, pod) (rate(kafka_server_brokertopicmetrics_bytesin_total[5m]))`, Unit: "Bps"},
+				{Title: "Under replicated partitions", Expr: `sum by (namespace, pod) (kafka_server_replicamanager_underreplicatedpartitions)`},
+			},
+		}
+	case v1.ProfilePostgres:
+		// PostgreSQL instances scraped through the postgres_exporter
+		return &Profile{
+			Name:    v1.ProfilePostgres,
+			Version: "1",
+			PodSelector: v12.LabelSelector{
+				MatchLabels: map[string]string{"app.kubernetes.io/name": "prometheus-postgres-exporter"},
+			},
+			Port: "http",
+			Rules: []prometheusv1.Rule{
+				profileRule("PostgresDown", `pg_up == 0`, "5m", "critical",
+					"PostgreSQL behind {{ $labels.pod }} is down."),
+				profileRule("PostgresTooManyConnections",
+					`sum by (namespace, pod) (pg_stat_activity_count) / max by (namespace, pod) (pg_settings_max_connections) > 0.9`, "10m", "warning",
+					"PostgreSQL behind {{ $labels.pod }} uses more than 90% of its connections."),
+			},
+			Dashboard: "PostgreSQL",
+			Panels: []ProfilePanel{
+				{Title: "Connections", Expr: `sum by (namespace, pod) (pg_stat_activity_count)`},
+				{Title: "Commits", Expr: `sum by (namespace, pod) (rate(pg_stat_database_xact_commit[5m]))`},
+				{Title: "Deadlocks", Expr: `sum by (namespace, pod) (rate(pg_stat_database_deadlocks[5m]))`},
+			},
+		}
+	case v1.ProfileRedis:
+		// Redis instances scraped through the redis_exporter
+		return &Profile{
+			Name:    v1.ProfileRedis,
+			Version: "1",
+			PodSelector: v12.LabelSelector{
+				MatchLabels: map[string]string{"app.kubernetes.io/name": "prometheus-redis-exporter"},
+			},
+			Port: "redis-exporter",
+			Rules: []prometheusv1.Rule{
+				profileRule("RedisDown", `redis_up == 0`, "5m", "critical",
+					"Redis behind {{ $labels.pod }} is down."),
+				profileRule("RedisMemoryHigh",
+					`redis_memory_used_bytes / redis_memory_max_bytes > 0.9 and redis_memory_max_bytes > 0`, "10m", "warning",
+					"Redis behind {{ $labels.pod }} uses more than 90% of its max memory."),
+			},
+			Dashboard: "Redis",
+			Panels: []ProfilePanel{
+				{Title: "Commands", Expr: `sum by (namespace, pod) (rate(redis_commands_processed_total[5m]))`},
+				{Title: "Memory used", Expr: `sum by (namespace, pod) (redis_memory_used_bytes)`, Unit: "bytes"},
+				{Title: "Connected clients", Expr: `sum by (namespace, pod) (redis_connected_clients)`},
+			},
+		}
+	case v1.ProfileNginxIngress:
+		// Controllers of the NGINX ingress, as deployed by its Helm chart
+		return &Profile{
+			Name:    v1.ProfileNginxIngress,
+			Version: "1",
+			PodSelector: v12.LabelSelector{
+				MatchLabels: map[string]string{
+					"app.kubernetes.io/name":      "ingress-nginx",
+					"app.kubernetes.io/component": "controller",
+				},
+			},
+			Port: "metrics",
+			Rules: []prometheusv1.Rule{
+				profileRule("NginxIngressHighErrorRate",
+					`sum by (namespace, ingress) (rate(nginx_ingress_controller_requests{status=~"5.."}[5m]))
+/ sum by (namespace, ingress) (rate(nginx_ingress_controller_requests[5m])) > 0.05`, "10m", "warning",
+					"More than 5% of the requests to ingress {{ $labels.namespace }}/{{ $labels.ingress }} fail."),
+				profileRule("NginxIngressConfigReloadFailed",
+					`nginx_ingress_controller_config_last_reload_successful == 0`, "10m", "warning",
+					"NGINX ingress controller {{ $labels.pod }} failed to reload its configuration."),
+			},
+			Dashboard: "NGINX Ingress",
+			Panels: []ProfilePanel{
+				{Title: "Requests", Expr: `sum by (namespace, ingress) (rate(nginx_ingress_controller_requests[5m]))`},
+				{Title: "Error ratio", Expr: `sum by (namespace, ingress) (rate(nginx_ingress_controller_requests{status=~"5.."}[5m]))
+/ sum by (namespace, ingress) (rate(nginx_ingress_controller_requests[5m]))`, Unit: "percentunit"},
+				{Title: "p99 latency", Expr: `histogram_quantile(0.99, sum by (le, namespace, ingress) (rate(nginx_ingress_controller_request_duration_seconds_bucket[5m])))`, Unit: "s"},
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// Name of the rule, the pod monitor and the dashboard of a profile
+func GetProfileResourceName(profile string) string {
+	return profileResourcePrefix + profile
+}
+
+func GetProfileRule(cr *v1.Observability, profile string) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      GetProfileResourceName(profile),
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// With shards, every shard scrapes its own copy of the pod monitor
+func GetProfilePodMonitor(cr *v1.Observability, profile string, shard int32) *prometheusv1.PodMonitor {
+	return &prometheusv1.PodMonitor{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      GetShardedPodMonitorName(GetProfileResourceName(profile), shard),
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetProfileDashboard(cr *v1.Observability, profile string) *v1alpha12.GrafanaDashboard {
+	return &v1alpha12.GrafanaDashboard{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      GetProfileResourceName(profile),
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Renders the panels of the profile as a dashboard with one row of panels per query
+func GetProfileDashboardJson(profile *Profile) (string, error) {
+	var panels []interface{}
+	for i, panel := range profile.Panels {
+		panels = append(panels, map[string]interface{}{
+			"id":         i + 1,
+			"type":       "timeseries",
+			"title":      panel.Title,
+			"datasource": "Prometheus",
+			"gridPos":    map[string]int{"x": 0, "y": i * 8, "w": 24, "h": 8},
+			"fieldConfig": map[string]interface{}{
+				"defaults": map[string]string{"unit": panel.Unit},
+			},
+			"targets": []interface{}{
+				map[string]string{"refId": "A", "expr": panel.Expr},
+			},
+		})
+	}
+
+	dashboard, err := json.Marshal(map[string]interface{}{
+		"uid":           GetProfileResourceName(profile.Name),
+		"title":         profile.Dashboard,
+		"tags":          []string{"profile", profile.Name},
+		"version":       1,
+		"schemaVersion": 27,
+		"refresh":       "1m",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"description":   fmt.Sprintf("Generated from version %v of the %v profile", profile.Version, profile.Name),
+		"panels":        panels,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(dashboard), nil
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+func TestGetProfile(t *testing.T) {
+	for _, name := range []string{v1.ProfileKafka, v1.ProfilePostgres, v1.ProfileRedis, v1.ProfileNginxIngress} {
+		t.Run(name, func(t *testing.T) {
+			profile := GetProfile(name)
+			if profile == nil {
+				t.Fatalf("GetProfile() = nil, want the %v profile", name)
+			}
+			if profile.Name != name || profile.Version == "" || profile.Port == "" || len(profile.Rules) == 0 {
+				t.Errorf("GetProfile() = %+v, want a name, version, port and rules", profile)
+			}
+
+			source, err := GetProfileDashboardJson(profile)
+			if err != nil {
+				t.Fatalf("GetProfileDashboardJson() error = %v", err)
+			}
+			dashboard := struct {
+				Uid    string        `json:"uid"`
+				Panels []interface{} `json:"panels"`
+			}{}
+			if err = json.Unmarshal([]byte(source), &dashboard); err != nil {
+				t.Fatalf("GetProfileDashboardJson() returned invalid json: %v", err)
+			}
+			if dashboard.Uid != GetProfileResourceName(name) || len(dashboard.Panels) != len(profile.Panels) {
+				t.Errorf("GetProfileDashboardJson() uid = %v with %v panels", dashboard.Uid, len(dashboard.Panels))
+			}
+		})
+	}
+
+	if GetProfile("mysql") != nil {
+		t.Errorf("GetProfile() of an unknown profile is not nil")
+	}
+}
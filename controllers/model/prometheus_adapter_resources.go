@@ -0,0 +1,132 @@
+package model
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v12 "k8s.io/api/core/v1"
+	v14 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	PrometheusAdapterName      = "prometheus-adapter"
+	PrometheusAdapterPort      = 6443
+	PrometheusAdapterConfigKey = "config.yaml"
+	// Pod annotation to restart the adapter when the rules change, the adapter doesn't reload them
+	PrometheusAdapterRulesAnnotation = "observability-operator/prometheus-adapter-rules"
+	// The adapter serves this version of the custom metrics API
+	CustomMetricsAPIService = "v1beta1.custom.metrics.k8s.io"
+)
+
+var APIServiceGroupVersionKind = schema.GroupVersionKind{
+	Group:   "apiregistration.k8s.io",
+	Version: "v1",
+	Kind:    "APIService",
+}
+
+func getPrometheusAdapterLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+		"app":        PrometheusAdapterName,
+	}
+}
+
+func GetPrometheusAdapterSelectorLabels() map[string]string {
+	return map[string]string{
+		"app": PrometheusAdapterName,
+	}
+}
+
+func GetPrometheusAdapterServiceAccount(cr *v1.Observability) *v12.ServiceAccount {
+	return &v12.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PrometheusAdapterName,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Cluster scoped, named after the namespace like the other cluster roles of the stack
+func GetPrometheusAdapterClusterRole(cr *v1.Observability) *v14.ClusterRole {
+	return &v14.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-%s", PrometheusAdapterName, cr.Namespace),
+		},
+	}
+}
+
+func GetPrometheusAdapterClusterRoleBinding(cr *v1.Observability) *v14.ClusterRoleBinding {
+	return &v14.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-%s", PrometheusAdapterName, cr.Namespace),
+		},
+	}
+}
+
+// Delegates the authentication and authorization of API requests to the API server
+func GetPrometheusAdapterDelegatorBinding(cr *v1.Observability) *v14.ClusterRoleBinding {
+	return &v14.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-delegator-%s", PrometheusAdapterName, cr.Namespace),
+		},
+	}
+}
+
+// Reads the client CA of the API server from kube-system
+func GetPrometheusAdapterAuthReaderBinding(cr *v1.Observability) *v14.RoleBinding {
+	return &v14.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-auth-reader-%s", PrometheusAdapterName, cr.Namespace),
+			Namespace: "kube-system",
+		},
+	}
+}
+
+// Allows the horizontal pod autoscaler controller to read the custom metrics
+func GetCustomMetricsReaderClusterRole(cr *v1.Observability) *v14.ClusterRole {
+	return &v14.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("custom-metrics-reader-%s", cr.Namespace),
+		},
+	}
+}
+
+func GetCustomMetricsReaderClusterRoleBinding(cr *v1.Observability) *v14.ClusterRoleBinding {
+	return &v14.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("custom-metrics-reader-%s", cr.Namespace),
+		},
+	}
+}
+
+func GetPrometheusAdapterDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PrometheusAdapterName,
+			Namespace: cr.Namespace,
+			Labels:    getPrometheusAdapterLabels(),
+		},
+	}
+}
+
+func GetPrometheusAdapterService(cr *v1.Observability) *v12.Service {
+	return &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PrometheusAdapterName,
+			Namespace: cr.Namespace,
+			Labels:    getPrometheusAdapterLabels(),
+		},
+	}
+}
+
+// The apiregistration types are not part of the scheme of the operator
+func GetCustomMetricsAPIService() *unstructured.Unstructured {
+	apiService := &unstructured.Unstructured{}
+	apiService.SetGroupVersionKind(APIServiceGroupVersionKind)
+	apiService.SetName(CustomMetricsAPIService)
+	return apiService
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+const PrometheusOperatorCSVPrefix = "prometheusoperator."
+
+// PrometheusOperatorRelease is a release of the Prometheus operator in the catalog index together
+// with the Kubernetes versions it supports and the newest Prometheus and Alertmanager it can run
+type PrometheusOperatorRelease struct {
+	Version              string
+	MinKubernetesVersion string
+	MaxKubernetesVersion string
+	PrometheusVersion    string
+	AlertmanagerVersion  string
+}
+
+// Supported upgrade path, oldest first. Upgrades can only move to the next release
+var PrometheusOperatorReleases = []PrometheusOperatorRelease{
+	{Version: "0.37.0", MinKubernetesVersion: "1.14", MaxKubernetesVersion: "1.19", PrometheusVersion: "v2.16.0", AlertmanagerVersion: "v0.20.0"},
+	{Version: "0.42.1", MinKubernetesVersion: "1.16", MaxKubernetesVersion: "1.20", PrometheusVersion: "v2.20.0", AlertmanagerVersion: "v0.21.0"},
+	{Version: "0.45.0", MinKubernetesVersion: "1.16", MaxKubernetesVersion: "1.20", PrometheusVersion: "v2.22.2", AlertmanagerVersion: "v0.21.0"},
+	{Version: "0.47.0", MinKubernetesVersion: "1.16", MaxKubernetesVersion: "1.21", PrometheusVersion: "v2.26.0", AlertmanagerVersion: "v0.21.0"},
+}
+
+func getPrometheusOperatorReleaseIndex(csv string) int {
+	version := strings.TrimPrefix(csv, PrometheusOperatorCSVPrefix)
+	for i, release := range PrometheusOperatorReleases {
+		if release.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns the release of a Prometheus operator CSV, nil if it is not in the version matrix
+func GetPrometheusOperatorRelease(csv string) *PrometheusOperatorRelease {
+	i := getPrometheusOperatorReleaseIndex(csv)
+	if i < 0 {
+		return nil
+	}
+	return &PrometheusOperatorReleases[i]
+}
+
+// Returns the release of the Prometheus operator that was installed through OLM, if any
+func GetInstalledPrometheusOperatorRelease(cr *v1.Observability) *PrometheusOperatorRelease {
+	status := cr.Status.GetSubscriptionStatus(GetPrometheusSubscription(cr).Name)
+	if status == nil || status.InstalledCSV == "" {
+		return nil
+	}
+	return GetPrometheusOperatorRelease(status.InstalledCSV)
+}
+
+// Compares the major and minor versions only, patch releases do not change compatibility
+func compareMinorVersions(a string, b string) (int, error) {
+	va, err := semver.ParseTolerant(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := semver.ParseTolerant(b)
+	if err != nil {
+		return 0, err
+	}
+	va.Patch, vb.Patch = 0, 0
+	va.Pre, vb.Pre = nil, nil
+	va.Build, vb.Build = nil, nil
+	return va.Compare(vb), nil
+}
+
+// Checks that the Prometheus operator can be upgraded from the installed to the target CSV. The
+// target has to be the same or the next release in the matrix and support the Kubernetes version
+func CheckPrometheusOperatorUpgrade(cr *v1.Observability, installedCSV string, targetCSV string) error {
+	target := getPrometheusOperatorReleaseIndex(targetCSV)
+	if target < 0 {
+		return fmt.Errorf("%v is not in the supported version matrix", targetCSV)
+	}
+
+	if installedCSV != "" {
+		installed := getPrometheusOperatorReleaseIndex(installedCSV)
+		if installed < 0 {
+			return fmt.Errorf("no supported upgrade path from %v", installedCSV)
+		}
+		if target < installed {
+			return fmt.Errorf("downgrade from %v to %v is not supported", installedCSV, targetCSV)
+		}
+		if target > installed+1 {
+			return fmt.Errorf("upgrade from %v to %v skips %v", installedCSV, targetCSV,
+				PrometheusOperatorCSVPrefix+PrometheusOperatorReleases[installed+1].Version)
+		}
+	}
+
+	if cr.Status.Capabilities == nil || cr.Status.Capabilities.KubernetesVersion == "" {
+		return nil
+	}
+
+	release := PrometheusOperatorReleases[target]
+	kubernetesVersion := cr.Status.Capabilities.KubernetesVersion
+	min, err := compareMinorVersions(kubernetesVersion, release.MinKubernetesVersion)
+	if err != nil {
+		return err
+	}
+	max, err := compareMinorVersions(kubernetesVersion, release.MaxKubernetesVersion)
+	if err != nil {
+		return err
+	}
+	if min < 0 || max > 0 {
+		return fmt.Errorf("%v supports Kubernetes %v to %v, the cluster runs %v", targetCSV,
+			release.MinKubernetesVersion, release.MaxKubernetesVersion, kubernetesVersion)
+	}
+	return nil
+}
+
+// Limit a requested Prometheus or Alertmanager version to the newest version the installed
+// operator supports. The CRs are upgraded once the operator upgrade completed
+func limitToOperatorVersion(requested string, supported string) string {
+	if requested == "" || supported == "" {
+		return requested
+	}
+	result, err := compareMinorVersions(requested, supported)
+	if err != nil || result <= 0 {
+		return requested
+	}
+	return supported
+}
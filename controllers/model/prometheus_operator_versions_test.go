@@ -0,0 +1,61 @@
+package model
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+
+	"testing"
+)
+
+func TestCheckPrometheusOperatorUpgrade(t *testing.T) {
+	type args struct {
+		cr           *v1.Observability
+		installedCSV string
+		targetCSV    string
+	}
+	kubernetes119 := &v1.Observability{
+		Status: v1.ObservabilityStatus{
+			Capabilities: &v1.ClusterCapabilities{KubernetesVersion: "1.19.0"},
+		},
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "upgrade to the next release is allowed",
+			args:    args{cr: kubernetes119, installedCSV: "prometheusoperator.0.42.1", targetCSV: "prometheusoperator.0.45.0"},
+			wantErr: false,
+		},
+		{
+			name:    "upgrade skipping a release is blocked",
+			args:    args{cr: kubernetes119, installedCSV: "prometheusoperator.0.37.0", targetCSV: "prometheusoperator.0.45.0"},
+			wantErr: true,
+		},
+		{
+			name:    "release not in the matrix is blocked",
+			args:    args{cr: kubernetes119, installedCSV: "prometheusoperator.0.45.0", targetCSV: "prometheusoperator.0.46.0"},
+			wantErr: true,
+		},
+		{
+			name: "release not supporting the kubernetes version is blocked",
+			args: args{
+				cr: &v1.Observability{
+					Status: v1.ObservabilityStatus{
+						Capabilities: &v1.ClusterCapabilities{KubernetesVersion: "1.20.4"},
+					},
+				},
+				installedCSV: "",
+				targetCSV:    "prometheusoperator.0.37.0",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckPrometheusOperatorUpgrade(tt.args.cr, tt.args.installedCSV, tt.args.targetCSV); (err != nil) != tt.wantErr {
+				t.Errorf("CheckPrometheusOperatorUpgrade() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	v13 "k8s.io/api/core/v1"
 	v14 "k8s.io/api/rbac/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -63,6 +64,16 @@ func GetPrometheusSubscription(cr *v1.Observability) *v1alpha1.Subscription {
 	}
 }
 
+// Created by OLM from the CSV of the Prometheus operator
+func GetPrometheusOperatorDeployment(cr *v1.Observability) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "prometheus-operator",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
 func GetPrometheusCatalogSource(cr *v1.Observability) *v1alpha1.CatalogSource {
 	return &v1alpha1.CatalogSource{
 		ObjectMeta: v12.ObjectMeta{
@@ -92,15 +103,16 @@ func GetPrometheusTLSSecret(cr *v1.Observability) *v13.Secret {
 
 func GetPrometheusServiceAccount(cr *v1.Observability) *v13.ServiceAccount {
 	route := GetPrometheusRoute(cr)
-	redirect := fmt.Sprintf("{\"kind\":\"OAuthRedirectReference\",\"apiVersion\":\"v1\",\"reference\":{\"kind\":\"Route\",\"name\":\"%s\"}}", route.Name)
+	annotations := getOAuthRedirectAnnotations(cr, route.Name, GetPrometheusHost(cr))
+	for key, value := range GetObjectStorageIdentityAnnotations(cr) {
+		annotations[key] = value
+	}
 
 	return &v13.ServiceAccount{
 		ObjectMeta: v12.ObjectMeta{
-			Name:      GetDefaultNamePrometheus(cr),
-			Namespace: cr.Namespace,
-			Annotations: map[string]string{
-				"serviceaccounts.openshift.io/oauth-redirectreference.primary": redirect,
-			},
+			Name:        GetDefaultNamePrometheus(cr),
+			Namespace:   cr.Namespace,
+			Annotations: annotations,
 		},
 	}
 }
@@ -183,6 +195,19 @@ func GetFederationConfig(user, pass string, patterns []string) ([]byte, error) {
 	return buffer.Bytes(), err
 }
 
+// Prometheus scrapes its own remote storage metrics to monitor the health of remote write
+func GetPrometheusSelfScrapeConfig() []byte {
+	return []byte(`
+- job_name: prometheus-self
+  static_configs:
+    - targets: [ 'localhost:9090' ]
+  metric_relabel_configs:
+    - action: keep
+      source_labels: [ '__name__' ]
+      regex: prometheus_remote_storage_.*
+`)
+}
+
 func GetPrometheusAdditionalScrapeConfig(cr *v1.Observability) *v13.Secret {
 	return &v13.Secret{
 		ObjectMeta: v12.ObjectMeta{
@@ -296,6 +321,15 @@ func GetDeadmansSwitch(cr *v1.Observability) *prometheusv1.PrometheusRule {
 	}
 }
 
+func GetRemoteWriteHealthRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-remote-write-health",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
 // Label Selectors
 
 func GetPrometheusPodMonitorLabelSelectors(cr *v1.Observability, indexes []v1.RepositoryIndex) *v12.LabelSelector {
@@ -456,13 +490,20 @@ func getPrometheusRepositoryIndexConfig(indexes []v1.RepositoryIndex) *v1.Promet
 }
 
 func GetPrometheusVersion(cr *v1.Observability) string {
+	version := PrometheusVersion
 	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.PrometheusVersion != "" {
-		return cr.Spec.SelfContained.PrometheusVersion
+		version = cr.Spec.SelfContained.PrometheusVersion
+	}
+	if release := GetInstalledPrometheusOperatorRelease(cr); release != nil {
+		return limitToOperatorVersion(version, release.PrometheusVersion)
 	}
-	return PrometheusVersion
+	return version
 }
 
 func GetPrometheusResourceRequirement(cr *v1.Observability) v13.ResourceRequirements {
+	if resources, ok := cr.GetResources(v1.ResourcesPrometheus); ok {
+		return resources
+	}
 	if cr.Spec.SelfContained != nil {
 		return cr.Spec.SelfContained.PrometheusResourceRequirement
 	}
@@ -470,6 +511,9 @@ func GetPrometheusResourceRequirement(cr *v1.Observability) v13.ResourceRequirem
 }
 
 func GetPrometheusOperatorResourceRequirement(cr *v1.Observability) v13.ResourceRequirements {
+	if resources, ok := cr.GetResources(v1.ResourcesPrometheusOperator); ok {
+		return resources
+	}
 	if cr.Spec.SelfContained != nil {
 		return cr.Spec.SelfContained.PrometheusOperatorResourceRequirement
 	}
@@ -483,9 +527,78 @@ func GetPrometheusStorageSize(cr *v1.Observability, indexes []v1.RepositoryIndex
 		cr.Spec.Storage.PrometheusStorageSpec.VolumeClaimTemplate.Spec.Resources.Requests.Storage() != nil {
 		customPrometheusStorageSize = cr.Spec.Storage.PrometheusStorageSpec.VolumeClaimTemplate.Spec.Resources.Requests.Storage().String()
 	}
+	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.PrometheusStorageRequest != "" {
+		customPrometheusStorageSize = cr.Spec.SelfContained.PrometheusStorageRequest
+	}
 	prometheusConfig := getPrometheusRepositoryIndexConfig(indexes)
 	if prometheusConfig != nil && prometheusConfig.OverridePrometheusPvcSize != "" {
 		customPrometheusStorageSize = prometheusConfig.OverridePrometheusPvcSize
 	}
 	return customPrometheusStorageSize
 }
+
+func GetPrometheusStorageClass(cr *v1.Observability) *string {
+	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.PrometheusStorageClass != nil {
+		return cr.Spec.SelfContained.PrometheusStorageClass
+	}
+	if cr.Spec.Storage != nil && cr.Spec.Storage.PrometheusStorageSpec != nil {
+		return cr.Spec.Storage.PrometheusStorageSpec.VolumeClaimTemplate.Spec.StorageClassName
+	}
+	return nil
+}
+
+func GetPrometheusRetentionSize(cr *v1.Observability) string {
+	if cr.Spec.SelfContained != nil {
+		return cr.Spec.SelfContained.PrometheusRetentionSize
+	}
+	return ""
+}
+
+// Prometheus gets a persistent volume if either a storage spec or a storage request is present in the CR
+func HasPrometheusStorage(cr *v1.Observability) bool {
+	if cr.Spec.Storage != nil && cr.Spec.Storage.PrometheusStorageSpec != nil {
+		return true
+	}
+	return cr.Spec.SelfContained != nil && cr.Spec.SelfContained.PrometheusStorageRequest != ""
+}
+
+func GetPrometheusScrapeInterval(cr *v1.Observability) string {
+	if cr.Spec.SelfContained != nil && v1.IsValidPrometheusDuration(cr.Spec.SelfContained.PrometheusScrapeInterval) {
+		return cr.Spec.SelfContained.PrometheusScrapeInterval
+	}
+	return ""
+}
+
+func GetPrometheusEvaluationInterval(cr *v1.Observability) string {
+	if cr.Spec.SelfContained != nil && v1.IsValidPrometheusDuration(cr.Spec.SelfContained.PrometheusEvaluationInterval) {
+		return cr.Spec.SelfContained.PrometheusEvaluationInterval
+	}
+	return ""
+}
+
+func GetPrometheusQuerySpec(cr *v1.Observability) *prometheusv1.QuerySpec {
+	if cr.Spec.SelfContained == nil {
+		return nil
+	}
+
+	query := &prometheusv1.QuerySpec{}
+	if cr.Spec.SelfContained.PrometheusQueryMaxSamples != nil && *cr.Spec.SelfContained.PrometheusQueryMaxSamples > 0 {
+		query.MaxSamples = cr.Spec.SelfContained.PrometheusQueryMaxSamples
+	}
+	if v1.IsValidPrometheusDuration(cr.Spec.SelfContained.PrometheusQueryTimeout) {
+		timeout := cr.Spec.SelfContained.PrometheusQueryTimeout
+		query.Timeout = &timeout
+	}
+
+	if query.MaxSamples == nil && query.Timeout == nil {
+		return nil
+	}
+	return query
+}
+
+func GetPrometheusWALCompression(cr *v1.Observability) *bool {
+	if cr.Spec.SelfContained != nil {
+		return cr.Spec.SelfContained.PrometheusWALCompression
+	}
+	return nil
+}
@@ -95,6 +95,32 @@ func TestGetPrometheusStorageSize(t *testing.T) {
 			},
 			want: "250Gi",
 		},
+		{
+			name: "selfcontained storage request takes precedence over the storage spec",
+			args: args{
+				cr: &v1.Observability{
+					Spec: v1.ObservabilitySpec{
+						Storage: &v1.Storage{
+							PrometheusStorageSpec: &monitoringv1.StorageSpec{
+								VolumeClaimTemplate: monitoringv1.EmbeddedPersistentVolumeClaim{
+									Spec: corev1.PersistentVolumeClaimSpec{
+										Resources: corev1.ResourceRequirements{
+											Requests: map[corev1.ResourceName]resource.Quantity{
+												corev1.ResourceStorage: resource.MustParse("10Gi"),
+											},
+										},
+									},
+								},
+							},
+						},
+						SelfContained: &v1.SelfContained{
+							PrometheusStorageRequest: "20Gi",
+						},
+					},
+				},
+			},
+			want: "20Gi",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
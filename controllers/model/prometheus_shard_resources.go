@@ -0,0 +1,135 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Label of the Prometheus shards and of the pod monitor copies scraped by a shard
+	PrometheusShardLabel = "observability-shard"
+	ThanosQueryName      = "prometheus-thanos-query"
+	ThanosQueryPort      = 9090
+)
+
+func GetPrometheusShards(cr *v1.Observability) int32 {
+	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.PrometheusShards != nil && *cr.Spec.SelfContained.PrometheusShards > 1 {
+		return *cr.Spec.SelfContained.PrometheusShards
+	}
+	return 1
+}
+
+// The first shard is the Prometheus of an unsharded setup
+func GetPrometheusShard(cr *v1.Observability, shard int32) *prometheusv1.Prometheus {
+	prometheus := GetPrometheus(cr)
+	if shard > 0 {
+		prometheus.Name = fmt.Sprintf("%s-shard-%d", prometheus.Name, shard)
+	}
+	return prometheus
+}
+
+// Name of the copy of a pod monitor that is scraped by a shard
+func GetShardedPodMonitorName(name string, shard int32) string {
+	if shard > 0 {
+		return fmt.Sprintf("%s-shard-%d", name, shard)
+	}
+	return name
+}
+
+// Restricts a pod monitor selector to the copies of a shard. The first shard also selects the pod
+// monitors that are not sharded by the operator.
+func GetPrometheusShardPodMonitorSelector(selector *v12.LabelSelector, shard int32, shards int32) *v12.LabelSelector {
+	if shards == 1 {
+		return selector
+	}
+
+	result := selector.DeepCopy()
+	if shard == 0 {
+		var others []string
+		for i := int32(1); i < shards; i++ {
+			others = append(others, strconv.Itoa(int(i)))
+		}
+		result.MatchExpressions = append(result.MatchExpressions, v12.LabelSelectorRequirement{
+			Key:      PrometheusShardLabel,
+			Operator: v12.LabelSelectorOpNotIn,
+			Values:   others,
+		})
+	} else {
+		result.MatchExpressions = append(result.MatchExpressions, v12.LabelSelectorRequirement{
+			Key:      PrometheusShardLabel,
+			Operator: v12.LabelSelectorOpIn,
+			Values:   []string{strconv.Itoa(int(shard))},
+		})
+	}
+	return result
+}
+
+// Keeps the targets of which the address hashes to the shard
+func GetPrometheusShardRelabelings(shard int32, shards int32) []*prometheusv1.RelabelConfig {
+	return []*prometheusv1.RelabelConfig{
+		{
+			SourceLabels: []string{"__address__"},
+			Modulus:      uint64(shards),
+			TargetLabel:  "__tmp_hash",
+			Action:       "hashmod",
+		},
+		{
+			SourceLabels: []string{"__tmp_hash"},
+			Regex:        strconv.Itoa(int(shard)),
+			Action:       "keep",
+		},
+	}
+}
+
+// Prometheus runs Thanos sidecars for sharding and for uploads to the backup bucket, both are
+// queried through Thanos Query
+func IsThanosEnabled(cr *v1.Observability) bool {
+	return GetPrometheusShards(cr) > 1 || cr.BackupEnabled()
+}
+
+// Url for PromQL queries. Sharded and backed up setups are queried through Thanos Query
+func GetPrometheusQueryUrl(cr *v1.Observability) string {
+	if IsThanosEnabled(cr) {
+		return fmt.Sprintf("http://%s.%s:%d", ThanosQueryName, cr.Namespace, ThanosQueryPort)
+	}
+	return fmt.Sprintf("http://prometheus-operated.%s:9090", cr.Namespace)
+}
+
+func getThanosQueryLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+		"app":        ThanosQueryName,
+	}
+}
+
+func GetThanosQuerySelectorLabels() map[string]string {
+	return map[string]string{
+		"app": ThanosQueryName,
+	}
+}
+
+func GetThanosQueryDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      ThanosQueryName,
+			Namespace: cr.Namespace,
+			Labels:    getThanosQueryLabels(),
+		},
+	}
+}
+
+func GetThanosQueryService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      ThanosQueryName,
+			Namespace: cr.Namespace,
+			Labels:    getThanosQueryLabels(),
+		},
+	}
+}
@@ -3,16 +3,48 @@ package model
 import (
 	"bytes"
 	"fmt"
+	"path"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	t "text/template"
+	"time"
 
+	"github.com/ghodss/yaml"
 	errors2 "github.com/pkg/errors"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	v13 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/core/v1"
 	v14 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var logMetricGroupRegex = regexp.MustCompile(`\(\?P<([a-zA-Z0-9_]+)>`)
+
+// Promtail presents its internal TLS client certificate from here
+const PromtailInternalTLSDir = "/opt/tls"
+
+// The secrets of the additional clients are mounted in subdirectories named after the client
+const PromtailClientsDir = "/opt/clients"
+
+// Promtail keeps its positions files and write ahead log here when buffering is enabled
+const PromtailBufferDir = "/var/lib/promtail"
+
+const (
+	PromtailBufferDefaultHostPath  = "/var/lib/observability-promtail"
+	PromtailBufferDefaultStorage   = "1Gi"
+	PromtailBufferDefaultRetention = "1h"
+	// Default max_period of the backoff of the Promtail clients
+	promtailDefaultMaxBackoff = 5 * time.Minute
+)
+
+// Secrets of an additional Promtail client
+const (
+	PromtailClientToken = "token"
+	PromtailClientCA    = "ca"
+	PromtailClientCert  = "cert"
 )
 
 func GetPromtailConfigmap(cr *v1.Observability, name string) *v12.ConfigMap {
@@ -64,21 +96,75 @@ func GetPromtailClusterRoleBinding(cr *v1.Observability) *v14.ClusterRoleBinding
 	}
 }
 
-func GetPromtailConfig(cr *v1.Observability, c *v1.ObservatoriumIndex, indexId string, namespaces []string) (string, error) {
+func GetPromtailConfig(cr *v1.Observability, c *v1.ObservatoriumIndex, indexId string, namespaces []string, tenants map[string]string) (string, error) {
 	const config = `
 server:
   http_listen_port: 9080
-  http_listen_address: 0.0.0.0
+  http_listen_address: "{{ .ListenHost }}"
+{{- if .Buffering }}
+positions:
+  filename: {{ .BufferDir }}/positions.yaml
+wal:
+  enabled: true
+  dir: {{ .BufferDir }}/wal
+  max_segment_age: {{ .Buffering.Retention }}
+{{- end }}
 clients:
   - url: {{ .Url }}
 	{{- if .RequireToken }}
     bearer_token_file: /opt/secrets/token
 	{{- end }}
+	{{- if .Timeout }}
+    timeout: {{ .Timeout }}
+	{{- end }}
+	{{- if or .Backoff .MaxRetries }}
+    backoff_config:
+	{{- if .Backoff }}
+	{{- if .Backoff.MinBackoff }}
+      min_period: {{ .Backoff.MinBackoff }}
+	{{- end }}
+	{{- if .Backoff.MaxBackoff }}
+      max_period: {{ .Backoff.MaxBackoff }}
+	{{- end }}
+	{{- end }}
+	{{- if .MaxRetries }}
+      max_retries: {{ .MaxRetries }}
+	{{- end }}
+	{{- end }}
     external_labels:
       cluster_id: "{{ .ClusterID }}"
       observability_id: "{{ .ObservabililtyId }}"
     tls_config:
       insecure_skip_verify: true
+	{{- if .InternalTLS }}
+      cert_file: {{ .InternalTLSDir }}/tls.crt
+      key_file: {{ .InternalTLSDir }}/tls.key
+	{{- end }}
+{{- range .Clients }}
+  - url: "{{ .URL }}"
+    {{- if .Tenant }}
+    tenant_id: "{{ .Tenant }}"
+    {{- end }}
+    {{- if .TokenFile }}
+    bearer_token_file: {{ .TokenFile }}
+    {{- end }}
+    {{- if $.ClientMaxRetries }}
+    backoff_config:
+      max_retries: {{ $.ClientMaxRetries }}
+    {{- end }}
+    external_labels:
+      cluster_id: "{{ $.ClusterID }}"
+      observability_id: "{{ $.ObservabililtyId }}"
+    tls_config:
+      insecure_skip_verify: {{ .InsecureSkipVerify }}
+    {{- if .CAFile }}
+      ca_file: {{ .CAFile }}
+    {{- end }}
+    {{- if .CertDir }}
+      cert_file: {{ .CertDir }}/tls.crt
+      key_file: {{ .CertDir }}/tls.key
+    {{- end }}
+{{- end }}
 scrape_configs:
   - job_name: "strimzi"
     relabel_configs:
@@ -110,15 +196,58 @@ scrape_configs:
       - __meta_kubernetes_pod_uid
       - __meta_kubernetes_pod_container_name
       target_label: __path__
+    {{- if or .TenantStages .MetricStages }}
+    pipeline_stages:
+    {{- if .TenantStages }}
+{{ .TenantStages }}
+    {{- end }}
+    {{- if .MetricStages }}
+{{ .MetricStages }}
+    {{- end }}
+    {{- end }}
     kubernetes_sd_configs:
       - role: "pod"
         namespaces:
           names: [{{ .Namespaces }}]
+        {{- if .PodSelector }}
+        selectors:
+          - role: "pod"
+            label: "{{ .PodSelector }}"
+        {{- end }}
+{{- if .Journal }}
+  - job_name: "journal"
+    journal:
+      path: /var/log/journal
+      max_age: 12h
+      labels:
+        job: systemd-journal
+    relabel_configs:
+    - source_labels:
+      - __journal__systemd_unit
+      target_label: unit
+    - source_labels:
+      - __journal__hostname
+      target_label: nodename
+{{- end }}
+{{- if .HostPaths }}
+  - job_name: "host-files"
+    static_configs:
+    {{- range .HostPaths }}
+      - targets:
+          - localhost
+        labels:
+          job: host-files
+          __path__: {{ . }}
+    {{- end }}
+{{- end }}
 `
 	template := t.Must(t.New("template").Parse(config))
 	var requireToken = false
 	var buffer bytes.Buffer
 	var url string
+	// Timeout and backoff of the pushes through the token refresher
+	var timeout string
+	var backoff *v1.TokenRefresherBackoff
 
 	if c != nil {
 		if !c.IsValid() {
@@ -134,29 +263,472 @@ scrape_configs:
 			}
 			tokenRefresherName := GetTokenRefresherName(c.Id, LogsTokenRefresher)
 			url = fmt.Sprintf("http://%v.%v.svc.cluster.local", tokenRefresherName, cr.Namespace)
+			timeout = GetTokenRefresherRequestTimeout(cr)
+			if b := GetTokenRefresherBackoff(cr); b.MinBackoff != "" || b.MaxBackoff != "" {
+				backoff = b
+			}
 		}
 	}
 
 	// Namespaces must be ordered to avoid different config hashes
 	sort.Strings(namespaces)
 
-	err := template.Execute(&buffer, struct {
+	metricStages, err := GetPromtailMetricStages(cr)
+	if err != nil {
+		return "", err
+	}
+
+	tenantStages, err := GetPromtailTenantStages(tenants)
+	if err != nil {
+		return "", err
+	}
+
+	podSelector := ""
+	journal := false
+	var hostPaths []string
+	if logs := GetPromtailLogs(cr); logs != nil {
+		if logs.PodSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(logs.PodSelector)
+			if err != nil {
+				return "", err
+			}
+			podSelector = selector.String()
+		}
+		journal = logs.Journal
+		hostPaths = logs.HostPaths
+	}
+
+	// With buffering the clients retry for the whole retention instead of dropping the batch after
+	// the default 10 retries
+	var buffering *v1.LogBuffering
+	var maxRetries, clientMaxRetries int
+	if buffering = GetPromtailBuffering(cr); buffering != nil {
+		maxPeriod := promtailDefaultMaxBackoff
+		if backoff != nil && backoff.MaxBackoff != "" {
+			maxPeriod, err = time.ParseDuration(backoff.MaxBackoff)
+			if err != nil {
+				return "", err
+			}
+		}
+		maxRetries, err = getPromtailMaxRetries(buffering.Retention, maxPeriod)
+		if err != nil {
+			return "", err
+		}
+		clientMaxRetries, err = getPromtailMaxRetries(buffering.Retention, promtailDefaultMaxBackoff)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	type client struct {
+		URL                string
+		Tenant             string
+		TokenFile          string
+		CAFile             string
+		CertDir            string
+		InsecureSkipVerify bool
+	}
+	var clients []client
+	for _, c := range GetPromtailClients(cr) {
+		rendered := client{
+			URL:                c.URL,
+			Tenant:             c.Tenant,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		}
+		if c.TokenSecret != "" {
+			rendered.TokenFile = path.Join(GetPromtailClientDir(c.Name, PromtailClientToken), "token")
+		}
+		if c.CASecret != "" {
+			rendered.CAFile = path.Join(GetPromtailClientDir(c.Name, PromtailClientCA), "ca.crt")
+		}
+		if c.CertSecret != "" {
+			rendered.CertDir = GetPromtailClientDir(c.Name, PromtailClientCert)
+		}
+		clients = append(clients, rendered)
+	}
+
+	err = template.Execute(&buffer, struct {
 		ClusterID        string
 		ObservabililtyId string
 		Namespaces       string
 		Url              string
 		RequireToken     bool
+		Timeout          string
+		Backoff          *v1.TokenRefresherBackoff
+		MaxRetries       int
+		ClientMaxRetries int
+		Buffering        *v1.LogBuffering
+		BufferDir        string
+		PodSelector      string
+		Journal          bool
+		HostPaths        []string
+		MetricStages     string
+		TenantStages     string
+		InternalTLS      bool
+		InternalTLSDir   string
+		ListenHost       string
+		Clients          []client
 	}{
 		ClusterID:        cr.Status.ClusterID,
 		ObservabililtyId: indexId,
 		Namespaces:       strings.Join(namespaces, ","),
 		Url:              url,
 		RequireToken:     requireToken,
+		Timeout:          timeout,
+		Backoff:          backoff,
+		MaxRetries:       maxRetries,
+		ClientMaxRetries: clientMaxRetries,
+		Buffering:        buffering,
+		BufferDir:        PromtailBufferDir,
+		PodSelector:      podSelector,
+		Journal:          journal,
+		HostPaths:        hostPaths,
+		MetricStages:     metricStages,
+		TenantStages:     tenantStages,
+		InternalTLS:      IsInternalTLSEnabled(cr),
+		InternalTLSDir:   PromtailInternalTLSDir,
+		ListenHost:       getListenHost(cr),
+		Clients:          clients,
 	})
 
 	return string(buffer.Bytes()), err
 }
 
+func GetPromtailLogs(cr *v1.Observability) *v1.PromtailLogs {
+	if cr.Spec.SelfContained != nil {
+		return cr.Spec.SelfContained.Logs
+	}
+	return nil
+}
+
+// Buffering of the CR with the defaults filled in, nil if buffering is disabled
+func GetPromtailBuffering(cr *v1.Observability) *v1.LogBuffering {
+	if cr.Spec.Logs == nil || cr.Spec.Logs.Buffering == nil {
+		return nil
+	}
+
+	buffering := cr.Spec.Logs.Buffering.DeepCopy()
+	if buffering.Storage == "" {
+		buffering.Storage = v1.LogBufferingHostPath
+	}
+	if buffering.Storage == v1.LogBufferingHostPath && buffering.HostPath == "" {
+		buffering.HostPath = PromtailBufferDefaultHostPath
+	}
+	if buffering.Storage == v1.LogBufferingPersistentVolumeClaim && buffering.Size == "" {
+		buffering.Size = PromtailBufferDefaultStorage
+	}
+	if buffering.Retention == "" {
+		buffering.Retention = PromtailBufferDefaultRetention
+	}
+	return buffering
+}
+
+// Retries of a client backing off up to the max period until the retention passed
+func getPromtailMaxRetries(retention string, maxPeriod time.Duration) (int, error) {
+	duration, err := time.ParseDuration(retention)
+	if err != nil {
+		return 0, err
+	}
+	retries := int(duration / maxPeriod)
+	if duration%maxPeriod != 0 {
+		retries++
+	}
+	return retries, nil
+}
+
+// Claim shared by the Promtail pods of all nodes with the PersistentVolumeClaim buffering
+func GetPromtailBufferClaim(cr *v1.Observability) *v12.PersistentVolumeClaim {
+	return &v12.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "promtail-buffer",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+// Loki push endpoints of Promtail in addition to Observatorium
+func GetPromtailClients(cr *v1.Observability) []v1.PromtailClient {
+	if cr.Spec.Logs != nil {
+		return cr.Spec.Logs.Clients
+	}
+	return nil
+}
+
+// Mount path of a secret of an additional client
+func GetPromtailClientDir(name string, secret string) string {
+	return path.Join(PromtailClientsDir, name, secret)
+}
+
+// Directories of the log files on the nodes, mounted into Promtail. The pod logs are always mounted
+func GetPromtailHostPathDirs(cr *v1.Observability) []string {
+	logs := GetPromtailLogs(cr)
+	if logs == nil {
+		return nil
+	}
+
+	var result []string
+	seen := map[string]bool{"/var/log/pods": true, "/var/log/journal": logs.Journal}
+	for _, hostPath := range logs.HostPaths {
+		dir := path.Dir(hostPath)
+		if !seen[dir] {
+			seen[dir] = true
+			result = append(result, dir)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func GetLogTenants(cr *v1.Observability) *v1.Logs {
+	if cr.Spec.Logs != nil && (len(cr.Spec.Logs.Tenants) > 0 || cr.Spec.Logs.TenantNamespaceLabel != "") {
+		return cr.Spec.Logs
+	}
+	return nil
+}
+
+// Tenants of the namespaces, keyed by namespace. The first tenant matching a namespace applies,
+// namespaces no tenant matches fall back to the value of the tenant namespace label
+func GetPromtailNamespaceTenants(cr *v1.Observability, namespaces []v12.Namespace) (map[string]string, error) {
+	logs := GetLogTenants(cr)
+	if logs == nil {
+		return nil, nil
+	}
+
+	matches := func(tenant v1.LogTenant, namespace v12.Namespace) (bool, error) {
+		for _, name := range tenant.Namespaces {
+			if name == namespace.Name {
+				return true, nil
+			}
+		}
+		if tenant.NamespaceSelector == nil {
+			return false, nil
+		}
+		selector, err := metav1.LabelSelectorAsSelector(tenant.NamespaceSelector)
+		if err != nil {
+			return false, err
+		}
+		return selector.Matches(labels.Set(namespace.Labels)), nil
+	}
+
+	result := map[string]string{}
+	for _, namespace := range namespaces {
+		for _, tenant := range logs.Tenants {
+			ok, err := matches(tenant, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				result[namespace.Name] = tenant.Tenant
+				break
+			}
+		}
+		if _, ok := result[namespace.Name]; !ok && logs.TenantNamespaceLabel != "" {
+			if tenant := namespace.Labels[logs.TenantNamespaceLabel]; tenant != "" {
+				result[namespace.Name] = tenant
+			}
+		}
+	}
+	return result, nil
+}
+
+// Promtail sends all logs to every client, the tenant of a log stream is set by a tenant stage
+// matching the namespaces of the tenant. Returns the stages indented for the scrape config.
+func GetPromtailTenantStages(tenants map[string]string) (string, error) {
+	namespaces := map[string][]string{}
+	for namespace, tenant := range tenants {
+		namespaces[tenant] = append(namespaces[tenant], namespace)
+	}
+
+	// Tenants and namespaces must be ordered to avoid different config hashes
+	var names []string
+	for tenant := range namespaces {
+		names = append(names, tenant)
+	}
+	sort.Strings(names)
+
+	var stages []map[string]interface{}
+	for _, tenant := range names {
+		sort.Strings(namespaces[tenant])
+		stages = append(stages, map[string]interface{}{
+			"match": map[string]interface{}{
+				"selector": fmt.Sprintf(`{namespace=~"%s"}`, strings.Join(namespaces[tenant], "|")),
+				"stages": []map[string]interface{}{
+					{
+						"tenant": map[string]string{
+							"value": tenant,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	if len(stages) == 0 {
+		return "", nil
+	}
+
+	rendered, err := yaml.Marshal(stages)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(rendered), "\n"), "\n") {
+		lines = append(lines, "      "+line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func GetLogMetrics(cr *v1.Observability) []v1.LogMetric {
+	if cr.Spec.Logs != nil {
+		return cr.Spec.Logs.Metrics
+	}
+	return nil
+}
+
+// Named groups of the regex are prefixed with the metric name, so values extracted for one
+// metric are never picked up by another
+func getLogMetricRegex(prefix string, regex string) string {
+	return fmt.Sprintf("(?P<%s>%s)", prefix, logMetricGroupRegex.ReplaceAllString(regex, fmt.Sprintf("(?P<%s_$1>", prefix)))
+}
+
+// Promtail pipeline stages recording the log metrics. Each metric extracts its values, adds its
+// labels to the stream, records the metric and drops the labels again so they don't reach Loki.
+// Returns the stages indented for the scrape config.
+func GetPromtailMetricStages(cr *v1.Observability) (string, error) {
+	var stages []map[string]interface{}
+	for _, metric := range GetLogMetrics(cr) {
+		prefix := fmt.Sprintf("log_metric_%s", metric.Name)
+		source := prefix
+
+		if metric.Regex != "" {
+			stages = append(stages, map[string]interface{}{
+				"regex": map[string]string{
+					"expression": getLogMetricRegex(prefix, metric.Regex),
+				},
+			})
+			if metric.Type == v1.LogMetricHistogram {
+				source = fmt.Sprintf("%s_value", prefix)
+			}
+		} else {
+			expressions := map[string]string{
+				prefix: metric.JSONField,
+			}
+			for _, label := range metric.Labels {
+				expressions[fmt.Sprintf("%s_%s", prefix, label)] = label
+			}
+			// Container log lines start with the CRI timestamp, stream and flags
+			line := fmt.Sprintf("%s_line", prefix)
+			stages = append(stages, map[string]interface{}{
+				"regex": map[string]string{
+					"expression": fmt.Sprintf(`^\S+ (?:stdout|stderr) [FP] (?P<%s>.*)$`, line),
+				},
+			}, map[string]interface{}{
+				"json": map[string]interface{}{
+					"expressions": expressions,
+					"source":      line,
+				},
+			})
+		}
+
+		if len(metric.Labels) > 0 {
+			labels := map[string]string{}
+			for _, label := range metric.Labels {
+				labels[label] = fmt.Sprintf("%s_%s", prefix, label)
+			}
+			stages = append(stages, map[string]interface{}{
+				"labels": labels,
+			})
+		}
+
+		config := map[string]interface{}{
+			"action": "inc",
+		}
+		metricType := "Counter"
+		if metric.Type == v1.LogMetricHistogram {
+			var buckets []float64
+			for _, bucket := range metric.Buckets {
+				value, err := strconv.ParseFloat(bucket, 64)
+				if err != nil {
+					return "", err
+				}
+				buckets = append(buckets, value)
+			}
+			config = map[string]interface{}{
+				"buckets": buckets,
+			}
+			metricType = "Histogram"
+		}
+		stages = append(stages, map[string]interface{}{
+			"metrics": map[string]interface{}{
+				metric.Name: map[string]interface{}{
+					"type":        metricType,
+					"description": metric.Description,
+					"source":      source,
+					"config":      config,
+				},
+			},
+		})
+
+		if len(metric.Labels) > 0 {
+			stages = append(stages, map[string]interface{}{
+				"labeldrop": metric.Labels,
+			})
+		}
+	}
+
+	if len(stages) == 0 {
+		return "", nil
+	}
+
+	rendered, err := yaml.Marshal(stages)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(rendered), "\n"), "\n") {
+		lines = append(lines, "      "+line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Prometheus scrapes the log metrics from the Promtail pods. The stream labels of the metrics are kept
+func GetPromtailScrapeConfig(cr *v1.Observability) []byte {
+	if len(GetLogMetrics(cr)) == 0 {
+		return nil
+	}
+
+	return []byte(fmt.Sprintf(`
+- job_name: promtail-log-metrics
+  honor_labels: true
+  kubernetes_sd_configs:
+    - role: pod
+      namespaces:
+        names:
+          - %s
+  relabel_configs:
+    - action: keep
+      source_labels: [ '__meta_kubernetes_pod_container_name' ]
+      regex: promtail
+    - source_labels: [ '__meta_kubernetes_pod_ip' ]
+      target_label: __address__
+      replacement: $1:9080
+  metric_relabel_configs:
+    - action: keep
+      source_labels: [ '__name__' ]
+      regex: promtail_custom_.*
+`, cr.Namespace))
+}
+
+func GetPromtailResourceRequirement(cr *v1.Observability) v12.ResourceRequirements {
+	resources, _ := cr.GetResources(v1.ResourcesPromtail)
+	return resources
+}
+
 func GetPromtailDaemonSetLabels(index *v1.RepositoryIndex) *metav1.LabelSelector {
 	if index.Config != nil && index.Config.Promtail != nil && index.Config.Promtail.DaemonSetLabelSelector != nil {
 		return index.Config.Promtail.DaemonSetLabelSelector
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetLogMetricRegex(t *testing.T) {
+	type args struct {
+		prefix string
+		regex  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "regex without named groups is wrapped in a group named after the metric",
+			args: args{
+				prefix: "log_metric_errors",
+				regex:  "level=error",
+			},
+			want: "(?P<log_metric_errors>level=error)",
+		},
+		{
+			name: "named groups are prefixed with the metric name",
+			args: args{
+				prefix: "log_metric_latency",
+				regex:  `status=(?P<status>\d+) took=(?P<value>[0-9.]+)`,
+			},
+			want: `(?P<log_metric_latency>status=(?P<log_metric_latency_status>\d+) took=(?P<log_metric_latency_value>[0-9.]+))`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getLogMetricRegex(tt.args.prefix, tt.args.regex); got != tt.want {
+				t.Errorf("getLogMetricRegex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPromtailTenantStages(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenants map[string]string
+		want    string
+	}{
+		{
+			name:    "no stages without tenants",
+			tenants: nil,
+			want:    "",
+		},
+		{
+			name: "namespaces of a tenant are matched by one stage",
+			tenants: map[string]string{
+				"team-b-prod": "team-b",
+				"team-a":      "team-a",
+				"team-b-dev":  "team-b",
+			},
+			want: `      - match:
+          selector: '{namespace=~"team-a"}'
+          stages:
+          - tenant:
+              value: team-a
+      - match:
+          selector: '{namespace=~"team-b-dev|team-b-prod"}'
+          stages:
+          - tenant:
+              value: team-b`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetPromtailTenantStages(tt.tenants)
+			if err != nil {
+				t.Errorf("GetPromtailTenantStages() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetPromtailTenantStages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPromtailMaxRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention string
+		maxPeriod time.Duration
+		want      int
+	}{
+		{
+			name:      "retries cover the retention at the max period",
+			retention: "1h",
+			maxPeriod: 5 * time.Minute,
+			want:      12,
+		},
+		{
+			name:      "a partial period is rounded up",
+			retention: "90s",
+			maxPeriod: time.Minute,
+			want:      2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getPromtailMaxRetries(tt.retention, tt.maxPeriod)
+			if err != nil {
+				t.Errorf("getPromtailMaxRetries() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("getPromtailMaxRetries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
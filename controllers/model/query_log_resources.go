@@ -0,0 +1,240 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	QueryLogDirectory = "/var/log/prometheus"
+	QueryLogFile      = QueryLogDirectory + "/query.log"
+	QueryLogContainer = "query-log"
+	// Port of the Promtail sidecar that serves the metrics of the query log
+	QueryLogMetricsPort = 9081
+	// The query log is opened for appending, the sidecar truncates it once it grew beyond this size
+	queryLogMaxBytes          = 100 * 1024 * 1024
+	defaultSlowQueryThreshold = 10 * time.Second
+)
+
+func GetQueryLogConfigMap(cr *v1.Observability) *v13.ConfigMap {
+	return &v13.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "prometheus-query-log",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetQueryLogDashboard(cr *v1.Observability) *v1alpha1.GrafanaDashboard {
+	return &v1alpha1.GrafanaDashboard{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-prometheus-queries",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetSlowQueryThreshold(cr *v1.Observability) time.Duration {
+	if cr.QueryLoggingEnabled() && cr.Spec.SelfContained.QueryLogging.SlowQueryThreshold != "" {
+		threshold, err := time.ParseDuration(cr.Spec.SelfContained.QueryLogging.SlowQueryThreshold)
+		if err == nil && threshold > 0 {
+			return threshold
+		}
+	}
+	return defaultSlowQueryThreshold
+}
+
+// Promtail runs in dry run mode, it sends nothing and prints the slow queries to its log. The
+// evaluation time of all queries is recorded in a histogram, slow queries are counted by query.
+// Counters of queries that stopped being slow expire after a day
+func GetQueryLogConfig(cr *v1.Observability) ([]byte, string) {
+	config := fmt.Sprintf(`server:
+  http_listen_port: %d
+  grpc_listen_port: 0
+positions:
+  filename: /tmp/positions.yaml
+clients:
+  - url: http://localhost/loki/api/v1/push
+scrape_configs:
+  - job_name: query-log
+    static_configs:
+      - targets: [ localhost ]
+        labels:
+          __path__: %s
+    pipeline_stages:
+      - json:
+          expressions:
+            query: params.query
+            duration: stats.timings.evalTotalTime
+      - metrics:
+          prometheus_query_duration_seconds:
+            type: Histogram
+            description: Evaluation time of the logged queries
+            source: duration
+            config:
+              buckets: [ 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120 ]
+      - template:
+          source: slow
+          template: '{{ if ge (float64 .duration) %g }}true{{ end }}'
+      - labels:
+          slow:
+      - match:
+          selector: '{slow!="true"}'
+          action: drop
+      - labels:
+          query:
+      - metrics:
+          prometheus_slow_queries_total:
+            type: Counter
+            description: Number of slow queries, by query
+            max_idle_duration: 24h
+            config:
+              match_all: true
+              action: inc
+          prometheus_slow_query_seconds_total:
+            type: Counter
+            description: Evaluation time of the slow queries, by query
+            source: duration
+            max_idle_duration: 24h
+            config:
+              action: add
+`, QueryLogMetricsPort, QueryLogFile, GetSlowQueryThreshold(cr).Seconds())
+
+	hash := sha256.Sum256([]byte(config))
+	return []byte(config), fmt.Sprintf("%x", hash)
+}
+
+// Command of the sidecar. Prometheus keeps the query log open until it is restarted, so it is
+// truncated instead of rotated. Queries logged between the last read and the truncation are lost
+func GetQueryLogCommand() []string {
+	return []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf(`while true; do
+  sleep 60
+  if [ "$(stat -c %%s %[1]s 2>/dev/null || echo 0)" -gt %[2]d ]; then : > %[1]s; fi
+done &
+exec /usr/bin/promtail -dry-run -config.file=/etc/query-log/promtail.yaml`, QueryLogFile, queryLogMaxBytes),
+	}
+}
+
+// Prometheus scrapes the metrics of the sidecars of all shards
+func GetQueryLogScrapeConfig(cr *v1.Observability) []byte {
+	if !cr.QueryLoggingEnabled() {
+		return nil
+	}
+	return []byte(fmt.Sprintf(`
+- job_name: %s
+  kubernetes_sd_configs:
+    - role: pod
+      namespaces:
+        names:
+          - %s
+  relabel_configs:
+    - action: keep
+      source_labels: [ '__meta_kubernetes_pod_container_name' ]
+      regex: %s
+    - source_labels: [ '__meta_kubernetes_pod_ip' ]
+      target_label: __address__
+      replacement: $1:%d
+    - source_labels: [ '__meta_kubernetes_pod_name' ]
+      target_label: pod
+`, QueryLogContainer, cr.Namespace, QueryLogContainer, QueryLogMetricsPort))
+}
+
+// Dashboard of the query load of Prometheus, the tables list the queries that took the most
+// evaluation time in the selected range
+func GetQueryLogDashboardJson(cr *v1.Observability) (string, error) {
+	datasource := GetGrafanaDatasourceName(cr)
+	target := func(expr string, legend string, format string) map[string]interface{} {
+		return map[string]interface{}{
+			"expr":         expr,
+			"legendFormat": legend,
+			"format":       format,
+			"instant":      format == "table",
+			"refId":        "A",
+		}
+	}
+	graph := func(id int, title string, y int, unit string, targets ...map[string]interface{}) map[string]interface{} {
+		for i := range targets {
+			targets[i]["refId"] = string(rune('A' + i))
+		}
+		return map[string]interface{}{
+			"id":         id,
+			"type":       "graph",
+			"title":      title,
+			"datasource": datasource,
+			"gridPos":    map[string]int{"x": 12 * ((id - 1) % 2), "y": y, "w": 12, "h": 8},
+			"targets":    targets,
+			"yaxes": []map[string]interface{}{
+				{"format": unit, "show": true},
+				{"format": "short", "show": false},
+			},
+			"lines":     true,
+			"linewidth": 1,
+		}
+	}
+	table := func(id int, title string, y int, expr string, unit string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":         id,
+			"type":       "table",
+			"title":      title,
+			"datasource": datasource,
+			"gridPos":    map[string]int{"x": 0, "y": y, "w": 24, "h": 10},
+			"targets":    []map[string]interface{}{target(expr, "", "table")},
+			"fieldConfig": map[string]interface{}{
+				"defaults": map[string]interface{}{"unit": unit},
+			},
+			"transformations": []map[string]interface{}{
+				{
+					"id": "organize",
+					"options": map[string]interface{}{
+						"excludeByName": map[string]bool{"Time": true},
+						"renameByName":  map[string]string{"query": "Query", "Value": title},
+					},
+				},
+			},
+			"options": map[string]interface{}{
+				"sortBy": []map[string]interface{}{{"displayName": title, "desc": true}},
+			},
+		}
+	}
+
+	count := fmt.Sprintf(`promtail_custom_prometheus_query_duration_seconds_count{job="%s"}`, QueryLogContainer)
+	histogram := fmt.Sprintf(`promtail_custom_prometheus_query_duration_seconds_bucket{job="%s"}`, QueryLogContainer)
+	slowSeconds := fmt.Sprintf(`promtail_custom_prometheus_slow_query_seconds_total{job="%s"}`, QueryLogContainer)
+	slowQueries := fmt.Sprintf(`promtail_custom_prometheus_slow_queries_total{job="%s"}`, QueryLogContainer)
+
+	dashboard := map[string]interface{}{
+		"uid":           "prometheus-queries",
+		"title":         "Prometheus Queries",
+		"tags":          []string{"observability-operator"},
+		"schemaVersion": 27,
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"refresh":       "1m",
+		"panels": []map[string]interface{}{
+			graph(1, "Queries", 0, "reqps",
+				target(fmt.Sprintf(`sum(rate(%s[5m]))`, count), "all", "time_series"),
+				target(fmt.Sprintf(`sum(rate(%s[5m]))`, slowQueries), fmt.Sprintf("slower than %v", GetSlowQueryThreshold(cr)), "time_series")),
+			graph(2, "Evaluation time", 0, "s",
+				target(fmt.Sprintf(`histogram_quantile(0.5, sum by (le) (rate(%s[5m])))`, histogram), "p50", "time_series"),
+				target(fmt.Sprintf(`histogram_quantile(0.9, sum by (le) (rate(%s[5m])))`, histogram), "p90", "time_series"),
+				target(fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(%s[5m])))`, histogram), "p99", "time_series")),
+			table(3, "Total evaluation time", 8, fmt.Sprintf(`topk(20, sum by (query) (increase(%s[$__range])))`, slowSeconds), "s"),
+			table(4, "Slow queries", 18, fmt.Sprintf(`topk(20, sum by (query) (increase(%s[$__range])))`, slowQueries), "short"),
+		},
+	}
+
+	bytes, err := json.Marshal(dashboard)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
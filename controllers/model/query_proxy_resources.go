@@ -0,0 +1,109 @@
+package model
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v12 "k8s.io/api/core/v1"
+	v14 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	QueryProxyName     = "prometheus-tenant-proxy"
+	QueryProxyPort     = 9092
+	QueryProxyTLS      = "prometheus-tenant-proxy-tls"
+	defaultTenantLabel = "namespace"
+)
+
+func getQueryProxyLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+		"app":        QueryProxyName,
+	}
+}
+
+func GetQueryProxySelectorLabels() map[string]string {
+	return map[string]string{
+		"app": QueryProxyName,
+	}
+}
+
+func GetQueryProxyServiceAccount(cr *v1.Observability) *v12.ServiceAccount {
+	return &v12.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      QueryProxyName,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Cluster scoped, named after the namespace in case multiple stacks run on the cluster
+func GetQueryProxyClusterRole(cr *v1.Observability) *v14.ClusterRole {
+	return &v14.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-%s", QueryProxyName, cr.Namespace),
+		},
+	}
+}
+
+func GetQueryProxyClusterRoleBinding(cr *v1.Observability) *v14.ClusterRoleBinding {
+	return &v14.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-%s", QueryProxyName, cr.Namespace),
+		},
+	}
+}
+
+func GetQueryProxyConfigMap(cr *v1.Observability) *v12.ConfigMap {
+	return &v12.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      QueryProxyName,
+			Namespace: cr.Namespace,
+			Labels:    getQueryProxyLabels(),
+		},
+	}
+}
+
+func GetQueryProxyDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      QueryProxyName,
+			Namespace: cr.Namespace,
+			Labels:    getQueryProxyLabels(),
+		},
+	}
+}
+
+func GetQueryProxyService(cr *v1.Observability) *v12.Service {
+	return &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      QueryProxyName,
+			Namespace: cr.Namespace,
+			Labels:    getQueryProxyLabels(),
+		},
+	}
+}
+
+func GetQueryProxyTenantLabel(cr *v1.Observability) string {
+	if cr.Spec.QueryProxy != nil && cr.Spec.QueryProxy.TenantLabel != "" {
+		return cr.Spec.QueryProxy.TenantLabel
+	}
+	return defaultTenantLabel
+}
+
+// kube-rbac-proxy checks that the caller can get pods.metrics.k8s.io in the namespace passed in
+// the tenant query parameter. prom-label-proxy then enforces the same value on the tenant label
+func GetQueryProxyConfig(cr *v1.Observability) string {
+	return fmt.Sprintf(`authorization:
+  rewrites:
+    byQueryParameter:
+      name: %s
+  resourceAttributes:
+    apiGroup: metrics.k8s.io
+    apiVersion: v1beta1
+    resource: pods
+    namespace: "{{ .Value }}"
+`, GetQueryProxyTenantLabel(cr))
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+const (
+	// Prefix of the reports in the bucket, followed by the name of the report
+	ReportPrefix        = "reports/"
+	ReportDefaultFormat = "pdf"
+	// Width of the rendered dashboards in pixels, the height fits the whole dashboard
+	ReportWidth = 1600
+)
+
+func GetReportFormat(report v1.Report) string {
+	if report.Format == "" {
+		return ReportDefaultFormat
+	}
+	return report.Format
+}
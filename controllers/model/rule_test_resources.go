@@ -0,0 +1,43 @@
+package model
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Label of the jobs and config maps of the rule unit tests
+	RuleTestLabel = "observability-operator/rule-test"
+	// The rule files and the test file are mounted here, promtool resolves the rule files relative
+	// to the test file
+	RuleTestDir  = "/etc/rule-test"
+	RuleTestFile = "test.yaml"
+)
+
+// Jobs and config maps of the rule tests are named after the hash of the tested files, so a job
+// runs once for every revision of the files
+func GetRuleTestName(hash string) string {
+	return fmt.Sprintf("rule-test-%s", hash[:10])
+}
+
+func GetRuleTestConfigMap(cr *v1.Observability, hash string) *v12.ConfigMap {
+	return &v12.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetRuleTestName(hash),
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetRuleTestJob(cr *v1.Observability, hash string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetRuleTestName(hash),
+			Namespace: cr.Namespace,
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Prefix of the scrape jobs of the components, followed by the name of the component
+	SelfMonitoringJobPrefix = "self-monitoring-"
+	// Annotation on the rule with the component versions it was rendered for
+	SelfMonitoringVersionsAnnotation = "observability-operator/component-versions"
+	// Promtail counts dropped entries since 2.3, older versions only count failed pushes
+	promtailDroppedEntriesVersion = "2.3.0"
+)
+
+// Component of the stack scraped by its own Prometheus, by container name and metrics port
+type SelfMonitoringComponent struct {
+	Name string
+	// Regex of the container names
+	Container string
+	Port      int
+	// Scraped through a service monitor of the component instead of the scrape config
+	ServiceMonitor bool
+}
+
+func GetSelfMonitoringRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-self-monitoring",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Components of the stack that run in the namespace of the CR
+func GetSelfMonitoringComponents(cr *v1.Observability) []SelfMonitoringComponent {
+	if !cr.SelfMonitoringAlertsEnabled() {
+		return nil
+	}
+
+	components := []SelfMonitoringComponent{{Name: "prometheus", Container: "prometheus", Port: 9090}}
+	if cr.AlertmanagerMode() == v1.ComponentManaged {
+		components = append(components, SelfMonitoringComponent{Name: "alertmanager", Container: "alertmanager", Port: 9093})
+	}
+	if cr.GrafanaMode() == v1.ComponentManaged {
+		components = append(components, SelfMonitoringComponent{Name: "grafana", Container: "grafana", Port: 3000})
+	}
+	if cr.PromtailMode() == v1.ComponentManaged {
+		components = append(components, SelfMonitoringComponent{Name: "promtail", Container: "promtail", Port: 9080})
+	}
+	// Token refreshers are named after their index and serve their metrics on the internal port
+	if cr.TokenRefresherMode() == v1.ComponentManaged && !cr.ObservatoriumDisabled() {
+		components = append(components, SelfMonitoringComponent{Name: "token-refresher", Container: "token-refresher-.+", Port: TokenRefresherMetricsPort, ServiceMonitor: true})
+	}
+	return components
+}
+
+func GetSelfMonitoringJob(component string) string {
+	return SelfMonitoringJobPrefix + component
+}
+
+// Prometheus scrapes the pods of the components, the containers are matched by name
+func GetSelfMonitoringScrapeConfig(cr *v1.Observability) []byte {
+	var result []string
+	for _, component := range GetSelfMonitoringComponents(cr) {
+		if component.ServiceMonitor {
+			continue
+		}
+		result = append(result, fmt.Sprintf(`
+- job_name: %s
+  kubernetes_sd_configs:
+    - role: pod
+      namespaces:
+        names:
+          - %s
+  relabel_configs:
+    - action: keep
+      source_labels: [ '__meta_kubernetes_pod_container_name' ]
+      regex: %s
+    - source_labels: [ '__meta_kubernetes_pod_ip' ]
+      target_label: __address__
+      replacement: $1:%d
+    - source_labels: [ '__meta_kubernetes_pod_name' ]
+      target_label: pod
+`, GetSelfMonitoringJob(component.Name), cr.Namespace, component.Container, component.Port))
+	}
+	return []byte(strings.Join(result, ""))
+}
+
+// Returns the tag of the Promtail image, latest for images referenced by digest
+func GetPromtailVersion(cr *v1.Observability) string {
+	image := GetImage(cr, v1.ImagePromtail, PromtailImage)
+	if strings.Contains(image, "@") {
+		return "latest"
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
+
+// Expression of the dropped Promtail entries per second, depending on the version of Promtail.
+// Tags that are not versions, e.g. latest, are expected to be recent
+func GetPromtailDroppedEntriesExpr(version string) string {
+	job := GetSelfMonitoringJob("promtail")
+	if parsed, err := semver.ParseTolerant(version); err == nil && parsed.LT(semver.MustParse(promtailDroppedEntriesVersion)) {
+		return fmt.Sprintf(`sum by (pod) (rate(promtail_request_duration_seconds_count{job="%s",status_code=~"5..|429"}[5m]))`, job)
+	}
+	return fmt.Sprintf(`sum by (pod) (rate(promtail_dropped_entries_total{job="%s"}[5m]))`, job)
+}
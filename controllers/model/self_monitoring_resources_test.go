@@ -0,0 +1,37 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetPromtailDroppedEntriesExpr(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{
+			name:    "dropped entries counter since 2.3",
+			version: "v2.3.0",
+			want:    "promtail_dropped_entries_total",
+		},
+		{
+			name:    "failed pushes before 2.3",
+			version: "2.2.1",
+			want:    "promtail_request_duration_seconds_count",
+		},
+		{
+			name:    "tags that are not versions are expected to be recent",
+			version: "latest",
+			want:    "promtail_dropped_entries_total",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetPromtailDroppedEntriesExpr(tt.version); !strings.Contains(got, tt.want) {
+				t.Errorf("GetPromtailDroppedEntriesExpr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Synthetic metric recorded by the generated rule, its samples keep the remote write queues busy
+	// so that a stalled queue is noticed on idle stacks as well
+	StackVerificationRecord = "observability_stack_verification_timestamp_seconds"
+	// Test alert sent to Alertmanager and routed to the receiver without notifications
+	StackVerificationAlert    = "ObservabilityStackVerification"
+	StackVerificationReceiver = "stack-verification"
+	defaultStackVerification  = 15 * time.Minute
+)
+
+func GetStackVerificationRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-stack-verification",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetStackVerificationInterval(cr *v1.Observability) time.Duration {
+	if cr.Spec.StackVerification != nil && cr.Spec.StackVerification.Interval != "" {
+		interval, err := time.ParseDuration(cr.Spec.StackVerification.Interval)
+		if err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return defaultStackVerification
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"fmt"
+
+	v1alpha12 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	TempoImage            = "docker.io/grafana/tempo:1.0.1"
+	TempoDefaultStorage   = "10Gi"
+	TempoDefaultRetention = "48h"
+	TempoHttpPort         = 3200
+	TempoDatasourceName   = "Tempo"
+)
+
+// The TempoStack API is not vendored, the CR is managed as an unstructured object
+var TempoStackGroupVersionKind = schema.GroupVersionKind{
+	Group:   "tempo.grafana.com",
+	Version: "v1alpha1",
+	Kind:    "TempoStack",
+}
+
+func getTempoLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+		"app":        "tempo",
+	}
+}
+
+func GetTempoServiceAccount(cr *v1.Observability) *v12.ServiceAccount {
+	return &v12.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tempo",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetTempoConfigMap(cr *v1.Observability) *v12.ConfigMap {
+	return &v12.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tempo-config",
+			Namespace: cr.Namespace,
+			Labels:    getTempoLabels(),
+		},
+	}
+}
+
+func GetTempoStatefulSet(cr *v1.Observability) *v13.StatefulSet {
+	return &v13.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tempo",
+			Namespace: cr.Namespace,
+			Labels:    getTempoLabels(),
+		},
+	}
+}
+
+func GetTempoService(cr *v1.Observability) *v12.Service {
+	return &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tempo",
+			Namespace: cr.Namespace,
+			Labels:    getTempoLabels(),
+		},
+	}
+}
+
+func GetTempoStack(cr *v1.Observability) *unstructured.Unstructured {
+	stack := &unstructured.Unstructured{}
+	stack.SetGroupVersionKind(TempoStackGroupVersionKind)
+	stack.SetName("observability")
+	stack.SetNamespace(cr.Namespace)
+	return stack
+}
+
+func GetTempoDatasource(cr *v1.Observability) *v1alpha12.GrafanaDataSource {
+	return &v1alpha12.GrafanaDataSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "on-cluster-tempo",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetTempoSelectorLabels() map[string]string {
+	return map[string]string{
+		"app": "tempo",
+	}
+}
+
+// Returns the url of the Tempo query API. The Tempo operator exposes it on the query frontend
+func GetTempoQueryUrl(cr *v1.Observability, tempoOperator bool) string {
+	if tempoOperator {
+		return fmt.Sprintf("http://tempo-%s-query-frontend.%s.svc:%d", GetTempoStack(cr).GetName(), cr.Namespace, TempoHttpPort)
+	}
+	return fmt.Sprintf("http://%s.%s.svc:%d", GetTempoService(cr).Name, cr.Namespace, TempoHttpPort)
+}
+
+func GetTempoStorageSize(cr *v1.Observability) string {
+	if cr.TracingEnabled() && cr.Spec.Tracing.Storage.Size != "" {
+		return cr.Spec.Tracing.Storage.Size
+	}
+	return TempoDefaultStorage
+}
+
+func GetTempoRetention(cr *v1.Observability) string {
+	if cr.TracingEnabled() && cr.Spec.Tracing.Storage.Retention != "" {
+		return cr.Spec.Tracing.Storage.Retention
+	}
+	return TempoDefaultRetention
+}
+
+func GetTempoResourceRequirement(cr *v1.Observability) v12.ResourceRequirements {
+	resources, _ := cr.GetResources(v1.ResourcesTempo)
+	return resources
+}
+
+// Monolithic Tempo storing traces on the local volume. Receives OTLP and Jaeger traces
+func GetTempoConfig(cr *v1.Observability) string {
+	return fmt.Sprintf(`server:
+  http_listen_port: %d
+distributor:
+  receivers:
+    otlp:
+      protocols:
+        grpc:
+        http:
+    jaeger:
+      protocols:
+        grpc:
+        thrift_http:
+compactor:
+  compaction:
+    block_retention: %s
+storage:
+  trace:
+    backend: local
+    wal:
+      path: /var/tempo/wal
+    local:
+      path: /var/tempo/blocks
+`, TempoHttpPort, GetTempoRetention(cr))
+}
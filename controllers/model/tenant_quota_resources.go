@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Usage of the namespaces with a quota, recorded by the generated rules
+	TenantSeriesRecord      = "namespace:scrape_series:sum"
+	TenantSamplesRecord     = "namespace:scrape_samples:rate5m"
+	TenantMaxSeriesRecord   = "namespace:quota_max_series"
+	TenantMaxSamplesRecord  = "namespace:quota_max_samples:rate5m"
+	AlertTenantSeriesQuota  = "TenantSeriesQuotaExceeded"
+	AlertTenantSamplesQuota = "TenantSamplesQuotaExceeded"
+	// A quota counts as exceeded if the usage stayed above it for the whole window
+	TenantQuotaExceededWindow = "10m"
+	TenantSuspensionDuration  = time.Hour
+	NamespaceNameLabel        = "kubernetes.io/metadata.name"
+)
+
+func GetTenantQuotaRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-tenant-quotas",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Namespaces that exceeded an enforced quota and are not scraped
+func GetSuspendedTenants(s *v1.ObservabilityStatus) []string {
+	var result []string
+	for _, quota := range s.TenantQuotas {
+		if quota.SuspendedSince != 0 {
+			result = append(result, quota.Namespace)
+		}
+	}
+	return result
+}
+
+// Excludes the suspended namespaces from a monitor namespace selector. Without a selector only the
+// namespace of the CR is selected, which can't be suspended
+func GetTenantNamespaceSelector(selector *v12.LabelSelector, suspended []string) *v12.LabelSelector {
+	if selector == nil || len(suspended) == 0 {
+		return selector
+	}
+
+	result := selector.DeepCopy()
+	result.MatchExpressions = append(result.MatchExpressions, v12.LabelSelectorRequirement{
+		Key:      NamespaceNameLabel,
+		Operator: v12.LabelSelectorOpNotIn,
+		Values:   suspended,
+	})
+	return result
+}
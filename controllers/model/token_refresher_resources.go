@@ -2,8 +2,11 @@ package model
 
 import (
 	"fmt"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	v13 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	v12 "k8s.io/api/core/v1"
 	v14 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,6 +19,13 @@ const (
 	LogsTokenRefresher    TokenRefresherType = "logs"
 )
 
+const (
+	// Token refreshers serve their metrics on the internal port
+	TokenRefresherMetricsPort = 8081
+	// CPU utilization the autoscalers of the token refreshers aim for by default
+	TokenRefresherDefaultTargetCPU = 80
+)
+
 type TokenRefresherConfigSet struct {
 	ObservatoriumUrl string
 	AuthUrl          string
@@ -57,3 +67,80 @@ func GetTokenRefresherNetworkPolicy(cr *v1.Observability, name string) *v14.Netw
 		},
 	}
 }
+
+func GetTokenRefresherResourceRequirement(cr *v1.Observability) v12.ResourceRequirements {
+	resources, _ := cr.GetResources(v1.ResourcesTokenRefresher)
+	return resources
+}
+
+func GetTokenRefresherAutoscaler(cr *v1.Observability, name string) *autoscalingv1.HorizontalPodAutoscaler {
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetTokenRefresherServiceMonitor(cr *v1.Observability, name string) *prometheusv1.ServiceMonitor {
+	return &prometheusv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetTokenRefresherAutoscaling(cr *v1.Observability) *v1.TokenRefresherAutoscaling {
+	if cr.Spec.TokenRefresher != nil {
+		return cr.Spec.TokenRefresher.Autoscaling
+	}
+	return nil
+}
+
+// Replicas of the token refresher deployments, nil if they are scaled by an autoscaler
+func GetTokenRefresherReplicas(cr *v1.Observability) *int32 {
+	if GetTokenRefresherAutoscaling(cr) != nil {
+		return nil
+	}
+	replicas := int32(1)
+	if cr.Spec.TokenRefresher != nil && cr.Spec.TokenRefresher.Replicas != nil {
+		replicas = *cr.Spec.TokenRefresher.Replicas
+	}
+	return &replicas
+}
+
+func GetTokenRefresherRequestTimeout(cr *v1.Observability) string {
+	if cr.Spec.TokenRefresher != nil {
+		return cr.Spec.TokenRefresher.RequestTimeout
+	}
+	return ""
+}
+
+func GetTokenRefresherBackoff(cr *v1.Observability) *v1.TokenRefresherBackoff {
+	if cr.Spec.TokenRefresher != nil && cr.Spec.TokenRefresher.Backoff != nil {
+		return cr.Spec.TokenRefresher.Backoff
+	}
+	return &v1.TokenRefresherBackoff{}
+}
+
+// Queue config of the remote writes through a token refresher. The backoff of the CR takes
+// precedence over the one of the repository
+func GetTokenRefresherQueueConfig(cr *v1.Observability, queueConfig *prometheusv1.QueueConfig) *prometheusv1.QueueConfig {
+	backoff := GetTokenRefresherBackoff(cr)
+	if backoff.MinBackoff == "" && backoff.MaxBackoff == "" {
+		return queueConfig
+	}
+
+	result := &prometheusv1.QueueConfig{}
+	if queueConfig != nil {
+		*result = *queueConfig
+	}
+	if backoff.MinBackoff != "" {
+		result.MinBackoff = backoff.MinBackoff
+	}
+	if backoff.MaxBackoff != "" {
+		result.MaxBackoff = backoff.MaxBackoff
+	}
+	return result
+}
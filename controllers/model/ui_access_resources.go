@@ -0,0 +1,203 @@
+package model
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	v14 "k8s.io/api/networking/v1"
+	v13 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const UIAccessName = "observability-ui-access"
+
+const (
+	// Keys of the OIDC client secret of the UIs
+	UIAccessOIDCClientId     = "clientId"
+	UIAccessOIDCClientSecret = "clientSecret"
+	// Key of the cookie secret of the OIDC proxies in the proxy secrets of the UIs. The session
+	// secrets of the OpenShift proxies are longer than oauth2-proxy accepts
+	OIDCProxyCookieSecret = "cookie_secret"
+)
+
+func getUIAccess(cr *v1.Observability) *v1.UIAccess {
+	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.UIAccess != nil {
+		return cr.Spec.SelfContained.UIAccess
+	}
+	return &v1.UIAccess{}
+}
+
+func GetPrometheusHost(cr *v1.Observability) string {
+	return getUIAccess(cr).PrometheusHost
+}
+
+func GetAlertmanagerHost(cr *v1.Observability) string {
+	return getUIAccess(cr).AlertmanagerHost
+}
+
+func GetGrafanaHost(cr *v1.Observability) string {
+	return getUIAccess(cr).GrafanaHost
+}
+
+func GetUITLSSecretName(cr *v1.Observability) string {
+	return getUIAccess(cr).TLSSecret
+}
+
+func GetUIIngressClassName(cr *v1.Observability) *string {
+	return getUIAccess(cr).IngressClassName
+}
+
+func GetUIAccessOIDC(cr *v1.Observability) *v1.UIAccessOIDC {
+	return getUIAccess(cr).OIDC
+}
+
+func GetPrometheusIngress(cr *v1.Observability) *v14.Ingress {
+	return &v14.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetDefaultNamePrometheus(cr),
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetAlertmanagerIngress(cr *v1.Observability) *v14.Ingress {
+	return &v14.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetDefaultNameAlertmanager(cr),
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// The backends only accept TLS, so ingress controllers have to re-encrypt. Annotations from the
+// CR take precedence
+func GetUIIngressAnnotations(cr *v1.Observability) map[string]string {
+	annotations := map[string]string{
+		"route.openshift.io/termination":               "reencrypt",
+		"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+	}
+	for key, value := range getUIAccess(cr).IngressAnnotations {
+		annotations[key] = value
+	}
+	return annotations
+}
+
+func GetUIAccessRole(cr *v1.Observability) *v13.Role {
+	return &v13.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      UIAccessName,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetUIAccessRoleBinding(cr *v1.Observability) *v13.RoleBinding {
+	return &v13.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      UIAccessName,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func GetUIAccessSubjects(cr *v1.Observability) []v13.Subject {
+	var subjects []v13.Subject
+	for _, group := range getUIAccess(cr).Groups {
+		subjects = append(subjects, v13.Subject{
+			Kind:     v13.GroupKind,
+			APIGroup: v13.GroupName,
+			Name:     group,
+		})
+	}
+	for _, user := range getUIAccess(cr).Users {
+		subjects = append(subjects, v13.Subject{
+			Kind:     v13.UserKind,
+			APIGroup: v13.GroupName,
+			Name:     user,
+		})
+	}
+	return subjects
+}
+
+// Subject access review the OAuth proxy performs for users logging in to a UI. Requests with a
+// bearer token are still authorized with the delegate urls, so that Grafana and the operator keep
+// their access
+func GetOAuthProxySAR(cr *v1.Observability, service string) string {
+	if !cr.HasUIAccessRBAC() {
+		return "{\"resource\": \"namespaces\", \"verb\": \"get\"}"
+	}
+	return fmt.Sprintf("{\"namespace\": \"%s\", \"resource\": \"services\", \"resourceName\": \"%s\", \"verb\": \"get\"}", cr.Namespace, service)
+}
+
+// The OAuth server only redirects back to registered URIs. Routes are referenced by name,
+// Ingresses by their host
+func getOAuthRedirectAnnotations(cr *v1.Observability, route string, host string) map[string]string {
+	if cr.GetUIAccessType() == v1.UIAccessIngress {
+		return map[string]string{
+			"serviceaccounts.openshift.io/oauth-redirecturi.primary": fmt.Sprintf("https://%s", host),
+		}
+	}
+
+	redirect := fmt.Sprintf("{\"kind\":\"OAuthRedirectReference\",\"apiVersion\":\"v1\",\"reference\":{\"kind\":\"Route\",\"name\":\"%s\"}}", route)
+	return map[string]string{
+		"serviceaccounts.openshift.io/oauth-redirectreference.primary": redirect,
+	}
+}
+
+// OAuth proxy of a UI on other distributions than OpenShift. Users log in with the OIDC provider of
+// the CR instead of their OpenShift account, and the groups of the CR are checked against their
+// groups claim instead of the permissions on the service. Like the OpenShift proxy it serves TLS on
+// the proxy port with the certificate of the TLS secret
+func GetOIDCProxyContainer(cr *v1.Observability, name string, port string, upstream string, tlsSecret string, proxySecret string) corev1.Container {
+	oidc := GetUIAccessOIDC(cr)
+	args := []string{
+		"--provider=oidc",
+		fmt.Sprintf("--oidc-issuer-url=%v", oidc.IssuerURL),
+		"--https-address=:9091",
+		"--reverse-proxy=true",
+		"--email-domain=*",
+		fmt.Sprintf("--upstream=%v", upstream),
+		"--tls-cert-file=/etc/tls/private/tls.crt",
+		"--tls-key-file=/etc/tls/private/tls.key",
+		"--skip-auth-route=^/metrics",
+	}
+	for _, group := range getUIAccess(cr).Groups {
+		args = append(args, fmt.Sprintf("--allowed-group=%v", group))
+	}
+
+	secretEnv := func(name string, secret string, key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+					Key:                  key,
+				},
+			},
+		}
+	}
+
+	return corev1.Container{
+		Name:  name,
+		Image: GetImage(cr, v1.ImageOAuth2Proxy, OAuth2ProxyImage),
+		Args:  args,
+		Env: []corev1.EnvVar{
+			secretEnv("OAUTH2_PROXY_CLIENT_ID", oidc.CredentialsSecret, UIAccessOIDCClientId),
+			secretEnv("OAUTH2_PROXY_CLIENT_SECRET", oidc.CredentialsSecret, UIAccessOIDCClientSecret),
+			secretEnv("OAUTH2_PROXY_COOKIE_SECRET", proxySecret, OIDCProxyCookieSecret),
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          port,
+				ContainerPort: 9091,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      fmt.Sprintf("secret-%v", tlsSecret),
+				MountPath: "/etc/tls/private",
+			},
+		},
+	}
+}
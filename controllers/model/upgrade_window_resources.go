@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+// Security fixes can't wait for the next window. Setting the annotation to a new value rolls out
+// everything that is deferred with the next sync
+const RolloutNowAnnotation = "observability.redhat.com/rollout-now"
+
+// Returns true if the rollout annotation changed since the last sync
+func RolloutNowRequested(cr *v1.Observability) bool {
+	rollout := cr.Annotations[RolloutNowAnnotation]
+	return rollout != "" && rollout != cr.Status.RolloutRequested
+}
+
+// Returns true if upgrades and rollouts that restart pods can be applied now
+func RolloutAllowed(cr *v1.Observability, now time.Time) bool {
+	if cr.Spec.UpgradeWindow == nil || RolloutNowRequested(cr) {
+		return true
+	}
+	open, _, _ := cr.Spec.UpgradeWindow.Open(now)
+	return open
+}
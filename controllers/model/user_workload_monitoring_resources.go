@@ -0,0 +1,61 @@
+package model
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/api/core/v1"
+	v13 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ClusterMonitoringConfigName      = "cluster-monitoring-config"
+	OpenshiftMonitoringNamespace     = "openshift-monitoring"
+	UserWorkloadMonitoringConfigName = "user-workload-monitoring-config"
+	UserWorkloadMonitoringNamespace  = "openshift-user-workload-monitoring"
+	// Copies of pod monitors and rules in other namespaces are labelled with the namespace of the stack
+	UserWorkloadStackLabel  = "observability-namespace"
+	defaultThanosQuerierUrl = "https://thanos-querier.openshift-monitoring.svc:9091"
+)
+
+func GetClusterMonitoringConfigMap() *v12.ConfigMap {
+	return &v12.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterMonitoringConfigName,
+			Namespace: OpenshiftMonitoringNamespace,
+		},
+	}
+}
+
+func GetUserWorkloadMonitoringConfigMap() *v12.ConfigMap {
+	return &v12.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      UserWorkloadMonitoringConfigName,
+			Namespace: UserWorkloadMonitoringNamespace,
+		},
+	}
+}
+
+func GetThanosQuerierUrl(cr *v1.Observability) string {
+	if cr.Spec.UserWorkloadMonitoring != nil && cr.Spec.UserWorkloadMonitoring.ThanosQuerierURL != "" {
+		return cr.Spec.UserWorkloadMonitoring.ThanosQuerierURL
+	}
+	return defaultThanosQuerierUrl
+}
+
+// Allows Grafana to query Thanos Querier with its service account token
+func GetGrafanaMonitoringViewBinding(cr *v1.Observability) *v13.ClusterRoleBinding {
+	return &v13.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("grafana-cluster-monitoring-view-%s", cr.Namespace),
+		},
+	}
+}
+
+func GetUserWorkloadStackLabels(cr *v1.Observability) map[string]string {
+	return map[string]string{
+		"managed-by":           "observability-operator",
+		UserWorkloadStackLabel: cr.Namespace,
+	}
+}
@@ -18,21 +18,46 @@ import (
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/access"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/alert_forwarder_installation"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/alert_history_installation"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/alert_ticketing_installation"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/alertmanager_installation"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/capabilities"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/console_integration"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/csv"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/datasource_health"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/external_secrets"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/grafana_configuration"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/grafana_installation"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/internal_tls"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/migration"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/observatorium_tenant"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/profiles"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/prometheus_adapter_installation"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/prometheus_configuration"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/prometheus_installation"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/promtail_installation"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/self_monitoring"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/stack_verification"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/tempo_installation"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/version_check"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 )
@@ -48,32 +73,61 @@ const (
 // ObservabilityReconciler reconciles a Observability object
 type ObservabilityReconciler struct {
 	client.Client
-	Log             logr.Logger
-	Scheme          *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Log level of the operator, changed at runtime from the logging config map
+	// Namespaces of which CRs are reconciled, all namespaces when nil
+	WatchNamespaces *WatchNamespaces
+	// Reconcile results for the health probes, not tracked when nil
+	Health          *ReconcileHealth
+	Diagnostics     *ReconcileDiagnostics
+	dependents      *namespacedDependents
 	installComplete bool
+	progress        map[types.NamespacedName]stageProgress
 }
 
 // +kubebuilder:rbac:groups=observability.redhat.com,resources=observabilities,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=observability.redhat.com,resources=observabilities/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=observability.redhat.com,resources=observabilitydefaults,verbs=get;list;watch
 // +kubebuilder:rbac:groups=corev1,resources=configmaps,verbs=get;list;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors;alertmanagers;prometheuses;prometheuses/finalizers;alertmanagers/finalizers;servicemonitors;prometheusrules;thanosrulers;thanosrulers/finalizers,verbs=get;list;create;update;patch;delete;watch
 // +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=privileged,verbs=use
-// +kubebuilder:rbac:groups=integreatly.org,resources=grafanas;grafanadashboards;grafanadatasources,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;create;update;delete;watch
+// +kubebuilder:rbac:groups=integreatly.org,resources=grafanas;grafanadashboards;grafanadatasources,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;create;update;patch;delete;watch
 // +kubebuilder:rbac:urls=/metrics,verbs=get
 // +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups=cert-manager.io,resources=issuers;certificates,verbs=get;list;create;update;patch;delete;watch
 // +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=apps,resources=deployments;daemonsets;statefulsets,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=operators.coreos.com,resources=catalogsources;subscriptions;operatorgroups;clusterserviceversions,verbs=get;list;create;update;delete;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings;roles;rolebindings,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,resourceNames=cluster-monitoring-view,verbs=bind
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,resourceNames="system:auth-delegator",verbs=bind
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,resourceNames=extension-apiserver-authentication-reader,verbs=bind
+// +kubebuilder:rbac:groups=apiregistration.k8s.io,resources=apiservices,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=custom.metrics.k8s.io,resources=*,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;daemonsets;statefulsets,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=catalogsources;subscriptions;operatorgroups;clusterserviceversions;installplans,verbs=get;list;create;update;patch;delete;watch
 // +kubebuilder:rbac:groups="",resources=namespaces;pods;nodes;nodes/proxy,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=secrets;serviceaccounts;configmaps;endpoints;services;nodes/proxy,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;create;update;delete;watch
+// +kubebuilder:rbac:groups="",resources=secrets;serviceaccounts;configmaps;endpoints;services;nodes/proxy,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies;ingresses,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups="",resources=services/mtls,verbs=get;create
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=tempo.grafana.com,resources=tempostacks,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=external-secrets.io,resources=externalsecrets,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=console.openshift.io,resources=consolelinks;consoleplugins,verbs=get;list;create;update;patch;delete;watch
 
 func (r *ObservabilityReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
-	log := r.Log.WithValues("observability", req.NamespacedName)
+	log := r.Log.WithValues("observability", req.NamespacedName, "reconcile", newReconcileId())
+	r.Health.reconcileStarted()
+	defer r.Health.reconcileFinished()
+	r.Diagnostics.reconcileStarted(req.NamespacedName)
+	defer r.Diagnostics.reconcileFinished(req.NamespacedName)
 
 	// fetch Observability instance
 	obs := &apiv1.Observability{}
@@ -82,6 +136,8 @@ func (r *ObservabilityReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		if apierrors.IsNotFound(err) {
 			// CR deleted since request queued, child objects getting GC'd, no requeue
 			log.Info("Observability CR not found, has been deleted")
+			r.Health.forget(req.NamespacedName)
+			r.Diagnostics.forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		// error fetching observability instance, requeue and try again
@@ -89,6 +145,28 @@ func (r *ObservabilityReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		return ctrl.Result{}, err
 	}
 
+	// CRs outside of the watched namespaces are left alone, but are still cleaned up on deletion
+	// so that the finalizer is removed
+	watched, err := r.WatchNamespaces.IsWatched(ctx, r.Client, obs.Namespace)
+	if err != nil {
+		log.Error(err, "error checking the watched namespaces")
+		return ctrl.Result{}, err
+	}
+	if !watched && obs.DeletionTimestamp == nil {
+		log.V(1).Info("namespace of the Observability CR is not watched")
+		r.Health.forget(req.NamespacedName)
+		r.Diagnostics.forget(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	// Resources created by OLM are watched in the namespaces of the CRs only
+	if obs.DeletionTimestamp == nil {
+		err = r.dependents.watch(obs.Namespace)
+		if err != nil {
+			log.Error(err, "error watching the dependents in the namespace")
+		}
+	}
+
 	// Add a cleanup finalizer if not already present
 	if obs.DeletionTimestamp == nil && len(obs.Finalizers) == 0 {
 		obs.Finalizers = append(obs.Finalizers, ObservabilityFinalizer)
@@ -96,34 +174,97 @@ func (r *ObservabilityReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		return ctrl.Result{}, err
 	}
 
+	// Paused CRs are left alone, but are still cleaned up on deletion
+	if obs.DeletionTimestamp == nil && isPaused(obs) {
+		log.Info("reconciliation paused")
+		nextStatus := obs.Status.DeepCopy()
+		setPausedCondition(obs, nextStatus, nil)
+		r.Health.recordResult(req.NamespacedName, false)
+		return r.updateStatus(obs, nextStatus)
+	}
+
+	// The CR is not updated after this point, except for its status, the finalizer on deletion and the
+	// spec translated from the v2 resources by the migration stage
+	if obs.DeletionTimestamp == nil {
+		err = r.applyDefaults(ctx, obs)
+		if err != nil {
+			log.Error(err, "error applying the observability defaults")
+			return ctrl.Result{}, err
+		}
+	}
+
 	var finished = true
+	var failed = false
 
 	var stages []apiv1.ObservabilityStageName
+	removed := map[apiv1.ObservabilityStageName]bool{}
 	if obs.DeletionTimestamp == nil {
-		stages = r.getInstallationStages()
+		modes := getStageComponentModes(obs)
+		// Disabled components are removed first, in cleanup order
+		for _, stage := range r.getCleanupStages() {
+			if modes[stage] == apiv1.ComponentDisabled {
+				stages = append(stages, stage)
+				removed[stage] = true
+			}
+		}
+		for _, stage := range r.getInstallationStages() {
+			if mode, ok := modes[stage]; !ok || mode == apiv1.ComponentManaged {
+				stages = append(stages, stage)
+			}
+		}
 	} else {
 		stages = r.getCleanupStages()
 	}
 
 	nextStatus := obs.Status.DeepCopy()
+	setPausedCondition(obs, nextStatus, stages)
+	pausedStages := getPausedStages(obs)
+	recorder := &annotatingRecorder{
+		EventRecorder: r.Recorder,
+		cr:            obs,
+		status:        nextStatus,
+	}
 
 	for _, stage := range stages {
+		if obs.DeletionTimestamp == nil && pausedStages[stage] {
+			log.V(1).Info("stage paused", "stage", stage)
+			continue
+		}
 		nextStatus.Stage = stage
 
-		reconciler := r.getReconcilerForStage(stage)
+		stageLog := log.WithValues("stage", stage)
+		stageClient := &driftClient{
+			Client:   r.Client,
+			scheme:   r.Scheme,
+			recorder: recorder,
+			log:      stageLog,
+			cr:       obs,
+			status:   nextStatus,
+		}
+		reconciler := r.getReconcilerForStage(stage, stageLog, stageClient, recorder)
 		if reconciler != nil {
 			var status apiv1.ObservabilityStageStatus
 			var err error
 
-			if obs.DeletionTimestamp == nil {
+			r.Diagnostics.stageStarted(req.NamespacedName, stage)
+			start := time.Now()
+			if obs.DeletionTimestamp == nil && !removed[stage] {
 				status, err = reconciler.Reconcile(ctx, obs, nextStatus)
 			} else {
 				status, err = reconciler.Cleanup(ctx, obs)
 			}
+			logStageResult(log, stage, status, time.Since(start))
+			r.Diagnostics.stageFinished(req.NamespacedName, stage, status, err, time.Since(start))
 
 			if err != nil {
 				log.Error(err, fmt.Sprintf("reconciler error in stage %v", stage))
+				failed = true
 				nextStatus.LastMessage = err.Error()
+				if obs.DeletionTimestamp == nil {
+					r.Recorder.Eventf(obs, v1.EventTypeWarning, apiv1.EventStageFailed, "stage %v failed: %v", stage, err)
+				} else {
+					r.Recorder.Eventf(obs, v1.EventTypeWarning, apiv1.EventCleanupFailed, "cleanup of stage %v failed: %v", stage, err)
+				}
 			} else {
 				// Reset error message when everything went well
 				nextStatus.LastMessage = ""
@@ -133,10 +274,14 @@ func (r *ObservabilityReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 
 			// If a stage is not complete, do not continue with the next
 			if status != apiv1.ResultSuccess {
+				waiting := r.waitingSince(req.NamespacedName, stage)
 				if obs.DeletionTimestamp == nil {
-					log.Info("stack install in progress", "working stage", stage)
+					log.Info("stack install in progress", "working stage", stage, "waiting", waiting.String())
 				} else {
-					log.Info("stack cleanup in progress", "working stage", stage)
+					log.Info("stack cleanup in progress", "working stage", stage, "waiting", waiting.String())
+					if err == nil {
+						r.Recorder.Eventf(obs, v1.EventTypeNormal, apiv1.EventCleanupInProgress, "waiting for cleanup of stage %v", stage)
+					}
 				}
 				finished = false
 				break
@@ -144,28 +289,109 @@ func (r *ObservabilityReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		}
 	}
 
+	if finished {
+		r.resetProgress(req.NamespacedName)
+	}
+	setDegradedCondition(nextStatus)
+	setRolloutsDeferredCondition(obs, nextStatus)
+	r.Health.recordResult(req.NamespacedName, failed)
+
 	if obs.DeletionTimestamp == nil && finished && !r.installComplete {
 		r.installComplete = true
 		log.Info("stack installation complete")
+		r.Recorder.Event(obs, v1.EventTypeNormal, apiv1.EventInstallationComplete, "all stages installed")
 	}
 
 	// Ready for deletion?
 	// Only remove the finalizer when all stages were successful
 	if obs.DeletionTimestamp != nil && finished {
 		log.Info("cleanup stages complete, removing finalizer")
+		r.Recorder.Event(obs, v1.EventTypeNormal, apiv1.EventCleanupComplete, "all stages cleaned up")
 		obs.Finalizers = []string{}
 		err = r.Update(ctx, obs)
 		r.installComplete = false
+		r.Health.forget(req.NamespacedName)
+		r.Diagnostics.forget(req.NamespacedName)
 		return ctrl.Result{}, err
 	}
 
+	if obs.DeletionTimestamp == nil {
+		r.reportFleetTelemetry(ctx, log, obs, nextStatus)
+	}
+
 	return r.updateStatus(obs, nextStatus)
 }
 
 func (r *ObservabilityReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	dynamicClient, err := dynamic.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	informers, err := newDependentInformers(mgr, dynamicClient)
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Observability{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &v1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapReferencedSecret),
+		}).
+		Watches(&source.Kind{Type: &apiv1.ObservabilityDefaults{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapDefaults),
+		})
+	if r.WatchNamespaces != nil && r.WatchNamespaces.Selector != nil {
+		builder = builder.Watches(&source.Kind{Type: &v1.Namespace{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapWatchedNamespace),
+		})
+	}
+	dependentHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(r.mapDependent),
+	}
+	for _, informer := range informers {
+		builder = builder.Watches(&source.Informer{Informer: informer.informer}, dependentHandler, ctrlbuilder.WithPredicates(informer.predicate))
+	}
+	c, err := builder.Build(r)
+	if err != nil {
+		return err
+	}
+
+	r.dependents = &namespacedDependents{
+		mgr:           mgr,
+		controller:    c,
+		dynamicClient: dynamicClient,
+		handler:       dependentHandler,
+		namespaces:    map[string]bool{},
+	}
+	return nil
+}
+
+// Enqueue the CRs referencing a secret so that credential rotations are picked up immediately, secrets
+// of the operator enqueue the CRs of their namespace
+func (r *ObservabilityReconciler) mapReferencedSecret(o handler.MapObject) []reconcile.Request {
+	if o.Meta.GetLabels()[utils.ManagedByLabel] == utils.ManagedByValue {
+		return r.mapDependent(o)
+	}
+
+	list := &apiv1.ObservabilityList{}
+	err := r.List(context.Background(), list)
+	if err != nil {
+		r.Log.Error(err, "error listing observability CRs for secret", "secret", o.Meta.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, obs := range list.Items {
+		if obs.Status.IsReferencedSecret(o.Meta.GetNamespace(), o.Meta.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: obs.Namespace,
+					Name:      obs.Name,
+				},
+			})
+		}
+	}
+	return requests
 }
 
 func (r *ObservabilityReconciler) UpdateOperand(from *apiv1.Observability, to *apiv1.Observability) error {
@@ -181,19 +407,21 @@ func (r *ObservabilityReconciler) UpdateOperand(from *apiv1.Observability, to *a
 	return nil
 }
 
-func (r *ObservabilityReconciler) InitializeOperand(mgr ctrl.Manager) error {
+// Returns the namespace of the operator pod. When running locally, the first watched namespace
+func GetOperatorNamespace() string {
 	// Try to retrieve the namespace from the pod filesystem first
-	r.Log.Info("determining if operand instantiation required")
-	var namespace string
 	namespacePath := "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
 	ns, err := ioutil.ReadFile(namespacePath)
 	if err != nil {
 		// If that does not work (running locally?) try the env vars
-		namespace = os.Getenv("WATCH_NAMESPACE")
-	} else {
-		namespace = string(ns)
+		return strings.TrimSpace(strings.Split(os.Getenv(WatchNamespaceEnv), ",")[0])
 	}
+	return strings.TrimSpace(string(ns))
+}
 
+func (r *ObservabilityReconciler) InitializeOperand(mgr ctrl.Manager) error {
+	r.Log.Info("determining if operand instantiation required")
+	namespace := GetOperatorNamespace()
 	if namespace == "" {
 		err := errors.New("unable to create operand: cannot detect operator namespace")
 		return err
@@ -203,6 +431,16 @@ func (r *ObservabilityReconciler) InitializeOperand(mgr ctrl.Manager) error {
 	mgrClient := mgr.GetClient()
 	apiReader := mgr.GetAPIReader()
 
+	// the operand would not be reconciled outside of the watched namespaces
+	watched, err := r.WatchNamespaces.IsWatched(context.Background(), apiReader, strings.TrimSpace(namespace))
+	if err != nil {
+		return err
+	}
+	if !watched {
+		r.Log.Info("operator namespace is not watched so wont create observability cr")
+		return nil
+	}
+
 	// don't initialise the operand if the following config map is found in the operator namespace
 	configMap := &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -283,32 +521,89 @@ func (r *ObservabilityReconciler) InitializeOperand(mgr ctrl.Manager) error {
 
 func (r *ObservabilityReconciler) getInstallationStages() []apiv1.ObservabilityStageName {
 	return []apiv1.ObservabilityStageName{
+		apiv1.CapabilityDetection,
+		apiv1.Migration,
+		apiv1.ExternalSecretSync,
 		apiv1.TokenRequest,
+		apiv1.TenantVerification,
+		apiv1.InternalTLS,
 		apiv1.PrometheusInstallation,
 		apiv1.PrometheusConfiguration,
 		apiv1.GrafanaInstallation,
 		apiv1.GrafanaConfiguration,
 		apiv1.AlertmanagerInstallation,
+		apiv1.AlertForwarderInstallation,
+		apiv1.AlertTicketingInstallation,
+		apiv1.AlertHistoryInstallation,
 		apiv1.PromtailInstallation,
+		apiv1.TracingInstallation,
+		apiv1.PrometheusAdapterInstallation,
 		apiv1.Csv,
 		apiv1.Configuration,
+		apiv1.SelfMonitoringConfiguration,
+		apiv1.ProfilesConfiguration,
+		apiv1.AccessConfiguration,
+		apiv1.ConsoleIntegration,
+		apiv1.StackVerification,
+		apiv1.DatasourceHealthCheck,
+		apiv1.VersionCheck,
 	}
 }
 
 func (r *ObservabilityReconciler) getCleanupStages() []apiv1.ObservabilityStageName {
 	return []apiv1.ObservabilityStageName{
+		apiv1.ConsoleIntegration,
 		apiv1.PrometheusConfiguration,
 		apiv1.GrafanaConfiguration,
 		apiv1.PrometheusInstallation,
 		apiv1.GrafanaInstallation,
 		apiv1.AlertmanagerInstallation,
+		apiv1.AlertForwarderInstallation,
+		apiv1.AlertTicketingInstallation,
+		apiv1.AlertHistoryInstallation,
 		apiv1.PromtailInstallation,
+		apiv1.TracingInstallation,
+		apiv1.PrometheusAdapterInstallation,
+		apiv1.SelfMonitoringConfiguration,
+		apiv1.ProfilesConfiguration,
+		apiv1.AccessConfiguration,
 		apiv1.Configuration,
+		apiv1.InternalTLS,
 		apiv1.TokenRequest,
+		apiv1.ExternalSecretSync,
 		apiv1.Csv,
 	}
 }
 
+// Modes of the stages that install optional components. Stages of disabled components are cleaned
+// up, stages of external components are skipped
+func getStageComponentModes(cr *apiv1.Observability) map[apiv1.ObservabilityStageName]apiv1.ComponentMode {
+	modes := map[apiv1.ObservabilityStageName]apiv1.ComponentMode{
+		apiv1.PrometheusConfiguration:  cr.PrometheusMode(),
+		apiv1.AlertmanagerInstallation: cr.AlertmanagerMode(),
+		apiv1.GrafanaInstallation:      cr.GrafanaMode(),
+		apiv1.GrafanaConfiguration:     cr.GrafanaMode(),
+		apiv1.PromtailInstallation:     cr.PromtailMode(),
+	}
+
+	// An external Grafana is configured through its API
+	if cr.GrafanaExternal() && cr.GrafanaMode() == apiv1.ComponentExternal {
+		modes[apiv1.GrafanaConfiguration] = apiv1.ComponentManaged
+	}
+
+	// The Prometheus operator runs both Prometheus and Alertmanager
+	switch {
+	case cr.PrometheusMode() == apiv1.ComponentManaged || cr.AlertmanagerMode() == apiv1.ComponentManaged:
+		modes[apiv1.PrometheusInstallation] = apiv1.ComponentManaged
+	case cr.PrometheusMode() == apiv1.ComponentDisabled && cr.AlertmanagerMode() == apiv1.ComponentDisabled:
+		modes[apiv1.PrometheusInstallation] = apiv1.ComponentDisabled
+	default:
+		modes[apiv1.PrometheusInstallation] = apiv1.ComponentExternal
+	}
+
+	return modes
+}
+
 func (r *ObservabilityReconciler) updateStatus(cr *apiv1.Observability, nextStatus *apiv1.ObservabilityStatus) (ctrl.Result, error) {
 	if !reflect.DeepEqual(&cr.Status, nextStatus) {
 		nextStatus.DeepCopyInto(&cr.Status)
@@ -387,34 +682,84 @@ func observabilityInstanceWithoutStorage(namespace string) apiv1.Observability {
 	}
 }
 
-func (r *ObservabilityReconciler) getReconcilerForStage(stage apiv1.ObservabilityStageName) reconcilers.ObservabilityReconciler {
+func (r *ObservabilityReconciler) getReconcilerForStage(stage apiv1.ObservabilityStageName, log logr.Logger, c client.Client, recorder record.EventRecorder) reconcilers.ObservabilityReconciler {
 	switch stage {
+	case apiv1.CapabilityDetection:
+		return capabilities.NewReconciler(c, log)
+
 	case apiv1.PrometheusInstallation:
-		return prometheus_installation.NewReconciler(r.Client, r.Log, r.Scheme)
+		return prometheus_installation.NewReconciler(c, log, r.Scheme, recorder)
 
 	case apiv1.PrometheusConfiguration:
-		return prometheus_configuration.NewReconciler(r.Client, r.Log)
+		return prometheus_configuration.NewReconciler(c, log)
 
 	case apiv1.GrafanaInstallation:
-		return grafana_installation.NewReconciler(r.Client, r.Log)
+		return grafana_installation.NewReconciler(c, log, recorder)
 
 	case apiv1.GrafanaConfiguration:
-		return grafana_configuration.NewReconciler(r.Client, r.Log)
+		return grafana_configuration.NewReconciler(c, log)
 
 	case apiv1.Csv:
-		return csv.NewReconciler(r.Client, r.Log)
+		return csv.NewReconciler(c, log, recorder)
 
 	case apiv1.TokenRequest:
-		return token.NewReconciler(r.Client, r.Log)
+		return token.NewReconciler(c, log)
 
 	case apiv1.PromtailInstallation:
-		return promtail_installation.NewReconciler(r.Client, r.Log)
+		return promtail_installation.NewReconciler(c, log)
 
 	case apiv1.AlertmanagerInstallation:
-		return alertmanager_installation.NewReconciler(r.Client, r.Log)
+		return alertmanager_installation.NewReconciler(c, log)
+
+	case apiv1.TracingInstallation:
+		return tempo_installation.NewReconciler(c, log)
+
+	case apiv1.AlertForwarderInstallation:
+		return alert_forwarder_installation.NewReconciler(c, log)
+
+	case apiv1.AlertTicketingInstallation:
+		return alert_ticketing_installation.NewReconciler(c, log)
+	case apiv1.AlertHistoryInstallation:
+		return alert_history_installation.NewReconciler(c, log)
+
+	case apiv1.PrometheusAdapterInstallation:
+		return prometheus_adapter_installation.NewReconciler(c, log)
+
+	case apiv1.SelfMonitoringConfiguration:
+		return self_monitoring.NewReconciler(c, log)
+
+	case apiv1.ProfilesConfiguration:
+		return profiles.NewReconciler(c, log)
+
+	case apiv1.AccessConfiguration:
+		return access.NewReconciler(c, log)
+
+	case apiv1.InternalTLS:
+		return internal_tls.NewReconciler(c, log)
 
 	case apiv1.Configuration:
-		return configuration.NewReconciler(r.Client, r.Log)
+		return configuration.NewReconciler(c, log, recorder)
+
+	case apiv1.Migration:
+		return migration.NewReconciler(c, log)
+
+	case apiv1.TenantVerification:
+		return observatorium_tenant.NewReconciler(c, log)
+
+	case apiv1.StackVerification:
+		return stack_verification.NewReconciler(c, log)
+
+	case apiv1.DatasourceHealthCheck:
+		return datasource_health.NewReconciler(c, log)
+
+	case apiv1.VersionCheck:
+		return version_check.NewReconciler(c, log)
+
+	case apiv1.ConsoleIntegration:
+		return console_integration.NewReconciler(c, log)
+
+	case apiv1.ExternalSecretSync:
+		return external_secrets.NewReconciler(c, log)
 
 	default:
 		return nil
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Annotation on the CR to stop reconciling the stack, e.g. during incident response
+	PausedAnnotation = "observability.redhat.com/paused"
+	// Annotation on the CR with a comma separated list of stages that are skipped
+	PausedStagesAnnotation = "observability.redhat.com/paused-stages"
+)
+
+func isPaused(cr *apiv1.Observability) bool {
+	return strings.EqualFold(strings.TrimSpace(cr.Annotations[PausedAnnotation]), "true")
+}
+
+func getPausedStages(cr *apiv1.Observability) map[apiv1.ObservabilityStageName]bool {
+	result := map[apiv1.ObservabilityStageName]bool{}
+	for _, stage := range strings.Split(cr.Annotations[PausedStagesAnnotation], ",") {
+		stage = strings.TrimSpace(stage)
+		if stage != "" {
+			result[apiv1.ObservabilityStageName(stage)] = true
+		}
+	}
+	return result
+}
+
+// Flip the Paused condition. CRs that were never paused don't get the condition
+func setPausedCondition(cr *apiv1.Observability, s *apiv1.ObservabilityStatus, stages []apiv1.ObservabilityStageName) {
+	if isPaused(cr) {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    apiv1.Paused,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Paused",
+			Message: fmt.Sprintf("reconciliation paused by the %v annotation", PausedAnnotation),
+		})
+		return
+	}
+
+	paused := getPausedStages(cr)
+	var names []string
+	for _, stage := range stages {
+		if paused[stage] {
+			names = append(names, string(stage))
+		}
+	}
+	if len(names) > 0 {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    apiv1.Paused,
+			Status:  metav1.ConditionTrue,
+			Reason:  "StagesPaused",
+			Message: fmt.Sprintf("stages paused: %v", strings.Join(names, ", ")),
+		})
+		return
+	}
+
+	if meta.FindStatusCondition(s.Conditions, apiv1.Paused) != nil {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:   apiv1.Paused,
+			Status: metav1.ConditionFalse,
+			Reason: "Resumed",
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Name of the work queue of the controller in the workqueue metrics
+const diagnosticsQueueName = "observability"
+
+// StageDiagnostics is the outcome of the last run of a stage
+type StageDiagnostics struct {
+	Status        apiv1.ObservabilityStageStatus `json:"status"`
+	LastRun       time.Time                      `json:"lastRun"`
+	Duration      string                         `json:"duration"`
+	LastError     string                         `json:"lastError,omitempty"`
+	LastErrorTime *time.Time                     `json:"lastErrorTime,omitempty"`
+}
+
+// CRDiagnostics is the state of the stage pipeline of a CR
+type CRDiagnostics struct {
+	Namespace     string                                             `json:"namespace"`
+	Name          string                                             `json:"name"`
+	Running       bool                                               `json:"running"`
+	Stage         apiv1.ObservabilityStageName                       `json:"stage,omitempty"`
+	LastStarted   time.Time                                          `json:"lastStarted"`
+	LastFinished  *time.Time                                         `json:"lastFinished,omitempty"`
+	Stages        map[apiv1.ObservabilityStageName]*StageDiagnostics `json:"stages"`
+	CompletedRuns int64                                              `json:"completedRuns"`
+}
+
+// QueueDiagnostics is the state of the work queue of the controller, read from its metrics
+type QueueDiagnostics struct {
+	Depth                 float64 `json:"depth"`
+	UnfinishedWorkSeconds float64 `json:"unfinishedWorkSeconds"`
+}
+
+// ReconcileDiagnostics tracks the stage pipeline of every CR for the diagnostics endpoint. Like
+// the health tracker it is read from another goroutine than the reconciles, so all access is locked.
+type ReconcileDiagnostics struct {
+	lock sync.Mutex
+	crs  map[types.NamespacedName]*CRDiagnostics
+}
+
+func NewReconcileDiagnostics() *ReconcileDiagnostics {
+	return &ReconcileDiagnostics{
+		crs: map[types.NamespacedName]*CRDiagnostics{},
+	}
+}
+
+func (d *ReconcileDiagnostics) get(key types.NamespacedName) *CRDiagnostics {
+	cr, ok := d.crs[key]
+	if !ok {
+		cr = &CRDiagnostics{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Stages:    map[apiv1.ObservabilityStageName]*StageDiagnostics{},
+		}
+		d.crs[key] = cr
+	}
+	return cr
+}
+
+func (d *ReconcileDiagnostics) reconcileStarted(key types.NamespacedName) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	cr := d.get(key)
+	cr.Running = true
+	cr.LastStarted = time.Now()
+}
+
+func (d *ReconcileDiagnostics) stageStarted(key types.NamespacedName, stage apiv1.ObservabilityStageName) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.get(key).Stage = stage
+}
+
+// The last error of a stage is kept after the stage succeeds again
+func (d *ReconcileDiagnostics) stageFinished(key types.NamespacedName, stage apiv1.ObservabilityStageName, status apiv1.ObservabilityStageStatus, err error, duration time.Duration) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	cr := d.get(key)
+	result, ok := cr.Stages[stage]
+	if !ok {
+		result = &StageDiagnostics{}
+		cr.Stages[stage] = result
+	}
+	now := time.Now()
+	result.Status = status
+	result.LastRun = now
+	result.Duration = duration.String()
+	if err != nil {
+		result.LastError = err.Error()
+		result.LastErrorTime = &now
+	}
+}
+
+func (d *ReconcileDiagnostics) reconcileFinished(key types.NamespacedName) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	cr, ok := d.crs[key]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	cr.Running = false
+	cr.LastFinished = &now
+	cr.CompletedRuns++
+}
+
+func (d *ReconcileDiagnostics) forget(key types.NamespacedName) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.crs, key)
+}
+
+// Returns a copy of the tracked CRs, optionally only those of a namespace
+func (d *ReconcileDiagnostics) snapshot(namespace string) []CRDiagnostics {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	result := []CRDiagnostics{}
+	for _, cr := range d.crs {
+		if namespace != "" && cr.Namespace != namespace {
+			continue
+		}
+		copied := *cr
+		copied.Stages = map[apiv1.ObservabilityStageName]*StageDiagnostics{}
+		for stage, status := range cr.Stages {
+			s := *status
+			copied.Stages[stage] = &s
+		}
+		result = append(result, copied)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// The items of the work queue are not accessible, only its size
+func getQueueDiagnostics() (*QueueDiagnostics, error) {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QueueDiagnostics{}
+	for _, family := range families {
+		for _, metric := range family.Metric {
+			queue := false
+			for _, label := range metric.Label {
+				if label.GetName() == "name" && label.GetValue() == diagnosticsQueueName {
+					queue = true
+				}
+			}
+			if !queue || metric.Gauge == nil {
+				continue
+			}
+			switch family.GetName() {
+			case "workqueue_depth":
+				result.Depth = metric.Gauge.GetValue()
+			case "workqueue_unfinished_work_seconds":
+				result.UnfinishedWorkSeconds = metric.Gauge.GetValue()
+			}
+		}
+	}
+	return result, nil
+}
+
+// ServeHTTP dumps the pipeline state of the CRs and the work queue as JSON, ?namespace= restricts
+// the dump to the CRs of a namespace
+func (d *ReconcileDiagnostics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	queue, err := getQueueDiagnostics()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(struct {
+		Time       time.Time        `json:"time"`
+		Queue      QueueDiagnostics `json:"queue"`
+		Reconciles []CRDiagnostics  `json:"reconciles"`
+	}{
+		Time:       time.Now(),
+		Queue:      *queue,
+		Reconciles: d.snapshot(r.URL.Query().Get("namespace")),
+	})
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var reconcileFailingDesc = prometheus.NewDesc("observability_operator_reconcile_failing",
+	"1 if the Observability CR has not been reconciled without errors for the failure threshold, 0 otherwise",
+	[]string{"namespace", "name"}, nil)
+
+// ReconcileHealth tracks the reconcile runs for the liveness probe and the reconcile metrics of the
+// operator. Both are served from another goroutine than the reconciles, so all access is locked.
+// Failing CRs are not reported by the readiness probe, an unready leader would drop out of the
+// endpoints of the webhook and reject the changes that fix the CRs.
+type ReconcileHealth struct {
+	// A CR is failing when it has not been reconciled without errors for this long
+	FailureThreshold time.Duration
+	// Liveness fails when a reconcile runs for this long, or no reconcile finished for this long
+	// while there are CRs to reconcile
+	StallThreshold time.Duration
+
+	lock sync.Mutex
+	// Time of the last reconcile without stage errors per CR
+	lastHealthy map[types.NamespacedName]time.Time
+	running     time.Time
+	finished    time.Time
+}
+
+func NewReconcileHealth(failureThreshold time.Duration, stallThreshold time.Duration) *ReconcileHealth {
+	return &ReconcileHealth{
+		FailureThreshold: failureThreshold,
+		StallThreshold:   stallThreshold,
+		lastHealthy:      map[types.NamespacedName]time.Time{},
+		finished:         time.Now(),
+	}
+}
+
+func (h *ReconcileHealth) reconcileStarted() {
+	if h == nil {
+		return
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.running = time.Now()
+}
+
+func (h *ReconcileHealth) reconcileFinished() {
+	if h == nil {
+		return
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.running = time.Time{}
+	h.finished = time.Now()
+}
+
+// A CR that is seen for the first time gets the full threshold to reconcile without errors
+func (h *ReconcileHealth) recordResult(key types.NamespacedName, failed bool) {
+	if h == nil {
+		return
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if _, ok := h.lastHealthy[key]; !ok || !failed {
+		h.lastHealthy[key] = time.Now()
+	}
+}
+
+func (h *ReconcileHealth) forget(key types.NamespacedName) {
+	if h == nil {
+		return
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.lastHealthy, key)
+}
+
+func (h *ReconcileHealth) Describe(ch chan<- *prometheus.Desc) {
+	ch <- reconcileFailingDesc
+}
+
+func (h *ReconcileHealth) Collect(ch chan<- prometheus.Metric) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for key, lastHealthy := range h.lastHealthy {
+		failing := 0.0
+		if time.Since(lastHealthy) > h.FailureThreshold {
+			failing = 1
+		}
+		ch <- prometheus.MustNewConstMetric(reconcileFailingDesc, prometheus.GaugeValue, failing, key.Namespace, key.Name)
+	}
+}
+
+func (h *ReconcileHealth) LivenessCheck(_ *http.Request) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if !h.running.IsZero() {
+		if since := time.Since(h.running); since > h.StallThreshold {
+			return fmt.Errorf("reconcile running for %v", since.Round(time.Second))
+		}
+		return nil
+	}
+	// Standby replicas don't reconcile and have no CRs
+	if len(h.lastHealthy) > 0 {
+		if since := time.Since(h.finished); since > h.StallThreshold {
+			return fmt.Errorf("no reconcile finished for %v", since.Round(time.Second))
+		}
+	}
+	return nil
+}
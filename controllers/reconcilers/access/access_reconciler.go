@@ -0,0 +1,161 @@
+package access
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var readVerbs = []string{"get", "list", "watch"}
+
+var writeVerbs = []string{"create", "update", "patch", "delete"}
+
+// Viewers see the resources of the stack. Permission to get the services passes the subject access
+// review of the OAuth proxies in front of the Prometheus and Alertmanager UIs
+var viewerRules = []rbacv1.PolicyRule{
+	{
+		Verbs:     readVerbs,
+		APIGroups: []string{""},
+		Resources: []string{"services", "pods"},
+	},
+	{
+		Verbs:     readVerbs,
+		APIGroups: []string{"monitoring.coreos.com"},
+		Resources: []string{"prometheuses", "alertmanagers", "prometheusrules", "podmonitors", "servicemonitors"},
+	},
+	{
+		Verbs:     readVerbs,
+		APIGroups: []string{"integreatly.org"},
+		Resources: []string{"grafanas", "grafanadashboards", "grafanadatasources"},
+	},
+	{
+		Verbs:     readVerbs,
+		APIGroups: []string{"observability.redhat.com"},
+		Resources: []string{"observabilities"},
+	},
+}
+
+// Editors in addition manage the rules, monitors and dashboards
+var editorRules = append(append([]rbacv1.PolicyRule{}, viewerRules...),
+	rbacv1.PolicyRule{
+		Verbs:     writeVerbs,
+		APIGroups: []string{"monitoring.coreos.com"},
+		Resources: []string{"prometheusrules", "podmonitors", "servicemonitors"},
+	},
+	rbacv1.PolicyRule{
+		Verbs:     writeVerbs,
+		APIGroups: []string{"integreatly.org"},
+		Resources: []string{"grafanadashboards"},
+	},
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// The cluster roles are only removed when no other CR grants access
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	for _, binding := range []*rbacv1.RoleBinding{model.GetAccessViewerRoleBinding(cr), model.GetAccessEditorRoleBinding(cr)} {
+		err := r.client.Delete(ctx, binding)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	list := &v1.ObservabilityList{}
+	err := r.client.List(ctx, list)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	for _, other := range list.Items {
+		if other.Namespace == cr.Namespace && other.Name == cr.Name {
+			continue
+		}
+		if other.DeletionTimestamp == nil && other.HasAccessGroups() {
+			return v1.ResultSuccess, nil
+		}
+	}
+
+	for _, role := range []*rbacv1.ClusterRole{model.GetAccessViewerClusterRole(), model.GetAccessEditorClusterRole()} {
+		err := r.client.Delete(ctx, role)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// Binds the groups of spec.access to the viewer and editor cluster roles in the namespace of the CR
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	if !cr.HasAccessGroups() {
+		return r.Cleanup(ctx, cr)
+	}
+
+	viewer := model.GetAccessViewerClusterRole()
+	err := r.reconcileClusterRole(ctx, viewer, viewerRules)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	editor := model.GetAccessEditorClusterRole()
+	err = r.reconcileClusterRole(ctx, editor, editorRules)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = r.reconcileRoleBinding(ctx, model.GetAccessViewerRoleBinding(cr), viewer.Name, cr.Spec.Access.ViewerGroups)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = r.reconcileRoleBinding(ctx, model.GetAccessEditorRoleBinding(cr), editor.Name, cr.Spec.Access.EditorGroups)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileClusterRole(ctx context.Context, role *rbacv1.ClusterRole, rules []rbacv1.PolicyRule) error {
+	return utils.Apply(ctx, r.client, role, func() error {
+		role.Rules = rules
+		return nil
+	})
+}
+
+// Bindings without groups are removed
+func (r *Reconciler) reconcileRoleBinding(ctx context.Context, binding *rbacv1.RoleBinding, role string, groups []string) error {
+	if len(groups) == 0 {
+		err := r.client.Delete(ctx, binding)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	return utils.Apply(ctx, r.client, binding, func() error {
+		binding.Subjects = model.GetAccessGroupSubjects(groups)
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     role,
+		}
+		return nil
+	})
+}
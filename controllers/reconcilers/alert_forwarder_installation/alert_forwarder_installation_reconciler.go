@@ -0,0 +1,264 @@
+package alert_forwarder_installation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	"github.com/redhat-developer/observability-operator/v3/forwarder"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	objects := []runtime.Object{
+		model.GetAlertForwarderDeployment(cr),
+		model.GetAlertForwarderService(cr),
+		model.GetAlertForwarderSecret(cr),
+		model.GetAlertReceiverTokenSecret(cr, model.AlertForwarderName),
+		model.GetAlertReceiverNetworkPolicy(cr, model.AlertForwarderName),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// The forwarder is opt-in and removed again when all destinations are removed
+	if !cr.AlertForwarderEnabled() {
+		return r.Cleanup(ctx, cr)
+	}
+
+	image := model.GetAlertForwarderImage(cr)
+	if image == "" {
+		return v1.ResultFailed, fmt.Errorf("the image of the operator is unknown, set the %v image override", v1.ImageAlertForwarder)
+	}
+
+	status, err := r.reconcileConfig(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	status, err = r.reconcileService(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	// Only Alertmanager may send messages to the destinations
+	_, err = utils.ReconcileAlertReceiverToken(ctx, r.client, cr, model.AlertForwarderName)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	err = utils.ReconcileAlertReceiverNetworkPolicy(ctx, r.client, cr, model.AlertForwarderName, model.GetAlertForwarderSelectorLabels(), model.AlertForwarderPort)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return r.reconcileDeployment(ctx, cr, image)
+}
+
+// Renders the forwarder config with the urls and credentials read from the secrets of the destinations
+func (r *Reconciler) reconcileConfig(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	config := forwarder.Config{}
+	for _, destination := range cr.Spec.Alerting.Forwarder.Destinations {
+		result := forwarder.Destination{
+			Name:     destination.Name,
+			Type:     destination.Type,
+			Template: destination.Template,
+		}
+
+		if destination.URLSecret != "" {
+			url, err := r.getSecretValue(ctx, cr, destination.URLSecret, "url")
+			if err != nil {
+				return v1.ResultFailed, err
+			}
+			result.URL = url
+		}
+
+		if destination.EventBridge != nil {
+			accessKey, err := r.getSecretValue(ctx, cr, destination.EventBridge.CredentialsSecret, "access_key")
+			if err != nil {
+				return v1.ResultFailed, err
+			}
+			secretKey, err := r.getSecretValue(ctx, cr, destination.EventBridge.CredentialsSecret, "secret_key")
+			if err != nil {
+				return v1.ResultFailed, err
+			}
+			result.EventBridge = &forwarder.EventBridge{
+				Region:    destination.EventBridge.Region,
+				EventBus:  destination.EventBridge.EventBus,
+				Source:    destination.EventBridge.Source,
+				AccessKey: accessKey,
+				SecretKey: secretKey,
+			}
+		}
+
+		config.Destinations = append(config.Destinations, result)
+	}
+
+	configBytes, err := yaml.Marshal(&config)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	secret := model.GetAlertForwarderSecret(cr)
+	err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Type = core.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			model.AlertForwarderConfigKey: configBytes,
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) getSecretValue(ctx context.Context, cr *v1.Observability, name string, key string) (string, error) {
+	secret := &core.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: name}, secret)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[key]
+	if !ok || len(value) == 0 {
+		return "", fmt.Errorf("secret %v has no %v key", name, key)
+	}
+	return string(value), nil
+}
+
+func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	service := model.GetAlertForwarderService(cr)
+	err := utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = model.GetAlertForwarderSelectorLabels()
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "http",
+				Port:       model.AlertForwarderPort,
+				TargetPort: intstr.FromString("http"),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileDeployment(ctx context.Context, cr *v1.Observability, image string) (v1.ObservabilityStageStatus, error) {
+	deployment := model.GetAlertForwarderDeployment(cr)
+	var replicas int32 = 1
+	err := utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: model.GetAlertForwarderSelectorLabels(),
+		}
+		deployment.Spec.Template = core.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: model.GetAlertForwarderSelectorLabels(),
+			},
+			Spec: core.PodSpec{
+				PriorityClassName: model.ObservabilityPriorityClassName,
+				Tolerations:       cr.Spec.Tolerations,
+				Affinity:          cr.Spec.Affinity,
+				Containers: []core.Container{
+					{
+						Name:  "alert-forwarder",
+						Image: image,
+						Args: []string{
+							fmt.Sprintf("--alert-forwarder-config=%v/%v", model.AlertForwarderConfigPath, model.AlertForwarderConfigKey),
+							fmt.Sprintf("--alert-forwarder-addr=:%d", model.AlertForwarderPort),
+							fmt.Sprintf("--alert-forwarder-token-file=%v/%v", model.AlertReceiverTokenPath, model.AlertReceiverTokenKey),
+						},
+						Ports: []core.ContainerPort{
+							{
+								Name:          "http",
+								ContainerPort: model.AlertForwarderPort,
+							},
+						},
+						ReadinessProbe: &core.Probe{
+							Handler: core.Handler{
+								HTTPGet: &core.HTTPGetAction{
+									Path: "/healthz",
+									Port: intstr.FromString("http"),
+								},
+							},
+						},
+						VolumeMounts: []core.VolumeMount{
+							{
+								Name:      "config",
+								MountPath: model.AlertForwarderConfigPath,
+								ReadOnly:  true,
+							},
+							{
+								Name:      "token",
+								MountPath: model.AlertReceiverTokenPath,
+								ReadOnly:  true,
+							},
+						},
+					},
+				},
+				Volumes: []core.Volume{
+					{
+						Name: "config",
+						VolumeSource: core.VolumeSource{
+							Secret: &core.SecretVolumeSource{
+								SecretName: model.GetAlertForwarderSecret(cr).Name,
+							},
+						},
+					},
+					{
+						Name: "token",
+						VolumeSource: core.VolumeSource{
+							Secret: &core.SecretVolumeSource{
+								SecretName: model.GetAlertReceiverTokenSecret(cr, model.AlertForwarderName).Name,
+							},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
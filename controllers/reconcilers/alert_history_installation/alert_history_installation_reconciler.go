@@ -0,0 +1,328 @@
+package alert_history_installation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// The volume with the recorded transitions survives turning the history off and is only deleted
+// with the CR
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	err := r.deleteGrafanaResources(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	objects := []runtime.Object{
+		model.GetAlertHistoryDeployment(cr),
+		model.GetAlertHistoryService(cr),
+		model.GetAlertReceiverTokenSecret(cr, model.AlertHistoryName),
+		model.GetAlertReceiverNetworkPolicy(cr, model.AlertHistoryName),
+	}
+	if cr.DeletionTimestamp != nil {
+		objects = append(objects, model.GetAlertHistoryPersistentVolumeClaim(cr))
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) deleteGrafanaResources(ctx context.Context, cr *v1.Observability) error {
+	objects := []runtime.Object{
+		model.GetAlertHistoryDashboard(cr),
+		model.GetAlertHistoryDatasource(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// The history is opt-in and removed again when its config is removed
+	if !cr.AlertHistoryEnabled() {
+		return r.Cleanup(ctx, cr)
+	}
+
+	image := model.GetAlertHistoryImage(cr)
+	if image == "" {
+		return v1.ResultFailed, fmt.Errorf("the image of the operator is unknown, set the %v image override", v1.ImageAlertHistory)
+	}
+
+	status, err := r.reconcilePersistentVolumeClaim(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	status, err = r.reconcileService(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	// Only Alertmanager may record and only Grafana may query the transitions
+	token, err := utils.ReconcileAlertReceiverToken(ctx, r.client, cr, model.AlertHistoryName)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	err = utils.ReconcileAlertReceiverNetworkPolicy(ctx, r.client, cr, model.AlertHistoryName, model.GetAlertHistorySelectorLabels(), model.AlertHistoryPort, model.GetGrafanaPodLabels())
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	status, err = r.reconcileDeployment(ctx, cr, image)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	return r.reconcileGrafana(ctx, cr, s, token)
+}
+
+// Only the size of an existing claim is updated, volumes can grow but not shrink
+func (r *Reconciler) reconcilePersistentVolumeClaim(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	size, err := resource.ParseQuantity(model.GetAlertHistoryStorageSize(cr))
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	pvc := model.GetAlertHistoryPersistentVolumeClaim(cr)
+	err = utils.Apply(ctx, r.client, pvc, func() error {
+		if pvc.CreationTimestamp.IsZero() {
+			pvc.Spec.AccessModes = []core.PersistentVolumeAccessMode{core.ReadWriteOnce}
+			pvc.Spec.StorageClassName = cr.Spec.Alerting.History.StorageClass
+		}
+		current := pvc.Spec.Resources.Requests[core.ResourceStorage]
+		if current.Cmp(size) < 0 {
+			pvc.Spec.Resources.Requests = core.ResourceList{
+				core.ResourceStorage: size,
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	service := model.GetAlertHistoryService(cr)
+	err := utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = model.GetAlertHistorySelectorLabels()
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "http",
+				Port:       model.AlertHistoryPort,
+				TargetPort: intstr.FromString("http"),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// The volume is mounted read write once, the old pod has to go before the new one starts
+func (r *Reconciler) reconcileDeployment(ctx context.Context, cr *v1.Observability, image string) (v1.ObservabilityStageStatus, error) {
+	deployment := model.GetAlertHistoryDeployment(cr)
+	var replicas int32 = 1
+	err := utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Strategy = apps.DeploymentStrategy{
+			Type: apps.RecreateDeploymentStrategyType,
+		}
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: model.GetAlertHistorySelectorLabels(),
+		}
+		deployment.Spec.Template = core.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: model.GetAlertHistorySelectorLabels(),
+			},
+			Spec: core.PodSpec{
+				PriorityClassName: model.ObservabilityPriorityClassName,
+				Tolerations:       cr.Spec.Tolerations,
+				Affinity:          cr.Spec.Affinity,
+				Containers: []core.Container{
+					{
+						Name:  "alert-history",
+						Image: image,
+						Args: []string{
+							fmt.Sprintf("--alert-history-dir=%v", model.AlertHistoryDataPath),
+							fmt.Sprintf("--alert-history-addr=:%d", model.AlertHistoryPort),
+							fmt.Sprintf("--alert-history-retention-days=%d", model.GetAlertHistoryRetentionDays(cr)),
+							fmt.Sprintf("--alert-history-token-file=%v/%v", model.AlertReceiverTokenPath, model.AlertReceiverTokenKey),
+						},
+						Ports: []core.ContainerPort{
+							{
+								Name:          "http",
+								ContainerPort: model.AlertHistoryPort,
+							},
+						},
+						ReadinessProbe: &core.Probe{
+							Handler: core.Handler{
+								HTTPGet: &core.HTTPGetAction{
+									Path: "/healthz",
+									Port: intstr.FromString("http"),
+								},
+							},
+						},
+						VolumeMounts: []core.VolumeMount{
+							{
+								Name:      "data",
+								MountPath: model.AlertHistoryDataPath,
+							},
+							{
+								Name:      "token",
+								MountPath: model.AlertReceiverTokenPath,
+								ReadOnly:  true,
+							},
+						},
+					},
+				},
+				Volumes: []core.Volume{
+					{
+						Name: "data",
+						VolumeSource: core.VolumeSource{
+							PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+								ClaimName: model.GetAlertHistoryPersistentVolumeClaim(cr).Name,
+							},
+						},
+					},
+					{
+						Name: "token",
+						VolumeSource: core.VolumeSource{
+							Secret: &core.SecretVolumeSource{
+								SecretName: model.GetAlertReceiverTokenSecret(cr, model.AlertHistoryName).Name,
+							},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// The datasource and the dashboard are only provisioned in the managed Grafana
+func (r *Reconciler) reconcileGrafana(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, token string) (v1.ObservabilityStageStatus, error) {
+	if cr.GrafanaMode() != v1.ComponentManaged {
+		err := r.deleteGrafanaResources(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		return v1.ResultSuccess, nil
+	}
+
+	served, err := utils.RequireAPI(ctx, r.client, cr, s, utils.APIGrafanaDashboard)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if !served {
+		return v1.ResultSuccess, nil
+	}
+
+	datasource := model.GetAlertHistoryDatasource(cr)
+	err = utils.Apply(ctx, r.client, datasource, func() error {
+		datasource.Spec.Name = "alert-history.yaml"
+		datasource.Spec.Datasources = []v1alpha1.GrafanaDataSourceFields{
+			{
+				Name:     model.AlertHistoryDatasourceName,
+				Type:     model.AlertHistoryDatasourceType,
+				Access:   "proxy",
+				Url:      model.GetAlertHistoryDatasourceUrl(cr),
+				Version:  model.GetGrafanaDatasourceVersion(cr),
+				Editable: true,
+				JsonData: v1alpha1.GrafanaDataSourceJsonData{
+					HTTPHeaderName1: "Authorization",
+				},
+				SecureJsonData: v1alpha1.GrafanaDataSourceSecureJsonData{
+					HTTPHeaderValue1: "Bearer " + token,
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	source, err := model.GetAlertHistoryDashboardJson()
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	grafana := model.GetGrafanaCr(cr)
+	err = r.client.Get(ctx, client.ObjectKey{Namespace: grafana.Namespace, Name: grafana.Name}, grafana)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+	dashboard := model.GetAlertHistoryDashboard(cr)
+	err = utils.Apply(ctx, r.client, dashboard, func() error {
+		dashboard.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		if len(grafana.Spec.DashboardLabelSelector) > 0 && grafana.Spec.DashboardLabelSelector[0] != nil {
+			for k, v := range grafana.Spec.DashboardLabelSelector[0].MatchLabels {
+				dashboard.Labels[k] = v
+			}
+		}
+		dashboard.Spec = v1alpha1.GrafanaDashboardSpec{
+			Json: source,
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
@@ -0,0 +1,259 @@
+package alert_ticketing_installation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	"github.com/redhat-developer/observability-operator/v3/ticketing"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	objects := []runtime.Object{
+		model.GetAlertTicketingDeployment(cr),
+		model.GetAlertTicketingService(cr),
+		model.GetAlertTicketingSecret(cr),
+		model.GetAlertReceiverTokenSecret(cr, model.AlertTicketingName),
+		model.GetAlertReceiverNetworkPolicy(cr, model.AlertTicketingName),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// The bridge is opt-in and removed again when the ticketing config is removed
+	if !cr.AlertTicketingEnabled() {
+		return r.Cleanup(ctx, cr)
+	}
+
+	image := model.GetAlertTicketingImage(cr)
+	if image == "" {
+		return v1.ResultFailed, fmt.Errorf("the image of the operator is unknown, set the %v image override", v1.ImageAlertTicketing)
+	}
+
+	status, err := r.reconcileConfig(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	status, err = r.reconcileService(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	// Only Alertmanager may open tickets
+	_, err = utils.ReconcileAlertReceiverToken(ctx, r.client, cr, model.AlertTicketingName)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	err = utils.ReconcileAlertReceiverNetworkPolicy(ctx, r.client, cr, model.AlertTicketingName, model.GetAlertTicketingSelectorLabels(), model.AlertTicketingPort)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return r.reconcileDeployment(ctx, cr, image)
+}
+
+// Renders the bridge config with the credentials read from the secret
+func (r *Reconciler) reconcileConfig(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	spec := cr.Spec.Alerting.Ticketing
+	username, err := r.getSecretValue(ctx, cr, spec.CredentialsSecret, "username")
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	password, err := r.getSecretValue(ctx, cr, spec.CredentialsSecret, "password")
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	config := ticketing.Config{
+		Type:        spec.Type,
+		URL:         spec.URL,
+		Username:    username,
+		Password:    password,
+		Fields:      spec.Fields,
+		AutoResolve: spec.AutoResolveEnabled(),
+	}
+	if spec.ServiceNow != nil {
+		config.ServiceNow = &ticketing.ServiceNow{
+			Table:         spec.ServiceNow.Table,
+			ResolveFields: spec.ServiceNow.ResolveFields,
+		}
+	}
+	if spec.Jira != nil {
+		config.Jira = &ticketing.Jira{
+			Project:           spec.Jira.Project,
+			IssueType:         spec.Jira.IssueType,
+			ResolveTransition: spec.Jira.ResolveTransition,
+		}
+	}
+
+	configBytes, err := yaml.Marshal(&config)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	secret := model.GetAlertTicketingSecret(cr)
+	err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Type = core.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			model.AlertTicketingConfigKey: configBytes,
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) getSecretValue(ctx context.Context, cr *v1.Observability, name string, key string) (string, error) {
+	secret := &core.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: name}, secret)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[key]
+	if !ok || len(value) == 0 {
+		return "", fmt.Errorf("secret %v has no %v key", name, key)
+	}
+	return string(value), nil
+}
+
+func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	service := model.GetAlertTicketingService(cr)
+	err := utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = model.GetAlertTicketingSelectorLabels()
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "http",
+				Port:       model.AlertTicketingPort,
+				TargetPort: intstr.FromString("http"),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileDeployment(ctx context.Context, cr *v1.Observability, image string) (v1.ObservabilityStageStatus, error) {
+	deployment := model.GetAlertTicketingDeployment(cr)
+	var replicas int32 = 1
+	err := utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: model.GetAlertTicketingSelectorLabels(),
+		}
+		deployment.Spec.Template = core.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: model.GetAlertTicketingSelectorLabels(),
+			},
+			Spec: core.PodSpec{
+				PriorityClassName: model.ObservabilityPriorityClassName,
+				Tolerations:       cr.Spec.Tolerations,
+				Affinity:          cr.Spec.Affinity,
+				Containers: []core.Container{
+					{
+						Name:  "alert-ticketing",
+						Image: image,
+						Args: []string{
+							fmt.Sprintf("--alert-ticketing-config=%v/%v", model.AlertTicketingConfigPath, model.AlertTicketingConfigKey),
+							fmt.Sprintf("--alert-ticketing-addr=:%d", model.AlertTicketingPort),
+							fmt.Sprintf("--alert-ticketing-token-file=%v/%v", model.AlertReceiverTokenPath, model.AlertReceiverTokenKey),
+						},
+						Ports: []core.ContainerPort{
+							{
+								Name:          "http",
+								ContainerPort: model.AlertTicketingPort,
+							},
+						},
+						ReadinessProbe: &core.Probe{
+							Handler: core.Handler{
+								HTTPGet: &core.HTTPGetAction{
+									Path: "/healthz",
+									Port: intstr.FromString("http"),
+								},
+							},
+						},
+						VolumeMounts: []core.VolumeMount{
+							{
+								Name:      "config",
+								MountPath: model.AlertTicketingConfigPath,
+								ReadOnly:  true,
+							},
+							{
+								Name:      "token",
+								MountPath: model.AlertReceiverTokenPath,
+								ReadOnly:  true,
+							},
+						},
+					},
+				},
+				Volumes: []core.Volume{
+					{
+						Name: "config",
+						VolumeSource: core.VolumeSource{
+							Secret: &core.SecretVolumeSource{
+								SecretName: model.GetAlertTicketingSecret(cr).Name,
+							},
+						},
+					},
+					{
+						Name: "token",
+						VolumeSource: core.VolumeSource{
+							Secret: &core.SecretVolumeSource{
+								SecretName: model.GetAlertReceiverTokenSecret(cr, model.AlertTicketingName).Name,
+							},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
@@ -13,9 +13,9 @@ import (
 	v12 "k8s.io/api/core/v1"
 	v15 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type Reconciler struct {
@@ -56,14 +56,33 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return status, err
 	}
 
-	status, err = r.reconcileAlertmanagerRoute(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
+	accessType, err := utils.GetUIAccessType(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
 	}
 
-	status, err = r.waitForRoute(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
+	if accessType == v1.UIAccessIngress {
+		status, err = r.reconcileAlertmanagerIngress(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
+	} else {
+		served, err := utils.RequireAPI(ctx, r.client, cr, s, utils.APIRoute)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+
+		if served {
+			status, err = r.reconcileAlertmanagerRoute(ctx, cr)
+			if status != v1.ResultSuccess {
+				return status, err
+			}
+
+			status, err = r.waitForRoute(ctx, cr)
+			if status != v1.ResultSuccess {
+				return status, err
+			}
+		}
 	}
 
 	return v1.ResultSuccess, nil
@@ -101,6 +120,12 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 
 	route := model.GetAlertmanagerRoute(cr)
 	err = r.client.Delete(ctx, route)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
+	ingress := model.GetAlertmanagerIngress(cr)
+	err = r.client.Delete(ctx, ingress)
 	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
@@ -133,24 +158,18 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 }
 
 func (r *Reconciler) waitForAlertmanagerToBeRemoved(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
-	list := &v14.StatefulSetList{}
-	opts := &client.ListOptions{
-		Namespace: cr.Namespace,
+	// Created by the Prometheus operator
+	alertmanager := model.GetAlertmanagerCr(cr)
+	statefulSet := &v14.StatefulSet{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: fmt.Sprintf("alertmanager-%s", alertmanager.Name)}, statefulSet)
+	if errors.IsNotFound(err) {
+		return v1.ResultSuccess, nil
 	}
-	err := r.client.List(ctx, list, opts)
-	if err != nil && !errors.IsNotFound(err) {
+	if err != nil {
 		return v1.ResultFailed, err
 	}
 
-	alertmanager := model.GetAlertmanagerCr(cr)
-
-	for _, ss := range list.Items {
-		if ss.Name == fmt.Sprintf("prometheus-%s", alertmanager.Name) {
-			return v1.ResultInProgress, nil
-		}
-	}
-
-	return v1.ResultSuccess, nil
+	return v1.ResultInProgress, nil
 }
 
 func (r *Reconciler) waitForRoute(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
@@ -177,8 +196,10 @@ func (r *Reconciler) waitForRoute(ctx context.Context, cr *v1.Observability) (v1
 
 func (r *Reconciler) reconcileAlertmanagerServiceAccount(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	sa := model.GetAlertmanagerServiceAccount(cr)
+	annotations := sa.Annotations
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, sa, func() error {
+	err := utils.Apply(ctx, r.client, sa, func() error {
+		sa.Annotations = annotations
 		return nil
 	})
 	if err != nil {
@@ -191,7 +212,7 @@ func (r *Reconciler) reconcileAlertmanagerServiceAccount(ctx context.Context, cr
 func (r *Reconciler) reconcileAlertmanagerClusterRole(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	role := model.GetAlertmanagerClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, role, func() error {
+	err := utils.Apply(ctx, r.client, role, func() error {
 		role.Rules = []v15.PolicyRule{
 			{
 				Verbs:     []string{"create"},
@@ -217,7 +238,7 @@ func (r *Reconciler) reconcileAlertmanagerClusterRoleBinding(ctx context.Context
 	binding := model.GetAlertmanagerClusterRoleBinding(cr)
 	role := model.GetAlertmanagerClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, binding, func() error {
+	err := utils.Apply(ctx, r.client, binding, func() error {
 		binding.Subjects = []v15.Subject{
 			{
 				Kind:      v15.ServiceAccountKind,
@@ -243,7 +264,7 @@ func (r *Reconciler) reconcileAlertmanagerService(ctx context.Context, cr *v1.Ob
 	service := model.GetAlertmanagerService(cr)
 	alertmanager := model.GetAlertmanagerCr(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
+	err := utils.Apply(ctx, r.client, service, func() error {
 		service.Annotations = map[string]string{
 			"service.alpha.openshift.io/serving-cert-secret-name": "alertmanager-k8s-tls",
 		}
@@ -255,6 +276,14 @@ func (r *Reconciler) reconcileAlertmanagerService(ctx context.Context, cr *v1.Ob
 				TargetPort: intstr.FromString("proxy"),
 			},
 		}
+		if model.IsInternalTLSEnabled(cr) {
+			service.Spec.Ports = append(service.Spec.Ports, v12.ServicePort{
+				Name:       model.InternalTLSPortName,
+				Protocol:   "TCP",
+				Port:       model.InternalTLSPort,
+				TargetPort: intstr.FromString(model.InternalTLSPortName),
+			})
+		}
 		service.Spec.Selector = map[string]string{
 			"alertmanager": alertmanager.Name,
 		}
@@ -265,6 +294,11 @@ func (r *Reconciler) reconcileAlertmanagerService(ctx context.Context, cr *v1.Ob
 		return v1.ResultFailed, err
 	}
 
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
 	return v1.ResultSuccess, err
 }
 
@@ -272,13 +306,27 @@ func (r *Reconciler) reconcileAlertmanagerRoute(ctx context.Context, cr *v1.Obse
 	route := model.GetAlertmanagerRoute(cr)
 	service := model.GetAlertmanagerService(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, route, func() error {
+	// Remove the ingress when switching back to routes
+	ingress := model.GetAlertmanagerIngress(cr)
+	err := r.client.Delete(ctx, ingress)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	tls, err := utils.GetUIRouteTLS(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.Apply(ctx, r.client, route, func() error {
+		// Keep the host assigned by the router unless a custom one is configured
+		if model.GetAlertmanagerHost(cr) != "" {
+			route.Spec.Host = model.GetAlertmanagerHost(cr)
+		}
 		route.Spec.Port = &v13.RoutePort{
 			TargetPort: intstr.FromString("web"),
 		}
-		route.Spec.TLS = &v13.TLSConfig{
-			Termination: "reencrypt",
-		}
+		route.Spec.TLS = tls
 		route.Spec.To = v13.RouteTargetReference{
 			Kind: "Service",
 			Name: service.Name,
@@ -293,15 +341,43 @@ func (r *Reconciler) reconcileAlertmanagerRoute(ctx context.Context, cr *v1.Obse
 	return v1.ResultSuccess, err
 }
 
+func (r *Reconciler) reconcileAlertmanagerIngress(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	// Remove the route when switching to ingresses
+	route := model.GetAlertmanagerRoute(cr)
+	err := r.client.Delete(ctx, route)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
+	ingress := model.GetAlertmanagerIngress(cr)
+	service := model.GetAlertmanagerService(cr)
+
+	err = utils.ReconcileUIIngress(ctx, r.client, cr, ingress, model.GetAlertmanagerHost(cr), service.Name)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
 func (r *Reconciler) reconcileAlertmanagerProxySecret(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	secret := model.GetAlertmanagerProxySecret(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
-		if secret.Data == nil {
-			secret.Type = v12.SecretTypeOpaque
-			secret.StringData = map[string]string{
-				"session_secret": utils.GenerateRandomString(64),
-			}
+	sessionSecret, err := utils.GetOrGenerateSecretValue(ctx, r.client, secret, "session_secret", 64)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	cookieSecret, err := utils.GetOrGenerateSecretValue(ctx, r.client, secret, model.OIDCProxyCookieSecret, 32)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Type = v12.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			"session_secret":            sessionSecret,
+			model.OIDCProxyCookieSecret: cookieSecret,
 		}
 		return nil
 	})
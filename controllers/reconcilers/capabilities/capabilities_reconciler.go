@@ -0,0 +1,73 @@
+package capabilities
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// APIs can be installed or removed at any time, e.g. when OLM is added to a cluster
+const DetectionInterval = 10 * time.Minute
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	if s.Capabilities != nil {
+		lastDetected := time.Unix(s.Capabilities.LastDetected, 0)
+		if time.Now().Before(lastDetected.Add(DetectionInterval)) {
+			return v1.ResultSuccess, nil
+		}
+	}
+
+	capabilities, err := utils.DetectCapabilities(ctx, r.client, cr.Namespace)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	if s.Capabilities == nil || !sameCapabilities(s.Capabilities, capabilities) {
+		r.logger.Info("detected cluster capabilities",
+			"olm", capabilities.OLM,
+			"routes", capabilities.Routes,
+			"prometheus operator", capabilities.PrometheusOperator,
+			"grafana operator", capabilities.GrafanaOperator,
+			"tempo operator", capabilities.TempoOperator,
+			"cert-manager", capabilities.CertManager,
+			"external secrets operator", capabilities.ExternalSecretsOperator,
+			"console", capabilities.Console,
+			"openshift version", capabilities.OpenShiftVersion)
+	}
+
+	s.Capabilities = capabilities
+	return v1.ResultSuccess, nil
+}
+
+func sameCapabilities(a *v1.ClusterCapabilities, b *v1.ClusterCapabilities) bool {
+	return a.OLM == b.OLM &&
+		a.Routes == b.Routes &&
+		a.PrometheusOperator == b.PrometheusOperator &&
+		a.GrafanaOperator == b.GrafanaOperator &&
+		a.TempoOperator == b.TempoOperator &&
+		a.CertManager == b.CertManager &&
+		a.ExternalSecretsOperator == b.ExternalSecretsOperator &&
+		a.Console == b.Console &&
+		a.OpenShiftVersion == b.OpenShiftVersion
+}
@@ -0,0 +1,125 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The Prometheus operator mounts every key of the Alertmanager config secret into this directory
+const AlertmanagerTemplatesPath = "/etc/alertmanager/config/*.tmpl"
+
+var invalidTemplateNameChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// Functions Alertmanager provides to notification templates. Parsing only needs the names
+var alertTemplateFuncs = template.FuncMap{
+	"toUpper":      strings.ToUpper,
+	"toLower":      strings.ToLower,
+	"title":        strings.Title,
+	"join":         strings.Join,
+	"match":        regexp.MatchString,
+	"safeHtml":     func(text string) string { return text },
+	"reReplaceAll": func(pattern, repl, text string) string { return text },
+	"stringSlice":  func(s ...string) []string { return s },
+}
+
+func getUniqueAlertTemplates(indexes []v1.RepositoryIndex) []ResourceInfo {
+	var result []ResourceInfo
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Alertmanager == nil {
+			continue
+		}
+	seek:
+		for _, tmpl := range index.Config.Alertmanager.Templates {
+			name := getNameFromUrl(tmpl)
+			for _, existing := range result {
+				if existing.Name == name {
+					continue seek
+				}
+			}
+			result = append(result, ResourceInfo{
+				Id:          index.Id,
+				Name:        name,
+				Url:         fmt.Sprintf("%s/%s", index.BaseUrl, tmpl),
+				AccessToken: index.AccessToken,
+				Tag:         index.Tag,
+				Source:      getIndexSource(&index),
+			})
+		}
+	}
+	return result
+}
+
+// Template files of the config maps selected by spec.alerting.templates, named after the config map
+// and the key
+func (r *Reconciler) getAlertTemplateConfigMaps(ctx context.Context, cr *v1.Observability) (map[string][]byte, error) {
+	result := map[string][]byte{}
+	if cr.Spec.Alerting == nil || cr.Spec.Alerting.Templates == nil || cr.Spec.Alerting.Templates.ConfigMapSelector == nil {
+		return result, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(cr.Spec.Alerting.Templates.ConfigMapSelector)
+	if err != nil {
+		return nil, err
+	}
+	list := &v12.ConfigMapList{}
+	err = r.client.List(ctx, list, &client.ListOptions{
+		LabelSelector: selector,
+		Namespace:     cr.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, configMap := range list.Items {
+		for key, data := range configMap.Data {
+			name := fmt.Sprintf("%v-%v", configMap.Name, strings.TrimSuffix(key, path.Ext(key)))
+			result[name] = []byte(data)
+		}
+	}
+	return result, nil
+}
+
+// Returns the template files to add to the Alertmanager config secret, keyed by file name. Files
+// that fail to parse are left out and reported in the status
+func (r *Reconciler) getAlertTemplates(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, indexes []v1.RepositoryIndex) (map[string][]byte, error) {
+	files, err := r.getAlertTemplateConfigMaps(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	for _, tmpl := range getUniqueAlertTemplates(orderIndexes(indexes, cr.AlertmanagerRouteMergeStrategy())) {
+		name := strings.TrimSuffix(tmpl.Name, path.Ext(tmpl.Name))
+		if _, ok := files[name]; ok {
+			continue
+		}
+		data, err := r.fetchResource(tmpl.Url, tmpl.Tag, tmpl.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+
+	s.InvalidAlertTemplates = nil
+	result := map[string][]byte{}
+	for name, data := range files {
+		_, err := template.New(name).Funcs(alertTemplateFuncs).Option("missingkey=zero").Parse(string(data))
+		if err != nil {
+			s.InvalidAlertTemplates = append(s.InvalidAlertTemplates, v1.InvalidAlertTemplate{Name: name, Reason: err.Error()})
+			continue
+		}
+		result[invalidTemplateNameChars.ReplaceAllString(name, "-")+".tmpl"] = data
+	}
+	sort.Slice(s.InvalidAlertTemplates, func(i, j int) bool {
+		return s.InvalidAlertTemplates[i].Name < s.InvalidAlertTemplates[j].Name
+	})
+	return result, nil
+}
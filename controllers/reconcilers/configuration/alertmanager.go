@@ -1,19 +1,32 @@
 package configuration
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
 	"github.com/ghodss/yaml"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	v12 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-func (r *Reconciler) reconcileAlertmanager(ctx context.Context, cr *v1.Observability) error {
+const (
+	AlertmanagerConfigKey   = "alertmanager.yaml"
+	ServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+func (r *Reconciler) reconcileAlertmanager(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
 	alertmanager := model.GetAlertmanagerCr(cr)
 	configSecretName := model.GetAlertmanagerSecretName(cr)
 	proxySecret := model.GetAlertmanagerProxySecret(cr)
@@ -25,37 +38,63 @@ func (r *Reconciler) reconcileAlertmanager(ctx context.Context, cr *v1.Observabi
 		Name:      route.Name,
 	}
 
-	err := r.client.Get(ctx, selector, route)
-	if err != nil && !errors.IsNotFound(err) {
+	accessType, err := utils.GetUIAccessType(ctx, r.client, cr)
+	if err != nil {
 		return err
 	}
 
 	host := ""
-	if utils.IsRouteReady(route) {
-		host = route.Spec.Host
+	if accessType == v1.UIAccessIngress {
+		host = model.GetAlertmanagerHost(cr)
+	} else {
+		err = r.client.Get(ctx, selector, route)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+		if utils.IsRouteReady(route) {
+			host = route.Spec.Host
+		}
+	}
+
+	tlsSecret, openshift, err := utils.GetUIProxyTLSSecret(ctx, r.client, cr, model.InternalTLSAlertmanager, "alertmanager-k8s-tls")
+	if err != nil {
+		return err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, alertmanager, func() error {
+	err = utils.Apply(ctx, r.client, alertmanager, func() error {
+		existing := alertmanager.Spec.DeepCopy()
 		alertmanager.Spec.ConfigSecret = configSecretName
 		alertmanager.Spec.ListenLocal = true
-		alertmanager.Spec.ExternalURL = fmt.Sprintf("https://%v", host)
+		alertmanager.Spec.ExternalURL = model.GetAlertmanagerExternalURL(cr, host)
+		// Peers in other clusters deduplicate notifications with this Alertmanager
+		alertmanager.Spec.AdditionalPeers = model.GetAlertmanagerPeers(cr)
+		alertmanager.Spec.ClusterAdvertiseAddress = model.GetAlertmanagerClusterAdvertiseAddress(cr)
+		alertmanager.Spec.ForceEnableClusterMode = len(alertmanager.Spec.AdditionalPeers) > 0
 		alertmanager.Spec.ServiceAccountName = sa.Name
-		alertmanager.Spec.Secrets = []string{
-			proxySecret.Name,
-			"alertmanager-k8s-tls",
+		alertmanager.Spec.Secrets = []string{proxySecret.Name}
+		// Tokens of the receivers of the alerts, for the http config of their webhooks
+		if cr.AlertForwarderEnabled() {
+			alertmanager.Spec.Secrets = append(alertmanager.Spec.Secrets, model.GetAlertReceiverTokenSecret(cr, model.AlertForwarderName).Name)
+		}
+		if cr.AlertTicketingEnabled() {
+			alertmanager.Spec.Secrets = append(alertmanager.Spec.Secrets, model.GetAlertReceiverTokenSecret(cr, model.AlertTicketingName).Name)
+		}
+		if cr.AlertHistoryEnabled() {
+			alertmanager.Spec.Secrets = append(alertmanager.Spec.Secrets, model.GetAlertReceiverTokenSecret(cr, model.AlertHistoryName).Name)
 		}
 		alertmanager.Spec.PriorityClassName = model.ObservabilityPriorityClassName
-		alertmanager.Spec.Containers = []v12.Container{
-			{
+		if openshift {
+			alertmanager.Spec.Secrets = append(alertmanager.Spec.Secrets, tlsSecret)
+			alertmanager.Spec.Containers = []v12.Container{{
 				Name:  "oauth-proxy",
-				Image: "quay.io/openshift/origin-oauth-proxy:4.8",
+				Image: model.GetImage(cr, v1.ImageOAuthProxy, model.OAuthProxyImage),
 				Args: []string{
 					"-provider=openshift",
 					"-https-address=:9091",
 					"-http-address=",
 					"-email-domain=*",
 					"-upstream=http://localhost:9093",
-					"-openshift-sar={\"resource\": \"namespaces\", \"verb\": \"get\"}",
+					fmt.Sprintf("-openshift-sar=%v", model.GetOAuthProxySAR(cr, model.GetAlertmanagerService(cr).Name)),
 					"-openshift-delegate-urls={\"/\": {\"resource\": \"namespaces\", \"verb\": \"get\"}}",
 					"-tls-cert=/etc/tls/private/tls.crt",
 					"-tls-key=/etc/tls/private/tls.key",
@@ -85,7 +124,7 @@ func (r *Reconciler) reconcileAlertmanager(ctx context.Context, cr *v1.Observabi
 				},
 				VolumeMounts: []v12.VolumeMount{
 					{
-						Name:      "secret-alertmanager-k8s-tls",
+						Name:      fmt.Sprintf("secret-%v", tlsSecret),
 						MountPath: "/etc/tls/private",
 					},
 					{
@@ -93,11 +132,45 @@ func (r *Reconciler) reconcileAlertmanager(ctx context.Context, cr *v1.Observabi
 						MountPath: "/etc/proxy/secrets",
 					},
 				},
-			},
+			}}
+		} else {
+			// The internal TLS secret is added below
+			alertmanager.Spec.Containers = []v12.Container{
+				model.GetOIDCProxyContainer(cr, "oauth-proxy", "proxy", "http://localhost:9093", tlsSecret, proxySecret.Name),
+			}
+		}
+		// Merged into the alertmanager container by the Prometheus operator
+		if model.IsIPv6Primary(cr) {
+			alertmanager.Spec.Containers = append(alertmanager.Spec.Containers, v12.Container{
+				Name: "alertmanager",
+				Env: []v12.EnvVar{
+					{
+						Name: model.PodIPEnv,
+						ValueFrom: &v12.EnvVarSource{
+							FieldRef: &v12.ObjectFieldSelector{
+								FieldPath: "status.podIP",
+							},
+						},
+					},
+				},
+			})
+		}
+		alertmanager.Spec.ConfigMaps = nil
+		if model.IsInternalTLSEnabled(cr) {
+			alertmanager.Spec.Secrets = append(alertmanager.Spec.Secrets, model.GetInternalTLSSecretName(model.InternalTLSAlertmanager))
+			alertmanager.Spec.ConfigMaps = []string{model.GetInternalTLSProxyConfigMap(cr).Name}
+			alertmanager.Spec.Containers = append(alertmanager.Spec.Containers, getInternalTLSProxy(cr, model.InternalTLSAlertmanager, 9093))
 		}
 		alertmanager.Spec.Version = model.GetAlertmanagerVersion(cr)
+		if image, ok := model.GetImageOverride(cr, v1.ImageAlertmanager); ok {
+			alertmanager.Spec.Image = &image
+		} else {
+			alertmanager.Spec.Image = nil
+		}
 		alertmanager.Spec.Resources = model.GetAlertmanagerResourceRequirement(cr)
-		return nil
+		return deferRollout(cr, s, alertmanager, alertmanager.Spec, func() {
+			alertmanager.Spec = *existing
+		})
 	})
 	if err != nil {
 		return err
@@ -107,7 +180,7 @@ func (r *Reconciler) reconcileAlertmanager(ctx context.Context, cr *v1.Observabi
 
 }
 
-func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, indexes []v1.RepositoryIndex) error {
 	root := &v1.AlertmanagerConfigRoute{
 		Receiver: "default-receiver",
 		Routes:   []v1.AlertmanagerConfigRoute{},
@@ -125,10 +198,98 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 		},
 	}
 
-	for _, index := range indexes {
-		if index.Config == nil || index.Config.Alertmanager == nil {
+	// The test alert of the stack verification stops at a receiver without notifications
+	if cr.StackVerificationEnabled() {
+		config.Receivers = append(config.Receivers, v1.AlertmanagerConfigReceiver{
+			Name: model.StackVerificationReceiver,
+		})
+		root.Routes = append(root.Routes, v1.AlertmanagerConfigRoute{
+			Receiver: model.StackVerificationReceiver,
+			Match: map[string]string{
+				"alertname": model.StackVerificationAlert,
+			},
+		})
+	}
+
+	// Forwarded alerts continue to the other routes, so the forwarder routes come first
+	if cr.AlertForwarderEnabled() {
+		for _, destination := range cr.Spec.Alerting.Forwarder.Destinations {
+			receiver := model.GetAlertForwarderReceiverName(destination.Name)
+			config.Receivers = append(config.Receivers, v1.AlertmanagerConfigReceiver{
+				Name: receiver,
+				WebhookConfigs: []v1.WebhookConfig{
+					{
+						Url:        model.GetAlertForwarderUrl(cr, destination.Name),
+						HttpConfig: model.GetAlertReceiverHttpConfig(cr, model.AlertForwarderName),
+					},
+				},
+			})
+
+			root.Routes = append(root.Routes, v1.AlertmanagerConfigRoute{
+				Receiver: receiver,
+				Match:    destination.Match,
+				Continue: true,
+			})
+		}
+	}
+
+	// Ticketed alerts continue to the other routes as well
+	if cr.AlertTicketingEnabled() {
+		config.Receivers = append(config.Receivers, v1.AlertmanagerConfigReceiver{
+			Name: model.AlertTicketingReceiver,
+			WebhookConfigs: []v1.WebhookConfig{
+				{
+					Url:        model.GetAlertTicketingUrl(cr),
+					HttpConfig: model.GetAlertReceiverHttpConfig(cr, model.AlertTicketingName),
+				},
+			},
+		})
+
+		root.Routes = append(root.Routes, v1.AlertmanagerConfigRoute{
+			Receiver: model.AlertTicketingReceiver,
+			Match:    cr.Spec.Alerting.Ticketing.Match,
+			Continue: true,
+		})
+	}
+
+	// The history records every alert and the alerts continue to the other routes
+	if cr.AlertHistoryEnabled() {
+		config.Receivers = append(config.Receivers, v1.AlertmanagerConfigReceiver{
+			Name: model.AlertHistoryReceiver,
+			WebhookConfigs: []v1.WebhookConfig{
+				{
+					Url:        model.GetAlertHistoryUrl(cr),
+					HttpConfig: model.GetAlertReceiverHttpConfig(cr, model.AlertHistoryName),
+				},
+			},
+		})
+
+		root.Routes = append(root.Routes, v1.AlertmanagerConfigRoute{
+			Receiver: model.AlertHistoryReceiver,
+			Continue: true,
+		})
+	}
+
+	// Alerts duplicating platform alerts stop before the routes of the indexes page for them
+	if cr.Spec.RuleDeduplication != nil && cr.Spec.RuleDeduplication.Strategy == v1.RuleDeduplicationRelabel {
+		config.Receivers = append(config.Receivers, v1.AlertmanagerConfigReceiver{
+			Name: DuplicateRulesReceiver,
+		})
+		root.Routes = append(root.Routes, v1.AlertmanagerConfigRoute{
+			Receiver: DuplicateRulesReceiver,
+			MatchRe: map[string]string{
+				v1.DuplicateOfLabel: ".+",
+			},
+		})
+	}
+
+	// The receivers are named after the id of the index, only one source provides the routes of an id
+	routed := map[string]bool{}
+	for _, index := range orderIndexes(indexes, cr.AlertmanagerRouteMergeStrategy()) {
+		if index.Config == nil || index.Config.Alertmanager == nil || routed[index.Id] {
 			continue
 		}
+		routed[index.Id] = true
 
 		if !cr.PagerDutyDisabled() {
 			pagerDutySecret, err := r.getPagerDutySecret(ctx, cr, index.Config.Alertmanager)
@@ -186,6 +347,27 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 		}
 	}
 
+	if cr.Spec.Alerting != nil {
+		for _, rule := range cr.Spec.Alerting.InhibitRules {
+			config.InhibitRules = append(config.InhibitRules, v1.AlertmanagerConfigInhibitRule{
+				SourceMatch:   rule.SourceMatch,
+				SourceMatchRe: rule.SourceMatchRegex,
+				TargetMatch:   rule.TargetMatch,
+				TargetMatchRe: rule.TargetMatchRegex,
+				Equal:         rule.Equal,
+			})
+		}
+	}
+
+	// Notification templates are keys of the config secret next to the config
+	templates, err := r.getAlertTemplates(ctx, cr, s, indexes)
+	if err != nil {
+		return err
+	}
+	if len(templates) > 0 {
+		config.Templates = []string{AlertmanagerTemplatesPath}
+	}
+
 	configBytes, err := yaml.Marshal(&config)
 	if err != nil {
 		return err
@@ -193,18 +375,16 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 
 	secret := model.GetAlertmanagerSecret(cr)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+	return r.applyConfigSecret(ctx, secret, func() error {
 		secret.Type = v12.SecretTypeOpaque
-		secret.StringData = map[string]string{
-			"alertmanager.yaml": string(configBytes),
+		secret.Data = map[string][]byte{
+			AlertmanagerConfigKey: configBytes,
+		}
+		for name, data := range templates {
+			secret.Data[name] = data
 		}
 		return nil
 	})
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func (r *Reconciler) getPagerDutySecret(ctx context.Context, cr *v1.Observability, config *v1.AlertmanagerIndex) ([]byte, error) {
@@ -274,3 +454,159 @@ func (r *Reconciler) getDeadMansSnitchUrl(ctx context.Context, cr *v1.Observabil
 
 	return url, nil
 }
+
+// Ask Alertmanager to reload its configuration and check that the config it reports as loaded
+// contains the receivers of the config secret. The result is surfaced as a condition on the CR.
+// Verification is skipped once the current config secret has been verified successfully.
+func (r *Reconciler) verifyAlertmanagerConfig(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	secret := &v12.Secret{}
+	selector := client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      model.GetAlertmanagerSecretName(cr),
+	}
+
+	err := r.client.Get(ctx, selector, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// No config written yet, nothing to verify
+			return nil
+		}
+		return err
+	}
+
+	configBytes := secret.Data[AlertmanagerConfigKey]
+	hash := fmt.Sprintf("%x", sha256.Sum256(configBytes))
+	if s.AlertmanagerConfigHash == hash && meta.IsStatusConditionTrue(s.Conditions, v1.AlertmanagerConfigLoaded) {
+		return nil
+	}
+
+	// The operator authenticates against the oauth-proxy in front of Alertmanager with its
+	// service account token. When running outside of the cluster there is no token available.
+	token, err := ioutil.ReadFile(ServiceAccountTokenPath)
+	if err != nil {
+		r.logger.Info("skipping alertmanager config verification, no service account token found")
+		return nil
+	}
+
+	service := model.GetAlertmanagerService(cr)
+	baseUrl := fmt.Sprintf("https://%v.%v.svc:9091", service.Name, cr.Namespace)
+
+	setCondition := func(status metav1.ConditionStatus, reason string, message string) {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.AlertmanagerConfigLoaded,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+
+	// Alertmanager responds with an error when the config currently mounted can't be loaded
+	body, code, err := r.alertmanagerRequest(http.MethodPost, fmt.Sprintf("%v/-/reload", baseUrl), string(token), nil)
+	if err != nil {
+		setCondition(metav1.ConditionUnknown, "AlertmanagerUnavailable", err.Error())
+		return nil
+	}
+
+	if code != http.StatusOK {
+		setCondition(metav1.ConditionFalse, "ReloadFailed", strings.TrimSpace(string(body)))
+		r.logger.Info("alertmanager failed to reload config", "error", strings.TrimSpace(string(body)))
+		return nil
+	}
+
+	body, code, err = r.alertmanagerRequest(http.MethodGet, fmt.Sprintf("%v/api/v2/status", baseUrl), string(token), nil)
+	if err != nil {
+		setCondition(metav1.ConditionUnknown, "AlertmanagerUnavailable", err.Error())
+		return nil
+	}
+
+	if code != http.StatusOK {
+		setCondition(metav1.ConditionUnknown, "AlertmanagerUnavailable", fmt.Sprintf("unexpected status code from alertmanager: %v", code))
+		return nil
+	}
+
+	loaded, err := getLoadedAlertmanagerConfig(body)
+	if err != nil {
+		setCondition(metav1.ConditionUnknown, "InvalidStatusResponse", err.Error())
+		return nil
+	}
+
+	expected := v1.AlertmanagerConfigRoot{}
+	err = yaml.Unmarshal(configBytes, &expected)
+	if err != nil {
+		setCondition(metav1.ConditionFalse, "InvalidConfig", err.Error())
+		return nil
+	}
+
+	// Secrets are masked in the config reported by Alertmanager, so only the receivers are compared
+	for _, receiver := range expected.Receivers {
+		if !hasAlertmanagerReceiver(loaded, receiver.Name) {
+			setCondition(metav1.ConditionFalse, "ConfigPending", fmt.Sprintf("receiver %v not loaded yet", receiver.Name))
+			return nil
+		}
+	}
+
+	setCondition(metav1.ConditionTrue, "ConfigLoaded", "alertmanager config loaded successfully")
+	s.AlertmanagerConfigHash = hash
+	return nil
+}
+
+func (r *Reconciler) alertmanagerRequest(method string, url string, token string, payload []byte) ([]byte, int, error) {
+	return AlertmanagerRequest(r.httpClient, method, url, token, payload)
+}
+
+// AlertmanagerRequest sends a request authenticated with the token to the Alertmanager API and
+// returns the body and status code of the response
+func AlertmanagerRequest(httpClient *http.Client, method string, url string, token string, payload []byte) ([]byte, int, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return body, resp.StatusCode, nil
+}
+
+// Extract the loaded config from the response of the Alertmanager /api/v2/status endpoint
+func getLoadedAlertmanagerConfig(statusResponse []byte) (*v1.AlertmanagerConfigRoot, error) {
+	status := struct {
+		Config struct {
+			Original string `json:"original"`
+		} `json:"config"`
+	}{}
+
+	err := json.Unmarshal(statusResponse, &status)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &v1.AlertmanagerConfigRoot{}
+	err = yaml.Unmarshal([]byte(status.Config.Original), config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func hasAlertmanagerReceiver(config *v1.AlertmanagerConfigRoot, name string) bool {
+	for _, receiver := range config.Receivers {
+		if receiver.Name == name {
+			return true
+		}
+	}
+	return false
+}
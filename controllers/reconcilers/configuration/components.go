@@ -0,0 +1,19 @@
+package configuration
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Remove the CR of a component that is no longer managed by the operator. The CRD may
+// not exist when the component is disabled.
+func (r *Reconciler) deleteUnmanagedComponent(ctx context.Context, object runtime.Object) error {
+	err := r.client.Delete(ctx, object)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	ApplyKindDashboards  = "GrafanaDashboard"
+	ApplyKindRules       = "PrometheusRule"
+	ApplyKindPodMonitors = "PodMonitor"
+)
+
+// Dashboards, rules or pod monitors rendered during a sync. Each kind is applied in batches right
+// after it was rendered, in the order the resources were staged
+type pendingApplies struct {
+	objects []runtime.Object
+	hash    []byte
+	// The applies of all kinds of a sync stop at the same deadline, set by the first of them
+	deadline time.Time
+}
+
+func newPendingApplies() *pendingApplies {
+	return &pendingApplies{}
+}
+
+// Renders a resource with mutate. The rendered resources make up the revision of the apply
+func (r *Reconciler) stageApply(obj runtime.Object, mutate controllerutil.MutateFn) error {
+	err := mutate()
+	if err != nil {
+		return err
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	hash.Write(r.applies.hash)
+	hash.Write([]byte(fmt.Sprintf("%T/%s/%s", obj, accessor.GetNamespace(), accessor.GetName())))
+	hash.Write(bytes)
+	r.applies.hash = hash.Sum(nil)
+	r.applies.objects = append(r.applies.objects, obj)
+	return nil
+}
+
+// Applies the resources of a kind staged since the last flush in batches of concurrent requests, rate
+// limited over all of them. The applies stop at the deadline of the sync and the next sync continues with the resources
+// that were not applied yet, as long as they didn't change. Returns true if all resources are applied
+func (r *Reconciler) flushApplies(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, kind string) (bool, error) {
+	objects := r.applies.objects
+	revision := fmt.Sprintf("%x", sha256.Sum256(r.applies.hash))[:12]
+	deadline := r.applies.deadline
+	if deadline.IsZero() {
+		deadline = time.Now().Add(model.GetConfigApplyMaxDuration(cr))
+	}
+	r.applies = &pendingApplies{deadline: deadline}
+
+	start := 0
+	if last := cr.Status.GetConfigApplyProgress(kind); last != nil && last.Revision == revision && last.Applied < last.Total {
+		start = last.Applied
+	}
+	s.ConfigApplyProgress = append(s.ConfigApplyProgress, v1.ConfigApplyProgress{
+		Kind:     kind,
+		Revision: revision,
+		Applied:  start,
+		Total:    len(objects),
+	})
+	progress := &s.ConfigApplyProgress[len(s.ConfigApplyProgress)-1]
+
+	batchSize := model.GetConfigApplyBatchSize(cr)
+	concurrency := model.GetConfigApplyConcurrency(cr)
+	limiter := flowcontrol.NewTokenBucketRateLimiter(float32(model.GetConfigApplyRequestsPerSecond(cr)), concurrency)
+	defer limiter.Stop()
+
+	for progress.Applied < len(objects) {
+		if time.Now().After(deadline) {
+			r.logger.Info("config apply deadline reached", "kind", kind, "applied", progress.Applied, "total", progress.Total)
+			return false, nil
+		}
+
+		end := progress.Applied + batchSize
+		if end > len(objects) {
+			end = len(objects)
+		}
+		err := r.applyBatch(ctx, objects[progress.Applied:end], concurrency, limiter)
+		if err != nil {
+			return false, err
+		}
+		progress.Applied = end
+	}
+	return true, nil
+}
+
+// Applies the resources of a batch with at most concurrency requests in flight. Returns the first error,
+// the batch is applied again by the next sync
+func (r *Reconciler) applyBatch(ctx context.Context, objects []runtime.Object, concurrency int, limiter flowcontrol.RateLimiter) error {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var result error
+	queue := make(chan runtime.Object)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range queue {
+				err := limiter.Wait(ctx)
+				if err == nil {
+					err = utils.Apply(ctx, r.client, obj, func() error {
+						return nil
+					})
+				}
+				if err != nil {
+					once.Do(func() {
+						result = err
+					})
+				}
+			}
+		}()
+	}
+
+	for _, obj := range objects {
+		queue <- obj
+	}
+	close(queue)
+	wg.Wait()
+	return result
+}
@@ -0,0 +1,142 @@
+package configuration
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+// Unchanged configuration is still applied at this interval, to revert out of band changes of the
+// resources created from it
+const ConfigReapplyPeriod = time.Hour
+
+// Validators of a file of a configuration repository, sent with the next request for it
+type fileValidator struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	// The files of all revisions share their url
+	Tag string `json:"tag,omitempty"`
+}
+
+// Fetches a file of a configuration repository. The request is conditional on the validators of the
+// file in the previous snapshot, unchanged files are served from the snapshot instead of being downloaded
+// again. Files are only requested once per sync. Returns the file before the config values are rendered.
+func (r *Reconciler) fetchRepositoryFile(path string, tag string, token string) ([]byte, error) {
+	if data, ok, err := r.snapshot.get(path); ok {
+		return data, err
+	}
+	if data, ok := r.snapshot.Resources[path]; ok {
+		return data, nil
+	}
+
+	fileUrl, err := url.ParseRequestURI(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == "" {
+		return nil, fmt.Errorf("repository ConfigMap missing required AccessToken")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fileUrl.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3.raw")
+
+	if tag != "" {
+		q := req.URL.Query()
+		q.Add("ref", tag)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	cached, validator, ok := r.snapshot.previousFile(path, tag)
+	if ok {
+		// If-None-Match takes precedence, the modification time is only used by servers without ETags
+		if validator.ETag != "" {
+			req.Header.Set("If-None-Match", validator.ETag)
+		} else if validator.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validator.LastModified)
+		}
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		r.snapshot.recordFile(path, cached, validator)
+		return cached, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code when reading %v: %v", req.URL.String(), resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.snapshot.recordFile(path, body, fileValidator{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Tag:          tag,
+	})
+	return body, nil
+}
+
+// Returns true if the files of the previous snapshot are unchanged in the repositories and no file was
+// added or removed since. The files are fetched again, conditionally, and kept for the sync.
+func (r *Reconciler) configUnchanged(repos map[string]v1.RepositoryInfo) (bool, error) {
+	previous := r.snapshot.previous
+	if previous == nil {
+		return false, nil
+	}
+
+	for path, data := range previous.Resources {
+		var repo *v1.RepositoryInfo
+		for _, info := range repos {
+			if strings.HasPrefix(path, fmt.Sprintf("%s/%s/", info.Repository, info.Channel)) {
+				repo = &info
+				break
+			}
+		}
+		// The repository was removed
+		if repo == nil {
+			return false, nil
+		}
+
+		fetched, err := r.fetchRepositoryFile(path, repo.Tag, repo.AccessToken)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(fetched, data) {
+			return false, nil
+		}
+	}
+
+	// A new index also references new files
+	return len(r.snapshot.Resources) == len(previous.Resources), nil
+}
+
+// Hash of the spec of the CR, the resources created from the configuration depend on it as well
+func getConfigSpecHash(cr *v1.Observability) (string, error) {
+	spec, err := json.Marshal(cr.Spec)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(spec)
+	return fmt.Sprintf("%x", hash)[:12], nil
+}
@@ -0,0 +1,115 @@
+package configuration
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Kinds of resources in the config conflicts
+const (
+	ConflictKindRule              = "Rule"
+	ConflictKindAlertmanagerRoute = "AlertmanagerRoute"
+)
+
+// Returns the indexes in the order in which their sources win conflicts. The indexes are sorted
+// by priority, so with LastWins the source with the lowest priority comes first
+func orderIndexes(indexes []v1.RepositoryIndex, strategy v1.MergeStrategy) []v1.RepositoryIndex {
+	if strategy != v1.MergeLastWins {
+		return indexes
+	}
+	result := make([]v1.RepositoryIndex, len(indexes))
+	for i, index := range indexes {
+		result[len(indexes)-1-i] = index
+	}
+	return result
+}
+
+// Returns the resources of a kind that are defined by more than one source, in the order of the
+// first definition
+func getConflicts(kind string, indexes []v1.RepositoryIndex, strategy v1.MergeStrategy, names func(index *v1.RepositoryIndex) []string) []v1.ConfigConflict {
+	var result []v1.ConfigConflict
+	conflicts := map[string]*v1.ConfigConflict{}
+	var order []string
+	for _, index := range orderIndexes(indexes, strategy) {
+		source := getIndexSource(&index)
+		for _, name := range names(&index) {
+			conflict, ok := conflicts[name]
+			if !ok {
+				conflicts[name] = &v1.ConfigConflict{
+					Kind:    kind,
+					Name:    name,
+					Sources: []string{source},
+				}
+				order = append(order, name)
+				continue
+			}
+			conflict.Sources = append(conflict.Sources, source)
+		}
+	}
+
+	for _, name := range order {
+		conflict := conflicts[name]
+		if len(conflict.Sources) < 2 {
+			continue
+		}
+		if strategy != v1.MergeErrorOnConflict {
+			conflict.Applied = conflict.Sources[0]
+		}
+		result = append(result, *conflict)
+	}
+	return result
+}
+
+func getRuleNames(index *v1.RepositoryIndex) []string {
+	if index.Config == nil || index.Config.Prometheus == nil {
+		return nil
+	}
+	var result []string
+	for _, rule := range index.Config.Prometheus.Rules {
+		result = append(result, getNameFromUrl(rule))
+	}
+	return result
+}
+
+// The receivers of the Alertmanager routes of an index are named after its id
+func getRouteNames(index *v1.RepositoryIndex) []string {
+	if index.Config == nil || index.Config.Alertmanager == nil {
+		return nil
+	}
+	return []string{index.Id}
+}
+
+// Updates the rules and Alertmanager routes defined by more than one source and fails if a kind
+// of resource merged with ErrorOnConflict has conflicts. Conflicts of dashboard names are checked as
+// well, those of their uids and titles are only known once the dashboards are fetched
+func (r *Reconciler) checkConfigConflicts(cr *v1.Observability, s *v1.ObservabilityStatus, indexes []v1.RepositoryIndex) error {
+	rules := getConflicts(ConflictKindRule, indexes, cr.RuleMergeStrategy(), getRuleNames)
+	routes := getConflicts(ConflictKindAlertmanagerRoute, indexes, cr.AlertmanagerRouteMergeStrategy(), getRouteNames)
+	s.ConfigConflicts = append(rules, routes...)
+
+	var err error
+	for _, conflict := range s.ConfigConflicts {
+		if conflict.Applied == "" {
+			err = fmt.Errorf("%v %v is defined by the configuration sources %v", conflict.Kind, conflict.Name, conflict.Sources)
+			break
+		}
+	}
+	if err == nil && cr.DashboardMergeStrategy() == v1.MergeErrorOnConflict {
+		err = getDashboardConflictError(getDashboardNameConflicts(indexes))
+	}
+	if err != nil {
+		r.recorder.Eventf(cr, corev1.EventTypeWarning, v1.EventConfigConflict, "configuration not applied: %v", err)
+	}
+	return err
+}
+
+func getDashboardConflictError(conflicts []v1.DashboardConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	conflict := conflicts[0]
+	return fmt.Errorf("dashboard %v of configuration source %v has the %v of dashboard %v of configuration source %v",
+		conflict.Name, conflict.Source, conflict.Field, conflict.Winner, conflict.WinnerSource)
+}
@@ -0,0 +1,138 @@
+package configuration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/api/core/v1"
+)
+
+const (
+	// File in the channel directory of a repository with the revision released to each ring.
+	// Always read from the default branch
+	RolloutFile = "rollout.json"
+	// Annotation on a configuration secret to pin the revision of a ring, overrides the rollout file
+	RolloutRevisionAnnotationPrefix = "observability-operator/revision-"
+)
+
+type rolloutIndex struct {
+	Rings map[string]string `json:"rings"`
+}
+
+// Returns nil if the repository has no rollout file
+func (r *Reconciler) readRolloutFile(repo *v1.RepositoryInfo) (*rolloutIndex, error) {
+	rolloutUrl, err := url.ParseRequestURI(fmt.Sprintf("%s/%s/%s", repo.Repository, repo.Channel, RolloutFile))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rolloutUrl.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", repo.AccessToken))
+	req.Header.Set("Accept", "application/vnd.github.v3.raw")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code when reading rollout file from %v: %v", req.URL.String(), resp.StatusCode)
+	}
+
+	bytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	rollout := &rolloutIndex{}
+	err = json.Unmarshal(bytes, rollout)
+	if err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// Set the revision of a repository to the one released to the ring of the cluster. A revision
+// that is not released to the ring yet is not picked up, the repository stays at the revision
+// applied by the last sync.
+func (r *Reconciler) resolveRolloutRevision(cr *v1.Observability, s *v1.ObservabilityStatus, configSecret *v12.Secret, repo *v1.RepositoryInfo) error {
+	// Rollbacks apply the snapshot regardless of the ring
+	if cr.Spec.ConfigRollout == nil || r.snapshot.replay {
+		return nil
+	}
+
+	ring := cr.Spec.ConfigRollout.Ring
+	revision := configSecret.Annotations[RolloutRevisionAnnotationPrefix+ring]
+	if revision == "" {
+		rollout, err := r.readRolloutFile(repo)
+		if err != nil {
+			return err
+		}
+
+		// Repositories without a rollout file are always synced at the configured tag
+		if rollout == nil {
+			return nil
+		}
+		revision = rollout.Rings[ring]
+	}
+
+	if revision == "" {
+		current := s.GetConfigRevision(configSecret.Name)
+		if current == nil {
+			return nil
+		}
+		revision = current.Revision
+	}
+
+	repo.Tag = revision
+	return nil
+}
+
+func getConfigRevisions(cr *v1.Observability, repos map[string]v1.RepositoryInfo, snapshot *configSnapshot) []v1.ConfigRevision {
+	var revisions []v1.ConfigRevision
+	for name, repo := range repos {
+		revision := v1.ConfigRevision{
+			Name:     name,
+			Revision: repo.Tag,
+			Hash:     snapshot.hash(fmt.Sprintf("%s/%s/", repo.Repository, repo.Channel)),
+		}
+		if cr.Spec.ConfigRollout != nil {
+			revision.Ring = cr.Spec.ConfigRollout.Ring
+		}
+		revisions = append(revisions, revision)
+	}
+
+	// Keep the status stable between reconciles
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Name < revisions[j].Name
+	})
+	return revisions
+}
+
+// Repositories with their tag or branch, e.g. for events
+func getConfigRevisionsSummary(revisions []v1.ConfigRevision) string {
+	var result []string
+	for _, revision := range revisions {
+		if revision.Revision != "" {
+			result = append(result, fmt.Sprintf("%v@%v", revision.Name, revision.Revision))
+		} else {
+			result = append(result, revision.Name)
+		}
+	}
+	return strings.Join(result, ", ")
+}
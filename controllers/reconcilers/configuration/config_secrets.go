@@ -0,0 +1,22 @@
+package configuration
+
+import (
+	"context"
+
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Writes a config secret of Prometheus or Alertmanager where it is rendered, but only if its content
+// changed, so that an unchanged config does not cause a reload
+func (r *Reconciler) applyConfigSecret(ctx context.Context, secret *v12.Secret, mutate controllerutil.MutateFn) error {
+	written, err := utils.ApplySecret(ctx, r.client, secret, mutate)
+	if err != nil {
+		return err
+	}
+	if written {
+		r.logger.Info("config secret changed", "secret", secret.Name)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package configuration
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Annotation on the CR to re-apply a previous config snapshot instead of the repositories
+	ConfigRollbackAnnotation = "observability.redhat.com/rollback-to"
+	ConfigSnapshotPrefix     = "observability-config-snapshot-"
+	ConfigSnapshotKey        = "snapshot.json.gz"
+	// Number of config snapshots kept for rollbacks
+	ConfigSnapshotHistory = 5
+)
+
+// configSnapshot holds everything fetched from the configuration repositories during a sync. When
+// replaying, the repositories are not contacted and the resources are served from the snapshot.
+type configSnapshot struct {
+	Resources map[string][]byte   `json:"resources"`
+	Revisions []v1.ConfigRevision `json:"revisions,omitempty"`
+	// Validators of the resources for conditional requests in the next sync
+	Validators map[string]fileValidator `json:"validators,omitempty"`
+	replay     bool
+	// Snapshot of the last sync, unchanged resources are served from it
+	previous *configSnapshot
+}
+
+func newConfigSnapshot() *configSnapshot {
+	return &configSnapshot{
+		Resources:  map[string][]byte{},
+		Validators: map[string]fileValidator{},
+	}
+}
+
+// Returns true if the resource has to be served from the snapshot
+func (in *configSnapshot) get(key string) ([]byte, bool, error) {
+	if in == nil || !in.replay {
+		return nil, false, nil
+	}
+	data, ok := in.Resources[key]
+	if !ok {
+		return nil, true, fmt.Errorf("%v is not part of the config snapshot", key)
+	}
+	return data, true, nil
+}
+
+func (in *configSnapshot) recordFile(key string, data []byte, validator fileValidator) {
+	if in == nil || in.replay {
+		return
+	}
+	in.Resources[key] = data
+	in.Validators[key] = validator
+}
+
+// Returns the resource of the previous snapshot and its validators, if it was fetched at the same tag
+func (in *configSnapshot) previousFile(key string, tag string) ([]byte, fileValidator, bool) {
+	if in == nil || in.previous == nil {
+		return nil, fileValidator{}, false
+	}
+	data, ok := in.previous.Resources[key]
+	validator, hasValidator := in.previous.Validators[key]
+	if !ok || !hasValidator || validator.Tag != tag {
+		return nil, fileValidator{}, false
+	}
+	return data, validator, true
+}
+
+// Content hash of the resources with the given url prefix, or of all resources
+func (in *configSnapshot) hash(prefix string) string {
+	var keys []string
+	for key := range in.Resources {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write(in.Resources[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))[:12]
+}
+
+func GetConfigSnapshotSelector() labels.Selector {
+	return labels.SelectorFromSet(map[string]string{
+		"managed-by": "observability-operator",
+		"purpose":    "config-snapshot",
+	})
+}
+
+func (r *Reconciler) loadConfigSnapshot(ctx context.Context, cr *v1.Observability, id string) (*configSnapshot, error) {
+	secret := &v12.Secret{}
+	selector := client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      ConfigSnapshotPrefix + id,
+	}
+
+	err := r.client.Get(ctx, selector, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("config snapshot %v does not exist", id)
+		}
+		return nil, err
+	}
+
+	snapshot, err := decodeConfigSnapshot(secret)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.replay = true
+	return snapshot, nil
+}
+
+func decodeConfigSnapshot(secret *v12.Secret) (*configSnapshot, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(secret.Data[ConfigSnapshotKey]))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &configSnapshot{}
+	err = json.Unmarshal(data, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Returns the resources of a config snapshot secret keyed by their url, e.g. to compare snapshots
+func GetConfigSnapshotResources(secret *v12.Secret) (map[string][]byte, error) {
+	snapshot, err := decodeConfigSnapshot(secret)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Resources, nil
+}
+
+// Store the snapshot of the current sync and remove the oldest ones. Returns the id of the snapshot
+func (r *Reconciler) storeConfigSnapshot(ctx context.Context, cr *v1.Observability, snapshot *configSnapshot) (string, error) {
+	id := snapshot.hash("")
+	if id == "" {
+		return "", nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", err
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err = writer.Write(data)
+	if err != nil {
+		return "", err
+	}
+	err = writer.Close()
+	if err != nil {
+		return "", err
+	}
+
+	secret := &v12.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigSnapshotPrefix + id,
+			Namespace: cr.Namespace,
+		},
+	}
+	err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Labels = map[string]string{
+			"managed-by": "observability-operator",
+			"purpose":    "config-snapshot",
+		}
+		secret.Data = map[string][]byte{
+			ConfigSnapshotKey: compressed.Bytes(),
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return id, r.pruneConfigSnapshots(ctx, cr, id)
+}
+
+func (r *Reconciler) pruneConfigSnapshots(ctx context.Context, cr *v1.Observability, current string) error {
+	list := &v12.SecretList{}
+	opts := &client.ListOptions{
+		LabelSelector: GetConfigSnapshotSelector(),
+		Namespace:     cr.Namespace,
+	}
+	err := r.client.List(ctx, list, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(list.Items) <= ConfigSnapshotHistory {
+		return nil
+	}
+
+	// Newest first
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[j].CreationTimestamp.Before(&list.Items[i].CreationTimestamp)
+	})
+
+	for _, secret := range list.Items[ConfigSnapshotHistory:] {
+		if secret.Name == ConfigSnapshotPrefix+current {
+			continue
+		}
+		err = r.client.Delete(ctx, &secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package configuration
+
+import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Restricts the resources of the indexes to those the configuration sources sync. Kinds are filtered
+// on the index, selectors require the resources to be fetched. Dashboards, rules and pod monitors
+// removed here are deleted like any other unrequested resource
+func (r *Reconciler) filterConfigSources(cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	for i := range indexes {
+		index := &indexes[i]
+		source := cr.GetConfigurationSource(getIndexSource(index))
+		if source == nil || index.Config == nil {
+			continue
+		}
+
+		if index.Config.Grafana != nil && !source.SyncsKind(v1.ConfigKindDashboard) {
+			index.Config.Grafana.Dashboards = nil
+			index.Config.Grafana.Folders = nil
+		}
+
+		if index.Config.Loki != nil && !source.SyncsKind(v1.ConfigKindLokiRule) {
+			index.Config.Loki.Rules = nil
+		}
+
+		if index.Config.Alertmanager != nil && !source.SyncsKind(v1.ConfigKindAlertTemplate) {
+			index.Config.Alertmanager.Templates = nil
+		}
+
+		prometheus := index.Config.Prometheus
+		if prometheus == nil {
+			continue
+		}
+		if !source.SyncsKind(v1.ConfigKindPrometheusRule) {
+			prometheus.Rules = nil
+			prometheus.RuleTests = nil
+		}
+		if !source.SyncsKind(v1.ConfigKindPodMonitor) {
+			prometheus.PodMonitors = nil
+		}
+
+		if source.Selector == nil && source.RuleSelector == nil {
+			continue
+		}
+		selector, err := getSourceSelector(source.Selector)
+		if err != nil {
+			return err
+		}
+		ruleSelector, err := getSourceSelector(source.RuleSelector)
+		if err != nil {
+			return err
+		}
+
+		prometheus.Rules, err = r.selectResources(index, prometheus.Rules, func(data []byte) (bool, error) {
+			rule := &v12.PrometheusRule{}
+			err := yaml.Unmarshal(data, rule)
+			if err != nil {
+				return false, err
+			}
+			if !selector.Matches(labels.Set(rule.Labels)) {
+				return false, nil
+			}
+			selectRules(rule, ruleSelector)
+			return len(rule.Spec.Groups) > 0, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Tests of rule files that are no longer synced would fail
+		kept := map[string]bool{}
+		for _, rule := range prometheus.Rules {
+			kept[getNameFromUrl(rule)] = true
+		}
+		prometheus.RuleTests, err = r.selectResources(index, prometheus.RuleTests, func(data []byte) (bool, error) {
+			test := struct {
+				RuleFiles []string `json:"rule_files"`
+			}{}
+			// Invalid test files are reported by the test run
+			err := yaml.Unmarshal(data, &test)
+			if err != nil {
+				return true, nil
+			}
+			for _, ruleFile := range test.RuleFiles {
+				if !kept[getNameFromUrl(ruleFile)] {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		prometheus.PodMonitors, err = r.selectResources(index, prometheus.PodMonitors, func(data []byte) (bool, error) {
+			monitor := &v12.PodMonitor{}
+			err := yaml.Unmarshal(data, monitor)
+			if err != nil {
+				return false, err
+			}
+			return selector.Matches(labels.Set(monitor.Labels)), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the paths of the resources of the index that match
+func (r *Reconciler) selectResources(index *v1.RepositoryIndex, paths []string, matches func(data []byte) (bool, error)) ([]string, error) {
+	var result []string
+	for _, path := range paths {
+		data, err := r.fetchResource(fmt.Sprintf("%s/%s", index.BaseUrl, path), index.Tag, index.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := matches(data)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, path)
+		}
+	}
+	return result, nil
+}
+
+// A missing selector matches everything
+func getSourceSelector(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// Removes the rules that don't match the selector and the groups left without rules
+func selectRules(rule *v12.PrometheusRule, selector labels.Selector) {
+	if selector.Empty() {
+		return
+	}
+
+	var groups []v12.RuleGroup
+	for _, group := range rule.Spec.Groups {
+		var rules []v12.Rule
+		for _, r := range group.Rules {
+			if selector.Matches(labels.Set(r.Labels)) {
+				rules = append(rules, r)
+			}
+		}
+		if len(rules) > 0 {
+			group.Rules = rules
+			groups = append(groups, group)
+		}
+	}
+	rule.Spec.Groups = groups
+}
+
+// Applies the rule selector of the configuration source of a fetched rule
+func selectSourceRules(cr *v1.Observability, resource ResourceInfo, rule *v12.PrometheusRule) error {
+	source := cr.GetConfigurationSource(resource.Source)
+	if source == nil {
+		return nil
+	}
+	selector, err := getSourceSelector(source.RuleSelector)
+	if err != nil {
+		return err
+	}
+	selectRules(rule, selector)
+	return nil
+}
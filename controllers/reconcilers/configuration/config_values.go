@@ -0,0 +1,41 @@
+package configuration
+
+import (
+	"regexp"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+// Only placeholders with braces are substituted, $name and {{ }} are left to Grafana and the
+// Prometheus templates
+var configValuePlaceholderRegex = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+
+// Values substituted for the placeholders in the resources fetched from the configuration
+// repositories. The values of the operator take precedence over the values of the CR.
+func getConfigValues(cr *v1.Observability) map[string]string {
+	values := map[string]string{}
+	for name, value := range cr.Spec.ConfigValues {
+		values[name] = value
+	}
+
+	values[v1.ConfigValueReservedPrefix+"CLUSTER_ID"] = cr.Status.ClusterID
+	values[v1.ConfigValueReservedPrefix+"NAMESPACE"] = cr.Namespace
+	tenant := ""
+	if cr.HasObservatoriumTenant() {
+		tenant = cr.Spec.Observatorium.Tenant.Tenant
+	}
+	values[v1.ConfigValueReservedPrefix+"TENANT"] = tenant
+	return values
+}
+
+// Substitute the placeholders of known values, unknown placeholders are kept as they may be
+// Grafana variables
+func renderConfigValues(source []byte, values map[string]string) []byte {
+	return configValuePlaceholderRegex.ReplaceAllFunc(source, func(placeholder []byte) []byte {
+		name := configValuePlaceholderRegex.FindSubmatch(placeholder)[1]
+		if value, ok := values[string(name)]; ok {
+			return []byte(value)
+		}
+		return placeholder
+	})
+}
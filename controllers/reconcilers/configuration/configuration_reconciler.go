@@ -5,10 +5,11 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	v14 "k8s.io/api/networking/v1"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -16,11 +17,15 @@ import (
 	errors2 "github.com/pkg/errors"
 	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
 	token2 "github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	v13 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -29,26 +34,40 @@ const (
 	RemoteAccessToken           = "access_token"
 	RemoteChannel               = "channel"
 	RemoteTag                   = "tag"
+	RemotePriority              = "priority"
 	PrometheusRuleIdentifierKey = "observability"
 	DefaultChannel              = "resources"
+	// Annotation on the CR to sync before the resync period is over. A sync is forced whenever the
+	// value changes, e.g. to the current time
+	ResyncAnnotation = "observability.redhat.com/resync"
 )
 
 type Reconciler struct {
 	client     client.Client
 	logger     logr.Logger
+	recorder   record.EventRecorder
 	httpClient *http.Client
+	// Resources fetched from the configuration repositories in the current sync
+	snapshot *configSnapshot
+	// Values substituted for the placeholders in the fetched resources
+	values map[string]string
+	// Dashboards, rules or pod monitors rendered and not yet applied in the current sync
+	applies *pendingApplies
 }
 
-func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+func NewReconciler(client client.Client, logger logr.Logger, recorder record.EventRecorder) reconcilers.ObservabilityReconciler {
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
-	httpClient := &http.Client{Transport: tr}
+	// Health, recommendation and quota queries run in the sync, a hung connection must not block it
+	httpClient := &http.Client{Transport: tr, Timeout: 30 * time.Second}
 
 	return &Reconciler{
 		client:     client,
 		logger:     logger,
+		recorder:   recorder,
 		httpClient: httpClient,
+		applies:    newPendingApplies(),
 	}
 }
 
@@ -92,7 +111,7 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 
 		dashboardList := &v1alpha1.GrafanaDashboardList{}
 		err = r.client.List(ctx, dashboardList, opts)
-		if err != nil {
+		if err != nil && !meta.IsNoMatchError(err) {
 			return v1.ResultFailed, err
 		}
 
@@ -106,7 +125,7 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 
 		prometheusRuleList := &prometheusv1.PrometheusRuleList{}
 		err = r.client.List(ctx, prometheusRuleList, opts)
-		if err != nil {
+		if err != nil && !meta.IsNoMatchError(err) {
 			return v1.ResultFailed, err
 		}
 
@@ -120,7 +139,7 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 
 		podMonitorList := &prometheusv1.PodMonitorList{}
 		err = r.client.List(ctx, podMonitorList, opts)
-		if err != nil {
+		if err != nil && !meta.IsNoMatchError(err) {
 			return v1.ResultFailed, err
 		}
 
@@ -133,6 +152,11 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 		}
 	}
 
+	err = r.deleteUnrequestedRuleTests(ctx, cr, nil)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
 	// Delete Promtail daemonsets
 	daemonsetList := &v13.DaemonSetList{}
 	err = r.client.List(ctx, daemonsetList, opts)
@@ -165,6 +189,11 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 		if err != nil {
 			return v1.ResultFailed, err
 		}
+
+		err = r.deleteTokenRefresherExtras(ctx, cr, deployment.Name)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
 	}
 
 	// Delete the network policies
@@ -212,18 +241,112 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return v1.ResultSuccess, nil
 	}
 
+	// Check that Alertmanager picked up the current config. This runs independently of the
+	// resync window because reloads can happen at any time after the config secret was written.
+	var err error
+	if cr.AlertmanagerMode() == v1.ComponentManaged {
+		err = r.verifyAlertmanagerConfig(ctx, cr, s)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error verifying alertmanager config")
+		}
+	}
+
+	if cr.PrometheusMode() != v1.ComponentDisabled {
+		r.checkRemoteWriteHealth(ctx, cr, s)
+	}
+	r.updateResourceRecommendations(cr, s)
+
+	// Suspending or resuming a tenant changes the namespace selectors of Prometheus
+	suspensionChanged := false
+	if cr.PrometheusMode() == v1.ComponentManaged {
+		suspensionChanged = r.checkTenantQuotas(cr, s)
+	}
+
+	// Reports are delivered on their schedule, independently of the resync window
+	if len(cr.Spec.Reports) > 0 || len(s.Reports) > 0 {
+		r.reconcileReports(ctx, cr, s)
+	}
+
+	// Silences follow the windows of the mute time intervals, independently of the resync window
+	if cr.AlertmanagerMode() == v1.ComponentManaged && ((cr.Spec.Alerting != nil && len(cr.Spec.Alerting.MuteTimeIntervals) > 0) || len(s.MuteTimeIntervals) > 0) {
+		r.reconcileMuteTimeIntervals(ctx, cr, s)
+	}
+
+	// Events of the previous reconciles are annotated in Grafana, independently of the resync window
+	if len(s.PendingGrafanaAnnotations) > 0 {
+		if cr.GrafanaMode() == v1.ComponentManaged || cr.GrafanaExternal() {
+			r.writeGrafanaAnnotations(ctx, cr, s)
+		} else {
+			s.PendingGrafanaAnnotations = nil
+		}
+	}
+
+	// Certificates expire and are rotated independently of the resync window
+	expiryChanged := r.checkCredentialExpiry(ctx, cr, s)
+
 	// Force a sync if one of the tokens has expired
 	overrideLastSync := false
-	overrideLastSync, err := token2.TokensExpired(ctx, r.client, cr)
+	overrideLastSync, err = token2.TokensExpired(ctx, r.client, cr)
 	if err != nil {
 		return v1.ResultFailed, errors2.Wrap(err, "error checking observatorium token lifetimes")
 	}
 
+	// Force a sync if one of the referenced credential secrets was rotated
+	rotated, err := r.getRotatedSecrets(ctx, s)
+	if err != nil {
+		return v1.ResultFailed, errors2.Wrap(err, "error checking referenced secrets")
+	}
+	if len(rotated) > 0 {
+		log.Info("referenced secrets changed, forcing resync", "secrets", len(rotated))
+		overrideLastSync = true
+	}
+
 	// Always react to CR updates when external repo sync is disabled
 	if cr.ExternalSyncDisabled() {
 		overrideLastSync = true
 	}
 
+	if suspensionChanged {
+		log.Info("tenant suspensions changed, forcing resync", "suspended", model.GetSuspendedTenants(s))
+		overrideLastSync = true
+	}
+
+	if expiryChanged {
+		log.Info("credential expiries changed, forcing resync")
+		overrideLastSync = true
+	}
+
+	// Force a sync when a rollback is requested or lifted
+	rollbackTo := cr.Annotations[ConfigRollbackAnnotation]
+	if rollbackTo != s.ConfigRollback {
+		log.Info("config rollback changed, forcing resync", "rollback to", rollbackTo)
+		overrideLastSync = true
+	}
+
+	// Force a sync when a grafana restore is requested
+	if cr.BackupEnabled() && cr.Annotations[GrafanaRestoreAnnotation] != "" && cr.Annotations[GrafanaRestoreAnnotation] != s.GrafanaRestoredBackup {
+		log.Info("grafana restore requested, forcing resync", "backup", cr.Annotations[GrafanaRestoreAnnotation])
+		overrideLastSync = true
+	}
+
+	// Force a sync when an immediate rollout is requested or deferred rollouts are due
+	rollout := cr.Annotations[model.RolloutNowAnnotation]
+	if rollout != s.RolloutRequested {
+		log.Info("rollout requested, forcing resync", "request", rollout)
+		overrideLastSync = true
+	}
+	if len(s.DeferredRollouts) > 0 && model.RolloutAllowed(cr, time.Now()) {
+		log.Info("upgrade window open, forcing resync of deferred rollouts")
+		overrideLastSync = true
+	}
+
+	// Force a sync when one is requested
+	resync := cr.Annotations[ResyncAnnotation]
+	if resync != s.ResyncRequested {
+		log.Info("resync requested, forcing resync", "request", resync)
+		overrideLastSync = true
+	}
+
 	// Then check if the next sync is due
 	// Override if any of the tokens needs a refresh
 	if cr.Status.LastSynced != 0 && !overrideLastSync {
@@ -266,6 +389,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		"secret count", len(configSecretList.Items), "self contained", cr.ExternalSyncDisabled())
 	repos := make(map[string]v1.RepositoryInfo)
 
+	// Re-apply a previous snapshot instead of fetching from the repositories
+	if rollbackTo != "" {
+		r.snapshot, err = r.loadConfigSnapshot(ctx, cr, rollbackTo)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error loading config snapshot")
+		}
+	} else {
+		r.snapshot = newConfigSnapshot()
+		// The last snapshot provides the validators for conditional requests. Without it all files
+		// are downloaded
+		if s.ConfigSnapshot != "" && s.ConfigRollback == "" {
+			r.snapshot.previous, err = r.loadConfigSnapshot(ctx, cr, s.ConfigSnapshot)
+			if err != nil {
+				log.Error(err, "error loading previous config snapshot")
+			}
+		}
+	}
+	r.values = getConfigValues(cr)
+	r.applies = newPendingApplies()
+
 	// pull all config repo indices from secrets first
 	for _, configSecret := range configSecretList.Items {
 		repoUrl := string(configSecret.Data[RemoteRepository])
@@ -280,13 +423,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 			log.Info("skipping duplicate configuration secret", "namespace", configSecret.Namespace,
 				"name", configSecret.Name)
 		} else {
-			repos[configSecret.Name] = v1.RepositoryInfo{
+			repoInfo := v1.RepositoryInfo{
 				AccessToken: string(configSecret.Data[RemoteAccessToken]),
 				Channel:     string(configSecret.Data[RemoteChannel]),
 				Tag:         string(configSecret.Data[RemoteTag]),
 				Repository:  repoUrl,
 				Source:      &configSecret,
 			}
+
+			err = r.resolveRolloutRevision(cr, s, &configSecret, &repoInfo)
+			if err != nil {
+				log.Error(err, "failed to read configuration rollout file")
+				r.recorder.Eventf(cr, v12.EventTypeWarning, v1.EventConfigFetchFailed, "failed to read rollout file of %v: %v", repoUrl, err)
+				return v1.ResultFailed, err
+			}
+			repos[configSecret.Name] = repoInfo
 		}
 	}
 
@@ -296,6 +447,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		indexBytes, err := r.readIndexFile(&repoInfo)
 		if err != nil {
 			log.Error(err, "failed to fetch configuration repository index file")
+			r.recorder.Eventf(cr, v12.EventTypeWarning, v1.EventConfigFetchFailed, "failed to fetch index of %v: %v", repoInfo.Repository, err)
 			return v1.ResultFailed, err
 		}
 
@@ -303,6 +455,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		err = json.Unmarshal(indexBytes, &index)
 		if err != nil {
 			log.Error(err, "failed to unmarshal configuration repository index")
+			r.recorder.Eventf(cr, v12.EventTypeWarning, v1.EventConfigFetchFailed, "invalid index in %v: %v", repoInfo.Repository, err)
 			return v1.ResultFailed, err
 		}
 		index.BaseUrl = fmt.Sprintf("%s/%s", repoInfo.Repository, repoInfo.Channel)
@@ -311,6 +464,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		index.Source = repoInfo.Source
 		indexes = append(indexes, index)
 	}
+	sortIndexes(indexes)
+
+	specHash, err := getConfigSpecHash(cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Nothing to apply if neither the repositories nor the spec changed since the last sync. Forced syncs
+	// and syncs that did not complete are always applied
+	if rollbackTo == "" && !overrideLastSync && cr.Status.LastSynced != 0 && specHash == s.ConfigSpecHash &&
+		time.Since(time.Unix(s.ConfigApplied, 0)) < ConfigReapplyPeriod {
+		unchanged, err := r.configUnchanged(repos)
+		if err != nil {
+			r.recorder.Eventf(cr, v12.EventTypeWarning, v1.EventConfigFetchFailed, "failed to fetch configuration: %v", err)
+			return v1.ResultFailed, err
+		}
+		if unchanged {
+			log.Info("configuration unchanged, skipping apply")
+			s.LastSynced = time.Now().Unix()
+			return v1.ResultSuccess, nil
+		}
+	}
+
+	err = r.filterConfigSources(cr, indexes)
+	if err != nil {
+		r.recorder.Eventf(cr, v12.EventTypeWarning, v1.EventConfigFetchFailed, "failed to filter configuration: %v", err)
+		return v1.ResultFailed, err
+	}
+
+	// Sources that define the same resources fail the sync before anything is applied, if the
+	// resources are merged with ErrorOnConflict
+	err = r.checkConfigConflicts(cr, s, indexes)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
 
 	// Delete unrequested token secrets
 	err = r.deleteUnrequestedCredentialSecrets(ctx, cr, indexes)
@@ -318,6 +506,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return v1.ResultFailed, err
 	}
 
+	err = r.invalidateRotatedTokens(ctx, cr, indexes, rotated)
+	if err != nil {
+		return v1.ResultFailed, errors2.Wrap(err, "error invalidating tokens of rotated credentials")
+	}
+
 	for _, index := range indexes {
 		err = token2.ReconcileObservatoria(r.logger, ctx, r.client, cr, &index)
 		if err != nil {
@@ -327,7 +520,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		r.stampConfigSource(ctx, &index)
 	}
 
-	err = r.deleteUnrequestedTokenRefreshers(ctx, cr, indexes)
+	// Token refreshers of externally managed or disabled components are removed
+	requestedRefreshers := indexes
+	if cr.TokenRefresherMode() != v1.ComponentManaged {
+		requestedRefreshers = nil
+	}
+	err = r.deleteUnrequestedTokenRefreshers(ctx, cr, requestedRefreshers)
 	if err != nil {
 		return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested token refreshers")
 	}
@@ -337,7 +535,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested network policies")
 	}
 
-	if !cr.ObservatoriumDisabled() {
+	if !cr.ObservatoriumDisabled() && cr.TokenRefresherMode() == v1.ComponentManaged {
 		err = r.reconcileTokenRefresher(ctx, cr, indexes)
 		if err != nil {
 			return v1.ResultFailed, errors2.Wrap(err, "error reconciling token refresher")
@@ -346,12 +544,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 
 	// Alertmanager configuration
 	// When external sync is disabled, allow to create secret
-	if !cr.ExternalSyncDisabled() {
+	if !cr.ExternalSyncDisabled() && cr.AlertmanagerMode() != v1.ComponentDisabled {
 		overrideConfigSecret, _ := cr.HasAlertmanagerConfigSecret()
 
 		// Only create the config secret if the user has not overridden it via CR
 		if !overrideConfigSecret {
-			err = r.reconcileAlertmanagerSecret(ctx, cr, indexes)
+			err = r.reconcileAlertmanagerSecret(ctx, cr, s, indexes)
 			if err != nil {
 				return v1.ResultFailed, errors2.Wrap(err, "error reconciling alertmanager secret")
 			}
@@ -361,103 +559,426 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 	// Prometheus additional scrape configs
 	patterns, err := r.fetchFederationConfigs(cr, indexes)
 	if err != nil {
+		r.recorder.Eventf(cr, v12.EventTypeWarning, v1.EventConfigFetchFailed, "failed to fetch federation config: %v", err)
 		return v1.ResultFailed, errors2.Wrap(err, "error fetching federation config")
 	}
-	err = r.createAdditionalScrapeConfigSecret(cr, ctx, patterns)
-	if err != nil {
-		return v1.ResultFailed, err
+	// Alertmanager CR
+	if cr.AlertmanagerMode() == v1.ComponentManaged {
+		err = r.reconcileAlertmanager(ctx, cr, s)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling alertmanager")
+		}
+	} else {
+		err = r.deleteUnmanagedComponent(ctx, model.GetAlertmanagerCr(cr))
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error deleting alertmanager")
+		}
+		s.SetDeferredRollout(model.GetAlertmanagerCr(cr).Name, "")
 	}
-	//blackbox exporter
-	hash, err := r.createBlackBoxConfig(cr, ctx)
-	if err != nil {
-		return v1.ResultFailed, err
+
+	resizing := false
+	if cr.PrometheusMode() == v1.ComponentManaged {
+		err = r.createAdditionalScrapeConfigSecret(cr, ctx, patterns)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		//blackbox exporter
+		hash, err := r.createBlackBoxConfig(cr, ctx)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+
+		err = r.reconcileQueryLogConfig(cr, ctx)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling query log config")
+		}
+
+		// Prometheus CR
+		err = r.reconcilePrometheus(ctx, cr, s, indexes, hash, model.GetSuspendedTenants(s))
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling prometheus")
+		}
+
+		// Expand existing Prometheus volumes if the requested storage size grew
+		resizing, err = r.reconcilePrometheusVolumes(ctx, cr, indexes)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling prometheus volumes")
+		}
+	} else {
+		err = r.deleteUnmanagedComponent(ctx, model.GetPrometheus(cr))
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error deleting prometheus")
+		}
+
+		err = r.deleteUnrequestedPrometheusShards(ctx, cr, s, 0)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error deleting prometheus shards")
+		}
 	}
-	// Alertmanager CR
-	err = r.reconcileAlertmanager(ctx, cr)
+
+	// Remote write of the user workload monitoring
+	err = r.reconcileUserWorkloadMonitoring(ctx, cr, indexes)
 	if err != nil {
-		return v1.ResultFailed, errors2.Wrap(err, "error reconciling alertmanager")
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling user workload monitoring")
 	}
 
-	// Prometheus CR
-	err = r.reconcilePrometheus(ctx, cr, indexes, hash)
-	if err != nil {
-		return v1.ResultFailed, errors2.Wrap(err, "error reconciling prometheus")
+	if cr.GrafanaMode() == v1.ComponentManaged {
+		// Grafana plugins
+		pluginsHash, err := r.reconcileGrafanaPlugins(ctx, cr, s, indexes)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana plugins")
+		}
+
+		// Grafana SMTP credentials
+		smtpHash, err := r.reconcileGrafanaSmtp(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana smtp")
+		}
+
+		// Grafana image renderer
+		rendererHash, err := r.reconcileGrafanaImageRenderer(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana image renderer")
+		}
+
+		// Grafana LDAP configuration
+		ldapHash, err := r.reconcileGrafanaLDAP(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana ldap")
+		}
+
+		// Grafana database credentials
+		databaseHash, err := r.reconcileGrafanaDatabase(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana database")
+		}
+
+		// Grafana sharing policy
+		sharingHash, err := r.reconcileGrafanaSharing(ctx, cr, s)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana sharing")
+		}
+
+		// Grafana CR
+		err = r.reconcileGrafanaCr(ctx, cr, s, indexes, pluginsHash, smtpHash, rendererHash, ldapHash, databaseHash, sharingHash)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana")
+		}
+	} else {
+		err = r.deleteUnmanagedComponent(ctx, model.GetGrafanaCr(cr))
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error deleting grafana")
+		}
+		s.SetDeferredRollout(model.GetGrafanaCr(cr).Name, "")
+		s.GrafanaSharing = nil
 	}
 
-	// Grafana CR
-	err = r.reconcileGrafanaCr(ctx, cr, indexes)
-	if err != nil {
-		return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana")
+	// Grafana contact points
+	if cr.GrafanaMode() == v1.ComponentManaged || cr.GrafanaExternal() {
+		err = r.reconcileGrafanaContactPoints(ctx, cr, s)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana contact points")
+		}
+
+		err = r.reconcileGrafanaAccess(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana organizations, teams and api keys")
+		}
+	}
+
+	// Grafana backups
+	if cr.BackupEnabled() && (cr.GrafanaMode() == v1.ComponentManaged || cr.GrafanaExternal()) {
+		err = r.reconcileGrafanaBackup(ctx, cr, s)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana backup")
+		}
 	}
 
 	// Manage monitoring resources
+	s.InvalidDashboards = nil
+	s.DashboardConflicts = nil
+	// Dashboards, rules and pod monitors are applied right after they are rendered, over several syncs
+	// if there are many of them. The progress of the last sync is in cr.Status
+	s.ConfigApplyProgress = nil
+	applied := true
 	if !cr.ExternalSyncDisabled() {
-		dashboards := getUniqueDashboards(indexes)
-		err = r.deleteUnrequestedDashboards(cr, ctx, dashboards)
+		if cr.GrafanaMode() != v1.ComponentDisabled {
+			s.DashboardConflicts = getDashboardNameConflicts(orderIndexes(indexes, cr.DashboardMergeStrategy()))
+		}
+
+		// Dashboards are still provisioned into an externally managed Grafana. An external Grafana
+		// configured in the CR receives them through its API
+		if cr.GrafanaExternal() && cr.GrafanaMode() == v1.ComponentExternal {
+			err = r.reconcileExternalDashboards(cr, ctx, getUniqueDashboards(orderIndexes(indexes, cr.DashboardMergeStrategy())), s)
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, "error reconciling external grafana dashboards")
+			}
+
+			err = r.reconcileGrafanaFolderPermissions(cr, ctx, getUniqueFolders(indexes))
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana folder permissions")
+			}
+		} else if cr.GrafanaMode() != v1.ComponentDisabled {
+			served, err := utils.RequireAPI(ctx, r.client, cr, s, utils.APIGrafanaDashboard)
+			if err != nil {
+				return v1.ResultFailed, err
+			}
+			if served {
+				dashboards := getUniqueDashboards(orderIndexes(indexes, cr.DashboardMergeStrategy()))
+				requested := dashboards
+				if cr.QueryLoggingEnabled() {
+					requested = append(requested, DashboardInfo{Name: model.GetQueryLogDashboard(cr).Name})
+				}
+				err = r.deleteUnrequestedDashboards(cr, ctx, requested)
+				if err != nil {
+					return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested dashboards")
+				}
+
+				err = r.createRequestedDashboards(cr, ctx, dashboards, s)
+				if err != nil {
+					return v1.ResultFailed, errors2.Wrap(err, "error creating requested dashboards")
+				}
+
+				applied, err = r.flushApplies(ctx, cr, s, ApplyKindDashboards)
+				if err != nil {
+					return v1.ResultFailed, errors2.Wrap(err, "error applying dashboards")
+				}
+
+				err = r.reconcileQueryLogDashboard(cr, ctx)
+				if err != nil {
+					return v1.ResultFailed, errors2.Wrap(err, "error reconciling query log dashboard")
+				}
+
+				err = r.reconcileGrafanaFolderPermissions(cr, ctx, getUniqueFolders(indexes))
+				if err != nil {
+					return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana folder permissions")
+				}
+			}
+		}
+	}
+
+	// Dashboards with the uid or title of another dashboard were skipped, the conflict is only an
+	// error once they are fetched
+	if cr.DashboardMergeStrategy() == v1.MergeErrorOnConflict {
+		err = getDashboardConflictError(s.DashboardConflicts)
 		if err != nil {
-			return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested dashboards")
+			r.recorder.Eventf(cr, v12.EventTypeWarning, v1.EventConfigConflict, "configuration not fully applied: %v", err)
+			return v1.ResultFailed, err
 		}
+	}
 
-		err = r.createRequestedDashboards(cr, ctx, dashboards)
+	// Rules and pod monitors are skipped on clusters without their CRDs
+	rulesServed, monitorsServed, testingRules := false, false, false
+	if cr.PrometheusMode() != v1.ComponentDisabled {
+		rulesServed, err = utils.RequireAPI(ctx, r.client, cr, s, utils.APIPrometheusRule)
 		if err != nil {
-			return v1.ResultFailed, errors2.Wrap(err, "error creating requested dashboards")
+			return v1.ResultFailed, err
+		}
+		monitorsServed, err = utils.RequireAPI(ctx, r.client, cr, s, utils.APIPodMonitor)
+		if err != nil {
+			return v1.ResultFailed, err
 		}
+	} else {
+		s.SetMissingAPI(utils.APIPrometheusRule, false)
+		s.SetMissingAPI(utils.APIPodMonitor, false)
+	}
 
+	if !cr.ExternalSyncDisabled() && cr.PrometheusMode() != v1.ComponentDisabled {
 		// Manage prometheus rules
-		rules := getUniqueRules(indexes)
-		err = r.deleteUnrequestedRules(cr, ctx, rules)
-		if err != nil {
-			return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested prometheus rules")
+		ruleIndexes := orderIndexes(indexes, cr.RuleMergeStrategy())
+		rules := getUniqueRules(ruleIndexes)
+		ruleTests := getUniqueRuleTests(ruleIndexes)
+		// Agents evaluate no rules, those of the sources are removed
+		if cr.PrometheusAgentEnabled() {
+			rules, ruleTests = nil, nil
 		}
+		if rulesServed {
+			err = r.deleteUnrequestedRules(cr, ctx, rules)
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested prometheus rules")
+			}
 
-		err = r.createRequestedRules(cr, ctx, rules)
-		if err != nil {
-			return v1.ResultFailed, errors2.Wrap(err, "error creating requested prometheus rules")
+			testingRules, err = r.createRequestedRules(cr, ctx, rules, ruleTests, s)
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, "error creating requested prometheus rules")
+			}
+
+			rulesApplied, err := r.flushApplies(ctx, cr, s, ApplyKindRules)
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, "error applying prometheus rules")
+			}
+			applied = applied && rulesApplied
 		}
 
 		// Manage pod monitors
 		monitors := getUniquePodMonitors(indexes)
-		err = r.deleteUnrequestedPodMonitors(cr, ctx, monitors)
-		if err != nil {
-			return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested pod monitors")
+		if monitorsServed {
+			err = r.deleteUnrequestedPodMonitors(cr, ctx, monitors)
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested pod monitors")
+			}
+
+			err = r.createRequestedPodMonitors(cr, ctx, monitors)
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, "error creating requested pod monitors")
+			}
+
+			monitorsApplied, err := r.flushApplies(ctx, cr, s, ApplyKindPodMonitors)
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, "error applying pod monitors")
+			}
+			applied = applied && monitorsApplied
 		}
 
-		err = r.createRequestedPodMonitors(cr, ctx, monitors)
-		if err != nil {
-			return v1.ResultFailed, errors2.Wrap(err, "error creating requested pod monitors")
+		// Copies of pod monitors and rules for the user workload monitoring
+		if rulesServed && monitorsServed {
+			err = r.reconcileUserWorkloadResources(cr, ctx, monitors, rules)
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, "error reconciling user workload monitoring resources")
+			}
 		}
-	} else {
+	} else if rulesServed {
 		err = r.createDMSAlert(cr, ctx)
 		if err != nil {
 			return v1.ResultFailed, errors2.Wrap(err, "error creating deadmansswitch alert")
 		}
 	}
 
+	if rulesServed {
+		err = r.createRemoteWriteHealthRules(cr, ctx, indexes)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error creating remote write health rules")
+		}
+
+		err = r.createTenantQuotaRules(cr, ctx, indexes)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error creating tenant quota rules")
+		}
+
+		err = r.createCredentialExpiryRules(cr, ctx, indexes, s)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error creating credential expiry rules")
+		}
+
+		err = r.createStackVerificationRules(cr, ctx, indexes)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error creating stack verification rules")
+		}
+	}
+
+	err = r.reconcileLokiRules(ctx, cr, s, indexes)
+	if err != nil {
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling loki rules")
+	}
+
 	// Promtail instances
 	// First cleanup any no longer requested instances
-	err = r.deleteUnrequestedDaemonsets(ctx, cr, indexes)
+	err = r.deleteUnrequestedDaemonsets(ctx, cr, s, indexes)
 	if err != nil {
 		return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested promtail daemon sets")
 	}
 
 	// Create requested promtail instances
 	// There will be a dedicated instance for every index
-	for _, index := range indexes {
-		err = r.createPromtailDaemonsetFor(ctx, cr, &index)
+	if cr.PromtailMode() == v1.ComponentManaged {
+		for _, index := range indexes {
+			err = r.createPromtailDaemonsetFor(ctx, cr, s, &index)
+			if err != nil {
+				return v1.ResultFailed, errors2.Wrap(err, fmt.Sprintf("error creating promtail daemon set for %s", index.Id))
+			}
+		}
+	}
+
+	err = r.updateReferencedSecrets(ctx, cr, indexes, s)
+	if err != nil {
+		return v1.ResultFailed, errors2.Wrap(err, "error updating referenced secrets")
+	}
+
+	previousSnapshot := s.ConfigSnapshot
+	if rollbackTo == "" {
+		r.snapshot.Revisions = getConfigRevisions(cr, repos, r.snapshot)
+		id, err := r.storeConfigSnapshot(ctx, cr, r.snapshot)
 		if err != nil {
-			return v1.ResultFailed, errors2.Wrap(err, fmt.Sprintf("error creating promtail daemon set for %s", index.Id))
+			// Syncing works without snapshots, only rollbacks to this revision are not possible
+			log.Error(err, "error storing config snapshot")
+		} else {
+			s.ConfigSnapshot = id
 		}
+	} else {
+		s.ConfigSnapshot = rollbackTo
+	}
+	s.ConfigRevisions = r.snapshot.Revisions
+	s.ConfigRollback = rollbackTo
+	s.ResyncRequested = resync
+	s.RolloutRequested = rollout
+	s.ConfigSpecHash = specHash
+	s.ConfigApplied = time.Now().Unix()
+	if s.ConfigSnapshot != previousSnapshot {
+		r.recorder.Eventf(cr, v12.EventTypeNormal, v1.EventConfigRevisionApplied, "applied configuration snapshot %v: %v", s.ConfigSnapshot, getConfigRevisionsSummary(s.ConfigRevisions))
 	}
 
 	// Next status: update timestamp
+	// Keep syncing until all Prometheus volumes are expanded
+	if resizing {
+		log.Info("waiting for prometheus volumes to be expanded")
+		s.LastSynced = 0
+		return v1.ResultInProgress, nil
+	}
+
+	// Keep syncing until all dashboards, rules and pod monitors are applied
+	if !applied {
+		log.Info("applying configuration", "progress", s.ConfigApplyProgress)
+		s.LastSynced = 0
+		return v1.ResultInProgress, nil
+	}
+
+	// Keep syncing until the unit tests of the rules finished
+	if testingRules {
+		log.Info("waiting for prometheus rule tests")
+		s.LastSynced = 0
+		return v1.ResultInProgress, nil
+	}
+
 	if cr.ExternalSyncDisabled() {
 		s.LastSynced = 0
 	} else {
 		s.LastSynced = time.Now().Unix()
+		r.recorder.Eventf(cr, v12.EventTypeNormal, v1.EventConfigSynced, "synced configuration from %v repositories", len(indexes))
 	}
 	return v1.ResultSuccess, nil
 }
 
+// Sources with a higher priority win conflicts between their resources. Ties are broken by the name
+// of the configuration secret, so the winner does not depend on the order the secrets are listed in
+func sortIndexes(indexes []v1.RepositoryIndex) {
+	sort.SliceStable(indexes, func(i, j int) bool {
+		pi, pj := getIndexPriority(&indexes[i]), getIndexPriority(&indexes[j])
+		if pi != pj {
+			return pi > pj
+		}
+		return getIndexSource(&indexes[i]) < getIndexSource(&indexes[j])
+	})
+}
+
+// The priority is an optional integer in the priority key of the configuration secret, 0 if absent
+func getIndexPriority(index *v1.RepositoryIndex) int {
+	if index.Source == nil {
+		return 0
+	}
+	priority, err := strconv.Atoi(string(index.Source.Data[RemotePriority]))
+	if err != nil {
+		return 0
+	}
+	return priority
+}
+
+func getIndexSource(index *v1.RepositoryIndex) string {
+	if index.Source == nil {
+		return ""
+	}
+	return index.Source.Name
+}
+
 func (r *Reconciler) deleteUnrequestedCredentialSecrets(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
 	list := v12.SecretList{}
 	selector := labels.SelectorFromSet(map[string]string{
@@ -505,84 +1026,18 @@ func (r *Reconciler) deleteUnrequestedCredentialSecrets(ctx context.Context, cr
 }
 
 func (r *Reconciler) readIndexFile(repo *v1.RepositoryInfo) ([]byte, error) {
-	repoUrl, err := url.ParseRequestURI(fmt.Sprintf("%s/%s/index.json", repo.Repository, repo.Channel))
+	indexUrl := fmt.Sprintf("%s/%s/index.json", repo.Repository, repo.Channel)
+	bytes, err := r.fetchRepositoryFile(indexUrl, repo.Tag, repo.AccessToken)
 	if err != nil {
 		return nil, err
 	}
-
-	if repo.AccessToken == "" {
-		return nil, fmt.Errorf("repository ConfigMap missing required AccessToken")
-	}
-
-	req, err := http.NewRequest(http.MethodGet, repoUrl.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", repo.AccessToken))
-	req.Header.Set("Accept", "application/vnd.github.v3.raw")
-
-	if repo.Tag != "" {
-		q := req.URL.Query()
-		q.Add("ref", repo.Tag)
-		req.URL.RawQuery = q.Encode()
-	}
-
-	resp, err := r.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code when reading index file from %v: %v", req.URL.String(), resp.StatusCode)
-	}
-
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return bytes, nil
+	return renderConfigValues(bytes, r.values), nil
 }
 
 func (r *Reconciler) fetchResource(path string, tag string, token string) ([]byte, error) {
-	resourceUrl, err := url.ParseRequestURI(path)
-	if err != nil {
-		return nil, errors2.Wrap(err, fmt.Sprintf("error parsing resource url: %s", path))
-	}
-
-	if token == "" {
-		return nil, fmt.Errorf("repository ConfigMap missing required AccessToken")
-	}
-
-	req, err := http.NewRequest(http.MethodGet, resourceUrl.String(), nil)
-	if err != nil {
-		return nil, errors2.Wrap(err, "error creating http request")
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
-	req.Header.Set("Accept", "application/vnd.github.v3.raw")
-
-	if tag != "" {
-		q := req.URL.Query()
-		q.Add("ref", tag)
-		req.URL.RawQuery = q.Encode()
-	}
-
-	resp, err := r.httpClient.Do(req)
+	body, err := r.fetchRepositoryFile(path, tag, token)
 	if err != nil {
 		return nil, errors2.Wrap(err, fmt.Sprintf("error fetching resource from %s", path))
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("unexpected status code when resource from %v: %v", req.URL.String(), resp.StatusCode)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors2.Wrap(err, "error reading response")
-	}
-
-	return body, nil
+	return renderConfigValues(body, r.values), nil
 }
@@ -0,0 +1,296 @@
+package configuration
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	token2 "github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v3/controllers/token"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Secret with certificates in one of its keys
+type certificateSecret struct {
+	kind string
+	name string
+	key  string
+}
+
+// Certificates of the stack in the namespace of the CR. Secrets that don't exist are not used by
+// the current setup and skipped
+func getCertificateSecrets(cr *v1.Observability) []certificateSecret {
+	result := []certificateSecret{
+		{v1.CredentialKindCertificate, model.GetPrometheusTLSSecret(cr).Name, corev1.TLSCertKey},
+		{v1.CredentialKindCertificate, model.GetAlertmanagerTLSSecret(cr).Name, corev1.TLSCertKey},
+		{v1.CredentialKindCertificate, "grafana-k8s-tls", corev1.TLSCertKey},
+		{v1.CredentialKindCertificate, model.QueryProxyTLS, corev1.TLSCertKey},
+	}
+	if model.IsInternalTLSEnabled(cr) {
+		for _, component := range model.InternalTLSComponents {
+			name := model.GetInternalTLSSecretName(component)
+			result = append(result,
+				certificateSecret{v1.CredentialKindCertificate, name, corev1.TLSCertKey},
+				certificateSecret{v1.CredentialKindCABundle, name, "ca.crt"})
+		}
+	}
+	for _, c := range model.GetPromtailClients(cr) {
+		if c.CASecret != "" {
+			result = append(result, certificateSecret{v1.CredentialKindCABundle, c.CASecret, "ca.crt"})
+		}
+		if c.CertSecret != "" {
+			result = append(result, certificateSecret{v1.CredentialKindCertificate, c.CertSecret, corev1.TLSCertKey})
+		}
+	}
+	return result
+}
+
+// Returns the expiry of the certificate in a PEM bundle that expires first
+func getCertificatesExpiry(data []byte) (time.Time, error) {
+	var expiry time.Time
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if expiry.IsZero() || certificate.NotAfter.Before(expiry) {
+			expiry = certificate.NotAfter
+		}
+	}
+	if expiry.IsZero() {
+		return time.Time{}, fmt.Errorf("no certificate found")
+	}
+	return expiry, nil
+}
+
+func (r *Reconciler) getCredentialExpiries(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) ([]v1.CredentialExpiry, error) {
+	var result []v1.CredentialExpiry
+
+	for _, certificates := range getCertificateSecrets(cr) {
+		secret := &corev1.Secret{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: certificates.name}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		expiry := v1.CredentialExpiry{
+			Kind:      certificates.kind,
+			Namespace: cr.Namespace,
+			Name:      certificates.name,
+		}
+		expires, err := getCertificatesExpiry(secret.Data[certificates.key])
+		if err != nil {
+			expiry.Error = fmt.Sprintf("invalid certificate in %v: %v", certificates.key, err)
+		} else {
+			expiry.Expires = expires.Unix()
+		}
+		result = append(result, expiry)
+	}
+
+	// Tokens without a lifetime don't expire
+	tokens := &corev1.SecretList{}
+	err := r.client.List(ctx, tokens, &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			"managed-by": "observability-operator",
+			"purpose":    "observatorium-token-secret",
+		}),
+		Namespace: cr.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range tokens.Items {
+		expires, err := strconv.ParseInt(string(secret.Data[token2.RemoteTokenLifetime]), 10, 64)
+		if err != nil || expires == 0 {
+			continue
+		}
+		result = append(result, v1.CredentialExpiry{
+			Kind:      v1.CredentialKindObservatoriumToken,
+			Namespace: secret.Namespace,
+			Name:      secret.Name,
+			Expires:   expires,
+		})
+	}
+
+	// The credentials of the referenced secrets don't carry their expiry, it can be annotated
+	for _, referenced := range s.ReferencedSecrets {
+		secret := &corev1.Secret{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: referenced.Namespace, Name: referenced.Name}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		annotation, ok := secret.Annotations[model.CredentialExpiresAnnotation]
+		if !ok {
+			continue
+		}
+
+		expiry := v1.CredentialExpiry{
+			Kind:      v1.CredentialKindCredentials,
+			Namespace: referenced.Namespace,
+			Name:      referenced.Name,
+		}
+		expires, err := time.Parse(time.RFC3339, annotation)
+		if err != nil {
+			expiry.Error = fmt.Sprintf("invalid %v annotation: %v", model.CredentialExpiresAnnotation, annotation)
+		} else {
+			expiry.Expires = expires.Unix()
+		}
+		result = append(result, expiry)
+	}
+
+	return result, nil
+}
+
+// Observatorium tokens are refreshed shortly before they expire, they only count as expiring
+// once the refresh is overdue
+func isCredentialExpiring(expiry v1.CredentialExpiry, window time.Duration, now time.Time) bool {
+	if expiry.Expires == 0 {
+		return false
+	}
+	if expiry.Kind == v1.CredentialKindObservatoriumToken {
+		return token.AuthTokenExpires(expiry.Expires)
+	}
+	return time.Unix(expiry.Expires, 0).Before(now.Add(window))
+}
+
+// Updates the expiries of the certificates and credentials and flips the CredentialsExpiring
+// condition. Returns true if an expiry changed, the recording rule has to be updated then
+func (r *Reconciler) checkCredentialExpiry(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) bool {
+	expiries, err := r.getCredentialExpiries(ctx, cr, s)
+	if err != nil {
+		// Keep the last known expiries until the secrets can be read
+		r.logger.Error(err, "error checking credential expiry")
+		return false
+	}
+
+	changed := len(expiries) != len(s.CredentialExpiries)
+	for i := 0; !changed && i < len(expiries); i++ {
+		changed = expiries[i] != s.CredentialExpiries[i]
+	}
+	s.CredentialExpiries = expiries
+
+	now := time.Now()
+	window := model.GetExpiryWindow(cr)
+	var expiring []string
+	for _, expiry := range expiries {
+		if expiry.Error != "" {
+			expiring = append(expiring, fmt.Sprintf("%v %v/%v: %v", expiry.Kind, expiry.Namespace, expiry.Name, expiry.Error))
+			continue
+		}
+		if isCredentialExpiring(expiry, window, now) {
+			expiring = append(expiring, fmt.Sprintf("%v %v/%v expires at %v", expiry.Kind, expiry.Namespace, expiry.Name,
+				time.Unix(expiry.Expires, 0).UTC().Format(time.RFC3339)))
+		}
+	}
+
+	if len(expiring) == 0 {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.CredentialsExpiring,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoCredentialsExpiring",
+			Message: fmt.Sprintf("no certificates or credentials expire within %v", window),
+		})
+		return changed
+	}
+
+	// Keep the message stable between reconciles
+	sort.Strings(expiring)
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:    v1.CredentialsExpiring,
+		Status:  metav1.ConditionTrue,
+		Reason:  "CredentialsExpiring",
+		Message: strings.Join(expiring, ", "),
+	})
+	return changed
+}
+
+// Records the expiries as series and alerts on the ones that expire within the window, so that
+// expiring credentials are noticed without watching the status of the CR
+func (r *Reconciler) createCredentialExpiryRules(cr *v1.Observability, ctx context.Context, indexes []v1.RepositoryIndex, s *v1.ObservabilityStatus) error {
+	rule := model.GetCredentialExpiryRule(cr)
+
+	var records []v12.Rule
+	for _, expiry := range s.CredentialExpiries {
+		if expiry.Expires == 0 {
+			continue
+		}
+		records = append(records, v12.Rule{
+			Record: model.CredentialExpiryRecord,
+			Expr:   intstr.FromString(fmt.Sprintf("vector(%v)", expiry.Expires)),
+			Labels: map[string]string{
+				"kind":             expiry.Kind,
+				"secret":           expiry.Name,
+				"secret_namespace": expiry.Namespace,
+			},
+		})
+	}
+
+	window := int64(model.GetExpiryWindow(cr).Seconds())
+	return utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+
+		// Make sure the rule is picked up by Prometheus
+		ruleSelector := model.GetPrometheusRuleLabelSelectors(cr, indexes)
+		if ruleSelector != nil {
+			for k, v := range ruleSelector.MatchLabels {
+				rule.Labels[k] = v
+			}
+		}
+
+		rule.Spec.Groups = []v12.RuleGroup{
+			{
+				Name: "credential-expiry-alerts",
+				Rules: []v12.Rule{
+					{
+						// Tokens are refreshed an hour before they expire
+						Alert: model.AlertCredentialExpiring,
+						Expr: intstr.FromString(fmt.Sprintf(`%[1]v{kind!="%[2]v"} - time() < %[3]v or %[1]v{kind="%[2]v"} - time() < 3600`,
+							model.CredentialExpiryRecord, v1.CredentialKindObservatoriumToken, window)),
+						For: "10m",
+						Labels: map[string]string{
+							"severity": "warning",
+						},
+						Annotations: map[string]string{
+							"message": "{{ $labels.kind }} {{ $labels.secret_namespace }}/{{ $labels.secret }} expires in {{ $value | humanizeDuration }}.",
+						},
+					},
+				},
+			},
+		}
+		if len(records) > 0 {
+			rule.Spec.Groups = append([]v12.RuleGroup{{Name: "credential-expiry", Rules: records}}, rule.Spec.Groups...)
+		}
+		return nil
+	})
+}
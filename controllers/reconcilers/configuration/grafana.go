@@ -6,19 +6,30 @@ import (
 	"github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, indexes []v1.RepositoryIndex, pluginsHash string, smtpHash string, rendererHash string, ldapHash string, databaseHash string, sharingHash string) error {
 	grafana := model.GetGrafanaCr(cr)
 
 	var f = false
 	var t = true
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, grafana, func() error {
+	accessType, err := utils.GetUIAccessType(ctx, r.client, cr)
+	if err != nil {
+		return err
+	}
+
+	tlsSecret, openshift, err := utils.GetUIProxyTLSSecret(ctx, r.client, cr, model.InternalTLSGrafana, "grafana-k8s-tls")
+	if err != nil {
+		return err
+	}
+
+	err = utils.Apply(ctx, r.client, grafana, func() error {
+		existing := grafana.Spec.DeepCopy()
 		grafana.Spec = v1alpha1.GrafanaSpec{
 			Config: v1alpha1.GrafanaConfig{
 				Log: &v1alpha1.GrafanaConfigLog{
@@ -39,7 +50,7 @@ func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observabilit
 			Containers: []core.Container{
 				{
 					Name:  "grafana-proxy",
-					Image: "quay.io/openshift/origin-oauth-proxy:4.8",
+					Image: model.GetImage(cr, v1.ImageOAuthProxy, model.OAuthProxyImage),
 					Args: []string{
 						"-provider=openshift",
 						"-pass-basic-auth=false",
@@ -115,16 +126,104 @@ func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observabilit
 			Deployment: &v1alpha1.GrafanaDeployment{
 				Replicas:          1,
 				PriorityClassName: model.ObservabilityPriorityClassName,
+				Annotations: map[string]string{
+					GrafanaPluginsAnnotation:       pluginsHash,
+					GrafanaSmtpAnnotation:          smtpHash,
+					GrafanaImageRendererAnnotation: rendererHash,
+					GrafanaLDAPAnnotation:          ldapHash,
+					GrafanaDatabaseAnnotation:      databaseHash,
+					GrafanaSharingAnnotation:       sharingHash,
+				},
+				EnvFrom: []core.EnvFromSource{
+					{
+						ConfigMapRef: &core.ConfigMapEnvSource{
+							LocalObjectReference: core.LocalObjectReference{
+								Name: model.GetGrafanaPluginsConfigMap(cr).Name,
+							},
+						},
+					},
+					{
+						SecretRef: &core.SecretEnvSource{
+							LocalObjectReference: core.LocalObjectReference{
+								Name: model.GetGrafanaSmtpSecret(cr).Name,
+							},
+							Optional: &t,
+						},
+					},
+					{
+						ConfigMapRef: &core.ConfigMapEnvSource{
+							LocalObjectReference: core.LocalObjectReference{
+								Name: model.GetGrafanaImageRendererConfigMap(cr).Name,
+							},
+							Optional: &t,
+						},
+					},
+				},
 			},
 			Resources: model.GetGrafanaResourceRequirement(cr),
 		}
+		if image, ok := model.GetImageOverride(cr, v1.ImageGrafana); ok {
+			grafana.Spec.BaseImage = image
+		}
+		// The bundle replaces the init container that downloads the plugins into /opt/plugins
+		if image, ok := model.GetImageOverride(cr, v1.ImageGrafanaPluginBundle); ok {
+			grafana.Spec.InitImage = image
+		}
+		// The Grafana operator creates a route on OpenShift and an ingress everywhere else
+		grafana.Spec.Ingress.Hostname = model.GetGrafanaHost(cr)
+		if accessType == v1.UIAccessIngress {
+			grafana.Spec.Ingress.Annotations = model.GetUIIngressAnnotations(cr)
+			grafana.Spec.Ingress.Path = "/"
+			grafana.Spec.Ingress.PathType = "Prefix"
+			grafana.Spec.Ingress.TLSEnabled = true
+			grafana.Spec.Ingress.TLSSecretName = model.GetUITLSSecretName(cr)
+			if className := model.GetUIIngressClassName(cr); className != nil {
+				grafana.Spec.Ingress.IngressClassName = *className
+			}
+		}
+		// Other distributions have neither the OpenShift login nor the service CA
+		if !openshift {
+			grafana.Spec.Containers[0] = model.GetOIDCProxyContainer(cr, "grafana-proxy", "grafana-proxy", "http://localhost:3000", tlsSecret, "grafana-k8s-proxy")
+			grafana.Spec.Secrets = []string{tlsSecret, "grafana-k8s-proxy"}
+			grafana.Spec.Service.Annotations = nil
+		}
+		if cr.GrafanaImageRendererEnabled() {
+			grafana.Spec.Containers = append(grafana.Spec.Containers, getGrafanaImageRendererContainer(cr))
+		}
+		if smtp := model.GetGrafanaSmtp(cr); smtp != nil {
+			grafana.Spec.Config.Smtp = &v1alpha1.GrafanaConfigSmtp{
+				Enabled:     &t,
+				Host:        smtp.Host,
+				FromAddress: smtp.FromAddress,
+				FromName:    smtp.FromName,
+				SkipVerify:  &smtp.SkipVerify,
+			}
+		}
+		// LDAP users log in with the login form, anonymous users would see the dashboards without logging in
+		if cr.GrafanaLDAPEnabled() {
+			grafana.Spec.Config.Auth.DisableSignoutMenu = &f
+			grafana.Spec.Config.AuthAnonymous.Enabled = &f
+			grafana.Spec.Config.AuthLdap = &v1alpha1.GrafanaConfigAuthLdap{
+				Enabled:     &t,
+				AllowSignUp: &t,
+				ConfigFile:  getGrafanaLDAPConfigFile(cr),
+			}
+			grafana.Spec.Secrets = append(grafana.Spec.Secrets, model.GetGrafanaLDAPSecret(cr).Name)
+		}
+		err := setGrafanaPersistence(cr, grafana)
+		if err != nil {
+			return err
+		}
+		setGrafanaSharing(cr, grafana)
 		if cr.Spec.Tolerations != nil {
 			grafana.Spec.Deployment.Tolerations = cr.Spec.Tolerations
 		}
 		if cr.Spec.Affinity != nil {
 			grafana.Spec.Deployment.Affinity = cr.Spec.Affinity
 		}
-		return nil
+		return deferRollout(cr, s, grafana, grafana.Spec, func() {
+			grafana.Spec = *existing
+		})
 	})
 
 	return err
@@ -0,0 +1,292 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type grafanaOrganization struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type grafanaUser struct {
+	UserId int64  `json:"userId"`
+	Login  string `json:"login"`
+	Email  string `json:"email"`
+}
+
+type grafanaAPIKey struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Role       string `json:"role"`
+	Expiration string `json:"expiration,omitempty"`
+}
+
+// Returns a copy of the client that sends requests in the context of an organization, the
+// organization of the client if the id is 0
+func (c *GrafanaClient) inOrganization(id int64) *GrafanaClient {
+	if id == 0 {
+		return c
+	}
+	scoped := *c
+	scoped.orgId = id
+	return &scoped
+}
+
+// Organizations are created by the Grafana admin, who becomes an admin of the new organization
+func (c *GrafanaClient) getOrCreateOrganization(name string) (int64, error) {
+	existing := []grafanaOrganization{}
+	err := c.do(http.MethodGet, "/api/orgs", nil, &existing)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, organization := range existing {
+		if organization.Name == name {
+			return organization.Id, nil
+		}
+	}
+
+	created := struct {
+		OrgId int64 `json:"orgId"`
+	}{}
+	err = c.do(http.MethodPost, "/api/orgs", map[string]string{"name": name}, &created)
+	if err != nil {
+		return 0, err
+	}
+	return created.OrgId, nil
+}
+
+// Provision the organizations, teams and API keys of the CR. Organizations and teams are only created,
+// API keys removed from the CR are deleted together with their secrets.
+func (r *Reconciler) reconcileGrafanaAccess(ctx context.Context, cr *v1.Observability) error {
+	secrets := &v12.SecretList{}
+	err := r.client.List(ctx, secrets, client.InNamespace(cr.Namespace), client.HasLabels{model.GrafanaAPIKeyLabel})
+	if err != nil {
+		return err
+	}
+
+	organizations := model.GetGrafanaOrganizations(cr)
+	teams := model.GetGrafanaTeams(cr)
+	apiKeys := model.GetGrafanaAPIKeys(cr)
+	if len(organizations) == 0 && len(teams) == 0 && len(apiKeys) == 0 && len(secrets.Items) == 0 {
+		return nil
+	}
+
+	grafana, err := r.getGrafanaClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	// The main organization, or the organization of the external Grafana, has no name in the CR
+	organizationIds := map[string]int64{"": 0}
+	for _, organization := range organizations {
+		id, err := grafana.getOrCreateOrganization(organization.Name)
+		if err != nil {
+			return fmt.Errorf("error creating grafana organization %v: %v", organization.Name, err)
+		}
+		organizationIds[organization.Name] = id
+	}
+
+	for _, team := range teams {
+		err = r.reconcileGrafanaTeam(grafana.inOrganization(organizationIds[team.Organization]), team)
+		if err != nil {
+			return fmt.Errorf("error reconciling grafana team %v: %v", team.Name, err)
+		}
+	}
+
+	requestedSecrets := map[string]bool{}
+	for name, id := range organizationIds {
+		var requested []v1.GrafanaAPIKey
+		for _, apiKey := range apiKeys {
+			if apiKey.Organization == name {
+				requested = append(requested, apiKey)
+				requestedSecrets[apiKey.Secret] = true
+			}
+		}
+
+		err = r.reconcileGrafanaAPIKeys(ctx, cr, grafana.inOrganization(id), requested)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range secrets.Items {
+		if requestedSecrets[secrets.Items[i].Name] {
+			continue
+		}
+		err = r.client.Delete(ctx, &secrets.Items[i])
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Users that don't exist yet, e.g. because they never logged in, are added during the next sync
+func (r *Reconciler) reconcileGrafanaTeam(grafana *GrafanaClient, team v1.GrafanaTeam) error {
+	found, err := grafana.getOrCreateTeam(team.Name)
+	if err != nil {
+		return err
+	}
+
+	users := []grafanaUser{}
+	err = grafana.do(http.MethodGet, "/api/org/users", nil, &users)
+	if err != nil {
+		return err
+	}
+
+	members := []grafanaUser{}
+	err = grafana.do(http.MethodGet, fmt.Sprintf("/api/teams/%v/members", found.Id), nil, &members)
+	if err != nil {
+		return err
+	}
+
+	requested := map[int64]bool{}
+	for _, member := range team.Members {
+		var user *grafanaUser
+		for i := range users {
+			if users[i].Login == member || users[i].Email == member {
+				user = &users[i]
+				break
+			}
+		}
+		if user == nil {
+			r.logger.Info("grafana user does not exist yet, skipping team membership", "team", team.Name, "user", member)
+			continue
+		}
+		requested[user.UserId] = true
+
+		isMember := false
+		for _, existing := range members {
+			if existing.UserId == user.UserId {
+				isMember = true
+				break
+			}
+		}
+		if !isMember {
+			err = grafana.do(http.MethodPost, fmt.Sprintf("/api/teams/%v/members", found.Id), map[string]int64{"userId": user.UserId}, nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, existing := range members {
+		if requested[existing.UserId] {
+			continue
+		}
+		err = grafana.do(http.MethodDelete, fmt.Sprintf("/api/teams/%v/members/%v", found.Id, existing.UserId), nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The key is only returned by Grafana when it is created, so keys are replaced when their secret is
+// lost, when they expired or when their role changed
+func (r *Reconciler) reconcileGrafanaAPIKeys(ctx context.Context, cr *v1.Observability, grafana *GrafanaClient, apiKeys []v1.GrafanaAPIKey) error {
+	existing := []grafanaAPIKey{}
+	err := grafana.do(http.MethodGet, "/api/auth/keys", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	requested := map[string]bool{}
+	for _, apiKey := range apiKeys {
+		name := model.GrafanaAPIKeyPrefix + apiKey.Name
+		requested[name] = true
+
+		var found *grafanaAPIKey
+		for i := range existing {
+			if existing[i].Name == name {
+				found = &existing[i]
+				break
+			}
+		}
+
+		secret := model.GetGrafanaAPIKeySecret(cr, apiKey)
+		err = r.client.Get(ctx, client.ObjectKey{Namespace: secret.Namespace, Name: secret.Name}, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if found != nil && found.Role == apiKey.Role && !isGrafanaAPIKeyExpired(found) && len(secret.Data[model.GrafanaAPIKeyTokenKey]) > 0 {
+			continue
+		}
+
+		if found != nil {
+			err = grafana.do(http.MethodDelete, fmt.Sprintf("/api/auth/keys/%v", found.Id), nil, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		request := map[string]interface{}{
+			"name": name,
+			"role": apiKey.Role,
+		}
+		if apiKey.TTL != "" {
+			ttl, err := time.ParseDuration(apiKey.TTL)
+			if err != nil {
+				return err
+			}
+			request["secondsToLive"] = int64(ttl.Seconds())
+		}
+
+		created := struct {
+			Key string `json:"key"`
+		}{}
+		err = grafana.do(http.MethodPost, "/api/auth/keys", request, &created)
+		if err != nil {
+			return fmt.Errorf("error creating grafana api key %v: %v", apiKey.Name, err)
+		}
+
+		secret = model.GetGrafanaAPIKeySecret(cr, apiKey)
+		labels := secret.Labels
+		err = utils.Apply(ctx, r.client, secret, func() error {
+			secret.Labels = labels
+			secret.Data = map[string][]byte{
+				model.GrafanaAPIKeyTokenKey: []byte(created.Key),
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		r.logger.Info("grafana api key created", "key", apiKey.Name, "secret", secret.Name)
+	}
+
+	for _, apiKey := range existing {
+		if !strings.HasPrefix(apiKey.Name, model.GrafanaAPIKeyPrefix) || requested[apiKey.Name] {
+			continue
+		}
+		err = grafana.do(http.MethodDelete, fmt.Sprintf("/api/auth/keys/%v", apiKey.Id), nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isGrafanaAPIKeyExpired(apiKey *grafanaAPIKey) bool {
+	if apiKey.Expiration == "" {
+		return false
+	}
+	expiration, err := time.Parse(time.RFC3339, apiKey.Expiration)
+	return err == nil && time.Now().After(expiration)
+}
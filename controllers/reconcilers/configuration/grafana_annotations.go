@@ -0,0 +1,37 @@
+package configuration
+
+import (
+	"context"
+	"net/http"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+// Tag of all annotations written by the operator, dashboards show them with an annotation query of
+// the Grafana datasource filtering by this tag
+const GrafanaAnnotationTag = "observability-operator"
+
+// Writes the pending annotations to Grafana in the order of their events. Annotations that could not
+// be written stay pending until the next reconcile, e.g. while Grafana is rolled out
+func (r *Reconciler) writeGrafanaAnnotations(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) {
+	grafana, err := r.getGrafanaClient(ctx, cr)
+	if err != nil {
+		r.logger.Error(err, "error writing grafana annotations")
+		return
+	}
+
+	for len(s.PendingGrafanaAnnotations) > 0 {
+		annotation := s.PendingGrafanaAnnotations[0]
+		err = grafana.do(http.MethodPost, "/api/annotations", map[string]interface{}{
+			"time": annotation.Time,
+			"tags": []string{GrafanaAnnotationTag, annotation.Reason},
+			"text": annotation.Text,
+		}, nil)
+		if err != nil {
+			r.logger.Error(err, "error writing grafana annotation", "reason", annotation.Reason)
+			return
+		}
+		s.PendingGrafanaAnnotations = s.PendingGrafanaAnnotations[1:]
+	}
+	s.PendingGrafanaAnnotations = nil
+}
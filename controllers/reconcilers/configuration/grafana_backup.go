@@ -0,0 +1,149 @@
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	v12 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Annotation on the CR with the id of a Grafana backup to restore, or latest
+	GrafanaRestoreAnnotation = "observability.redhat.com/restore-grafana-backup"
+	GrafanaRestoreLatest     = "latest"
+)
+
+// Dashboard as exported by the Grafana API, with the title of its folder
+type grafanaBackupDashboard struct {
+	Dashboard map[string]interface{} `json:"dashboard"`
+	Meta      struct {
+		FolderTitle string `json:"folderTitle"`
+	} `json:"meta"`
+}
+
+func (r *Reconciler) getBackupClient(ctx context.Context, cr *v1.Observability) (*s3Client, error) {
+	secret := &v12.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: cr.Spec.Backup.ObjectStorageSecret}, secret)
+	if err != nil {
+		return nil, err
+	}
+	return newS3Client(r.httpClient, secret.Data[model.BackupObjectStorageKey])
+}
+
+// Restore a backup when the restore annotation changes, then export the dashboards when the
+// next backup is due. Only dashboards are backed up, the Grafana database is not reachable
+// through the API.
+func (r *Reconciler) reconcileGrafanaBackup(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	restore := cr.Annotations[GrafanaRestoreAnnotation]
+	due := s.GrafanaBackupTime == 0 || time.Now().After(time.Unix(s.GrafanaBackupTime, 0).Add(model.GetBackupGrafanaInterval(cr)))
+	if (restore == "" || restore == s.GrafanaRestoredBackup) && !due {
+		return nil
+	}
+
+	bucket, err := r.getBackupClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	grafana, err := r.getGrafanaClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	if restore != "" && restore != s.GrafanaRestoredBackup {
+		id := restore
+		if id == GrafanaRestoreLatest {
+			id = s.GrafanaBackup
+		}
+		if id == "" {
+			return fmt.Errorf("no grafana backup to restore")
+		}
+
+		err = r.restoreGrafanaBackup(grafana, bucket, id)
+		if err != nil {
+			return fmt.Errorf("error restoring grafana backup %v: %v", id, err)
+		}
+		r.logger.Info("grafana backup restored", "backup", id)
+		s.GrafanaRestoredBackup = restore
+	}
+
+	if due {
+		id := time.Now().UTC().Format("20060102T150405Z")
+		err = r.backupGrafana(grafana, bucket, id)
+		if err != nil {
+			return fmt.Errorf("error backing up grafana: %v", err)
+		}
+		r.logger.Info("grafana backup created", "backup", id)
+		s.GrafanaBackup = id
+		s.GrafanaBackupTime = time.Now().Unix()
+	}
+
+	return nil
+}
+
+func (r *Reconciler) backupGrafana(grafana *GrafanaClient, bucket *s3Client, id string) error {
+	existing := []grafanaSearchResult{}
+	err := grafana.do(http.MethodGet, "/api/search?type=dash-db", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	var dashboards []grafanaBackupDashboard
+	for _, result := range existing {
+		dashboard := grafanaBackupDashboard{}
+		err = grafana.do(http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%v", result.Uid), nil, &dashboard)
+		if err != nil {
+			return err
+		}
+		dashboards = append(dashboards, dashboard)
+	}
+
+	body, err := json.Marshal(dashboards)
+	if err != nil {
+		return err
+	}
+	return bucket.put(fmt.Sprintf("%v%v.json", model.GrafanaBackupPrefix, id), body)
+}
+
+// Dashboards are imported with their uid, overwriting dashboards that still exist
+func (r *Reconciler) restoreGrafanaBackup(grafana *GrafanaClient, bucket *s3Client, id string) error {
+	body, err := bucket.get(fmt.Sprintf("%v%v.json", model.GrafanaBackupPrefix, id))
+	if err != nil {
+		return err
+	}
+
+	var dashboards []grafanaBackupDashboard
+	err = json.Unmarshal(body, &dashboards)
+	if err != nil {
+		return err
+	}
+
+	for _, dashboard := range dashboards {
+		var folderId int64
+		if title := dashboard.Meta.FolderTitle; title != "" && title != "General" {
+			folder, err := grafana.getOrCreateFolder(title)
+			if err != nil {
+				return err
+			}
+			folderId = folder.Id
+		}
+
+		delete(dashboard.Dashboard, "id")
+		request := map[string]interface{}{
+			"dashboard": dashboard.Dashboard,
+			"folderId":  folderId,
+			"overwrite": true,
+		}
+		err = grafana.do(http.MethodPost, "/api/dashboards/db", request, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
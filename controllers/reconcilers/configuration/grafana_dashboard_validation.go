@@ -0,0 +1,175 @@
+package configuration
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+)
+
+// Dashboards with an older schema use the row layout that predates panels with a grid position
+const MinDashboardSchemaVersion = 16
+
+// Datasources built into Grafana that can always be referenced
+var builtinDatasources = map[string]bool{
+	"default":         true,
+	"-- Grafana --":   true,
+	"-- Mixed --":     true,
+	"-- Dashboard --": true,
+}
+
+// Validates dashboards before they are applied. A broken dashboard breaks the provisioning
+// loop of Grafana, so invalid dashboards are skipped and reported in the status instead.
+// Dashboards are validated in the order of their sources given by the merge strategy, the first
+// dashboard with a uid or title wins and later ones are reported as conflicts.
+type dashboardValidator struct {
+	datasources map[string]bool
+	// Accepted dashboards by uid and by folder and title, to detect dashboards that would
+	// overwrite each other
+	uids      map[string]DashboardInfo
+	titles    map[string]DashboardInfo
+	invalid   []v1.InvalidDashboard
+	conflicts []v1.DashboardConflict
+}
+
+func newDashboardValidator(cr *v1.Observability) *dashboardValidator {
+	datasources := map[string]bool{}
+	for _, name := range model.GetGrafanaDatasourceNames(cr) {
+		datasources[name] = true
+	}
+	return &dashboardValidator{
+		datasources: datasources,
+		uids:        map[string]DashboardInfo{},
+		titles:      map[string]DashboardInfo{},
+	}
+}
+
+// Returns false and records the reason if the dashboard json is invalid or conflicts with an
+// accepted dashboard
+func (v *dashboardValidator) validate(d DashboardInfo, source []byte) bool {
+	dashboard := map[string]interface{}{}
+	err := json.Unmarshal(source, &dashboard)
+	if err == nil {
+		err = v.validateDashboard(dashboard)
+	} else {
+		err = fmt.Errorf("invalid json: %v", err)
+	}
+	if err != nil {
+		v.invalid = append(v.invalid, v1.InvalidDashboard{
+			Name:   d.Name,
+			Reason: err.Error(),
+		})
+		return false
+	}
+
+	uid, _ := dashboard["uid"].(string)
+	if winner, ok := v.uids[uid]; ok && uid != "" {
+		v.addConflict(d, winner, "uid")
+		return false
+	}
+	// Grafana rejects dashboards with the title of another dashboard in the same folder
+	title, _ := dashboard["title"].(string)
+	titleKey := fmt.Sprintf("%s/%s", d.Folder, title)
+	if winner, ok := v.titles[titleKey]; ok && title != "" {
+		v.addConflict(d, winner, "title")
+		return false
+	}
+
+	if uid != "" {
+		v.uids[uid] = d
+	}
+	if title != "" {
+		v.titles[titleKey] = d
+	}
+	return true
+}
+
+func (v *dashboardValidator) addConflict(d DashboardInfo, winner DashboardInfo, field string) {
+	v.conflicts = append(v.conflicts, v1.DashboardConflict{
+		Name:         d.Name,
+		Source:       d.Source,
+		Field:        field,
+		Winner:       winner.Name,
+		WinnerSource: winner.Source,
+	})
+}
+
+func (v *dashboardValidator) validateDashboard(dashboard map[string]interface{}) error {
+	schemaVersion, ok := dashboard["schemaVersion"].(float64)
+	if !ok {
+		return fmt.Errorf("schemaVersion missing")
+	}
+	if schemaVersion < MinDashboardSchemaVersion {
+		return fmt.Errorf("schemaVersion %v is older than %v", schemaVersion, MinDashboardSchemaVersion)
+	}
+
+	if templating, ok := dashboard["templating"].(map[string]interface{}); ok {
+		variables, _ := templating["list"].([]interface{})
+		for _, variable := range variables {
+			if variable, ok := variable.(map[string]interface{}); ok {
+				err := v.validateDatasource(variable["datasource"])
+				if err != nil {
+					return fmt.Errorf("variable %v: %v", variable["name"], err)
+				}
+			}
+		}
+	}
+
+	panels, _ := dashboard["panels"].([]interface{})
+	return v.validatePanels(panels)
+}
+
+func (v *dashboardValidator) validatePanels(panels []interface{}) error {
+	for i, p := range panels {
+		panel, ok := p.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("panel %v is not an object", i)
+		}
+
+		if panelType, _ := panel["type"].(string); panelType == "" {
+			return fmt.Errorf("panel %v has no type", i)
+		}
+
+		err := v.validateDatasource(panel["datasource"])
+		if err != nil {
+			return fmt.Errorf("panel %v: %v", i, err)
+		}
+
+		// Queries of panels using the mixed datasource have their own datasource
+		targets, _ := panel["targets"].([]interface{})
+		for _, t := range targets {
+			if target, ok := t.(map[string]interface{}); ok {
+				err = v.validateDatasource(target["datasource"])
+				if err != nil {
+					return fmt.Errorf("panel %v: %v", i, err)
+				}
+			}
+		}
+
+		// Panels of collapsed rows
+		nested, _ := panel["panels"].([]interface{})
+		err = v.validatePanels(nested)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Datasources are referenced by name, by a template variable or, in newer schemas, by an object
+// with the uid. The operator doesn't assign uids, so only references by name can be checked.
+func (v *dashboardValidator) validateDatasource(ref interface{}) error {
+	switch datasource := ref.(type) {
+	case nil, map[string]interface{}:
+		return nil
+	case string:
+		if datasource == "" || strings.HasPrefix(datasource, "$") || builtinDatasources[datasource] || v.datasources[datasource] {
+			return nil
+		}
+		return fmt.Errorf("datasource %v does not exist", datasource)
+	default:
+		return fmt.Errorf("invalid datasource reference %v", datasource)
+	}
+}
@@ -6,12 +6,11 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
-	"io/ioutil"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"k8s.io/apimachinery/pkg/types"
-	"net/http"
 	url2 "net/url"
+	"path"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"strings"
 )
 
@@ -29,6 +28,9 @@ type DashboardInfo struct {
 	Url         string
 	AccessToken string
 	Tag         string
+	Folder      string
+	// Configuration secret of the repository
+	Source string
 }
 
 func getNameFromUrl(path string) string {
@@ -40,11 +42,11 @@ func getNameFromUrl(path string) string {
 
 func getUniqueDashboards(indexes []v1.RepositoryIndex) []DashboardInfo {
 	var result []DashboardInfo
-seek:
 	for _, index := range indexes {
 		if index.Config == nil || index.Config.Grafana == nil {
 			continue
 		}
+	seek:
 		for _, dashboard := range index.Config.Grafana.Dashboards {
 			name := getNameFromUrl(dashboard)
 			for _, existing := range result {
@@ -57,12 +59,65 @@ seek:
 				Url:         fmt.Sprintf("%s/%s", index.BaseUrl, dashboard),
 				AccessToken: index.AccessToken,
 				Tag:         index.Tag,
+				Folder:      getDashboardFolder(index.Config.Grafana, dashboard),
+				Source:      getIndexSource(&index),
 			})
 		}
 	}
 	return result
 }
 
+// Dashboards of the same name are only applied from the first source, which has the highest
+// priority unless dashboards are merged with LastWins. The others are reported as conflicts
+func getDashboardNameConflicts(indexes []v1.RepositoryIndex) []v1.DashboardConflict {
+	var result []v1.DashboardConflict
+	winners := map[string]string{}
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Grafana == nil {
+			continue
+		}
+		source := getIndexSource(&index)
+		for _, dashboard := range index.Config.Grafana.Dashboards {
+			name := getNameFromUrl(dashboard)
+			winner, ok := winners[name]
+			if !ok {
+				winners[name] = source
+				continue
+			}
+			result = append(result, v1.DashboardConflict{
+				Name:         name,
+				Source:       source,
+				Field:        "name",
+				Winner:       name,
+				WinnerSource: winner,
+			})
+		}
+	}
+	return result
+}
+
+// An explicit folder takes precedence over the directory of the dashboard. Dashboards without
+// a folder end up in the default folder of the Grafana operator.
+func getDashboardFolder(grafana *v1.GrafanaIndex, dashboard string) string {
+	for _, folder := range grafana.Folders {
+		for _, p := range folder.Dashboards {
+			if p == dashboard {
+				return folder.Name
+			}
+		}
+	}
+
+	// The full directory path, dashboards in directories of the same name below different parents
+	// must not end up in the same folder
+	if grafana.FoldersFromDirectories {
+		if dir := path.Dir(strings.TrimPrefix(dashboard, "/")); dir != "." {
+			return dir
+		}
+	}
+
+	return ""
+}
+
 func (r *Reconciler) deleteUnrequestedDashboards(cr *v1.Observability, ctx context.Context, dashboards []DashboardInfo) error {
 	// List existing dashboards
 	existingDashboards := &v1alpha1.GrafanaDashboardList{}
@@ -86,6 +141,14 @@ func (r *Reconciler) deleteUnrequestedDashboards(cr *v1.Observability, ctx conte
 	// Check which dashboards are no longer requested and
 	// delete them
 	for _, dashboard := range existingDashboards.Items {
+		// Owned by the profiles stage
+		if dashboard.Labels[model.ProfileLabel] != "" {
+			continue
+		}
+		// Owned by the alert history stage
+		if dashboard.Name == model.AlertHistoryDashboardName {
+			continue
+		}
 		if isRequested(dashboard.Name) == false {
 			err = r.client.Delete(ctx, &dashboard)
 			if err != nil {
@@ -97,7 +160,15 @@ func (r *Reconciler) deleteUnrequestedDashboards(cr *v1.Observability, ctx conte
 	return nil
 }
 
-func (r *Reconciler) createRequestedDashboards(cr *v1.Observability, ctx context.Context, dashboards []DashboardInfo) error {
+// Invalid dashboards are skipped, an existing dashboard of the same name keeps its last valid
+// version as it is still requested
+func (r *Reconciler) createRequestedDashboards(cr *v1.Observability, ctx context.Context, dashboards []DashboardInfo, s *v1.ObservabilityStatus) error {
+	validator := newDashboardValidator(cr)
+	defer func() {
+		s.InvalidDashboards = validator.invalid
+		s.DashboardConflicts = append(s.DashboardConflicts, validator.conflicts...)
+	}()
+
 	// Create a list of requested dashboards from the external sources provided
 	// in the CR
 	var requestedDashboards []*v1alpha1.GrafanaDashboard
@@ -115,13 +186,27 @@ func (r *Reconciler) createRequestedDashboards(cr *v1.Observability, ctx context
 			if err != nil {
 				return err
 			}
+			if dashboard.Spec.Json != "" && !validator.validate(d, []byte(dashboard.Spec.Json)) {
+				r.logger.Info("skipping invalid dashboard", "dashboard", d.Name)
+				continue
+			}
+			if d.Folder != "" {
+				dashboard.Spec.CustomFolderName = d.Folder
+			}
 			requestedDashboards = append(requestedDashboards, dashboard)
 		case SourceTypeJsonnet:
 		case SourceTypeJson:
+			if !validator.validate(d, source) {
+				r.logger.Info("skipping invalid dashboard", "dashboard", d.Name)
+				continue
+			}
 			dashboard, err := createDashboardFromSource(cr, d.Name, sourceType, source)
 			if err != nil {
 				return err
 			}
+			if d.Folder != "" {
+				dashboard.Spec.CustomFolderName = d.Folder
+			}
 			requestedDashboards = append(requestedDashboards, dashboard)
 		default:
 		}
@@ -133,7 +218,7 @@ func (r *Reconciler) createRequestedDashboards(cr *v1.Observability, ctx context
 		requestedSpec := dashboard.Spec
 		requestedLabels := dashboard.Labels
 
-		_, err := controllerutil.CreateOrUpdate(ctx, r.client, dashboard, func() error {
+		err := r.stageApply(dashboard, func() error {
 			dashboard.Spec = requestedSpec
 			dashboard.Labels = MergeLabels(map[string]string{
 				"managed-by": "observability-operator",
@@ -205,38 +290,11 @@ func (r *Reconciler) fetchDashboard(path string, tag string, token string) (Sour
 		return SourceTypeUnknown, nil, err
 	}
 
-	if token == "" {
-		return SourceTypeUnknown, nil, fmt.Errorf("repository ConfigMap missing required AccessToken")
-	}
-
-	req, err := http.NewRequest(http.MethodGet, url.String(), nil)
-	if err != nil {
-		return SourceTypeUnknown, nil, err
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
-	req.Header.Set("Accept", "application/vnd.github.v3.raw")
-
-	if tag != "" {
-		q := req.URL.Query()
-		q.Add("ref", tag)
-		req.URL.RawQuery = q.Encode()
-	}
-
-	resp, err := r.httpClient.Do(req)
-	if err != nil {
-		return SourceTypeUnknown, nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return SourceTypeUnknown, nil, fmt.Errorf("unexpected status code: %v", resp.StatusCode)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := r.fetchRepositoryFile(path, tag, token)
 	if err != nil {
 		return SourceTypeUnknown, nil, err
 	}
 
 	sourceType := getFileType(url.Path)
-	return sourceType, body, nil
+	return sourceType, renderConfigValues(body, r.values), nil
 }
@@ -0,0 +1,42 @@
+package configuration
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+)
+
+// GrafanaDatasource is a datasource as listed by the Grafana API
+type GrafanaDatasource struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ListDatasources returns the datasources of the organization of the client
+func (c *GrafanaClient) ListDatasources() ([]GrafanaDatasource, error) {
+	var datasources []GrafanaDatasource
+	err := c.do(http.MethodGet, "/api/datasources", nil, &datasources)
+	return datasources, err
+}
+
+// CheckDatasource queries a datasource through the Grafana proxy. Grafana 7 has no health endpoint
+// for datasources, returns false for the types that can't be checked
+func (c *GrafanaClient) CheckDatasource(datasource GrafanaDatasource) (bool, error) {
+	var path string
+	switch datasource.Type {
+	case "prometheus":
+		path = fmt.Sprintf("/api/datasources/proxy/%v/api/v1/query?query=1", datasource.Id)
+	case "loki":
+		path = fmt.Sprintf("/api/datasources/proxy/%v/loki/api/v1/labels", datasource.Id)
+	case "tempo":
+		path = fmt.Sprintf("/api/datasources/proxy/%v/api/echo", datasource.Id)
+	case model.AlertHistoryDatasourceType:
+		path = fmt.Sprintf("/api/datasources/proxy/%v/", datasource.Id)
+	default:
+		return false, nil
+	}
+	_, err := c.doRaw(http.MethodGet, path, nil)
+	return true, err
+}
@@ -0,0 +1,162 @@
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+// Dashboards imported into an external Grafana are tagged, unrequested dashboards with this
+// tag are deleted
+const GrafanaExternalDashboardTag = "observability-operator"
+
+type grafanaSearchResult struct {
+	Uid string `json:"uid"`
+}
+
+// Returns the dashboard model of a json dashboard or a GrafanaDashboard yaml. Jsonnet can't
+// be imported through the API.
+func getExternalDashboardModel(d DashboardInfo, sourceType SourceType, source []byte) (map[string]interface{}, error) {
+	switch sourceType {
+	case SourceTypeYaml:
+		dashboard, err := parseDashboardFromYaml(&v1.Observability{}, d.Name, source)
+		if err != nil {
+			return nil, err
+		}
+		if dashboard.Spec.Json == "" {
+			return nil, nil
+		}
+		source = []byte(dashboard.Spec.Json)
+	case SourceTypeJson:
+	default:
+		return nil, nil
+	}
+
+	dashboard := map[string]interface{}{}
+	err := json.Unmarshal(source, &dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dashboards are matched by uid, the id is assigned by Grafana
+	delete(dashboard, "id")
+	if uid, ok := dashboard["uid"].(string); !ok || uid == "" {
+		dashboard["uid"] = d.Name
+	}
+
+	tags, _ := dashboard["tags"].([]interface{})
+	for _, tag := range tags {
+		if tag == GrafanaExternalDashboardTag {
+			return dashboard, nil
+		}
+	}
+	dashboard["tags"] = append(tags, GrafanaExternalDashboardTag)
+	return dashboard, nil
+}
+
+func (c *GrafanaClient) getOrCreateFolder(title string) (*grafanaFolder, error) {
+	existing := []grafanaFolder{}
+	err := c.do(http.MethodGet, "/api/folders", nil, &existing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, folder := range existing {
+		if folder.Title == title {
+			return &folder, nil
+		}
+	}
+
+	folder := &grafanaFolder{}
+	err = c.do(http.MethodPost, "/api/folders", map[string]string{"title": title}, folder)
+	if err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// Import the requested dashboards into the external Grafana through its API and delete the
+// dashboards that are no longer requested
+func (r *Reconciler) reconcileExternalDashboards(cr *v1.Observability, ctx context.Context, dashboards []DashboardInfo, s *v1.ObservabilityStatus) error {
+	grafana, err := r.getGrafanaClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	validator := newDashboardValidator(cr)
+	defer func() {
+		s.InvalidDashboards = validator.invalid
+		s.DashboardConflicts = append(s.DashboardConflicts, validator.conflicts...)
+	}()
+
+	requested := map[string]bool{}
+	for _, d := range dashboards {
+		sourceType, source, err := r.fetchDashboard(d.Url, d.Tag, d.AccessToken)
+		if err != nil {
+			return err
+		}
+
+		dashboard, err := getExternalDashboardModel(d, sourceType, source)
+		if err != nil {
+			return fmt.Errorf("error parsing dashboard %v: %v", d.Name, err)
+		}
+		if dashboard == nil {
+			r.logger.Info("dashboard can't be imported into the external grafana", "dashboard", d.Name)
+			continue
+		}
+
+		source, err = json.Marshal(dashboard)
+		if err != nil {
+			return err
+		}
+		if !validator.validate(d, source) {
+			// Still requested, so that an existing version is not deleted
+			r.logger.Info("skipping invalid dashboard", "dashboard", d.Name)
+			requested[dashboard["uid"].(string)] = true
+			continue
+		}
+
+		var folderId int64
+		if d.Folder != "" {
+			folder, err := grafana.getOrCreateFolder(d.Folder)
+			if err != nil {
+				return err
+			}
+			folderId = folder.Id
+		}
+
+		body := map[string]interface{}{
+			"dashboard": dashboard,
+			"folderId":  folderId,
+			"overwrite": true,
+		}
+		err = grafana.do(http.MethodPost, "/api/dashboards/db", body, nil)
+		if err != nil {
+			return err
+		}
+		requested[dashboard["uid"].(string)] = true
+	}
+
+	existing := []grafanaSearchResult{}
+	path := fmt.Sprintf("/api/search?type=dash-db&tag=%v", url.QueryEscape(GrafanaExternalDashboardTag))
+	err = grafana.do(http.MethodGet, path, nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	for _, dashboard := range existing {
+		if requested[dashboard.Uid] {
+			continue
+		}
+		err = grafana.do(http.MethodDelete, fmt.Sprintf("/api/dashboards/uid/%v", dashboard.Uid), nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
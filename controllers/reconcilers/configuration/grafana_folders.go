@@ -0,0 +1,281 @@
+package configuration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	v12 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Created by the Grafana operator
+	GrafanaAdminSecretName  = "grafana-admin-credentials"
+	GrafanaAdminUserKey     = "GF_SECURITY_ADMIN_USER"
+	GrafanaAdminPasswordKey = "GF_SECURITY_ADMIN_PASSWORD"
+)
+
+var grafanaPermissions = map[string]int{
+	"View":  1,
+	"Edit":  2,
+	"Admin": 4,
+}
+
+type grafanaFolder struct {
+	Id    int64  `json:"id"`
+	Uid   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+type grafanaTeam struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// GrafanaClient is a minimal client for the Grafana HTTP API, authenticated as the Grafana admin or
+// with the API token of an external Grafana
+type GrafanaClient struct {
+	httpClient *http.Client
+	baseUrl    string
+	user       string
+	password   string
+	token      string
+	orgId      int64
+}
+
+func getUniqueFolders(indexes []v1.RepositoryIndex) []v1.GrafanaFolderIndex {
+	var result []v1.GrafanaFolderIndex
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Grafana == nil {
+			continue
+		}
+	seek:
+		for _, folder := range index.Config.Grafana.Folders {
+			for _, existing := range result {
+				if existing.Name == folder.Name {
+					continue seek
+				}
+			}
+			result = append(result, folder)
+		}
+	}
+	return result
+}
+
+// Folders are created by the Grafana operator when the first dashboard is imported into them.
+// Permissions of folders that don't exist yet are set during the next sync.
+func (r *Reconciler) reconcileGrafanaFolderPermissions(cr *v1.Observability, ctx context.Context, folders []v1.GrafanaFolderIndex) error {
+	hasPermissions := false
+	for _, folder := range folders {
+		if len(folder.Permissions) > 0 {
+			hasPermissions = true
+			break
+		}
+	}
+	if !hasPermissions {
+		return nil
+	}
+
+	grafana, err := r.getGrafanaClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	existing := []grafanaFolder{}
+	err = grafana.do(http.MethodGet, "/api/folders", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	for _, folder := range folders {
+		if len(folder.Permissions) == 0 {
+			continue
+		}
+
+		var found *grafanaFolder
+		for i := range existing {
+			if existing[i].Title == folder.Name {
+				found = &existing[i]
+				break
+			}
+		}
+		if found == nil {
+			r.logger.Info("grafana folder does not exist yet, skipping permissions", "folder", folder.Name)
+			continue
+		}
+
+		err = r.setGrafanaFolderPermissions(grafana, found, folder.Permissions)
+		if err != nil {
+			return fmt.Errorf("error setting permissions of grafana folder %v: %v", folder.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Replaces all permissions of the folder with the requested ones
+func (r *Reconciler) setGrafanaFolderPermissions(grafana *GrafanaClient, folder *grafanaFolder, permissions []v1.GrafanaFolderPermission) error {
+	type item struct {
+		TeamId     int64  `json:"teamId,omitempty"`
+		Role       string `json:"role,omitempty"`
+		Permission int    `json:"permission"`
+	}
+
+	var items []item
+	for _, permission := range permissions {
+		level, ok := grafanaPermissions[permission.Permission]
+		if !ok {
+			return fmt.Errorf("unknown permission %v", permission.Permission)
+		}
+
+		if permission.Team != "" {
+			team, err := grafana.getOrCreateTeam(permission.Team)
+			if err != nil {
+				return err
+			}
+			items = append(items, item{TeamId: team.Id, Permission: level})
+		} else if permission.Role != "" {
+			items = append(items, item{Role: permission.Role, Permission: level})
+		}
+	}
+
+	body := struct {
+		Items []item `json:"items"`
+	}{
+		Items: items,
+	}
+
+	return grafana.do(http.MethodPost, fmt.Sprintf("/api/folders/%v/permissions", folder.Uid), body, nil)
+}
+
+func (r *Reconciler) getGrafanaClient(ctx context.Context, cr *v1.Observability) (*GrafanaClient, error) {
+	return NewGrafanaClient(ctx, r.client, r.httpClient, cr)
+}
+
+// NewGrafanaClient returns a client of the Grafana of the CR, the managed one or the external one
+// configured in the CR
+func NewGrafanaClient(ctx context.Context, c client.Client, httpClient *http.Client, cr *v1.Observability) (*GrafanaClient, error) {
+	if external := model.GetGrafanaExternal(cr); external != nil {
+		secret := &v12.Secret{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: external.TokenSecret}, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		return &GrafanaClient{
+			httpClient: httpClient,
+			baseUrl:    strings.TrimSuffix(external.URL, "/"),
+			token:      string(secret.Data[model.GrafanaExternalTokenKey]),
+			orgId:      external.OrgID,
+		}, nil
+	}
+
+	secret := &v12.Secret{}
+	selector := client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      GrafanaAdminSecretName,
+	}
+
+	err := c.Get(ctx, selector, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GrafanaClient{
+		httpClient: httpClient,
+		baseUrl:    fmt.Sprintf("http://grafana-service.%s:3000", cr.Namespace),
+		user:       string(secret.Data[GrafanaAdminUserKey]),
+		password:   string(secret.Data[GrafanaAdminPasswordKey]),
+	}, nil
+}
+
+// Teams referenced in folder permissions are created if they don't exist yet
+func (c *GrafanaClient) getOrCreateTeam(name string) (*grafanaTeam, error) {
+	search := struct {
+		Teams []grafanaTeam `json:"teams"`
+	}{}
+
+	err := c.do(http.MethodGet, fmt.Sprintf("/api/teams/search?name=%v", url.QueryEscape(name)), nil, &search)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, team := range search.Teams {
+		if team.Name == name {
+			return &team, nil
+		}
+	}
+
+	created := struct {
+		TeamId int64 `json:"teamId"`
+	}{}
+
+	err = c.do(http.MethodPost, "/api/teams", map[string]string{"name": name}, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grafanaTeam{Id: created.TeamId, Name: name}, nil
+}
+
+func (c *GrafanaClient) do(method string, path string, body interface{}, result interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	respBody, err := c.doRaw(method, path, payload)
+	if err != nil {
+		return err
+	}
+
+	if result != nil {
+		return json.Unmarshal(respBody, result)
+	}
+	return nil
+}
+
+// Sends a request and returns the response body as it is, e.g. a rendered image
+func (c *GrafanaClient) doRaw(method string, path string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, fmt.Sprintf("%v%v", c.baseUrl, path), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", c.token))
+	} else {
+		req.SetBasicAuth(c.user, c.password)
+	}
+	if c.orgId != 0 {
+		req.Header.Set("X-Grafana-Org-Id", fmt.Sprintf("%v", c.orgId))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from grafana for %v %v: %v", method, path, resp.Status)
+	}
+	return respBody, nil
+}
@@ -0,0 +1,71 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// Pod annotation to restart Grafana when the image renderer is enabled or disabled
+	GrafanaImageRendererAnnotation = "observability-operator/grafana-image-renderer"
+	// The renderer runs in the Grafana pod and calls back into Grafana to load the panels
+	GrafanaImageRendererPort        = 8081
+	GrafanaImageRendererServerUrl   = "http://localhost:8081/render"
+	GrafanaImageRendererCallbackUrl = "http://localhost:3000/"
+)
+
+// Write the urls of the image renderer into the config map Grafana reads GF_RENDERING_SERVER_URL
+// and GF_RENDERING_CALLBACK_URL from. Returns a hash that changes when the renderer is toggled.
+func (r *Reconciler) reconcileGrafanaImageRenderer(ctx context.Context, cr *v1.Observability) (string, error) {
+	configMap := model.GetGrafanaImageRendererConfigMap(cr)
+
+	if !cr.GrafanaImageRendererEnabled() {
+		err := r.client.Delete(ctx, configMap)
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		return "", nil
+	}
+
+	err := utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		configMap.Data = map[string]string{
+			"GF_RENDERING_SERVER_URL":   GrafanaImageRendererServerUrl,
+			"GF_RENDERING_CALLBACK_URL": GrafanaImageRendererCallbackUrl,
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return "enabled", nil
+}
+
+// Sidecar of Grafana that renders panels to images with a headless browser
+func getGrafanaImageRendererContainer(cr *v1.Observability) v12.Container {
+	return v12.Container{
+		Name:  "grafana-image-renderer",
+		Image: model.GetImage(cr, v1.ImageGrafanaImageRenderer, model.GrafanaImageRendererImage),
+		Env: []v12.EnvVar{
+			{
+				Name:  "HTTP_PORT",
+				Value: fmt.Sprintf("%v", GrafanaImageRendererPort),
+			},
+		},
+		Ports: []v12.ContainerPort{
+			{
+				Name:          "image-renderer",
+				ContainerPort: GrafanaImageRendererPort,
+			},
+		},
+	}
+}
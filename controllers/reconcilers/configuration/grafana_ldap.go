@@ -0,0 +1,128 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Keys of the secret referenced in spec.grafana.auth.ldap.bindSecret
+	GrafanaLDAPBindDNKey   = "bindDN"
+	GrafanaLDAPPasswordKey = "password"
+	// Key of the LDAP configuration in the secret mounted into Grafana
+	GrafanaLDAPConfigKey = "ldap.toml"
+	// The Grafana operator mounts the secrets of the Grafana CR below /etc/grafana-secrets
+	GrafanaLDAPConfigDir = "/etc/grafana-secrets/"
+	// Pod annotation to restart Grafana when the LDAP configuration changes, it is only read on startup
+	GrafanaLDAPAnnotation = "observability-operator/grafana-ldap"
+	// Active Directory login
+	GrafanaLDAPDefaultSearchFilter = "(sAMAccountName=%s)"
+)
+
+// Render the LDAP configuration of Grafana, including the bind credentials, into the secret mounted into
+// Grafana. Returns a hash of the configuration.
+func (r *Reconciler) reconcileGrafanaLDAP(ctx context.Context, cr *v1.Observability) (string, error) {
+	secret := model.GetGrafanaLDAPSecret(cr)
+	ldap := model.GetGrafanaLDAP(cr)
+
+	if ldap == nil {
+		err := r.client.Delete(ctx, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		return "", nil
+	}
+
+	credentials := &v12.Secret{}
+	selector := client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      ldap.BindSecret,
+	}
+	err := r.client.Get(ctx, selector, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := getGrafanaLDAPConfig(ldap, string(credentials.Data[GrafanaLDAPBindDNKey]), string(credentials.Data[GrafanaLDAPPasswordKey]))
+	if err != nil {
+		return "", err
+	}
+
+	err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Data = map[string][]byte{
+			GrafanaLDAPConfigKey: []byte(config),
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(config))
+	return fmt.Sprintf("%x", hash.Sum(nil))[:12], nil
+}
+
+func getGrafanaLDAPConfigFile(cr *v1.Observability) string {
+	return GrafanaLDAPConfigDir + model.GetGrafanaLDAPSecret(cr).Name + "/" + GrafanaLDAPConfigKey
+}
+
+// Renders the ldap.toml of Grafana. The attributes are the ones of Active Directory
+func getGrafanaLDAPConfig(ldap *v1.GrafanaLDAP, bindDN string, password string) (string, error) {
+	host, port, err := net.SplitHostPort(ldap.Server)
+	if err != nil {
+		return "", err
+	}
+	searchFilter := ldap.SearchFilter
+	if searchFilter == "" {
+		searchFilter = GrafanaLDAPDefaultSearchFilter
+	}
+	var searchBaseDNs []string
+	for _, dn := range ldap.SearchBaseDNs {
+		searchBaseDNs = append(searchBaseDNs, quoteToml(dn))
+	}
+
+	var config strings.Builder
+	config.WriteString("[[servers]]\n")
+	fmt.Fprintf(&config, "host = %v\n", quoteToml(host))
+	fmt.Fprintf(&config, "port = %v\n", port)
+	fmt.Fprintf(&config, "use_ssl = %v\n", ldap.UseSSL)
+	fmt.Fprintf(&config, "start_tls = %v\n", ldap.StartTLS)
+	fmt.Fprintf(&config, "ssl_skip_verify = %v\n", ldap.SkipVerify)
+	fmt.Fprintf(&config, "bind_dn = %v\n", quoteToml(bindDN))
+	fmt.Fprintf(&config, "bind_password = %v\n", quoteToml(password))
+	fmt.Fprintf(&config, "search_filter = %v\n", quoteToml(searchFilter))
+	fmt.Fprintf(&config, "search_base_dns = [%v]\n", strings.Join(searchBaseDNs, ", "))
+	config.WriteString(`
+[servers.attributes]
+name = "givenName"
+surname = "sn"
+username = "sAMAccountName"
+member_of = "memberOf"
+email = "mail"
+`)
+	for _, mapping := range ldap.GroupMappings {
+		config.WriteString("\n[[servers.group_mappings]]\n")
+		fmt.Fprintf(&config, "group_dn = %v\n", quoteToml(mapping.GroupDN))
+		fmt.Fprintf(&config, "org_role = %v\n", quoteToml(mapping.Role))
+		fmt.Fprintf(&config, "grafana_admin = %v\n", mapping.GrafanaAdmin)
+	}
+	return config.String(), nil
+}
+
+// TOML basic string, DNs and passwords may contain backslashes, quotes or a trailing newline
+var tomlEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func quoteToml(value string) string {
+	return `"` + tomlEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,180 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Keys of the secret referenced in spec.grafana.smtp.credentialsSecret
+	GrafanaSmtpUserKey     = "user"
+	GrafanaSmtpPasswordKey = "password"
+	// Key of the secret referenced in slackWebhookSecret
+	GrafanaSlackWebhookKey = "url"
+	// Pod annotation to restart Grafana when the SMTP credentials change
+	GrafanaSmtpAnnotation = "observability-operator/grafana-smtp"
+	// Notification channels with this uid prefix are managed by the operator
+	GrafanaContactPointUidPrefix = "observability-"
+)
+
+type grafanaNotificationChannel struct {
+	Uid            string            `json:"uid"`
+	Name           string            `json:"name"`
+	Type           string            `json:"type"`
+	IsDefault      bool              `json:"isDefault"`
+	SendReminder   bool              `json:"sendReminder"`
+	Frequency      string            `json:"frequency,omitempty"`
+	Settings       map[string]string `json:"settings"`
+	SecureSettings map[string]string `json:"secureSettings,omitempty"`
+}
+
+// Copy the SMTP credentials into the secret Grafana reads GF_SMTP_USER and GF_SMTP_PASSWORD from.
+// Returns a hash of the credentials.
+func (r *Reconciler) reconcileGrafanaSmtp(ctx context.Context, cr *v1.Observability) (string, error) {
+	secret := model.GetGrafanaSmtpSecret(cr)
+	smtp := model.GetGrafanaSmtp(cr)
+
+	if smtp == nil || smtp.CredentialsSecret == "" {
+		err := r.client.Delete(ctx, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		return "", nil
+	}
+
+	credentials := &v12.Secret{}
+	selector := client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      smtp.CredentialsSecret,
+	}
+	err := r.client.Get(ctx, selector, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		secret.Data = map[string][]byte{
+			"GF_SMTP_USER":     credentials.Data[GrafanaSmtpUserKey],
+			"GF_SMTP_PASSWORD": credentials.Data[GrafanaSmtpPasswordKey],
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(secret.Data["GF_SMTP_USER"])
+	hash.Write(secret.Data["GF_SMTP_PASSWORD"])
+	return fmt.Sprintf("%x", hash.Sum(nil))[:12], nil
+}
+
+func (r *Reconciler) getGrafanaNotificationChannel(ctx context.Context, cr *v1.Observability, contactPoint v1.GrafanaContactPoint) (*grafanaNotificationChannel, error) {
+	channel := &grafanaNotificationChannel{
+		Uid:       GrafanaContactPointUidPrefix + contactPoint.Name,
+		Name:      contactPoint.Name,
+		Type:      contactPoint.Type,
+		IsDefault: contactPoint.Default,
+		Settings:  map[string]string{},
+	}
+
+	if contactPoint.ReminderInterval != "" {
+		channel.SendReminder = true
+		channel.Frequency = contactPoint.ReminderInterval
+	}
+
+	switch contactPoint.Type {
+	case v1.GrafanaContactPointEmail:
+		channel.Settings["addresses"] = strings.Join(contactPoint.Addresses, ";")
+	case v1.GrafanaContactPointSlack:
+		secret := &v12.Secret{}
+		selector := client.ObjectKey{
+			Namespace: cr.Namespace,
+			Name:      contactPoint.SlackWebhookSecret,
+		}
+		err := r.client.Get(ctx, selector, secret)
+		if err != nil {
+			return nil, err
+		}
+		channel.Settings["recipient"] = contactPoint.SlackChannel
+		channel.SecureSettings = map[string]string{
+			"url": string(secret.Data[GrafanaSlackWebhookKey]),
+		}
+	}
+
+	return channel, nil
+}
+
+// Provision the contact points of the CR as Grafana notification channels. Channels of contact points
+// that were removed from the CR are deleted.
+func (r *Reconciler) reconcileGrafanaContactPoints(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	contactPoints := model.GetGrafanaContactPoints(cr)
+	if len(contactPoints) == 0 && len(s.GrafanaContactPoints) == 0 {
+		return nil
+	}
+
+	grafana, err := r.getGrafanaClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	existing := []grafanaNotificationChannel{}
+	err = grafana.do(http.MethodGet, "/api/alert-notifications", nil, &existing)
+	if err != nil {
+		return err
+	}
+
+	requested := map[string]bool{}
+	var provisioned []string
+	for _, contactPoint := range contactPoints {
+		channel, err := r.getGrafanaNotificationChannel(ctx, cr, contactPoint)
+		if err != nil {
+			return fmt.Errorf("error reading grafana contact point %v: %v", contactPoint.Name, err)
+		}
+		requested[channel.Uid] = true
+
+		found := false
+		for _, e := range existing {
+			if e.Uid == channel.Uid {
+				found = true
+				break
+			}
+		}
+
+		if found {
+			err = grafana.do(http.MethodPut, fmt.Sprintf("/api/alert-notifications/uid/%v", channel.Uid), channel, nil)
+		} else {
+			err = grafana.do(http.MethodPost, "/api/alert-notifications", channel, nil)
+		}
+		if err != nil {
+			return err
+		}
+		provisioned = append(provisioned, contactPoint.Name)
+	}
+
+	for _, channel := range existing {
+		if !strings.HasPrefix(channel.Uid, GrafanaContactPointUidPrefix) || requested[channel.Uid] {
+			continue
+		}
+		err = grafana.do(http.MethodDelete, fmt.Sprintf("/api/alert-notifications/uid/%v", channel.Uid), nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.GrafanaContactPoints = provisioned
+	return nil
+}
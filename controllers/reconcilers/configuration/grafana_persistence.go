@@ -0,0 +1,106 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Keys of the secret referenced in spec.grafana.persistence.database.credentialsSecret
+	GrafanaDatabaseUserKey     = "user"
+	GrafanaDatabasePasswordKey = "password"
+	// Pod annotation to restart Grafana when the database credentials change, they are only read on startup
+	GrafanaDatabaseAnnotation = "observability-operator/grafana-database"
+)
+
+// Copy the credentials of the external database into the secret Grafana reads its environment from.
+// Returns a hash of the credentials.
+func (r *Reconciler) reconcileGrafanaDatabase(ctx context.Context, cr *v1.Observability) (string, error) {
+	secret := model.GetGrafanaDatabaseSecret(cr)
+
+	if !cr.GrafanaDatabaseEnabled() {
+		err := r.client.Delete(ctx, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		return "", nil
+	}
+
+	credentials := &v12.Secret{}
+	selector := client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      cr.Spec.Grafana.Persistence.Database.CredentialsSecret,
+	}
+	err := r.client.Get(ctx, selector, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		secret.Data = map[string][]byte{
+			"GF_DATABASE_USER":     credentials.Data[GrafanaDatabaseUserKey],
+			"GF_DATABASE_PASSWORD": credentials.Data[GrafanaDatabasePasswordKey],
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(secret.Data["GF_DATABASE_USER"])
+	hash.Write(secret.Data["GF_DATABASE_PASSWORD"])
+	return fmt.Sprintf("%x", hash.Sum(nil))[:12], nil
+}
+
+// The Grafana operator creates a claim for the data directory of Grafana, which holds the SQLite
+// database, or Grafana connects to the external database
+func setGrafanaPersistence(cr *v1.Observability, grafana *v1alpha1.Grafana) error {
+	if cr.GrafanaVolumeEnabled() {
+		volume := cr.Spec.Grafana.Persistence.Volume
+		size, err := resource.ParseQuantity(volume.Size)
+		if err != nil {
+			return fmt.Errorf("invalid grafana volume size: %v", volume.Size)
+		}
+		grafana.Spec.DataStorage = &v1alpha1.GrafanaDataStorage{
+			AccessModes: []v12.PersistentVolumeAccessMode{v12.ReadWriteOnce},
+			Size:        size,
+		}
+		if volume.StorageClass != nil {
+			grafana.Spec.DataStorage.Class = *volume.StorageClass
+		}
+	}
+
+	if cr.GrafanaDatabaseEnabled() {
+		database := cr.Spec.Grafana.Persistence.Database
+		grafana.Spec.Config.Database = &v1alpha1.GrafanaConfigDatabase{
+			Type:    database.Type,
+			Host:    database.Host,
+			Name:    database.Name,
+			SslMode: database.SSLMode,
+		}
+		optional := true
+		grafana.Spec.Deployment.EnvFrom = append(grafana.Spec.Deployment.EnvFrom, v12.EnvFromSource{
+			SecretRef: &v12.SecretEnvSource{
+				LocalObjectReference: v12.LocalObjectReference{
+					Name: model.GetGrafanaDatabaseSecret(cr).Name,
+				},
+				Optional: &optional,
+			},
+		})
+	}
+	return nil
+}
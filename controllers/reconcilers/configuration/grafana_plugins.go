@@ -0,0 +1,103 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+)
+
+const (
+	// Environment variable read by the Grafana image to install plugins on startup
+	GrafanaInstallPluginsKey = "GF_INSTALL_PLUGINS"
+	// Pod annotation to restart Grafana when the plugins change
+	GrafanaPluginsAnnotation = "observability-operator/grafana-plugins"
+	GrafanaPluginDownloadUrl = "https://grafana.com/api/plugins/%s/versions/%s/download"
+)
+
+func getGrafanaPluginKey(plugin v1.GrafanaPlugin) string {
+	return fmt.Sprintf("%s:%s:%s", plugin.Name, plugin.Version, plugin.Checksum)
+}
+
+// Download the plugin archive and compare its sha256 with the pinned checksum. Plugins without a
+// checksum are not verified.
+func (r *Reconciler) verifyGrafanaPlugin(s *v1.ObservabilityStatus, plugin v1.GrafanaPlugin) (bool, error) {
+	if plugin.Checksum == "" {
+		return true, nil
+	}
+
+	for _, verified := range s.VerifiedGrafanaPlugins {
+		if verified == getGrafanaPluginKey(plugin) {
+			return true, nil
+		}
+	}
+
+	resp, err := r.httpClient.Get(fmt.Sprintf(GrafanaPluginDownloadUrl, plugin.Name, plugin.Version))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code when downloading grafana plugin %v: %v", plugin.Name, resp.StatusCode)
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, resp.Body)
+	if err != nil {
+		return false, err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)) == plugin.Checksum, nil
+}
+
+// Render the requested plugins into the config map Grafana reads GF_INSTALL_PLUGINS from. With a
+// plugin bundle nothing is downloaded, the bundle provides the plugins through the init container.
+// Returns a hash of the installed plugins.
+func (r *Reconciler) reconcileGrafanaPlugins(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, indexes []v1.RepositoryIndex) (string, error) {
+	bundle, offline := model.GetImageOverride(cr, v1.ImageGrafanaPluginBundle)
+
+	var install []string
+	var verified []string
+	if !offline {
+		for _, plugin := range model.GetGrafanaPlugins(cr, indexes) {
+			ok, err := r.verifyGrafanaPlugin(s, plugin)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				r.logger.Info("checksum mismatch, skipping grafana plugin", "plugin", plugin.Name, "version", plugin.Version)
+				r.recorder.Eventf(cr, v12.EventTypeWarning, v1.EventPluginRejected, "checksum of grafana plugin %v %v does not match", plugin.Name, plugin.Version)
+				continue
+			}
+			if plugin.Checksum != "" {
+				verified = append(verified, getGrafanaPluginKey(plugin))
+			}
+			install = append(install, fmt.Sprintf("%s %s", plugin.Name, plugin.Version))
+		}
+	}
+	s.VerifiedGrafanaPlugins = verified
+
+	configMap := model.GetGrafanaPluginsConfigMap(cr)
+	err := utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		configMap.Data = map[string]string{
+			GrafanaInstallPluginsKey: strings.Join(install, ","),
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(bundle + configMap.Data[GrafanaInstallPluginsKey]))
+	return fmt.Sprintf("%x", hash)[:12], nil
+}
@@ -0,0 +1,301 @@
+package configuration
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	v12 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const smtpDialTimeout = 10 * time.Second
+
+// Deliver the reports of which a scheduled run has passed since their last run. Missed runs, e.g.
+// while the operator was down, are delivered once. Failed deliveries are retried with the next
+// reconcile and don't fail the stage.
+func (r *Reconciler) reconcileReports(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) {
+	var result []v1.ReportStatus
+	for _, report := range cr.Spec.Reports {
+		// New reports wait for their first scheduled run
+		status := v1.ReportStatus{Name: report.Name, Time: time.Now().Unix()}
+		for _, existing := range s.Reports {
+			if existing.Name == report.Name {
+				status = existing
+				break
+			}
+		}
+
+		schedule, err := v1.ParseSchedule(report.Schedule)
+		if err != nil {
+			result = append(result, status)
+			continue
+		}
+		scheduled := schedule.Next(time.Unix(status.Time, 0))
+		if scheduled.IsZero() || time.Now().Before(scheduled) {
+			result = append(result, status)
+			continue
+		}
+
+		err = r.deliverReport(ctx, cr, report, scheduled)
+		if err != nil {
+			if status.Error != err.Error() {
+				r.recorder.Eventf(cr, v12.EventTypeWarning, v1.EventReportFailed, "report %v could not be delivered: %v", report.Name, err)
+			}
+			r.logger.Error(err, "error delivering report", "report", report.Name)
+			status.Error = err.Error()
+		} else {
+			r.logger.Info("report delivered", "report", report.Name)
+			status.Time = time.Now().Unix()
+			status.Error = ""
+		}
+		result = append(result, status)
+	}
+	s.Reports = result
+}
+
+func (r *Reconciler) deliverReport(ctx context.Context, cr *v1.Observability, report v1.Report, scheduled time.Time) error {
+	grafana, err := r.getGrafanaClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	content, err := grafana.renderDashboard(report.Dashboard, report.TimeRange)
+	if err != nil {
+		return err
+	}
+
+	format := model.GetReportFormat(report)
+	contentType := "image/png"
+	if format == "pdf" {
+		content, err = convertImageToPDF(content)
+		if err != nil {
+			return err
+		}
+		contentType = "application/pdf"
+	}
+	filename := fmt.Sprintf("%v-%v.%v", report.Name, scheduled.Format("20060102T1504Z"), format)
+
+	if len(report.Recipients) > 0 {
+		err = r.sendReport(ctx, cr, report, filename, contentType, content)
+		if err != nil {
+			return fmt.Errorf("error sending email: %v", err)
+		}
+	}
+
+	if report.ObjectStorageSecret != "" {
+		secret := &v12.Secret{}
+		err = r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: report.ObjectStorageSecret}, secret)
+		if err != nil {
+			return err
+		}
+		bucket, err := newS3Client(r.httpClient, secret.Data[model.BackupObjectStorageKey])
+		if err != nil {
+			return err
+		}
+		err = bucket.put(fmt.Sprintf("%v%v/%v", model.ReportPrefix, report.Name, filename), content)
+		if err != nil {
+			return fmt.Errorf("error uploading report: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Renders the whole dashboard to a PNG with the image renderer, in kiosk mode without the menus
+func (c *GrafanaClient) renderDashboard(uid string, timeRange string) ([]byte, error) {
+	query := url.Values{}
+	query.Set("width", fmt.Sprintf("%v", model.ReportWidth))
+	query.Set("height", "-1")
+	query.Set("kiosk", "true")
+	if timeRange != "" {
+		query.Set("from", fmt.Sprintf("now-%v", timeRange))
+		query.Set("to", "now")
+	}
+	return c.doRaw(http.MethodGet, fmt.Sprintf("/render/d/%v?%v", url.PathEscape(uid), query.Encode()), nil)
+}
+
+// Sends the report through the SMTP server Grafana sends its notifications with
+func (r *Reconciler) sendReport(ctx context.Context, cr *v1.Observability, report v1.Report, filename string, contentType string, content []byte) error {
+	config := model.GetGrafanaSmtp(cr)
+	if config == nil {
+		return fmt.Errorf("grafana smtp is not configured")
+	}
+
+	var auth smtp.Auth
+	host, _, err := net.SplitHostPort(config.Host)
+	if err != nil {
+		return err
+	}
+	if config.CredentialsSecret != "" {
+		credentials := &v12.Secret{}
+		err = r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: config.CredentialsSecret}, credentials)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", string(credentials.Data[GrafanaSmtpUserKey]), string(credentials.Data[GrafanaSmtpPasswordKey]), host)
+	}
+
+	message, err := buildReportMessage(config, report, filename, contentType, content)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", config.Host, smtpDialTimeout)
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		err = c.StartTLS(&tls.Config{ServerName: host, InsecureSkipVerify: config.SkipVerify})
+		if err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		err = c.Auth(auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.Mail(config.FromAddress)
+	if err != nil {
+		return err
+	}
+	for _, recipient := range report.Recipients {
+		err = c.Rcpt(recipient)
+		if err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(message)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Builds a multipart email with the report attached
+func buildReportMessage(config *v1.GrafanaSmtp, report v1.Report, filename string, contentType string, content []byte) ([]byte, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	from := mail.Address{Name: config.FromName, Address: config.FromAddress}
+	fmt.Fprintf(body, "From: %v\r\n", from.String())
+	fmt.Fprintf(body, "To: %v\r\n", strings.Join(report.Recipients, ", "))
+	fmt.Fprintf(body, "Subject: Report %v\r\n", report.Name)
+	fmt.Fprintf(body, "Date: %v\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	fmt.Fprintf(body, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(body, "Content-Type: multipart/mixed; boundary=%v\r\n\r\n", writer.Boundary())
+
+	text, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/plain; charset=utf-8"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(text, "Report %v of dashboard %v is attached.\r\n", report.Name, report.Dashboard)
+
+	attachment, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", filename)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		fmt.Fprintf(attachment, "%v\r\n", encoded[:76])
+		encoded = encoded[76:]
+	}
+	fmt.Fprintf(attachment, "%v\r\n", encoded)
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// Wraps a PNG into a single page PDF. The image is embedded as a JPEG, which PDF readers decode
+// natively, and the page has the size of the image.
+func convertImageToPDF(content []byte) ([]byte, error) {
+	img, err := png.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+
+	encoded := &bytes.Buffer{}
+	err = jpeg.Encode(encoded, flattenImage(img), &jpeg.Options{Quality: 90})
+	if err != nil {
+		return nil, err
+	}
+
+	drawing := fmt.Sprintf("q %v 0 0 %v 0 0 cm /Im0 Do Q", width, height)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %v %v] /Resources << /XObject << /Im0 4 0 R >> >> /Contents 5 0 R >>", width, height),
+		fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %v /Height %v /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %v >>\nstream\n%s\nendstream",
+			width, height, encoded.Len(), encoded.Bytes()),
+		fmt.Sprintf("<< /Length %v >>\nstream\n%v\nendstream", len(drawing), drawing),
+	}
+
+	pdf := &bytes.Buffer{}
+	pdf.WriteString("%PDF-1.4\n")
+	var offsets []int
+	for i, object := range objects {
+		offsets = append(offsets, pdf.Len())
+		fmt.Fprintf(pdf, "%v 0 obj\n%v\nendobj\n", i+1, object)
+	}
+	xref := pdf.Len()
+	fmt.Fprintf(pdf, "xref\n0 %v\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(pdf, "trailer\n<< /Size %v /Root 1 0 R >>\nstartxref\n%v\n%%%%EOF\n", len(objects)+1, xref)
+	return pdf.Bytes(), nil
+}
+
+// JPEG has no alpha channel, transparent areas of the dashboard are drawn on white
+func flattenImage(img image.Image) image.Image {
+	bounds := img.Bounds()
+	result := image.NewRGBA(bounds)
+	draw.Draw(result, bounds, image.White, image.Point{}, draw.Src)
+	draw.Draw(result, bounds, img, bounds.Min, draw.Over)
+	return result
+}
@@ -0,0 +1,108 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Pod annotation to restart Grafana when the sharing policy changes, the environment is only read on startup
+const GrafanaSharingAnnotation = "observability-operator/grafana-sharing"
+
+// Write the settings of the sharing policy the Grafana CR has no fields for into the config map
+// Grafana reads its environment from, and record the policy in the status. Returns a hash of the
+// settings.
+func (r *Reconciler) reconcileGrafanaSharing(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (string, error) {
+	configMap := model.GetGrafanaSharingConfigMap(cr)
+	sharing := getGrafanaSharing(cr)
+
+	s.GrafanaSharing = &v1.GrafanaSharingStatus{
+		Snapshots:        !sharing.DisableSnapshots,
+		PublicDashboards: !sharing.DisablePublicDashboards,
+		ExternalSharing:  !sharing.DisableSnapshots && !sharing.DisableExternalSharing,
+	}
+	if sharing.SnapshotServer != nil {
+		s.GrafanaSharing.SnapshotServer = sharing.SnapshotServer.Url
+	}
+
+	if cr.Spec.Grafana == nil || cr.Spec.Grafana.Sharing == nil {
+		err := r.client.Delete(ctx, configMap)
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		return "", nil
+	}
+
+	data := map[string]string{
+		"GF_SNAPSHOTS_ENABLED":         fmt.Sprintf("%v", s.GrafanaSharing.Snapshots),
+		"GF_PUBLIC_DASHBOARDS_ENABLED": fmt.Sprintf("%v", s.GrafanaSharing.PublicDashboards),
+	}
+	err := utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		configMap.Data = data
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var keys []string
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%v=%v\n", key, data[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func getGrafanaSharing(cr *v1.Observability) *v1.GrafanaSharing {
+	if cr.Spec.Grafana == nil || cr.Spec.Grafana.Sharing == nil {
+		return &v1.GrafanaSharing{}
+	}
+	return cr.Spec.Grafana.Sharing
+}
+
+// Render the sharing policy into grafana.ini and the environment of the Grafana deployment
+func setGrafanaSharing(cr *v1.Observability, grafana *v1alpha1.Grafana) {
+	if cr.Spec.Grafana == nil || cr.Spec.Grafana.Sharing == nil {
+		return
+	}
+	sharing := cr.Spec.Grafana.Sharing
+
+	external := !sharing.DisableSnapshots && !sharing.DisableExternalSharing
+	grafana.Spec.Config.Snapshots = &v1alpha1.GrafanaConfigSnapshots{
+		ExternalEnabled: &external,
+	}
+	if sharing.SnapshotServer != nil {
+		grafana.Spec.Config.Snapshots.ExternalSnapshotUrl = strings.TrimSuffix(sharing.SnapshotServer.Url, "/")
+		grafana.Spec.Config.Snapshots.ExternalSnapshotName = sharing.SnapshotServer.Name
+	}
+	if sharing.DisableExternalSharing {
+		embedding := false
+		grafana.Spec.Config.Security = &v1alpha1.GrafanaConfigSecurity{
+			AllowEmbedding: &embedding,
+		}
+	}
+
+	grafana.Spec.Deployment.EnvFrom = append(grafana.Spec.Deployment.EnvFrom, v12.EnvFromSource{
+		ConfigMapRef: &v12.ConfigMapEnvSource{
+			LocalObjectReference: v12.LocalObjectReference{
+				Name: model.GetGrafanaSharingConfigMap(cr).Name,
+			},
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package configuration
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	v12 "k8s.io/api/core/v1"
+)
+
+// Mount path of the certificate of a component in the containers of Prometheus and Alertmanager
+const internalTLSMountPath = "/etc/tls/internal"
+
+// kube-rbac-proxy sidecar of Prometheus and Alertmanager that only lets clients with a certificate of
+// the internal CA through to the local port of the component. The Prometheus operator mounts the
+// secret and config map listed in the CR as volumes named after them.
+func getInternalTLSProxy(cr *v1.Observability, component string, upstreamPort int) v12.Container {
+	secret := model.GetInternalTLSSecretName(component)
+	configMap := model.GetInternalTLSProxyConfigMap(cr).Name
+
+	return v12.Container{
+		Name:  "mtls-proxy",
+		Image: model.GetImage(cr, v1.ImageKubeRbacProxy, model.KubeRbacProxyImage),
+		Args: append([]string{
+			fmt.Sprintf("--secure-listen-address=%v", model.GetListenAddress(cr, model.InternalTLSPort)),
+			fmt.Sprintf("--upstream=http://127.0.0.1:%d/", upstreamPort),
+			fmt.Sprintf("--config-file=/etc/kube-rbac-proxy/%v.yaml", component),
+			fmt.Sprintf("--tls-cert-file=%v/%v", internalTLSMountPath, v12.TLSCertKey),
+			fmt.Sprintf("--tls-private-key-file=%v/%v", internalTLSMountPath, v12.TLSPrivateKeyKey),
+			fmt.Sprintf("--client-ca-file=%v/ca.crt", internalTLSMountPath),
+			"--tls-min-version=VersionTLS12",
+		}, model.GetKubeRbacProxyFIPSArgs(cr)...),
+		Ports: []v12.ContainerPort{
+			{
+				Name:          model.InternalTLSPortName,
+				ContainerPort: model.InternalTLSPort,
+			},
+		},
+		VolumeMounts: []v12.VolumeMount{
+			{
+				Name:      fmt.Sprintf("secret-%v", secret),
+				MountPath: internalTLSMountPath,
+				ReadOnly:  true,
+			},
+			{
+				Name:      fmt.Sprintf("configmap-%v", configMap),
+				MountPath: "/etc/kube-rbac-proxy",
+				ReadOnly:  true,
+			},
+		},
+	}
+}
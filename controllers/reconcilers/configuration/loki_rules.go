@@ -0,0 +1,400 @@
+package configuration
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/observatorium_tenant"
+	"github.com/redhat-developer/observability-operator/v3/controllers/token"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Rule files in the format of the Loki ruler, which is the format of Prometheus rule files with
+// LogQL expressions
+type lokiRuleFile struct {
+	Groups []lokiRuleGroup `json:"groups"`
+}
+
+type lokiRuleGroup struct {
+	Name     string     `json:"name"`
+	Interval string     `json:"interval,omitempty"`
+	Limit    int        `json:"limit,omitempty"`
+	Rules    []lokiRule `json:"rules"`
+}
+
+type lokiRule struct {
+	Alert       string            `json:"alert,omitempty"`
+	Record      string            `json:"record,omitempty"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Rules API of a Loki ruler or of the Loki tenant of Observatorium
+type lokiRulerClient struct {
+	httpClient *http.Client
+	baseUrl    string
+	tenant     string
+	token      string
+}
+
+func getUniqueLokiRules(indexes []v1.RepositoryIndex) []ResourceInfo {
+	var result []ResourceInfo
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Loki == nil {
+			continue
+		}
+	seek:
+		for _, rule := range index.Config.Loki.Rules {
+			name := getNameFromUrl(rule)
+			for _, existing := range result {
+				if existing.Name == name {
+					continue seek
+				}
+			}
+			result = append(result, ResourceInfo{
+				Id:          index.Id,
+				Name:        name,
+				Url:         fmt.Sprintf("%s/%s", index.BaseUrl, rule),
+				AccessToken: index.AccessToken,
+				Tag:         index.Tag,
+				Source:      getIndexSource(&index),
+			})
+		}
+	}
+	return result
+}
+
+// Every rule file is a namespace of the ruler. The prefix tells the namespaces of the CR apart from
+// those created by others, e.g. other clusters sharing the tenant
+func getLokiRuleNamespacePrefix(cr *v1.Observability) string {
+	return fmt.Sprintf("observability-%v-", cr.Namespace)
+}
+
+func parseLokiRuleFile(data []byte) (*lokiRuleFile, error) {
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	decoder.DisallowUnknownFields()
+
+	file := &lokiRuleFile{}
+	err = decoder.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return file, validateLokiRuleFile(file)
+}
+
+// The ruler rejects a namespace with an invalid group only when it evaluates it, so the files are
+// validated before they are pushed. The LogQL expressions are checked by the ruler
+func validateLokiRuleFile(file *lokiRuleFile) error {
+	if len(file.Groups) == 0 {
+		return fmt.Errorf("no rule groups")
+	}
+	names := map[string]bool{}
+	for _, group := range file.Groups {
+		if group.Name == "" {
+			return fmt.Errorf("rule group without a name")
+		}
+		if names[group.Name] {
+			return fmt.Errorf("duplicate rule group %v", group.Name)
+		}
+		names[group.Name] = true
+
+		if group.Interval != "" && !v1.IsValidPrometheusDuration(group.Interval) {
+			return fmt.Errorf("invalid interval of rule group %v: %v", group.Name, group.Interval)
+		}
+		if len(group.Rules) == 0 {
+			return fmt.Errorf("rule group %v has no rules", group.Name)
+		}
+		for i, rule := range group.Rules {
+			if (rule.Alert == "") == (rule.Record == "") {
+				return fmt.Errorf("rule %v of group %v requires either alert or record", i, group.Name)
+			}
+			if strings.TrimSpace(rule.Expr) == "" {
+				return fmt.Errorf("rule %v of group %v has no expression", i, group.Name)
+			}
+			if rule.For != "" && (rule.Record != "" || !v1.IsValidPrometheusDuration(rule.For)) {
+				return fmt.Errorf("invalid for of rule %v of group %v: %v", i, group.Name, rule.For)
+			}
+		}
+	}
+	return nil
+}
+
+// Rule files of the config maps selected by spec.logs.rules, named after the config map and the key
+func (r *Reconciler) getLokiRuleConfigMaps(ctx context.Context, cr *v1.Observability) (map[string][]byte, error) {
+	result := map[string][]byte{}
+	if cr.Spec.Logs == nil || cr.Spec.Logs.Rules == nil || cr.Spec.Logs.Rules.ConfigMapSelector == nil {
+		return result, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(cr.Spec.Logs.Rules.ConfigMapSelector)
+	if err != nil {
+		return nil, err
+	}
+	list := &v12.ConfigMapList{}
+	err = r.client.List(ctx, list, &client.ListOptions{
+		LabelSelector: selector,
+		Namespace:     cr.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, configMap := range list.Items {
+		for key, data := range configMap.Data {
+			name := fmt.Sprintf("%v-%v", configMap.Name, strings.TrimSuffix(key, path.Ext(key)))
+			result[name] = []byte(data)
+		}
+	}
+	return result, nil
+}
+
+// Returns the client of the configured ruler, or of the Observatorium tenant. Nil if there is neither
+func (r *Reconciler) getLokiRulerClient(ctx context.Context, cr *v1.Observability) (*lokiRulerClient, error) {
+	if cr.Spec.Logs != nil && cr.Spec.Logs.Rules != nil && cr.Spec.Logs.Rules.Ruler != nil {
+		ruler := cr.Spec.Logs.Rules.Ruler
+		c := &lokiRulerClient{
+			httpClient: &http.Client{
+				Timeout: 30 * time.Second,
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: ruler.InsecureSkipVerify},
+				},
+			},
+			baseUrl: strings.TrimSuffix(ruler.URL, "/"),
+			tenant:  ruler.Tenant,
+		}
+		if ruler.TokenSecret != "" {
+			secret := &v12.Secret{}
+			err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: ruler.TokenSecret}, secret)
+			if err != nil {
+				return nil, err
+			}
+			c.token = string(secret.Data[model.PromtailClientToken])
+		}
+		return c, nil
+	}
+
+	if !cr.HasObservatoriumTenant() {
+		return nil, nil
+	}
+	tenant := cr.Spec.Observatorium.Tenant
+	secret := &v12.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: tenant.CredentialsSecret}, secret)
+	if err != nil {
+		return nil, err
+	}
+	tokenEndpoint, err := token.GetTokenEndpoint(r.httpClient, tenant.OIDCIssuerUrl)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := token.FetchClientCredentialsToken(r.httpClient, tokenEndpoint,
+		string(secret.Data[observatorium_tenant.CredentialsClientId]),
+		string(secret.Data[observatorium_tenant.CredentialsClientSecret]))
+	if err != nil {
+		return nil, err
+	}
+	return &lokiRulerClient{
+		httpClient: r.httpClient,
+		baseUrl:    fmt.Sprintf("%v/api/logs/v1/%v", strings.TrimSuffix(tenant.Gateway, "/"), tenant.Tenant),
+		token:      accessToken,
+	}, nil
+}
+
+func (c *lokiRulerClient) do(method string, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, fmt.Sprintf("%v/loki/api/v1/rules%v", c.baseUrl, path), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", c.token))
+	}
+	if c.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", c.tenant)
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ruler answers 404 when the tenant has no rules yet
+	if method == http.MethodGet && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected response from the loki ruler for %v %v: %v", method, path, resp.Status)
+	}
+	return respBody, nil
+}
+
+// Groups of the rule namespaces of the ruler
+func (c *lokiRulerClient) list() (map[string][]lokiRuleGroup, error) {
+	body, err := c.do(http.MethodGet, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string][]lokiRuleGroup{}
+	err = yaml.Unmarshal(body, &result)
+	return result, err
+}
+
+// Loki recording and alerting rules of the repositories and of the config maps of spec.logs.rules.
+// Valid rule files are pushed to the ruler, invalid ones are reported in the status. Groups of the
+// CR that are no longer requested are deleted from the ruler
+func (r *Reconciler) reconcileLokiRules(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, indexes []v1.RepositoryIndex) error {
+	files, err := r.getLokiRuleConfigMaps(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if !cr.ExternalSyncDisabled() {
+		for _, rule := range getUniqueLokiRules(orderIndexes(indexes, cr.RuleMergeStrategy())) {
+			if _, ok := files[rule.Name]; ok {
+				continue
+			}
+			data, err := r.fetchResource(rule.Url, rule.Tag, rule.AccessToken)
+			if err != nil {
+				return err
+			}
+			files[rule.Name] = data
+		}
+	}
+
+	// Invalid files are requested without groups, the groups last pushed for them stay in place
+	s.InvalidLogRules = nil
+	requested := map[string]*lokiRuleFile{}
+	for name, data := range files {
+		file, err := parseLokiRuleFile(data)
+		if err != nil {
+			s.InvalidLogRules = append(s.InvalidLogRules, v1.InvalidLogRule{Name: name, Reason: err.Error()})
+			file = nil
+		}
+		requested[getLokiRuleNamespacePrefix(cr)+name] = file
+	}
+	sort.Slice(s.InvalidLogRules, func(i, j int) bool {
+		return s.InvalidLogRules[i].Name < s.InvalidLogRules[j].Name
+	})
+
+	ruler, err := r.getLokiRulerClient(ctx, cr)
+	if err == nil && ruler == nil {
+		if len(files) == 0 {
+			meta.RemoveStatusCondition(&s.Conditions, v1.LogRulesSynced)
+			return nil
+		}
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.LogRulesSynced,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoRuler",
+			Message: "loki rules require spec.logs.rules.ruler or an observatorium tenant",
+		})
+		return nil
+	}
+
+	// An unavailable ruler does not hold back the rest of the configuration
+	if err == nil {
+		err = r.pushLokiRules(cr, ruler, requested)
+	}
+	if err != nil {
+		r.logger.Info("error pushing loki rules", "error", err.Error())
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.LogRulesSynced,
+			Status:  metav1.ConditionFalse,
+			Reason:  "RulerUnavailable",
+			Message: err.Error(),
+		})
+		return nil
+	}
+
+	if len(s.InvalidLogRules) > 0 {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.LogRulesSynced,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidRules",
+			Message: fmt.Sprintf("%v rule files failed validation and were not pushed", len(s.InvalidLogRules)),
+		})
+		return nil
+	}
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:   v1.LogRulesSynced,
+		Status: metav1.ConditionTrue,
+		Reason: "RulesPushed",
+	})
+	return nil
+}
+
+func (r *Reconciler) pushLokiRules(cr *v1.Observability, ruler *lokiRulerClient, requested map[string]*lokiRuleFile) error {
+	existing, err := ruler.list()
+	if err != nil {
+		return err
+	}
+
+	// Groups are replaced one at a time, the ruler has no call to replace a namespace
+	for namespace, file := range requested {
+		if file == nil {
+			continue
+		}
+		for _, group := range file.Groups {
+			body, err := yaml.Marshal(group)
+			if err != nil {
+				return err
+			}
+			_, err = ruler.do(http.MethodPost, "/"+url.PathEscape(namespace), body)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for namespace, groups := range existing {
+		if !strings.HasPrefix(namespace, getLokiRuleNamespacePrefix(cr)) {
+			continue
+		}
+		file, ok := requested[namespace]
+		if ok && file == nil {
+			continue
+		}
+		keep := map[string]bool{}
+		if ok {
+			for _, group := range file.Groups {
+				keep[group.Name] = true
+			}
+		}
+		for _, group := range groups {
+			if keep[group.Name] {
+				continue
+			}
+			_, err = ruler.do(http.MethodDelete, fmt.Sprintf("/%v/%v", url.PathEscape(namespace), url.PathEscape(group.Name)), nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+)
+
+// Author of the silences of the mute time intervals
+const MuteTimeIntervalCreator = "observability-operator"
+
+type silenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+}
+
+type silence struct {
+	Matchers  []silenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+}
+
+// Keeps a silence for the current or next window of every mute time interval. A new silence is
+// created once the window of the last one ended or the interval changed, silences of changed or
+// removed intervals are expired
+func (r *Reconciler) reconcileMuteTimeIntervals(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) {
+	var intervals []v1.MuteTimeInterval
+	if cr.Spec.Alerting != nil {
+		intervals = cr.Spec.Alerting.MuteTimeIntervals
+	}
+
+	// Like the config verification, silences are created with the service account token
+	token, err := ioutil.ReadFile(ServiceAccountTokenPath)
+	if err != nil {
+		r.logger.Info("skipping mute time intervals, no service account token found")
+		return
+	}
+	service := model.GetAlertmanagerService(cr)
+	baseUrl := fmt.Sprintf("https://%v.%v.svc:9091", service.Name, cr.Namespace)
+
+	previous := map[string]v1.MuteTimeIntervalStatus{}
+	for _, status := range s.MuteTimeIntervals {
+		previous[status.Name] = status
+	}
+
+	now := time.Now()
+	var result []v1.MuteTimeIntervalStatus
+	for _, interval := range intervals {
+		windows, err := v1.ParseMuteWindows(&interval)
+		if err != nil {
+			result = append(result, v1.MuteTimeIntervalStatus{
+				Name:  interval.Name,
+				Error: err.Error(),
+			})
+			continue
+		}
+
+		status, ok := previous[interval.Name]
+		delete(previous, interval.Name)
+		hash := getMuteTimeIntervalHash(&interval)
+		if ok && status.SilenceID != "" && status.EndsAt > now.Unix() && status.Hash == hash {
+			result = append(result, status)
+			continue
+		}
+		start, end := windows.Next(now)
+		// The silence of a changed interval is replaced
+		if ok && status.SilenceID != "" && status.EndsAt > now.Unix() {
+			r.expireSilence(baseUrl, string(token), status)
+		}
+
+		status = v1.MuteTimeIntervalStatus{
+			Name:     interval.Name,
+			Hash:     hash,
+			StartsAt: start.Unix(),
+			EndsAt:   end.Unix(),
+		}
+		status.SilenceID, err = r.createSilence(baseUrl, string(token), &interval, start, end)
+		if err != nil {
+			r.logger.Error(err, "error creating silence of mute time interval", "interval", interval.Name)
+			status.Error = err.Error()
+		}
+		result = append(result, status)
+	}
+
+	for _, status := range previous {
+		if status.SilenceID != "" && status.EndsAt > now.Unix() {
+			r.expireSilence(baseUrl, string(token), status)
+		}
+	}
+
+	s.MuteTimeIntervals = result
+}
+
+// The silence of an interval is kept until its window ends unless the interval changed, so a
+// silence of joined windows is not replaced when the window of its first day ends
+func getMuteTimeIntervalHash(interval *v1.MuteTimeInterval) string {
+	spec, _ := json.Marshal(interval)
+	return fmt.Sprintf("%x", sha256.Sum256(spec))[:12]
+}
+
+func (r *Reconciler) createSilence(baseUrl string, token string, interval *v1.MuteTimeInterval, start time.Time, end time.Time) (string, error) {
+	var names []string
+	for name := range interval.Match {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var matchers []silenceMatcher
+	for _, name := range names {
+		matchers = append(matchers, silenceMatcher{
+			Name:  name,
+			Value: interval.Match[name],
+		})
+	}
+
+	payload, err := json.Marshal(silence{
+		Matchers:  matchers,
+		StartsAt:  start,
+		EndsAt:    end,
+		CreatedBy: MuteTimeIntervalCreator,
+		Comment:   fmt.Sprintf("mute time interval %v", interval.Name),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	body, code, err := r.alertmanagerRequest(http.MethodPost, fmt.Sprintf("%v/api/v2/silences", baseUrl), token, payload)
+	if err != nil {
+		return "", err
+	}
+	if code != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code from alertmanager: %v: %v", code, strings.TrimSpace(string(body)))
+	}
+
+	response := struct {
+		SilenceID string `json:"silenceID"`
+	}{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return "", err
+	}
+	return response.SilenceID, nil
+}
+
+// Silences that were expired by someone else can't be expired again, failures are only logged
+func (r *Reconciler) expireSilence(baseUrl string, token string, status v1.MuteTimeIntervalStatus) {
+	body, code, err := r.alertmanagerRequest(http.MethodDelete, fmt.Sprintf("%v/api/v2/silence/%v", baseUrl, status.SilenceID), token, nil)
+	if err == nil && code != http.StatusOK {
+		err = fmt.Errorf("unexpected status code from alertmanager: %v: %v", code, strings.TrimSpace(string(body)))
+	}
+	if err != nil {
+		r.logger.Error(err, "error expiring silence of mute time interval", "interval", status.Name, "silence", status.SilenceID)
+	}
+}
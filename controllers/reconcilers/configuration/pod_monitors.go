@@ -6,8 +6,9 @@ import (
 	"github.com/ghodss/yaml"
 	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strconv"
 )
 
 func MergeLabels(requested map[string]string, existing map[string]string) map[string]string {
@@ -58,10 +59,13 @@ func (r *Reconciler) deleteUnrequestedPodMonitors(cr *v1.Observability, ctx cont
 		return err
 	}
 
+	shards := model.GetPrometheusShards(cr)
 	isRequested := func(name string) bool {
 		for _, monitor := range monitors {
-			if name == monitor.Name {
-				return true
+			for shard := int32(0); shard < shards; shard++ {
+				if name == model.GetShardedPodMonitorName(monitor.Name, shard) {
+					return true
+				}
 			}
 		}
 		return false
@@ -69,6 +73,10 @@ func (r *Reconciler) deleteUnrequestedPodMonitors(cr *v1.Observability, ctx cont
 
 	// Check which pod monitors are no longer requested and delete them
 	for _, monitor := range existingMonitors.Items {
+		// Owned by the profiles stage
+		if monitor.Labels[model.ProfileLabel] != "" {
+			continue
+		}
 		if isRequested(monitor.Name) == false {
 			err = r.client.Delete(ctx, monitor)
 			if err != nil {
@@ -88,28 +96,50 @@ func (r *Reconciler) createRequestedPodMonitors(cr *v1.Observability, ctx contex
 			return err
 		}
 
-		monitor, err := parsePodMonitorFromYaml(cr, resource.Name, bytes)
-		if err != nil {
-			return err
-		}
+		// With shards, every shard scrapes its own copy of the pod monitor
+		shards := model.GetPrometheusShards(cr)
+		for shard := int32(0); shard < shards; shard++ {
+			monitor, err := parsePodMonitorFromYaml(cr, resource.Name, bytes)
+			if err != nil {
+				return err
+			}
+			monitor.Name = model.GetShardedPodMonitorName(monitor.Name, shard)
 
-		requestedLabels := monitor.Labels
-		requestedSpec := monitor.Spec
+			requestedLabels := monitor.Labels
+			requestedSpec := monitor.Spec
+			if shards > 1 {
+				shardPodMonitor(&requestedSpec, shard, shards)
+			}
 
-		_, err = controllerutil.CreateOrUpdate(ctx, r.client, monitor, func() error {
-			monitor.Spec = requestedSpec
-			monitor.Labels = MergeLabels(map[string]string{
-				"managed-by": "observability-operator",
-			}, requestedLabels)
-			return nil
-		})
-		if err != nil {
-			return err
+			err = r.stageApply(monitor, func() error {
+				monitor.Spec = requestedSpec
+				monitor.Labels = MergeLabels(map[string]string{
+					"managed-by": "observability-operator",
+				}, requestedLabels)
+				if shards > 1 {
+					monitor.Labels[model.PrometheusShardLabel] = strconv.Itoa(int(shard))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// Restrict the endpoints of a pod monitor copy to the targets of the shard
+func shardPodMonitor(spec *v12.PodMonitorSpec, shard int32, shards int32) {
+	endpoints := make([]v12.PodMetricsEndpoint, len(spec.PodMetricsEndpoints))
+	for i, endpoint := range spec.PodMetricsEndpoints {
+		endpoint.RelabelConfigs = append(append([]*v12.RelabelConfig{}, endpoint.RelabelConfigs...),
+			model.GetPrometheusShardRelabelings(shard, shards)...)
+		endpoints[i] = endpoint
+	}
+	spec.PodMetricsEndpoints = endpoints
+}
+
 func parsePodMonitorFromYaml(cr *v1.Observability, name string, source []byte) (*v12.PodMonitor, error) {
 	monitor := &v12.PodMonitor{}
 	err := yaml.Unmarshal(source, monitor)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 
 	"github.com/ghodss/yaml"
 	errors2 "github.com/pkg/errors"
@@ -15,17 +16,19 @@ import (
 	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	"github.com/sirupsen/logrus"
 	kv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
 	PrometheusBaseImage       = "quay.io/prometheus/prometheus"
 	PrometheusRetention       = "45d"
+	PrometheusAgentRetention  = "1d"
 	OpenshiftVersionToCompare = "4.10.0"
 )
 
@@ -84,7 +87,7 @@ func (r *Reconciler) createBlackBoxConfig(cr *v1.Observability, ctx context.Cont
 		return hash, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, configMap, func() error {
+	err = utils.Apply(ctx, r.client, configMap, func() error {
 		configMap.Data = map[string]string{
 			"black-box-config.yaml": string(cfg),
 		}
@@ -98,39 +101,44 @@ func (r *Reconciler) createBlackBoxConfig(cr *v1.Observability, ctx context.Cont
 func (r *Reconciler) createAdditionalScrapeConfigSecret(cr *v1.Observability, ctx context.Context, patterns []string) error {
 	secret := model.GetPrometheusAdditionalScrapeConfig(cr)
 
-	user, password, err := r.getOpenshiftMonitoringCredentials(ctx)
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
 	if err != nil {
 		return err
 	}
 
-	federationConfig, err := model.GetFederationConfig(user, password, patterns)
-	if err != nil {
-		return err
+	// Federation from openshift-monitoring is only possible on OpenShift
+	var federationConfig []byte
+	if capabilities.IsOpenShift() {
+		user, password, err := r.getOpenshiftMonitoringCredentials(ctx, capabilities.OpenShiftVersion)
+		if err != nil {
+			return err
+		}
+
+		federationConfig, err = model.GetFederationConfig(user, password, patterns)
+		if err != nil {
+			return err
+		}
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+	scrapeConfig := append(federationConfig, model.GetPrometheusSelfScrapeConfig()...)
+	scrapeConfig = append(scrapeConfig, model.GetPromtailScrapeConfig(cr)...)
+	scrapeConfig = append(scrapeConfig, model.GetSelfMonitoringScrapeConfig(cr)...)
+	scrapeConfig = append(scrapeConfig, model.GetQueryLogScrapeConfig(cr)...)
+
+	return r.applyConfigSecret(ctx, secret, func() error {
 		secret.Type = kv1.SecretTypeOpaque
-		secret.StringData = map[string]string{
-			"additional-scrape-config.yaml": string(federationConfig),
+		secret.Data = map[string][]byte{
+			"additional-scrape-config.yaml": scrapeConfig,
 		}
 		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
-func (r *Reconciler) getOpenshiftMonitoringCredentials(ctx context.Context) (string, string, error) {
+func (r *Reconciler) getOpenshiftMonitoringCredentials(ctx context.Context, currentOSVersionString string) (string, string, error) {
 	secret := &kv1.Secret{}
 	var isDatasourceV2 bool
+	var err error
 
-	currentOSVersionString, err := utils.GetClusterOSVersion(ctx, r.client)
-	if err != nil {
-		return "", "", err
-	}
 	//this checks if Openshift 4.10+ is present as grafana-datasources secret data in v4.10+ has different structure to previous versions
 	isDatasourceV2, err = utils.HasNewerOrSameClusterVersion(currentOSVersionString, OpenshiftVersionToCompare)
 	if err != nil {
@@ -241,10 +249,15 @@ func (r *Reconciler) getRemoteWriteSpecForRedHat(cr *v1.Observability, index v1.
 	tokenRefresherName := model.GetTokenRefresherName(observatoriumConfig.Id, model.MetricsTokenRefresher)
 	tokenRefresherUrl := fmt.Sprintf("http://%v.%v.svc.cluster.local", tokenRefresherName, cr.Namespace)
 
+	remoteTimeout := remoteWrite.RemoteTimeout
+	if timeout := model.GetTokenRefresherRequestTimeout(cr); timeout != "" {
+		remoteTimeout = timeout
+	}
+
 	return &prometheusv1.RemoteWriteSpec{
 		URL:                 tokenRefresherUrl,
 		Name:                index.Id,
-		RemoteTimeout:       remoteWrite.RemoteTimeout,
+		RemoteTimeout:       remoteTimeout,
 		WriteRelabelConfigs: remoteWrite.WriteRelabelConfigs,
 		TLSConfig: &prometheusv1.TLSConfig{
 			SafeTLSConfig: prometheusv1.SafeTLSConfig{
@@ -252,7 +265,7 @@ func (r *Reconciler) getRemoteWriteSpecForRedHat(cr *v1.Observability, index v1.
 			},
 		},
 		ProxyURL:    remoteWrite.ProxyUrl,
-		QueueConfig: remoteWrite.QueueConfig,
+		QueueConfig: model.GetTokenRefresherQueueConfig(cr, remoteWrite.QueueConfig),
 	}, "", nil
 }
 
@@ -280,6 +293,29 @@ func (r *Reconciler) getAlerting(cr *v1.Observability) *prometheusv1.AlertingSpe
 	alertmanager := model.GetAlertmanagerCr(cr)
 	alertmanagerService := model.GetAlertmanagerService(cr)
 
+	// Prometheus authenticates with its certificate at the mTLS sidecar of Alertmanager
+	if model.IsInternalTLSEnabled(cr) {
+		certDir := fmt.Sprintf("/etc/prometheus/secrets/%v", model.GetInternalTLSSecretName(model.InternalTLSPrometheus))
+		return &prometheusv1.AlertingSpec{
+			Alertmanagers: []prometheusv1.AlertmanagerEndpoints{
+				{
+					Namespace: cr.Namespace,
+					Name:      alertmanager.Name,
+					Port:      intstr.FromString(model.InternalTLSPortName),
+					Scheme:    "https",
+					TLSConfig: &prometheusv1.TLSConfig{
+						CAFile:   fmt.Sprintf("%v/ca.crt", certDir),
+						CertFile: fmt.Sprintf("%v/%v", certDir, kv1.TLSCertKey),
+						KeyFile:  fmt.Sprintf("%v/%v", certDir, kv1.TLSPrivateKeyKey),
+						SafeTLSConfig: prometheusv1.SafeTLSConfig{
+							ServerName: fmt.Sprintf("%v.%v.svc", alertmanagerService.Name, cr.Namespace),
+						},
+					},
+				},
+			},
+		}
+	}
+
 	return &prometheusv1.AlertingSpec{
 		Alertmanagers: []prometheusv1.AlertmanagerEndpoints{
 			{
@@ -299,7 +335,7 @@ func (r *Reconciler) getAlerting(cr *v1.Observability) *prometheusv1.AlertingSpe
 	}
 }
 
-func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, configHash string) error {
+func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, indexes []v1.RepositoryIndex, configHash string, suspended []string) error {
 	proxySecret := model.GetPrometheusProxySecret(cr)
 	sa := model.GetPrometheusServiceAccount(cr)
 
@@ -309,19 +345,35 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 		Name:      route.Name,
 	}
 
-	err := r.client.Get(ctx, selector, route)
-	if err != nil && !errors.IsNotFound(err) {
+	accessType, err := utils.GetUIAccessType(ctx, r.client, cr)
+	if err != nil {
 		return err
 	}
 
 	host := ""
-	if utils.IsRouteReady(route) {
-		host = route.Spec.Host
+	if accessType == v1.UIAccessIngress {
+		host = model.GetPrometheusHost(cr)
+	} else {
+		err = r.client.Get(ctx, selector, route)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+		if utils.IsRouteReady(route) {
+			host = route.Spec.Host
+		}
+	}
+
+	tlsSecret, openshift, err := utils.GetUIProxyTLSSecret(ctx, r.client, cr, model.InternalTLSPrometheus, "prometheus-k8s-tls")
+	if err != nil {
+		return err
 	}
 
 	var secrets []string
 	secrets = append(secrets, proxySecret.Name)
-	secrets = append(secrets, "prometheus-k8s-tls")
+	// Otherwise the internal TLS secret is added below
+	if openshift {
+		secrets = append(secrets, tlsSecret)
+	}
 
 	var remoteWrites []prometheusv1.RemoteWriteSpec
 	var sidecars []kv1.Container
@@ -347,61 +399,74 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 		}
 	}
 
-	var image = fmt.Sprintf("%s:%s", PrometheusBaseImage, model.GetPrometheusVersion(cr))
-
-	sidecars = append(sidecars, kv1.Container{
-		Name:  "oauth-proxy",
-		Image: "quay.io/openshift/origin-oauth-proxy:4.8",
-		Args: []string{
-			"-provider=openshift",
-			"-https-address=:9091",
-			"-http-address=",
-			"-email-domain=*",
-			"-upstream=http://localhost:9090",
-			fmt.Sprintf("-openshift-service-account=%v", sa.Name),
-			"-openshift-sar={\"resource\": \"namespaces\", \"verb\": \"get\"}",
-			"-openshift-delegate-urls={\"/\": {\"resource\": \"namespaces\", \"verb\": \"get\"}}",
-			"-tls-cert=/etc/tls/private/tls.crt",
-			"-tls-key=/etc/tls/private/tls.key",
-			"-client-secret-file=/var/run/secrets/kubernetes.io/serviceaccount/token",
-			"-cookie-secret-file=/etc/proxy/secrets/session_secret",
-			"-openshift-ca=/etc/pki/tls/cert.pem",
-			"-openshift-ca=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
-			"-skip-auth-regex=^/metrics",
-		},
-		Env: []kv1.EnvVar{
-			{
-				Name: "HTTP_PROXY",
-			},
-			{
-				Name: "HTTPS_PROXY",
-			},
-			{
-				Name: "NO_PROXY",
+	var configMaps []string
+	if model.IsInternalTLSEnabled(cr) {
+		secrets = append(secrets, model.GetInternalTLSSecretName(model.InternalTLSPrometheus))
+		configMaps = append(configMaps, model.GetInternalTLSProxyConfigMap(cr).Name)
+		sidecars = append(sidecars, getInternalTLSProxy(cr, model.InternalTLSPrometheus, 9090))
+	}
+
+	var image = model.GetImage(cr, v1.ImagePrometheus, fmt.Sprintf("%s:%s", PrometheusBaseImage, model.GetPrometheusVersion(cr)))
+
+	var oauthProxy kv1.Container
+	if openshift {
+		oauthProxy = kv1.Container{
+			Name:  "oauth-proxy",
+			Image: model.GetImage(cr, v1.ImageOAuthProxy, model.OAuthProxyImage),
+			Args: []string{
+				"-provider=openshift",
+				"-https-address=:9091",
+				"-http-address=",
+				"-email-domain=*",
+				"-upstream=http://localhost:9090",
+				fmt.Sprintf("-openshift-service-account=%v", sa.Name),
+				fmt.Sprintf("-openshift-sar=%v", model.GetOAuthProxySAR(cr, model.GetPrometheusService(cr).Name)),
+				"-openshift-delegate-urls={\"/\": {\"resource\": \"namespaces\", \"verb\": \"get\"}}",
+				"-tls-cert=/etc/tls/private/tls.crt",
+				"-tls-key=/etc/tls/private/tls.key",
+				"-client-secret-file=/var/run/secrets/kubernetes.io/serviceaccount/token",
+				"-cookie-secret-file=/etc/proxy/secrets/session_secret",
+				"-openshift-ca=/etc/pki/tls/cert.pem",
+				"-openshift-ca=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+				"-skip-auth-regex=^/metrics",
 			},
-		},
-		Ports: []kv1.ContainerPort{
-			{
-				Name:          "proxy",
-				ContainerPort: 9091,
+			Env: []kv1.EnvVar{
+				{
+					Name: "HTTP_PROXY",
+				},
+				{
+					Name: "HTTPS_PROXY",
+				},
+				{
+					Name: "NO_PROXY",
+				},
 			},
-		},
-		VolumeMounts: []kv1.VolumeMount{
-			{
-				Name:      "secret-prometheus-k8s-tls",
-				MountPath: "/etc/tls/private",
+			Ports: []kv1.ContainerPort{
+				{
+					Name:          "proxy",
+					ContainerPort: 9091,
+				},
 			},
-			{
-				Name:      fmt.Sprintf("secret-%v", proxySecret.Name),
-				MountPath: "/etc/proxy/secrets",
+			VolumeMounts: []kv1.VolumeMount{
+				{
+					Name:      fmt.Sprintf("secret-%v", tlsSecret),
+					MountPath: "/etc/tls/private",
+				},
+				{
+					Name:      fmt.Sprintf("secret-%v", proxySecret.Name),
+					MountPath: "/etc/proxy/secrets",
+				},
 			},
-		},
-	})
+		}
+	} else {
+		oauthProxy = model.GetOIDCProxyContainer(cr, "oauth-proxy", "proxy", "http://localhost:9090", tlsSecret, proxySecret.Name)
+	}
+	sidecars = append(sidecars, oauthProxy)
 
 	if !cr.BlackboxExporterDisabled() {
 		sidecars = append(sidecars, kv1.Container{
 			Name:  "blackbox-exporter",
-			Image: "quay.io/prometheus/blackbox-exporter:v0.19.0",
+			Image: model.GetImage(cr, v1.ImageBlackboxExporter, model.BlackboxExporterImage),
 			Args: []string{
 				"--config.file=/opt/config/black-box-config.yaml",
 			},
@@ -429,8 +494,10 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 			},
 		})
 	}
-	prometheus := model.GetPrometheus(cr)
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, prometheus, func() error {
+	shards := model.GetPrometheusShards(cr)
+	prometheus := model.GetPrometheusShard(cr, 0)
+	err = utils.Apply(ctx, r.client, prometheus, func() error {
+		existing := prometheus.Spec.DeepCopy()
 		cr.Labels = map[string]string{
 			"app": "prometheus",
 		}
@@ -445,6 +512,11 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 			// Spec
 			ServiceAccountName: sa.Name,
 			Retention:          getRetentionHelper(cr),
+			RetentionSize:      model.GetPrometheusRetentionSize(cr),
+			ScrapeInterval:     model.GetPrometheusScrapeInterval(cr),
+			EvaluationInterval: model.GetPrometheusEvaluationInterval(cr),
+			Query:              model.GetPrometheusQuerySpec(cr),
+			WALCompression:     model.GetPrometheusWALCompression(cr),
 			ExternalURL:        fmt.Sprintf("https://%v", host),
 			AdditionalScrapeConfigs: &kv1.SecretKeySelector{
 				LocalObjectReference: kv1.LocalObjectReference{
@@ -468,20 +540,21 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 				},
 			},
 			PodMonitorSelector:              model.GetPrometheusPodMonitorLabelSelectors(cr, indexes),
-			PodMonitorNamespaceSelector:     model.GetPrometheusPodMonitorNamespaceSelectors(cr, indexes),
+			PodMonitorNamespaceSelector:     model.GetTenantNamespaceSelector(model.GetPrometheusPodMonitorNamespaceSelectors(cr, indexes), suspended),
 			ServiceMonitorSelector:          model.GetPrometheusServiceMonitorLabelSelectors(cr, indexes),
-			ServiceMonitorNamespaceSelector: model.GetPrometheusServiceMonitorNamespaceSelectors(cr, indexes),
+			ServiceMonitorNamespaceSelector: model.GetTenantNamespaceSelector(model.GetPrometheusServiceMonitorNamespaceSelectors(cr, indexes), suspended),
 			RuleSelector:                    model.GetPrometheusRuleLabelSelectors(cr, indexes),
 			RuleNamespaceSelector:           model.GetPrometheusRuleNamespaceSelectors(cr, indexes),
 			ProbeSelector:                   model.GetProbeLabelSelectors(cr, indexes),
-			ProbeNamespaceSelector:          model.GetProbeNamespaceSelectors(cr, indexes),
+			ProbeNamespaceSelector:          model.GetTenantNamespaceSelector(model.GetProbeNamespaceSelectors(cr, indexes), suspended),
 			RemoteWrite:                     remoteWrites,
 			Alerting:                        r.getAlerting(cr),
 			Secrets:                         secrets,
+			ConfigMaps:                      configMaps,
 			Containers:                      sidecars,
 			Resources:                       model.GetPrometheusResourceRequirement(cr),
 		}
-		if cr.Spec.Storage != nil && cr.Spec.Storage.PrometheusStorageSpec != nil {
+		if model.HasPrometheusStorage(cr) {
 			prometheusStorageSpec, err := getPrometheusStorageSpecHelper(cr, indexes)
 			if err != nil {
 				return err
@@ -494,26 +567,262 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 		if cr.Spec.Affinity != nil {
 			prometheus.Spec.Affinity = cr.Spec.Affinity
 		}
-		return nil
+		if cr.QueryLoggingEnabled() {
+			setPrometheusQueryLogSpec(cr, &prometheus.Spec)
+		}
+		if model.IsThanosEnabled(cr) {
+			setPrometheusShardSpec(cr, &prometheus.Spec, indexes, 0, shards)
+		}
+		if cr.PrometheusAgentEnabled() {
+			setPrometheusAgentSpec(&prometheus.Spec)
+		}
+		return deferPrometheusRollout(cr, s, prometheus, existing)
 	})
 
 	if err != nil {
 		return err
 	}
 
+	// The other shards only scrape their share of the pod monitor targets
+	for shard := int32(1); shard < shards; shard++ {
+		requestedSpec := prometheus.Spec.DeepCopy()
+		setPrometheusShardSpec(cr, requestedSpec, indexes, shard, shards)
+
+		prometheusShard := model.GetPrometheusShard(cr, shard)
+		err = utils.Apply(ctx, r.client, prometheusShard, func() error {
+			existing := prometheusShard.Spec.DeepCopy()
+			prometheusShard.Labels = map[string]string{
+				"managed-by":               "observability-operator",
+				model.PrometheusShardLabel: strconv.Itoa(int(shard)),
+			}
+			prometheusShard.Spec = *requestedSpec
+			return deferPrometheusRollout(cr, s, prometheusShard, existing)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.deleteUnrequestedPrometheusShards(ctx, cr, s, shards)
+}
+
+// Every shard scrapes the pod monitor copies of its shard. Service monitors, probes and the
+// additional scrape configs are only scraped by the first shard. With backups, the Thanos
+// sidecars upload the TSDB blocks of all shards.
+func setPrometheusShardSpec(cr *v1.Observability, spec *prometheusv1.PrometheusSpec, indexes []v1.RepositoryIndex, shard int32, shards int32) {
+	image := model.GetImage(cr, v1.ImageThanos, model.ThanosImage)
+	spec.Thanos = &prometheusv1.ThanosSpec{
+		Image: &image,
+	}
+	if cr.BackupEnabled() {
+		spec.Thanos.ObjectStorageConfig = &kv1.SecretKeySelector{
+			LocalObjectReference: kv1.LocalObjectReference{
+				Name: cr.Spec.Backup.ObjectStorageSecret,
+			},
+			Key: model.BackupObjectStorageKey,
+		}
+		// Merged into the sidecar container generated by the Prometheus operator. The other shards
+		// are copies of the first one
+		if model.GetObjectStorageIdentity(cr) != nil && shard == 0 {
+			spec.Volumes = append(spec.Volumes, model.GetObjectStorageIdentityVolumes(cr)...)
+			spec.Containers = append(spec.Containers, kv1.Container{
+				Name:         "thanos-sidecar",
+				Env:          model.GetObjectStorageIdentityEnv(cr),
+				VolumeMounts: model.GetObjectStorageIdentityVolumeMounts(cr),
+			})
+		}
+	}
+	spec.PodMonitorSelector = model.GetPrometheusShardPodMonitorSelector(model.GetPrometheusPodMonitorLabelSelectors(cr, indexes), shard, shards)
+	if shard == 0 {
+		return
+	}
+
+	spec.ServiceMonitorSelector = nil
+	spec.ProbeSelector = nil
+	spec.AdditionalScrapeConfigs = nil
+
+	var containers []kv1.Container
+	for _, container := range spec.Containers {
+		if container.Name != "blackbox-exporter" {
+			containers = append(containers, container)
+		}
+	}
+	spec.Containers = containers
+}
+
+// The Prometheus operator releases supported here predate the PrometheusAgent CRD and always pass
+// the TSDB flags that Prometheus rejects in agent mode. Agents are therefore Prometheus servers
+// that select no rules, send no alerts and only keep blocks long enough to survive remote write
+// outages, the WAL is what is forwarded
+func setPrometheusAgentSpec(spec *prometheusv1.PrometheusSpec) {
+	spec.RuleSelector = nil
+	spec.RuleNamespaceSelector = nil
+	spec.Alerting = nil
+	spec.Query = nil
+	spec.Retention = PrometheusAgentRetention
+	spec.RetentionSize = ""
+}
+
+func (r *Reconciler) deleteUnrequestedPrometheusShards(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, shards int32) error {
+	list := &prometheusv1.PrometheusList{}
+	opts := &client.ListOptions{
+		Namespace: cr.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			"managed-by": "observability-operator",
+		}),
+	}
+	err := r.client.List(ctx, list, opts)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, prometheus := range list.Items {
+		shard, err := strconv.Atoi(prometheus.Labels[model.PrometheusShardLabel])
+		if err != nil || int32(shard) < shards {
+			continue
+		}
+		err = r.client.Delete(ctx, prometheus)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		s.SetDeferredRollout(prometheus.Name, "")
+	}
 	return nil
 }
 
 //construct Prometheus storage spec with either default or override value from resources
 func getPrometheusStorageSpecHelper(cr *v1.Observability, indexes []v1.RepositoryIndex) (*prometheusv1.StorageSpec, error) {
-	prometheusStorageSpec := cr.Spec.Storage.PrometheusStorageSpec
-	customStorageSize := model.GetPrometheusStorageSize(cr, indexes)
-	if customStorageSize == "" {
+	prometheusStorageSpec := &prometheusv1.StorageSpec{}
+	if cr.Spec.Storage != nil && cr.Spec.Storage.PrometheusStorageSpec != nil {
+		prometheusStorageSpec = cr.Spec.Storage.PrometheusStorageSpec.DeepCopy()
+	}
+
+	// An emptyDir does not use the volume claim template
+	if prometheusStorageSpec.EmptyDir != nil {
 		return prometheusStorageSpec, nil
 	}
 
-	_, err := resource.ParseQuantity(customStorageSize) //check if resources value is valid
-	return cr.Spec.Storage.PrometheusStorageSpec, err
+	storageSize, err := resource.ParseQuantity(model.GetPrometheusStorageSize(cr, indexes)) //check if resources value is valid
+	if err != nil {
+		return nil, err
+	}
+
+	if prometheusStorageSpec.VolumeClaimTemplate.Spec.Resources.Requests == nil {
+		prometheusStorageSpec.VolumeClaimTemplate.Spec.Resources.Requests = kv1.ResourceList{}
+	}
+	prometheusStorageSpec.VolumeClaimTemplate.Spec.Resources.Requests[kv1.ResourceStorage] = storageSize
+	prometheusStorageSpec.VolumeClaimTemplate.Spec.StorageClassName = model.GetPrometheusStorageClass(cr)
+	return prometheusStorageSpec, nil
+}
+
+// The volume claim template of the Prometheus stateful set is immutable. When the requested storage
+// size grows, the Prometheus operator recreates the stateful set, but the existing claims are kept
+// and have to be expanded separately. Returns true while an expansion is still in progress.
+func (r *Reconciler) reconcilePrometheusVolumes(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) (bool, error) {
+	if !model.HasPrometheusStorage(cr) {
+		return false, nil
+	}
+
+	requested, err := resource.ParseQuantity(model.GetPrometheusStorageSize(cr, indexes))
+	if err != nil {
+		return false, err
+	}
+
+	// Volumes of all shards
+	list := &kv1.PersistentVolumeClaimList{}
+	for shard := int32(0); shard < model.GetPrometheusShards(cr); shard++ {
+		shardList := &kv1.PersistentVolumeClaimList{}
+		opts := &client.ListOptions{
+			Namespace: cr.Namespace,
+			LabelSelector: labels.SelectorFromSet(map[string]string{
+				"prometheus": model.GetPrometheusShard(cr, shard).Name,
+			}),
+		}
+		err = r.client.List(ctx, shardList, opts)
+		if err != nil {
+			return false, err
+		}
+		list.Items = append(list.Items, shardList.Items...)
+	}
+
+	resizing := false
+	for i := range list.Items {
+		pvc := &list.Items[i]
+		current := pvc.Spec.Resources.Requests[kv1.ResourceStorage]
+
+		if current.Cmp(requested) < 0 {
+			expandable, err := r.storageClassAllowsExpansion(ctx, pvc.Spec.StorageClassName)
+			if err != nil {
+				return false, err
+			}
+			if !expandable {
+				r.logger.Info("storage class does not allow volume expansion, prometheus volume not resized",
+					"pvc", pvc.Name, "requested", requested.String())
+				continue
+			}
+
+			pvc.Spec.Resources.Requests[kv1.ResourceStorage] = requested
+			err = r.client.Update(ctx, pvc)
+			if err != nil {
+				return false, errors2.Wrap(err, fmt.Sprintf("error expanding prometheus volume %v", pvc.Name))
+			}
+			r.logger.Info("expanding prometheus volume", "pvc", pvc.Name, "from", current.String(), "to", requested.String())
+			resizing = true
+			continue
+		}
+
+		// Wait until the volume has been expanded. Once the claim reports FileSystemResizePending the
+		// remaining file system resize is handled by the kubelet.
+		capacity := pvc.Status.Capacity[kv1.ResourceStorage]
+		if capacity.Cmp(current) < 0 && !hasPvcCondition(pvc, kv1.PersistentVolumeClaimFileSystemResizePending) {
+			resizing = true
+		}
+	}
+
+	return resizing, nil
+}
+
+func (r *Reconciler) storageClassAllowsExpansion(ctx context.Context, name *string) (bool, error) {
+	storageClass := &storagev1.StorageClass{}
+	if name != nil && *name != "" {
+		err := r.client.Get(ctx, client.ObjectKey{Name: *name}, storageClass)
+		if err != nil {
+			return false, err
+		}
+	} else {
+		// No storage class in the claim: look up the default one
+		list := &storagev1.StorageClassList{}
+		err := r.client.List(ctx, list)
+		if err != nil {
+			return false, err
+		}
+
+		found := false
+		for _, sc := range list.Items {
+			if sc.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+				storageClass = &sc
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+func hasPvcCondition(pvc *kv1.PersistentVolumeClaim, conditionType kv1.PersistentVolumeClaimConditionType) bool {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == kv1.ConditionTrue {
+			return true
+		}
+	}
+	return false
 }
 
 func getRetentionHelper(cr *v1.Observability) string {
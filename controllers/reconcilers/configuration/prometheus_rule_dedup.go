@@ -0,0 +1,165 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	PlatformMonitoringNamespace = "openshift-monitoring"
+	// Platform monitoring also evaluates the rules of the namespaces with this label
+	clusterMonitoringLabel = "openshift.io/cluster-monitoring"
+	// Receiver without notifications the Relabel strategy routes duplicates to
+	DuplicateRulesReceiver = "duplicate-rules"
+)
+
+// Alerting rules of the platform monitoring, keyed by alert name and, unless only names are
+// matched, the expression
+type platformRules map[string]string
+
+func getDuplicateRuleKey(dedup *v1.RuleDeduplication, rule *v12.Rule) string {
+	if dedup.MatchAlertNameOnly {
+		return rule.Alert
+	}
+	return fmt.Sprintf("%v\x00%v", rule.Alert, strings.Join(strings.Fields(rule.Expr.String()), ""))
+}
+
+// Returns the alerting rules evaluated by the platform monitoring. Empty without OpenShift monitoring
+func (r *Reconciler) getPlatformRules(ctx context.Context, cr *v1.Observability) (platformRules, error) {
+	result := platformRules{}
+	if cr.Spec.RuleDeduplication == nil {
+		return result, nil
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	err := r.client.List(ctx, namespaces, &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{clusterMonitoringLabel: "true"}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := []string{PlatformMonitoringNamespace}
+	for _, namespace := range namespaces.Items {
+		// Rules of the CR itself are no platform rules, even if its namespace is monitored by the platform
+		if namespace.Name != PlatformMonitoringNamespace && namespace.Name != cr.Namespace {
+			names = append(names, namespace.Name)
+		}
+	}
+
+	for _, namespace := range names {
+		list := &v12.PrometheusRuleList{}
+		err := r.client.List(ctx, list, client.InNamespace(namespace))
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				return result, nil
+			}
+			return nil, err
+		}
+		for _, rule := range list.Items {
+			for _, group := range rule.Spec.Groups {
+				for i := range group.Rules {
+					if group.Rules[i].Alert == "" {
+						continue
+					}
+					result[getDuplicateRuleKey(cr.Spec.RuleDeduplication, &group.Rules[i])] = fmt.Sprintf("%v/%v", rule.Namespace, rule.Name)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// Finds the alerting rules of a PrometheusRule that duplicate platform rules and applies the
+// strategy to them
+func deduplicateRules(dedup *v1.RuleDeduplication, platform platformRules, rule *v12.PrometheusRule) []v1.DuplicateRule {
+	if dedup == nil || len(platform) == 0 {
+		return nil
+	}
+	strategy := dedup.Strategy
+	if strategy == "" {
+		strategy = v1.RuleDeduplicationReport
+	}
+
+	var result []v1.DuplicateRule
+	var groups []v12.RuleGroup
+	for _, group := range rule.Spec.Groups {
+		var rules []v12.Rule
+		for _, r := range group.Rules {
+			platformRule, ok := platform[getDuplicateRuleKey(dedup, &r)]
+			if r.Alert == "" || !ok {
+				rules = append(rules, r)
+				continue
+			}
+
+			result = append(result, v1.DuplicateRule{
+				Rule:         rule.Name,
+				Group:        group.Name,
+				Alert:        r.Alert,
+				PlatformRule: platformRule,
+				Strategy:     strategy,
+			})
+			switch strategy {
+			case v1.RuleDeduplicationSuppress:
+				continue
+			case v1.RuleDeduplicationRelabel:
+				r.Labels = MergeLabels(map[string]string{
+					v1.DuplicateOfLabel: platformRule,
+				}, r.Labels)
+			}
+			rules = append(rules, r)
+		}
+		// Groups of which all rules were suppressed are dropped
+		if len(rules) > 0 || len(group.Rules) == 0 {
+			group.Rules = rules
+			groups = append(groups, group)
+		}
+	}
+	rule.Spec.Groups = groups
+	return result
+}
+
+func setDuplicateRulesStatus(cr *v1.Observability, s *v1.ObservabilityStatus, duplicates []v1.DuplicateRule) {
+	if cr.Spec.RuleDeduplication == nil {
+		meta.RemoveStatusCondition(&s.Conditions, v1.PlatformRulesDistinct)
+		s.DuplicateRules = nil
+		return
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Rule < duplicates[j].Rule
+	})
+	s.DuplicateRules = duplicates
+	if len(duplicates) == 0 {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.PlatformRulesDistinct,
+			Status:  metav1.ConditionTrue,
+			Reason:  "NoDuplicates",
+			Message: "no alerting rule duplicates a rule of the platform monitoring",
+		})
+		return
+	}
+
+	reason := "DuplicatesReported"
+	switch duplicates[0].Strategy {
+	case v1.RuleDeduplicationSuppress:
+		reason = "DuplicatesSuppressed"
+	case v1.RuleDeduplicationRelabel:
+		reason = "DuplicatesRelabeled"
+	}
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:    v1.PlatformRulesDistinct,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: fmt.Sprintf("%v alerting rules duplicate rules of the platform monitoring, see status.duplicateRules", len(duplicates)),
+	})
+}
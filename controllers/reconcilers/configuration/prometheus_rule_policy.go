@@ -0,0 +1,159 @@
+package configuration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	severityLabel        = "severity"
+	runbookUrlAnnotation = "runbook_url"
+)
+
+// Checks the alerting rules of a PrometheusRule against the policy and normalizes their severities.
+// The Reject action drops non-conforming rules, the Annotate action fills in the defaults and applies
+// the rules with their remaining violations as an annotation
+func applyRulePolicy(policy *v1.RulePolicy, rule *v12.PrometheusRule) []v1.RulePolicyViolation {
+	if policy == nil {
+		return nil
+	}
+	action := policy.Action
+	if action == "" {
+		action = v1.RulePolicyReject
+	}
+
+	var result []v1.RulePolicyViolation
+	var groups []v12.RuleGroup
+	for _, group := range rule.Spec.Groups {
+		var rules []v12.Rule
+		for _, r := range group.Rules {
+			if r.Alert == "" {
+				rules = append(rules, r)
+				continue
+			}
+			if action == v1.RulePolicyAnnotate {
+				setRulePolicyDefaults(policy, &r)
+			}
+			violations := checkRulePolicy(policy, &r)
+			if len(violations) == 0 {
+				rules = append(rules, r)
+				continue
+			}
+
+			result = append(result, v1.RulePolicyViolation{
+				Rule:       rule.Name,
+				Group:      group.Name,
+				Alert:      r.Alert,
+				Violations: violations,
+				Action:     action,
+			})
+			if action == v1.RulePolicyAnnotate {
+				r.Annotations = MergeLabels(map[string]string{
+					v1.RulePolicyViolationsAnnotation: strings.Join(violations, ", "),
+				}, r.Annotations)
+				rules = append(rules, r)
+			}
+		}
+		// Groups of which all rules were rejected are dropped
+		if len(rules) > 0 || len(group.Rules) == 0 {
+			group.Rules = rules
+			groups = append(groups, group)
+		}
+	}
+	rule.Spec.Groups = groups
+	return result
+}
+
+func setRulePolicyDefaults(policy *v1.RulePolicy, rule *v12.Rule) {
+	for label, value := range policy.DefaultLabels {
+		if rule.Labels[label] == "" {
+			rule.Labels = MergeLabels(map[string]string{label: value}, rule.Labels)
+		}
+	}
+	if policy.DefaultRunbookUrl != "" && rule.Annotations[runbookUrlAnnotation] == "" {
+		rule.Annotations = MergeLabels(map[string]string{runbookUrlAnnotation: policy.DefaultRunbookUrl}, rule.Annotations)
+	}
+}
+
+// Returns the violations of the rule after normalizing its severity to the spelling of the policy
+func checkRulePolicy(policy *v1.RulePolicy, rule *v12.Rule) []string {
+	var violations []string
+	for _, label := range policy.RequiredLabels {
+		if rule.Labels[label] == "" {
+			violations = append(violations, fmt.Sprintf("missing label %v", label))
+		}
+	}
+
+	if severity := rule.Labels[severityLabel]; severity != "" {
+		normalized, ok := normalizeSeverity(policy, severity)
+		if ok {
+			rule.Labels[severityLabel] = normalized
+		} else {
+			violations = append(violations, fmt.Sprintf("severity %v is not one of %v", severity,
+				strings.Join(policy.AllowedSeverities, ", ")))
+		}
+	}
+
+	if policy.RequireRunbookUrl && rule.Annotations[runbookUrlAnnotation] == "" {
+		violations = append(violations, fmt.Sprintf("missing annotation %v", runbookUrlAnnotation))
+	}
+	return violations
+}
+
+// Resolves aliases and the case of a severity. Any severity is allowed if the policy lists none
+func normalizeSeverity(policy *v1.RulePolicy, severity string) (string, bool) {
+	for alias, target := range policy.SeverityAliases {
+		if strings.EqualFold(alias, severity) {
+			severity = target
+			break
+		}
+	}
+	if len(policy.AllowedSeverities) == 0 {
+		return severity, true
+	}
+	for _, allowed := range policy.AllowedSeverities {
+		if strings.EqualFold(allowed, severity) {
+			return allowed, true
+		}
+	}
+	return "", false
+}
+
+func setRulePolicyStatus(cr *v1.Observability, s *v1.ObservabilityStatus, violations []v1.RulePolicyViolation) {
+	if cr.Spec.RulePolicy == nil {
+		meta.RemoveStatusCondition(&s.Conditions, v1.RulePolicyCompliant)
+		s.RulePolicyViolations = nil
+		return
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Rule < violations[j].Rule
+	})
+	s.RulePolicyViolations = violations
+	if len(violations) == 0 {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.RulePolicyCompliant,
+			Status:  metav1.ConditionTrue,
+			Reason:  "RulesConform",
+			Message: "all alerting rules conform to the rule policy",
+		})
+		return
+	}
+
+	reason := "RulesRejected"
+	if violations[0].Action == v1.RulePolicyAnnotate {
+		reason = "RulesAnnotated"
+	}
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:    v1.RulePolicyCompliant,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: fmt.Sprintf("%v alerting rules violate the rule policy, see status.rulePolicyViolations", len(violations)),
+	})
+}
@@ -0,0 +1,326 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Time a test job may run before it counts as failed
+const ruleTestDeadlineSeconds = 300
+
+// Test files are identified by their path, rule files of the same name in different repositories
+// are deduplicated like the rules
+func getUniqueRuleTests(indexes []v1.RepositoryIndex) []ResourceInfo {
+	var result []ResourceInfo
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Prometheus == nil {
+			continue
+		}
+	seek:
+		for _, test := range index.Config.Prometheus.RuleTests {
+			for _, existing := range result {
+				if existing.Name == test {
+					continue seek
+				}
+			}
+			result = append(result, ResourceInfo{
+				Id:          index.Id,
+				Name:        test,
+				Url:         fmt.Sprintf("%s/%s", index.BaseUrl, test),
+				AccessToken: index.AccessToken,
+				Tag:         index.Tag,
+			})
+		}
+	}
+	return result
+}
+
+// Runs the promtool unit tests of the rules. The operator can't evaluate PromQL itself, so every test
+// file runs in a job with the Prometheus image. Returns the rules covered by failed or pending tests,
+// which must not be applied, and if any test is still running.
+func (r *Reconciler) runRuleTests(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, tests []ResourceInfo, rules map[string]*v12.PrometheusRule) (map[string]bool, bool, error) {
+	rejected := map[string]bool{}
+	requested := map[string]bool{}
+	pending := false
+	var results []v1.RuleTestResult
+
+	for _, test := range tests {
+		source, err := r.fetchResource(test.Url, test.Tag, test.AccessToken)
+		if err != nil {
+			return nil, false, err
+		}
+
+		result := v1.RuleTestResult{
+			Name: test.Name,
+		}
+		files, covered, err := getRuleTestFiles(source, rules)
+		if err != nil {
+			result.Result = v1.RuleTestFailed
+			result.Message = err.Error()
+		} else {
+			hash := getRuleTestHash(cr, files)
+			requested[model.GetRuleTestName(hash)] = true
+			result.Rules = covered
+			result.Result, result.Message, err = r.runRuleTest(ctx, cr, hash, files)
+			if err != nil {
+				return nil, false, err
+			}
+		}
+
+		switch result.Result {
+		case v1.RuleTestFailed:
+			r.recorder.Eventf(cr, corev1.EventTypeWarning, v1.EventRuleTestFailed, "rule test %v failed, the rules it covers are not applied", test.Name)
+		case v1.RuleTestPending:
+			pending = true
+		}
+		if result.Result != v1.RuleTestPassed {
+			for _, rule := range covered {
+				rejected[rule] = true
+			}
+		}
+		results = append(results, result)
+	}
+
+	err := r.deleteUnrequestedRuleTests(ctx, cr, requested)
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.RuleTests = results
+	return rejected, pending, nil
+}
+
+// Returns the files of a test job: the test file with its rule files pointing to the rules as fetched
+// from the repositories, and those rules. The rules are tested before the id label is injected.
+func getRuleTestFiles(source []byte, rules map[string]*v12.PrometheusRule) (map[string]string, []string, error) {
+	test := map[string]interface{}{}
+	err := yaml.Unmarshal(source, &test)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid test file: %v", err)
+	}
+
+	ruleFiles, ok := test["rule_files"].([]interface{})
+	if !ok || len(ruleFiles) == 0 {
+		return nil, nil, fmt.Errorf("test file has no rule_files")
+	}
+
+	files := map[string]string{}
+	var covered []string
+	var rewritten []string
+	for _, ruleFile := range ruleFiles {
+		value, ok := ruleFile.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid rule file: %v", ruleFile)
+		}
+		name := getNameFromUrl(value)
+		rule, ok := rules[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("rule file %v is not a rule of the repositories", value)
+		}
+
+		groups, err := yaml.Marshal(rule.Spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		file := fmt.Sprintf("%s.yaml", name)
+		files[file] = string(groups)
+		covered = append(covered, name)
+		rewritten = append(rewritten, file)
+	}
+	test["rule_files"] = rewritten
+
+	rendered, err := yaml.Marshal(test)
+	if err != nil {
+		return nil, nil, err
+	}
+	files[model.RuleTestFile] = string(rendered)
+	return files, covered, nil
+}
+
+func getRuleTestHash(cr *v1.Observability, files map[string]string) string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	io.WriteString(hash, getPromtoolImage(cr))
+	for _, name := range names {
+		io.WriteString(hash, name)
+		io.WriteString(hash, files[name])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// Promtool is part of the Prometheus image, the tests run with the version of the installed Prometheus
+func getPromtoolImage(cr *v1.Observability) string {
+	return model.GetImage(cr, v1.ImagePrometheus, fmt.Sprintf("%s:%s", PrometheusBaseImage, model.GetPrometheusVersion(cr)))
+}
+
+// Starts the job of a test file or returns its result
+func (r *Reconciler) runRuleTest(ctx context.Context, cr *v1.Observability, hash string, files map[string]string) (v1.RuleTestResultType, string, error) {
+	job := model.GetRuleTestJob(cr, hash)
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: job.Namespace, Name: job.Name}, job)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", "", err
+	}
+
+	if errors.IsNotFound(err) {
+		err = r.createRuleTestJob(ctx, cr, hash, files)
+		return v1.RuleTestPending, "", err
+	}
+
+	if job.Status.Succeeded > 0 {
+		return v1.RuleTestPassed, "", nil
+	}
+	if job.Status.Failed > 0 {
+		message, err := r.getRuleTestOutput(ctx, job)
+		return v1.RuleTestFailed, message, err
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return v1.RuleTestFailed, condition.Message, nil
+		}
+	}
+	return v1.RuleTestPending, "", nil
+}
+
+func (r *Reconciler) createRuleTestJob(ctx context.Context, cr *v1.Observability, hash string, files map[string]string) error {
+	configMap := model.GetRuleTestConfigMap(cr, hash)
+	err := utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Labels = map[string]string{
+			model.RuleTestLabel: "true",
+		}
+		configMap.Data = files
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var backoffLimit int32 = 0
+	var deadline int64 = ruleTestDeadlineSeconds
+	job := model.GetRuleTestJob(cr, hash)
+	return utils.Apply(ctx, r.client, job, func() error {
+		job.Labels = map[string]string{
+			model.RuleTestLabel: "true",
+		}
+		job.Spec = batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &deadline,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						model.RuleTestLabel: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:       "promtool",
+							Image:      getPromtoolImage(cr),
+							Command:    []string{"/bin/promtool", "test", "rules", model.RuleTestFile},
+							WorkingDir: model.RuleTestDir,
+							// The output of promtool ends up in the status of failed tests
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "rule-test",
+									MountPath: model.RuleTestDir,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "rule-test",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: configMap.Name,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+}
+
+// The failure message of promtool is the termination message of the container of the job
+func (r *Reconciler) getRuleTestOutput(ctx context.Context, job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	err := r.client.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name})
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.State.Terminated != nil && container.State.Terminated.Message != "" {
+				return strings.TrimSpace(container.State.Terminated.Message), nil
+			}
+		}
+	}
+	return "promtool failed without output", nil
+}
+
+// Removes the jobs and config maps of previous revisions of the test files
+func (r *Reconciler) deleteUnrequestedRuleTests(ctx context.Context, cr *v1.Observability, requested map[string]bool) error {
+	opts := []client.ListOption{
+		client.InNamespace(cr.Namespace),
+		client.MatchingLabels{model.RuleTestLabel: "true"},
+	}
+
+	jobs := &batchv1.JobList{}
+	err := r.client.List(ctx, jobs, opts...)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs.Items {
+		if requested[job.Name] {
+			continue
+		}
+		// The pods of the job are removed with it
+		err = r.client.Delete(ctx, &job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	err = r.client.List(ctx, configMaps, opts...)
+	if err != nil {
+		return err
+	}
+	for _, configMap := range configMaps.Items {
+		if requested[configMap.Name] {
+			continue
+		}
+		err = r.client.Delete(ctx, &configMap)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
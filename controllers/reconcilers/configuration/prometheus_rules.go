@@ -7,9 +7,9 @@ import (
 	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type ResourceInfo struct {
@@ -18,15 +18,17 @@ type ResourceInfo struct {
 	Url         string
 	AccessToken string
 	Tag         string
+	// Configuration secret of the index
+	Source string
 }
 
 func getUniqueRules(indexes []v1.RepositoryIndex) []ResourceInfo {
 	var result []ResourceInfo
-seek:
 	for _, index := range indexes {
 		if index.Config == nil || index.Config.Prometheus == nil {
 			continue
 		}
+	seek:
 		for _, rule := range index.Config.Prometheus.Rules {
 			name := getNameFromUrl(rule)
 			for _, existing := range result {
@@ -40,6 +42,7 @@ seek:
 				Url:         fmt.Sprintf("%s/%s", index.BaseUrl, rule),
 				AccessToken: index.AccessToken,
 				Tag:         index.Tag,
+				Source:      getIndexSource(&index),
 			})
 		}
 	}
@@ -69,6 +72,10 @@ func (r *Reconciler) deleteUnrequestedRules(cr *v1.Observability, ctx context.Co
 	// Check which rules are no longer requested and
 	// delete them
 	for _, rule := range existingRules.Items {
+		// Owned by the self monitoring and the profiles stages
+		if rule.Name == model.GetSelfMonitoringRule(cr).Name || rule.Labels[model.ProfileLabel] != "" {
+			continue
+		}
 		if isRequested(rule.Name) == false {
 			err = r.client.Delete(ctx, rule)
 			if err != nil {
@@ -80,23 +87,52 @@ func (r *Reconciler) deleteUnrequestedRules(cr *v1.Observability, ctx context.Co
 	return nil
 }
 
-func (r *Reconciler) createRequestedRules(cr *v1.Observability, ctx context.Context, rules []ResourceInfo) error {
-	// Sync requested prometheus rules
+// Returns true while unit tests of the rules are running
+func (r *Reconciler) createRequestedRules(cr *v1.Observability, ctx context.Context, rules []ResourceInfo, tests []ResourceInfo, s *v1.ObservabilityStatus) (bool, error) {
+	parsedRules := map[string]*v12.PrometheusRule{}
 	for _, rule := range rules {
 		bytes, err := r.fetchResource(rule.Url, rule.Tag, rule.AccessToken)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		parsedRule, err := parseRuleFromYaml(cr, rule.Name, bytes)
 		if err != nil {
-			return err
+			return false, err
+		}
+		parsedRules[rule.Name] = parsedRule
+	}
+
+	// Rules covered by failed or running tests keep their applied revision
+	rejected, testing, err := r.runRuleTests(ctx, cr, s, tests, parsedRules)
+	if err != nil {
+		return false, err
+	}
+
+	platform, err := r.getPlatformRules(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+
+	// Sync requested prometheus rules
+	var violations []v1.RulePolicyViolation
+	var duplicates []v1.DuplicateRule
+	for _, rule := range rules {
+		if rejected[rule.Name] {
+			continue
 		}
 
+		parsedRule := parsedRules[rule.Name]
+		err = selectSourceRules(cr, rule, parsedRule)
+		if err != nil {
+			return false, err
+		}
+		violations = append(violations, applyRulePolicy(cr.Spec.RulePolicy, parsedRule)...)
+		duplicates = append(duplicates, deduplicateRules(cr.Spec.RuleDeduplication, platform, parsedRule)...)
 		requestedSpec := parsedRule.Spec
 		requestedLabels := parsedRule.Labels
 
-		_, err = controllerutil.CreateOrUpdate(ctx, r.client, parsedRule, func() error {
+		err = r.stageApply(parsedRule, func() error {
 			// Add managed label to Rule CR
 			parsedRule.Spec = requestedSpec
 			parsedRule.Labels = MergeLabels(map[string]string{
@@ -107,10 +143,12 @@ func (r *Reconciler) createRequestedRules(cr *v1.Observability, ctx context.Cont
 			return nil
 		})
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
-	return nil
+	setRulePolicyStatus(cr, s, violations)
+	setDuplicateRulesStatus(cr, s, duplicates)
+	return testing, nil
 }
 
 func (r *Reconciler) createDMSAlert(cr *v1.Observability, ctx context.Context) error {
@@ -120,7 +158,7 @@ func (r *Reconciler) createDMSAlert(cr *v1.Observability, ctx context.Context) e
 	}
 
 	dms := model.GetDeadmansSwitch(cr)
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, dms, func() error {
+	err := utils.Apply(ctx, r.client, dms, func() error {
 		if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.RuleLabelSelector != nil {
 			if dms.Labels == nil {
 				dms.Labels = make(map[string]string)
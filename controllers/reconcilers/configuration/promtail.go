@@ -5,28 +5,35 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"io"
+	"path"
 
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	v13 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/core/v1"
 	v14 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // Get the namespaces in which this Promtail instance should scrape the logs from all pods
 // Based on the label selectors in the index
-func (r *Reconciler) getScrapeNamespacesFor(ctx context.Context, cr *v1.Observability, index *v1.RepositoryIndex) ([]string, error) {
+func (r *Reconciler) getScrapeNamespacesFor(ctx context.Context, cr *v1.Observability, index *v1.RepositoryIndex) ([]v12.Namespace, error) {
 	if index.Config == nil || index.Config.Promtail == nil || index.Config.Promtail.Enabled == false {
 		return nil, nil
 	}
 
-	var result []string
 	list := &v12.NamespaceList{}
 	selector := labels.SelectorFromSet(index.Config.Promtail.NamespaceLabelSelector)
+	if logs := model.GetPromtailLogs(cr); logs != nil && logs.NamespaceSelector != nil {
+		s, err := v14.LabelSelectorAsSelector(logs.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		selector = s
+	}
 	opts := &client.ListOptions{
 		LabelSelector: selector,
 	}
@@ -36,11 +43,7 @@ func (r *Reconciler) getScrapeNamespacesFor(ctx context.Context, cr *v1.Observab
 		return nil, err
 	}
 
-	for _, ns := range list.Items {
-		result = append(result, ns.Name)
-	}
-
-	return result, nil
+	return list.Items, nil
 }
 
 // Create an index-specific Promtail config
@@ -50,10 +53,23 @@ func (r *Reconciler) createPromtailConfigFor(ctx context.Context, cr *v1.Observa
 		return nil, nil, err
 	}
 
+	tenants, err := model.GetPromtailNamespaceTenants(cr, namespaces)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var names []string
+	for _, namespace := range namespaces {
+		if logs := model.GetLogTenants(cr); logs != nil && logs.OnlyTenantNamespaces && tenants[namespace.Name] == "" {
+			continue
+		}
+		names = append(names, namespace.Name)
+	}
+
 	configMap := model.GetPromtailConfigmap(cr, index.Id)
-	config, err := model.GetPromtailConfig(cr, observatorium, index.Id, namespaces)
+	config, err := model.GetPromtailConfig(cr, observatorium, index.Id, names, tenants)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, configMap, func() error {
+	err = utils.Apply(ctx, r.client, configMap, func() error {
 		configMap.Labels = map[string]string{
 			"managed-by": "observability-operator",
 		}
@@ -74,10 +90,13 @@ func (r *Reconciler) createPromtailConfigFor(ctx context.Context, cr *v1.Observa
 }
 
 // If new indexes are added or existing indexes change their id, we have to cleanup the outdated daemonsets
-func (r *Reconciler) deleteUnrequestedDaemonsets(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+func (r *Reconciler) deleteUnrequestedDaemonsets(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, indexes []v1.RepositoryIndex) error {
 	list := &v13.DaemonSetList{}
 	opts := &client.ListOptions{
 		Namespace: cr.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			utils.ManagedByLabel: utils.ManagedByValue,
+		}),
 	}
 
 	err := r.client.List(ctx, list, opts)
@@ -92,6 +111,10 @@ func (r *Reconciler) deleteUnrequestedDaemonsets(ctx context.Context, cr *v1.Obs
 			return false
 		}
 
+		if cr.PromtailMode() != v1.ComponentManaged {
+			return false
+		}
+
 		for _, index := range indexes {
 			expectedName := fmt.Sprintf("promtail-%s", index.Id)
 			if name == expectedName {
@@ -120,6 +143,7 @@ func (r *Reconciler) deleteUnrequestedDaemonsets(ctx context.Context, cr *v1.Obs
 			if err != nil {
 				return err
 			}
+			s.SetDeferredRollout(daemonset.Name, "")
 		}
 	}
 
@@ -127,7 +151,7 @@ func (r *Reconciler) deleteUnrequestedDaemonsets(ctx context.Context, cr *v1.Obs
 }
 
 // Create an index-specific daemonset
-func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Observability, index *v1.RepositoryIndex) error {
+func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, index *v1.RepositoryIndex) error {
 	if index.Config == nil || index.Config.Promtail == nil || index.Config.Promtail.Enabled == false {
 		return nil
 	}
@@ -158,7 +182,8 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 	}
 
 	var t = true
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, daemonset, func() error {
+	err = utils.Apply(ctx, r.client, daemonset, func() error {
+		existing := daemonset.Spec.DeepCopy()
 		daemonset.Labels = map[string]string{
 			"managed-by": "observability-operator",
 		}
@@ -209,8 +234,9 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 					PriorityClassName: model.ObservabilityPriorityClassName,
 					Containers: []v12.Container{
 						{
-							Name:  "promtail",
-							Image: "quay.io/integreatly/promtail:latest",
+							Name:      "promtail",
+							Image:     model.GetImage(cr, v1.ImagePromtail, model.PromtailImage),
+							Resources: model.GetPromtailResourceRequirement(cr),
 
 							SecurityContext: &v12.SecurityContext{
 								Privileged: &t,
@@ -257,6 +283,114 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 			},
 		}
 
+		podSpec := &daemonset.Spec.Template.Spec
+		for i, dir := range model.GetPromtailHostPathDirs(cr) {
+			name := fmt.Sprintf("host-path-%d", i)
+			podSpec.Volumes = append(podSpec.Volumes, v12.Volume{
+				Name: name,
+				VolumeSource: v12.VolumeSource{
+					HostPath: &v12.HostPathVolumeSource{
+						Path: dir,
+					},
+				},
+			})
+			podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v12.VolumeMount{
+				Name:      name,
+				MountPath: dir,
+				ReadOnly:  true,
+			})
+		}
+
+		// Every daemonset writes to its own directory on the node, or to its own subdirectory per node
+		// in the shared claim
+		if buffering := model.GetPromtailBuffering(cr); buffering != nil {
+			mount := v12.VolumeMount{
+				Name:      "buffer",
+				MountPath: model.PromtailBufferDir,
+			}
+			volume := v12.Volume{
+				Name: "buffer",
+			}
+			if buffering.Storage == v1.LogBufferingPersistentVolumeClaim {
+				volume.VolumeSource.PersistentVolumeClaim = &v12.PersistentVolumeClaimVolumeSource{
+					ClaimName: model.GetPromtailBufferClaim(cr).Name,
+				}
+				mount.SubPathExpr = fmt.Sprintf("%s/$(HOSTNAME)", index.Id)
+			} else {
+				hostPathType := v12.HostPathDirectoryOrCreate
+				volume.VolumeSource.HostPath = &v12.HostPathVolumeSource{
+					Path: path.Join(buffering.HostPath, index.Id),
+					Type: &hostPathType,
+				}
+			}
+			podSpec.Volumes = append(podSpec.Volumes, volume)
+			podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, mount)
+		}
+
+		// The journal reader needs the machine id to find the journal of the node
+		if logs := model.GetPromtailLogs(cr); logs != nil && logs.Journal {
+			for _, mount := range []struct{ name, hostPath string }{
+				{"journal", "/var/log/journal"},
+				{"machine-id", "/etc/machine-id"},
+			} {
+				podSpec.Volumes = append(podSpec.Volumes, v12.Volume{
+					Name: mount.name,
+					VolumeSource: v12.VolumeSource{
+						HostPath: &v12.HostPathVolumeSource{
+							Path: mount.hostPath,
+						},
+					},
+				})
+				podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v12.VolumeMount{
+					Name:      mount.name,
+					MountPath: mount.hostPath,
+					ReadOnly:  true,
+				})
+			}
+		}
+
+		if model.IsInternalTLSEnabled(cr) {
+			podSpec.Volumes = append(podSpec.Volumes, v12.Volume{
+				Name: "internal-tls",
+				VolumeSource: v12.VolumeSource{
+					Secret: &v12.SecretVolumeSource{
+						SecretName: model.GetInternalTLSSecretName(model.InternalTLSPromtail),
+					},
+				},
+			})
+			podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v12.VolumeMount{
+				Name:      "internal-tls",
+				MountPath: model.PromtailInternalTLSDir,
+				ReadOnly:  true,
+			})
+		}
+
+		for _, c := range model.GetPromtailClients(cr) {
+			for _, secret := range []struct{ kind, name string }{
+				{model.PromtailClientToken, c.TokenSecret},
+				{model.PromtailClientCA, c.CASecret},
+				{model.PromtailClientCert, c.CertSecret},
+			} {
+				if secret.name == "" {
+					continue
+				}
+				name := fmt.Sprintf("client-%s-%s", c.Name, secret.kind)
+				podSpec.Volumes = append(podSpec.Volumes, v12.Volume{
+					Name: name,
+					VolumeSource: v12.VolumeSource{
+						Secret: &v12.SecretVolumeSource{
+							SecretName: secret.name,
+						},
+					},
+				})
+				podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v12.VolumeMount{
+					Name:      name,
+					MountPath: model.GetPromtailClientDir(c.Name, secret.kind),
+					ReadOnly:  true,
+				})
+			}
+		}
+
 		if index.Config.Promtail.Observatorium != "" {
 			observatoriumSecretName := token.GetObservatoriumPromtailSecretName(index)
 			if observatoriumConfig.AuthType == v1.AuthTypeDex {
@@ -276,7 +410,9 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 
 			}
 		}
-		return nil
+		return deferRollout(cr, s, daemonset, daemonset.Spec.Template, func() {
+			daemonset.Spec = *existing
+		})
 	})
 	return err
 }
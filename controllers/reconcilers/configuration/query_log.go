@@ -0,0 +1,120 @@
+package configuration
+
+import (
+	"context"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+func (r *Reconciler) reconcileQueryLogConfig(cr *v1.Observability, ctx context.Context) error {
+	configMap := model.GetQueryLogConfigMap(cr)
+	if !cr.QueryLoggingEnabled() {
+		err := r.client.Delete(ctx, configMap)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	config, _ := model.GetQueryLogConfig(cr)
+	return utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		configMap.Data = map[string]string{
+			"promtail.yaml": string(config),
+		}
+		return nil
+	})
+}
+
+// Prometheus writes the query log to an empty dir shared with the Promtail sidecar. The Prometheus
+// operator merges the volume mount into the Prometheus container it generates
+func setPrometheusQueryLogSpec(cr *v1.Observability, spec *prometheusv1.PrometheusSpec) {
+	_, hash := model.GetQueryLogConfig(cr)
+	configMap := model.GetQueryLogConfigMap(cr)
+
+	spec.QueryLogFile = model.QueryLogFile
+	spec.Volumes = append(spec.Volumes,
+		kv1.Volume{
+			Name: model.QueryLogContainer,
+			VolumeSource: kv1.VolumeSource{
+				EmptyDir: &kv1.EmptyDirVolumeSource{},
+			},
+		},
+		kv1.Volume{
+			Name: configMap.Name,
+			VolumeSource: kv1.VolumeSource{
+				ConfigMap: &kv1.ConfigMapVolumeSource{
+					LocalObjectReference: kv1.LocalObjectReference{
+						Name: configMap.Name,
+					},
+				},
+			},
+		},
+	)
+
+	logMount := kv1.VolumeMount{
+		Name:      model.QueryLogContainer,
+		MountPath: model.QueryLogDirectory,
+	}
+	spec.Containers = append(spec.Containers,
+		kv1.Container{
+			Name:         "prometheus",
+			VolumeMounts: []kv1.VolumeMount{logMount},
+		},
+		kv1.Container{
+			Name:    model.QueryLogContainer,
+			Image:   model.GetImage(cr, v1.ImagePromtail, model.PromtailImage),
+			Command: model.GetQueryLogCommand(),
+			Env: []kv1.EnvVar{
+				{
+					Name:  "CONFIG_HASH",
+					Value: hash,
+				},
+			},
+			Ports: []kv1.ContainerPort{
+				{
+					Name:          model.QueryLogContainer,
+					ContainerPort: model.QueryLogMetricsPort,
+				},
+			},
+			VolumeMounts: []kv1.VolumeMount{
+				logMount,
+				{
+					Name:      configMap.Name,
+					MountPath: "/etc/query-log",
+				},
+			},
+		},
+	)
+}
+
+// The dashboard is generated from the CR, it is kept when the dashboards of the repositories are synced
+func (r *Reconciler) reconcileQueryLogDashboard(cr *v1.Observability, ctx context.Context) error {
+	dashboard := model.GetQueryLogDashboard(cr)
+	if !cr.QueryLoggingEnabled() {
+		err := r.client.Delete(ctx, dashboard)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	json, err := model.GetQueryLogDashboardJson(cr)
+	if err != nil {
+		return err
+	}
+	return utils.Apply(ctx, r.client, dashboard, func() error {
+		dashboard.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		dashboard.Spec.Json = json
+		return nil
+	})
+}
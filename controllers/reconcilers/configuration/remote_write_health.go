@@ -0,0 +1,195 @@
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	AlertRemoteWriteFailureRate    = "RemoteWriteFailureRateHigh"
+	AlertRemoteWriteQueueBacklog   = "RemoteWriteQueueBacklog"
+	AlertRemoteWriteSamplesDropped = "RemoteWriteSamplesDropped"
+)
+
+var remoteWriteAlerts = []string{
+	AlertRemoteWriteFailureRate,
+	AlertRemoteWriteQueueBacklog,
+	AlertRemoteWriteSamplesDropped,
+}
+
+// The health of the remote writes is only known for the managed Prometheus in server mode. Agents
+// evaluate no rules, and the operator can't query an external or the user workload Prometheus
+func remoteWriteHealthChecked(cr *v1.Observability) bool {
+	return cr.PrometheusMode() == v1.ComponentManaged && !cr.PrometheusAgentEnabled() && !cr.UserWorkloadMonitoringEnabled()
+}
+
+// Alerts on the remote storage metrics of Prometheus, one series per remote write endpoint
+func (r *Reconciler) createRemoteWriteHealthRules(cr *v1.Observability, ctx context.Context, indexes []v1.RepositoryIndex) error {
+	rule := model.GetRemoteWriteHealthRule(cr)
+	if !remoteWriteHealthChecked(cr) {
+		err := r.client.Delete(ctx, rule)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	err := utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+
+		// Make sure the rule is picked up by Prometheus
+		selector := model.GetPrometheusRuleLabelSelectors(cr, indexes)
+		if selector != nil {
+			for k, v := range selector.MatchLabels {
+				rule.Labels[k] = v
+			}
+		}
+
+		rule.Spec.Groups = []v12.RuleGroup{
+			{
+				Name: "remote-write-health",
+				Rules: []v12.Rule{
+					{
+						Alert: AlertRemoteWriteFailureRate,
+						Expr: intstr.FromString(`rate(prometheus_remote_storage_failed_samples_total[5m]) /
+(rate(prometheus_remote_storage_failed_samples_total[5m]) + rate(prometheus_remote_storage_succeeded_samples_total[5m])) > 0.1`),
+						For: "15m",
+						Labels: map[string]string{
+							"severity": "warning",
+						},
+						Annotations: map[string]string{
+							"message": "More than 10% of the samples sent to {{ $labels.url }} fail.",
+						},
+					},
+					{
+						Alert: AlertRemoteWriteQueueBacklog,
+						Expr:  intstr.FromString("prometheus_remote_storage_pending_samples > 100000"),
+						For:   "15m",
+						Labels: map[string]string{
+							"severity": "warning",
+						},
+						Annotations: map[string]string{
+							"message": "The retry queue for {{ $labels.url }} holds {{ $value }} pending samples.",
+						},
+					},
+					{
+						Alert: AlertRemoteWriteSamplesDropped,
+						Expr:  intstr.FromString("rate(prometheus_remote_storage_dropped_samples_total[5m]) > 0"),
+						For:   "15m",
+						Labels: map[string]string{
+							"severity": "warning",
+						},
+						Annotations: map[string]string{
+							"message": "Samples for {{ $labels.url }} are dropped.",
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+	return err
+}
+
+// Flip the RemoteWriteDegraded condition depending on the remote write alerts firing in Prometheus
+func (r *Reconciler) checkRemoteWriteHealth(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) {
+	setCondition := func(status metav1.ConditionStatus, reason string, message string) {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.RemoteWriteDegraded,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+
+	if !remoteWriteHealthChecked(cr) {
+		setCondition(metav1.ConditionUnknown, "RemoteWriteHealthNotChecked", fmt.Sprintf("remote write health is not checked for %v prometheus", remoteWriteHealthMode(cr)))
+		return
+	}
+
+	alertsUrl := fmt.Sprintf("http://prometheus-operated.%s:9090/api/v1/alerts", cr.Namespace)
+	resp, err := r.httpClient.Get(alertsUrl)
+	if err != nil {
+		setCondition(metav1.ConditionUnknown, "PrometheusUnavailable", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		setCondition(metav1.ConditionUnknown, "PrometheusUnavailable", fmt.Sprintf("unexpected status code from prometheus: %v", resp.StatusCode))
+		return
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		setCondition(metav1.ConditionUnknown, "PrometheusUnavailable", err.Error())
+		return
+	}
+
+	alerts := struct {
+		Data struct {
+			Alerts []struct {
+				Labels map[string]string `json:"labels"`
+				State  string            `json:"state"`
+			} `json:"alerts"`
+		} `json:"data"`
+	}{}
+
+	err = json.Unmarshal(body, &alerts)
+	if err != nil {
+		setCondition(metav1.ConditionUnknown, "InvalidAlertsResponse", err.Error())
+		return
+	}
+
+	var degraded []string
+	for _, alert := range alerts.Data.Alerts {
+		if alert.State != "firing" || !isRemoteWriteAlert(alert.Labels["alertname"]) {
+			continue
+		}
+		degraded = append(degraded, fmt.Sprintf("%v: %v", alert.Labels["alertname"], alert.Labels["url"]))
+	}
+
+	if len(degraded) == 0 {
+		setCondition(metav1.ConditionFalse, "RemoteWriteHealthy", "all remote write endpoints are healthy")
+		return
+	}
+
+	// Keep the message stable between reconciles
+	sort.Strings(degraded)
+	setCondition(metav1.ConditionTrue, "RemoteWriteAlertsFiring", strings.Join(degraded, ", "))
+}
+
+func remoteWriteHealthMode(cr *v1.Observability) string {
+	if cr.PrometheusAgentEnabled() {
+		return "agent"
+	}
+	if cr.UserWorkloadMonitoringEnabled() {
+		return "user workload monitoring"
+	}
+	return strings.ToLower(string(cr.PrometheusMode()))
+}
+
+func isRemoteWriteAlert(name string) bool {
+	for _, alert := range remoteWriteAlerts {
+		if alert == name {
+			return true
+		}
+	}
+	return false
+}
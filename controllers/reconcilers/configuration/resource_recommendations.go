@@ -0,0 +1,191 @@
+package configuration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	RecommendationInterval = time.Hour
+	// Usage window the p95 is computed over
+	RecommendationWindow = "1d"
+	// Headroom added on top of the p95 usage
+	RecommendationHeadroom = 1.2
+)
+
+// Container names of the components, matched with a regex to cover the per-config token refreshers
+var recommendationContainers = map[string]string{
+	v1.ResourcesGrafana:            "grafana",
+	v1.ResourcesGrafanaOperator:    "grafana-operator",
+	v1.ResourcesPrometheus:         "prometheus",
+	v1.ResourcesPrometheusOperator: "prometheus-operator",
+	v1.ResourcesAlertmanager:       "alertmanager",
+	v1.ResourcesPromtail:           "promtail",
+	v1.ResourcesTokenRefresher:     "token-refresher-.+",
+	v1.ResourcesTempo:              "tempo",
+}
+
+func getConfiguredRequests(cr *v1.Observability, component string) v12.ResourceList {
+	switch component {
+	case v1.ResourcesGrafana:
+		return model.GetGrafanaResourceRequirement(cr).Requests
+	case v1.ResourcesGrafanaOperator:
+		return model.GetGrafanaOperatorResourceRequirement(cr).Requests
+	case v1.ResourcesPrometheus:
+		return model.GetPrometheusResourceRequirement(cr).Requests
+	case v1.ResourcesPrometheusOperator:
+		return model.GetPrometheusOperatorResourceRequirement(cr).Requests
+	case v1.ResourcesAlertmanager:
+		return model.GetAlertmanagerResourceRequirement(cr).Requests
+	case v1.ResourcesPromtail:
+		return model.GetPromtailResourceRequirement(cr).Requests
+	case v1.ResourcesTokenRefresher:
+		return model.GetTokenRefresherResourceRequirement(cr).Requests
+	case v1.ResourcesTempo:
+		return model.GetTempoResourceRequirement(cr).Requests
+	default:
+		return nil
+	}
+}
+
+// Run an instant query against Prometheus and return the value of the first sample. Returns
+// false if the query has no result, e.g. when the container metrics are not scraped
+func (r *Reconciler) queryScalar(cr *v1.Observability, query string) (float64, bool, error) {
+	queryUrl := fmt.Sprintf("%s/api/v1/query?%v", model.GetPrometheusQueryUrl(cr), url.Values{
+		"query": []string{query},
+	}.Encode())
+
+	resp, err := r.httpClient.Get(queryUrl)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, fmt.Errorf("unexpected status code from prometheus: %v", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, false, err
+	}
+
+	result := struct {
+		Data struct {
+			Result []struct {
+				Value []interface{} `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}{}
+
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(result.Data.Result) == 0 || len(result.Data.Result[0].Value) != 2 {
+		return 0, false, nil
+	}
+
+	value, ok := result.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, false, fmt.Errorf("unexpected sample value in query result: %v", result.Data.Result[0].Value[1])
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, false, err
+	}
+	return f, true, nil
+}
+
+func (r *Reconciler) getResourceRecommendation(cr *v1.Observability, component string) (*v1.ResourceRecommendation, error) {
+	selector := fmt.Sprintf(`namespace="%v",container=~"%v"`, cr.Namespace, recommendationContainers[component])
+
+	cpu, hasCpu, err := r.queryScalar(cr, fmt.Sprintf(
+		"max(quantile_over_time(0.95, rate(container_cpu_usage_seconds_total{%v}[5m])[%v:5m]))",
+		selector, RecommendationWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	memory, hasMemory, err := r.queryScalar(cr, fmt.Sprintf(
+		"max(quantile_over_time(0.95, container_memory_working_set_bytes{%v}[%v]))",
+		selector, RecommendationWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasCpu && !hasMemory {
+		return nil, nil
+	}
+
+	recommendation := &v1.ResourceRecommendation{
+		Component:   component,
+		Requests:    getConfiguredRequests(cr, component),
+		Usage:       v12.ResourceList{},
+		Recommended: v12.ResourceList{},
+	}
+
+	if hasCpu {
+		recommendation.Usage[v12.ResourceCPU] = *resource.NewMilliQuantity(int64(math.Ceil(cpu*1000)), resource.DecimalSI)
+		recommendation.Recommended[v12.ResourceCPU] = *resource.NewMilliQuantity(int64(math.Ceil(cpu*1000*RecommendationHeadroom)), resource.DecimalSI)
+	}
+
+	if hasMemory {
+		// Round to MiB to keep the values readable
+		recommendation.Usage[v12.ResourceMemory] = *resource.NewQuantity(int64(math.Ceil(memory/(1<<20)))<<20, resource.BinarySI)
+		recommendation.Recommended[v12.ResourceMemory] = *resource.NewQuantity(int64(math.Ceil(memory*RecommendationHeadroom/(1<<20)))<<20, resource.BinarySI)
+	}
+
+	return recommendation, nil
+}
+
+// Publish right-sizing recommendations for the components in the status. Requires the cAdvisor
+// container metrics to be available in Prometheus, e.g. federated from openshift-monitoring
+func (r *Reconciler) updateResourceRecommendations(cr *v1.Observability, s *v1.ObservabilityStatus) {
+	if cr.Status.ResourceRecommendationsUpdated != 0 {
+		lastUpdated := time.Unix(cr.Status.ResourceRecommendationsUpdated, 0)
+		if time.Now().Before(lastUpdated.Add(RecommendationInterval)) {
+			return
+		}
+	}
+
+	// Failed attempts count as well, the queries are retried after the interval and not on every reconcile
+	s.ResourceRecommendationsUpdated = time.Now().Unix()
+
+	var recommendations []v1.ResourceRecommendation
+	for _, component := range []string{
+		v1.ResourcesGrafana,
+		v1.ResourcesGrafanaOperator,
+		v1.ResourcesPrometheus,
+		v1.ResourcesPrometheusOperator,
+		v1.ResourcesAlertmanager,
+		v1.ResourcesPromtail,
+		v1.ResourcesTokenRefresher,
+		v1.ResourcesTempo,
+	} {
+		recommendation, err := r.getResourceRecommendation(cr, component)
+		if err != nil {
+			// Keep the previous recommendations
+			r.logger.Error(err, "error computing resource recommendation", "component", component)
+			return
+		}
+		if recommendation != nil {
+			recommendations = append(recommendations, *recommendation)
+		}
+	}
+
+	s.ResourceRecommendations = recommendations
+}
@@ -0,0 +1,104 @@
+package configuration
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+)
+
+// Thanos object storage config, only the fields of the S3 type are read
+type objectStorageConfig struct {
+	Type   string `json:"type"`
+	Config struct {
+		Bucket    string `json:"bucket"`
+		Endpoint  string `json:"endpoint"`
+		Region    string `json:"region"`
+		AccessKey string `json:"access_key"`
+		SecretKey string `json:"secret_key"`
+		Insecure  bool   `json:"insecure"`
+	} `json:"config"`
+}
+
+// Minimal client for S3 compatible object storage, requests use path style urls
+type s3Client struct {
+	httpClient  *http.Client
+	baseUrl     string
+	host        string
+	bucket      string
+	credentials utils.AWSCredentials
+}
+
+func newS3Client(httpClient *http.Client, source []byte) (*s3Client, error) {
+	config := &objectStorageConfig{}
+	err := yaml.Unmarshal(source, config)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(config.Type, "S3") {
+		return nil, fmt.Errorf("object storage type %v is not supported, S3 is required", config.Type)
+	}
+	if config.Config.Bucket == "" || config.Config.Endpoint == "" {
+		return nil, fmt.Errorf("object storage config requires a bucket and an endpoint")
+	}
+
+	scheme := "https"
+	if config.Config.Insecure {
+		scheme = "http"
+	}
+	region := config.Config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &s3Client{
+		httpClient: httpClient,
+		baseUrl:    fmt.Sprintf("%v://%v", scheme, config.Config.Endpoint),
+		host:       config.Config.Endpoint,
+		bucket:     config.Config.Bucket,
+		credentials: utils.AWSCredentials{
+			Region:    region,
+			AccessKey: config.Config.AccessKey,
+			SecretKey: config.Config.SecretKey,
+		},
+	}, nil
+}
+
+func (c *s3Client) put(key string, body []byte) error {
+	_, err := c.do(http.MethodPut, key, body)
+	return err
+}
+
+func (c *s3Client) get(key string) ([]byte, error) {
+	return c.do(http.MethodGet, key, nil)
+}
+
+func (c *s3Client) do(method string, key string, body []byte) ([]byte, error) {
+	path := fmt.Sprintf("/%v/%v", c.bucket, key)
+	req, err := http.NewRequest(method, c.baseUrl+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Host", c.host)
+	utils.SignAWSRequest(req, body, "s3", c.credentials, time.Now().UTC())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from object storage for %v %v: %v", method, key, resp.StatusCode)
+	}
+	return respBody, nil
+}
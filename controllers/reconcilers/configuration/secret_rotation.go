@@ -0,0 +1,164 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	token2 "github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/token"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Returns the secrets with credentials that are referenced from the index files. Secrets without
+// an explicit namespace are expected in the namespace of the CR
+func getReferencedSecrets(cr *v1.Observability, indexes []v1.RepositoryIndex) []v1.ReferencedSecret {
+	var result []v1.ReferencedSecret
+
+	add := func(namespace string, name string) {
+		if name == "" {
+			return
+		}
+		if namespace == "" {
+			namespace = cr.Namespace
+		}
+		for _, secret := range result {
+			if secret.Namespace == namespace && secret.Name == name {
+				return
+			}
+		}
+		result = append(result, v1.ReferencedSecret{
+			Namespace: namespace,
+			Name:      name,
+		})
+	}
+
+	for _, index := range indexes {
+		if index.Config == nil {
+			continue
+		}
+
+		if index.Config.Alertmanager != nil {
+			add(index.Config.Alertmanager.PagerDutySecretNamespace, index.Config.Alertmanager.PagerDutySecretName)
+			add(index.Config.Alertmanager.DeadmansSnitchSecretNamespace, index.Config.Alertmanager.DeadmansSnitchSecretName)
+		}
+
+		for _, observatorium := range index.Config.Observatoria {
+			// Contains the Dex or Red Hat SSO credentials
+			add(cr.Namespace, observatorium.SecretName)
+			if observatorium.DexConfig != nil {
+				add(observatorium.DexConfig.CredentialSecretNamespace, observatorium.DexConfig.CredentialSecretName)
+			}
+		}
+	}
+
+	return result
+}
+
+// Missing secrets hash to an empty string, so that creating them later is detected as a change
+func (r *Reconciler) getSecretHash(ctx context.Context, namespace string, name string) (string, error) {
+	secret := &v12.Secret{}
+	selector := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+
+	err := r.client.Get(ctx, selector, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	var keys []string
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write(secret.Data[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// Returns the referenced secrets that changed since the last sync
+func (r *Reconciler) getRotatedSecrets(ctx context.Context, s *v1.ObservabilityStatus) ([]v1.ReferencedSecret, error) {
+	var rotated []v1.ReferencedSecret
+	for _, secret := range s.ReferencedSecrets {
+		hash, err := r.getSecretHash(ctx, secret.Namespace, secret.Name)
+		if err != nil {
+			return nil, err
+		}
+		if hash != secret.Hash {
+			rotated = append(rotated, secret)
+		}
+	}
+	return rotated, nil
+}
+
+func (r *Reconciler) updateReferencedSecrets(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, s *v1.ObservabilityStatus) error {
+	secrets := getReferencedSecrets(cr, indexes)
+	for i := range secrets {
+		hash, err := r.getSecretHash(ctx, secrets[i].Namespace, secrets[i].Name)
+		if err != nil {
+			return err
+		}
+		secrets[i].Hash = hash
+	}
+	s.ReferencedSecrets = secrets
+	return nil
+}
+
+// Tokens obtained with rotated credentials are deleted, so that they are fetched again with the
+// new credentials during the sync. Components using the token refresher are rolled out because
+// the credentials are part of the deployment
+func (r *Reconciler) invalidateRotatedTokens(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, rotated []v1.ReferencedSecret) error {
+	isRotated := func(namespace string, name string) bool {
+		if namespace == "" {
+			namespace = cr.Namespace
+		}
+		for _, secret := range rotated {
+			if secret.Namespace == namespace && secret.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, index := range indexes {
+		if index.Config == nil {
+			continue
+		}
+
+		for _, observatorium := range index.Config.Observatoria {
+			credentialsRotated := observatorium.SecretName != "" && isRotated(cr.Namespace, observatorium.SecretName)
+			if observatorium.DexConfig != nil && observatorium.DexConfig.CredentialSecretName != "" &&
+				isRotated(observatorium.DexConfig.CredentialSecretNamespace, observatorium.DexConfig.CredentialSecretName) {
+				credentialsRotated = true
+			}
+
+			if !credentialsRotated {
+				continue
+			}
+
+			token := &v12.Secret{}
+			token.Namespace = cr.Namespace
+			token.Name = token2.GetObservatoriumTokenSecretName(&observatorium)
+
+			err := r.client.Delete(ctx, token)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			r.logger.Info("credentials rotated, invalidated observatorium token", "observatorium", observatorium.Id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package configuration
+
+import (
+	"context"
+
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Records the synthetic metric of the stack verification. Agents evaluate no rules, their remote
+// write queues are checked without it
+func (r *Reconciler) createStackVerificationRules(cr *v1.Observability, ctx context.Context, indexes []v1.RepositoryIndex) error {
+	rule := model.GetStackVerificationRule(cr)
+	if !cr.StackVerificationEnabled() || cr.PrometheusAgentEnabled() {
+		err := r.client.Delete(ctx, rule)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	return utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+
+		// Make sure the rule is picked up by Prometheus
+		selector := model.GetPrometheusRuleLabelSelectors(cr, indexes)
+		if selector != nil {
+			for k, v := range selector.MatchLabels {
+				rule.Labels[k] = v
+			}
+		}
+
+		rule.Spec.Groups = []v12.RuleGroup{
+			{
+				Name:     "stack-verification",
+				Interval: "30s",
+				Rules: []v12.Rule{
+					{
+						Record: model.StackVerificationRecord,
+						Expr:   intstr.FromString("time()"),
+					},
+				},
+			},
+		}
+		return nil
+	})
+}
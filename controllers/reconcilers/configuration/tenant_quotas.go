@@ -0,0 +1,248 @@
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Records the usage and the quotas of the namespaces with a quota and alerts when one is exceeded.
+// The series of a namespace are approximated by the samples of the last scrapes of its targets,
+// counting the series in the TSDB would be too expensive
+func (r *Reconciler) createTenantQuotaRules(cr *v1.Observability, ctx context.Context, indexes []v1.RepositoryIndex) error {
+	rule := model.GetTenantQuotaRule(cr)
+	if len(cr.Spec.TenantQuotas) == 0 {
+		err := r.client.Delete(ctx, rule)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	var namespaces []string
+	var quotas []v12.Rule
+	for _, quota := range cr.Spec.TenantQuotas {
+		namespaces = append(namespaces, quota.Namespace)
+		if quota.MaxSeries > 0 {
+			quotas = append(quotas, v12.Rule{
+				Record: model.TenantMaxSeriesRecord,
+				Expr:   intstr.FromString(fmt.Sprintf(`label_replace(vector(%v), "namespace", "%v", "", "")`, quota.MaxSeries, quota.Namespace)),
+			})
+		}
+		if quota.MaxSamplesPerSecond > 0 {
+			quotas = append(quotas, v12.Rule{
+				Record: model.TenantMaxSamplesRecord,
+				Expr:   intstr.FromString(fmt.Sprintf(`label_replace(vector(%v), "namespace", "%v", "", "")`, quota.MaxSamplesPerSecond, quota.Namespace)),
+			})
+		}
+	}
+	selector := fmt.Sprintf(`namespace=~"%v"`, strings.Join(namespaces, "|"))
+
+	return utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+
+		// Make sure the rule is picked up by Prometheus
+		ruleSelector := model.GetPrometheusRuleLabelSelectors(cr, indexes)
+		if ruleSelector != nil {
+			for k, v := range ruleSelector.MatchLabels {
+				rule.Labels[k] = v
+			}
+		}
+
+		rule.Spec.Groups = []v12.RuleGroup{
+			{
+				Name: "tenant-usage",
+				Rules: append([]v12.Rule{
+					{
+						Record: model.TenantSeriesRecord,
+						Expr:   intstr.FromString(fmt.Sprintf("sum by (namespace) (scrape_samples_post_metric_relabeling{%v})", selector)),
+					},
+					{
+						Record: model.TenantSamplesRecord,
+						Expr:   intstr.FromString(fmt.Sprintf("sum by (namespace) (sum_over_time(scrape_samples_post_metric_relabeling{%v}[5m])) / 300", selector)),
+					},
+				}, quotas...),
+			},
+			{
+				Name: "tenant-quotas",
+				Rules: []v12.Rule{
+					{
+						Alert: model.AlertTenantSeriesQuota,
+						Expr:  intstr.FromString(fmt.Sprintf("%v > on (namespace) %v", model.TenantSeriesRecord, model.TenantMaxSeriesRecord)),
+						For:   model.TenantQuotaExceededWindow,
+						Labels: map[string]string{
+							"severity": "warning",
+						},
+						Annotations: map[string]string{
+							"message": "The targets in namespace {{ $labels.namespace }} expose {{ $value }} series, more than the quota of the namespace.",
+						},
+					},
+					{
+						Alert: model.AlertTenantSamplesQuota,
+						Expr:  intstr.FromString(fmt.Sprintf("%v > on (namespace) %v", model.TenantSamplesRecord, model.TenantMaxSamplesRecord)),
+						For:   model.TenantQuotaExceededWindow,
+						Labels: map[string]string{
+							"severity": "warning",
+						},
+						Annotations: map[string]string{
+							"message": "Prometheus ingests {{ $value }} samples per second from namespace {{ $labels.namespace }}, more than the quota of the namespace.",
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+}
+
+// Updates the usage of the tenant quotas and suspends the scraping of namespaces that exceed an
+// enforced quota. Returns true if a namespace was suspended or resumed, the namespace selectors of
+// Prometheus have to be updated then
+func (r *Reconciler) checkTenantQuotas(cr *v1.Observability, s *v1.ObservabilityStatus) bool {
+	if len(cr.Spec.TenantQuotas) == 0 {
+		suspended := len(model.GetSuspendedTenants(s)) > 0
+		s.TenantQuotas = nil
+		return suspended
+	}
+
+	queries := []string{
+		model.TenantSeriesRecord,
+		model.TenantSamplesRecord,
+		fmt.Sprintf("min_over_time(%v[%v])", model.TenantSeriesRecord, model.TenantQuotaExceededWindow),
+		fmt.Sprintf("min_over_time(%v[%v])", model.TenantSamplesRecord, model.TenantQuotaExceededWindow),
+	}
+	var usage []map[string]float64
+	for _, query := range queries {
+		result, err := r.queryVector(cr, query, "namespace")
+		if err != nil {
+			// Keep the last known usage until Prometheus is reachable
+			r.logger.Error(err, "error querying tenant usage")
+			return false
+		}
+		usage = append(usage, result)
+	}
+	series, samples, sustainedSeries, sustainedSamples := usage[0], usage[1], usage[2], usage[3]
+
+	previous := map[string]v1.TenantQuotaStatus{}
+	for _, status := range s.TenantQuotas {
+		previous[status.Namespace] = status
+	}
+
+	changed := false
+	var result []v1.TenantQuotaStatus
+	for _, quota := range cr.Spec.TenantQuotas {
+		status := v1.TenantQuotaStatus{
+			Namespace:        quota.Namespace,
+			Series:           int64(math.Round(series[quota.Namespace])),
+			SamplesPerSecond: int64(math.Round(samples[quota.Namespace])),
+			Exceeded: (quota.MaxSeries > 0 && sustainedSeries[quota.Namespace] > float64(quota.MaxSeries)) ||
+				(quota.MaxSamplesPerSecond > 0 && sustainedSamples[quota.Namespace] > float64(quota.MaxSamplesPerSecond)),
+			SuspendedSince: previous[quota.Namespace].SuspendedSince,
+		}
+
+		switch {
+		case !quota.Enforce:
+			status.SuspendedSince = 0
+		case status.SuspendedSince != 0:
+			// Scraping resumes after the suspension, the namespace is suspended again if it still
+			// exceeds its quota
+			if time.Now().After(time.Unix(status.SuspendedSince, 0).Add(model.TenantSuspensionDuration)) {
+				status.SuspendedSince = 0
+			}
+		case status.Exceeded:
+			status.SuspendedSince = time.Now().Unix()
+			r.recorder.Eventf(cr, corev1.EventTypeWarning, v1.EventTenantSuspended, "namespace %v exceeds its quota, its targets are not scraped for %v", quota.Namespace, model.TenantSuspensionDuration)
+		}
+
+		if (status.SuspendedSince != 0) != (previous[quota.Namespace].SuspendedSince != 0) {
+			changed = true
+		}
+		delete(previous, quota.Namespace)
+		result = append(result, status)
+	}
+
+	// Namespaces of removed quotas are resumed
+	for _, status := range previous {
+		if status.SuspendedSince != 0 {
+			changed = true
+		}
+	}
+
+	s.TenantQuotas = result
+	return changed
+}
+
+func (r *Reconciler) queryVector(cr *v1.Observability, query string, label string) (map[string]float64, error) {
+	return QueryVector(r.httpClient, cr, query, label)
+}
+
+// QueryVector runs an instant query against the Prometheus of the CR and returns the values of the
+// samples by the value of the label
+func QueryVector(httpClient *http.Client, cr *v1.Observability, query string, label string) (map[string]float64, error) {
+	queryUrl := fmt.Sprintf("%s/api/v1/query?%v", model.GetPrometheusQueryUrl(cr), url.Values{
+		"query": []string{query},
+	}.Encode())
+
+	resp, err := httpClient.Get(queryUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from prometheus: %v", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Data struct {
+			Result []struct {
+				Metric map[string]string `json:"metric"`
+				Value  []interface{}     `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}{}
+
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]float64{}
+	for _, sample := range result.Data.Result {
+		if len(sample.Value) != 2 {
+			continue
+		}
+		value, ok := sample.Value[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected sample value in query result: %v", sample.Value[1])
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(f) {
+			continue
+		}
+		values[sample.Metric[label]] = f
+	}
+	return values, nil
+}
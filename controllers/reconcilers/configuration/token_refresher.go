@@ -7,16 +7,20 @@ import (
 	"path"
 
 	errors2 "github.com/pkg/errors"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	v13 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	v12 "k8s.io/api/core/v1"
 	v15 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v14 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
@@ -69,7 +73,11 @@ func getTokenRefresherConfigSetFor(t model.TokenRefresherType, observatorium *v1
 func (r *Reconciler) createServiceFor(ctx context.Context, cr *v1.Observability, config *model.TokenRefresherConfigSet) error {
 	service := model.GetTokenRefresherService(cr, config.Name)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
+	err := utils.Apply(ctx, r.client, service, func() error {
+		service.Labels = map[string]string{
+			"app.kubernetes.io/component": "authentication-proxy",
+			"app.kubernetes.io/name":      config.Name,
+		}
 		service.Spec.Ports = []v12.ServicePort{
 			{
 				Name:        "http",
@@ -81,6 +89,11 @@ func (r *Reconciler) createServiceFor(ctx context.Context, cr *v1.Observability,
 				},
 				NodePort: 0,
 			},
+			{
+				Name:       "metrics",
+				Port:       model.TokenRefresherMetricsPort,
+				TargetPort: intstr.FromInt(model.TokenRefresherMetricsPort),
+			},
 		}
 		service.Spec.Selector = map[string]string{
 			"app.kubernetes.io/component": "authentication-proxy",
@@ -88,8 +101,11 @@ func (r *Reconciler) createServiceFor(ctx context.Context, cr *v1.Observability,
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
 }
 
 func (r *Reconciler) createNetworkPolicyFor(ctx context.Context, cr *v1.Observability, config *model.TokenRefresherConfigSet) error {
@@ -103,7 +119,7 @@ func (r *Reconciler) createNetworkPolicyFor(ctx context.Context, cr *v1.Observab
 		selector["app"] = "prometheus"
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, policy, func() error {
+	err := utils.Apply(ctx, r.client, policy, func() error {
 		policy.Labels = map[string]string{
 			"app.kubernetes.io/component": "authentication-proxy",
 		}
@@ -141,12 +157,13 @@ func (r *Reconciler) createDeploymentFor(ctx context.Context, cr *v1.Observabili
 		return err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, deployment, func() error {
+	err = utils.Apply(ctx, r.client, deployment, func() error {
 		deployment.Labels = map[string]string{
 			"app.kubernetes.io/component": "authentication-proxy",
 			"app.kubernetes.io/name":      config.Name,
 		}
 		deployment.Spec = v13.DeploymentSpec{
+			Replicas: model.GetTokenRefresherReplicas(cr),
 			Selector: &v14.LabelSelector{
 				MatchLabels: map[string]string{
 					"app.kubernetes.io/component": "authentication-proxy",
@@ -163,12 +180,33 @@ func (r *Reconciler) createDeploymentFor(ctx context.Context, cr *v1.Observabili
 				},
 				Spec: v12.PodSpec{
 					PriorityClassName: model.ObservabilityPriorityClassName,
+					// Spread the replicas over the nodes, so a node failure doesn't stop the remote writes
+					Affinity: &v12.Affinity{
+						PodAntiAffinity: &v12.PodAntiAffinity{
+							PreferredDuringSchedulingIgnoredDuringExecution: []v12.WeightedPodAffinityTerm{
+								{
+									Weight: 100,
+									PodAffinityTerm: v12.PodAffinityTerm{
+										LabelSelector: &v14.LabelSelector{
+											MatchLabels: map[string]string{
+												"app.kubernetes.io/name": config.Name,
+											},
+										},
+										TopologyKey: "kubernetes.io/hostname",
+									},
+								},
+							},
+						},
+					},
 					Containers: []v12.Container{
 						{
 							Name:            config.Name,
-							Image:           fmt.Sprintf("quay.io/rhoas/mk-token-refresher:%v", TokenRefresherImageTag),
+							Image:           model.GetImage(cr, v1.ImageTokenRefresher, fmt.Sprintf("%v:%v", model.TokenRefresherImage, TokenRefresherImageTag)),
 							ImagePullPolicy: v12.PullAlways,
+							Resources:       model.GetTokenRefresherResourceRequirement(cr),
 							Args: []string{
+								fmt.Sprintf("--web.listen=%v", model.GetListenAddress(cr, 8080)),
+								fmt.Sprintf("--web.internal.listen=%v", model.GetListenAddress(cr, model.TokenRefresherMetricsPort)),
 								"--oidc.audience=observatorium-telemeter",
 								fmt.Sprintf("--oidc.client-id=%v", config.Client),
 								fmt.Sprintf("--oidc.client-secret=%v", config.Secret),
@@ -180,6 +218,10 @@ func (r *Reconciler) createDeploymentFor(ctx context.Context, cr *v1.Observabili
 									Name:          "http",
 									ContainerPort: 8080,
 								},
+								{
+									Name:          "metrics",
+									ContainerPort: model.TokenRefresherMetricsPort,
+								},
 							},
 						},
 					},
@@ -188,11 +230,83 @@ func (r *Reconciler) createDeploymentFor(ctx context.Context, cr *v1.Observabili
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return r.reconcileTokenRefresherAutoscaler(ctx, cr, config)
+}
+
+// Without autoscaling the replicas of the deployment are set from the CR and the autoscaler is removed
+func (r *Reconciler) reconcileTokenRefresherAutoscaler(ctx context.Context, cr *v1.Observability, config *model.TokenRefresherConfigSet) error {
+	autoscaler := model.GetTokenRefresherAutoscaler(cr, config.Name)
+	autoscaling := model.GetTokenRefresherAutoscaling(cr)
+	if autoscaling == nil {
+		err := r.client.Delete(ctx, autoscaler)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	target := int32(model.TokenRefresherDefaultTargetCPU)
+	if autoscaling.TargetCPUUtilization != nil {
+		target = *autoscaling.TargetCPUUtilization
+	}
+	return utils.Apply(ctx, r.client, autoscaler, func() error {
+		autoscaler.Labels = map[string]string{
+			"app.kubernetes.io/component": "authentication-proxy",
+			"app.kubernetes.io/name":      config.Name,
+		}
+		autoscaler.Spec = autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       config.Name,
+			},
+			MinReplicas:                    autoscaling.MinReplicas,
+			MaxReplicas:                    autoscaling.MaxReplicas,
+			TargetCPUUtilizationPercentage: &target,
+		}
+		return nil
+	})
 }
 
-func (r *Reconciler) reconcileTokenRefresherFor(ctx context.Context, cr *v1.Observability, observatorium *v1.ObservatoriumIndex, logsDisabled bool) error {
+// Prometheus scrapes the error metrics of the token refreshers through a service monitor. The job is
+// named like the jobs of the self monitoring, which alerts on the errors
+func (r *Reconciler) createServiceMonitorFor(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, config *model.TokenRefresherConfigSet) error {
+	monitor := model.GetTokenRefresherServiceMonitor(cr, config.Name)
+	return utils.Apply(ctx, r.client, monitor, func() error {
+		labels := map[string]string{}
+		for key, value := range model.GetPrometheusServiceMonitorLabelSelectors(cr, indexes).MatchLabels {
+			labels[key] = value
+		}
+		labels["app.kubernetes.io/component"] = "authentication-proxy"
+		monitor.Labels = labels
+		monitor.Spec = prometheusv1.ServiceMonitorSpec{
+			Selector: v14.LabelSelector{
+				MatchLabels: map[string]string{
+					"app.kubernetes.io/component": "authentication-proxy",
+					"app.kubernetes.io/name":      config.Name,
+				},
+			},
+			Endpoints: []prometheusv1.Endpoint{
+				{
+					Port: "metrics",
+					RelabelConfigs: []*prometheusv1.RelabelConfig{
+						{
+							TargetLabel: "job",
+							Replacement: model.GetSelfMonitoringJob("token-refresher"),
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+}
+
+func (r *Reconciler) reconcileTokenRefresherFor(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, observatorium *v1.ObservatoriumIndex, logsDisabled bool) error {
 	if !observatorium.IsValid() {
 		return errors2.New(fmt.Sprintf("incomplete observatorium config, tenant or gateway missing for %v", observatorium.Id))
 	}
@@ -223,6 +337,13 @@ func (r *Reconciler) reconcileTokenRefresherFor(ctx context.Context, cr *v1.Obse
 		if err != nil {
 			return err
 		}
+
+		if cr.PrometheusMode() != v1.ComponentDisabled {
+			err = r.createServiceMonitorFor(ctx, cr, indexes, configSet)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -242,7 +363,7 @@ func (r *Reconciler) reconcileTokenRefresher(ctx context.Context, cr *v1.Observa
 		for _, observatorium := range index.Config.Observatoria {
 			// token-refresher is only used for sso.redhat.com authentication
 			if observatorium.AuthType == v1.AuthTypeRedhat {
-				err := r.reconcileTokenRefresherFor(ctx, cr, &observatorium, promtailDisabled)
+				err := r.reconcileTokenRefresherFor(ctx, cr, indexes, &observatorium, promtailDisabled)
 				if err != nil {
 					return err
 				}
@@ -369,8 +490,27 @@ func (r *Reconciler) deleteUnrequestedTokenRefreshers(ctx context.Context, cr *v
 			if err != nil {
 				return err
 			}
+
+			err = r.deleteTokenRefresherExtras(ctx, cr, deployment.Name)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
+
+// Deletes the autoscaler and the service monitor of a token refresher
+func (r *Reconciler) deleteTokenRefresherExtras(ctx context.Context, cr *v1.Observability, name string) error {
+	err := r.client.Delete(ctx, model.GetTokenRefresherAutoscaler(cr, name))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	err = r.client.Delete(ctx, model.GetTokenRefresherServiceMonitor(cr, name))
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package configuration
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Hash of the fields of a workload that restart its pods, as last rolled out
+const RolloutHashAnnotation = "observability-operator/rollout-hash"
+
+// Called at the end of a mutate function. Outside of the upgrade window a changed rollout of an
+// existing workload is deferred: restore puts back the fields of the existing object and the hash
+// is left as it is, so the first sync in the window rolls out the requested fields. Workloads
+// without the hash are deferred too, the rollout in the window is a no-op if nothing changed
+func deferRollout(cr *v1.Observability, s *v1.ObservabilityStatus, object metav1.Object, rollout interface{}, restore func()) error {
+	bytes, err := json.Marshal(rollout)
+	if err != nil {
+		return err
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256(bytes))
+
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if object.GetResourceVersion() != "" && annotations[RolloutHashAnnotation] != hash && !model.RolloutAllowed(cr, time.Now()) {
+		restore()
+		s.SetDeferredRollout(object.GetName(), "pod template")
+		return nil
+	}
+
+	annotations[RolloutHashAnnotation] = hash
+	object.SetAnnotations(annotations)
+	s.SetDeferredRollout(object.GetName(), "")
+	return nil
+}
+
+// Copies the fields of the Prometheus spec that are reloaded without restarting the pods. They
+// are applied immediately, also when the rollout is deferred
+func setPrometheusReloadedFields(dst *prometheusv1.PrometheusSpec, src *prometheusv1.PrometheusSpec) {
+	dst.PodMonitorSelector = src.PodMonitorSelector
+	dst.PodMonitorNamespaceSelector = src.PodMonitorNamespaceSelector
+	dst.ServiceMonitorSelector = src.ServiceMonitorSelector
+	dst.ServiceMonitorNamespaceSelector = src.ServiceMonitorNamespaceSelector
+	dst.RuleSelector = src.RuleSelector
+	dst.RuleNamespaceSelector = src.RuleNamespaceSelector
+	dst.ProbeSelector = src.ProbeSelector
+	dst.ProbeNamespaceSelector = src.ProbeNamespaceSelector
+	dst.AdditionalScrapeConfigs = src.AdditionalScrapeConfigs
+	dst.RemoteWrite = src.RemoteWrite
+	dst.RemoteRead = src.RemoteRead
+	dst.Alerting = src.Alerting
+	dst.ExternalLabels = src.ExternalLabels
+	dst.ScrapeInterval = src.ScrapeInterval
+	dst.EvaluationInterval = src.EvaluationInterval
+}
+
+// Defers the fields of a Prometheus spec that restart the pods, existing is the spec before the
+// mutate function changed it
+func deferPrometheusRollout(cr *v1.Observability, s *v1.ObservabilityStatus, prometheus *prometheusv1.Prometheus, existing *prometheusv1.PrometheusSpec) error {
+	rollout := prometheus.Spec.DeepCopy()
+	setPrometheusReloadedFields(rollout, &prometheusv1.PrometheusSpec{})
+	return deferRollout(cr, s, prometheus, rollout, func() {
+		requested := prometheus.Spec
+		prometheus.Spec = *existing
+		setPrometheusReloadedFields(&prometheus.Spec, &requested)
+	})
+}
@@ -0,0 +1,330 @@
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	MonitoringConfigKey = "config.yaml"
+	// Remote write targets of the user workload Prometheus with this prefix, followed by the namespace
+	// and name of the CR, are managed by the operator
+	UserWorkloadRemoteWritePrefix = "observability-operator-"
+	// Prefix of the targets of older versions, which didn't scope the targets by CR. Only the targets
+	// a CR replaces are removed with it, other targets with the prefix may belong to the admins
+	legacyUserWorkloadRemoteWritePrefix = "observability-"
+)
+
+// Several CRs share the user workload monitoring config, each of them only manages its own targets
+func getUserWorkloadRemoteWritePrefix(cr *v1.Observability) string {
+	return fmt.Sprintf("%v%v-%v-", UserWorkloadRemoteWritePrefix, cr.Namespace, cr.Name)
+}
+
+// Change the config.yaml of one of the cluster monitoring config maps, keeping the settings
+// made by the cluster admins
+func updateMonitoringConfig(configMap *kv1.ConfigMap, update func(config map[string]interface{})) error {
+	config := map[string]interface{}{}
+	if data := configMap.Data[MonitoringConfigKey]; data != "" {
+		err := yaml.Unmarshal([]byte(data), &config)
+		if err != nil {
+			return err
+		}
+	}
+
+	update(config)
+
+	bytes, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[MonitoringConfigKey] = string(bytes)
+	return nil
+}
+
+// Replace the remote write targets with the prefix of a CR, targets of the cluster admins and of other
+// CRs are kept. Targets of older versions are replaced by the targets with the same name and the prefix
+func setUserWorkloadRemoteWrites(config map[string]interface{}, prefix string, remoteWrites []interface{}) {
+	prometheus, _ := config["prometheus"].(map[string]interface{})
+	if prometheus == nil {
+		prometheus = map[string]interface{}{}
+	}
+
+	replaced := map[string]bool{}
+	for _, remoteWrite := range remoteWrites {
+		if target, ok := remoteWrite.(map[string]interface{}); ok {
+			name, _ := target["name"].(string)
+			replaced[legacyUserWorkloadRemoteWritePrefix+strings.TrimPrefix(name, prefix)] = true
+		}
+	}
+
+	existing, _ := prometheus["remoteWrite"].([]interface{})
+	var result []interface{}
+	for _, remoteWrite := range existing {
+		if target, ok := remoteWrite.(map[string]interface{}); ok {
+			if name, _ := target["name"].(string); strings.HasPrefix(name, prefix) || replaced[name] {
+				continue
+			}
+		}
+		result = append(result, remoteWrite)
+	}
+	result = append(result, remoteWrites...)
+
+	if len(result) == 0 {
+		delete(prometheus, "remoteWrite")
+	} else {
+		prometheus["remoteWrite"] = result
+	}
+
+	if len(prometheus) == 0 {
+		delete(config, "prometheus")
+	} else {
+		config["prometheus"] = prometheus
+	}
+}
+
+func hasUserWorkloadRemoteWrites(configMap *kv1.ConfigMap, prefix string) (bool, error) {
+	config := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(configMap.Data[MonitoringConfigKey]), &config)
+	if err != nil {
+		return false, err
+	}
+
+	prometheus, _ := config["prometheus"].(map[string]interface{})
+	existing, _ := prometheus["remoteWrite"].([]interface{})
+	for _, remoteWrite := range existing {
+		if target, ok := remoteWrite.(map[string]interface{}); ok {
+			if name, _ := target["name"].(string); strings.HasPrefix(name, prefix) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// Remote write targets of the repositories in the format of the user workload monitoring config.
+// The user workload Prometheus can't mount the token secrets, so only observatoria that are
+// reached through the token refresher are supported.
+func (r *Reconciler) getUserWorkloadRemoteWrites(cr *v1.Observability, indexes []v1.RepositoryIndex) ([]interface{}, error) {
+	if cr.ObservatoriumDisabled() {
+		return nil, nil
+	}
+
+	var result []interface{}
+	for _, index := range indexes {
+		rw, err := r.getRemoteWriteIndex(index)
+		if err != nil {
+			return nil, err
+		}
+
+		remoteWrite, tokenSecret, err := r.getRemoteWriteSpec(cr, index, rw)
+		if err != nil {
+			r.logger.Error(err, "error creating remote write target", "index", index.Id)
+			continue
+		}
+		if tokenSecret != "" {
+			r.logger.Info("remote write with a token secret is not supported by user workload monitoring", "index", index.Id)
+			continue
+		}
+		remoteWrite.Name = getUserWorkloadRemoteWritePrefix(cr) + remoteWrite.Name
+
+		bytes, err := json.Marshal(remoteWrite)
+		if err != nil {
+			return nil, err
+		}
+		target := map[string]interface{}{}
+		err = json.Unmarshal(bytes, &target)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, target)
+	}
+	return result, nil
+}
+
+// Enable user workload monitoring in the cluster monitoring config and add the remote write targets
+// to the user workload monitoring config. When disabled, the remote write targets are removed again.
+func (r *Reconciler) reconcileUserWorkloadMonitoring(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	userWorkloadConfig := model.GetUserWorkloadMonitoringConfigMap()
+
+	if !cr.UserWorkloadMonitoringEnabled() {
+		selector := client.ObjectKey{Namespace: userWorkloadConfig.Namespace, Name: userWorkloadConfig.Name}
+		err := r.client.Get(ctx, selector, userWorkloadConfig)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		managed, err := hasUserWorkloadRemoteWrites(userWorkloadConfig, getUserWorkloadRemoteWritePrefix(cr))
+		if err != nil || !managed {
+			return err
+		}
+
+		err = updateMonitoringConfig(userWorkloadConfig, func(config map[string]interface{}) {
+			setUserWorkloadRemoteWrites(config, getUserWorkloadRemoteWritePrefix(cr), nil)
+		})
+		if err != nil {
+			return err
+		}
+		return r.client.Update(ctx, userWorkloadConfig)
+	}
+
+	// The monitoring config maps are shared with the cluster admins in a single key and can't be
+	// applied, they are updated in place instead
+	clusterConfig := model.GetClusterMonitoringConfigMap()
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, clusterConfig, func() error {
+		return updateMonitoringConfig(clusterConfig, func(config map[string]interface{}) {
+			config["enableUserWorkload"] = true
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	remoteWrites, err := r.getUserWorkloadRemoteWrites(cr, indexes)
+	if err != nil {
+		return err
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.client, userWorkloadConfig, func() error {
+		return updateMonitoringConfig(userWorkloadConfig, func(config map[string]interface{}) {
+			setUserWorkloadRemoteWrites(config, getUserWorkloadRemoteWritePrefix(cr), remoteWrites)
+		})
+	})
+	return err
+}
+
+// The user workload Prometheus only selects pods in the namespace of a pod monitor and restricts
+// rules to the metrics of their namespace. Pod monitors that select other namespaces are copied
+// into these namespaces, together with the rules.
+func (r *Reconciler) reconcileUserWorkloadResources(cr *v1.Observability, ctx context.Context, monitors []ResourceInfo, rules []ResourceInfo) error {
+	requested := map[string]bool{}
+
+	if cr.UserWorkloadMonitoringEnabled() {
+		namespaces := map[string]bool{}
+		for _, resource := range monitors {
+			bytes, err := r.fetchResource(resource.Url, resource.Tag, resource.AccessToken)
+			if err != nil {
+				return err
+			}
+
+			monitor, err := parsePodMonitorFromYaml(cr, resource.Name, bytes)
+			if err != nil {
+				return err
+			}
+
+			if monitor.Spec.NamespaceSelector.Any {
+				r.logger.Info("pod monitors selecting any namespace are not supported by user workload monitoring", "monitor", monitor.Name)
+			}
+
+			for _, namespace := range monitor.Spec.NamespaceSelector.MatchNames {
+				if namespace == cr.Namespace {
+					continue
+				}
+				namespaces[namespace] = true
+
+				requestedSpec := monitor.Spec
+				requestedSpec.NamespaceSelector = prometheusv1.NamespaceSelector{}
+				requestedLabels := monitor.Labels
+
+				podMonitor := &prometheusv1.PodMonitor{}
+				podMonitor.Name = monitor.Name
+				podMonitor.Namespace = namespace
+				err = utils.Apply(ctx, r.client, podMonitor, func() error {
+					podMonitor.Spec = requestedSpec
+					podMonitor.Labels = MergeLabels(model.GetUserWorkloadStackLabels(cr), requestedLabels)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				requested["PodMonitor/"+namespace+"/"+monitor.Name] = true
+			}
+		}
+
+		for _, rule := range rules {
+			bytes, err := r.fetchResource(rule.Url, rule.Tag, rule.AccessToken)
+			if err != nil {
+				return err
+			}
+
+			for namespace := range namespaces {
+				parsedRule, err := parseRuleFromYaml(cr, rule.Name, bytes)
+				if err != nil {
+					return err
+				}
+				parsedRule.Namespace = namespace
+				err = selectSourceRules(cr, rule, parsedRule)
+				if err != nil {
+					return err
+				}
+
+				requestedSpec := parsedRule.Spec
+				requestedLabels := parsedRule.Labels
+
+				err = utils.Apply(ctx, r.client, parsedRule, func() error {
+					parsedRule.Spec = requestedSpec
+					parsedRule.Labels = MergeLabels(model.GetUserWorkloadStackLabels(cr), requestedLabels)
+					injectIdLabel(parsedRule, rule.Id)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				requested["PrometheusRule/"+namespace+"/"+parsedRule.Name] = true
+			}
+		}
+	}
+
+	// Delete the copies that are no longer requested
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(model.GetUserWorkloadStackLabels(cr)),
+	}
+
+	existingMonitors := &prometheusv1.PodMonitorList{}
+	err := r.client.List(ctx, existingMonitors, opts)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range existingMonitors.Items {
+		if !requested["PodMonitor/"+monitor.Namespace+"/"+monitor.Name] {
+			err = r.client.Delete(ctx, monitor)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	existingRules := &prometheusv1.PrometheusRuleList{}
+	err = r.client.List(ctx, existingRules, opts)
+	if err != nil {
+		return err
+	}
+	for _, rule := range existingRules.Items {
+		if !requested["PrometheusRule/"+rule.Namespace+"/"+rule.Name] {
+			err = r.client.Delete(ctx, rule)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
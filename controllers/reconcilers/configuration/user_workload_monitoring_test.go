@@ -0,0 +1,66 @@
+package configuration
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetUserWorkloadRemoteWrites(t *testing.T) {
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Namespace: "kafka", Name: "observability"}}
+	prefix := getUserWorkloadRemoteWritePrefix(cr)
+	target := func(name string) interface{} {
+		return map[string]interface{}{"name": name}
+	}
+
+	tests := []struct {
+		name         string
+		existing     []interface{}
+		remoteWrites []interface{}
+		want         []interface{}
+	}{
+		{
+			name:         "replaces the targets of the CR",
+			existing:     []interface{}{target(prefix + "old")},
+			remoteWrites: []interface{}{target(prefix + "new")},
+			want:         []interface{}{target(prefix + "new")},
+		},
+		{
+			name:         "keeps the targets of the admins with the prefix of older versions",
+			existing:     []interface{}{target("observability-thanos"), target("central")},
+			remoteWrites: []interface{}{target(prefix + "new")},
+			want:         []interface{}{target("observability-thanos"), target("central"), target(prefix + "new")},
+		},
+		{
+			name:         "keeps the targets of other CRs",
+			existing:     []interface{}{target(UserWorkloadRemoteWritePrefix + "redis-observability-main")},
+			remoteWrites: []interface{}{target(prefix + "main")},
+			want:         []interface{}{target(UserWorkloadRemoteWritePrefix + "redis-observability-main"), target(prefix + "main")},
+		},
+		{
+			name:         "replaces the targets of older versions by name",
+			existing:     []interface{}{target("observability-main")},
+			remoteWrites: []interface{}{target(prefix + "main")},
+			want:         []interface{}{target(prefix + "main")},
+		},
+		{
+			name:     "removes the targets of the CR",
+			existing: []interface{}{target(prefix + "main"), target("central")},
+			want:     []interface{}{target("central")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"prometheus": map[string]interface{}{"remoteWrite": tt.existing},
+			}
+			setUserWorkloadRemoteWrites(config, prefix, tt.remoteWrites)
+			prometheus, _ := config["prometheus"].(map[string]interface{})
+			if got := prometheus["remoteWrite"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setUserWorkloadRemoteWrites() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package console_integration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	consolev1 "github.com/openshift/api/console/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// The links, the dashboard and the plugin are cluster scoped or outside of the namespace of the CR,
+// so they are not garbage collected with it
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	err := r.deleteConsoleLinks(ctx, cr, nil)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = r.deleteConsolePlugin(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = r.client.Delete(ctx, model.GetConsoleDashboard(cr))
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+	return v1.ResultSuccess, nil
+}
+
+// Nothing is created on clusters without the console
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if !capabilities.Console {
+		return v1.ResultSuccess, nil
+	}
+
+	err = r.reconcileConsoleLinks(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = r.reconcileConsoleDashboard(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	if !cr.ConsolePluginEnabled() {
+		err = r.deleteConsolePlugin(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		return v1.ResultSuccess, nil
+	}
+	return r.reconcileConsolePlugin(ctx, cr)
+}
+
+// Links to the UIs of the managed components. A component is only linked once it has a host
+func (r *Reconciler) reconcileConsoleLinks(ctx context.Context, cr *v1.Observability) error {
+	if !cr.ConsoleLinksEnabled() {
+		return r.deleteConsoleLinks(ctx, cr, nil)
+	}
+
+	accessType, err := utils.GetUIAccessType(ctx, r.client, cr)
+	if err != nil {
+		return err
+	}
+
+	components := []struct {
+		name    string
+		text    string
+		managed bool
+		route   *routev1.Route
+		host    string
+	}{
+		{"grafana", "Grafana", cr.GrafanaMode() == v1.ComponentManaged, model.GetGrafanaRoute(cr), model.GetGrafanaHost(cr)},
+		{"prometheus", "Prometheus", cr.PrometheusMode() == v1.ComponentManaged, model.GetPrometheusRoute(cr), model.GetPrometheusHost(cr)},
+		{"alertmanager", "Alertmanager", cr.AlertmanagerMode() == v1.ComponentManaged, model.GetAlertmanagerRoute(cr), model.GetAlertmanagerHost(cr)},
+	}
+
+	keep := map[string]bool{}
+	for _, component := range components {
+		if !component.managed {
+			continue
+		}
+
+		host := component.host
+		if accessType != v1.UIAccessIngress {
+			err = r.client.Get(ctx, client.ObjectKey{Namespace: component.route.Namespace, Name: component.route.Name}, component.route)
+			if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return err
+			}
+			host = ""
+			if utils.IsRouteReady(component.route) {
+				host = component.route.Spec.Host
+			}
+		}
+		if host == "" {
+			continue
+		}
+
+		link := model.GetConsoleLink(cr, component.name)
+		keep[link.Name] = true
+		err = utils.Apply(ctx, r.client, link, func() error {
+			link.Spec = consolev1.ConsoleLinkSpec{
+				Link: consolev1.Link{
+					Text: fmt.Sprintf("%s (%s)", component.text, cr.Namespace),
+					Href: fmt.Sprintf("https://%s", host),
+				},
+				Location: consolev1.ApplicationMenu,
+				ApplicationMenu: &consolev1.ApplicationMenuSpec{
+					Section: model.ConsoleLinkSection,
+				},
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.deleteConsoleLinks(ctx, cr, keep)
+}
+
+// Deletes the console links of the stack that are not kept, all of them if keep is nil
+func (r *Reconciler) deleteConsoleLinks(ctx context.Context, cr *v1.Observability, keep map[string]bool) error {
+	list := &consolev1.ConsoleLinkList{}
+	err := r.client.List(ctx, list, &client.ListOptions{
+		LabelSelector: model.GetConsoleLinkSelector(cr),
+	})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	for i := range list.Items {
+		if keep[list.Items[i].Name] {
+			continue
+		}
+		err = r.client.Delete(ctx, &list.Items[i])
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileConsoleDashboard(ctx context.Context, cr *v1.Observability) error {
+	dashboard := model.GetConsoleDashboard(cr)
+	if !cr.ConsoleDashboardEnabled() {
+		err := r.client.Delete(ctx, dashboard)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	json, err := model.GetConsoleDashboardJson(cr)
+	if err != nil {
+		return err
+	}
+	return utils.Apply(ctx, r.client, dashboard, func() error {
+		dashboard.Labels = model.GetConsoleDashboardLabels(cr)
+		dashboard.Data = map[string]string{
+			fmt.Sprintf("%s.json", dashboard.Name): json,
+		}
+		return nil
+	})
+}
+
+func (r *Reconciler) reconcileConsolePlugin(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	service := model.GetConsolePluginService(cr)
+	err := utils.Apply(ctx, r.client, service, func() error {
+		service.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		service.Spec.Selector = model.GetConsolePluginSelectorLabels()
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "https",
+				Port:       model.ConsolePluginPort,
+				TargetPort: intstr.FromInt(model.ConsolePluginPort),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	deployment := model.GetConsolePluginDeployment(cr)
+	err = utils.Apply(ctx, r.client, deployment, func() error {
+		replicas := int32(1)
+		deployment.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: model.GetConsolePluginSelectorLabels(),
+		}
+		deployment.Spec.Template.Labels = model.GetConsolePluginSelectorLabels()
+		deployment.Spec.Template.Spec.Tolerations = cr.Spec.Tolerations
+		deployment.Spec.Template.Spec.Affinity = cr.Spec.Affinity
+		deployment.Spec.Template.Spec.Volumes = []core.Volume{
+			{
+				Name: "serving-cert",
+				VolumeSource: core.VolumeSource{
+					Secret: &core.SecretVolumeSource{
+						SecretName: service.Annotations["service.beta.openshift.io/serving-cert-secret-name"],
+					},
+				},
+			},
+		}
+		deployment.Spec.Template.Spec.Containers = []core.Container{
+			{
+				Name:  "console-plugin",
+				Image: cr.Spec.Console.Plugin.Image,
+				Ports: []core.ContainerPort{
+					{
+						Name:          "https",
+						ContainerPort: model.ConsolePluginPort,
+					},
+				},
+				VolumeMounts: []core.VolumeMount{
+					{
+						Name:      "serving-cert",
+						MountPath: "/var/serving-cert",
+						ReadOnly:  true,
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	plugin := model.GetConsolePlugin(cr)
+	spec := model.GetConsolePluginSpec(cr)
+	err = utils.Apply(ctx, r.client, plugin, func() error {
+		plugin.SetLabels(map[string]string{
+			"managed-by": "observability-operator",
+		})
+		return unstructured.SetNestedMap(plugin.Object, spec, "spec")
+	})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return v1.ResultFailed, fmt.Errorf("the console of this cluster doesn't support dynamic plugins")
+		}
+		return v1.ResultFailed, err
+	}
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) deleteConsolePlugin(ctx context.Context, cr *v1.Observability) error {
+	objects := []runtime.Object{
+		model.GetConsolePlugin(cr),
+		model.GetConsolePluginDeployment(cr),
+		model.GetConsolePluginService(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
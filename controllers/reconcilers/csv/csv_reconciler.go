@@ -7,29 +7,59 @@ import (
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
 )
 
 type Reconciler struct {
-	client client.Client
-	logger logr.Logger
+	client   client.Client
+	logger   logr.Logger
+	recorder record.EventRecorder
 }
 
-func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+func NewReconciler(client client.Client, logger logr.Logger, recorder record.EventRecorder) reconcilers.ObservabilityReconciler {
 	return &Reconciler{
-		client: client,
-		logger: logger,
+		client:   client,
+		logger:   logger,
+		recorder: recorder,
 	}
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// Subscriptions and CSVs only exist with OLM
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if !capabilities.OLM {
+		return v1.ResultSuccess, nil
+	}
+
+	// Watch for stuck upgrades of the operator subscriptions
+	err = CheckSubscription(ctx, r.client, r.logger, r.recorder, cr, s, model.GetPrometheusSubscription(cr).Name, model.CheckPrometheusOperatorUpgrade)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = CheckSubscription(ctx, r.client, r.logger, r.recorder, cr, s, model.GetGrafanaSubscription(cr).Name, nil)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Remove what previous versions of the operators left behind
+	err = PruneOLMResources(ctx, r.client, r.logger, r.recorder, cr, s)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
 	list := &v1alpha1.ClusterServiceVersionList{}
 	opts := &client.ListOptions{
 		Namespace: cr.Namespace,
 	}
-	err := r.client.List(ctx, list, opts)
+	err = r.client.List(ctx, list, opts)
 	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
@@ -72,11 +102,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 }
 
 func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if !capabilities.OLM {
+		return v1.ResultSuccess, nil
+	}
+
 	list := &v1alpha1.ClusterServiceVersionList{}
 	opts := &client.ListOptions{
 		Namespace: cr.Namespace,
 	}
-	err := r.client.List(ctx, list, opts)
+	err = r.client.List(ctx, list, opts)
 	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
@@ -0,0 +1,222 @@
+package csv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const maxPrunedResources = 20
+
+// Label OLM sets on the CSVs it copies into the namespaces of an operator group
+const copiedFromLabel = "olm.copiedFrom"
+
+// An operator installed by the operator through OLM
+type managedOperator struct {
+	subscription string
+	csvPrefix    string
+	catalog      string
+	catalogImage string
+}
+
+func getManagedOperators(cr *v1.Observability) []managedOperator {
+	return []managedOperator{
+		{
+			subscription: model.GetPrometheusSubscription(cr).Name,
+			csvPrefix:    "prometheusoperator.",
+			catalog:      model.GetPrometheusCatalogSource(cr).Name,
+			catalogImage: model.GetImage(cr, v1.ImagePrometheusCatalogIndex, model.PrometheusCatalogIndexImage),
+		},
+		{
+			subscription: model.GetGrafanaSubscription(cr).Name,
+			csvPrefix:    "grafana-operator.",
+			catalog:      model.GetGrafanaCatalogSource(cr).Name,
+			catalogImage: model.GetImage(cr, v1.ImageGrafanaCatalogIndex, model.GrafanaCatalogIndexImage),
+		},
+	}
+}
+
+// Repository of an image reference, without tag or digest
+func getImageRepository(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	i := strings.LastIndex(image, ":")
+	if i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
+
+// PruneOLMResources deletes the OLM resources of previous versions of the managed operators that
+// nothing refers to anymore: CSVs that are neither installed or current in a subscription nor the
+// rollback target recorded in the status, failed install plans that are not the current install
+// plan of a subscription, and catalog sources of a previous catalog image that no subscription
+// uses. CSVs are only pruned once the subscription installed successfully. Resources of other
+// operators in the namespace are never touched
+func PruneOLMResources(ctx context.Context, c client.Client, logger logr.Logger, recorder record.EventRecorder, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	subscriptions := &v1alpha1.SubscriptionList{}
+	opts := &client.ListOptions{
+		Namespace: cr.Namespace,
+	}
+	err := c.List(ctx, subscriptions, opts)
+	if err != nil {
+		return err
+	}
+
+	referencedCSVs := map[string]bool{}
+	referencedPlans := map[string]bool{}
+	referencedCatalogs := map[string]bool{}
+	for _, subscription := range subscriptions.Items {
+		referencedCSVs[subscription.Status.InstalledCSV] = true
+		referencedCSVs[subscription.Status.CurrentCSV] = true
+		if subscription.Status.InstallPlanRef != nil {
+			referencedPlans[subscription.Status.InstallPlanRef.Name] = true
+		}
+		if subscription.Spec != nil && subscription.Spec.CatalogSourceNamespace == cr.Namespace {
+			referencedCatalogs[subscription.Spec.CatalogSource] = true
+		}
+	}
+	for _, status := range s.Subscriptions {
+		referencedCSVs[status.InstalledCSV] = true
+	}
+
+	var pruned []v1.PrunedResource
+	prune := func(obj runtime.Object, kind string, name string, reason string) error {
+		err := c.Delete(ctx, obj)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("pruned OLM resource", "kind", kind, "name", name, "reason", reason)
+		recorder.Eventf(cr, v12.EventTypeNormal, v1.EventOLMResourcePruned, "deleted %v %v: %v", kind, name, reason)
+		pruned = append(pruned, v1.PrunedResource{
+			Kind:   kind,
+			Name:   name,
+			Reason: reason,
+			Time:   time.Now().Unix(),
+		})
+		return nil
+	}
+
+	operators := getManagedOperators(cr)
+
+	csvs := &v1alpha1.ClusterServiceVersionList{}
+	err = c.List(ctx, csvs, opts)
+	if err != nil {
+		return err
+	}
+	for i := range csvs.Items {
+		csv := &csvs.Items[i]
+		if referencedCSVs[csv.Name] || csv.Labels[copiedFromLabel] != "" || csv.DeletionTimestamp != nil {
+			continue
+		}
+		// OLM removes the CSVs it replaces itself
+		if csv.Status.Phase == v1alpha1.CSVPhaseReplacing || csv.Status.Phase == v1alpha1.CSVPhaseDeleting {
+			continue
+		}
+		for _, operator := range operators {
+			if !strings.HasPrefix(csv.Name, operator.csvPrefix) {
+				continue
+			}
+			installed, err := getSucceededCSV(ctx, c, subscriptions.Items, operator.subscription)
+			if err != nil {
+				return err
+			}
+			if installed == "" {
+				break
+			}
+			err = prune(csv, "ClusterServiceVersion", csv.Name, fmt.Sprintf("replaced by %v of subscription %v", installed, operator.subscription))
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	plans := &v1alpha1.InstallPlanList{}
+	err = c.List(ctx, plans, opts)
+	if err != nil {
+		return err
+	}
+	for i := range plans.Items {
+		plan := &plans.Items[i]
+		if referencedPlans[plan.Name] || plan.Status.Phase != v1alpha1.InstallPlanPhaseFailed || !isManagedInstallPlan(plan, operators) {
+			continue
+		}
+		err = prune(plan, "InstallPlan", plan.Name, fmt.Sprintf("failed install of %v is no longer the current install plan", strings.Join(plan.Spec.ClusterServiceVersionNames, ", ")))
+		if err != nil {
+			return err
+		}
+	}
+
+	catalogs := &v1alpha1.CatalogSourceList{}
+	err = c.List(ctx, catalogs, opts)
+	if err != nil {
+		return err
+	}
+	for i := range catalogs.Items {
+		catalog := &catalogs.Items[i]
+		if referencedCatalogs[catalog.Name] || catalog.Spec.SourceType != v1alpha1.SourceTypeGrpc || catalog.Spec.Image == "" {
+			continue
+		}
+		for _, operator := range operators {
+			if catalog.Name == operator.catalog || getImageRepository(catalog.Spec.Image) != getImageRepository(operator.catalogImage) {
+				continue
+			}
+			err = prune(catalog, "CatalogSource", catalog.Name, fmt.Sprintf("catalog %v of a previous version is not used by any subscription", catalog.Spec.Image))
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	if len(pruned) > 0 {
+		s.PrunedOLMResources = append(pruned, s.PrunedOLMResources...)
+		if len(s.PrunedOLMResources) > maxPrunedResources {
+			s.PrunedOLMResources = s.PrunedOLMResources[:maxPrunedResources]
+		}
+	}
+	return nil
+}
+
+// Name of the installed CSV of a subscription, if it installed successfully
+func getSucceededCSV(ctx context.Context, c client.Client, subscriptions []v1alpha1.Subscription, name string) (string, error) {
+	for _, subscription := range subscriptions {
+		if subscription.Name != name {
+			continue
+		}
+		csv, err := getCSV(ctx, c, subscription.Namespace, subscription.Status.InstalledCSV)
+		if err != nil || csv == nil || csv.Status.Phase != v1alpha1.CSVPhaseSucceeded {
+			return "", err
+		}
+		return csv.Name, nil
+	}
+	return "", nil
+}
+
+// Install plans of the managed operators only install their CSVs
+func isManagedInstallPlan(plan *v1alpha1.InstallPlan, operators []managedOperator) bool {
+	if len(plan.Spec.ClusterServiceVersionNames) == 0 {
+		return false
+	}
+seek:
+	for _, name := range plan.Spec.ClusterServiceVersionNames {
+		for _, operator := range operators {
+			if strings.HasPrefix(name, operator.csvPrefix) {
+				continue seek
+			}
+		}
+		return false
+	}
+	return true
+}
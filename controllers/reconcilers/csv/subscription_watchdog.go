@@ -0,0 +1,235 @@
+package csv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpgradeCheck returns an error if an install plan must not be approved because the upgrade
+// from the installed to the target CSV is not supported
+type UpgradeCheck func(cr *v1.Observability, installedCSV string, targetCSV string) error
+
+// Check the health of an OLM subscription and resolve stuck installs according to the install
+// plan approval policy of the CR. The outcome is recorded in the subscription status of the CR.
+func CheckSubscription(ctx context.Context, c client.Client, logger logr.Logger, recorder record.EventRecorder, cr *v1.Observability, s *v1.ObservabilityStatus, name string, check UpgradeCheck) error {
+	// Install plans that are no longer deferred are removed from the status on every return
+	deferredChange := ""
+	defer func() {
+		s.SetDeferredRollout(name, deferredChange)
+	}()
+
+	subscription := &v1alpha1.Subscription{}
+	selector := client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      name,
+	}
+
+	err := c.Get(ctx, selector, subscription)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	status := s.GetSubscriptionStatus(name)
+	if status == nil {
+		s.Subscriptions = append(s.Subscriptions, v1.SubscriptionStatus{Name: name})
+		status = &s.Subscriptions[len(s.Subscriptions)-1]
+	}
+
+	// Remember the last successfully installed CSV, it is the target of a rollback
+	installedCSV, err := getCSV(ctx, c, cr.Namespace, subscription.Status.InstalledCSV)
+	if err != nil {
+		return err
+	}
+	if installedCSV != nil && installedCSV.Status.Phase == v1alpha1.CSVPhaseSucceeded && installedCSV.Name != status.InstalledCSV {
+		if status.InstalledCSV == "" {
+			recorder.Eventf(cr, v12.EventTypeNormal, v1.EventComponentInstalled, "installed %v", installedCSV.Name)
+		} else {
+			recorder.Eventf(cr, v12.EventTypeNormal, v1.EventComponentUpgraded, "upgraded %v to %v", status.InstalledCSV, installedCSV.Name)
+		}
+		status.InstalledCSV = installedCSV.Name
+		// The rollback is over once another CSV installed, the subscription no longer starts at the old one
+		status.FailedCSV = ""
+	}
+
+	currentCSV, err := getCSV(ctx, c, cr.Namespace, subscription.Status.CurrentCSV)
+	if err != nil {
+		return err
+	}
+
+	installPlan, err := getInstallPlan(ctx, c, subscription)
+	if err != nil {
+		return err
+	}
+
+	if installPlan == nil {
+		if subscription.Status.State == v1alpha1.SubscriptionStateAtLatest {
+			setSubscriptionState(status, v1.SubscriptionHealthy, "")
+		} else {
+			setSubscriptionState(status, v1.SubscriptionInstalling, fmt.Sprintf("waiting for install plan, subscription state: %v", subscription.Status.State))
+		}
+		return nil
+	}
+
+	failed := installPlan.Status.Phase == v1alpha1.InstallPlanPhaseFailed ||
+		(currentCSV != nil && currentCSV.Status.Phase == v1alpha1.CSVPhaseFailed)
+
+	switch {
+	case failed:
+		if cr.GetInstallPlanApproval() == v1.InstallPlanApprovalRollback &&
+			status.InstalledCSV != "" && status.InstalledCSV != subscription.Status.CurrentCSV {
+			err = rollbackSubscription(ctx, c, logger, subscription, installPlan, status)
+			if err != nil {
+				return err
+			}
+			recorder.Event(cr, v12.EventTypeWarning, v1.EventUpgradeRolledBack, status.Message)
+			return nil
+		}
+		if status.State != v1.SubscriptionFailed {
+			recorder.Eventf(cr, v12.EventTypeWarning, v1.EventUpgradeFailed, "install of %v failed", subscription.Status.CurrentCSV)
+		}
+		setSubscriptionState(status, v1.SubscriptionFailed, fmt.Sprintf("install of %v failed", subscription.Status.CurrentCSV))
+	case installPlan.Status.Phase == v1alpha1.InstallPlanPhaseRequiresApproval && !installPlan.Spec.Approved:
+		if check != nil {
+			for _, target := range installPlan.Spec.ClusterServiceVersionNames {
+				err = check(cr, status.InstalledCSV, target)
+				if err == nil {
+					continue
+				}
+				if status.State != v1.SubscriptionBlocked {
+					recorder.Eventf(cr, v12.EventTypeWarning, v1.EventUpgradeBlocked, "install plan %v blocked: %v", installPlan.Name, err)
+				}
+				setSubscriptionState(status, v1.SubscriptionBlocked, err.Error())
+				return nil
+			}
+		}
+
+		if cr.GetInstallPlanApproval() == v1.InstallPlanApprovalManual || containsCSV(installPlan, status.FailedCSV) {
+			setSubscriptionState(status, v1.SubscriptionApprovalRequired, fmt.Sprintf("install plan %v requires approval", installPlan.Name))
+			return nil
+		}
+
+		// Only upgrades wait for the window, without an installed CSV the stack can't come up
+		if status.InstalledCSV != "" && !model.RolloutAllowed(cr, time.Now()) {
+			deferredChange = fmt.Sprintf("install plan %v", installPlan.Name)
+			setSubscriptionState(status, v1.SubscriptionDeferred, fmt.Sprintf("install plan %v deferred to the next upgrade window", installPlan.Name))
+			return nil
+		}
+
+		installPlan.Spec.Approved = true
+		err = c.Update(ctx, installPlan)
+		if err != nil {
+			return err
+		}
+		logger.Info("approved install plan", "subscription", name, "installplan", installPlan.Name)
+		setSubscriptionState(status, v1.SubscriptionInstalling, fmt.Sprintf("approved install plan %v", installPlan.Name))
+	case installPlan.Status.Phase == v1alpha1.InstallPlanPhaseComplete &&
+		currentCSV != nil && currentCSV.Status.Phase == v1alpha1.CSVPhaseSucceeded:
+		setSubscriptionState(status, v1.SubscriptionHealthy, "")
+	default:
+		setSubscriptionState(status, v1.SubscriptionInstalling, fmt.Sprintf("install plan %v in phase %v", installPlan.Name, installPlan.Status.Phase))
+	}
+
+	return nil
+}
+
+// OLM has no native rollback. Remove the failed CSV together with its install plan and the subscription.
+// The subscription is recreated by the installation stage, starting at the last healthy CSV.
+func rollbackSubscription(ctx context.Context, c client.Client, logger logr.Logger, subscription *v1alpha1.Subscription, installPlan *v1alpha1.InstallPlan, status *v1.SubscriptionStatus) error {
+	failedCSV := subscription.Status.CurrentCSV
+	logger.Info("rolling back failed subscription upgrade", "subscription", subscription.Name,
+		"from", failedCSV, "to", status.InstalledCSV)
+
+	csv := &v1alpha1.ClusterServiceVersion{}
+	csv.Name = failedCSV
+	csv.Namespace = subscription.Namespace
+	err := c.Delete(ctx, csv)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	err = c.Delete(ctx, installPlan)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	err = c.Delete(ctx, subscription)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	status.FailedCSV = failedCSV
+	setSubscriptionState(status, v1.SubscriptionRolledBack, fmt.Sprintf("rolled back from %v to %v", failedCSV, status.InstalledCSV))
+	return nil
+}
+
+func getCSV(ctx context.Context, c client.Client, namespace string, name string) (*v1alpha1.ClusterServiceVersion, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	csv := &v1alpha1.ClusterServiceVersion{}
+	selector := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+
+	err := c.Get(ctx, selector, csv)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return csv, nil
+}
+
+func getInstallPlan(ctx context.Context, c client.Client, subscription *v1alpha1.Subscription) (*v1alpha1.InstallPlan, error) {
+	if subscription.Status.InstallPlanRef == nil {
+		return nil, nil
+	}
+
+	installPlan := &v1alpha1.InstallPlan{}
+	selector := client.ObjectKey{
+		Namespace: subscription.Status.InstallPlanRef.Namespace,
+		Name:      subscription.Status.InstallPlanRef.Name,
+	}
+
+	err := c.Get(ctx, selector, installPlan)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return installPlan, nil
+}
+
+func containsCSV(installPlan *v1alpha1.InstallPlan, name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, csv := range installPlan.Spec.ClusterServiceVersionNames {
+		if csv == name {
+			return true
+		}
+	}
+	return false
+}
+
+func setSubscriptionState(status *v1.SubscriptionStatus, state v1.SubscriptionState, message string) {
+	status.State = state
+	status.Message = message
+}
@@ -0,0 +1,140 @@
+package datasource_health
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Provisioning the datasources again restarts Grafana, a datasource that stays broken is repaired
+// at most once per backoff
+const RepairBackoff = time.Hour
+
+type Reconciler struct {
+	client     client.Client
+	logger     logr.Logger
+	httpClient *http.Client
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	httpClient := &http.Client{Transport: tr, Timeout: 30 * time.Second}
+
+	return &Reconciler{
+		client:     client,
+		logger:     logger,
+		httpClient: httpClient,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	return v1.ResultSuccess, nil
+}
+
+// A broken datasource of the managed Grafana, e.g. with a stale service account token, is repaired
+// by increasing the version of the provisioned datasources. The Grafana configuration stage renders
+// them again with the current token and url on the next reconcile. The datasource of an external
+// Grafana is applied on every reconcile, its health is only reported
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// The repairs are kept, resetting the version would provision the datasources again
+	if !cr.DatasourceHealthCheckEnabled() {
+		meta.RemoveStatusCondition(&s.Conditions, v1.DatasourcesHealthy)
+		return v1.ResultSuccess, nil
+	}
+
+	health := v1.GrafanaDatasourceHealthStatus{}
+	if s.DatasourceHealth != nil {
+		health = *s.DatasourceHealth
+	}
+	if health.LastCheck != 0 && time.Since(time.Unix(health.LastCheck, 0)) < model.GetDatasourceHealthInterval(cr) {
+		return v1.ResultSuccess, nil
+	}
+
+	now := time.Now()
+	health.LastCheck = now.Unix()
+	defer func() {
+		s.DatasourceHealth = &health
+	}()
+
+	grafana, err := configuration.NewGrafanaClient(ctx, r.client, r.httpClient, cr)
+	if err == nil {
+		health.Unhealthy, err = r.checkDatasources(cr, grafana)
+	}
+	// Grafana itself is not a datasource problem, e.g. while it is still starting
+	if err != nil {
+		health.Unhealthy = nil
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.DatasourcesHealthy,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "GrafanaUnavailable",
+			Message: fmt.Sprintf("error listing the datasources: %v", err),
+		})
+		return v1.ResultSuccess, nil
+	}
+
+	if len(health.Unhealthy) == 0 {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.DatasourcesHealthy,
+			Status:  metav1.ConditionTrue,
+			Reason:  "DatasourcesHealthy",
+			Message: "all datasources of the operator answered",
+		})
+		return v1.ResultSuccess, nil
+	}
+
+	message := strings.Join(health.Unhealthy, ", ")
+	if !cr.GrafanaExternal() && (health.LastRepair == 0 || now.Sub(time.Unix(health.LastRepair, 0)) >= RepairBackoff) {
+		health.Repairs++
+		health.LastRepair = now.Unix()
+		message = fmt.Sprintf("%v, provisioning the datasources again", message)
+	}
+	r.logger.Info("datasources unhealthy", "datasources", health.Unhealthy, "repairs", health.Repairs)
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:    v1.DatasourcesHealthy,
+		Status:  metav1.ConditionFalse,
+		Reason:  "DatasourcesUnhealthy",
+		Message: message,
+	})
+	return v1.ResultSuccess, nil
+}
+
+// Returns the datasources of the operator that are missing or don't answer
+func (r *Reconciler) checkDatasources(cr *v1.Observability, grafana *configuration.GrafanaClient) ([]string, error) {
+	datasources, err := grafana.ListDatasources()
+	if err != nil {
+		return nil, err
+	}
+
+	var unhealthy []string
+	for _, name := range model.GetGrafanaDatasourceNames(cr) {
+		found := false
+		for _, datasource := range datasources {
+			if datasource.Name != name {
+				continue
+			}
+			found = true
+			_, err = grafana.CheckDatasource(datasource)
+			if err != nil {
+				unhealthy = append(unhealthy, fmt.Sprintf("%v: %v", name, err))
+			}
+		}
+		if !found {
+			unhealthy = append(unhealthy, fmt.Sprintf("%v: not provisioned", name))
+		}
+	}
+	return unhealthy, nil
+}
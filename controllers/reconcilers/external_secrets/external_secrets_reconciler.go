@@ -0,0 +1,182 @@
+package external_secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// The secrets are owned by the external secrets and deleted by the garbage collector
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	return r.deleteExternalSecrets(ctx, cr, nil)
+}
+
+// The External Secrets Operator materializes the secrets and refreshes them. Refreshed secrets are
+// picked up by the watch of the referenced secrets like any other credential rotation
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	if len(cr.Spec.ExternalSecrets) == 0 {
+		meta.RemoveStatusCondition(&s.Conditions, v1.ExternalSecretsReady)
+		if s.Capabilities == nil || !s.Capabilities.ExternalSecretsOperator {
+			return v1.ResultSuccess, nil
+		}
+		return r.Cleanup(ctx, cr)
+	}
+
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Stages referencing the secrets fail until they exist, the condition tells why
+	if !capabilities.ExternalSecretsOperator {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.ExternalSecretsReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  "ExternalSecretsOperatorNotInstalled",
+			Message: "spec.externalSecrets requires the External Secrets Operator",
+		})
+		return v1.ResultSuccess, nil
+	}
+
+	keep := map[string]bool{}
+	for _, secret := range cr.Spec.ExternalSecrets {
+		keep[secret.Name] = true
+
+		externalSecret := model.GetExternalSecret(cr, secret.Name)
+		spec := model.GetExternalSecretSpec(secret)
+		err = utils.Apply(ctx, r.client, externalSecret, func() error {
+			externalSecret.SetLabels(model.GetExternalSecretLabels())
+			return unstructured.SetNestedMap(externalSecret.Object, spec, "spec")
+		})
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+	}
+
+	status, err := r.deleteExternalSecrets(ctx, cr, keep)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	return r.waitForSecrets(ctx, cr, s)
+}
+
+// Deletes the external secrets of the operator that are not kept, all of them if keep is nil
+func (r *Reconciler) deleteExternalSecrets(ctx context.Context, cr *v1.Observability, keep map[string]bool) (v1.ObservabilityStageStatus, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(model.ExternalSecretGroupVersionKind.GroupVersion().WithKind("ExternalSecretList"))
+	err := r.client.List(ctx, list, &client.ListOptions{
+		LabelSelector: model.GetExternalSecretSelector(),
+		Namespace:     cr.Namespace,
+	})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return v1.ResultSuccess, nil
+		}
+		return v1.ResultFailed, err
+	}
+
+	for i := range list.Items {
+		if keep[list.Items[i].GetName()] {
+			continue
+		}
+		err = r.client.Delete(ctx, &list.Items[i])
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+	return v1.ResultSuccess, nil
+}
+
+// The stages after this one require the secrets, so they wait until every secret was materialized
+// once. Failed refreshes leave the last data in place and are only reported
+func (r *Reconciler) waitForSecrets(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	var failed []string
+	for _, spec := range cr.Spec.ExternalSecrets {
+		externalSecret := model.GetExternalSecret(cr, spec.Name)
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: spec.Name}, externalSecret)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		ready, message := getReadyCondition(externalSecret)
+
+		secret := &core.Secret{}
+		err = r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: spec.Name}, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+		if errors.IsNotFound(err) {
+			reason := "waiting for the first sync"
+			if message != "" {
+				reason = message
+			}
+			meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+				Type:    v1.ExternalSecretsReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  "SecretsPending",
+				Message: fmt.Sprintf("secret %v is not materialized: %v", spec.Name, reason),
+			})
+			return v1.ResultInProgress, nil
+		}
+
+		if !ready {
+			failed = append(failed, fmt.Sprintf("%v: %v", spec.Name, message))
+		}
+	}
+
+	if len(failed) > 0 {
+		r.logger.Info("refresh of external secrets failed", "secrets", failed)
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.ExternalSecretsReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  "RefreshFailed",
+			Message: strings.Join(failed, ", "),
+		})
+		return v1.ResultSuccess, nil
+	}
+
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:   v1.ExternalSecretsReady,
+		Status: metav1.ConditionTrue,
+		Reason: "SecretsSynced",
+	})
+	return v1.ResultSuccess, nil
+}
+
+// Returns the Ready condition the External Secrets Operator sets after every sync
+func getReadyCondition(externalSecret *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(externalSecret.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		return condition["status"] == string(metav1.ConditionTrue), message
+	}
+	return false, "not synced yet"
+}
@@ -17,11 +17,11 @@ import (
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
 	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	v14 "k8s.io/api/apps/v1"
+	v13 "k8s.io/api/core/v1"
 	v12 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type SourceType int
@@ -46,6 +46,11 @@ func NewReconciler(client client.Client, logger logr.Logger) reconcilers.Observa
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// Nothing is installed for an external Grafana, only the datasource is created
+	if cr.GrafanaExternal() {
+		return r.reconcileExternalDatasource(ctx, cr)
+	}
+
 	status, err := r.reconileProxySecret(ctx, cr)
 	if status != v1.ResultSuccess {
 		return status, err
@@ -61,6 +66,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return status, err
 	}
 
+	status, err = r.reconcileMonitoringViewBinding(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
 	status, err = r.reconcileGrafanaDatasource(ctx, cr)
 	if status != v1.ResultSuccess {
 		return status, err
@@ -70,6 +80,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 }
 
 func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	if cr.GrafanaExternal() {
+		return r.deleteExternalDatasource(ctx, cr)
+	}
+
 	// Grafana CR
 	grafana := model.GetGrafanaCr(cr)
 	err := r.client.Delete(ctx, grafana)
@@ -108,36 +122,46 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 		return v1.ResultFailed, err
 	}
 
+	monitoringViewBinding := model.GetGrafanaMonitoringViewBinding(cr)
+	err = r.client.Delete(ctx, monitoringViewBinding)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
 	return v1.ResultSuccess, nil
 }
 
 func (r *Reconciler) waitForGrafanaToBeRemoved(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
-	list := &v14.DeploymentList{}
-	opts := &client.ListOptions{
-		Namespace: cr.Namespace,
+	// Created by the Grafana operator
+	deployment := &v14.Deployment{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: "grafana-deployment"}, deployment)
+	if errors.IsNotFound(err) {
+		return v1.ResultSuccess, nil
 	}
-	err := r.client.List(ctx, list, opts)
-	if err != nil && !errors.IsNotFound(err) {
+	if err != nil {
 		return v1.ResultFailed, err
 	}
 
-	for _, ss := range list.Items {
-		if ss.Name == "grafana-deployment" {
-			return v1.ResultInProgress, nil
-		}
-	}
-
-	return v1.ResultSuccess, nil
+	return v1.ResultInProgress, nil
 }
 
 func (r *Reconciler) reconileProxySecret(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	secret := model.GetGrafanaProxySecret(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
-		if secret.Data == nil {
-			secret.StringData = map[string]string{
-				"session_secret": utils.GenerateRandomString(32),
-			}
+	sessionSecret, err := utils.GetOrGenerateSecretValue(ctx, r.client, secret, "session_secret", 32)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	cookieSecret, err := utils.GetOrGenerateSecretValue(ctx, r.client, secret, model.OIDCProxyCookieSecret, 32)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Data = map[string][]byte{
+			"session_secret":            sessionSecret,
+			model.OIDCProxyCookieSecret: cookieSecret,
 		}
 		return nil
 	})
@@ -152,7 +176,7 @@ func (r *Reconciler) reconileProxySecret(ctx context.Context, cr *v1.Observabili
 func (r *Reconciler) reconcileClusterRole(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	clusterRole := model.GetGrafanaClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, clusterRole, func() error {
+	err := utils.Apply(ctx, r.client, clusterRole, func() error {
 		clusterRole.Rules = []v12.PolicyRule{
 			{
 				Verbs:     []string{"create"},
@@ -179,7 +203,7 @@ func (r *Reconciler) reconcileClusterRoleBinding(ctx context.Context, cr *v1.Obs
 	clusterRoleBinding := model.GetGrafanaClusterRoleBinding(cr)
 	clusterRole := model.GetGrafanaClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, clusterRoleBinding, func() error {
+	err := utils.Apply(ctx, r.client, clusterRoleBinding, func() error {
 		clusterRoleBinding.RoleRef = v12.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     bundle.ClusterRoleKind,
@@ -202,25 +226,119 @@ func (r *Reconciler) reconcileClusterRoleBinding(ctx context.Context, cr *v1.Obs
 	return v1.ResultSuccess, nil
 }
 
+// Grafana queries Thanos Querier of the user workload monitoring with its service account token
+func (r *Reconciler) reconcileMonitoringViewBinding(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	binding := model.GetGrafanaMonitoringViewBinding(cr)
+
+	if !cr.UserWorkloadMonitoringEnabled() {
+		err := r.client.Delete(ctx, binding)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+		return v1.ResultSuccess, nil
+	}
+
+	err := utils.Apply(ctx, r.client, binding, func() error {
+		binding.RoleRef = v12.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     bundle.ClusterRoleKind,
+			Name:     "cluster-monitoring-view",
+		}
+		binding.Subjects = []v12.Subject{
+			{
+				Kind:      v12.ServiceAccountKind,
+				Name:      "grafana-serviceaccount", // Created by the Grafana Operator
+				Namespace: cr.Namespace,
+			},
+		}
+		return nil
+	})
+
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// Returns the token of the Grafana service account, empty if the token secret was not created yet
+func (r *Reconciler) getGrafanaServiceAccountToken(ctx context.Context, cr *v1.Observability) (string, error) {
+	sa := &v13.ServiceAccount{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: "grafana-serviceaccount"}, sa)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	for _, ref := range sa.Secrets {
+		secret := &v13.Secret{}
+		err = r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: ref.Name}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		if secret.Type == v13.SecretTypeServiceAccountToken {
+			return string(secret.Data[v13.ServiceAccountTokenKey]), nil
+		}
+	}
+
+	return "", nil
+}
+
 func (r *Reconciler) reconcileGrafanaDatasource(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	datasource := model.GetGrafanaDatasource(cr)
-	url := fmt.Sprintf("http://prometheus-operated.%s:9090", cr.Namespace)
+	url := model.GetGrafanaDatasourceUrl(cr)
+
+	jsonData := v1alpha1.GrafanaDataSourceJsonData{
+		TlsSkipVerify: true,
+		TimeInterval:  "10s",
+	}
+	secureJsonData := v1alpha1.GrafanaDataSourceSecureJsonData{}
+
+	if cr.UserWorkloadMonitoringEnabled() {
+		token, err := r.getGrafanaServiceAccountToken(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		if token == "" {
+			return v1.ResultInProgress, nil
+		}
+		jsonData.HTTPHeaderName1 = "Authorization"
+		secureJsonData.HTTPHeaderValue1 = fmt.Sprintf("Bearer %s", token)
+	}
+
+	// The certificates are inlined, renewed certificates are picked up on the next reconcile
+	if model.UsesInternalTLSDatasource(cr) {
+		secret := model.GetInternalTLSSecret(cr, model.InternalTLSGrafana)
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: secret.Namespace, Name: secret.Name}, secret)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		jsonData.TlsSkipVerify = false
+		jsonData.TlsAuth = true
+		jsonData.TlsAuthWithCACert = true
+		secureJsonData.TlsCaCert = string(secret.Data["ca.crt"])
+		secureJsonData.TlsClientCert = string(secret.Data[v13.TLSCertKey])
+		secureJsonData.TlsClientKey = string(secret.Data[v13.TLSPrivateKeyKey])
+	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, datasource, func() error {
+	err := utils.Apply(ctx, r.client, datasource, func() error {
 		datasource.Spec.Name = "kafka-prometheus.yaml"
 		datasource.Spec.Datasources = []v1alpha1.GrafanaDataSourceFields{
 			{
-				Name:      "Prometheus",
-				Type:      "prometheus",
-				Access:    "proxy",
-				Url:       url,
-				IsDefault: true,
-				Version:   1,
-				Editable:  true,
-				JsonData: v1alpha1.GrafanaDataSourceJsonData{
-					TlsSkipVerify: true,
-					TimeInterval:  "10s",
-				},
+				Name:           "Prometheus",
+				Type:           "prometheus",
+				Access:         "proxy",
+				Url:            url,
+				IsDefault:      true,
+				Version:        model.GetGrafanaDatasourceVersion(cr),
+				Editable:       true,
+				JsonData:       jsonData,
+				SecureJsonData: secureJsonData,
 			},
 		}
 		return nil
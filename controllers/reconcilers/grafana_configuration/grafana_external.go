@@ -0,0 +1,123 @@
+package grafana_configuration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	v13 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var externalGrafanaClient = &http.Client{Timeout: 10 * time.Second}
+
+type externalDatasource struct {
+	Id        int64             `json:"id,omitempty"`
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	Access    string            `json:"access"`
+	Url       string            `json:"url"`
+	IsDefault bool              `json:"isDefault"`
+	JsonData  map[string]string `json:"jsonData"`
+}
+
+// Send a request to the external Grafana, authenticated with the API token. Returns the status code
+func (r *Reconciler) doExternal(ctx context.Context, cr *v1.Observability, method string, path string, body interface{}, result interface{}) (int, error) {
+	external := model.GetGrafanaExternal(cr)
+
+	secret := &v13.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: external.TokenSecret}, secret)
+	if err != nil {
+		return 0, err
+	}
+
+	var payload []byte
+	if body != nil {
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(external.URL, "/")+path, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", secret.Data[model.GrafanaExternalTokenKey]))
+	req.Header.Set("Content-Type", "application/json")
+	if external.OrgID != 0 {
+		req.Header.Set("X-Grafana-Org-Id", fmt.Sprintf("%d", external.OrgID))
+	}
+
+	resp, err := externalGrafanaClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK && result != nil {
+		return resp.StatusCode, json.NewDecoder(resp.Body).Decode(result)
+	}
+	return resp.StatusCode, nil
+}
+
+// Create or update the Prometheus datasource in the external Grafana
+func (r *Reconciler) reconcileExternalDatasource(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	datasource := &externalDatasource{
+		Name:   model.GetGrafanaDatasourceName(cr),
+		Type:   "prometheus",
+		Access: "proxy",
+		Url:    model.GetGrafanaDatasourceUrl(cr),
+		JsonData: map[string]string{
+			"timeInterval": "10s",
+		},
+	}
+
+	existing := &externalDatasource{}
+	path := fmt.Sprintf("/api/datasources/name/%s", url.PathEscape(datasource.Name))
+	code, err := r.doExternal(ctx, cr, http.MethodGet, path, nil, existing)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	switch code {
+	case http.StatusOK:
+		datasource.Id = existing.Id
+		code, err = r.doExternal(ctx, cr, http.MethodPut, fmt.Sprintf("/api/datasources/%d", existing.Id), datasource, nil)
+	case http.StatusNotFound:
+		code, err = r.doExternal(ctx, cr, http.MethodPost, "/api/datasources", datasource, nil)
+	}
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if code != http.StatusOK {
+		return v1.ResultFailed, fmt.Errorf("unexpected response from external grafana for datasource %v: %v", datasource.Name, code)
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) deleteExternalDatasource(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	path := fmt.Sprintf("/api/datasources/name/%s", url.PathEscape(model.GetGrafanaDatasourceName(cr)))
+	code, err := r.doExternal(ctx, cr, http.MethodDelete, path, nil, nil)
+	if errors.IsNotFound(err) {
+		// Without the token secret the datasource can't be removed, don't block the deletion
+		r.logger.Info("token secret of the external grafana not found, datasource not deleted")
+		return v1.ResultSuccess, nil
+	}
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if code != http.StatusOK && code != http.StatusNotFound {
+		return v1.ResultFailed, fmt.Errorf("unexpected response from external grafana deleting the datasource: %v", code)
+	}
+	return v1.ResultSuccess, nil
+}
@@ -5,32 +5,44 @@ import (
 	"github.com/go-logr/logr"
 	coreosv1 "github.com/operator-framework/api/pkg/operators/v1"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	errors2 "github.com/pkg/errors"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/csv"
 	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
-	v12 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"strings"
 )
 
 type Reconciler struct {
-	client client.Client
-	logger logr.Logger
+	client   client.Client
+	logger   logr.Logger
+	recorder record.EventRecorder
 }
 
-func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+func NewReconciler(client client.Client, logger logr.Logger, recorder record.EventRecorder) reconcilers.ObservabilityReconciler {
 	return &Reconciler{
-		client: client,
-		logger: logger,
+		client:   client,
+		logger:   logger,
+		recorder: recorder,
 	}
 }
 
 func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	// Operators that were not installed through OLM are not managed by us
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if !capabilities.OLM {
+		return v1.ResultSuccess, nil
+	}
+
 	source := model.GetGrafanaCatalogSource(cr)
-	err := r.client.Delete(ctx, source)
+	err = r.client.Delete(ctx, source)
 	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
@@ -48,28 +60,27 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 	}
 
 	// We have to remove the grafana operator deployment manually
-	deployments := &v12.DeploymentList{}
-	opts := &client.ListOptions{
-		Namespace: cr.Namespace,
-	}
-	err = r.client.List(ctx, deployments, opts)
-	if err != nil {
+	err = r.client.Delete(ctx, model.GetGrafanaOperatorDeployment(cr))
+	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
 
-	for _, deployment := range deployments.Items {
-		if deployment.Name == "grafana-operator" {
-			err = r.client.Delete(ctx, &deployment)
-			if err != nil && !errors.IsNotFound(err) {
-				return v1.ResultFailed, err
-			}
-		}
-	}
-
 	return v1.ResultSuccess, nil
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// Without OLM the Grafana operator has to be installed beforehand
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if !capabilities.OLM {
+		if capabilities.GrafanaOperator {
+			return v1.ResultSuccess, nil
+		}
+		return v1.ResultFailed, errors2.New("OLM is not available and the grafana operator is not installed")
+	}
+
 	// Remove old subscriptions
 	status, err := r.deleteUnrequestedSubscriptions(ctx, cr)
 	if status != v1.ResultSuccess {
@@ -83,7 +94,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 	}
 
 	// Grafana subscription
-	status, err = r.reconcileSubscription(ctx, cr)
+	status, err = r.reconcileSubscription(ctx, cr, s)
 	if status != v1.ResultSuccess {
 		return status, err
 	}
@@ -95,6 +106,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 	}
 
 	status, err = r.waitForGrafanaOperator(ctx, cr)
+	if status == v1.ResultInProgress {
+		// The operator is not ready yet, check if the install is stuck
+		err = csv.CheckSubscription(ctx, r.client, r.logger, r.recorder, cr, s, model.GetGrafanaSubscription(cr).Name, nil)
+	}
 	if status != v1.ResultSuccess {
 		return status, err
 	}
@@ -155,10 +170,10 @@ func (r *Reconciler) deleteUnrequestedSubscriptions(ctx context.Context, cr *v1.
 func (r *Reconciler) reconcileCatalogSource(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	source := model.GetGrafanaCatalogSource(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, source, func() error {
+	err := utils.Apply(ctx, r.client, source, func() error {
 		source.Spec = v1alpha1.CatalogSourceSpec{
 			SourceType: v1alpha1.SourceTypeGrpc,
-			Image:      "quay.io/rhoas/grafana-operator-index:v3.10.4",
+			Image:      model.GetImage(cr, v1.ImageGrafanaCatalogIndex, model.GrafanaCatalogIndexImage),
 		}
 		return nil
 	})
@@ -170,17 +185,18 @@ func (r *Reconciler) reconcileCatalogSource(ctx context.Context, cr *v1.Observab
 	return v1.ResultSuccess, nil
 }
 
-func (r *Reconciler) reconcileSubscription(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+func (r *Reconciler) reconcileSubscription(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
 	subscription := model.GetGrafanaSubscription(cr)
 	source := model.GetGrafanaCatalogSource(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, subscription, func() error {
+	err := utils.Apply(ctx, r.client, subscription, func() error {
 		subscription.Spec = &v1alpha1.SubscriptionSpec{
 			CatalogSource:          source.Name,
 			CatalogSourceNamespace: source.Namespace,
 			Package:                "grafana-operator",
 			Channel:                "alpha",
-			StartingCSV:            "grafana-operator.v3.10.4",
+			StartingCSV:            model.GetSubscriptionStartingCSV(s, subscription.Name, "grafana-operator.v3.10.4"),
+			InstallPlanApproval:    model.GetSubscriptionInstallPlanApproval(cr),
 			Config:                 v1alpha1.SubscriptionConfig{Resources: model.GetGrafanaOperatorResourceRequirement(cr)},
 		}
 		return nil
@@ -205,7 +221,7 @@ func (r *Reconciler) reconcileOperatorgroup(ctx context.Context, cr *v1.Observab
 
 	operatorgroup := model.GetGrafanaOperatorGroup(cr)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, operatorgroup, func() error {
+	err = utils.Apply(ctx, r.client, operatorgroup, func() error {
 		operatorgroup.Spec = coreosv1.OperatorGroupSpec{
 			TargetNamespaces: []string{cr.Namespace},
 		}
@@ -220,22 +236,17 @@ func (r *Reconciler) reconcileOperatorgroup(ctx context.Context, cr *v1.Observab
 }
 
 func (r *Reconciler) waitForGrafanaOperator(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
-	// We have to remove the prometheus operator deployment manually
-	deployments := &v12.DeploymentList{}
-	opts := &client.ListOptions{
-		Namespace: cr.Namespace,
+	deployment := model.GetGrafanaOperatorDeployment(cr)
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: deployment.Namespace, Name: deployment.Name}, deployment)
+	if errors.IsNotFound(err) {
+		return v1.ResultInProgress, nil
 	}
-	err := r.client.List(ctx, deployments, opts)
 	if err != nil {
 		return v1.ResultFailed, err
 	}
 
-	for _, deployment := range deployments.Items {
-		if strings.HasPrefix(deployment.Name, "grafana-operator") {
-			if deployment.Status.ReadyReplicas > 0 {
-				return v1.ResultSuccess, nil
-			}
-		}
+	if deployment.Status.ReadyReplicas > 0 {
+		return v1.ResultSuccess, nil
 	}
 	return v1.ResultInProgress, nil
 }
@@ -0,0 +1,289 @@
+package internal_tls
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	core "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Certificates are deleted before the issuers, the secrets are not owned by the certificates and
+// are deleted explicitly
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	var objects []runtime.Object
+	for _, component := range model.InternalTLSComponents {
+		objects = append(objects, model.GetInternalTLSCertificate(cr, component), model.GetInternalTLSSecret(cr, component))
+	}
+	objects = append(objects,
+		model.GetInternalTLSCAIssuer(cr),
+		model.GetInternalTLSCACertificate(cr),
+		&core.Secret{ObjectMeta: metav1.ObjectMeta{Name: model.InternalTLSCAName, Namespace: cr.Namespace}},
+		model.GetInternalTLSSelfSignedIssuer(cr),
+		model.GetInternalTLSRoleBinding(cr),
+		model.GetInternalTLSRole(cr),
+		model.GetInternalTLSProxyConfigMap(cr),
+	)
+
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	if !cr.InternalTLSEnabled() {
+		meta.RemoveStatusCondition(&s.Conditions, v1.InternalTLSReady)
+		return r.Cleanup(ctx, cr)
+	}
+
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// The stack keeps running without internal TLS until cert-manager is installed
+	if !capabilities.CertManager {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.InternalTLSReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  "CertManagerNotInstalled",
+			Message: "spec.tls.internal requires cert-manager",
+		})
+		return v1.ResultSuccess, nil
+	}
+
+	status, err := r.reconcileIssuers(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	for _, component := range model.InternalTLSComponents {
+		status, err = r.reconcileCertificate(ctx, cr, component)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
+	}
+
+	status, err = r.reconcileAuthorization(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	return r.waitForCertificates(ctx, cr, s)
+}
+
+// A self signed issuer issues the CA certificate of the namespace, the CA issuer the component
+// certificates. cert-manager renews all of them before they expire
+func (r *Reconciler) reconcileIssuers(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	selfSigned := model.GetInternalTLSSelfSignedIssuer(cr)
+	err := utils.Apply(ctx, r.client, selfSigned, func() error {
+		selfSigned.SetLabels(getLabels())
+		return unstructured.SetNestedMap(selfSigned.Object, map[string]interface{}{
+			"selfSigned": map[string]interface{}{},
+		}, "spec")
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	ca := model.GetInternalTLSCACertificate(cr)
+	err = utils.Apply(ctx, r.client, ca, func() error {
+		ca.SetLabels(getLabels())
+		return unstructured.SetNestedMap(ca.Object, map[string]interface{}{
+			"isCA":       true,
+			"commonName": model.InternalTLSCAName,
+			"secretName": model.InternalTLSCAName,
+			"privateKey": map[string]interface{}{
+				"algorithm": "ECDSA",
+				"size":      int64(256),
+			},
+			"issuerRef": map[string]interface{}{
+				"name":  selfSigned.GetName(),
+				"kind":  model.IssuerGroupVersionKind.Kind,
+				"group": model.IssuerGroupVersionKind.Group,
+			},
+		}, "spec")
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	issuer := model.GetInternalTLSCAIssuer(cr)
+	err = utils.Apply(ctx, r.client, issuer, func() error {
+		issuer.SetLabels(getLabels())
+		return unstructured.SetNestedMap(issuer.Object, map[string]interface{}{
+			"ca": map[string]interface{}{
+				"secretName": model.InternalTLSCAName,
+			},
+		}, "spec")
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// Prometheus and Alertmanager get server certificates for their services. Prometheus is also a
+// client of Alertmanager
+func (r *Reconciler) reconcileCertificate(ctx context.Context, cr *v1.Observability, component string) (v1.ObservabilityStageStatus, error) {
+	usages := []interface{}{"digital signature", "key encipherment", "client auth"}
+	dnsNames := model.GetInternalTLSDNSNames(cr, component)
+	if len(dnsNames) > 0 {
+		usages = append(usages, "server auth")
+	}
+
+	certificate := model.GetInternalTLSCertificate(cr, component)
+	err := utils.Apply(ctx, r.client, certificate, func() error {
+		certificate.SetLabels(getLabels())
+		spec := map[string]interface{}{
+			"commonName": model.GetInternalTLSUser(component),
+			"secretName": model.GetInternalTLSSecretName(component),
+			"usages":     usages,
+			"privateKey": map[string]interface{}{
+				"algorithm":      "ECDSA",
+				"size":           int64(256),
+				"rotationPolicy": "Always",
+			},
+			"issuerRef": map[string]interface{}{
+				"name":  model.InternalTLSCAName,
+				"kind":  model.IssuerGroupVersionKind.Kind,
+				"group": model.IssuerGroupVersionKind.Group,
+			},
+		}
+		if len(dnsNames) > 0 {
+			var names []interface{}
+			for _, name := range dnsNames {
+				names = append(names, name)
+			}
+			spec["dnsNames"] = names
+		}
+		return unstructured.SetNestedMap(certificate.Object, spec, "spec")
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// kube-rbac-proxy authorizes the users of the client certificates, see GetInternalTLSProxyConfig
+func (r *Reconciler) reconcileAuthorization(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	configMap := model.GetInternalTLSProxyConfigMap(cr)
+	err := utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Labels = getLabels()
+		configMap.Data = map[string]string{
+			"prometheus.yaml":   model.GetInternalTLSProxyConfig(cr, model.GetPrometheusService(cr).Name),
+			"alertmanager.yaml": model.GetInternalTLSProxyConfig(cr, model.GetAlertmanagerService(cr).Name),
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	role := model.GetInternalTLSRole(cr)
+	err = utils.Apply(ctx, r.client, role, func() error {
+		role.Labels = getLabels()
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				Verbs:     []string{"get", "create"},
+				APIGroups: []string{""},
+				Resources: []string{fmt.Sprintf("services/%v", model.InternalTLSSubresource)},
+				ResourceNames: []string{
+					model.GetPrometheusService(cr).Name,
+					model.GetAlertmanagerService(cr).Name,
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	binding := model.GetInternalTLSRoleBinding(cr)
+	err = utils.Apply(ctx, r.client, binding, func() error {
+		binding.Labels = getLabels()
+		binding.Subjects = nil
+		for _, component := range []string{model.InternalTLSPrometheus, model.InternalTLSGrafana} {
+			binding.Subjects = append(binding.Subjects, rbacv1.Subject{
+				Kind:     rbacv1.UserKind,
+				APIGroup: rbacv1.GroupName,
+				Name:     model.GetInternalTLSUser(component),
+			})
+		}
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role.Name,
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// The components mount the secrets, so they are only configured once all certificates are issued
+func (r *Reconciler) waitForCertificates(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	for _, component := range model.InternalTLSComponents {
+		secret := model.GetInternalTLSSecret(cr, component)
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: secret.Namespace, Name: secret.Name}, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+		if errors.IsNotFound(err) || len(secret.Data[core.TLSCertKey]) == 0 {
+			meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+				Type:    v1.InternalTLSReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  "CertificatesPending",
+				Message: fmt.Sprintf("waiting for certificate %v", secret.Name),
+			})
+			return v1.ResultInProgress, nil
+		}
+	}
+
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:   v1.InternalTLSReady,
+		Status: metav1.ConditionTrue,
+		Reason: "CertificatesIssued",
+	})
+	return v1.ResultSuccess, nil
+}
+
+func getLabels() map[string]string {
+	return map[string]string{
+		"managed-by": "observability-operator",
+	}
+}
@@ -0,0 +1,184 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Adopted resources are left behind like the other resources of the CR that are not cleaned up
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	return v1.ResultSuccess, nil
+}
+
+// Adopt the resources created by the v2 operator without recreating them. Fields of the spec that
+// the v2 resources depend on are translated first, so that the later stages update the resources in
+// place instead of replacing the volumes of Prometheus
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	if s.Migration != nil && s.Migration.Completed != 0 {
+		return v1.ResultSuccess, nil
+	}
+
+	detected, err := r.detectV2Resources(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	// Nothing to adopt, the stage doesn't look again
+	if len(detected) == 0 && s.Migration == nil {
+		s.Migration = &v1.MigrationStatus{Completed: time.Now().Unix()}
+		return v1.ResultSuccess, nil
+	}
+	if s.Migration == nil {
+		s.Migration = &v1.MigrationStatus{}
+	}
+	s.Migration.Detected = s.Migration.Adopted + len(detected)
+
+	translated, err := r.translateSpec(ctx, cr, detected)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if len(translated) > 0 {
+		s.Migration.TranslatedFields = append(s.Migration.TranslatedFields, translated...)
+		r.logger.Info("translated the spec from the v2 resources", "fields", translated)
+		// The next reconcile runs the stages with the translated spec
+		return v1.ResultInProgress, nil
+	}
+
+	for _, object := range detected {
+		err = r.adopt(ctx, object)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		s.Migration.Adopted++
+	}
+
+	s.Migration.Completed = time.Now().Unix()
+	r.logger.Info("adopted the resources of the v2 operator", "resources", s.Migration.Adopted)
+	return v1.ResultSuccess, nil
+}
+
+// Returns the resources of the v2 operator that are not adopted yet
+func (r *Reconciler) detectV2Resources(ctx context.Context, cr *v1.Observability) ([]runtime.Object, error) {
+	var detected []runtime.Object
+	for _, object := range model.GetV2Resources(cr) {
+		accessor, err := meta.Accessor(object)
+		if err != nil {
+			return nil, err
+		}
+		err = r.client.Get(ctx, client.ObjectKey{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}, object)
+		if err != nil {
+			if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+		if model.IsV2Resource(accessor) {
+			detected = append(detected, object)
+		}
+	}
+	return detected, nil
+}
+
+// The v2 CR schema is not known to this operator, the API server prunes its fields. What the v2
+// spec configured is read from the v2 Prometheus instead. Only fields that the CR and the defaults
+// leave empty are translated, and the CR is patched with them. Returns the translated fields
+func (r *Reconciler) translateSpec(ctx context.Context, cr *v1.Observability, detected []runtime.Object) ([]string, error) {
+	var prometheus *prometheusv1.Prometheus
+	for _, object := range detected {
+		if p, ok := object.(*prometheusv1.Prometheus); ok {
+			prometheus = p
+		}
+	}
+	if prometheus == nil {
+		return nil, nil
+	}
+
+	patched := cr.DeepCopy()
+	var translated []string
+	// A changed volume claim template replaces the volumes of Prometheus, losing its data
+	if prometheus.Spec.Storage != nil && (cr.Spec.Storage == nil || cr.Spec.Storage.PrometheusStorageSpec == nil) {
+		if patched.Spec.Storage == nil {
+			patched.Spec.Storage = &v1.Storage{}
+		}
+		patched.Spec.Storage.PrometheusStorageSpec = prometheus.Spec.Storage.DeepCopy()
+		translated = append(translated, "spec.storage.prometheus")
+	}
+	if prometheus.Spec.Retention != "" && cr.Spec.Retention == "" {
+		patched.Spec.Retention = prometheus.Spec.Retention
+		translated = append(translated, "spec.retention")
+	}
+	if len(prometheus.Spec.Tolerations) > 0 && len(cr.Spec.Tolerations) == 0 {
+		patched.Spec.Tolerations = prometheus.Spec.Tolerations
+		translated = append(translated, "spec.tolerations")
+	}
+	if prometheus.Spec.Affinity != nil && cr.Spec.Affinity == nil {
+		patched.Spec.Affinity = prometheus.Spec.Affinity.DeepCopy()
+		translated = append(translated, "spec.affinity")
+	}
+	if len(translated) == 0 {
+		return nil, nil
+	}
+
+	err := r.client.Patch(ctx, patched, client.MergeFrom(cr))
+	if err != nil {
+		return nil, fmt.Errorf("error translating %v from prometheus %v: %w", translated, prometheus.Name, err)
+	}
+	// The status is updated after the stages, on top of the patched CR
+	cr.ResourceVersion = patched.ResourceVersion
+	return translated, nil
+}
+
+// Labels the object as adopted and removes its owner references to the v2 CR, the garbage collector
+// would delete it with the v2 CR. The object is patched in place, its spec and the labels the v3
+// operator selects its resources by are left to the stages that manage it
+func (r *Reconciler) adopt(ctx context.Context, object runtime.Object) error {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(object.DeepCopyObject())
+	labels := accessor.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[model.MigratedLabel] = model.MigratedFrom
+	accessor.SetLabels(labels)
+
+	var owners []metav1.OwnerReference
+	for _, owner := range accessor.GetOwnerReferences() {
+		if !model.IsObservabilityOwner(owner) {
+			owners = append(owners, owner)
+		}
+	}
+	accessor.SetOwnerReferences(owners)
+
+	err = r.client.Patch(ctx, object, patch)
+	if err != nil {
+		return fmt.Errorf("error adopting %T %v: %w", object, accessor.GetName(), err)
+	}
+	return nil
+}
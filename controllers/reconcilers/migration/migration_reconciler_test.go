@@ -0,0 +1,108 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	grafana "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const namespace = "observability"
+
+func newReconciler(t *testing.T, objects ...runtime.Object) (*Reconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, v1.AddToScheme, prometheusv1.AddToScheme, grafana.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := fake.NewFakeClientWithScheme(scheme, objects...)
+	return &Reconciler{client: c, logger: log.NullLogger{}}, c
+}
+
+func newObservability() *v1.Observability {
+	return &v1.Observability{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "Observability"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "observability-stack", UID: "stack"},
+	}
+}
+
+func owner(cr *v1.Observability) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{APIVersion: cr.APIVersion, Kind: cr.Kind, Name: cr.Name, UID: cr.UID}}
+}
+
+func TestReconcileLeavesV3ResourcesAlone(t *testing.T) {
+	cr := newObservability()
+	prometheus := model.GetPrometheus(cr)
+	prometheus.Labels = map[string]string{"managed-by": "observability-operator"}
+	// Labelled like a v2 resource, but with another name and not owned by the CR
+	rule := &prometheusv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{
+		Namespace: namespace,
+		Name:      "kafka-rules",
+		Labels:    map[string]string{"app.kubernetes.io/managed-by": "observability-operator"},
+	}}
+	r, c := newReconciler(t, cr, prometheus, rule)
+
+	s := &v1.ObservabilityStatus{}
+	status, err := r.Reconcile(context.Background(), cr, s)
+	if err != nil || status != v1.ResultSuccess {
+		t.Fatalf("Reconcile() = %v, %v, want %v", status, err, v1.ResultSuccess)
+	}
+	if s.Migration == nil || s.Migration.Completed == 0 || s.Migration.Detected != 0 {
+		t.Errorf("Reconcile() migration = %+v, want completed without detected resources", s.Migration)
+	}
+
+	gotPrometheus := &prometheusv1.Prometheus{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: prometheus.Name}, gotPrometheus); err != nil {
+		t.Fatal(err)
+	}
+	gotRule := &prometheusv1.PrometheusRule{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: rule.Name}, gotRule); err != nil {
+		t.Fatal(err)
+	}
+	if model.IsMigrated(gotPrometheus) || model.IsMigrated(gotRule) {
+		t.Errorf("Reconcile() adopted the resources of the v3 operator")
+	}
+}
+
+func TestReconcileAdoptsV2Resources(t *testing.T) {
+	cr := newObservability()
+	prometheus := model.GetPrometheus(cr)
+	prometheus.OwnerReferences = owner(cr)
+	grafanaCr := model.GetGrafanaCr(cr)
+	grafanaCr.OwnerReferences = owner(cr)
+	secret := model.GetPrometheusProxySecret(cr)
+	secret.OwnerReferences = owner(cr)
+	// Owned by the CR, but not a resource of the v2 operator
+	other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "other", OwnerReferences: owner(cr)}}
+	r, c := newReconciler(t, cr, prometheus, grafanaCr, secret, other)
+
+	s := &v1.ObservabilityStatus{}
+	status, err := r.Reconcile(context.Background(), cr, s)
+	if err != nil || status != v1.ResultSuccess {
+		t.Fatalf("Reconcile() = %v, %v, want %v", status, err, v1.ResultSuccess)
+	}
+	if s.Migration == nil || s.Migration.Completed == 0 || s.Migration.Detected != 3 || s.Migration.Adopted != 3 {
+		t.Errorf("Reconcile() migration = %+v, want 3 adopted resources", s.Migration)
+	}
+
+	for name, want := range map[string]bool{secret.Name: true, other.Name: false} {
+		got := &corev1.Secret{}
+		if err := c.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, got); err != nil {
+			t.Fatal(err)
+		}
+		if model.IsMigrated(got) != want {
+			t.Errorf("adopted %v = %v, want %v", name, model.IsMigrated(got), want)
+		}
+	}
+}
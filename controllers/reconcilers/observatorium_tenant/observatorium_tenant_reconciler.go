@@ -0,0 +1,178 @@
+package observatorium_tenant
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/token"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	CredentialsClientId     = "clientId"
+	CredentialsClientSecret = "clientSecret"
+	// Tenants are verified at most once per interval to stay clear of the Observatorium rate limits
+	VerificationInterval = 5 * time.Minute
+)
+
+type Reconciler struct {
+	client     client.Client
+	logger     logr.Logger
+	httpClient *http.Client
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	httpClient := &http.Client{Transport: tr, Timeout: 30 * time.Second}
+
+	return &Reconciler{
+		client:     client,
+		logger:     logger,
+		httpClient: httpClient,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	return v1.ResultSuccess, nil
+}
+
+// Verify that the tenant is known to the Observatorium API and that its credentials are accepted.
+// The Observatorium API has no endpoint to register tenants, they are read from its tenants
+// configuration (name, id, OIDC client and rate limits) when it starts. With a registration, the
+// tenant is added to that configuration if the Observatorium API runs in the same cluster. A failed
+// registration or verification is only reported in the status of the CR and does not block the
+// installation.
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	if !cr.HasObservatoriumTenant() {
+		meta.RemoveStatusCondition(&s.Conditions, v1.ObservatoriumTenantReady)
+		s.ObservatoriumTenantLastChecked = 0
+		return v1.ResultSuccess, nil
+	}
+
+	lastChecked := time.Unix(s.ObservatoriumTenantLastChecked, 0)
+	if time.Now().Before(lastChecked.Add(VerificationInterval)) {
+		return v1.ResultSuccess, nil
+	}
+
+	tenant := cr.Spec.Observatorium.Tenant
+	status, reason, message := r.registerAndVerifyTenant(ctx, cr, tenant)
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:    v1.ObservatoriumTenantReady,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	s.ObservatoriumTenantLastChecked = time.Now().Unix()
+
+	if status != metav1.ConditionTrue {
+		r.logger.Info("observatorium tenant verification failed", "tenant", tenant.Tenant, "reason", reason, "message", message)
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// A tenant that was just registered is verified once the Observatorium API restarted, with the next
+// verification
+func (r *Reconciler) registerAndVerifyTenant(ctx context.Context, cr *v1.Observability, tenant *v1.ObservatoriumTenant) (metav1.ConditionStatus, string, string) {
+	if tenant.Registration == nil {
+		return r.verifyTenant(ctx, cr, tenant)
+	}
+
+	clientId, _, err := r.getCredentials(ctx, cr, tenant)
+	if err != nil {
+		return metav1.ConditionFalse, "CredentialsMissing", err.Error()
+	}
+	registered, err := r.registerTenant(ctx, tenant, clientId)
+	if err != nil {
+		return metav1.ConditionFalse, "RegistrationFailed", err.Error()
+	}
+	if registered {
+		r.logger.Info("registered observatorium tenant", "tenant", tenant.Tenant,
+			"namespace", tenant.Registration.Namespace, "secret", tenant.Registration.Secret)
+		return metav1.ConditionUnknown, "TenantRegistered", fmt.Sprintf("tenant %v registered in %v/%v, the Observatorium API reads it when it restarts",
+			tenant.Tenant, tenant.Registration.Namespace, tenant.Registration.Secret)
+	}
+	return r.verifyTenant(ctx, cr, tenant)
+}
+
+func (r *Reconciler) getCredentials(ctx context.Context, cr *v1.Observability, tenant *v1.ObservatoriumTenant) (string, string, error) {
+	secret := &v12.Secret{}
+	selector := client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      tenant.CredentialsSecret,
+	}
+
+	err := r.client.Get(ctx, selector, secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	clientId := string(secret.Data[CredentialsClientId])
+	clientSecret := string(secret.Data[CredentialsClientSecret])
+	if clientId == "" || clientSecret == "" {
+		return "", "", fmt.Errorf("secret %v must contain %v and %v", secret.Name, CredentialsClientId, CredentialsClientSecret)
+	}
+	return clientId, clientSecret, nil
+}
+
+func (r *Reconciler) verifyTenant(ctx context.Context, cr *v1.Observability, tenant *v1.ObservatoriumTenant) (metav1.ConditionStatus, string, string) {
+	clientId, clientSecret, err := r.getCredentials(ctx, cr, tenant)
+	if err != nil {
+		return metav1.ConditionFalse, "CredentialsMissing", err.Error()
+	}
+
+	tokenEndpoint, err := token.GetTokenEndpoint(r.httpClient, tenant.OIDCIssuerUrl)
+	if err != nil {
+		return metav1.ConditionFalse, "InvalidOIDCConfig", err.Error()
+	}
+
+	accessToken, err := token.FetchClientCredentialsToken(r.httpClient, tokenEndpoint, clientId, clientSecret)
+	if err != nil {
+		return metav1.ConditionFalse, "AuthenticationFailed", err.Error()
+	}
+
+	// Run a trivial query to check that the tenant exists and accepts the token
+	query := url.Values{"query": {"vector(1)"}}
+	queryUrl := fmt.Sprintf("%v/api/metrics/v1/%v/api/v1/query?%v",
+		strings.TrimSuffix(tenant.Gateway, "/"), tenant.Tenant, query.Encode())
+
+	req, err := http.NewRequest(http.MethodGet, queryUrl, nil)
+	if err != nil {
+		return metav1.ConditionFalse, "InvalidGateway", err.Error()
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return metav1.ConditionUnknown, "GatewayUnavailable", err.Error()
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return metav1.ConditionTrue, "TenantVerified", fmt.Sprintf("tenant %v verified", tenant.Tenant)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return metav1.ConditionFalse, "Unauthorized",
+			fmt.Sprintf("observatorium rejected the credentials for tenant %v: %v", tenant.Tenant, resp.Status)
+	case http.StatusNotFound:
+		return metav1.ConditionFalse, "TenantNotFound", fmt.Sprintf("tenant %v is not registered, it has to be added to the tenants configuration of the Observatorium API or set a registration", tenant.Tenant)
+	case http.StatusTooManyRequests:
+		return metav1.ConditionFalse, "RateLimited",
+			fmt.Sprintf("tenant %v is rate limited, retry after %v", tenant.Tenant, resp.Header.Get("Retry-After"))
+	default:
+		return metav1.ConditionUnknown, "UnexpectedResponse", fmt.Sprintf("unexpected response from observatorium: %v", resp.Status)
+	}
+}
@@ -0,0 +1,120 @@
+package observatorium_tenant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Key of the tenants configuration in the secret of the Observatorium API
+const DefaultTenantsKey = "tenants.yaml"
+
+// Adds the tenant to the tenants configuration of the Observatorium API, or updates its OIDC client
+// and rate limits. Other tenants and the other settings of the tenant are kept. Returns true if the
+// configuration changed, the Observatorium API only reads it when it starts
+func (r *Reconciler) registerTenant(ctx context.Context, tenant *v1.ObservatoriumTenant, clientId string) (bool, error) {
+	registration := tenant.Registration
+	key := registration.Key
+	if key == "" {
+		key = DefaultTenantsKey
+	}
+
+	secret := &v12.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: registration.Namespace, Name: registration.Secret}, secret)
+	if err != nil {
+		return false, fmt.Errorf("error reading the tenants configuration %v/%v: %w", registration.Namespace, registration.Secret, err)
+	}
+
+	config, changed, err := setTenant(secret.Data[key], tenant, clientId)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenants configuration in %v/%v: %w", registration.Namespace, registration.Secret, err)
+	}
+	if !changed {
+		return false, nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[key] = config
+	err = r.client.Update(ctx, secret)
+	if err != nil {
+		return false, fmt.Errorf("error registering tenant %v in %v/%v: %w", tenant.Tenant, registration.Namespace, registration.Secret, err)
+	}
+	return true, nil
+}
+
+// Sets the tenant in the tenants configuration, in the format of the Observatorium API. Returns the
+// configuration and true if it changed
+func setTenant(source []byte, tenant *v1.ObservatoriumTenant, clientId string) ([]byte, bool, error) {
+	existing := map[string]interface{}{}
+	config := map[string]interface{}{}
+	for _, into := range []*map[string]interface{}{&existing, &config} {
+		err := yaml.Unmarshal(source, into)
+		if err != nil {
+			return nil, false, err
+		}
+		if *into == nil {
+			*into = map[string]interface{}{}
+		}
+	}
+
+	tenants, _ := config["tenants"].([]interface{})
+	var entry map[string]interface{}
+	for _, t := range tenants {
+		if candidate, ok := t.(map[string]interface{}); ok && candidate["name"] == tenant.Tenant {
+			entry = candidate
+		}
+	}
+	if entry == nil {
+		entry = map[string]interface{}{"name": tenant.Tenant}
+		tenants = append(tenants, entry)
+	}
+
+	registration := tenant.Registration
+	if registration.Id != "" {
+		entry["id"] = registration.Id
+	} else if id, _ := entry["id"].(string); id == "" {
+		entry["id"] = string(uuid.NewUUID())
+	}
+
+	oidc, _ := entry["oidc"].(map[string]interface{})
+	if oidc == nil {
+		oidc = map[string]interface{}{}
+	}
+	oidc["clientID"] = clientId
+	oidc["issuerURL"] = tenant.OIDCIssuerUrl
+	entry["oidc"] = oidc
+
+	if len(registration.RateLimits) > 0 {
+		// Through JSON, so that unchanged limits compare equal to the parsed ones
+		bytes, err := json.Marshal(registration.RateLimits)
+		if err != nil {
+			return nil, false, err
+		}
+		var rateLimits []interface{}
+		err = json.Unmarshal(bytes, &rateLimits)
+		if err != nil {
+			return nil, false, err
+		}
+		entry["rateLimits"] = rateLimits
+	}
+
+	config["tenants"] = tenants
+	if reflect.DeepEqual(existing, config) {
+		return source, false, nil
+	}
+
+	result, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, true, nil
+}
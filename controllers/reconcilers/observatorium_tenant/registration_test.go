@@ -0,0 +1,138 @@
+package observatorium_tenant
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+const tenantsConfig = `tenants:
+- name: other
+  id: 11111111-1111-1111-1111-111111111111
+  oidc:
+    clientID: other
+    issuerURL: https://sso.example.com/auth/realms/other
+- name: managedkafka
+  id: 22222222-2222-2222-2222-222222222222
+  oidc:
+    clientID: old
+    issuerURL: https://sso.example.com/auth/realms/observatorium
+    usernameClaim: email
+`
+
+func TestSetTenant(t *testing.T) {
+	tenant := func(registration v1.ObservatoriumTenantRegistration) *v1.ObservatoriumTenant {
+		return &v1.ObservatoriumTenant{
+			Tenant:        "managedkafka",
+			OIDCIssuerUrl: "https://sso.example.com/auth/realms/observatorium",
+			Registration:  &registration,
+		}
+	}
+	rateLimits := []v1.ObservatoriumRateLimit{{Endpoint: "/api/metrics/v1/.+/api/v1/receive", Limit: 1000, Window: "1s"}}
+
+	tests := []struct {
+		name        string
+		source      string
+		tenant      *v1.ObservatoriumTenant
+		clientId    string
+		wantChanged bool
+		want        map[string]interface{}
+	}{
+		{
+			name:        "updates the client and keeps the other settings",
+			source:      tenantsConfig,
+			tenant:      tenant(v1.ObservatoriumTenantRegistration{}),
+			clientId:    "new",
+			wantChanged: true,
+			want: map[string]interface{}{
+				"name": "managedkafka",
+				"id":   "22222222-2222-2222-2222-222222222222",
+				"oidc": map[string]interface{}{
+					"clientID":      "new",
+					"issuerURL":     "https://sso.example.com/auth/realms/observatorium",
+					"usernameClaim": "email",
+				},
+			},
+		},
+		{
+			name:        "unchanged",
+			source:      tenantsConfig,
+			tenant:      tenant(v1.ObservatoriumTenantRegistration{}),
+			clientId:    "old",
+			wantChanged: false,
+		},
+		{
+			name:        "sets the id and rate limits",
+			source:      tenantsConfig,
+			tenant:      tenant(v1.ObservatoriumTenantRegistration{Id: "33333333-3333-3333-3333-333333333333", RateLimits: rateLimits}),
+			clientId:    "old",
+			wantChanged: true,
+			want: map[string]interface{}{
+				"name": "managedkafka",
+				"id":   "33333333-3333-3333-3333-333333333333",
+				"oidc": map[string]interface{}{
+					"clientID":      "old",
+					"issuerURL":     "https://sso.example.com/auth/realms/observatorium",
+					"usernameClaim": "email",
+				},
+				"rateLimits": []interface{}{
+					map[string]interface{}{"endpoint": "/api/metrics/v1/.+/api/v1/receive", "limit": float64(1000), "window": "1s"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, changed, err := setTenant([]byte(tt.source), tt.tenant, tt.clientId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("setTenant() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if !changed {
+				return
+			}
+
+			config := map[string]interface{}{}
+			if err := yaml.Unmarshal(result, &config); err != nil {
+				t.Fatal(err)
+			}
+			tenants := config["tenants"].([]interface{})
+			if len(tenants) != 2 || tenants[0].(map[string]interface{})["name"] != "other" {
+				t.Errorf("setTenant() tenants = %v, want the other tenant kept", tenants)
+			}
+			if got := tenants[1]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setTenant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTenantRegistersNewTenant(t *testing.T) {
+	tenant := &v1.ObservatoriumTenant{
+		Tenant:        "managedkafka",
+		OIDCIssuerUrl: "https://sso.example.com/auth/realms/observatorium",
+		Registration:  &v1.ObservatoriumTenantRegistration{},
+	}
+	result, changed, err := setTenant(nil, tenant, "client")
+	if err != nil || !changed {
+		t.Fatalf("setTenant() = %v, %v, want a changed configuration", changed, err)
+	}
+
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal(result, &config); err != nil {
+		t.Fatal(err)
+	}
+	entry := config["tenants"].([]interface{})[0].(map[string]interface{})
+	if entry["name"] != "managedkafka" || entry["id"] == "" {
+		t.Errorf("setTenant() = %v, want the tenant with a generated id", entry)
+	}
+
+	_, changed, err = setTenant(result, tenant, "client")
+	if err != nil || changed {
+		t.Errorf("setTenant() on the registered tenant = %v, %v, want unchanged", changed, err)
+	}
+}
@@ -0,0 +1,256 @@
+package profiles
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	v1alpha12 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	err := r.deleteUnrequested(ctx, cr, map[string]bool{})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	return v1.ResultSuccess, nil
+}
+
+// Generates the pod monitors, rules and dashboards of the workload profiles in spec.profiles. They are
+// labeled with their profile, so that the configuration stage leaves them alone
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	if len(cr.Spec.Profiles) == 0 {
+		s.AppliedProfiles = nil
+		return r.Cleanup(ctx, cr)
+	}
+
+	for _, api := range []string{utils.APIPrometheusRule, utils.APIPodMonitor} {
+		served, err := utils.RequireAPI(ctx, r.client, cr, s, api)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		if !served {
+			return v1.ResultSuccess, nil
+		}
+	}
+
+	// The selectors of Prometheus and Grafana are set by the configuration stages, from the CR or the
+	// repository index
+	var ruleLabels, monitorLabels, dashboardLabels map[string]string
+	if cr.PrometheusMode() == v1.ComponentManaged {
+		prometheus := model.GetPrometheus(cr)
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: prometheus.Namespace, Name: prometheus.Name}, prometheus)
+		if errors.IsNotFound(err) {
+			return v1.ResultInProgress, nil
+		}
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		if prometheus.Spec.RuleSelector != nil {
+			ruleLabels = prometheus.Spec.RuleSelector.MatchLabels
+		}
+		if prometheus.Spec.PodMonitorSelector != nil {
+			monitorLabels = prometheus.Spec.PodMonitorSelector.MatchLabels
+		}
+	}
+
+	dashboards := false
+	if cr.GrafanaMode() == v1.ComponentManaged {
+		served, err := utils.RequireAPI(ctx, r.client, cr, s, utils.APIGrafanaDashboard)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		grafana := model.GetGrafanaCr(cr)
+		err = r.client.Get(ctx, client.ObjectKey{Namespace: grafana.Namespace, Name: grafana.Name}, grafana)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return v1.ResultFailed, err
+		}
+		if err == nil && len(grafana.Spec.DashboardLabelSelector) > 0 && grafana.Spec.DashboardLabelSelector[0] != nil {
+			dashboardLabels = grafana.Spec.DashboardLabelSelector[0].MatchLabels
+		}
+		dashboards = served && err == nil
+	}
+
+	requested := map[string]bool{}
+	var applied []string
+	for _, name := range cr.Spec.Profiles {
+		profile := model.GetProfile(name)
+		if profile == nil {
+			continue
+		}
+
+		err := r.reconcileRule(ctx, cr, profile, ruleLabels)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		err = r.reconcilePodMonitors(ctx, cr, profile, monitorLabels)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+		if dashboards {
+			err = r.reconcileDashboard(ctx, cr, profile, dashboardLabels)
+			if err != nil {
+				return v1.ResultFailed, err
+			}
+		}
+
+		requested[profile.Name] = true
+		applied = append(applied, fmt.Sprintf("%v:%v", profile.Name, profile.Version))
+	}
+
+	err := r.deleteUnrequested(ctx, cr, requested)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	s.AppliedProfiles = applied
+
+	return v1.ResultSuccess, nil
+}
+
+func getProfileLabels(profile *model.Profile, selectorLabels map[string]string) map[string]string {
+	labels := map[string]string{
+		"managed-by":       "observability-operator",
+		model.ProfileLabel: profile.Name,
+	}
+	for k, v := range selectorLabels {
+		labels[k] = v
+	}
+	return labels
+}
+
+func (r *Reconciler) reconcileRule(ctx context.Context, cr *v1.Observability, profile *model.Profile, selectorLabels map[string]string) error {
+	rule := model.GetProfileRule(cr, profile.Name)
+	return utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = getProfileLabels(profile, selectorLabels)
+		rule.Annotations = map[string]string{
+			model.ProfileVersionAnnotation: profile.Version,
+		}
+		rule.Spec.Groups = []prometheusv1.RuleGroup{
+			{
+				Name:  model.GetProfileResourceName(profile.Name),
+				Rules: profile.Rules,
+			},
+		}
+		return nil
+	})
+}
+
+func (r *Reconciler) reconcilePodMonitors(ctx context.Context, cr *v1.Observability, profile *model.Profile, selectorLabels map[string]string) error {
+	shards := model.GetPrometheusShards(cr)
+	for shard := int32(0); shard < shards; shard++ {
+		endpoint := prometheusv1.PodMetricsEndpoint{
+			Port: profile.Port,
+		}
+		if shards > 1 {
+			endpoint.RelabelConfigs = model.GetPrometheusShardRelabelings(shard, shards)
+		}
+
+		monitor := model.GetProfilePodMonitor(cr, profile.Name, shard)
+		err := utils.Apply(ctx, r.client, monitor, func() error {
+			monitor.Labels = getProfileLabels(profile, selectorLabels)
+			if shards > 1 {
+				monitor.Labels[model.PrometheusShardLabel] = strconv.Itoa(int(shard))
+			}
+			monitor.Annotations = map[string]string{
+				model.ProfileVersionAnnotation: profile.Version,
+			}
+			monitor.Spec = prometheusv1.PodMonitorSpec{
+				Selector:            profile.PodSelector,
+				NamespaceSelector:   prometheusv1.NamespaceSelector{Any: true},
+				PodMetricsEndpoints: []prometheusv1.PodMetricsEndpoint{endpoint},
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileDashboard(ctx context.Context, cr *v1.Observability, profile *model.Profile, selectorLabels map[string]string) error {
+	source, err := model.GetProfileDashboardJson(profile)
+	if err != nil {
+		return err
+	}
+
+	dashboard := model.GetProfileDashboard(cr, profile.Name)
+	return utils.Apply(ctx, r.client, dashboard, func() error {
+		dashboard.Labels = getProfileLabels(profile, selectorLabels)
+		dashboard.Annotations = map[string]string{
+			model.ProfileVersionAnnotation: profile.Version,
+		}
+		dashboard.Spec = v1alpha12.GrafanaDashboardSpec{
+			Json: source,
+		}
+		return nil
+	})
+}
+
+// Deletes the resources of profiles that are no longer requested and the pod monitor copies of
+// removed shards
+func (r *Reconciler) deleteUnrequested(ctx context.Context, cr *v1.Observability, requested map[string]bool) error {
+	names := map[string]bool{}
+	for profile := range requested {
+		names[model.GetProfileResourceName(profile)] = true
+		for shard := int32(1); shard < model.GetPrometheusShards(cr); shard++ {
+			names[model.GetShardedPodMonitorName(model.GetProfileResourceName(profile), shard)] = true
+		}
+	}
+
+	lists := []runtime.Object{
+		&prometheusv1.PrometheusRuleList{},
+		&prometheusv1.PodMonitorList{},
+		&v1alpha12.GrafanaDashboardList{},
+	}
+	for _, list := range lists {
+		err := r.client.List(ctx, list, client.InNamespace(cr.Namespace), client.HasLabels{model.ProfileLabel})
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			object, err := meta.Accessor(item)
+			if err != nil {
+				return err
+			}
+			if names[object.GetName()] {
+				continue
+			}
+			err = r.client.Delete(ctx, item)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+package prometheus_adapter_installation
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	core "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	// The API service goes first, API discovery fails for all clients while it has no backend
+	err := r.deleteAPIService(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	objects := []runtime.Object{
+		model.GetPrometheusAdapterDeployment(cr),
+		model.GetPrometheusAdapterService(cr),
+		model.GetCustomMetricsReaderClusterRoleBinding(cr),
+		model.GetCustomMetricsReaderClusterRole(cr),
+		model.GetPrometheusAdapterAuthReaderBinding(cr),
+		model.GetPrometheusAdapterDelegatorBinding(cr),
+		model.GetPrometheusAdapterClusterRoleBinding(cr),
+		model.GetPrometheusAdapterClusterRole(cr),
+		model.GetPrometheusAdapterServiceAccount(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// The adapter is opt-in and removed again when spec.prometheusAdapter is removed
+	if !cr.PrometheusAdapterEnabled() {
+		return r.Cleanup(ctx, cr)
+	}
+
+	rules := &core.ConfigMap{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: cr.Spec.PrometheusAdapter.RulesConfigMap}, rules)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	config, ok := rules.Data[model.PrometheusAdapterConfigKey]
+	if !ok {
+		return v1.ResultFailed, fmt.Errorf("config map %v has no %v key", rules.Name, model.PrometheusAdapterConfigKey)
+	}
+
+	status, err := r.reconcileRBAC(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	status, err = r.reconcileService(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	hash := sha256.Sum256([]byte(config))
+	status, err = r.reconcileDeployment(ctx, cr, fmt.Sprintf("%x", hash)[:12])
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	return r.reconcileAPIService(ctx, cr)
+}
+
+func (r *Reconciler) reconcileRBAC(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	sa := model.GetPrometheusAdapterServiceAccount(cr)
+	err := utils.Apply(ctx, r.client, sa, func() error {
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	subjects := []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      sa.Name,
+			Namespace: sa.Namespace,
+		},
+	}
+
+	// The adapter maps the labels of the series to these resources
+	role := model.GetPrometheusAdapterClusterRole(cr)
+	err = utils.Apply(ctx, r.client, role, func() error {
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				Verbs:     []string{"get", "list", "watch"},
+				APIGroups: []string{""},
+				Resources: []string{"nodes", "namespaces", "pods", "services"},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	bindings := []struct {
+		binding *rbacv1.ClusterRoleBinding
+		role    string
+	}{
+		{model.GetPrometheusAdapterClusterRoleBinding(cr), role.Name},
+		{model.GetPrometheusAdapterDelegatorBinding(cr), "system:auth-delegator"},
+	}
+	for _, b := range bindings {
+		binding := b.binding
+		err = utils.Apply(ctx, r.client, binding, func() error {
+			binding.RoleRef = rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "ClusterRole",
+				Name:     b.role,
+			}
+			binding.Subjects = subjects
+			return nil
+		})
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+	}
+
+	authReader := model.GetPrometheusAdapterAuthReaderBinding(cr)
+	err = utils.Apply(ctx, r.client, authReader, func() error {
+		authReader.RoleRef = rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     "extension-apiserver-authentication-reader",
+		}
+		authReader.Subjects = subjects
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	reader := model.GetCustomMetricsReaderClusterRole(cr)
+	err = utils.Apply(ctx, r.client, reader, func() error {
+		reader.Rules = []rbacv1.PolicyRule{
+			{
+				Verbs:     []string{"get", "list", "watch"},
+				APIGroups: []string{"custom.metrics.k8s.io"},
+				Resources: []string{"*"},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	readerBinding := model.GetCustomMetricsReaderClusterRoleBinding(cr)
+	err = utils.Apply(ctx, r.client, readerBinding, func() error {
+		readerBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     reader.Name,
+		}
+		readerBinding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      "horizontal-pod-autoscaler",
+				Namespace: "kube-system",
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	service := model.GetPrometheusAdapterService(cr)
+	err := utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = model.GetPrometheusAdapterSelectorLabels()
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "https",
+				Port:       443,
+				TargetPort: intstr.FromString("https"),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileDeployment(ctx context.Context, cr *v1.Observability, rulesHash string) (v1.ObservabilityStageStatus, error) {
+	deployment := model.GetPrometheusAdapterDeployment(cr)
+	var replicas int32 = 1
+	err := utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: model.GetPrometheusAdapterSelectorLabels(),
+		}
+		deployment.Spec.Template = core.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: model.GetPrometheusAdapterSelectorLabels(),
+				Annotations: map[string]string{
+					model.PrometheusAdapterRulesAnnotation: rulesHash,
+				},
+			},
+			Spec: core.PodSpec{
+				ServiceAccountName: model.GetPrometheusAdapterServiceAccount(cr).Name,
+				PriorityClassName:  model.ObservabilityPriorityClassName,
+				Tolerations:        cr.Spec.Tolerations,
+				Affinity:           cr.Spec.Affinity,
+				Containers: []core.Container{
+					{
+						Name:  "prometheus-adapter",
+						Image: model.GetImage(cr, v1.ImagePrometheusAdapter, model.PrometheusAdapterImage),
+						Args: []string{
+							// Without certificates in the directory the adapter serves a self-signed one
+							"--cert-dir=/var/run/serving-cert",
+							fmt.Sprintf("--config=/etc/adapter/%v", model.PrometheusAdapterConfigKey),
+							"--logtostderr=true",
+							"--metrics-relist-interval=1m",
+							fmt.Sprintf("--prometheus-url=%v", model.GetPrometheusQueryUrl(cr)),
+							fmt.Sprintf("--secure-port=%d", model.PrometheusAdapterPort),
+						},
+						Ports: []core.ContainerPort{
+							{
+								Name:          "https",
+								ContainerPort: model.PrometheusAdapterPort,
+							},
+						},
+						VolumeMounts: []core.VolumeMount{
+							{
+								Name:      "config",
+								MountPath: "/etc/adapter",
+								ReadOnly:  true,
+							},
+							{
+								Name:      "serving-cert",
+								MountPath: "/var/run/serving-cert",
+							},
+						},
+					},
+				},
+				Volumes: []core.Volume{
+					{
+						Name: "config",
+						VolumeSource: core.VolumeSource{
+							ConfigMap: &core.ConfigMapVolumeSource{
+								LocalObjectReference: core.LocalObjectReference{
+									Name: cr.Spec.PrometheusAdapter.RulesConfigMap,
+								},
+							},
+						},
+					},
+					{
+						Name: "serving-cert",
+						VolumeSource: core.VolumeSource{
+							EmptyDir: &core.EmptyDirVolumeSource{},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// The API service is cluster wide, it is only deleted if it belongs to the adapter of this stack
+func (r *Reconciler) deleteAPIService(ctx context.Context, cr *v1.Observability) error {
+	apiService := model.GetCustomMetricsAPIService()
+	err := r.client.Get(ctx, client.ObjectKey{Name: apiService.GetName()}, apiService)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	namespace, _, _ := unstructured.NestedString(apiService.Object, "spec", "service", "namespace")
+	if namespace != cr.Namespace || apiService.GetLabels()[utils.ManagedByLabel] != utils.ManagedByValue {
+		return nil
+	}
+
+	err = r.client.Delete(ctx, apiService)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Registers the adapter as the custom metrics API. Its certificate is self-signed, so it is not
+// verified by the API server, like in the upstream manifests
+func (r *Reconciler) reconcileAPIService(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	apiService := model.GetCustomMetricsAPIService()
+	err := utils.Apply(ctx, r.client, apiService, func() error {
+		return unstructured.SetNestedMap(apiService.Object, map[string]interface{}{
+			"group":                 "custom.metrics.k8s.io",
+			"version":               "v1beta1",
+			"groupPriorityMinimum":  int64(100),
+			"versionPriority":       int64(100),
+			"insecureSkipTLSVerify": true,
+			"service": map[string]interface{}{
+				"name":      model.GetPrometheusAdapterService(cr).Name,
+				"namespace": cr.Namespace,
+			},
+		}, "spec")
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
@@ -3,6 +3,7 @@ package prometheus_configuration
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
@@ -17,7 +18,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type Reconciler struct {
@@ -42,6 +42,44 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 	// Delete route
 	route := model.GetPrometheusRoute(cr)
 	err = r.client.Delete(ctx, route)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
+	// Delete ingress
+	ingress := model.GetPrometheusIngress(cr)
+	err = r.client.Delete(ctx, ingress)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	// Delete tenant query proxy
+	status, err := r.deleteQueryProxy(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	// Delete query endpoint of the Prometheus shards
+	status, err = r.deleteThanosQuery(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	// Delete store gateway of the backup bucket
+	status, err = r.deleteThanosStore(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	// Delete ui access role and rolebinding
+	uiAccessBinding := model.GetUIAccessRoleBinding(cr)
+	err = r.client.Delete(ctx, uiAccessBinding)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	uiAccessRole := model.GetUIAccessRole(cr)
+	err = r.client.Delete(ctx, uiAccessRole)
 	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
@@ -53,8 +91,13 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 		return v1.ResultFailed, err
 	}
 
+	err = r.deletePrometheusShards(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
 	// Wait for the operator to be removed
-	status, err := r.waitForPrometheusToBeRemoved(ctx, cr)
+	status, err = r.waitForPrometheusToBeRemoved(ctx, cr)
 	if status != v1.ResultSuccess {
 		return status, err
 	}
@@ -114,8 +157,9 @@ func (r *Reconciler) waitForPrometheusToBeRemoved(ctx context.Context, cr *v1.Ob
 
 	prom := model.GetPrometheus(cr)
 
+	// Includes the statefulsets of the shards
 	for _, ss := range list.Items {
-		if ss.Name == fmt.Sprintf("prometheus-%s", prom.Name) {
+		if strings.HasPrefix(ss.Name, fmt.Sprintf("prometheus-%s", prom.Name)) {
 			return v1.ResultInProgress, nil
 		}
 	}
@@ -155,13 +199,56 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return status, err
 	}
 
-	// prometheus route
-	status, err = r.reconcileRoute(ctx, cr)
+	// prometheus route or ingress
+	accessType, err := utils.GetUIAccessType(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	if accessType == v1.UIAccessIngress {
+		status, err = r.reconcileIngress(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
+	} else {
+		served, err := utils.RequireAPI(ctx, r.client, cr, s, utils.APIRoute)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+
+		if served {
+			status, err = r.reconcileRoute(ctx, cr)
+			if status != v1.ResultSuccess {
+				return status, err
+			}
+
+			status, err = r.waitForRoute(ctx, cr)
+			if status != v1.ResultSuccess {
+				return status, err
+			}
+		}
+	}
+
+	// access to the prometheus and alertmanager UIs
+	status, err = r.reconcileUIAccessRole(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	// tenant scoped query frontend
+	status, err = r.reconcileQueryProxy(ctx, cr)
 	if status != v1.ResultSuccess {
 		return status, err
 	}
 
-	status, err = r.waitForRoute(ctx, cr)
+	// query endpoint of the prometheus shards
+	status, err = r.reconcileThanosQuery(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	// store gateway of the backup bucket
+	status, err = r.reconcileThanosStore(ctx, cr)
 	if status != v1.ResultSuccess {
 		return status, err
 	}
@@ -183,7 +270,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 
 func (r *Reconciler) reconcileTokenLifetimeStorage(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	configmap := model.GetPrometheusAuthTokenLifetimes(cr)
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, configmap, func() error {
+	err := utils.Apply(ctx, r.client, configmap, func() error {
 		configmap.Labels = map[string]string{
 			"managed-by": "observability-operator",
 		}
@@ -199,8 +286,12 @@ func (r *Reconciler) reconcileTokenLifetimeStorage(ctx context.Context, cr *v1.O
 
 func (r *Reconciler) reconcileServiceAccount(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	serviceAccount := model.GetPrometheusServiceAccount(cr)
+	annotations := serviceAccount.Annotations
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, serviceAccount, func() error { return nil })
+	err := utils.Apply(ctx, r.client, serviceAccount, func() error {
+		serviceAccount.Annotations = annotations
+		return nil
+	})
 
 	if err != nil {
 		return v1.ResultFailed, err
@@ -212,11 +303,20 @@ func (r *Reconciler) reconcileServiceAccount(ctx context.Context, cr *v1.Observa
 func (r *Reconciler) reconcilePrometheusProxySecret(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	secret := model.GetPrometheusProxySecret(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
-		if secret.Data == nil {
-			secret.StringData = map[string]string{
-				"session_secret": utils.GenerateRandomString(64),
-			}
+	sessionSecret, err := utils.GetOrGenerateSecretValue(ctx, r.client, secret, "session_secret", 64)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	cookieSecret, err := utils.GetOrGenerateSecretValue(ctx, r.client, secret, model.OIDCProxyCookieSecret, 32)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Data = map[string][]byte{
+			"session_secret":            sessionSecret,
+			model.OIDCProxyCookieSecret: cookieSecret,
 		}
 		return nil
 	})
@@ -231,7 +331,7 @@ func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability)
 	service := model.GetPrometheusService(cr)
 	prom := model.GetPrometheus(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
+	err := utils.Apply(ctx, r.client, service, func() error {
 		service.Annotations = map[string]string{
 			"service.alpha.openshift.io/serving-cert-secret-name": "prometheus-k8s-tls",
 		}
@@ -250,6 +350,13 @@ func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability)
 				TargetPort: intstr.FromString("web"),
 			},
 		}
+		if model.IsInternalTLSEnabled(cr) {
+			service.Spec.Ports = append(service.Spec.Ports, core.ServicePort{
+				Name:       model.InternalTLSPortName,
+				Port:       model.InternalTLSPort,
+				TargetPort: intstr.FromString(model.InternalTLSPortName),
+			})
+		}
 		return nil
 	})
 
@@ -257,13 +364,18 @@ func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability)
 		return v1.ResultFailed, err
 	}
 
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
 	return v1.ResultSuccess, nil
 }
 
 func (r *Reconciler) reconcileClusterRole(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	clusterRole := model.GetPrometheusClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, clusterRole, func() error {
+	err := utils.Apply(ctx, r.client, clusterRole, func() error {
 		clusterRole.Rules = []rbacv1.PolicyRule{
 			{
 				Verbs:     []string{"get", "list", "watch"},
@@ -304,7 +416,7 @@ func (r *Reconciler) reconcileClusterRoleBinding(ctx context.Context, cr *v1.Obs
 	clusterRoleBinding := model.GetPrometheusClusterRoleBinding(cr)
 	role := model.GetPrometheusClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, clusterRoleBinding, func() error {
+	err := utils.Apply(ctx, r.client, clusterRoleBinding, func() error {
 		clusterRoleBinding.Subjects = []rbacv1.Subject{
 			{
 				Kind:      rbacv1.ServiceAccountKind,
@@ -331,8 +443,27 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, cr *v1.Observability) (
 	route := model.GetPrometheusRoute(cr)
 	service := model.GetPrometheusService(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, route, func() error {
+	// Remove the ingress when switching back to routes
+	ingress := model.GetPrometheusIngress(cr)
+	err := r.client.Delete(ctx, ingress)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	tls, err := utils.GetUIRouteTLS(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.Apply(ctx, r.client, route, func() error {
+		// Keep the host assigned by the router unless a custom one is configured
+		host := route.Spec.Host
+		if model.GetPrometheusHost(cr) != "" {
+			host = model.GetPrometheusHost(cr)
+		}
+
 		route.Spec = routev1.RouteSpec{
+			Host: host,
 			To: routev1.RouteTargetReference{
 				Kind: "Service",
 				Name: service.Name,
@@ -341,9 +472,7 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, cr *v1.Observability) (
 				TargetPort: intstr.FromString("web"),
 			},
 			WildcardPolicy: routev1.WildcardPolicyNone,
-			TLS: &routev1.TLSConfig{
-				Termination: "reencrypt",
-			},
+			TLS:            tls,
 		}
 		return nil
 	})
@@ -355,6 +484,79 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, cr *v1.Observability) (
 	return v1.ResultSuccess, nil
 }
 
+func (r *Reconciler) reconcileIngress(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	// Remove the route when switching to ingresses
+	route := model.GetPrometheusRoute(cr)
+	err := r.client.Delete(ctx, route)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
+	ingress := model.GetPrometheusIngress(cr)
+	service := model.GetPrometheusService(cr)
+
+	err = utils.ReconcileUIIngress(ctx, r.client, cr, ingress, model.GetPrometheusHost(cr), service.Name)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// Grants the configured groups and users access to the Prometheus and Alertmanager UIs. The OAuth
+// proxies check for permission to get the services
+func (r *Reconciler) reconcileUIAccessRole(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	role := model.GetUIAccessRole(cr)
+	binding := model.GetUIAccessRoleBinding(cr)
+
+	if !cr.HasUIAccessRBAC() {
+		err := r.client.Delete(ctx, binding)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+
+		err = r.client.Delete(ctx, role)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+
+		return v1.ResultSuccess, nil
+	}
+
+	err := utils.Apply(ctx, r.client, role, func() error {
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				Verbs:     []string{"get"},
+				APIGroups: []string{""},
+				Resources: []string{"services"},
+				ResourceNames: []string{
+					model.GetPrometheusService(cr).Name,
+					model.GetAlertmanagerService(cr).Name,
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.Apply(ctx, r.client, binding, func() error {
+		binding.Subjects = model.GetUIAccessSubjects(cr)
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     role.Name,
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
 func (r *Reconciler) waitForRoute(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	route := model.GetPrometheusRoute(cr)
 	selector := client.ObjectKey{
@@ -387,7 +589,18 @@ func (r *Reconciler) fetchClusterId(ctx context.Context, cr *v1.Observability, n
 		return v1.ResultSuccess, nil
 	}
 
-	clusterId, err := utils.GetClusterId(ctx, r.client)
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Other distributions have no cluster version, the uid of kube-system identifies the cluster instead
+	var clusterId string
+	if capabilities.IsOpenShift() {
+		clusterId, err = utils.GetClusterId(ctx, r.client)
+	} else {
+		clusterId, err = utils.GetKubeSystemUID(ctx, r.client)
+	}
 	if err != nil {
 		return v1.ResultFailed, err
 	}
@@ -0,0 +1,240 @@
+package prometheus_configuration
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	core "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Query frontend of Prometheus that restricts tenants to their own metrics
+func (r *Reconciler) reconcileQueryProxy(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	if cr.Spec.QueryProxy == nil {
+		return r.deleteQueryProxy(ctx, cr)
+	}
+
+	sa := model.GetQueryProxyServiceAccount(cr)
+	err := utils.Apply(ctx, r.client, sa, func() error {
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	role := model.GetQueryProxyClusterRole(cr)
+	err = utils.Apply(ctx, r.client, role, func() error {
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				Verbs:     []string{"create"},
+				APIGroups: []string{"authentication.k8s.io"},
+				Resources: []string{"tokenreviews"},
+			},
+			{
+				Verbs:     []string{"create"},
+				APIGroups: []string{"authorization.k8s.io"},
+				Resources: []string{"subjectaccessreviews"},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	binding := model.GetQueryProxyClusterRoleBinding(cr)
+	err = utils.Apply(ctx, r.client, binding, func() error {
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     role.Name,
+		}
+		binding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	configMap := model.GetQueryProxyConfigMap(cr)
+	err = utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Data = map[string]string{
+			"config.yaml": model.GetQueryProxyConfig(cr),
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	service := model.GetQueryProxyService(cr)
+	err = utils.Apply(ctx, r.client, service, func() error {
+		// Without the OpenShift service CA kube-rbac-proxy generates a self-signed certificate
+		if capabilities.IsOpenShift() {
+			service.Annotations = map[string]string{
+				"service.alpha.openshift.io/serving-cert-secret-name": model.QueryProxyTLS,
+			}
+		}
+		service.Spec.Selector = model.GetQueryProxySelectorLabels()
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "https",
+				Port:       model.QueryProxyPort,
+				TargetPort: intstr.FromString("https"),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return r.reconcileQueryProxyDeployment(ctx, cr, capabilities.IsOpenShift())
+}
+
+func (r *Reconciler) reconcileQueryProxyDeployment(ctx context.Context, cr *v1.Observability, servingCert bool) (v1.ObservabilityStageStatus, error) {
+	deployment := model.GetQueryProxyDeployment(cr)
+	configMap := model.GetQueryProxyConfigMap(cr)
+
+	rbacProxyArgs := []string{
+		fmt.Sprintf("--secure-listen-address=%v", model.GetListenAddress(cr, model.QueryProxyPort)),
+		"--upstream=http://127.0.0.1:9095/",
+		"--config-file=/etc/kube-rbac-proxy/config.yaml",
+		"--allow-paths=/api/v1/query,/api/v1/query_range,/api/v1/series,/api/v1/labels,/api/v1/label/*",
+	}
+	rbacProxyArgs = append(rbacProxyArgs, model.GetKubeRbacProxyFIPSArgs(cr)...)
+	volumes := []core.Volume{
+		{
+			Name: "config",
+			VolumeSource: core.VolumeSource{
+				ConfigMap: &core.ConfigMapVolumeSource{
+					LocalObjectReference: core.LocalObjectReference{
+						Name: configMap.Name,
+					},
+				},
+			},
+		},
+	}
+	mounts := []core.VolumeMount{
+		{
+			Name:      "config",
+			MountPath: "/etc/kube-rbac-proxy",
+		},
+	}
+	if servingCert {
+		rbacProxyArgs = append(rbacProxyArgs,
+			"--tls-cert-file=/etc/tls/private/tls.crt",
+			"--tls-private-key-file=/etc/tls/private/tls.key")
+		volumes = append(volumes, core.Volume{
+			Name: "tls",
+			VolumeSource: core.VolumeSource{
+				Secret: &core.SecretVolumeSource{
+					SecretName: model.QueryProxyTLS,
+				},
+			},
+		})
+		mounts = append(mounts, core.VolumeMount{
+			Name:      "tls",
+			MountPath: "/etc/tls/private",
+		})
+	}
+
+	var replicas int32 = 1
+	err := utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: model.GetQueryProxySelectorLabels(),
+		}
+		deployment.Spec.Template = core.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: model.GetQueryProxySelectorLabels(),
+			},
+			Spec: core.PodSpec{
+				ServiceAccountName: model.GetQueryProxyServiceAccount(cr).Name,
+				PriorityClassName:  model.ObservabilityPriorityClassName,
+				Tolerations:        cr.Spec.Tolerations,
+				Affinity:           cr.Spec.Affinity,
+				Volumes:            volumes,
+				Containers: []core.Container{
+					{
+						Name:  "kube-rbac-proxy",
+						Image: model.GetImage(cr, v1.ImageKubeRbacProxy, model.KubeRbacProxyImage),
+						Args:  rbacProxyArgs,
+						Ports: []core.ContainerPort{
+							{
+								Name:          "https",
+								ContainerPort: model.QueryProxyPort,
+							},
+						},
+						VolumeMounts: mounts,
+					},
+					{
+						Name:  "prom-label-proxy",
+						Image: model.GetImage(cr, v1.ImagePromLabelProxy, model.PromLabelProxyImage),
+						Args: []string{
+							"--insecure-listen-address=127.0.0.1:9095",
+							fmt.Sprintf("--upstream=%s", model.GetPrometheusQueryUrl(cr)),
+							fmt.Sprintf("--label=%s", model.GetQueryProxyTenantLabel(cr)),
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) deleteQueryProxy(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	// Nothing to do if the proxy was never enabled
+	deployment := model.GetQueryProxyDeployment(cr)
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: deployment.Namespace, Name: deployment.Name}, deployment)
+	if errors.IsNotFound(err) {
+		return v1.ResultSuccess, nil
+	}
+
+	objects := []runtime.Object{
+		deployment,
+		model.GetQueryProxyService(cr),
+		model.GetQueryProxyConfigMap(cr),
+		model.GetQueryProxyClusterRoleBinding(cr),
+		model.GetQueryProxyClusterRole(cr),
+		model.GetQueryProxyServiceAccount(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
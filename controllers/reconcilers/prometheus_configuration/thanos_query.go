@@ -0,0 +1,138 @@
+package prometheus_configuration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Single query endpoint over all Prometheus shards and the backup bucket. The Thanos sidecars
+// of the shards are discovered through the grpc port of the prometheus-operated service
+func (r *Reconciler) reconcileThanosQuery(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	if !model.IsThanosEnabled(cr) {
+		return r.deleteThanosQuery(ctx, cr)
+	}
+
+	args := []string{
+		"query",
+		fmt.Sprintf("--http-address=%v", model.GetListenAddress(cr, model.ThanosQueryPort)),
+		fmt.Sprintf("--store=dnssrv+_grpc._tcp.prometheus-operated.%s.svc.cluster.local", cr.Namespace),
+		"--query.replica-label=prometheus_replica",
+	}
+	if cr.BackupEnabled() {
+		args = append(args, fmt.Sprintf("--store=dnssrv+_grpc._tcp.%s.%s.svc.cluster.local", model.ThanosStoreName, cr.Namespace))
+	}
+
+	service := model.GetThanosQueryService(cr)
+	err := utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = model.GetThanosQuerySelectorLabels()
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "http",
+				Port:       model.ThanosQueryPort,
+				TargetPort: intstr.FromString("http"),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	deployment := model.GetThanosQueryDeployment(cr)
+	var replicas int32 = 1
+	err = utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: model.GetThanosQuerySelectorLabels(),
+		}
+		deployment.Spec.Template = core.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: model.GetThanosQuerySelectorLabels(),
+			},
+			Spec: core.PodSpec{
+				PriorityClassName: model.ObservabilityPriorityClassName,
+				Tolerations:       cr.Spec.Tolerations,
+				Affinity:          cr.Spec.Affinity,
+				Containers: []core.Container{
+					{
+						Name:  "thanos-query",
+						Image: model.GetImage(cr, v1.ImageThanos, model.ThanosImage),
+						Args:  args,
+						Ports: []core.ContainerPort{
+							{
+								Name:          "http",
+								ContainerPort: model.ThanosQueryPort,
+							},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) deleteThanosQuery(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	objects := []runtime.Object{
+		model.GetThanosQueryDeployment(cr),
+		model.GetThanosQueryService(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// Shards other than the first are labelled with their number
+func (r *Reconciler) deletePrometheusShards(ctx context.Context, cr *v1.Observability) error {
+	list := &prometheusv1.PrometheusList{}
+	opts := &client.ListOptions{
+		Namespace: cr.Namespace,
+	}
+	err := r.client.List(ctx, list, opts)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	prometheus := model.GetPrometheus(cr)
+	for _, shard := range list.Items {
+		if _, ok := shard.Labels[model.PrometheusShardLabel]; !ok || !strings.HasPrefix(shard.Name, prometheus.Name) {
+			continue
+		}
+		err = r.client.Delete(ctx, shard)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
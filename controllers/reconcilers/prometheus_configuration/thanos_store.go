@@ -0,0 +1,176 @@
+package prometheus_configuration
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Serves the TSDB blocks uploaded to the backup bucket to Thanos Query, so that the metrics
+// history survives the loss of the Prometheus volumes
+func (r *Reconciler) reconcileThanosStore(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	if !cr.BackupEnabled() {
+		return r.deleteThanosStore(ctx, cr)
+	}
+
+	status, err := r.reconcileObjectStorageCredentials(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	service := model.GetThanosStoreService(cr)
+	err = utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = model.GetThanosStoreSelectorLabels()
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "grpc",
+				Port:       model.ThanosStoreGrpcPort,
+				TargetPort: intstr.FromString("grpc"),
+			},
+			{
+				Name:       "http",
+				Port:       model.ThanosStoreHttpPort,
+				TargetPort: intstr.FromString("http"),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	deployment := model.GetThanosStoreDeployment(cr)
+	var replicas int32 = 1
+	err = utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: model.GetThanosStoreSelectorLabels(),
+		}
+		deployment.Spec.Template = core.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: model.GetThanosStoreSelectorLabels(),
+			},
+			Spec: core.PodSpec{
+				// Shares the object storage identity of the Thanos sidecars
+				ServiceAccountName: model.GetPrometheusServiceAccount(cr).Name,
+				PriorityClassName:  model.ObservabilityPriorityClassName,
+				Tolerations:        cr.Spec.Tolerations,
+				Affinity:           cr.Spec.Affinity,
+				Containers: []core.Container{
+					{
+						Name:  "thanos-store",
+						Image: model.GetImage(cr, v1.ImageThanos, model.ThanosImage),
+						Args: []string{
+							"store",
+							"--data-dir=/var/thanos/store",
+							fmt.Sprintf("--objstore.config-file=/etc/thanos/%s", model.BackupObjectStorageKey),
+							fmt.Sprintf("--grpc-address=%v", model.GetListenAddress(cr, model.ThanosStoreGrpcPort)),
+							fmt.Sprintf("--http-address=%v", model.GetListenAddress(cr, model.ThanosStoreHttpPort)),
+						},
+						Env: model.GetObjectStorageIdentityEnv(cr),
+						Ports: []core.ContainerPort{
+							{
+								Name:          "grpc",
+								ContainerPort: model.ThanosStoreGrpcPort,
+							},
+							{
+								Name:          "http",
+								ContainerPort: model.ThanosStoreHttpPort,
+							},
+						},
+						VolumeMounts: append([]core.VolumeMount{
+							{
+								Name:      "data",
+								MountPath: "/var/thanos/store",
+							},
+							{
+								Name:      "objstore",
+								MountPath: "/etc/thanos",
+								ReadOnly:  true,
+							},
+						}, model.GetObjectStorageIdentityVolumeMounts(cr)...),
+					},
+				},
+				Volumes: append([]core.Volume{
+					{
+						Name: "data",
+						VolumeSource: core.VolumeSource{
+							EmptyDir: &core.EmptyDirVolumeSource{},
+						},
+					},
+					{
+						Name: "objstore",
+						VolumeSource: core.VolumeSource{
+							Secret: &core.SecretVolumeSource{
+								SecretName: cr.Spec.Backup.ObjectStorageSecret,
+							},
+						},
+					},
+				}, model.GetObjectStorageIdentityVolumes(cr)...),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// The credential configuration is only needed for Workload Identity Federation on Google Cloud
+func (r *Reconciler) reconcileObjectStorageCredentials(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	configMap := model.GetObjectStorageCredentialsConfigMap(cr)
+	identity := model.GetObjectStorageIdentity(cr)
+	if identity == nil || identity.Provider != v1.ObjectStorageIdentityGCP {
+		err := r.client.Delete(ctx, configMap)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+		return v1.ResultSuccess, nil
+	}
+
+	credentials, err := model.GetObjectStorageGCPCredentials(identity)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	err = utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Data = map[string]string{
+			model.ObjectStorageCredentialsKey: credentials,
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) deleteThanosStore(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	objects := []runtime.Object{
+		model.GetThanosStoreDeployment(cr),
+		model.GetThanosStoreService(cr),
+		model.GetObjectStorageCredentialsConfigMap(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
@@ -5,35 +5,49 @@ import (
 	"github.com/go-logr/logr"
 	coreosv1 "github.com/operator-framework/api/pkg/operators/v1"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	errors2 "github.com/pkg/errors"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/csv"
 	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
-	v12 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type Reconciler struct {
-	client client.Client
-	logger logr.Logger
-	scheme *runtime.Scheme
+	client   client.Client
+	logger   logr.Logger
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 }
 
-func NewReconciler(client client.Client, logger logr.Logger, scheme *runtime.Scheme) reconcilers.ObservabilityReconciler {
+func NewReconciler(client client.Client, logger logr.Logger, scheme *runtime.Scheme, recorder record.EventRecorder) reconcilers.ObservabilityReconciler {
 	return &Reconciler{
-		client: client,
-		logger: logger,
-		scheme: scheme,
+		client:   client,
+		logger:   logger,
+		scheme:   scheme,
+		recorder: recorder,
 	}
 }
 
 func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	// Operators that were not installed through OLM are not managed by us
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if !capabilities.OLM {
+		return v1.ResultSuccess, nil
+	}
+
 	// Delete subscription
 	subscription := model.GetPrometheusSubscription(cr)
-	err := r.client.Delete(ctx, subscription)
+	err = r.client.Delete(ctx, subscription)
 	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
@@ -53,36 +67,38 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 	}
 
 	// We have to remove the prometheus operator deployment manually
-	deployments := &v12.DeploymentList{}
-	opts := &client.ListOptions{
-		Namespace: cr.Namespace,
-	}
-	err = r.client.List(ctx, deployments, opts)
-	if err != nil {
+	err = r.client.Delete(ctx, model.GetPrometheusOperatorDeployment(cr))
+	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
 
-	for _, deployment := range deployments.Items {
-		if deployment.Name == "prometheus-operator" {
-			err = r.client.Delete(ctx, &deployment)
-			if err != nil && !errors.IsNotFound(err) {
-				return v1.ResultFailed, err
-			}
-		}
-	}
-
 	return v1.ResultSuccess, nil
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// Without OLM the Prometheus operator has to be installed beforehand
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if !capabilities.OLM {
+		if capabilities.PrometheusOperator {
+			return v1.ResultSuccess, nil
+		}
+		return v1.ResultFailed, errors2.New("OLM is not available and the prometheus operator is not installed")
+	}
+
 	// Catalog source
 	status, err := r.reconcileCatalogSource(ctx, cr)
 	if status != v1.ResultSuccess {
 		return status, err
 	}
 
+	// Report upgrades that the subscription watchdog refused to approve
+	setUpgradeBlockedCondition(cr, s)
+
 	// Prometheus subscription
-	status, err = r.reconcileSubscription(ctx, cr)
+	status, err = r.reconcileSubscription(ctx, cr, s)
 	if status != v1.ResultSuccess {
 		return status, err
 	}
@@ -94,6 +110,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 	}
 
 	status, err = r.waitForPrometheusOperator(ctx, cr)
+	if status == v1.ResultInProgress {
+		// The operator is not ready yet, check if the install is stuck
+		err = csv.CheckSubscription(ctx, r.client, r.logger, r.recorder, cr, s, model.GetPrometheusSubscription(cr).Name, model.CheckPrometheusOperatorUpgrade)
+	}
 	if status != v1.ResultSuccess {
 		return status, err
 	}
@@ -101,23 +121,38 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 	return v1.ResultSuccess, nil
 }
 
+func setUpgradeBlockedCondition(cr *v1.Observability, s *v1.ObservabilityStatus) {
+	status := s.GetSubscriptionStatus(model.GetPrometheusSubscription(cr).Name)
+	if status != nil && status.State == v1.SubscriptionBlocked {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.PrometheusOperatorUpgradeBlocked,
+			Status:  metav1.ConditionTrue,
+			Reason:  "UnsupportedUpgrade",
+			Message: status.Message,
+		})
+		return
+	}
+
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:    v1.PrometheusOperatorUpgradeBlocked,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NoBlockedUpgrade",
+		Message: "no pending upgrade is blocked",
+	})
+}
+
 func (r *Reconciler) waitForPrometheusOperator(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
-	// We have to remove the prometheus operator deployment manually
-	deployments := &v12.DeploymentList{}
-	opts := &client.ListOptions{
-		Namespace: cr.Namespace,
+	deployment := model.GetPrometheusOperatorDeployment(cr)
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: deployment.Namespace, Name: deployment.Name}, deployment)
+	if errors.IsNotFound(err) {
+		return v1.ResultInProgress, nil
 	}
-	err := r.client.List(ctx, deployments, opts)
 	if err != nil {
 		return v1.ResultFailed, err
 	}
 
-	for _, deployment := range deployments.Items {
-		if deployment.Name == "prometheus-operator" {
-			if deployment.Status.ReadyReplicas > 0 {
-				return v1.ResultSuccess, nil
-			}
-		}
+	if deployment.Status.ReadyReplicas > 0 {
+		return v1.ResultSuccess, nil
 	}
 	return v1.ResultInProgress, nil
 }
@@ -125,10 +160,10 @@ func (r *Reconciler) waitForPrometheusOperator(ctx context.Context, cr *v1.Obser
 func (r *Reconciler) reconcileCatalogSource(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	source := model.GetPrometheusCatalogSource(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, source, func() error {
+	err := utils.Apply(ctx, r.client, source, func() error {
 		source.Spec = v1alpha1.CatalogSourceSpec{
 			SourceType: v1alpha1.SourceTypeGrpc,
-			Image:      "quay.io/integreatly/custom-prometheus-index:1.0.0",
+			Image:      model.GetImage(cr, v1.ImagePrometheusCatalogIndex, model.PrometheusCatalogIndexImage),
 		}
 		return nil
 	})
@@ -140,18 +175,20 @@ func (r *Reconciler) reconcileCatalogSource(ctx context.Context, cr *v1.Observab
 	return v1.ResultSuccess, nil
 }
 
-func (r *Reconciler) reconcileSubscription(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+func (r *Reconciler) reconcileSubscription(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
 	subscription := model.GetPrometheusSubscription(cr)
 	source := model.GetPrometheusCatalogSource(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, subscription, func() error {
+	err := utils.Apply(ctx, r.client, subscription, func() error {
 		subscription.Spec = &v1alpha1.SubscriptionSpec{
 			CatalogSource:          source.Name,
 			CatalogSourceNamespace: cr.Namespace,
 			Package:                "prometheus",
 			Channel:                "preview",
-			InstallPlanApproval:    v1alpha1.ApprovalAutomatic,
-			Config:                 v1alpha1.SubscriptionConfig{Resources: model.GetPrometheusOperatorResourceRequirement(cr)},
+			// Install plans are always approved by the operator, after checking the version matrix
+			InstallPlanApproval: v1alpha1.ApprovalManual,
+			StartingCSV:         model.GetSubscriptionStartingCSV(s, subscription.Name, ""),
+			Config:              v1alpha1.SubscriptionConfig{Resources: model.GetPrometheusOperatorResourceRequirement(cr)},
 		}
 
 		return nil
@@ -176,7 +213,7 @@ func (r *Reconciler) reconcileOperatorgroup(ctx context.Context, cr *v1.Observab
 
 	operatorgroup := model.GetPrometheusOperatorgroup(cr)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, operatorgroup, func() error {
+	err = utils.Apply(ctx, r.client, operatorgroup, func() error {
 		operatorgroup.Spec = coreosv1.OperatorGroupSpec{
 			TargetNamespaces: []string{cr.Namespace},
 		}
@@ -6,10 +6,12 @@ import (
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/model"
 	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type Reconciler struct {
@@ -49,6 +51,12 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 		return v1.ResultFailed, err
 	}
 
+	claim := model.GetPromtailBufferClaim(cr)
+	err = r.client.Delete(ctx, claim)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
 	return v1.ResultSuccess, nil
 }
 
@@ -74,13 +82,59 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return status, err
 	}
 
+	status, err = r.reconcilePromtailBufferClaim(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// The claim of the buffering is shared by the Promtail pods of all nodes. It is removed when the
+// buffering moves to the nodes or is turned off
+func (r *Reconciler) reconcilePromtailBufferClaim(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	claim := model.GetPromtailBufferClaim(cr)
+
+	buffering := model.GetPromtailBuffering(cr)
+	if buffering == nil || buffering.Storage != v1.LogBufferingPersistentVolumeClaim {
+		err := r.client.Delete(ctx, claim)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+		return v1.ResultSuccess, nil
+	}
+
+	size, err := resource.ParseQuantity(buffering.Size)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Volumes can grow but not shrink, and the other fields of the spec are immutable
+	err = utils.Apply(ctx, r.client, claim, func() error {
+		if claim.CreationTimestamp.IsZero() {
+			claim.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+			claim.Spec.StorageClassName = buffering.StorageClass
+		}
+		current := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+		if current.Cmp(size) < 0 {
+			claim.Spec.Resources.Requests = corev1.ResourceList{
+				corev1.ResourceStorage: size,
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
 	return v1.ResultSuccess, nil
 }
 
 func (r *Reconciler) reconcilePromtailServiceAccount(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	sa := model.GetPromtailServiceAccount(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, sa, func() error {
+	err := utils.Apply(ctx, r.client, sa, func() error {
 		return nil
 	})
 
@@ -94,7 +148,7 @@ func (r *Reconciler) reconcilePromtailServiceAccount(ctx context.Context, cr *v1
 func (r *Reconciler) reconcilePromtailClusterRole(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	role := model.GetPromtailClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, role, func() error {
+	err := utils.Apply(ctx, r.client, role, func() error {
 		role.Rules = []rbacv1.PolicyRule{
 			{
 				Verbs:     []string{"get", "list", "watch"},
@@ -129,7 +183,7 @@ func (r *Reconciler) reconcilePromtailClusterRoleBinding(ctx context.Context, cr
 	sa := model.GetPromtailServiceAccount(cr)
 	role := model.GetPromtailClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, rolebinding, func() error {
+	err := utils.Apply(ctx, r.client, rolebinding, func() error {
 		rolebinding.RoleRef = rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
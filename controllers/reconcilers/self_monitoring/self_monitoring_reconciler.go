@@ -0,0 +1,177 @@
+package self_monitoring
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	AlertPrometheusDown                 = "PrometheusDown"
+	AlertAlertmanagerConfigReloadFailed = "AlertmanagerConfigReloadFailed"
+	AlertGrafanaDatasourceBroken        = "GrafanaDatasourceBroken"
+	AlertPromtailDroppingLogs           = "PromtailDroppingLogs"
+	AlertTokenRefresherErrors           = "TokenRefresherErrors"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	err := r.client.Delete(ctx, model.GetSelfMonitoringRule(cr))
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+	return v1.ResultSuccess, nil
+}
+
+// Keeps the alerts on the health of the stack in line with the enabled components and their versions.
+// The components are scraped through the additional scrape config of the configuration stage
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	if !cr.SelfMonitoringAlertsEnabled() {
+		return r.Cleanup(ctx, cr)
+	}
+
+	served, err := utils.RequireAPI(ctx, r.client, cr, s, utils.APIPrometheusRule)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if !served {
+		return v1.ResultSuccess, nil
+	}
+
+	// The rule selector of Prometheus is set by the configuration stage, from the CR or the repository index
+	prometheus := model.GetPrometheus(cr)
+	err = r.client.Get(ctx, client.ObjectKey{Namespace: prometheus.Namespace, Name: prometheus.Name}, prometheus)
+	if errors.IsNotFound(err) {
+		return v1.ResultInProgress, nil
+	}
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	var groups []prometheusv1.RuleGroup
+	for _, component := range model.GetSelfMonitoringComponents(cr) {
+		groups = append(groups, prometheusv1.RuleGroup{
+			Name:  model.GetSelfMonitoringJob(component.Name),
+			Rules: getComponentRules(cr, component.Name),
+		})
+	}
+
+	rule := model.GetSelfMonitoringRule(cr)
+	err = utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = map[string]string{}
+		if prometheus.Spec.RuleSelector != nil {
+			for k, v := range prometheus.Spec.RuleSelector.MatchLabels {
+				rule.Labels[k] = v
+			}
+		}
+		rule.Annotations = map[string]string{
+			model.SelfMonitoringVersionsAnnotation: fmt.Sprintf("prometheus=%v,alertmanager=%v,promtail=%v",
+				model.GetPrometheusVersion(cr), model.GetAlertmanagerVersion(cr), model.GetPromtailVersion(cr)),
+		}
+		rule.Spec.Groups = groups
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func getComponentRules(cr *v1.Observability, component string) []prometheusv1.Rule {
+	job := model.GetSelfMonitoringJob(component)
+	warning := map[string]string{
+		"severity": "warning",
+	}
+
+	switch component {
+	case "prometheus":
+		// A Prometheus that is down can't alert on itself, the DeadMansSwitch stops in that case. Shards
+		// alert on each other
+		return []prometheusv1.Rule{
+			{
+				Alert: AlertPrometheusDown,
+				Expr:  intstr.FromString(fmt.Sprintf(`up{job="%s"} == 0`, job)),
+				For:   "5m",
+				Labels: map[string]string{
+					"severity": "critical",
+				},
+				Annotations: map[string]string{
+					"message": "Prometheus {{ $labels.pod }} is down.",
+				},
+			},
+		}
+	case "alertmanager":
+		return []prometheusv1.Rule{
+			{
+				Alert:  AlertAlertmanagerConfigReloadFailed,
+				Expr:   intstr.FromString(fmt.Sprintf(`max_over_time(alertmanager_config_last_reload_successful{job="%s"}[5m]) == 0`, job)),
+				For:    "10m",
+				Labels: warning,
+				Annotations: map[string]string{
+					"message": "Alertmanager {{ $labels.pod }} failed to load its configuration, it still runs the last valid one.",
+				},
+			},
+		}
+	case "grafana":
+		return []prometheusv1.Rule{
+			{
+				Alert: AlertGrafanaDatasourceBroken,
+				Expr: intstr.FromString(fmt.Sprintf(`sum by (datasource) (rate(grafana_datasource_request_total{job="%s",code!~"2.."}[5m]))
+/ sum by (datasource) (rate(grafana_datasource_request_total{job="%s"}[5m])) > 0.5`, job, job)),
+				For:    "15m",
+				Labels: warning,
+				Annotations: map[string]string{
+					"message": "More than half of the queries of Grafana to datasource {{ $labels.datasource }} fail.",
+				},
+			},
+		}
+	case "promtail":
+		return []prometheusv1.Rule{
+			{
+				Alert:  AlertPromtailDroppingLogs,
+				Expr:   intstr.FromString(model.GetPromtailDroppedEntriesExpr(model.GetPromtailVersion(cr)) + " > 0"),
+				For:    "15m",
+				Labels: warning,
+				Annotations: map[string]string{
+					"message": "Promtail {{ $labels.pod }} fails to ship log entries.",
+				},
+			},
+		}
+	case "token-refresher":
+		return []prometheusv1.Rule{
+			{
+				Alert:  AlertTokenRefresherErrors,
+				Expr:   intstr.FromString(fmt.Sprintf(`sum by (pod) (rate(http_requests_total{job="%s",code=~"5.."}[5m])) > 0`, job)),
+				For:    "15m",
+				Labels: warning,
+				Annotations: map[string]string{
+					"message": "Token refresher {{ $labels.pod }} fails to forward requests to Observatorium.",
+				},
+			},
+		}
+	default:
+		return nil
+	}
+}
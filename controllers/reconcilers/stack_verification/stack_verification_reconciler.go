@@ -0,0 +1,272 @@
+package stack_verification
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Age after which the synthetic metric or the samples sent by a remote write queue count as stale
+const MaxAge = 2 * time.Minute
+
+type Reconciler struct {
+	client     client.Client
+	logger     logr.Logger
+	httpClient *http.Client
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	httpClient := &http.Client{Transport: tr, Timeout: 30 * time.Second}
+
+	return &Reconciler{
+		client:     client,
+		logger:     logger,
+		httpClient: httpClient,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	return v1.ResultSuccess, nil
+}
+
+// Like the tenant verification, a failed verification is only reported in the status of the CR and
+// does not block the installation
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	if !cr.StackVerificationEnabled() {
+		meta.RemoveStatusCondition(&s.Conditions, v1.StackVerified)
+		s.StackVerification = nil
+		return v1.ResultSuccess, nil
+	}
+
+	if s.StackVerification != nil && time.Since(time.Unix(s.StackVerification.LastRun, 0)) < model.GetStackVerificationInterval(cr) {
+		return v1.ResultSuccess, nil
+	}
+
+	previous := map[string]v1.StackVerificationCheck{}
+	if s.StackVerification != nil {
+		for _, check := range s.StackVerification.Checks {
+			previous[check.Name] = check
+		}
+	}
+
+	now := time.Now()
+	verification := &v1.StackVerificationStatus{
+		LastRun: now.Unix(),
+	}
+	var failed []string
+	for _, check := range []struct {
+		name   string
+		verify func() (string, string)
+	}{
+		{v1.VerificationCheckRemoteWrite, func() (string, string) { return r.verifyRemoteWrite(cr) }},
+		{v1.VerificationCheckAlerting, func() (string, string) { return r.verifyAlerting(cr) }},
+		{v1.VerificationCheckGrafana, func() (string, string) { return r.verifyGrafanaDatasources(ctx, cr) }},
+	} {
+		result, message := check.verify()
+		status := v1.StackVerificationCheck{
+			Name:       check.name,
+			Result:     result,
+			Message:    message,
+			LastPassed: previous[check.name].LastPassed,
+		}
+		if result == v1.VerificationPassed {
+			status.LastPassed = now.Unix()
+		}
+		if result == v1.VerificationFailed {
+			failed = append(failed, fmt.Sprintf("%v: %v", check.name, message))
+		}
+		verification.Checks = append(verification.Checks, status)
+	}
+	s.StackVerification = verification
+
+	if len(failed) > 0 {
+		r.logger.Info("stack verification failed", "checks", failed)
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.StackVerified,
+			Status:  metav1.ConditionFalse,
+			Reason:  "VerificationFailed",
+			Message: strings.Join(failed, ", "),
+		})
+		return v1.ResultSuccess, nil
+	}
+
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:    v1.StackVerified,
+		Status:  metav1.ConditionTrue,
+		Reason:  "StackVerified",
+		Message: "all checks of the stack verification passed",
+	})
+	return v1.ResultSuccess, nil
+}
+
+// The synthetic metric is recorded every 30s, so every remote write queue that works has sent a
+// sample within the last two minutes
+func (r *Reconciler) verifyRemoteWrite(cr *v1.Observability) (string, string) {
+	if cr.PrometheusMode() != v1.ComponentManaged {
+		return v1.VerificationSkipped, "prometheus is not managed"
+	}
+
+	maxAge := MaxAge.Seconds()
+	if !cr.PrometheusAgentEnabled() {
+		recorded, err := configuration.QueryVector(r.httpClient, cr, fmt.Sprintf("time() - max(%v)", model.StackVerificationRecord), "")
+		if err != nil {
+			return v1.VerificationFailed, fmt.Sprintf("error querying prometheus: %v", err)
+		}
+		age, ok := recorded[""]
+		if !ok {
+			return v1.VerificationFailed, "the synthetic metric is not recorded yet"
+		}
+		if age > maxAge {
+			return v1.VerificationFailed, fmt.Sprintf("the synthetic metric was last recorded %.0fs ago", age)
+		}
+	}
+
+	queues, err := configuration.QueryVector(r.httpClient, cr, "time() - prometheus_remote_storage_queue_highest_sent_timestamp_seconds", "url")
+	if err != nil {
+		return v1.VerificationFailed, fmt.Sprintf("error querying prometheus: %v", err)
+	}
+	if len(queues) == 0 {
+		return v1.VerificationSkipped, "prometheus has no remote write endpoints"
+	}
+
+	var stale []string
+	for endpoint, age := range queues {
+		if age > maxAge {
+			stale = append(stale, fmt.Sprintf("%v %.0fs ago", endpoint, age))
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return v1.VerificationFailed, fmt.Sprintf("samples last sent to %v", strings.Join(stale, ", "))
+	}
+	return v1.VerificationPassed, ""
+}
+
+// Sends the test alert to Alertmanager and checks that it is routed to the receiver without
+// notifications. The alert resolves on its own after a few minutes
+func (r *Reconciler) verifyAlerting(cr *v1.Observability) (string, string) {
+	if cr.AlertmanagerMode() != v1.ComponentManaged {
+		return v1.VerificationSkipped, "alertmanager is not managed"
+	}
+
+	// Like the config verification, alerts are sent with the service account token
+	token, err := ioutil.ReadFile(configuration.ServiceAccountTokenPath)
+	if err != nil {
+		return v1.VerificationSkipped, "no service account token found"
+	}
+	service := model.GetAlertmanagerService(cr)
+	baseUrl := fmt.Sprintf("https://%v.%v.svc:9091", service.Name, cr.Namespace)
+
+	now := time.Now().UTC()
+	payload, err := json.Marshal([]map[string]interface{}{
+		{
+			"labels": map[string]string{
+				"alertname": model.StackVerificationAlert,
+				"namespace": cr.Namespace,
+			},
+			"annotations": map[string]string{
+				"message": "Test alert of the stack verification, it is not notified.",
+			},
+			"startsAt": now.Format(time.RFC3339),
+			"endsAt":   now.Add(5 * time.Minute).Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return v1.VerificationFailed, err.Error()
+	}
+
+	body, code, err := configuration.AlertmanagerRequest(r.httpClient, http.MethodPost, fmt.Sprintf("%v/api/v2/alerts", baseUrl), string(token), payload)
+	if err != nil {
+		return v1.VerificationFailed, fmt.Sprintf("error sending the test alert: %v", err)
+	}
+	if code != http.StatusOK {
+		return v1.VerificationFailed, fmt.Sprintf("alertmanager rejected the test alert: %v", strings.TrimSpace(string(body)))
+	}
+
+	filter := url.Values{"filter": []string{fmt.Sprintf(`alertname="%v"`, model.StackVerificationAlert)}}
+	body, code, err = configuration.AlertmanagerRequest(r.httpClient, http.MethodGet, fmt.Sprintf("%v/api/v2/alerts?%v", baseUrl, filter.Encode()), string(token), nil)
+	if err != nil {
+		return v1.VerificationFailed, fmt.Sprintf("error reading the test alert: %v", err)
+	}
+	if code != http.StatusOK {
+		return v1.VerificationFailed, fmt.Sprintf("unexpected status code from alertmanager: %v", code)
+	}
+
+	var alerts []struct {
+		Receivers []struct {
+			Name string `json:"name"`
+		} `json:"receivers"`
+	}
+	err = json.Unmarshal(body, &alerts)
+	if err != nil {
+		return v1.VerificationFailed, fmt.Sprintf("invalid alerts response: %v", err)
+	}
+	if len(alerts) == 0 {
+		return v1.VerificationFailed, "the test alert was accepted but is not active"
+	}
+
+	var receivers []string
+	for _, receiver := range alerts[0].Receivers {
+		if receiver.Name == model.StackVerificationReceiver {
+			return v1.VerificationPassed, ""
+		}
+		receivers = append(receivers, receiver.Name)
+	}
+	return v1.VerificationFailed, fmt.Sprintf("the test alert was routed to %v, the config is not loaded yet", strings.Join(receivers, ", "))
+}
+
+// Queries every Prometheus, Loki and Tempo datasource through the Grafana proxy
+func (r *Reconciler) verifyGrafanaDatasources(ctx context.Context, cr *v1.Observability) (string, string) {
+	if cr.GrafanaMode() != v1.ComponentManaged && !cr.GrafanaExternal() {
+		return v1.VerificationSkipped, "grafana is not managed"
+	}
+
+	grafana, err := configuration.NewGrafanaClient(ctx, r.client, r.httpClient, cr)
+	if err != nil {
+		return v1.VerificationFailed, fmt.Sprintf("error connecting to grafana: %v", err)
+	}
+
+	datasources, err := grafana.ListDatasources()
+	if err != nil {
+		return v1.VerificationFailed, fmt.Sprintf("error listing the datasources: %v", err)
+	}
+
+	checked := 0
+	var failed []string
+	for _, datasource := range datasources {
+		ok, err := grafana.CheckDatasource(datasource)
+		if ok {
+			checked++
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%v: %v", datasource.Name, err))
+		}
+	}
+
+	if checked == 0 {
+		return v1.VerificationSkipped, "grafana has no prometheus, loki or tempo datasources"
+	}
+	if len(failed) > 0 {
+		return v1.VerificationFailed, strings.Join(failed, ", ")
+	}
+	return v1.VerificationPassed, ""
+}
@@ -0,0 +1,403 @@
+package tempo_installation
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	datasource := model.GetTempoDatasource(cr)
+	err := r.client.Delete(ctx, datasource)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
+	stack := model.GetTempoStack(cr)
+	err = r.client.Delete(ctx, stack)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
+	statefulSet := model.GetTempoStatefulSet(cr)
+	err = r.client.Delete(ctx, statefulSet)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	service := model.GetTempoService(cr)
+	err = r.client.Delete(ctx, service)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	configMap := model.GetTempoConfigMap(cr)
+	err = r.client.Delete(ctx, configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	sa := model.GetTempoServiceAccount(cr)
+	err = r.client.Delete(ctx, sa)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	// Tracing is opt-in
+	if !cr.TracingEnabled() {
+		return v1.ResultSuccess, nil
+	}
+
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	var status v1.ObservabilityStageStatus
+	if capabilities.TempoOperator {
+		status, err = r.reconcileTempoStack(ctx, cr)
+	} else {
+		status, err = r.reconcileMonolithicTempo(ctx, cr)
+	}
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	// Without Grafana the Grafana operator CRDs may not exist
+	if cr.GrafanaMode() == v1.ComponentDisabled {
+		return v1.ResultSuccess, nil
+	}
+
+	return r.reconcileTempoDatasource(ctx, cr, capabilities.TempoOperator)
+}
+
+func (r *Reconciler) reconcileMonolithicTempo(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	status, err := r.reconcileTempoServiceAccount(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	status, err = r.reconcileTempoConfigMap(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	status, err = r.reconcileTempoService(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	status, err = r.reconcileTempoStatefulSet(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
+	return r.waitForTempo(ctx, cr)
+}
+
+func (r *Reconciler) reconcileTempoServiceAccount(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	sa := model.GetTempoServiceAccount(cr)
+
+	err := utils.Apply(ctx, r.client, sa, func() error {
+		return nil
+	})
+
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileTempoConfigMap(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	configMap := model.GetTempoConfigMap(cr)
+
+	err := utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Data = map[string]string{
+			"tempo.yaml": model.GetTempoConfig(cr),
+		}
+		return nil
+	})
+
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileTempoService(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	service := model.GetTempoService(cr)
+
+	err := utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = model.GetTempoSelectorLabels()
+		service.Spec.Ports = []v12.ServicePort{
+			{
+				Name:       "http",
+				Port:       model.TempoHttpPort,
+				TargetPort: intstr.FromString("http"),
+			},
+			{
+				Name:       "otlp-grpc",
+				Port:       4317,
+				TargetPort: intstr.FromString("otlp-grpc"),
+			},
+			{
+				Name:       "otlp-http",
+				Port:       4318,
+				TargetPort: intstr.FromString("otlp-http"),
+			},
+			{
+				Name:       "jaeger-grpc",
+				Port:       14250,
+				TargetPort: intstr.FromString("jaeger-grpc"),
+			},
+			{
+				Name:       "jaeger-thrift",
+				Port:       14268,
+				TargetPort: intstr.FromString("jaeger-thrift"),
+			},
+		}
+		return nil
+	})
+
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = utils.ReconcileServiceIPFamilies(ctx, r.client, cr, service)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileTempoStatefulSet(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	statefulSet := model.GetTempoStatefulSet(cr)
+	sa := model.GetTempoServiceAccount(cr)
+	configMap := model.GetTempoConfigMap(cr)
+
+	size, err := resource.ParseQuantity(model.GetTempoStorageSize(cr))
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	existing := model.GetTempoStatefulSet(cr)
+	err = r.client.Get(ctx, client.ObjectKey{Namespace: existing.Namespace, Name: existing.Name}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	var replicas int32 = 1
+	err = utils.Apply(ctx, r.client, statefulSet, func() error {
+		statefulSet.Spec.Replicas = &replicas
+		statefulSet.Spec.ServiceName = model.GetTempoService(cr).Name
+		statefulSet.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: model.GetTempoSelectorLabels(),
+		}
+
+		// Volume claim templates cannot be changed after the stateful set was created, the existing
+		// templates are applied again as they are
+		statefulSet.Spec.VolumeClaimTemplates = existing.Spec.VolumeClaimTemplates
+		if existing.CreationTimestamp.IsZero() {
+			statefulSet.Spec.VolumeClaimTemplates = []v12.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "data",
+					},
+					Spec: v12.PersistentVolumeClaimSpec{
+						AccessModes:      []v12.PersistentVolumeAccessMode{v12.ReadWriteOnce},
+						StorageClassName: cr.Spec.Tracing.Storage.StorageClass,
+						Resources: v12.ResourceRequirements{
+							Requests: v12.ResourceList{
+								v12.ResourceStorage: size,
+							},
+						},
+					},
+				},
+			}
+		}
+
+		statefulSet.Spec.Template = v12.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: model.GetTempoSelectorLabels(),
+			},
+			Spec: v12.PodSpec{
+				ServiceAccountName: sa.Name,
+				PriorityClassName:  model.ObservabilityPriorityClassName,
+				Tolerations:        cr.Spec.Tolerations,
+				Affinity:           cr.Spec.Affinity,
+				Volumes: []v12.Volume{
+					{
+						Name: "config",
+						VolumeSource: v12.VolumeSource{
+							ConfigMap: &v12.ConfigMapVolumeSource{
+								LocalObjectReference: v12.LocalObjectReference{
+									Name: configMap.Name,
+								},
+							},
+						},
+					},
+				},
+				Containers: []v12.Container{
+					{
+						Name:  "tempo",
+						Image: model.GetImage(cr, v1.ImageTempo, model.TempoImage),
+						Args: []string{
+							"-config.file=/etc/tempo/tempo.yaml",
+						},
+						Ports: []v12.ContainerPort{
+							{Name: "http", ContainerPort: model.TempoHttpPort},
+							{Name: "otlp-grpc", ContainerPort: 4317},
+							{Name: "otlp-http", ContainerPort: 4318},
+							{Name: "jaeger-grpc", ContainerPort: 14250},
+							{Name: "jaeger-thrift", ContainerPort: 14268},
+						},
+						ReadinessProbe: &v12.Probe{
+							Handler: v12.Handler{
+								HTTPGet: &v12.HTTPGetAction{
+									Path: "/ready",
+									Port: intstr.FromString("http"),
+								},
+							},
+						},
+						Resources: model.GetTempoResourceRequirement(cr),
+						VolumeMounts: []v12.VolumeMount{
+							{
+								Name:      "config",
+								MountPath: "/etc/tempo",
+							},
+							{
+								Name:      "data",
+								MountPath: "/var/tempo",
+							},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) waitForTempo(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	statefulSet := model.GetTempoStatefulSet(cr)
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: statefulSet.Namespace, Name: statefulSet.Name}, statefulSet)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	if statefulSet.Status.ReadyReplicas < 1 {
+		return v1.ResultInProgress, nil
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// With the Tempo operator the traces are stored in object storage. The credentials secret has to
+// be created by the user in the namespace of the CR
+func (r *Reconciler) reconcileTempoStack(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	storage := cr.Spec.Tracing.Storage
+	if storage.ObjectStorageSecret == "" {
+		r.logger.Info("tempo operator detected, but no object storage secret configured")
+		return v1.ResultFailed, nil
+	}
+
+	storageType := storage.ObjectStorageType
+	if storageType == "" {
+		storageType = "s3"
+	}
+
+	stack := model.GetTempoStack(cr)
+	err := utils.Apply(ctx, r.client, stack, func() error {
+		stack.SetLabels(map[string]string{
+			"managed-by": "observability-operator",
+		})
+
+		spec := map[string]interface{}{
+			"storageSize": model.GetTempoStorageSize(cr),
+			"storage": map[string]interface{}{
+				"secret": map[string]interface{}{
+					"name": storage.ObjectStorageSecret,
+					"type": storageType,
+				},
+			},
+			"retention": map[string]interface{}{
+				"global": map[string]interface{}{
+					"traces": model.GetTempoRetention(cr),
+				},
+			},
+		}
+		if storage.StorageClass != nil {
+			spec["storageClassName"] = *storage.StorageClass
+		}
+		return unstructured.SetNestedMap(stack.Object, spec, "spec")
+	})
+
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileTempoDatasource(ctx context.Context, cr *v1.Observability, tempoOperator bool) (v1.ObservabilityStageStatus, error) {
+	datasource := model.GetTempoDatasource(cr)
+
+	err := utils.Apply(ctx, r.client, datasource, func() error {
+		datasource.Spec.Name = "tempo.yaml"
+		datasource.Spec.Datasources = []v1alpha1.GrafanaDataSourceFields{
+			{
+				Name:     model.TempoDatasourceName,
+				Type:     "tempo",
+				Access:   "proxy",
+				Url:      model.GetTempoQueryUrl(cr, tempoOperator),
+				Version:  model.GetGrafanaDatasourceVersion(cr),
+				Editable: true,
+			},
+		}
+		return nil
+	})
+
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
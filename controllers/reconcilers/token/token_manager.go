@@ -7,12 +7,12 @@ import (
 	errors2 "github.com/pkg/errors"
 	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers/token"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
 	v12 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"strconv"
 )
 
@@ -115,14 +115,15 @@ func saveToken(ctx context.Context, c client.Client, config *v1.ObservatoriumInd
 		},
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, c, secret, func() error {
+	// Prometheus reloads when the token secret changes, an unchanged token is not written again
+	_, err := utils.ApplySecret(ctx, c, secret, func() error {
 		secret.Labels = map[string]string{
 			"managed-by": "observability-operator",
 			"purpose":    "observatorium-token-secret",
 		}
-		secret.StringData = map[string]string{
-			RemoteTokenValue:    token,
-			RemoteTokenLifetime: strconv.FormatInt(lifetime, 10),
+		secret.Data = map[string][]byte{
+			RemoteTokenValue:    []byte(token),
+			RemoteTokenLifetime: []byte(strconv.FormatInt(lifetime, 10)),
 		}
 		return nil
 	})
@@ -223,6 +224,14 @@ func assignFromSecret(ctx context.Context, c client.Client, cr *v1.Observability
 	return nil
 }
 
+// Completes the config of an Observatorium with the gateway, tenant and credentials of its secret
+func ResolveObservatorium(ctx context.Context, c client.Client, cr *v1.Observability, config *v1.ObservatoriumIndex) error {
+	if config.SecretName == "" {
+		return nil
+	}
+	return assignFromSecret(ctx, c, cr, config)
+}
+
 func ReconcileObservatoria(log logr.Logger, ctx context.Context, c client.Client, cr *v1.Observability, index *v1.RepositoryIndex) error {
 	if index == nil || index.Config == nil || index.Config.Observatoria == nil {
 		return nil
@@ -0,0 +1,294 @@
+package version_check
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	v13 "k8s.io/api/apps/v1"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Rollouts run the old and the new version side by side for a while, a skew is only reported once
+// it lasted longer than this
+const versionSkewGracePeriod = 15 * time.Minute
+
+// Component of the stack of which the version is read from the images of its running pods
+type versionedComponent struct {
+	Name      string
+	Container string
+	// Version the operator expects, empty if another operator chooses it
+	Expected string
+	// Prefix of the names of the resources of which rollouts may be deferred to the upgrade window
+	Owner string
+}
+
+// Operator of the stack installed through OLM
+type versionedOperator struct {
+	Name         string
+	Subscription string
+	CSVPrefix    string
+}
+
+func getVersionedComponents(cr *v1.Observability) []versionedComponent {
+	var result []versionedComponent
+	if cr.PrometheusMode() == v1.ComponentManaged {
+		image := model.GetImage(cr, v1.ImagePrometheus, fmt.Sprintf("%s:%s", configuration.PrometheusBaseImage, model.GetPrometheusVersion(cr)))
+		result = append(result, versionedComponent{
+			Name:      "prometheus",
+			Container: "prometheus",
+			Expected:  model.GetImageVersion(image),
+			Owner:     model.GetDefaultNamePrometheus(cr),
+		})
+	}
+	if cr.AlertmanagerMode() == v1.ComponentManaged {
+		expected := model.GetAlertmanagerVersion(cr)
+		if image, ok := model.GetImageOverride(cr, v1.ImageAlertmanager); ok {
+			expected = model.GetImageVersion(image)
+		}
+		result = append(result, versionedComponent{
+			Name:      "alertmanager",
+			Container: "alertmanager",
+			Expected:  expected,
+			Owner:     model.GetDefaultNameAlertmanager(cr),
+		})
+	}
+	if cr.GrafanaMode() == v1.ComponentManaged {
+		expected := ""
+		if image, ok := model.GetImageOverride(cr, v1.ImageGrafana); ok {
+			expected = model.GetImageVersion(image)
+		}
+		result = append(result, versionedComponent{
+			Name:      "grafana",
+			Container: "grafana",
+			Expected:  expected,
+			Owner:     model.GetDefaultNameGrafana(cr),
+		})
+	}
+	if cr.PromtailMode() == v1.ComponentManaged {
+		result = append(result, versionedComponent{
+			Name:      "promtail",
+			Container: "promtail",
+			Expected:  model.GetImageVersion(model.GetImage(cr, v1.ImagePromtail, model.PromtailImage)),
+			Owner:     "promtail-",
+		})
+	}
+	return result
+}
+
+func getVersionedOperators(cr *v1.Observability) []versionedOperator {
+	return []versionedOperator{
+		{
+			Name:         "prometheus-operator",
+			Subscription: model.GetPrometheusSubscription(cr).Name,
+			CSVPrefix:    model.PrometheusOperatorCSVPrefix,
+		},
+		{
+			Name:         "grafana-operator",
+			Subscription: model.GetGrafanaSubscription(cr).Name,
+			CSVPrefix:    "grafana-operator.",
+		},
+	}
+}
+
+type Reconciler struct {
+	client client.Client
+	logger logr.Logger
+}
+
+func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
+	return &Reconciler{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	return v1.ResultSuccess, nil
+}
+
+// Reports the versions the components run and raises a condition when they differ from the versions
+// the operator expects, e.g. after an image was changed by hand or an upgrade got stuck. Rollouts
+// deferred to the upgrade window are expected to run the old version
+func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
+	var versions []v1.ComponentVersion
+
+	pods := &v12.PodList{}
+	err := r.client.List(ctx, pods, client.InNamespace(cr.Namespace))
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	for _, component := range getVersionedComponents(cr) {
+		running := getRunningVersions(pods.Items, component.Container)
+		if len(running) == 0 {
+			continue
+		}
+		version := v1.ComponentVersion{
+			Name:     component.Name,
+			Versions: running,
+			Expected: component.Expected,
+		}
+		if !isRolloutDeferred(s, component.Owner) {
+			version.Skew = getVersionSkew(running, component.Expected)
+		}
+		versions = append(versions, version)
+	}
+
+	capabilities, err := utils.GetCapabilities(ctx, r.client, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+	if capabilities.OLM {
+		csvs := &v1alpha1.ClusterServiceVersionList{}
+		err = r.client.List(ctx, csvs, client.InNamespace(cr.Namespace))
+		if err != nil && !meta.IsNoMatchError(err) {
+			return v1.ResultFailed, err
+		}
+		for _, operator := range getVersionedOperators(cr) {
+			version, err := r.getOperatorVersion(ctx, cr, s, operator, csvs.Items)
+			if err != nil {
+				return v1.ResultFailed, err
+			}
+			if version != nil {
+				versions = append(versions, *version)
+			}
+		}
+	}
+
+	now := time.Now().Unix()
+	var skewed []string
+	for i := range versions {
+		version := &versions[i]
+		if version.Skew == "" {
+			continue
+		}
+		version.SkewSince = now
+		if previous := s.GetComponentVersion(version.Name); previous != nil && previous.SkewSince != 0 {
+			version.SkewSince = previous.SkewSince
+		}
+		if time.Since(time.Unix(version.SkewSince, 0)) >= versionSkewGracePeriod {
+			skewed = append(skewed, fmt.Sprintf("%v: %v", version.Name, version.Skew))
+		}
+	}
+	s.ComponentVersions = versions
+
+	if len(skewed) == 0 {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    v1.VersionSkew,
+			Status:  metav1.ConditionFalse,
+			Reason:  "VersionsExpected",
+			Message: "all components run the expected versions",
+		})
+		return v1.ResultSuccess, nil
+	}
+
+	r.logger.Info("component version skew", "components", skewed)
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:    v1.VersionSkew,
+		Status:  metav1.ConditionTrue,
+		Reason:  "VersionSkew",
+		Message: strings.Join(skewed, ", "),
+	})
+	return v1.ResultSuccess, nil
+}
+
+// Sorted versions of the images of the container in the running pods
+func getRunningVersions(pods []v12.Pod, container string) []string {
+	found := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Status.Phase != v12.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, c := range pod.Spec.Containers {
+			if c.Name == container {
+				found[model.GetImageVersion(c.Image)] = true
+			}
+		}
+	}
+
+	var result []string
+	for version := range found {
+		result = append(result, version)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func getVersionSkew(running []string, expected string) string {
+	if len(running) > 1 {
+		return fmt.Sprintf("runs %v at the same time", strings.Join(running, " and "))
+	}
+	if expected != "" && running[0] != expected {
+		return fmt.Sprintf("runs %v, expected %v", running[0], expected)
+	}
+	return ""
+}
+
+func isRolloutDeferred(s *v1.ObservabilityStatus, owner string) bool {
+	for _, deferred := range s.DeferredRollouts {
+		if strings.HasPrefix(deferred.Component, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// The version of an operator is the version of its installed CSV. The operator expects the CSV the
+// subscription last installed successfully, and the images of the deployments to be those of the CSV
+func (r *Reconciler) getOperatorVersion(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus, operator versionedOperator, csvs []v1alpha1.ClusterServiceVersion) (*v1.ComponentVersion, error) {
+	var installed *v1alpha1.ClusterServiceVersion
+	for i, csv := range csvs {
+		if strings.HasPrefix(csv.Name, operator.CSVPrefix) && csv.Status.Phase == v1alpha1.CSVPhaseSucceeded {
+			installed = &csvs[i]
+		}
+	}
+	if installed == nil {
+		return nil, nil
+	}
+
+	version := &v1.ComponentVersion{
+		Name:     operator.Name,
+		Versions: []string{installed.Spec.Version.String()},
+	}
+	subscription := s.GetSubscriptionStatus(operator.Subscription)
+	if subscription != nil && subscription.InstalledCSV != "" {
+		version.Expected = strings.TrimPrefix(strings.TrimPrefix(subscription.InstalledCSV, operator.CSVPrefix), "v")
+		if subscription.InstalledCSV != installed.Name && !isRolloutDeferred(s, operator.Subscription) {
+			version.Skew = fmt.Sprintf("installed CSV is %v, expected %v", installed.Name, subscription.InstalledCSV)
+			return version, nil
+		}
+	}
+
+	for _, spec := range installed.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		deployment := &v13.Deployment{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: spec.Name}, deployment)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, expected := range spec.Spec.Template.Spec.Containers {
+			for _, container := range deployment.Spec.Template.Spec.Containers {
+				if container.Name == expected.Name && container.Image != expected.Image {
+					version.Skew = fmt.Sprintf("deployment %v runs %v, the CSV has %v", deployment.Name, container.Image, expected.Image)
+					return version, nil
+				}
+			}
+		}
+	}
+	return version, nil
+}
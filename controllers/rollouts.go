@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Flip the RolloutsDeferred condition depending on the rollouts deferred by the stages. CRs without
+// an upgrade window don't get the condition
+func setRolloutsDeferredCondition(cr *apiv1.Observability, s *apiv1.ObservabilityStatus) {
+	if len(s.DeferredRollouts) > 0 && cr.Spec.UpgradeWindow != nil {
+		var deferred []string
+		for _, rollout := range s.DeferredRollouts {
+			deferred = append(deferred, fmt.Sprintf("%v (%v)", rollout.Component, rollout.Change))
+		}
+		message := fmt.Sprintf("deferred: %v", strings.Join(deferred, ", "))
+		_, reason, next := cr.Spec.UpgradeWindow.Open(time.Now())
+		if reason != "" {
+			message = fmt.Sprintf("%v, %v", reason, message)
+		}
+		if !next.IsZero() {
+			message = fmt.Sprintf("%v, next window at %v", message, next.Format(time.RFC3339))
+		}
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    apiv1.RolloutsDeferred,
+			Status:  metav1.ConditionTrue,
+			Reason:  "OutsideUpgradeWindow",
+			Message: message,
+		})
+		return
+	}
+
+	if meta.FindStatusCondition(s.Conditions, apiv1.RolloutsDeferred) != nil {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:   apiv1.RolloutsDeferred,
+			Status: metav1.ConditionFalse,
+			Reason: "NothingDeferred",
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/observatorium_tenant"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v3/fakes"
+)
+
+var _ = Describe("Observatorium tenant verification against the simulated external services", func() {
+	const namespace = "default"
+
+	var environment *fakes.Environment
+	var cr *v1.Observability
+
+	verify := func() *metav1.Condition {
+		status := &v1.ObservabilityStatus{}
+		reconciler := observatorium_tenant.NewReconciler(k8sClient, logf.Log.WithName("test"))
+		result, err := reconciler.Reconcile(context.Background(), cr, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(v1.ResultSuccess))
+		return meta.FindStatusCondition(status.Conditions, v1.ObservatoriumTenantReady)
+	}
+
+	BeforeEach(func() {
+		var err error
+		environment, err = fakes.NewEnvironment("127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		environment.Serve()
+
+		err = environment.Seed(context.Background(), k8sClient, namespace, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		cr = &v1.Observability{
+			ObjectMeta: metav1.ObjectMeta{Name: "simulated", Namespace: namespace},
+			Spec: v1.ObservabilitySpec{
+				Observatorium: &v1.Observatorium{Tenant: environment.ObservatoriumTenant()},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(environment.Close()).To(Succeed())
+	})
+
+	It("verifies the seeded tenant", func() {
+		condition := verify()
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("reports unknown tenants", func() {
+		cr.Spec.Observatorium.Tenant.Tenant = "unknown"
+		condition := verify()
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("TenantNotFound"))
+	})
+
+	It("reports rejected credentials", func() {
+		environment.InjectFault(fakes.ComponentObservatorium, http.StatusForbidden)
+		condition := verify()
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("Unauthorized"))
+	})
+
+	It("reports an unavailable SSO", func() {
+		environment.InjectFault(fakes.ComponentSSO, http.StatusServiceUnavailable)
+		condition := verify()
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("InvalidOIDCConfig"))
+	})
+})
+
+var _ = Describe("Configuration sync against the simulated external services", func() {
+	const namespace = "default"
+	var selector = map[string]string{"configures": "simulated"}
+
+	var environment *fakes.Environment
+	var cr *v1.Observability
+
+	sync := func() (*v1.ObservabilityStatus, v1.ObservabilityStageStatus, error) {
+		status := &v1.ObservabilityStatus{}
+		reconciler := configuration.NewReconciler(k8sClient, logf.Log.WithName("test"), record.NewFakeRecorder(100))
+		result, err := reconciler.Reconcile(context.Background(), cr, status)
+		return status, result, err
+	}
+
+	tokenSecret := func() (*corev1.Secret, error) {
+		secret := &corev1.Secret{}
+		name := token.GetObservatoriumTokenSecretName(&v1.ObservatoriumIndex{Id: fakes.SimulatedTenant})
+		key := client.ObjectKey{Namespace: namespace, Name: name}
+		return secret, k8sClient.Get(context.Background(), key, secret)
+	}
+
+	BeforeEach(func() {
+		var err error
+		environment, err = fakes.NewEnvironment("127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		environment.Serve()
+
+		err = environment.Seed(context.Background(), k8sClient, namespace, selector)
+		Expect(err).ToNot(HaveOccurred())
+
+		// Only the synced resources that need no other CRDs than those of the operator
+		cr = &v1.Observability{
+			ObjectMeta: metav1.ObjectMeta{Name: "simulated", Namespace: namespace},
+			Spec: v1.ObservabilitySpec{
+				ConfigurationSelector: &metav1.LabelSelector{MatchLabels: selector},
+				ResyncPeriod:          "1h",
+				Observatorium:         &v1.Observatorium{Tenant: environment.ObservatoriumTenant()},
+				Components: &v1.Components{
+					Prometheus:     v1.ComponentDisabled,
+					Alertmanager:   v1.ComponentDisabled,
+					Grafana:        v1.ComponentDisabled,
+					Promtail:       v1.ComponentDisabled,
+					TokenRefresher: v1.ComponentDisabled,
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(environment.Close()).To(Succeed())
+		// Token secrets and config snapshots of the sync
+		Expect(k8sClient.DeleteAllOf(context.Background(), &corev1.Secret{}, client.InNamespace(namespace),
+			client.MatchingLabels{"managed-by": "observability-operator"})).To(Succeed())
+	})
+
+	It("syncs the seeded repository", func() {
+		status, result, err := sync()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(v1.ResultSuccess))
+		Expect(status.LastSynced).ToNot(BeZero())
+
+		// The token of the Dex user of the index is stored for Prometheus
+		secret, err := tokenSecret()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(environment.SSO.ValidToken(string(secret.Data[token.RemoteTokenValue]))).To(BeTrue())
+
+		// The log rules of the index are pushed with the client credentials of the tenant
+		Expect(environment.Observatorium.LokiRuleGroups(fakes.SimulatedTenant)).To(ContainElement(ConsistOf("simulated")))
+		condition := meta.FindStatusCondition(status.Conditions, v1.LogRulesSynced)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+
+		// Everything fetched is kept in the snapshot of the sync
+		Expect(status.ConfigRevisions).To(HaveLen(1))
+		Expect(status.ConfigRevisions[0].Name).To(Equal(fakes.SimulatedConfigSecret))
+		Expect(status.ConfigSnapshot).ToNot(BeEmpty())
+		snapshot := &corev1.Secret{}
+		err = k8sClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: configuration.ConfigSnapshotPrefix + status.ConfigSnapshot}, snapshot)
+		Expect(err).ToNot(HaveOccurred())
+		resources, err := configuration.GetConfigSnapshotResources(snapshot)
+		Expect(err).ToNot(HaveOccurred())
+		channel := environment.RepositoryURL() + "/" + fakes.SimulatedChannel
+		Expect(resources).To(HaveKey(channel + "/index.json"))
+		Expect(resources).To(HaveKey(channel + "/loki/rules.yaml"))
+	})
+
+	It("fails the sync if the repository is unavailable", func() {
+		environment.InjectFault(fakes.ComponentRepository, http.StatusServiceUnavailable)
+		status, result, err := sync()
+		Expect(err).To(HaveOccurred())
+		Expect(result).To(Equal(v1.ResultFailed))
+		Expect(status.LastSynced).To(BeZero())
+	})
+
+	It("syncs without tokens if the SSO is unavailable", func() {
+		environment.InjectFault(fakes.ComponentSSO, http.StatusServiceUnavailable)
+		status, result, err := sync()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(v1.ResultSuccess))
+
+		_, err = tokenSecret()
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		condition := meta.FindStatusCondition(status.Conditions, v1.LogRulesSynced)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("RulerUnavailable"))
+	})
+})
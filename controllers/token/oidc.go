@@ -0,0 +1,78 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Look up the token endpoint in the OIDC discovery document of the issuer
+func GetTokenEndpoint(httpClient *http.Client, issuer string) (string, error) {
+	discoveryUrl := fmt.Sprintf("%v/.well-known/openid-configuration", strings.TrimSuffix(issuer, "/"))
+	resp, err := httpClient.Get(discoveryUrl)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response from oidc discovery endpoint: %v", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	discovery := struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}{}
+
+	err = json.Unmarshal(body, &discovery)
+	if err != nil {
+		return "", err
+	}
+
+	if discovery.TokenEndpoint == "" {
+		return "", fmt.Errorf("no token endpoint in the oidc configuration of %v", issuer)
+	}
+
+	return discovery.TokenEndpoint, nil
+}
+
+func FetchClientCredentialsToken(httpClient *http.Client, tokenEndpoint string, clientId string, clientSecret string) (string, error) {
+	formData := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientId},
+		"client_secret": {clientSecret},
+	}
+
+	resp, err := httpClient.PostForm(tokenEndpoint, formData)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response from token endpoint: %v", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	tokenResponse := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+
+	err = json.Unmarshal(body, &tokenResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenResponse.AccessToken, nil
+}
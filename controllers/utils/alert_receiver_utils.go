@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"context"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Generates the bearer token Alertmanager authenticates to a receiver of the alerts with. The token
+// is kept once generated and returned
+func ReconcileAlertReceiverToken(ctx context.Context, client k8sclient.Client, cr *v1.Observability, receiver string) (string, error) {
+	secret := model.GetAlertReceiverTokenSecret(cr, receiver)
+	token, err := GetOrGenerateSecretValue(ctx, client, secret, model.AlertReceiverTokenKey, 32)
+	if err != nil {
+		return "", err
+	}
+
+	err = Apply(ctx, client, secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			model.AlertReceiverTokenKey: token,
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+// Only allows the Alertmanager of the CR, and the pods of the additional selectors, to reach the
+// port of the receiver
+func ReconcileAlertReceiverNetworkPolicy(ctx context.Context, client k8sclient.Client, cr *v1.Observability, receiver string, podSelector map[string]string, port int, clients ...map[string]string) error {
+	peers := []networkingv1.NetworkPolicyPeer{
+		{
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: model.GetAlertmanagerPodLabels(cr),
+			},
+		},
+	}
+	for _, selector := range clients {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+		})
+	}
+
+	policy := model.GetAlertReceiverNetworkPolicy(cr, receiver)
+	policyPort := intstr.FromInt(port)
+	return Apply(ctx, client, policy, func() error {
+		policy.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		policy.Spec = networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: podSelector,
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{
+							Port: &policyPort,
+						},
+					},
+					From: peers,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		}
+		return nil
+	})
+}
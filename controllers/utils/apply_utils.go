@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// Field manager of the resources applied by the operator
+	FieldManager = "observability-operator"
+	// Label of all resources applied by the operator, which restricts lists to them
+	ManagedByLabel = "managed-by"
+	ManagedByValue = "observability-operator"
+	// Hash of the labels and data of a secret applied with ApplySecret
+	ContentHashAnnotation = "observability.redhat.com/content-hash"
+)
+
+// Applies the state of a resource set by mutate with server-side apply. Unlike CreateOrUpdate, the live
+// resource is not read first: only the fields set by mutate and the managed-by label are sent and owned by
+// the operator, and labels, annotations or defaults added by users and other controllers are kept. Fields
+// the operator stops setting are removed and conflicts with other field managers are resolved in favour of
+// the operator. Typed objects don't carry their group, version and kind, the client has to set them from
+// its scheme like the client of the stages does.
+func Apply(ctx context.Context, client k8sclient.Client, obj runtime.Object, mutate controllerutil.MutateFn) error {
+	err := mutate()
+	if err != nil {
+		return err
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	accessor.SetResourceVersion("")
+	accessor.SetManagedFields(nil)
+
+	labels := map[string]string{}
+	for key, value := range accessor.GetLabels() {
+		labels[key] = value
+	}
+	labels[ManagedByLabel] = ManagedByValue
+	accessor.SetLabels(labels)
+
+	return client.Patch(ctx, obj, k8sclient.Apply, k8sclient.FieldOwner(FieldManager), k8sclient.ForceOwnership)
+}
+
+// Applies a secret like Apply, but only if its labels or data changed since it was last applied. Every
+// update of a secret mounted into Prometheus or Alertmanager makes their config reloaders reload the
+// config. Returns true if the secret was written.
+func ApplySecret(ctx context.Context, client k8sclient.Client, secret *corev1.Secret, mutate controllerutil.MutateFn) (bool, error) {
+	err := mutate()
+	if err != nil {
+		return false, err
+	}
+	hash := GetSecretContentHash(secret)
+
+	existing := &corev1.Secret{}
+	err = client.Get(ctx, k8sclient.ObjectKey{Namespace: secret.Namespace, Name: secret.Name}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	// The data is hashed again in case the secret was edited by someone else
+	if err == nil && existing.Annotations[ContentHashAnnotation] == hash && GetSecretContentHash(existing) == hash {
+		return false, nil
+	}
+
+	return true, Apply(ctx, client, secret, func() error {
+		annotations := map[string]string{}
+		for key, value := range secret.Annotations {
+			annotations[key] = value
+		}
+		annotations[ContentHashAnnotation] = hash
+		secret.Annotations = annotations
+		return nil
+	})
+}
+
+// Hash of the type, the labels and the data of a secret. The managed-by label is left out, it is
+// added by Apply
+func GetSecretContentHash(secret *corev1.Secret) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%v\n", secret.Type)
+
+	var labels []string
+	for key, value := range secret.Labels {
+		if key != ManagedByLabel {
+			labels = append(labels, fmt.Sprintf("%v=%v", key, value))
+		}
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		fmt.Fprintf(hash, "%v\n", label)
+	}
+
+	var keys []string
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%v:%v:", key, len(secret.Data[key]))
+		hash.Write(secret.Data[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))[:16]
+}
+
+// Returns the value of a key of an existing secret or a new random string of the given length. Generated
+// values have to be applied again as they are, or they would be removed together with the owned field.
+func GetOrGenerateSecretValue(ctx context.Context, client k8sclient.Client, secret *corev1.Secret, key string, length int) ([]byte, error) {
+	existing := &corev1.Secret{}
+	err := client.Get(ctx, k8sclient.ObjectKey{Namespace: secret.Namespace, Name: secret.Name}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if value := existing.Data[key]; len(value) > 0 {
+		return value, nil
+	}
+	return []byte(GenerateRandomString(length)), nil
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSCredentials of an IAM user, used to sign requests to AWS and S3 compatible APIs
+type AWSCredentials struct {
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+// Adds the headers of an AWS signature version 4 for the service. All headers set on the request
+// are signed, the body has to be passed as the request body can only be read once.
+func SignAWSRequest(req *http.Request, body []byte, service string, credentials AWSCredentials, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	var names []string
+	headers := map[string]string{}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		names = append(names, name)
+		headers[name] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(fmt.Sprintf("%v:%v\n", name, headers[name]))
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%v/%v/%v/aws4_request", date, credentials.Region, service)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+credentials.SecretKey), date)
+	key = hmacSha256(key, credentials.Region)
+	key = hmacSha256(key, service)
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v",
+		credentials.AccessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blang/semver"
+	grafanav1alpha1 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	consolev1 "github.com/openshift/api/console/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Kinds of the optional CRDs the stages depend on
+const (
+	APIRoute            = "Route"
+	APIPrometheusRule   = "PrometheusRule"
+	APIPodMonitor       = "PodMonitor"
+	APIGrafanaDashboard = "GrafanaDashboard"
+)
+
+var optionalAPIs = map[string]func() runtime.Object{
+	APIRoute:            func() runtime.Object { return &routev1.RouteList{} },
+	APIPrometheusRule:   func() runtime.Object { return &prometheusv1.PrometheusRuleList{} },
+	APIPodMonitor:       func() runtime.Object { return &prometheusv1.PodMonitorList{} },
+	APIGrafanaDashboard: func() runtime.Object { return &grafanav1alpha1.GrafanaDashboardList{} },
+}
+
+// Checks if the cluster serves one of the optional APIs
+func HasAPI(ctx context.Context, client k8sclient.Client, namespace string, api string) (bool, error) {
+	list, ok := optionalAPIs[api]
+	if !ok {
+		return false, fmt.Errorf("unknown api: %v", api)
+	}
+	return hasAPI(ctx, client, list(), namespace)
+}
+
+// Capability gate of the stages for the optional APIs. Stages skip the resources of a missing API
+// instead of failing, until its CRD is installed. Missing APIs are recorded in the status and
+// reported by the Degraded condition
+func RequireAPI(ctx context.Context, client k8sclient.Client, cr *v1.Observability, s *v1.ObservabilityStatus, api string) (bool, error) {
+	served, err := HasAPI(ctx, client, cr.Namespace, api)
+	if err != nil {
+		return false, err
+	}
+	s.SetMissingAPI(api, !served)
+	return served, nil
+}
+
+// Checks if the cluster serves the API of a list type. The client resolves kinds through
+// discovery, so an unknown kind means the API is not installed
+func hasAPI(ctx context.Context, client k8sclient.Client, list runtime.Object, namespace string) (bool, error) {
+	err := client.List(ctx, list, k8sclient.InNamespace(namespace))
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Returns the OpenShift version, or an empty string on other distributions
+func getOpenShiftVersion(ctx context.Context, client k8sclient.Client) (string, error) {
+	version, err := GetClusterOSVersion(ctx, client)
+	if err != nil {
+		if meta.IsNoMatchError(err) || errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return version, nil
+}
+
+// Returns the lowest kubelet version of the nodes. During a cluster upgrade this is the version
+// that all nodes support
+func getKubernetesVersion(ctx context.Context, client k8sclient.Client) (string, error) {
+	nodes := &corev1.NodeList{}
+	err := client.List(ctx, nodes)
+	if err != nil {
+		return "", err
+	}
+
+	var lowest *semver.Version
+	for _, node := range nodes.Items {
+		version, err := semver.ParseTolerant(node.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			continue
+		}
+		if lowest == nil || version.LT(*lowest) {
+			lowest = &version
+		}
+	}
+
+	if lowest == nil {
+		return "", nil
+	}
+	return fmt.Sprintf("%v.%v.%v", lowest.Major, lowest.Minor, lowest.Patch), nil
+}
+
+func DetectCapabilities(ctx context.Context, client k8sclient.Client, namespace string) (*v1.ClusterCapabilities, error) {
+	var err error
+	capabilities := &v1.ClusterCapabilities{
+		LastDetected: time.Now().Unix(),
+	}
+
+	capabilities.OLM, err = hasAPI(ctx, client, &v1alpha1.SubscriptionList{}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities.Routes, err = HasRouteAPI(ctx, client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities.PrometheusOperator, err = hasAPI(ctx, client, &prometheusv1.PrometheusList{}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities.GrafanaOperator, err = hasAPI(ctx, client, &grafanav1alpha1.GrafanaList{}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	tempoStacks := &unstructured.UnstructuredList{}
+	tempoStacks.SetGroupVersionKind(model.TempoStackGroupVersionKind.GroupVersion().WithKind("TempoStackList"))
+	capabilities.TempoOperator, err = hasAPI(ctx, client, tempoStacks, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := &unstructured.UnstructuredList{}
+	certificates.SetGroupVersionKind(model.CertificateGroupVersionKind.GroupVersion().WithKind("CertificateList"))
+	capabilities.CertManager, err = hasAPI(ctx, client, certificates, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	externalSecrets := &unstructured.UnstructuredList{}
+	externalSecrets.SetGroupVersionKind(model.ExternalSecretGroupVersionKind.GroupVersion().WithKind("ExternalSecretList"))
+	capabilities.ExternalSecretsOperator, err = hasAPI(ctx, client, externalSecrets, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities.Console, err = hasAPI(ctx, client, &consolev1.ConsoleLinkList{}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities.OpenShiftVersion, err = getOpenShiftVersion(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities.KubernetesVersion, err = getKubernetesVersion(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return capabilities, nil
+}
+
+// Returns the capabilities stored in the status of the CR. They are detected on the fly before
+// the first detection stage completed
+func GetCapabilities(ctx context.Context, client k8sclient.Client, cr *v1.Observability) (*v1.ClusterCapabilities, error) {
+	if cr.Status.Capabilities != nil {
+		return cr.Status.Capabilities, nil
+	}
+	return DetectCapabilities(ctx, client, cr.Namespace)
+}
+
+// Returns the uid of the kube-system namespace, which is stable for the lifetime of a cluster
+func GetKubeSystemUID(ctx context.Context, client k8sclient.Client) (string, error) {
+	ns := &corev1.Namespace{}
+	selector := k8sclient.ObjectKey{
+		Name: "kube-system",
+	}
+
+	err := client.Get(ctx, selector, ns)
+	if err != nil {
+		return "", err
+	}
+	return string(ns.UID), nil
+}
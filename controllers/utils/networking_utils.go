@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Sets the ip families of spec.networking on a service. The client types predate the dual-stack
+// fields of the service spec, so they are patched in after the service is created or updated
+func ReconcileServiceIPFamilies(ctx context.Context, client k8sclient.Client, cr *v1.Observability, service *corev1.Service) error {
+	families := cr.IPFamilies()
+	if len(families) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ipFamilyPolicy": model.GetIPFamilyPolicy(cr),
+			"ipFamilies":     families,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return client.Patch(ctx, service, k8sclient.RawPatch(types.MergePatchType, patch))
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"errors"
+
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Checks if the cluster serves the OpenShift Route API
+func HasRouteAPI(ctx context.Context, client k8sclient.Client, namespace string) (bool, error) {
+	return hasAPI(ctx, client, &routev1.RouteList{}, namespace)
+}
+
+// Returns how the UIs are exposed. Unless configured in the CR, Routes are used on clusters that
+// support them and Ingresses everywhere else
+func GetUIAccessType(ctx context.Context, client k8sclient.Client, cr *v1.Observability) (v1.UIAccessType, error) {
+	if accessType := cr.GetUIAccessType(); accessType != "" {
+		return accessType, nil
+	}
+
+	capabilities, err := GetCapabilities(ctx, client, cr)
+	if err != nil {
+		return "", err
+	}
+	if capabilities.Routes {
+		return v1.UIAccessRoute, nil
+	}
+	return v1.UIAccessIngress, nil
+}
+
+// Returns the secret with the serving certificate of the OAuth proxy of a UI and if the proxy logs
+// users in with their OpenShift account. On OpenShift the service CA issues the serving certificate.
+// Other distributions have neither the service CA nor the OpenShift login, the proxy uses the
+// certificate of the component issued by the internal CA and the OIDC provider of the CR instead
+func GetUIProxyTLSSecret(ctx context.Context, client k8sclient.Client, cr *v1.Observability, component string, servingCertSecret string) (string, bool, error) {
+	capabilities, err := GetCapabilities(ctx, client, cr)
+	if err != nil {
+		return "", false, err
+	}
+	if capabilities.IsOpenShift() {
+		return servingCertSecret, true, nil
+	}
+
+	if model.GetUIAccessOIDC(cr) == nil {
+		return "", false, errors.New("the OpenShift login is not available, spec.selfContained.uiAccess.oidc is required to log in to the UIs")
+	}
+	if !model.IsInternalTLSEnabled(cr) {
+		return "", false, errors.New("the OpenShift service CA is not available, spec.tls.internal and cert-manager are required for the certificates of the UIs")
+	}
+	return model.GetInternalTLSSecretName(component), false, nil
+}
+
+// Returns the TLS config of a UI route. Routes use the router certificate unless a custom
+// certificate is configured in spec.selfContained.uiAccess
+func GetUIRouteTLS(ctx context.Context, client k8sclient.Client, cr *v1.Observability) (*routev1.TLSConfig, error) {
+	tls := &routev1.TLSConfig{
+		Termination: routev1.TLSTerminationReencrypt,
+	}
+
+	secretName := model.GetUITLSSecretName(cr)
+	if secretName == "" {
+		return tls, nil
+	}
+
+	secret := &corev1.Secret{}
+	selector := k8sclient.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      secretName,
+	}
+
+	err := client.Get(ctx, selector, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	tls.Certificate = string(secret.Data[corev1.TLSCertKey])
+	tls.Key = string(secret.Data[corev1.TLSPrivateKeyKey])
+	return tls, nil
+}
+
+// Creates or updates the ingress of a UI, routing all requests to the web port of the service
+func ReconcileUIIngress(ctx context.Context, client k8sclient.Client, cr *v1.Observability, ingress *networkingv1.Ingress, host string, service string) error {
+	if host == "" {
+		return errors.New("routes are not available, host names in spec.selfContained.uiAccess are required to create ingresses")
+	}
+
+	pathType := networkingv1.PathTypePrefix
+
+	err := Apply(ctx, client, ingress, func() error {
+		ingress.Annotations = model.GetUIIngressAnnotations(cr)
+		ingress.Spec = networkingv1.IngressSpec{
+			IngressClassName: model.GetUIIngressClassName(cr),
+			TLS: []networkingv1.IngressTLS{
+				{
+					Hosts:      []string{host},
+					SecretName: model.GetUITLSSecretName(cr),
+				},
+			},
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: service,
+											Port: networkingv1.ServiceBackendPort{
+												Name: "web",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+
+	return err
+}
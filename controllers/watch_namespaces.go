@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// Comma separated list of the namespaces of which Observability CRs are reconciled, all
+	// namespaces when empty
+	WatchNamespaceEnv = "WATCH_NAMESPACE"
+	// Label selector for the namespaces of which Observability CRs are reconciled
+	WatchNamespaceSelectorEnv = "WATCH_NAMESPACE_SELECTOR"
+)
+
+// Namespaces of which Observability CRs are reconciled. A namespace has to be in the list, if
+// one is given, and match the selector, if one is given.
+type WatchNamespaces struct {
+	Names    map[string]bool
+	Selector labels.Selector
+}
+
+func GetWatchNamespaces() (*WatchNamespaces, error) {
+	result := &WatchNamespaces{}
+
+	for _, name := range strings.Split(os.Getenv(WatchNamespaceEnv), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if result.Names == nil {
+			result.Names = map[string]bool{}
+		}
+		result.Names[name] = true
+	}
+
+	if selector := strings.TrimSpace(os.Getenv(WatchNamespaceSelectorEnv)); selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return nil, err
+		}
+		result.Selector = parsed
+	}
+
+	return result, nil
+}
+
+func (w *WatchNamespaces) IsClusterScoped() bool {
+	return w == nil || (w.Names == nil && w.Selector == nil)
+}
+
+func (w *WatchNamespaces) IsWatched(ctx context.Context, c client.Reader, namespace string) (bool, error) {
+	if w.IsClusterScoped() {
+		return true, nil
+	}
+	if w.Names != nil && !w.Names[namespace] {
+		return false, nil
+	}
+	if w.Selector == nil {
+		return true, nil
+	}
+
+	ns := &v1.Namespace{}
+	err := c.Get(ctx, client.ObjectKey{Name: namespace}, ns)
+	if err != nil {
+		return false, err
+	}
+	return w.Selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// Enqueue the CRs of a namespace when its labels change, so that they are reconciled as soon as
+// the namespace matches the selector
+func (r *ObservabilityReconciler) mapWatchedNamespace(o handler.MapObject) []reconcile.Request {
+	list := &apiv1.ObservabilityList{}
+	err := r.List(context.Background(), list, client.InNamespace(o.Meta.GetName()))
+	if err != nil {
+		r.Log.Error(err, "error listing observability CRs for namespace", "namespace", o.Meta.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, obs := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: obs.Namespace,
+				Name:      obs.Name,
+			},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,267 @@
+// Package fakes provides in-process fakes of the external dependencies of the operator: the
+// configuration repository, the SSO and the Observatorium API. The operator runs against them with
+// --simulate-external, tests start them with NewEnvironment
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Names of the fakes, used for the paths they are served at and to inject faults
+const (
+	ComponentRepository    = "repository"
+	ComponentSSO           = "sso"
+	ComponentObservatorium = "observatorium"
+)
+
+// Defaults the environment is seeded with
+const (
+	SimulatedTenant       = "simulated"
+	SimulatedChannel      = "simulated"
+	SimulatedAccessToken  = "simulated-access-token"
+	SimulatedRealm        = "simulated"
+	SimulatedClientId     = "simulated-admin"
+	SimulatedClientSecret = "simulated-admin-secret"
+	SimulatedDexUsername  = "simulated@example.com"
+	SimulatedDexPassword  = "simulated-password"
+	SimulatedDexSecret    = "simulated-dex-secret"
+	SimulatedConfigSecret = "simulated-config"
+	SimulatedTenantSecret = "simulated-tenant-credentials"
+)
+
+// Rule files of the seeded repository index
+const (
+	simulatedPrometheusRule = `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: simulated-rules
+spec:
+  groups:
+    - name: simulated
+      rules:
+        - alert: SimulatedAlert
+          expr: vector(0) > 1
+`
+	simulatedLokiRule = `groups:
+  - name: simulated
+    rules:
+      - alert: SimulatedLogErrors
+        expr: sum(rate({namespace="simulated"} |= "error" [5m])) > 10
+        for: 5m
+`
+)
+
+// Environment serves the fakes on a single listener, each below the path of its name
+type Environment struct {
+	Repository    *Repository
+	SSO           *SSO
+	Observatorium *Observatorium
+	// Fraction of the requests answered with 503 Service Unavailable, for chaos testing
+	FailureRate float64
+
+	url      string
+	listener net.Listener
+	server   *http.Server
+
+	mu     sync.Mutex
+	faults map[string]int
+}
+
+// NewEnvironment listens on the address, e.g. 127.0.0.1:0 for a random port, and seeds the fakes
+// with a repository index that sends the metrics of the stack to the simulated tenant
+func NewEnvironment(addr string) (*Environment, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	sso := NewSSO()
+	e := &Environment{
+		Repository:    NewRepository(SimulatedAccessToken),
+		SSO:           sso,
+		Observatorium: NewObservatorium(sso),
+		url:           fmt.Sprintf("http://%v", listener.Addr().String()),
+		listener:      listener,
+		faults:        map[string]int{},
+	}
+	sso.URL = e.url + "/" + ComponentSSO
+
+	mux := http.NewServeMux()
+	mux.Handle("/"+ComponentRepository+"/", e.handler(ComponentRepository, e.Repository))
+	mux.Handle("/"+ComponentSSO+"/", e.handler(ComponentSSO, e.SSO))
+	mux.Handle("/"+ComponentObservatorium+"/", e.handler(ComponentObservatorium, e.Observatorium))
+	e.server = &http.Server{Handler: mux}
+
+	err = e.seed()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *Environment) seed() error {
+	e.SSO.AddClient(SimulatedClientId, SimulatedClientSecret)
+	e.SSO.AddClient(SimulatedTenant, SimulatedDexSecret)
+	e.SSO.AddUser(SimulatedDexUsername, SimulatedDexPassword)
+	e.Observatorium.AddTenant(SimulatedTenant)
+
+	e.Repository.SetFile("", SimulatedChannel+"/prometheus/rules.yaml", []byte(simulatedPrometheusRule))
+	e.Repository.SetFile("", SimulatedChannel+"/loki/rules.yaml", []byte(simulatedLokiRule))
+	return e.Repository.SetIndex("", SimulatedChannel, &v1.RepositoryIndex{
+		Id: SimulatedChannel,
+		Config: &v1.RepositoryConfig{
+			Prometheus: &v1.PrometheusIndex{
+				Rules:         []string{"prometheus/rules.yaml"},
+				Observatorium: SimulatedTenant,
+			},
+			Loki: &v1.LokiIndex{
+				Rules: []string{"loki/rules.yaml"},
+			},
+			Observatoria: []v1.ObservatoriumIndex{
+				{
+					Id:       SimulatedTenant,
+					Gateway:  e.GatewayURL(),
+					Tenant:   SimulatedTenant,
+					AuthType: v1.AuthTypeDex,
+					DexConfig: &v1.DexConfig{
+						Url:      e.url + "/" + ComponentSSO,
+						Username: SimulatedDexUsername,
+						Password: SimulatedDexPassword,
+						Secret:   SimulatedDexSecret,
+					},
+				},
+			},
+		},
+	})
+}
+
+// Injects faults and failures, the fakes only see the requests that pass
+func (e *Environment) handler(component string, handler http.Handler) http.Handler {
+	handler = http.StripPrefix("/"+component, handler)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		e.mu.Lock()
+		status := e.faults[component]
+		failureRate := e.FailureRate
+		e.mu.Unlock()
+
+		if status == 0 && failureRate > 0 && rand.Float64() < failureRate {
+			status = http.StatusServiceUnavailable
+		}
+		if status != 0 {
+			http.Error(w, fmt.Sprintf("injected fault of the simulated %v", component), status)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// InjectFault answers all requests to the component with the status, until it is set to 0
+func (e *Environment) InjectFault(component string, status int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.faults[component] = status
+}
+
+// Serve serves the fakes in the background until Close is called
+func (e *Environment) Serve() {
+	go func() {
+		_ = e.server.Serve(e.listener)
+	}()
+}
+
+func (e *Environment) Close() error {
+	return e.server.Close()
+}
+
+// Start serves the fakes until the stop channel is closed, so that the environment runs as a
+// runnable of the manager
+func (e *Environment) Start(stop <-chan struct{}) error {
+	e.Serve()
+	<-stop
+	return e.Close()
+}
+
+func (e *Environment) URL() string {
+	return e.url
+}
+
+func (e *Environment) RepositoryURL() string {
+	return e.url + "/" + ComponentRepository
+}
+
+func (e *Environment) IssuerURL() string {
+	return fmt.Sprintf("%v/realms/%v", e.SSO.URL, SimulatedRealm)
+}
+
+func (e *Environment) GatewayURL() string {
+	return e.url + "/" + ComponentObservatorium
+}
+
+// ConfigSecret returns a configuration secret of the simulated repository, the labels have to
+// match the configuration selector of the CR
+func (e *Environment) ConfigSecret(namespace string, labels map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SimulatedConfigSecret,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{
+			"repository":   []byte(e.RepositoryURL()),
+			"channel":      []byte(SimulatedChannel),
+			"access_token": []byte(SimulatedAccessToken),
+		},
+	}
+}
+
+// TenantCredentialsSecret returns the credentials of the tenant returned by ObservatoriumTenant
+func (e *Environment) TenantCredentialsSecret(namespace string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SimulatedTenantSecret,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"clientId":     []byte(SimulatedClientId),
+			"clientSecret": []byte(SimulatedClientSecret),
+		},
+	}
+}
+
+// ObservatoriumTenant returns the simulated tenant for spec.observatorium.tenant
+func (e *Environment) ObservatoriumTenant() *v1.ObservatoriumTenant {
+	return &v1.ObservatoriumTenant{
+		Gateway:           e.GatewayURL(),
+		Tenant:            SimulatedTenant,
+		OIDCIssuerUrl:     e.IssuerURL(),
+		CredentialsSecret: SimulatedTenantSecret,
+	}
+}
+
+// Seed creates or updates the configuration secret and the tenant credentials in the namespace
+func (e *Environment) Seed(ctx context.Context, c client.Client, namespace string, labels map[string]string) error {
+	for _, desired := range []*corev1.Secret{e.ConfigSecret(namespace, labels), e.TenantCredentialsSecret(namespace)} {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+		_, err := controllerutil.CreateOrUpdate(ctx, c, secret, func() error {
+			secret.Labels = desired.Labels
+			secret.Data = desired.Data
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package fakes
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+)
+
+// Observatorium is an API gateway of which the tenants accept the tokens of an SSO. Pushed metrics
+// and logs are counted and dropped, queries return a constant vector and the Loki rules of the
+// tenants are kept in memory
+type Observatorium struct {
+	sso *SSO
+
+	mu      sync.Mutex
+	tenants map[string]bool
+	pushes  map[string]int
+	// Rule groups by tenant, namespace and group name
+	rules map[string]map[string]map[string][]byte
+}
+
+// NewObservatorium returns a gateway that accepts the tokens of the SSO, or any token if it is nil
+func NewObservatorium(sso *SSO) *Observatorium {
+	return &Observatorium{
+		sso:     sso,
+		tenants: map[string]bool{},
+		pushes:  map[string]int{},
+		rules:   map[string]map[string]map[string][]byte{},
+	}
+}
+
+func (o *Observatorium) AddTenant(tenant string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.tenants[tenant] = true
+}
+
+// Pushes returns the number of pushes to the metrics or the logs api of the tenant
+func (o *Observatorium) Pushes(tenant string, api string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.pushes[tenant+"/"+api]
+}
+
+// LokiRuleGroups returns the names of the Loki rule groups of the tenant by namespace
+func (o *Observatorium) LokiRuleGroups(tenant string) map[string][]string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	result := map[string][]string{}
+	for namespace, groups := range o.rules[tenant] {
+		for name := range groups {
+			result[namespace] = append(result[namespace], name)
+		}
+		sort.Strings(result[namespace])
+	}
+	return result
+}
+
+// Serves /api/{metrics,logs}/v1/{tenant}/...
+func (o *Observatorium) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 5)
+	if len(parts) < 5 || parts[0] != "api" || parts[2] != "v1" {
+		http.NotFound(w, req)
+		return
+	}
+	api, tenant, path := parts[1], parts[3], "/"+parts[4]
+
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if token == "" || (o.sso != nil && !o.sso.ValidToken(token)) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.tenants[tenant] {
+		http.Error(w, "tenant not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case api == "metrics" && path == "/api/v1/receive":
+		o.pushes[tenant+"/"+api]++
+	case api == "logs" && path == "/loki/api/v1/push":
+		o.pushes[tenant+"/"+api]++
+		w.WriteHeader(http.StatusNoContent)
+	case api == "metrics" && (path == "/api/v1/query" || path == "/api/v1/query_range"):
+		writeJSON(w, map[string]interface{}{
+			"status": "success",
+			"data": map[string]interface{}{
+				"resultType": "vector",
+				"result": []interface{}{
+					map[string]interface{}{"metric": map[string]string{}, "value": []interface{}{0, "1"}},
+				},
+			},
+		})
+	case api == "logs" && strings.HasPrefix(path, "/loki/api/v1/rules"):
+		o.serveRules(w, req, tenant, strings.TrimPrefix(strings.TrimPrefix(path, "/loki/api/v1/rules"), "/"))
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// The rules API of the Loki ruler: list all namespaces, set a group of a namespace or delete one
+func (o *Observatorium) serveRules(w http.ResponseWriter, req *http.Request, tenant string, path string) {
+	namespaces := o.rules[tenant]
+	if namespaces == nil {
+		namespaces = map[string]map[string][]byte{}
+		o.rules[tenant] = namespaces
+	}
+	parts := strings.SplitN(path, "/", 2)
+
+	switch {
+	case req.Method == http.MethodGet && path == "":
+		if len(namespaces) == 0 {
+			http.NotFound(w, req)
+			return
+		}
+		result := map[string][]interface{}{}
+		for namespace, groups := range namespaces {
+			for _, data := range groups {
+				var group interface{}
+				_ = yaml.Unmarshal(data, &group)
+				result[namespace] = append(result[namespace], group)
+			}
+		}
+		body, _ := yaml.Marshal(result)
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(body)
+	case req.Method == http.MethodPost && len(parts) == 1 && parts[0] != "":
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		group := struct {
+			Name string `json:"name"`
+		}{}
+		if err = yaml.Unmarshal(body, &group); err != nil || group.Name == "" {
+			http.Error(w, "invalid rule group", http.StatusBadRequest)
+			return
+		}
+		if namespaces[parts[0]] == nil {
+			namespaces[parts[0]] = map[string][]byte{}
+		}
+		namespaces[parts[0]][group.Name] = body
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == http.MethodDelete && len(parts) == 2:
+		delete(namespaces[parts[0]], parts[1])
+		if len(namespaces[parts[0]]) == 0 {
+			delete(namespaces, parts[0])
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "unsupported rules request", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,72 @@
+package fakes
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+)
+
+// Repository is a configuration repository served like the GitHub contents API. Files are kept by
+// ref, files of the empty ref are those of the default branch
+type Repository struct {
+	accessToken string
+
+	mu    sync.Mutex
+	files map[string]map[string][]byte
+}
+
+func NewRepository(accessToken string) *Repository {
+	return &Repository{
+		accessToken: accessToken,
+		files:       map[string]map[string][]byte{},
+	}
+}
+
+// SetFile sets the content of a file at the ref, the path is relative to the repository
+func (r *Repository) SetFile(ref string, path string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.files[ref] == nil {
+		r.files[ref] = map[string][]byte{}
+	}
+	r.files[ref][strings.TrimPrefix(path, "/")] = data
+}
+
+// SetIndex sets the index of a channel at the ref
+func (r *Repository) SetIndex(ref string, channel string, index *v1.RepositoryIndex) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	r.SetFile(ref, fmt.Sprintf("%v/index.json", channel), data)
+	return nil
+}
+
+func (r *Repository) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Header.Get("Authorization") != fmt.Sprintf("token %v", r.accessToken) {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+		return
+	}
+
+	r.mu.Lock()
+	data, ok := r.files[req.URL.Query().Get("ref")][strings.TrimPrefix(req.URL.Path, "/")]
+	r.mu.Unlock()
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	// Syncs send the ETag of the last sync to skip unchanged files
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(data))
+	w.Header().Set("ETag", etag)
+	if req.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	_, _ = w.Write(data)
+}
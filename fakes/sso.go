@@ -0,0 +1,129 @@
+package fakes
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSO is an OIDC issuer that hands out opaque tokens for the client credentials and the password
+// grants. The discovery document and the token endpoint are served below any path, so it stands in
+// for Red Hat SSO realms as well as for Dex
+type SSO struct {
+	// Url the SSO is served at, set by the environment
+	URL string
+	// Lifetime of the issued tokens
+	TokenLifetime time.Duration
+
+	mu      sync.Mutex
+	clients map[string]string
+	users   map[string]string
+	tokens  map[string]time.Time
+}
+
+func NewSSO() *SSO {
+	return &SSO{
+		TokenLifetime: time.Hour,
+		clients:       map[string]string{},
+		users:         map[string]string{},
+		tokens:        map[string]time.Time{},
+	}
+}
+
+// AddClient registers a client for the client credentials grant
+func (s *SSO) AddClient(id string, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[id] = secret
+}
+
+// AddUser registers a user for the password grant, the client of the grant must be registered too
+func (s *SSO) AddUser(username string, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username] = password
+}
+
+// ValidToken returns whether the token was issued by the SSO and did not expire
+func (s *SSO) ValidToken(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expires, ok := s.tokens[token]
+	return ok && time.Now().Before(expires)
+}
+
+// RevokeTokens invalidates all issued tokens, e.g. to test the refresh of expired tokens
+func (s *SSO) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]time.Time{}
+}
+
+func (s *SSO) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/.well-known/openid-configuration"):
+		issuer := s.URL + strings.TrimSuffix(req.URL.Path, "/.well-known/openid-configuration")
+		writeJSON(w, map[string]string{
+			"issuer":         issuer,
+			"token_endpoint": issuer + "/protocol/openid-connect/token",
+		})
+	case strings.HasSuffix(req.URL.Path, "/token") && req.Method == http.MethodPost:
+		s.serveToken(w, req)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (s *SSO) serveToken(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	clientId, clientSecret, ok := req.BasicAuth()
+	if !ok {
+		clientId, clientSecret = req.PostForm.Get("client_id"), req.PostForm.Get("client_secret")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if secret, ok := s.clients[clientId]; !ok || secret != clientSecret {
+		http.Error(w, "invalid client", http.StatusUnauthorized)
+		return
+	}
+	switch req.PostForm.Get("grant_type") {
+	case "client_credentials":
+	case "password":
+		if password, ok := s.users[req.PostForm.Get("username")]; !ok || password != req.PostForm.Get("password") {
+			http.Error(w, "invalid user", http.StatusUnauthorized)
+			return
+		}
+	default:
+		http.Error(w, "unsupported grant type", http.StatusBadRequest)
+		return
+	}
+
+	token := newToken()
+	s.tokens[token] = time.Now().Add(s.TokenLifetime)
+	// Dex returns the token the gateways accept as the id token
+	writeJSON(w, map[string]interface{}{
+		"access_token": token,
+		"id_token":     token,
+		"token_type":   "Bearer",
+		"expires_in":   int64(s.TokenLifetime.Seconds()),
+	})
+}
+
+func newToken() string {
+	bytes := make([]byte, 16)
+	_, _ = rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
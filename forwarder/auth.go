@@ -0,0 +1,37 @@
+package forwarder
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Reads the bearer token the receivers of the alerts expect from Alertmanager
+func ReadToken(tokenFile string) (string, error) {
+	if tokenFile == "" {
+		return "", fmt.Errorf("a token file is required")
+	}
+	token, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return "", err
+	}
+	if len(strings.TrimSpace(string(token))) == 0 {
+		return "", fmt.Errorf("token file %v is empty", tokenFile)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// Rejects requests without the bearer token
+func RequireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		given := strings.TrimPrefix(header, "Bearer ")
+		if given == header || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,60 @@
+package forwarder
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestRequireToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{name: "bearer token", authorization: "Bearer secret", want: http.StatusOK},
+		{name: "wrong token", authorization: "Bearer other", want: http.StatusUnauthorized},
+		{name: "token without scheme", authorization: "secret", want: http.StatusUnauthorized},
+		{name: "no token", want: http.StatusUnauthorized},
+	}
+	handler := RequireToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/alerts", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tt.want {
+				t.Errorf("RequireToken() = %v, want %v", recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadToken(t *testing.T) {
+	dir := t.TempDir()
+	token := filepath.Join(dir, "token")
+	empty := filepath.Join(dir, "empty")
+	if err := ioutil.WriteFile(token, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(empty, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadToken(token)
+	if err != nil || got != "secret" {
+		t.Errorf("ReadToken() = %v, %v, want secret", got, err)
+	}
+	for _, file := range []string{"", empty, filepath.Join(dir, "missing")} {
+		if _, err := ReadToken(file); err == nil {
+			t.Errorf("ReadToken(%q) succeeded, want an error", file)
+		}
+	}
+}
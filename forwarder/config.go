@@ -0,0 +1,54 @@
+package forwarder
+
+import (
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config of the alert forwarder, rendered by the operator into a secret with the urls and
+// credentials of the destinations resolved
+type Config struct {
+	Destinations []Destination `json:"destinations"`
+}
+
+type Destination struct {
+	Name        string       `json:"name"`
+	Type        string       `json:"type"`
+	URL         string       `json:"url,omitempty"`
+	Template    string       `json:"template,omitempty"`
+	EventBridge *EventBridge `json:"eventBridge,omitempty"`
+}
+
+type EventBridge struct {
+	Region    string `json:"region"`
+	EventBus  string `json:"eventBus,omitempty"`
+	Source    string `json:"source"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+}
+
+// The config is read on every notification, so that changes of the mounted secret are picked up
+// without a restart
+func loadConfig(path string) (*Config, error) {
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	err = yaml.Unmarshal(source, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) getDestination(name string) *Destination {
+	for i, destination := range c.Destinations {
+		if destination.Name == name {
+			return &c.Destinations[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package forwarder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+)
+
+const (
+	// Message of the googlechat type if the destination has no template
+	DefaultGoogleChatTemplate = `{{ range .Alerts }}*[{{ .Status | toUpper }}] {{ .Labels.alertname }}*{{ with .Annotations.summary }} {{ . }}{{ end }}
+{{ end }}`
+	DefaultEventBridgeSource = "observability-operator"
+	EventBridgeDetailType    = "Alertmanager Notification"
+)
+
+func (s *Server) forward(destination *Destination, notification *Notification, payload []byte) error {
+	switch destination.Type {
+	case v1.AlertForwarderTypeHTTP:
+		return s.forwardHTTP(destination, notification, payload)
+	case v1.AlertForwarderTypeGoogleChat:
+		return s.forwardGoogleChat(destination, notification)
+	case v1.AlertForwarderTypeEventBridge:
+		return s.forwardEventBridge(destination, notification, payload)
+	default:
+		return fmt.Errorf("unsupported destination type %v", destination.Type)
+	}
+}
+
+func render(destination *Destination, text string, notification *Notification) ([]byte, error) {
+	tpl, err := v1.ParseAlertForwarderTemplate(destination.Name, text)
+	if err != nil {
+		return nil, err
+	}
+
+	var result bytes.Buffer
+	err = tpl.Execute(&result, notification)
+	if err != nil {
+		return nil, err
+	}
+	return result.Bytes(), nil
+}
+
+func (s *Server) forwardHTTP(destination *Destination, notification *Notification, payload []byte) error {
+	body := payload
+	if destination.Template != "" {
+		rendered, err := render(destination, destination.Template, notification)
+		if err != nil {
+			return err
+		}
+		body = rendered
+	}
+
+	contentType := "text/plain"
+	if json.Valid(body) {
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, destination.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return s.send(req)
+}
+
+// Posts a text message to a Google Chat space through an incoming webhook
+func (s *Server) forwardGoogleChat(destination *Destination, notification *Notification) error {
+	text := destination.Template
+	if text == "" {
+		text = DefaultGoogleChatTemplate
+	}
+
+	message, err := render(destination, text, notification)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"text": string(message),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, destination.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	return s.send(req)
+}
+
+// Puts the notification as a single event on the event bus, with the payload or the rendered
+// template as the event detail
+func (s *Server) forwardEventBridge(destination *Destination, notification *Notification, payload []byte) error {
+	eventBridge := destination.EventBridge
+	if eventBridge == nil {
+		return fmt.Errorf("destination %v has no eventbridge config", destination.Name)
+	}
+
+	detail := payload
+	if destination.Template != "" {
+		rendered, err := render(destination, destination.Template, notification)
+		if err != nil {
+			return err
+		}
+		detail = rendered
+	}
+	if !json.Valid(detail) {
+		return fmt.Errorf("eventbridge event detail is not valid json")
+	}
+
+	source := eventBridge.Source
+	if source == "" {
+		source = DefaultEventBridgeSource
+	}
+
+	entry := map[string]interface{}{
+		"Source":     source,
+		"DetailType": EventBridgeDetailType,
+		"Detail":     string(detail),
+	}
+	if eventBridge.EventBus != "" {
+		entry["EventBusName"] = eventBridge.EventBus
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"Entries": []interface{}{entry},
+	})
+	if err != nil {
+		return err
+	}
+
+	host := fmt.Sprintf("events.%v.amazonaws.com", eventBridge.Region)
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("https://%v/", host), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Host", host)
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AWSEvents.PutEvents")
+	utils.SignAWSRequest(req, body, "events", utils.AWSCredentials{
+		Region:    eventBridge.Region,
+		AccessKey: eventBridge.AccessKey,
+		SecretKey: eventBridge.SecretKey,
+	}, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code from eventbridge: %v", resp.StatusCode)
+	}
+
+	result := struct {
+		FailedEntryCount int `json:"FailedEntryCount"`
+	}{}
+	err = json.Unmarshal(respBody, &result)
+	if err != nil {
+		return err
+	}
+	if result.FailedEntryCount > 0 {
+		return fmt.Errorf("eventbridge rejected the event: %v", string(respBody))
+	}
+	return nil
+}
+
+func (s *Server) send(req *http.Request) error {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code from destination: %v", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package forwarder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+)
+
+// Alert of an Alertmanager webhook notification
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// Notification is the payload of the Alertmanager webhook receiver, the data of the destination templates
+type Notification struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []Alert           `json:"alerts"`
+}
+
+// Server receives the notifications of Alertmanager on /alerts/<destination> and forwards them to
+// the destination. Failures are returned to Alertmanager, which retries the notification
+type Server struct {
+	configPath string
+	httpClient *http.Client
+	logger     logr.Logger
+}
+
+func NewServer(configPath string, logger logr.Logger) *Server {
+	return &Server{
+		configPath: configPath,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		logger:     logger,
+	}
+}
+
+// Runs the alert forwarder until the listener fails. Only Alertmanager knows the token, other pods
+// could send messages to the destinations otherwise
+func Run(addr string, configPath string, tokenFile string, logger logr.Logger) error {
+	token, err := ReadToken(tokenFile)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(model.AlertForwarderAlertsPath, RequireToken(token, NewServer(configPath, logger)))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	logger.Info("starting alert forwarder", "addr", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, model.AlertForwarderAlertsPath)
+	config, err := loadConfig(s.configPath)
+	if err != nil {
+		s.logger.Error(err, "error reading alert forwarder config")
+		http.Error(w, "error reading config", http.StatusInternalServerError)
+		return
+	}
+
+	destination := config.getDestination(name)
+	if destination == nil {
+		http.Error(w, "unknown destination", http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "error reading notification", http.StatusBadRequest)
+		return
+	}
+
+	notification := &Notification{}
+	err = json.Unmarshal(body, notification)
+	if err != nil {
+		http.Error(w, "invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	err = s.forward(destination, notification, body)
+	if err != nil {
+		s.logger.Error(err, "error forwarding notification", "destination", name)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	s.logger.V(1).Info("notification forwarded", "destination", name, "alerts", len(notification.Alerts))
+	w.WriteHeader(http.StatusOK)
+}
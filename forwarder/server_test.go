@@ -0,0 +1,365 @@
+package forwarder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const testNotification = `{
+	"version": "4",
+	"status": "firing",
+	"receiver": "forwarder",
+	"commonLabels": {"alertname": "TargetDown"},
+	"alerts": [
+		{"status": "firing", "labels": {"alertname": "TargetDown", "job": "api"}, "annotations": {"summary": "api is down"}},
+		{"status": "firing", "labels": {"alertname": "TargetDown", "job": "web"}}
+	]
+}`
+
+// Request received by a destination
+type received struct {
+	path        string
+	contentType string
+	header      http.Header
+	body        string
+}
+
+// Destination that records the requests and answers with the status code
+func newDestination(t *testing.T, code int, response string) (*httptest.Server, *[]received) {
+	var requests []received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("error reading request: %v", err)
+		}
+		requests = append(requests, received{
+			path:        r.URL.Path,
+			contentType: r.Header.Get("Content-Type"),
+			header:      r.Header,
+			body:        string(body),
+		})
+		w.WriteHeader(code)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTestServer(t *testing.T, config *Config) *Server {
+	source, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err = ioutil.WriteFile(path, source, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewServer(path, log.NullLogger{})
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	destination, requests := newDestination(t, http.StatusOK, "")
+	failing, _ := newDestination(t, http.StatusInternalServerError, "")
+
+	server := newTestServer(t, &Config{
+		Destinations: []Destination{
+			{Name: "raw", Type: v1.AlertForwarderTypeHTTP, URL: destination.URL + "/raw"},
+			{Name: "text", Type: v1.AlertForwarderTypeHTTP, URL: destination.URL + "/text", Template: "{{ len .Alerts }} alerts {{ .Status | toUpper }}"},
+			{Name: "json", Type: v1.AlertForwarderTypeHTTP, URL: destination.URL + "/json", Template: `{"alert": "{{ .CommonLabels.alertname }}"}`},
+			{Name: "failing", Type: v1.AlertForwarderTypeHTTP, URL: failing.URL},
+			{Name: "broken", Type: v1.AlertForwarderTypeHTTP, URL: destination.URL, Template: "{{ .Alerts"},
+			{Name: "unsupported", Type: "sms", URL: destination.URL},
+		},
+	})
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		body            string
+		wantCode        int
+		wantPath        string
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:     "only posts are accepted",
+			method:   http.MethodGet,
+			path:     "/alerts/raw",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:     "unknown destination",
+			method:   http.MethodPost,
+			path:     "/alerts/unknown",
+			body:     testNotification,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "invalid notification",
+			method:   http.MethodPost,
+			path:     "/alerts/raw",
+			body:     "{",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:            "payload is forwarded without template",
+			method:          http.MethodPost,
+			path:            "/alerts/raw",
+			body:            testNotification,
+			wantCode:        http.StatusOK,
+			wantPath:        "/raw",
+			wantContentType: "application/json",
+			wantBody:        testNotification,
+		},
+		{
+			name:            "template rendered as text",
+			method:          http.MethodPost,
+			path:            "/alerts/text",
+			body:            testNotification,
+			wantCode:        http.StatusOK,
+			wantPath:        "/text",
+			wantContentType: "text/plain",
+			wantBody:        "2 alerts FIRING",
+		},
+		{
+			name:            "template rendered as json",
+			method:          http.MethodPost,
+			path:            "/alerts/json",
+			body:            testNotification,
+			wantCode:        http.StatusOK,
+			wantPath:        "/json",
+			wantContentType: "application/json",
+			wantBody:        `{"alert": "TargetDown"}`,
+		},
+		{
+			name:     "failures of the destination are returned to alertmanager",
+			method:   http.MethodPost,
+			path:     "/alerts/failing",
+			body:     testNotification,
+			wantCode: http.StatusBadGateway,
+		},
+		{
+			name:     "invalid template",
+			method:   http.MethodPost,
+			path:     "/alerts/broken",
+			body:     testNotification,
+			wantCode: http.StatusBadGateway,
+		},
+		{
+			name:     "unsupported destination type",
+			method:   http.MethodPost,
+			path:     "/alerts/unsupported",
+			body:     testNotification,
+			wantCode: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*requests = nil
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("ServeHTTP() code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if tt.wantPath == "" {
+				if len(*requests) != 0 {
+					t.Errorf("ServeHTTP() sent %v requests to the destination, want none", len(*requests))
+				}
+				return
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("ServeHTTP() sent %v requests to the destination, want 1", len(*requests))
+			}
+			got := (*requests)[0]
+			if got.path != tt.wantPath || got.contentType != tt.wantContentType || got.body != tt.wantBody {
+				t.Errorf("destination received %v %v %q, want %v %v %q", got.path, got.contentType, got.body, tt.wantPath, tt.wantContentType, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestServer_ServeHTTP_missingConfig(t *testing.T) {
+	server := NewServer(filepath.Join(t.TempDir(), "missing.yaml"), log.NullLogger{})
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/alerts/raw", strings.NewReader(testNotification)))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("ServeHTTP() code = %v, want %v", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestServer_forwardGoogleChat(t *testing.T) {
+	destination, requests := newDestination(t, http.StatusOK, "")
+
+	tests := []struct {
+		name     string
+		template string
+		wantText string
+	}{
+		{
+			name:     "default template",
+			wantText: "*[FIRING] TargetDown* api is down\n*[FIRING] TargetDown*\n",
+		},
+		{
+			name:     "custom template",
+			template: "{{ .CommonLabels.alertname }} in {{ len .Alerts }} jobs",
+			wantText: "TargetDown in 2 jobs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*requests = nil
+			server := newTestServer(t, &Config{
+				Destinations: []Destination{
+					{Name: "chat", Type: v1.AlertForwarderTypeGoogleChat, URL: destination.URL, Template: tt.template},
+				},
+			})
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/alerts/chat", strings.NewReader(testNotification)))
+			if w.Code != http.StatusOK {
+				t.Fatalf("ServeHTTP() code = %v, want %v", w.Code, http.StatusOK)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("ServeHTTP() sent %v requests to the destination, want 1", len(*requests))
+			}
+
+			message := map[string]string{}
+			err := json.Unmarshal([]byte((*requests)[0].body), &message)
+			if err != nil {
+				t.Fatalf("google chat message is not valid json: %v", err)
+			}
+			if message["text"] != tt.wantText {
+				t.Errorf("google chat text = %q, want %q", message["text"], tt.wantText)
+			}
+			if contentType := (*requests)[0].contentType; contentType != "application/json; charset=UTF-8" {
+				t.Errorf("google chat content type = %v", contentType)
+			}
+		})
+	}
+}
+
+// Sends the requests for any host to the test server instead
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (r *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestServer_forwardEventBridge(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventBridge *EventBridge
+		template    string
+		response    string
+		wantCode    int
+		wantEntry   map[string]interface{}
+	}{
+		{
+			name:        "payload as detail",
+			eventBridge: &EventBridge{Region: "eu-west-1", AccessKey: "key", SecretKey: "secret"},
+			response:    `{"FailedEntryCount": 0}`,
+			wantCode:    http.StatusOK,
+			wantEntry: map[string]interface{}{
+				"Source":     DefaultEventBridgeSource,
+				"DetailType": EventBridgeDetailType,
+			},
+		},
+		{
+			name:        "rendered detail on a custom bus",
+			eventBridge: &EventBridge{Region: "eu-west-1", EventBus: "alerts", Source: "cluster-a", AccessKey: "key", SecretKey: "secret"},
+			template:    `{"alert": "{{ .CommonLabels.alertname }}"}`,
+			response:    `{"FailedEntryCount": 0}`,
+			wantCode:    http.StatusOK,
+			wantEntry: map[string]interface{}{
+				"Source":       "cluster-a",
+				"DetailType":   EventBridgeDetailType,
+				"EventBusName": "alerts",
+				"Detail":       `{"alert": "TargetDown"}`,
+			},
+		},
+		{
+			name:        "rendered detail is no json",
+			eventBridge: &EventBridge{Region: "eu-west-1", AccessKey: "key", SecretKey: "secret"},
+			template:    "{{ .Status }}",
+			wantCode:    http.StatusBadGateway,
+		},
+		{
+			name:        "rejected entries",
+			eventBridge: &EventBridge{Region: "eu-west-1", AccessKey: "key", SecretKey: "secret"},
+			response:    `{"FailedEntryCount": 1, "Entries": [{"ErrorCode": "AccessDenied"}]}`,
+			wantCode:    http.StatusBadGateway,
+		},
+		{
+			name:     "missing eventbridge config",
+			wantCode: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destination, requests := newDestination(t, http.StatusOK, tt.response)
+			target, err := url.Parse(destination.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			server := newTestServer(t, &Config{
+				Destinations: []Destination{
+					{Name: "bus", Type: v1.AlertForwarderTypeEventBridge, EventBridge: tt.eventBridge, Template: tt.template},
+				},
+			})
+			server.httpClient = &http.Client{Transport: &redirectTransport{target: target}}
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/alerts/bus", strings.NewReader(testNotification)))
+			if w.Code != tt.wantCode {
+				t.Errorf("ServeHTTP() code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if tt.wantEntry == nil {
+				return
+			}
+
+			if len(*requests) != 1 {
+				t.Fatalf("ServeHTTP() sent %v requests to eventbridge, want 1", len(*requests))
+			}
+			got := (*requests)[0]
+			if target := got.header.Get("X-Amz-Target"); target != "AWSEvents.PutEvents" {
+				t.Errorf("X-Amz-Target = %v", target)
+			}
+			if auth := got.header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=key/") || !strings.Contains(auth, "/eu-west-1/events/aws4_request") {
+				t.Errorf("Authorization = %v", auth)
+			}
+
+			body := struct {
+				Entries []map[string]interface{} `json:"Entries"`
+			}{}
+			err = json.Unmarshal([]byte(got.body), &body)
+			if err != nil || len(body.Entries) != 1 {
+				t.Fatalf("invalid PutEvents request %v: %v", got.body, err)
+			}
+			if _, ok := tt.wantEntry["Detail"]; !ok {
+				tt.wantEntry["Detail"] = testNotification
+			}
+			for key, want := range tt.wantEntry {
+				if body.Entries[0][key] != want {
+					t.Errorf("entry %v = %v, want %v", key, body.Entries[0][key], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/forwarder"
+)
+
+// Targets of the Grafana datasource
+const (
+	TargetTransitions = "transitions"
+	TargetFiring      = "firing"
+)
+
+// Server receives the notifications of Alertmanager and serves the recorded transitions, as JSON
+// on /api/v1 and in the format of the Grafana SimpleJSON datasource on /grafana
+type Server struct {
+	store  *Store
+	logger logr.Logger
+}
+
+// Runs the alert history until the listener fails. Alertmanager records and Grafana queries the
+// transitions with the token, other pods could forge or read the alerts otherwise
+func Run(addr string, dir string, retention time.Duration, tokenFile string, logger logr.Logger) error {
+	token, err := forwarder.ReadToken(tokenFile)
+	if err != nil {
+		return err
+	}
+	store, err := NewStore(dir, retention)
+	if err != nil {
+		return err
+	}
+	server := &Server{store: store, logger: logger}
+
+	api := http.NewServeMux()
+	api.HandleFunc(model.AlertHistoryAlertsPath, server.serveAlerts)
+	api.HandleFunc("/api/v1/transitions", server.serveTransitions)
+	api.HandleFunc("/api/v1/firing", server.serveFiring)
+	api.HandleFunc("/grafana/", server.serveGrafana)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", forwarder.RequireToken(token, api))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	logger.Info("starting alert history", "addr", addr, "dir", dir, "retention", retention)
+	return http.ListenAndServe(addr, mux)
+}
+
+// Failures are returned to Alertmanager, which retries the notification
+func (s *Server) serveAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	notification := &forwarder.Notification{}
+	err := json.NewDecoder(r.Body).Decode(notification)
+	if err != nil {
+		http.Error(w, "invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	recorded, err := s.store.Record(notification.Alerts, time.Now())
+	if err != nil {
+		s.logger.Error(err, "error recording alert transitions")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if recorded > 0 {
+		s.logger.V(1).Info("recorded alert transitions", "transitions", recorded)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET /api/v1/transitions?from=&to=&match=severity=critical, from and to default to the last day
+func (s *Server) serveTransitions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	to, err := parseTime(query.Get("to"), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	from, err := parseTime(query.Get("from"), to.Add(-24*time.Hour))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	match, err := parseMatch(query["match"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transitions, err := s.store.Query(from, to, match)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, transitions)
+}
+
+// GET /api/v1/firing?at=&match=severity=critical, the alerts that were firing at the time
+func (s *Server) serveFiring(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	at, err := parseTime(query.Get("at"), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	match, err := parseMatch(query["match"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transitions, err := s.store.Firing(at, match)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, transitions)
+}
+
+type grafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type grafanaRequest struct {
+	Range   grafanaRange `json:"range"`
+	Targets []struct {
+		Target string `json:"target"`
+	} `json:"targets"`
+	Annotation struct {
+		Name  string `json:"name"`
+		Query string `json:"query"`
+	} `json:"annotation"`
+}
+
+// The SimpleJSON datasource tests the connection with a GET of the root, lists the targets with
+// search and queries tables and annotations
+func (s *Server) serveGrafana(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/grafana")
+	if path == "" || path == "/" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	request := &grafanaRequest{}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(request) != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	switch path {
+	case "/search":
+		writeJSON(w, []string{TargetTransitions, TargetFiring})
+	case "/query":
+		var tables []interface{}
+		for _, target := range request.Targets {
+			transitions, err := s.queryTarget(target.Target, request.Range)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			tables = append(tables, getTable(transitions))
+		}
+		writeJSON(w, tables)
+	case "/annotations":
+		transitions, err := s.queryTarget(request.Annotation.Query, request.Range)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, getAnnotations(request.Annotation.Name, request.Annotation.Query, transitions))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Targets are transitions or firing, optionally followed by a match, e.g. firing severity=critical
+func (s *Server) queryTarget(target string, timeRange grafanaRange) ([]Transition, error) {
+	fields := strings.Fields(target)
+	if len(fields) == 0 {
+		fields = []string{TargetTransitions}
+	}
+	var matchers []string
+	if len(fields) > 1 {
+		matchers = strings.Split(fields[1], ",")
+	}
+	match, err := parseMatch(matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	switch fields[0] {
+	case TargetTransitions:
+		return s.store.Query(timeRange.From, timeRange.To, match)
+	case TargetFiring:
+		return s.store.Firing(timeRange.To, match)
+	default:
+		return nil, fmt.Errorf("unknown target %v", fields[0])
+	}
+}
+
+func getTable(transitions []Transition) interface{} {
+	rows := [][]interface{}{}
+	for i := len(transitions) - 1; i >= 0; i-- {
+		t := transitions[i]
+		rows = append(rows, []interface{}{
+			t.Time.UnixNano() / int64(time.Millisecond),
+			t.Labels["alertname"],
+			t.Status,
+			t.Labels["severity"],
+			t.Labels["namespace"],
+			formatLabels(t.Labels),
+			t.Annotations["summary"] + t.Annotations["message"],
+		})
+	}
+
+	var columns []map[string]string
+	for _, column := range []string{"Time", "Alert", "Status", "Severity", "Namespace", "Labels", "Summary"} {
+		columnType := "string"
+		if column == "Time" {
+			columnType = "time"
+		}
+		columns = append(columns, map[string]string{"text": column, "type": columnType})
+	}
+	return map[string]interface{}{
+		"type":    "table",
+		"columns": columns,
+		"rows":    rows,
+	}
+}
+
+func getAnnotations(name string, query string, transitions []Transition) []interface{} {
+	result := []interface{}{}
+	for _, t := range transitions {
+		result = append(result, map[string]interface{}{
+			"annotation": map[string]interface{}{"name": name, "query": query, "enable": true},
+			"time":       t.Time.UnixNano() / int64(time.Millisecond),
+			"title":      fmt.Sprintf("%v %v", t.Labels["alertname"], t.Status),
+			"tags":       []string{t.Status, t.Labels["severity"]},
+			"text":       formatLabels(t.Labels),
+		})
+	}
+	return result
+}
+
+func formatLabels(labels map[string]string) string {
+	var pairs []string
+	for k, v := range labels {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// Times are RFC 3339 or unix seconds
+func parseTime(value string, defaultValue time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %v, expected RFC 3339 or unix seconds", value)
+	}
+	return t, nil
+}
+
+func parseMatch(matchers []string) (map[string]string, error) {
+	match := map[string]string{}
+	for _, matcher := range matchers {
+		parts := strings.SplitN(matcher, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid match %v, expected label=value", matcher)
+		}
+		match[parts[0]] = parts[1]
+	}
+	return match, nil
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,347 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat-developer/observability-operator/v3/forwarder"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const retention = 30 * 24 * time.Hour
+
+func newTestServer(t *testing.T, dir string) *Server {
+	store, err := NewStore(dir, retention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Server{store: store, logger: log.NullLogger{}}
+}
+
+func notify(t *testing.T, s *Server, alerts ...forwarder.Alert) {
+	body, err := json.Marshal(&forwarder.Notification{Alerts: alerts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	s.serveAlerts(w, httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(string(body))))
+	if w.Code != http.StatusOK {
+		t.Fatalf("serveAlerts() code = %v, want %v", w.Code, http.StatusOK)
+	}
+}
+
+func alert(fingerprint string, status string, severity string, startsAt time.Time, endsAt time.Time) forwarder.Alert {
+	return forwarder.Alert{
+		Status:      status,
+		Fingerprint: fingerprint,
+		Labels:      map[string]string{"alertname": "Alert" + strings.ToUpper(fingerprint), "severity": severity},
+		Annotations: map[string]string{"summary": "summary of " + fingerprint},
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+	}
+}
+
+// Fingerprints and statuses of the transitions
+func summarize(transitions []Transition) []string {
+	result := []string{}
+	for _, t := range transitions {
+		result = append(result, t.Fingerprint+" "+t.Status)
+	}
+	return result
+}
+
+func TestServer_serveAlerts(t *testing.T) {
+	s := newTestServer(t, t.TempDir())
+
+	w := httptest.NewRecorder()
+	s.serveAlerts(w, httptest.NewRequest(http.MethodGet, "/alerts", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("serveAlerts() code = %v, want %v", w.Code, http.StatusMethodNotAllowed)
+	}
+
+	w = httptest.NewRecorder()
+	s.serveAlerts(w, httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader("{")))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("serveAlerts() code = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	start := time.Now().UTC().Truncate(time.Minute).Add(-2 * time.Hour)
+	notify(t, s, alert("a", "firing", "critical", start, time.Time{}), alert("", "firing", "critical", start, time.Time{}))
+	// Repeated notifications of a firing alert are no transitions
+	notify(t, s, alert("a", "firing", "critical", start, time.Time{}))
+	notify(t, s, alert("b", "firing", "warning", start.Add(10*time.Minute), time.Time{}))
+	notify(t, s, alert("a", "resolved", "critical", start, start.Add(30*time.Minute)))
+
+	transitions, err := s.store.Query(start.Add(-time.Hour), time.Now(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a firing", "b firing", "a resolved"}
+	if got := summarize(transitions); !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded transitions = %v, want %v", got, want)
+	}
+	if !transitions[2].Time.Equal(start.Add(30 * time.Minute)) {
+		t.Errorf("resolved transition time = %v, want the end of the alert", transitions[2].Time)
+	}
+
+	// The last status is restored, so the firing alert is not recorded again after a restart
+	s = newTestServer(t, s.store.dir)
+	notify(t, s, alert("b", "firing", "warning", start.Add(10*time.Minute), time.Time{}))
+	transitions, err = s.store.Query(start.Add(-time.Hour), time.Now(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transitions) != 3 {
+		t.Errorf("recorded %v transitions after the restart, want 3", len(transitions))
+	}
+}
+
+func TestServer_serveTransitions(t *testing.T) {
+	s := newTestServer(t, t.TempDir())
+	start := time.Now().UTC().Truncate(time.Minute).Add(-2 * time.Hour)
+	notify(t, s,
+		alert("a", "firing", "critical", start, time.Time{}),
+		alert("b", "firing", "warning", start.Add(10*time.Minute), time.Time{}),
+	)
+	notify(t, s, alert("a", "resolved", "critical", start, start.Add(30*time.Minute)))
+
+	tests := []struct {
+		name     string
+		handler  func(http.ResponseWriter, *http.Request)
+		query    url.Values
+		wantCode int
+		want     []string
+	}{
+		{
+			name:     "transitions of the last day",
+			handler:  s.serveTransitions,
+			wantCode: http.StatusOK,
+			want:     []string{"a firing", "b firing", "a resolved"},
+		},
+		{
+			name:     "transitions between unix seconds",
+			handler:  s.serveTransitions,
+			query:    url.Values{"from": {fmt.Sprint(start.Add(5 * time.Minute).Unix())}, "to": {fmt.Sprint(start.Add(20 * time.Minute).Unix())}},
+			wantCode: http.StatusOK,
+			want:     []string{"b firing"},
+		},
+		{
+			name:     "transitions between RFC 3339 times",
+			handler:  s.serveTransitions,
+			query:    url.Values{"from": {start.Add(-time.Minute).Format(time.RFC3339)}, "to": {start.Add(time.Minute).Format(time.RFC3339)}},
+			wantCode: http.StatusOK,
+			want:     []string{"a firing"},
+		},
+		{
+			name:     "matching transitions",
+			handler:  s.serveTransitions,
+			query:    url.Values{"match": {"severity=critical"}},
+			wantCode: http.StatusOK,
+			want:     []string{"a firing", "a resolved"},
+		},
+		{
+			name:     "invalid time",
+			handler:  s.serveTransitions,
+			query:    url.Values{"from": {"yesterday"}},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid match",
+			handler:  s.serveTransitions,
+			query:    url.Values{"match": {"critical"}},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "firing now",
+			handler:  s.serveFiring,
+			wantCode: http.StatusOK,
+			want:     []string{"b firing"},
+		},
+		{
+			name:     "firing at a time",
+			handler:  s.serveFiring,
+			query:    url.Values{"at": {fmt.Sprint(start.Add(20 * time.Minute).Unix())}},
+			wantCode: http.StatusOK,
+			want:     []string{"a firing", "b firing"},
+		},
+		{
+			name:     "matching firing alerts",
+			handler:  s.serveFiring,
+			query:    url.Values{"at": {fmt.Sprint(start.Add(20 * time.Minute).Unix())}, "match": {"severity=critical"}},
+			wantCode: http.StatusOK,
+			want:     []string{"a firing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, httptest.NewRequest(http.MethodGet, "/api/v1/transitions?"+tt.query.Encode(), nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var transitions []Transition
+			err := json.Unmarshal(w.Body.Bytes(), &transitions)
+			if err != nil {
+				t.Fatalf("invalid response %v: %v", w.Body.String(), err)
+			}
+			if got := summarize(transitions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("transitions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_serveGrafana(t *testing.T) {
+	s := newTestServer(t, t.TempDir())
+	start := time.Now().UTC().Truncate(time.Minute).Add(-2 * time.Hour)
+	notify(t, s,
+		alert("a", "firing", "critical", start, time.Time{}),
+		alert("b", "firing", "warning", start.Add(10*time.Minute), time.Time{}),
+	)
+	notify(t, s, alert("a", "resolved", "critical", start, start.Add(30*time.Minute)))
+
+	timeRange := fmt.Sprintf(`"range": {"from": %q, "to": %q}`, start.Add(-time.Hour).Format(time.RFC3339), time.Now().UTC().Format(time.RFC3339))
+	ms := func(t time.Time) float64 {
+		return float64(t.UnixNano() / int64(time.Millisecond))
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+		want     interface{}
+	}{
+		{
+			name:     "connection test",
+			method:   http.MethodGet,
+			path:     "/grafana/",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "search",
+			method:   http.MethodPost,
+			path:     "/grafana/search",
+			body:     `{"target": ""}`,
+			wantCode: http.StatusOK,
+			want:     []interface{}{TargetTransitions, TargetFiring},
+		},
+		{
+			name:     "query of the transitions, newest first",
+			method:   http.MethodPost,
+			path:     "/grafana/query",
+			body:     fmt.Sprintf(`{%v, "targets": [{"target": "transitions severity=critical"}]}`, timeRange),
+			wantCode: http.StatusOK,
+			want: []interface{}{
+				map[string]interface{}{
+					"type": "table",
+					"columns": []interface{}{
+						map[string]interface{}{"text": "Time", "type": "time"},
+						map[string]interface{}{"text": "Alert", "type": "string"},
+						map[string]interface{}{"text": "Status", "type": "string"},
+						map[string]interface{}{"text": "Severity", "type": "string"},
+						map[string]interface{}{"text": "Namespace", "type": "string"},
+						map[string]interface{}{"text": "Labels", "type": "string"},
+						map[string]interface{}{"text": "Summary", "type": "string"},
+					},
+					"rows": []interface{}{
+						[]interface{}{ms(start.Add(30 * time.Minute)), "AlertA", "resolved", "critical", "", "alertname=AlertA, severity=critical", "summary of a"},
+						[]interface{}{ms(start), "AlertA", "firing", "critical", "", "alertname=AlertA, severity=critical", "summary of a"},
+					},
+				},
+			},
+		},
+		{
+			name:     "annotations of the firing alerts",
+			method:   http.MethodPost,
+			path:     "/grafana/annotations",
+			body:     fmt.Sprintf(`{%v, "annotation": {"name": "alerts", "query": "firing"}}`, timeRange),
+			wantCode: http.StatusOK,
+			want: []interface{}{
+				map[string]interface{}{
+					"annotation": map[string]interface{}{"name": "alerts", "query": "firing", "enable": true},
+					"time":       ms(start.Add(10 * time.Minute)),
+					"title":      "AlertB firing",
+					"tags":       []interface{}{"firing", "warning"},
+					"text":       "alertname=AlertB, severity=warning",
+				},
+			},
+		},
+		{
+			name:     "unknown target",
+			method:   http.MethodPost,
+			path:     "/grafana/query",
+			body:     fmt.Sprintf(`{%v, "targets": [{"target": "silences"}]}`, timeRange),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "queries are posted",
+			method:   http.MethodGet,
+			path:     "/grafana/query",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown path",
+			method:   http.MethodPost,
+			path:     "/grafana/tag-keys",
+			body:     "{}",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.serveGrafana(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.wantCode {
+				t.Fatalf("serveGrafana() code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if tt.want == nil {
+				return
+			}
+
+			var got interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &got)
+			if err != nil {
+				t.Fatalf("invalid response %v: %v", w.Body.String(), err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("serveGrafana() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_prune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC()
+	old := filepath.Join(dir, filePrefix+now.Add(-retention-48*time.Hour).Format(dayLayout)+fileSuffix)
+	err := ioutil.WriteFile(old, []byte("{}\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestServer(t, dir)
+	notify(t, s, alert("a", "firing", "critical", now.Add(-time.Hour), time.Time{}))
+
+	if _, err = os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("file of a day before the retention was not deleted: %v", err)
+	}
+	if _, err = os.Stat(s.store.path(now.Add(-time.Hour))); err != nil {
+		t.Errorf("file of the transition is missing: %v", err)
+	}
+}
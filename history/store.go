@@ -0,0 +1,265 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redhat-developer/observability-operator/v3/forwarder"
+)
+
+const (
+	filePrefix = "transitions-"
+	fileSuffix = ".jsonl"
+	dayLayout  = "2006-01-02"
+)
+
+// Transition is a change of the state of an alert, from resolved to firing or back
+type Transition struct {
+	Time        time.Time         `json:"time"`
+	Fingerprint string            `json:"fingerprint"`
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt,omitempty"`
+}
+
+// Store appends the transitions to one file per day and deletes the files that fell out of the
+// retention. Alertmanager repeats the notifications of firing alerts, the store only records an
+// alert again when its status changed
+type Store struct {
+	dir       string
+	retention time.Duration
+
+	mu sync.Mutex
+	// Last status by fingerprint
+	states map[string]string
+	pruned time.Time
+}
+
+// NewStore opens the store in the directory and restores the last status of the alerts from the
+// transitions within the retention
+func NewStore(dir string, retention time.Duration) (*Store, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		dir:       dir,
+		retention: retention,
+		states:    map[string]string{},
+	}
+	transitions, err := s.Query(time.Now().Add(-retention), time.Now(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range transitions {
+		s.states[t.Fingerprint] = t.Status
+	}
+	return s, nil
+}
+
+// Record appends the alerts of a notification of which the status changed, and returns how many
+// transitions were recorded
+func (s *Store) Record(alerts []forwarder.Alert, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var transitions []Transition
+	for _, alert := range alerts {
+		if alert.Fingerprint == "" || s.states[alert.Fingerprint] == alert.Status {
+			continue
+		}
+		// Resolved alerts record when they resolved, firing ones when they started
+		at := alert.StartsAt
+		if alert.Status == "resolved" && !alert.EndsAt.IsZero() {
+			at = alert.EndsAt
+		}
+		if at.IsZero() {
+			at = now
+		}
+		transitions = append(transitions, Transition{
+			Time:        at.UTC(),
+			Fingerprint: alert.Fingerprint,
+			Status:      alert.Status,
+			Labels:      alert.Labels,
+			Annotations: alert.Annotations,
+			StartsAt:    alert.StartsAt,
+			EndsAt:      alert.EndsAt,
+		})
+	}
+
+	for _, t := range transitions {
+		err := s.append(t)
+		if err != nil {
+			return 0, err
+		}
+		s.states[t.Fingerprint] = t.Status
+	}
+
+	if now.Sub(s.pruned) > time.Hour {
+		err := s.prune(now)
+		if err != nil {
+			return len(transitions), err
+		}
+		s.pruned = now
+	}
+	return len(transitions), nil
+}
+
+func (s *Store) append(t Transition) error {
+	line, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path(t.Time), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func (s *Store) path(t time.Time) string {
+	return filepath.Join(s.dir, filePrefix+t.UTC().Format(dayLayout)+fileSuffix)
+}
+
+// Deletes the files of the days before the retention
+func (s *Store) prune(now time.Time) error {
+	days, err := s.days()
+	if err != nil {
+		return err
+	}
+
+	oldest := now.Add(-s.retention).UTC().Format(dayLayout)
+	for _, day := range days {
+		if day < oldest {
+			err = os.Remove(filepath.Join(s.dir, filePrefix+day+fileSuffix))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Sorted days of the files in the store
+func (s *Store) days() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []string
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasPrefix(name, filePrefix) && strings.HasSuffix(name, fileSuffix) {
+			days = append(days, strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix))
+		}
+	}
+	sort.Strings(days)
+	return days, nil
+}
+
+// Query returns the transitions between from and to of the alerts that have all the labels of
+// match, sorted by time
+func (s *Store) Query(from time.Time, to time.Time, match map[string]string) ([]Transition, error) {
+	days, err := s.days()
+	if err != nil {
+		return nil, err
+	}
+
+	first, last := from.UTC().Format(dayLayout), to.UTC().Format(dayLayout)
+	var result []Transition
+	for _, day := range days {
+		if day < first || day > last {
+			continue
+		}
+		err = s.read(filepath.Join(s.dir, filePrefix+day+fileSuffix), func(t Transition) {
+			if !t.Time.Before(from) && !t.Time.After(to) && matches(t.Labels, match) {
+				result = append(result, t)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result, nil
+}
+
+// Firing returns the last firing transition of every alert that was firing at the time
+func (s *Store) Firing(at time.Time, match map[string]string) ([]Transition, error) {
+	transitions, err := s.Query(at.Add(-s.retention), at, match)
+	if err != nil {
+		return nil, err
+	}
+
+	last := map[string]Transition{}
+	for _, t := range transitions {
+		last[t.Fingerprint] = t
+	}
+
+	var result []Transition
+	for _, t := range last {
+		if t.Status == "firing" {
+			result = append(result, t)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result, nil
+}
+
+// Lines that fail to decode, e.g. one cut short by a crash, are skipped
+func (s *Store) read(path string, fn func(Transition)) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		t := Transition{}
+		if json.Unmarshal(scanner.Bytes(), &t) == nil {
+			fn(t)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("error reading %v: %v", path, err)
+	}
+	return nil
+}
+
+func matches(labels map[string]string, match map[string]string) bool {
+	for k, v := range match {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -18,27 +18,45 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"os"
+	"time"
+	// Time zones of the mute time intervals don't depend on the tzdata of the image
+	_ "time/tzdata"
 
 	"github.com/go-logr/logr"
 	grafana "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
 	configv1 "github.com/openshift/api/config/v1"
+	consolev1 "github.com/openshift/api/console/v1"
 	projectv1 "github.com/openshift/api/project/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	coreosv1 "github.com/operator-framework/api/pkg/operators/v1"
 	coreosv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	apiv1 "github.com/redhat-developer/observability-operator/v3/api/v1"
 	"github.com/redhat-developer/observability-operator/v3/controllers"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/fakes"
+	"github.com/redhat-developer/observability-operator/v3/forwarder"
+	"github.com/redhat-developer/observability-operator/v3/history"
+	"github.com/redhat-developer/observability-operator/v3/preflight"
 	"github.com/redhat-developer/observability-operator/v3/runners"
+	"github.com/redhat-developer/observability-operator/v3/ticketing"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -56,6 +74,8 @@ func init() {
 
 	utilruntime.Must(routev1.AddToScheme(scheme))
 
+	utilruntime.Must(consolev1.AddToScheme(scheme))
+
 	utilruntime.Must(prometheusv1.AddToScheme(scheme))
 
 	utilruntime.Must(coreosv1alpha1.AddToScheme(scheme))
@@ -71,33 +91,227 @@ func init() {
 
 func main() {
 	var metricsAddr string
+	var probeAddr string
 	var enableLeaderElection bool
+	var leaseDuration time.Duration
+	var renewDeadline time.Duration
+	var retryPeriod time.Duration
+	var reconcileFailurePeriods int
+	var livenessStallTimeout time.Duration
 	var disableWebhooks bool
+	var logLevel string
+	var alertForwarderConfig string
+	var alertForwarderAddr string
+	var alertForwarderTokenFile string
+	var alertTicketingConfig string
+	var alertTicketingAddr string
+	var alertTicketingTokenFile string
+	var alertHistoryDir string
+	var alertHistoryAddr string
+	var alertHistoryTokenFile string
+	var alertHistoryRetentionDays int
+	var diagnosticsAddr string
+	var enablePprof bool
+	var simulateExternal bool
+	var runPreflight bool
+	var simulateExternalAddr string
+	var simulateExternalFailureRate float64
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-addr", ":8081", "The address the health and readiness probes bind to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second,
+		"Duration that standby replicas wait before taking over the leader lease of an unresponsive leader.")
+	flag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second,
+		"Duration that the leader retries renewing the leader lease before giving it up.")
+	flag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second,
+		"Duration between attempts to acquire or renew the leader lease.")
+	flag.IntVar(&reconcileFailurePeriods, "reconcile-failure-periods", 30,
+		"Number of reconcile periods after which a CR failing to reconcile is reported by the observability_operator_reconcile_failing metric.")
+	flag.DurationVar(&livenessStallTimeout, "liveness-stall-timeout", 10*time.Minute,
+		"Duration after which a reconcile that doesn't finish, or no reconcile finishing, fails the liveness probe.")
 	flag.BoolVar(&disableWebhooks, "disable-webhooks", false, "disable webhooks for running on local environment")
+	flag.StringVar(&logLevel, "log-level", "info", "Log level, one of debug, info or error. "+
+		"Can be changed at runtime in the observability-operator-logging config map.")
+	flag.StringVar(&alertForwarderConfig, "alert-forwarder-config", "",
+		"Run the alert forwarder with this config file instead of the operator.")
+	flag.StringVar(&alertForwarderAddr, "alert-forwarder-addr", ":9095", "The address the alert forwarder binds to.")
+	flag.StringVar(&alertForwarderTokenFile, "alert-forwarder-token-file", "", "The file with the bearer token "+
+		"Alertmanager authenticates to the alert forwarder with.")
+	flag.StringVar(&alertTicketingConfig, "alert-ticketing-config", "",
+		"Run the alert ticketing bridge with this config file instead of the operator.")
+	flag.StringVar(&alertTicketingAddr, "alert-ticketing-addr", ":9096", "The address the alert ticketing bridge binds to.")
+	flag.StringVar(&alertTicketingTokenFile, "alert-ticketing-token-file", "", "The file with the bearer token "+
+		"Alertmanager authenticates to the alert ticketing bridge with.")
+	flag.StringVar(&alertHistoryDir, "alert-history-dir", "",
+		"Run the alert history with the transitions stored in this directory instead of the operator.")
+	flag.StringVar(&alertHistoryAddr, "alert-history-addr", ":9098", "The address the alert history binds to.")
+	flag.StringVar(&alertHistoryTokenFile, "alert-history-token-file", "", "The file with the bearer token "+
+		"Alertmanager and Grafana authenticate to the alert history with.")
+	flag.IntVar(&alertHistoryRetentionDays, "alert-history-retention-days", 90, "Days the alert history keeps the transitions.")
+	flag.StringVar(&diagnosticsAddr, "diagnostics-addr", "", "The localhost address the authenticated diagnostics endpoint binds to, "+
+		"disabled if empty.")
+	flag.BoolVar(&enablePprof, "enable-pprof", false, "Serve the pprof profiles on the diagnostics endpoint.")
+	flag.BoolVar(&runPreflight, "preflight", false, "Verify the prerequisites of the stack, print a JSON report and exit. "+
+		"Exits with 1 if a check failed.")
+	flag.BoolVar(&simulateExternal, "simulate-external", false, "Run against in-process fakes of Observatorium, the SSO "+
+		"and the configuration repository instead of the real services, for local development and chaos testing.")
+	flag.StringVar(&simulateExternalAddr, "simulate-external-addr", "127.0.0.1:9097", "The address the simulated external services bind to.")
+	flag.Float64Var(&simulateExternalFailureRate, "simulate-external-failure-rate", 0, "Fraction of the requests to the "+
+		"simulated external services that fail with 503 Service Unavailable, between 0 and 1.")
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	var defaultLogLevel zapcore.Level
+	logLevelErr := defaultLogLevel.UnmarshalText([]byte(logLevel))
+	atomicLogLevel := uberzap.NewAtomicLevelAt(defaultLogLevel)
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true), zap.Level(&atomicLogLevel)))
+
+	if logLevelErr != nil {
+		setupLog.Error(logLevelErr, "invalid log level")
+		os.Exit(1)
+	}
+
+	// The operator image also runs the alert forwarder deployed for spec.alerting.forwarder
+	if alertForwarderConfig != "" {
+		if err := forwarder.Run(alertForwarderAddr, alertForwarderConfig, alertForwarderTokenFile, ctrl.Log.WithName("alert-forwarder")); err != nil {
+			setupLog.Error(err, "problem running alert forwarder")
+			os.Exit(1)
+		}
+		return
+	}
+
+	// And the ticketing bridge deployed for spec.alerting.ticketing
+	if alertTicketingConfig != "" {
+		if err := ticketing.Run(alertTicketingAddr, alertTicketingConfig, alertTicketingTokenFile, ctrl.Log.WithName("alert-ticketing")); err != nil {
+			setupLog.Error(err, "problem running alert ticketing")
+			os.Exit(1)
+		}
+		return
+	}
+
+	// And the alert history deployed for spec.alerting.history
+	if alertHistoryDir != "" {
+		retention := time.Duration(alertHistoryRetentionDays) * 24 * time.Hour
+		if err := history.Run(alertHistoryAddr, alertHistoryDir, retention, alertHistoryTokenFile, ctrl.Log.WithName("alert-history")); err != nil {
+			setupLog.Error(err, "problem running alert history")
+			os.Exit(1)
+		}
+		return
+	}
+
+	// The init container of the operator runs the preflight checks before the manager starts
+	if runPreflight {
+		if err := runPreflightChecks(); err != nil {
+			setupLog.Error(err, "preflight checks failed")
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := validateLeaderElection(leaseDuration, renewDeadline, retryPeriod); err != nil {
+		setupLog.Error(err, "invalid leader election settings")
+		os.Exit(1)
+	}
+
+	if reconcileFailurePeriods <= 0 || livenessStallTimeout <= 0 {
+		setupLog.Error(fmt.Errorf("reconcile failure periods and liveness stall timeout must be positive"), "invalid health probe settings")
+		os.Exit(1)
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:             scheme,
-		MetricsBindAddress: metricsAddr,
-		Port:               9443,
-		LeaderElection:     enableLeaderElection,
-		LeaderElectionID:   "04220e3f.redhat.com",
+		Scheme:                 scheme,
+		MetricsBindAddress:     metricsAddr,
+		HealthProbeBindAddress: probeAddr,
+		Port:                   9443,
+		LeaderElection:         enableLeaderElection,
+		LeaderElectionID:       "04220e3f.redhat.com",
+		LeaseDuration:          &leaseDuration,
+		RenewDeadline:          &renewDeadline,
+		RetryPeriod:            &retryPeriod,
+		NewClient:              controllers.NewClient,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	cacheSyncChecker := runners.NewCacheSyncChecker(mgr.GetCache())
+	if err = mgr.Add(cacheSyncChecker); err != nil {
+		setupLog.Error(err, "unable to add cache sync checker")
+		os.Exit(1)
+	}
+	reconcileHealth := controllers.NewReconcileHealth(time.Duration(reconcileFailurePeriods)*controllers.RequeueDelaySuccess, livenessStallTimeout)
+	if err = mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err = mgr.AddHealthzCheck("reconcile", reconcileHealth.LivenessCheck); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	// Readiness only waits for the cache, failing CRs are reported by the reconcile metrics because the
+	// webhook is served by the same pods
+	if err = mgr.AddReadyzCheck("cache-sync", cacheSyncChecker.Check); err != nil {
+		setupLog.Error(err, "unable to set up readiness check")
+		os.Exit(1)
+	}
+	if err = metrics.Registry.Register(reconcileHealth); err != nil {
+		setupLog.Error(err, "unable to register reconcile metrics")
+		os.Exit(1)
+	}
+
+	if err = detectOperatorImage(mgr.GetAPIReader()); err != nil {
+		setupLog.Error(err, "unable to determine the operator image, the alert forwarder, ticketing bridge and history require an image override")
+	}
+
+	watchNamespaces, err := controllers.GetWatchNamespaces()
+	if err != nil {
+		setupLog.Error(err, "invalid watch namespace selector")
+		os.Exit(1)
+	}
+	if watchNamespaces.IsClusterScoped() {
+		setupLog.Info("watching all namespaces")
+	} else {
+		setupLog.Info("watching namespaces", "namespaces", os.Getenv(controllers.WatchNamespaceEnv),
+			"selector", os.Getenv(controllers.WatchNamespaceSelectorEnv))
+	}
+
 	observabilityReconciler := &controllers.ObservabilityReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Observability"),
-		Scheme: mgr.GetScheme(),
+		Client:          mgr.GetClient(),
+		Log:             ctrl.Log.WithName("controllers").WithName("Observability"),
+		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor("observability-operator"),
+		WatchNamespaces: watchNamespaces,
+		Health:          reconcileHealth,
+	}
+
+	if namespace := controllers.GetOperatorNamespace(); namespace != "" {
+		logLevelWatcher := runners.NewLogLevelWatcher(mgr.GetAPIReader(), namespace, &atomicLogLevel, defaultLogLevel,
+			ctrl.Log.WithName("logging"))
+		if err = mgr.Add(logLevelWatcher); err != nil {
+			setupLog.Error(err, "unable to add log level watcher")
+			os.Exit(1)
+		}
+	} else {
+		setupLog.Info("operator namespace unknown, the log level can't be changed at runtime")
+	}
+
+	if diagnosticsAddr != "" {
+		observabilityReconciler.Diagnostics = controllers.NewReconcileDiagnostics()
+		diagnosticsServer, err := runners.NewDiagnosticsServer(diagnosticsAddr, observabilityReconciler.Diagnostics, enablePprof,
+			mgr.GetClient(), ctrl.Log.WithName("diagnostics"))
+		if err != nil {
+			setupLog.Error(err, "invalid diagnostics settings")
+			os.Exit(1)
+		}
+		if err = mgr.Add(diagnosticsServer); err != nil {
+			setupLog.Error(err, "unable to add diagnostics server")
+			os.Exit(1)
+		}
+	} else if enablePprof {
+		setupLog.Error(fmt.Errorf("--enable-pprof requires --diagnostics-addr"), "invalid diagnostics settings")
+		os.Exit(1)
 	}
 
 	if err = observabilityReconciler.SetupWithManager(mgr); err != nil {
@@ -119,6 +333,16 @@ func main() {
 		return err
 	}))
 
+	if simulateExternal {
+		if err = addSimulatedEnvironment(mgr, simulateExternalAddr, simulateExternalFailureRate); err != nil {
+			setupLog.Error(err, "unable to simulate external services")
+			os.Exit(1)
+		}
+	} else if simulateExternalFailureRate != 0 {
+		setupLog.Error(fmt.Errorf("--simulate-external-failure-rate requires --simulate-external"), "invalid simulation settings")
+		os.Exit(1)
+	}
+
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		// ENABLE TO AUTO-DELETE CR ON OPERATOR SIGINT/KILL FOR LOCAL DEV
 		// if err := injectStopHandler(mgr, o, setupLog); err != nil {
@@ -127,6 +351,116 @@ func main() {
 	}
 }
 
+// Serves the fakes of the external services and seeds the operator namespace with the secrets
+// pointing to them. The default configuration selector of the CR picks up the configuration secret
+func addSimulatedEnvironment(mgr ctrl.Manager, addr string, failureRate float64) error {
+	if failureRate < 0 || failureRate > 1 {
+		return fmt.Errorf("failure rate %v must be between 0 and 1", failureRate)
+	}
+	environment, err := fakes.NewEnvironment(addr)
+	if err != nil {
+		return err
+	}
+	environment.FailureRate = failureRate
+	if err = mgr.Add(environment); err != nil {
+		return err
+	}
+
+	// The cache is not started yet when the initializer runs, write with a client of its own
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return err
+	}
+	setupLog.Info("simulating external services", "repository", environment.RepositoryURL(),
+		"issuer", environment.IssuerURL(), "gateway", environment.GatewayURL(), "failureRate", failureRate)
+	return mgr.Add(runners.NewOperandInitializer(func() error {
+		namespace := controllers.GetOperatorNamespace()
+		if namespace == "" {
+			return fmt.Errorf("unable to seed simulated secrets: cannot detect operator namespace")
+		}
+		err := environment.Seed(context.Background(), c, namespace, map[string]string{
+			"configures": "observability-operator",
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to seed simulated secrets")
+		}
+		return err
+	}))
+}
+
+// Prints the report of the preflight checks to stdout. Returns an error if a check failed
+func runPreflightChecks() error {
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	namespace := controllers.GetOperatorNamespace()
+	if namespace == "" {
+		return fmt.Errorf("cannot detect operator namespace")
+	}
+
+	report := preflight.Run(context.Background(), c, namespace)
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+
+	for _, check := range report.Checks {
+		switch check.Result {
+		case preflight.ResultFailed:
+			setupLog.Info("preflight check failed", "check", check.Name, "message", check.Message)
+		case preflight.ResultWarning:
+			setupLog.Info("preflight check warning", "check", check.Name, "message", check.Message)
+		}
+	}
+	if !report.Passed {
+		return fmt.Errorf("the prerequisites of the stack are not met")
+	}
+	return nil
+}
+
+// A standby replica only takes over after the lease expired, the leader has to give up the
+// lease before that happens
+func validateLeaderElection(leaseDuration, renewDeadline, retryPeriod time.Duration) error {
+	if retryPeriod <= 0 {
+		return fmt.Errorf("leader election retry period must be positive")
+	}
+	if renewDeadline <= retryPeriod {
+		return fmt.Errorf("leader election renew deadline %v must be greater than the retry period %v", renewDeadline, retryPeriod)
+	}
+	if leaseDuration <= renewDeadline {
+		return fmt.Errorf("leader election lease duration %v must be greater than the renew deadline %v", leaseDuration, renewDeadline)
+	}
+	return nil
+}
+
+// The alert forwarder runs the image of the operator. Unless set explicitly it is read from the
+// operator pod, passed through the downward API
+func detectOperatorImage(reader client.Reader) error {
+	if os.Getenv(model.OperatorImageEnv) != "" {
+		return nil
+	}
+
+	name, namespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if name == "" || namespace == "" {
+		return fmt.Errorf("POD_NAME and POD_NAMESPACE are not set")
+	}
+
+	pod := &corev1.Pod{}
+	err := reader.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, pod)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "manager" {
+			return os.Setenv(model.OperatorImageEnv, container.Image)
+		}
+	}
+	return fmt.Errorf("operator pod %v has no manager container", name)
+}
+
 func injectStopHandler(mgr ctrl.Manager, o *apiv1.Observability, setupLog logr.Logger) error {
 	defer func() {
 		setupLog.Info("SIGINT/KILL received, deleting Observability CR")
@@ -0,0 +1,406 @@
+package preflight
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/configuration"
+	tokenmanager "github.com/redhat-developer/observability-operator/v3/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v3/controllers/token"
+	"github.com/redhat-developer/observability-operator/v3/controllers/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Result string
+
+const (
+	ResultPassed  Result = "Passed"
+	ResultWarning Result = "Warning"
+	ResultFailed  Result = "Failed"
+)
+
+// Check is the outcome of one of the prerequisites. The message of a failed check says what to fix
+type Check struct {
+	Name    string `json:"name"`
+	Result  Result `json:"result"`
+	Message string `json:"message,omitempty"`
+}
+
+// Report is printed as JSON, the manager only starts if it passed. Only missing CRDs fail it, the
+// other checks report warnings: the manager serves the validating webhook, if it didn't start a
+// CR with bad credentials or storage classes could not be fixed, and a short outage of a
+// repository or the identity provider would keep the operator down
+type Report struct {
+	Passed bool    `json:"passed"`
+	Checks []Check `json:"checks"`
+}
+
+func (r *Report) add(name string, result Result, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, Check{
+		Name:    name,
+		Result:  result,
+		Message: fmt.Sprintf(format, args...),
+	})
+	if result == ResultFailed {
+		r.Passed = false
+	}
+}
+
+// Namespaces OLM is installed in on OpenShift and elsewhere
+var olmNamespaces = []string{"openshift-operator-lifecycle-manager", "olm"}
+
+// The configuration selector of the CR the operator creates on startup
+var defaultConfigurationSelector = &metav1.LabelSelector{
+	MatchLabels: map[string]string{
+		"configures": "observability-operator",
+	},
+}
+
+type checker struct {
+	client     client.Client
+	httpClient *http.Client
+	namespace  string
+	report     *Report
+}
+
+// Run verifies the prerequisites of the stack in the namespace of the operator: the required CRDs,
+// the health of OLM, the configuration repositories, the Observatorium credentials and the storage
+// classes of the CRs
+func Run(ctx context.Context, c client.Client, namespace string) *Report {
+	r := &checker{
+		client: c,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		},
+		namespace: namespace,
+		report:    &Report{Passed: true},
+	}
+
+	list := &v1.ObservabilityList{}
+	err := c.List(ctx, list, client.InNamespace(namespace))
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			r.report.add("CRDs", ResultFailed, "the Observability CRD is not installed, install the CRDs of the operator bundle")
+		} else {
+			r.report.add("CRDs", ResultFailed, "unable to list Observability CRs: %v", err)
+		}
+		return r.report
+	}
+	crs := list.Items
+	// Without a CR the checks run against the one the operator creates on startup
+	if len(crs) == 0 {
+		crs = []v1.Observability{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+			Spec: v1.ObservabilitySpec{
+				ConfigurationSelector: defaultConfigurationSelector,
+			},
+		}}
+	}
+
+	olm := r.checkCRDs(ctx)
+	if olm {
+		r.checkOLM(ctx)
+	}
+	for i := range crs {
+		indexes := r.checkRepositories(ctx, &crs[i])
+		r.checkObservatoria(ctx, &crs[i], indexes)
+		r.checkStorageClasses(ctx, &crs[i])
+	}
+	return r.report
+}
+
+// Returns true if OLM is installed. The Prometheus and Grafana operators are either installed
+// through OLM or have to be installed up front
+func (r *checker) checkCRDs(ctx context.Context) bool {
+	capabilities, err := utils.DetectCapabilities(ctx, r.client, r.namespace)
+	if err != nil {
+		r.report.add("CRDs", ResultFailed, "unable to detect the APIs of the cluster: %v", err)
+		return false
+	}
+
+	var missing []string
+	if !capabilities.PrometheusOperator {
+		missing = append(missing, "Prometheus operator")
+	}
+	if !capabilities.GrafanaOperator {
+		missing = append(missing, "Grafana operator")
+	}
+	switch {
+	case len(missing) == 0:
+		r.report.add("CRDs", ResultPassed, "")
+	case capabilities.OLM:
+		r.report.add("CRDs", ResultWarning, "the CRDs of the %v are not installed yet, they are installed through OLM", missing)
+	default:
+		r.report.add("CRDs", ResultFailed, "the CRDs of the %v are missing and OLM is not available to install them, "+
+			"install OLM or the operators", missing)
+	}
+	return capabilities.OLM
+}
+
+func (r *checker) checkOLM(ctx context.Context) {
+	found := false
+	for _, namespace := range olmNamespaces {
+		for _, name := range []string{"olm-operator", "catalog-operator"} {
+			deployment := &appsv1.Deployment{}
+			err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, deployment)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				r.report.add("OLM", ResultWarning, "unable to read the %v deployment: %v", name, err)
+				return
+			}
+			found = true
+			if deployment.Status.AvailableReplicas == 0 {
+				r.report.add("OLM", ResultWarning, "the %v deployment in %v has no available replicas, "+
+					"subscriptions of the operators are not resolved until it is running", name, namespace)
+				return
+			}
+		}
+	}
+	if !found {
+		r.report.add("OLM", ResultWarning, "the OLM deployments were not found in %v", olmNamespaces)
+		return
+	}
+
+	catalogs := &v1alpha1.CatalogSourceList{}
+	err := r.client.List(ctx, catalogs, client.InNamespace(r.namespace))
+	if err != nil {
+		r.report.add("OLM", ResultWarning, "unable to list catalog sources: %v", err)
+		return
+	}
+	for _, catalog := range catalogs.Items {
+		state := catalog.Status.GRPCConnectionState
+		if state != nil && state.LastObservedState == "TRANSIENT_FAILURE" {
+			r.report.add("OLM", ResultWarning, "catalog source %v can't be reached, check that the image %v can be pulled",
+				catalog.Name, catalog.Spec.Image)
+			return
+		}
+	}
+	r.report.add("OLM", ResultPassed, "")
+}
+
+// Fetches the index of every configuration secret of the CR and returns the indexes
+func (r *checker) checkRepositories(ctx context.Context, cr *v1.Observability) []v1.RepositoryIndex {
+	name := fmt.Sprintf("Repositories %v", cr.Name)
+	if cr.ExternalSyncDisabled() {
+		return nil
+	}
+	if cr.Spec.ConfigurationSelector == nil {
+		r.report.add(name, ResultWarning, "no configuration selector, the configuration is not synced")
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(cr.Spec.ConfigurationSelector)
+	if err != nil {
+		r.report.add(name, ResultWarning, "invalid configuration selector: %v", err)
+		return nil
+	}
+	secrets := &corev1.SecretList{}
+	err = r.client.List(ctx, secrets, &client.ListOptions{LabelSelector: selector})
+	if err != nil {
+		r.report.add(name, ResultWarning, "unable to list configuration secrets: %v", err)
+		return nil
+	}
+	if len(secrets.Items) == 0 {
+		r.report.add(name, ResultWarning, "no configuration secret matches the selector %v, the stack is not configured until one is created",
+			selector.String())
+		return nil
+	}
+
+	var indexes []v1.RepositoryIndex
+	for _, secret := range secrets.Items {
+		repository := string(secret.Data[configuration.RemoteRepository])
+		indexUrl := fmt.Sprintf("%s/%s/index.json", repository, string(secret.Data[configuration.RemoteChannel]))
+		data, err := r.fetch(indexUrl, string(secret.Data[configuration.RemoteTag]), string(secret.Data[configuration.RemoteAccessToken]))
+		if err != nil {
+			r.report.add(name, ResultWarning, "unable to fetch the index of %v from configuration secret %v/%v: %v, "+
+				"check the repository url, channel, tag and access token", repository, secret.Namespace, secret.Name, err)
+			return nil
+		}
+		index := v1.RepositoryIndex{}
+		err = json.Unmarshal(data, &index)
+		if err != nil {
+			r.report.add(name, ResultWarning, "invalid index of %v: %v", repository, err)
+			return nil
+		}
+		indexes = append(indexes, index)
+	}
+	r.report.add(name, ResultPassed, "")
+	return indexes
+}
+
+func (r *checker) fetch(path string, tag string, accessToken string) ([]byte, error) {
+	fileUrl, err := url.ParseRequestURI(path)
+	if err != nil {
+		return nil, err
+	}
+	if accessToken == "" {
+		return nil, fmt.Errorf("access token missing")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fileUrl.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", accessToken))
+	req.Header.Set("Accept", "application/vnd.github.v3.raw")
+	if tag != "" {
+		q := req.URL.Query()
+		q.Add("ref", tag)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %v", resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Requests a token with the credentials of every Observatorium of the indexes
+func (r *checker) checkObservatoria(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) {
+	if cr.ObservatoriumDisabled() {
+		return
+	}
+	for _, index := range indexes {
+		if index.Config == nil {
+			continue
+		}
+		for _, observatorium := range index.Config.Observatoria {
+			name := fmt.Sprintf("Observatorium %v", observatorium.Id)
+			err := tokenmanager.ResolveObservatorium(ctx, r.client, cr, &observatorium)
+			if err != nil {
+				r.report.add(name, ResultWarning, "unable to read secret %v: %v", observatorium.SecretName, err)
+				continue
+			}
+			err = r.fetchObservatoriumToken(ctx, cr, &observatorium)
+			if err != nil {
+				r.report.add(name, ResultWarning, "unable to get a token for tenant %v: %v, check the credentials in secret %v",
+					observatorium.Tenant, err, observatorium.SecretName)
+				continue
+			}
+			r.report.add(name, ResultPassed, "")
+		}
+	}
+}
+
+func (r *checker) fetchObservatoriumToken(ctx context.Context, cr *v1.Observability, observatorium *v1.ObservatoriumIndex) error {
+	switch observatorium.AuthType {
+	case v1.AuthTypeDex:
+		_, _, err := token.NewDexTokenFetcher(ctx, r.client).Fetch(cr, observatorium, "")
+		return err
+	case v1.AuthTypeRedhat:
+		sso := observatorium.RedhatSsoConfig
+		if sso == nil || !sso.HasAuthServer() {
+			return fmt.Errorf("no auth server")
+		}
+		issuer, err := url.Parse(sso.Url)
+		if err != nil {
+			return err
+		}
+		issuer.Path = path.Join(issuer.Path, "realms", sso.Realm)
+		endpoint, err := token.GetTokenEndpoint(r.httpClient, issuer.String())
+		if err != nil {
+			return err
+		}
+		if sso.HasMetrics() {
+			_, err = token.FetchClientCredentialsToken(r.httpClient, endpoint, sso.MetricsClient, sso.MetricsSecret)
+			if err != nil {
+				return err
+			}
+		}
+		if sso.HasLogs() {
+			_, err = token.FetchClientCredentialsToken(r.httpClient, endpoint, sso.LogsClient, sso.LogsSecret)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown auth type %v", observatorium.AuthType)
+	}
+}
+
+// Checks that the storage classes of the CR exist. Volumes without a storage class need a default
+func (r *checker) checkStorageClasses(ctx context.Context, cr *v1.Observability) {
+	name := fmt.Sprintf("StorageClasses %v", cr.Name)
+	classes := &storagev1.StorageClassList{}
+	err := r.client.List(ctx, classes)
+	if err != nil {
+		r.report.add(name, ResultWarning, "unable to list storage classes: %v", err)
+		return
+	}
+
+	existing := map[string]bool{}
+	hasDefault := false
+	for _, class := range classes.Items {
+		existing[class.Name] = true
+		if class.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+			hasDefault = true
+		}
+	}
+
+	for _, class := range getStorageClasses(cr) {
+		if !existing[class] {
+			r.report.add(name, ResultWarning, "storage class %v does not exist, the volumes using it can't be provisioned", class)
+			return
+		}
+	}
+	if !hasDefault {
+		r.report.add(name, ResultWarning, "there is no default storage class, volumes without a storage class stay pending")
+		return
+	}
+	r.report.add(name, ResultPassed, "")
+}
+
+// Storage classes named in the spec of the CR
+func getStorageClasses(cr *v1.Observability) []string {
+	var result []string
+	add := func(class *string) {
+		if class != nil && *class != "" {
+			result = append(result, *class)
+		}
+	}
+	if cr.Spec.SelfContained != nil {
+		add(cr.Spec.SelfContained.PrometheusStorageClass)
+	}
+	if cr.Spec.Storage != nil && cr.Spec.Storage.PrometheusStorageSpec != nil {
+		add(cr.Spec.Storage.PrometheusStorageSpec.VolumeClaimTemplate.Spec.StorageClassName)
+	}
+	if cr.Spec.Alerting != nil && cr.Spec.Alerting.History != nil {
+		add(cr.Spec.Alerting.History.StorageClass)
+	}
+	if cr.Spec.Logs != nil && cr.Spec.Logs.Buffering != nil {
+		add(cr.Spec.Logs.Buffering.StorageClass)
+	}
+	if cr.Spec.Tracing != nil && cr.Spec.Tracing.Storage != nil {
+		add(cr.Spec.Tracing.Storage.StorageClass)
+	}
+	if cr.Spec.Grafana != nil && cr.Spec.Grafana.Persistence != nil && cr.Spec.Grafana.Persistence.Volume != nil {
+		add(cr.Spec.Grafana.Persistence.Volume.StorageClass)
+	}
+	return result
+}
@@ -0,0 +1,285 @@
+package preflight
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	grafana "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/fakes"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	namespace   = "observability"
+	accessToken = "token"
+)
+
+// Client of a cluster that only serves the APIs of the scheme, like a real client that resolves
+// the kinds through discovery
+type clusterClient struct {
+	client.Client
+}
+
+func noMatch(err error) error {
+	if runtime.IsNotRegisteredError(err) {
+		return &meta.NoKindMatchError{}
+	}
+	return err
+}
+
+func (c *clusterClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return noMatch(c.Client.Get(ctx, key, obj))
+}
+
+func (c *clusterClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	return noMatch(c.Client.List(ctx, list, opts...))
+}
+
+// Repository and SSO the checks run against
+func newExternalServices(t *testing.T) (*httptest.Server, *fakes.Repository, *fakes.SSO) {
+	repository := fakes.NewRepository(accessToken)
+	sso := fakes.NewSSO()
+	sso.AddClient("tenant", "dex-secret")
+	sso.AddUser("user@example.com", "password")
+
+	mux := http.NewServeMux()
+	mux.Handle("/repository/", http.StripPrefix("/repository", repository))
+	mux.Handle("/sso/", http.StripPrefix("/sso", sso))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	sso.URL = server.URL + "/sso"
+	return server, repository, sso
+}
+
+func TestRun(t *testing.T) {
+	server, repository, _ := newExternalServices(t)
+	err := repository.SetIndex("", "staging", &v1.RepositoryIndex{
+		Id: "staging",
+		Config: &v1.RepositoryConfig{
+			Observatoria: []v1.ObservatoriumIndex{
+				{
+					Id:       "tenant",
+					Tenant:   "tenant",
+					AuthType: v1.AuthTypeDex,
+					DexConfig: &v1.DexConfig{
+						Url:      server.URL + "/sso",
+						Username: "user@example.com",
+						Password: "password",
+						Secret:   "dex-secret",
+					},
+				},
+				{
+					Id:       "rejected",
+					Tenant:   "tenant",
+					AuthType: v1.AuthTypeDex,
+					DexConfig: &v1.DexConfig{
+						Url:      server.URL + "/sso",
+						Username: "user@example.com",
+						Password: "wrong",
+						Secret:   "dex-secret",
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configSecret := func(repositoryUrl string, token string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "config",
+				Namespace: namespace,
+				Labels:    defaultConfigurationSelector.MatchLabels,
+			},
+			Data: map[string][]byte{
+				"repository":   []byte(repositoryUrl),
+				"channel":      []byte("staging"),
+				"access_token": []byte(token),
+			},
+		}
+	}
+	olmDeployments := []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "olm-operator", Namespace: "olm"},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "catalog-operator", Namespace: "olm"},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 1},
+		},
+	}
+	defaultClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "standard",
+			Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		// APIs the cluster serves in addition to the core ones
+		apis       []func(*runtime.Scheme) error
+		objects    []runtime.Object
+		wantPassed bool
+		want       map[string]Result
+	}{
+		{
+			name:       "missing Observability CRD",
+			wantPassed: false,
+			want: map[string]Result{
+				"CRDs": ResultFailed,
+			},
+		},
+		{
+			name:       "all prerequisites",
+			apis:       []func(*runtime.Scheme) error{v1.AddToScheme, v1alpha1.AddToScheme, prometheusv1.AddToScheme, grafana.AddToScheme},
+			objects:    append([]runtime.Object{configSecret(server.URL+"/repository", accessToken), defaultClass}, olmDeployments...),
+			wantPassed: true,
+			want: map[string]Result{
+				"CRDs":                   ResultPassed,
+				"OLM":                    ResultPassed,
+				"Repositories ":          ResultPassed,
+				"Observatorium tenant":   ResultPassed,
+				"Observatorium rejected": ResultWarning,
+				"StorageClasses ":        ResultPassed,
+			},
+		},
+		{
+			name:       "operator CRDs are installed through OLM",
+			apis:       []func(*runtime.Scheme) error{v1.AddToScheme, v1alpha1.AddToScheme},
+			objects:    []runtime.Object{defaultClass},
+			wantPassed: true,
+			want: map[string]Result{
+				"CRDs":            ResultWarning,
+				"OLM":             ResultWarning,
+				"Repositories ":   ResultWarning,
+				"StorageClasses ": ResultPassed,
+			},
+		},
+		{
+			name:       "operator CRDs missing without OLM",
+			apis:       []func(*runtime.Scheme) error{v1.AddToScheme},
+			objects:    []runtime.Object{defaultClass},
+			wantPassed: false,
+			want: map[string]Result{
+				"CRDs":            ResultFailed,
+				"Repositories ":   ResultWarning,
+				"StorageClasses ": ResultPassed,
+			},
+		},
+		{
+			name:       "rejected access token of the repository",
+			apis:       []func(*runtime.Scheme) error{v1.AddToScheme, prometheusv1.AddToScheme, grafana.AddToScheme},
+			objects:    []runtime.Object{configSecret(server.URL+"/repository", "wrong"), defaultClass},
+			wantPassed: true,
+			want: map[string]Result{
+				"CRDs":            ResultPassed,
+				"Repositories ":   ResultWarning,
+				"StorageClasses ": ResultPassed,
+			},
+		},
+		{
+			name:       "unreachable repository",
+			apis:       []func(*runtime.Scheme) error{v1.AddToScheme, prometheusv1.AddToScheme, grafana.AddToScheme},
+			objects:    []runtime.Object{configSecret("http://127.0.0.1:1/repository", accessToken), defaultClass},
+			wantPassed: true,
+			want: map[string]Result{
+				"CRDs":            ResultPassed,
+				"Repositories ":   ResultWarning,
+				"StorageClasses ": ResultPassed,
+			},
+		},
+		{
+			name:       "no default storage class",
+			apis:       []func(*runtime.Scheme) error{v1.AddToScheme, prometheusv1.AddToScheme, grafana.AddToScheme},
+			objects:    []runtime.Object{configSecret(server.URL+"/repository", accessToken)},
+			wantPassed: true,
+			want: map[string]Result{
+				"CRDs":                   ResultPassed,
+				"Repositories ":          ResultPassed,
+				"Observatorium tenant":   ResultPassed,
+				"Observatorium rejected": ResultWarning,
+				"StorageClasses ":        ResultWarning,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			for _, add := range append([]func(*runtime.Scheme) error{clientgoscheme.AddToScheme}, tt.apis...) {
+				if err := add(scheme); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := &clusterClient{Client: fake.NewFakeClientWithScheme(scheme, tt.objects...)}
+
+			report := Run(context.Background(), c, namespace)
+			if report.Passed != tt.wantPassed {
+				t.Errorf("Run() passed = %v, want %v: %+v", report.Passed, tt.wantPassed, report.Checks)
+			}
+			got := map[string]Result{}
+			for _, check := range report.Checks {
+				got[check.Name] = check.Result
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run() checks = %v, want %v: %+v", got, tt.want, report.Checks)
+			}
+		})
+	}
+}
+
+func TestRun_storageClassesOfTheCR(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, v1.AddToScheme, prometheusv1.AddToScheme, grafana.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fast := "fast"
+	cr := &v1.Observability{
+		ObjectMeta: metav1.ObjectMeta{Name: "stack", Namespace: namespace},
+		Spec: v1.ObservabilitySpec{
+			ConfigurationSelector: defaultConfigurationSelector,
+			SelfContained: &v1.SelfContained{
+				DisableRepoSync:        &[]bool{true}[0],
+				PrometheusStorageClass: &fast,
+			},
+		},
+	}
+	c := &clusterClient{Client: fake.NewFakeClientWithScheme(scheme, cr, &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "standard",
+			Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"},
+		},
+	})}
+
+	report := Run(context.Background(), c, namespace)
+	if !report.Passed {
+		t.Errorf("Run() passed = false, a missing storage class is a warning")
+	}
+	for _, check := range report.Checks {
+		if check.Name == "StorageClasses stack" {
+			if check.Result != ResultWarning || check.Message != "storage class fast does not exist, the volumes using it can't be provisioned" {
+				t.Errorf("Run() storage class check = %+v", check)
+			}
+			return
+		}
+	}
+	t.Errorf("Run() has no storage class check: %+v", report.Checks)
+}
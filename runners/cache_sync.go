@@ -0,0 +1,41 @@
+package runners
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// CacheSyncChecker reports a replica as ready once its informer cache is synced. It runs on
+// every replica, so standby replicas are ready to take over the leader lease.
+type CacheSyncChecker struct {
+	cache  cache.Cache
+	synced int32
+}
+
+func NewCacheSyncChecker(cache cache.Cache) *CacheSyncChecker {
+	return &CacheSyncChecker{
+		cache: cache,
+	}
+}
+
+func (r *CacheSyncChecker) Start(stop <-chan struct{}) error {
+	if r.cache.WaitForCacheSync(stop) {
+		atomic.StoreInt32(&r.synced, 1)
+	}
+	<-stop
+	return nil
+}
+
+func (r *CacheSyncChecker) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *CacheSyncChecker) Check(_ *http.Request) error {
+	if atomic.LoadInt32(&r.synced) == 0 {
+		return errors.New("informer cache not synced")
+	}
+	return nil
+}
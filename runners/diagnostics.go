@@ -0,0 +1,151 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DiagnosticsServer serves the reconcile dump on /debug/reconciles and optionally the pprof profiles
+// on /debug/pprof/. Callers authenticate with a service account or user token, which needs the get
+// verb on the path as a non-resource URL, like the endpoints of the API server. The endpoint is plain
+// http, so it only binds to localhost and is reached through a port forward.
+type DiagnosticsServer struct {
+	addr       string
+	reconciles http.Handler
+	pprof      bool
+	client     client.Client
+	logger     logr.Logger
+}
+
+func NewDiagnosticsServer(addr string, reconciles http.Handler, pprof bool, client client.Client, logger logr.Logger) (*DiagnosticsServer, error) {
+	addr, err := localAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &DiagnosticsServer{
+		addr:       addr,
+		reconciles: reconciles,
+		pprof:      pprof,
+		client:     client,
+		logger:     logger,
+	}, nil
+}
+
+// Binds an address without a host to localhost, the bearer tokens are sent in plain text
+func localAddr(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if host == "" {
+		return net.JoinHostPort("127.0.0.1", port), nil
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return "", fmt.Errorf("diagnostics address %v is not a localhost address", addr)
+	}
+	return addr, nil
+}
+
+func (r *DiagnosticsServer) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/reconciles", r.reconciles)
+	if r.pprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	server := &http.Server{
+		Addr:    r.addr,
+		Handler: r.authorize(mux),
+	}
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	r.logger.Info("starting diagnostics server", "addr", r.addr, "pprof", r.pprof)
+	err := server.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Standby replicas are diagnosed as well
+func (r *DiagnosticsServer) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *DiagnosticsServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if token == "" || token == req.Header.Get("Authorization") {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		allowed, err := r.isAllowed(req.Context(), token, req.URL.Path)
+		if err != nil {
+			r.logger.Error(err, "error authorizing diagnostics request")
+			http.Error(w, "error authorizing request", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (r *DiagnosticsServer) isAllowed(ctx context.Context, token string, path string) (bool, error) {
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token: token,
+		},
+	}
+	err := r.client.Create(ctx, review)
+	if err != nil {
+		return false, err
+	}
+	if !review.Status.Authenticated {
+		return false, nil
+	}
+
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, v := range review.Status.User.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	access := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   review.Status.User.Username,
+			UID:    review.Status.User.UID,
+			Groups: review.Status.User.Groups,
+			Extra:  extra,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: path,
+				Verb: "get",
+			},
+		},
+	}
+	err = r.client.Create(ctx, access)
+	if err != nil {
+		return false, err
+	}
+	return access.Status.Allowed, nil
+}
@@ -0,0 +1,30 @@
+package runners
+
+import "testing"
+
+func TestLocalAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		want    string
+		wantErr bool
+	}{
+		{addr: ":8083", want: "127.0.0.1:8083"},
+		{addr: "127.0.0.1:8083", want: "127.0.0.1:8083"},
+		{addr: "localhost:8083", want: "localhost:8083"},
+		{addr: "[::1]:8083", want: "[::1]:8083"},
+		{addr: "0.0.0.0:8083", wantErr: true},
+		{addr: "10.0.0.1:8083", wantErr: true},
+		{addr: "8083", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			got, err := localAddr(tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("localAddr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("localAddr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
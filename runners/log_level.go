@@ -0,0 +1,82 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ConfigMap in the namespace of the operator to change the log level at runtime
+	LoggingConfigMapName = "observability-operator-logging"
+	LoggingLevelKey      = "level"
+
+	logLevelInterval = 30 * time.Second
+)
+
+// LogLevelWatcher applies the log level of the logging ConfigMap, or the level from the command line
+// if there is none. The operator namespace may not be watched, so the ConfigMap is read on a timer
+// instead of from the cache. It runs on every replica, standby replicas log as well.
+type LogLevelWatcher struct {
+	reader       client.Reader
+	namespace    string
+	level        *zap.AtomicLevel
+	defaultLevel zapcore.Level
+	logger       logr.Logger
+}
+
+func NewLogLevelWatcher(reader client.Reader, namespace string, level *zap.AtomicLevel, defaultLevel zapcore.Level, logger logr.Logger) *LogLevelWatcher {
+	return &LogLevelWatcher{
+		reader:       reader,
+		namespace:    namespace,
+		level:        level,
+		defaultLevel: defaultLevel,
+		logger:       logger,
+	}
+}
+
+func (r *LogLevelWatcher) Start(stop <-chan struct{}) error {
+	ticker := time.NewTicker(logLevelInterval)
+	defer ticker.Stop()
+	for {
+		r.update(context.Background())
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *LogLevelWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *LogLevelWatcher) update(ctx context.Context) {
+	level := r.defaultLevel
+	configMap := &v1.ConfigMap{}
+	err := r.reader.Get(ctx, client.ObjectKey{Namespace: r.namespace, Name: LoggingConfigMapName}, configMap)
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.logger.Error(err, "error reading logging config map")
+		return
+	}
+
+	if err == nil && configMap.Data[LoggingLevelKey] != "" {
+		err = level.UnmarshalText([]byte(configMap.Data[LoggingLevelKey]))
+		if err != nil {
+			r.logger.Error(err, "invalid log level in logging config map", "level", configMap.Data[LoggingLevelKey])
+			level = r.defaultLevel
+		}
+	}
+
+	if r.level.Level() != level {
+		r.logger.Info("changing log level", "level", level.String())
+		r.level.SetLevel(level)
+	}
+}
@@ -0,0 +1,47 @@
+package ticketing
+
+import (
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config of the ticketing bridge, rendered by the operator into a secret with the credentials
+// resolved
+type Config struct {
+	Type        string            `json:"type"`
+	URL         string            `json:"url"`
+	Username    string            `json:"username"`
+	Password    string            `json:"password"`
+	Fields      map[string]string `json:"fields,omitempty"`
+	AutoResolve bool              `json:"autoResolve"`
+	ServiceNow  *ServiceNow       `json:"serviceNow,omitempty"`
+	Jira        *Jira             `json:"jira,omitempty"`
+}
+
+type ServiceNow struct {
+	Table         string            `json:"table,omitempty"`
+	ResolveFields map[string]string `json:"resolveFields,omitempty"`
+}
+
+type Jira struct {
+	Project           string `json:"project"`
+	IssueType         string `json:"issueType,omitempty"`
+	ResolveTransition string `json:"resolveTransition,omitempty"`
+}
+
+// The config is read on every notification, so that changes of the mounted secret are picked up
+// without a restart
+func loadConfig(path string) (*Config, error) {
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	err = yaml.Unmarshal(source, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,101 @@
+package ticketing
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/redhat-developer/observability-operator/v3/forwarder"
+)
+
+const (
+	DefaultJiraIssueType         = "Task"
+	DefaultJiraResolveTransition = "Done"
+	// Prefix of the label of the issues, followed by the fingerprint of the alert
+	jiraLabelPrefix = "alert-"
+)
+
+// Issues are created with the REST API v2 and found again by their label
+type jira struct {
+	server *Server
+	config *Config
+}
+
+func (t *jira) FindOpen(id string) (string, error) {
+	query := url.Values{}
+	query.Set("jql", fmt.Sprintf(`project = "%v" AND labels = "%v%v" AND statusCategory != Done`, t.config.Jira.Project, jiraLabelPrefix, id))
+	query.Set("fields", "key")
+	query.Set("maxResults", "1")
+
+	result := struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}{}
+	err := t.server.request(t.config, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &result)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Issues) == 0 {
+		return "", nil
+	}
+	return result.Issues[0].Key, nil
+}
+
+func (t *jira) Open(id string, alert *forwarder.Alert) error {
+	templates := t.config.Fields
+	if len(templates) == 0 {
+		templates = map[string]string{
+			"summary":     DefaultSummaryTemplate,
+			"description": DefaultDescriptionTemplate,
+		}
+	}
+	fields, err := renderFields(templates, alert)
+	if err != nil {
+		return err
+	}
+
+	issueType := t.config.Jira.IssueType
+	if issueType == "" {
+		issueType = DefaultJiraIssueType
+	}
+	fields["project"] = map[string]string{"key": t.config.Jira.Project}
+	fields["issuetype"] = map[string]string{"name": issueType}
+
+	// The label of the alert is added to the labels of the fields
+	label := jiraLabelPrefix + id
+	labels, _ := fields["labels"].([]interface{})
+	fields["labels"] = append(labels, label)
+
+	return t.server.request(t.config, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{
+		"fields": fields,
+	}, nil)
+}
+
+func (t *jira) Resolve(ref string, alert *forwarder.Alert) error {
+	name := t.config.Jira.ResolveTransition
+	if name == "" {
+		name = DefaultJiraResolveTransition
+	}
+
+	result := struct {
+		Transitions []struct {
+			Id   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}{}
+	path := fmt.Sprintf("/rest/api/2/issue/%v/transitions", ref)
+	err := t.server.request(t.config, http.MethodGet, path, nil, &result)
+	if err != nil {
+		return err
+	}
+
+	for _, transition := range result.Transitions {
+		if transition.Name == name {
+			return t.server.request(t.config, http.MethodPost, path, map[string]interface{}{
+				"transition": map[string]string{"id": transition.Id},
+			}, nil)
+		}
+	}
+	return fmt.Errorf("issue %v has no transition %v", ref, name)
+}
@@ -0,0 +1,218 @@
+package ticketing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"github.com/redhat-developer/observability-operator/v3/controllers/model"
+	"github.com/redhat-developer/observability-operator/v3/forwarder"
+)
+
+const (
+	DefaultSummaryTemplate     = `[{{ .Status | toUpper }}] {{ .Labels.alertname }}{{ with .Annotations.summary }}: {{ . }}{{ end }}`
+	DefaultDescriptionTemplate = `{{ with .Annotations.description }}{{ . }}
+
+{{ end }}Labels: {{ json .Labels }}
+Started: {{ .StartsAt }}
+Source: {{ .GeneratorURL }}`
+)
+
+// Tracker opens and resolves the tickets of one ticketing system. The id of a ticket is the
+// fingerprint of its alert, which is stored with the ticket to find it again
+type Tracker interface {
+	// Returns the reference of the open ticket of the alert, or an empty string
+	FindOpen(id string) (string, error)
+	Open(id string, alert *forwarder.Alert) error
+	Resolve(ref string, alert *forwarder.Alert) error
+}
+
+// Server receives the notifications of Alertmanager and opens or resolves a ticket for every alert.
+// Failures are returned to Alertmanager, which retries the notification. Tickets of retried alerts
+// are found again, so retries don't open duplicates
+type Server struct {
+	configPath string
+	httpClient *http.Client
+	logger     logr.Logger
+}
+
+func NewServer(configPath string, logger logr.Logger) *Server {
+	return &Server{
+		configPath: configPath,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		logger:     logger,
+	}
+}
+
+// Runs the ticketing bridge until the listener fails. Only Alertmanager knows the token, other pods
+// could open tickets otherwise
+func Run(addr string, configPath string, tokenFile string, logger logr.Logger) error {
+	token, err := forwarder.ReadToken(tokenFile)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(model.AlertTicketingAlertsPath, forwarder.RequireToken(token, NewServer(configPath, logger)))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	logger.Info("starting alert ticketing", "addr", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	config, err := loadConfig(s.configPath)
+	if err != nil {
+		s.logger.Error(err, "error reading alert ticketing config")
+		http.Error(w, "error reading config", http.StatusInternalServerError)
+		return
+	}
+
+	notification := &forwarder.Notification{}
+	err = json.NewDecoder(r.Body).Decode(notification)
+	if err != nil {
+		http.Error(w, "invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	tracker, err := s.getTracker(config)
+	if err != nil {
+		s.logger.Error(err, "error creating ticket tracker")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for i := range notification.Alerts {
+		err = s.handle(config, tracker, &notification.Alerts[i])
+		if err != nil {
+			s.logger.Error(err, "error updating ticket", "alert", notification.Alerts[i].Labels["alertname"],
+				"fingerprint", notification.Alerts[i].Fingerprint)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getTracker(config *Config) (Tracker, error) {
+	switch config.Type {
+	case v1.AlertTicketingTypeServiceNow:
+		return &serviceNow{server: s, config: config}, nil
+	case v1.AlertTicketingTypeJira:
+		if config.Jira == nil {
+			return nil, fmt.Errorf("jira ticketing has no jira config")
+		}
+		return &jira{server: s, config: config}, nil
+	default:
+		return nil, fmt.Errorf("unsupported ticketing type %v", config.Type)
+	}
+}
+
+func (s *Server) handle(config *Config, tracker Tracker, alert *forwarder.Alert) error {
+	if alert.Fingerprint == "" {
+		return nil
+	}
+
+	ref, err := tracker.FindOpen(alert.Fingerprint)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case alert.Status == "firing" && ref == "":
+		err = tracker.Open(alert.Fingerprint, alert)
+		if err == nil {
+			s.logger.Info("opened ticket", "alert", alert.Labels["alertname"], "fingerprint", alert.Fingerprint)
+		}
+	case alert.Status == "resolved" && ref != "" && config.AutoResolve:
+		err = tracker.Resolve(ref, alert)
+		if err == nil {
+			s.logger.Info("resolved ticket", "ticket", ref, "alert", alert.Labels["alertname"])
+		}
+	}
+	return err
+}
+
+// Renders the field templates with the alert. Values that render to a JSON object or array are
+// decoded, so that they are sent as JSON
+func renderFields(templates map[string]string, alert *forwarder.Alert) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	for name, text := range templates {
+		tpl, err := v1.ParseAlertForwarderTemplate(name, text)
+		if err != nil {
+			return nil, err
+		}
+
+		var result bytes.Buffer
+		err = tpl.Execute(&result, alert)
+		if err != nil {
+			return nil, err
+		}
+
+		value := strings.TrimSpace(result.String())
+		if strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[") {
+			var decoded interface{}
+			if json.Unmarshal([]byte(value), &decoded) == nil {
+				fields[name] = decoded
+				continue
+			}
+		}
+		fields[name] = result.String()
+	}
+	return fields, nil
+}
+
+// Sends a JSON request with basic auth and decodes the JSON response into result, if not nil
+func (s *Server) request(config *Config, method string, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(config.URL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(config.Username, config.Password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code from %v %v: %v", method, path, resp.StatusCode)
+	}
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, result)
+}
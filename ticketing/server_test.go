@@ -0,0 +1,377 @@
+package ticketing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/redhat-developer/observability-operator/v3/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func notification(status string, fingerprint string) string {
+	return `{
+	"version": "4",
+	"status": "` + status + `",
+	"alerts": [
+		{
+			"status": "` + status + `",
+			"fingerprint": "` + fingerprint + `",
+			"labels": {"alertname": "TargetDown", "job": "api"},
+			"annotations": {"summary": "api is down"},
+			"startsAt": "2021-08-04T12:00:00Z",
+			"endsAt": "2021-08-04T13:00:00Z"
+		}
+	]
+}`
+}
+
+// Request received by the ticketing system, with the JSON body decoded
+type received struct {
+	method string
+	uri    string
+	body   map[string]interface{}
+}
+
+// Ticketing system that records the requests and answers them with respond
+func newTicketingSystem(t *testing.T, respond func(r *http.Request) string) (*httptest.Server, *[]received) {
+	var requests []received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		request := received{method: r.Method, uri: r.URL.RequestURI()}
+		body, _ := ioutil.ReadAll(r.Body)
+		if len(body) > 0 {
+			err := json.Unmarshal(body, &request.body)
+			if err != nil {
+				t.Errorf("request body is not valid json: %v", err)
+			}
+		}
+		requests = append(requests, request)
+
+		response := respond(r)
+		if response == "error" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTestServer(t *testing.T, config *Config) *Server {
+	source, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err = ioutil.WriteFile(path, source, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewServer(path, log.NullLogger{})
+}
+
+func post(server *Server, body string) int {
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(body)))
+	return w.Code
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	system, requests := newTicketingSystem(t, func(r *http.Request) string {
+		return "error"
+	})
+
+	tests := []struct {
+		name     string
+		config   *Config
+		method   string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "only posts are accepted",
+			config:   &Config{Type: v1.AlertTicketingTypeServiceNow, URL: system.URL},
+			method:   http.MethodGet,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:     "invalid notification",
+			config:   &Config{Type: v1.AlertTicketingTypeServiceNow, URL: system.URL},
+			method:   http.MethodPost,
+			body:     "{",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unsupported type",
+			config:   &Config{Type: "bugzilla", URL: system.URL},
+			method:   http.MethodPost,
+			body:     notification("firing", "abc"),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "jira without jira config",
+			config:   &Config{Type: v1.AlertTicketingTypeJira, URL: system.URL},
+			method:   http.MethodPost,
+			body:     notification("firing", "abc"),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "alerts without fingerprint are skipped",
+			config:   &Config{Type: v1.AlertTicketingTypeServiceNow, URL: system.URL, Username: "user", Password: "password"},
+			method:   http.MethodPost,
+			body:     notification("firing", ""),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "failures of the ticketing system are returned to alertmanager",
+			config:   &Config{Type: v1.AlertTicketingTypeServiceNow, URL: system.URL, Username: "user", Password: "password"},
+			method:   http.MethodPost,
+			body:     notification("firing", "abc"),
+			wantCode: http.StatusBadGateway,
+		},
+		{
+			name:     "rejected credentials",
+			config:   &Config{Type: v1.AlertTicketingTypeServiceNow, URL: system.URL, Username: "user", Password: "wrong"},
+			method:   http.MethodPost,
+			body:     notification("firing", "abc"),
+			wantCode: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*requests = nil
+			server := newTestServer(t, tt.config)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, httptest.NewRequest(tt.method, "/alerts", strings.NewReader(tt.body)))
+			if w.Code != tt.wantCode {
+				t.Errorf("ServeHTTP() code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && len(*requests) != 0 {
+				t.Errorf("ServeHTTP() sent %v requests, want none", len(*requests))
+			}
+		})
+	}
+}
+
+func TestServer_serviceNow(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       Config
+		notification string
+		// sys_id of the open incident, if any
+		open         string
+		wantRequests []received
+	}{
+		{
+			name:         "firing alert opens an incident",
+			config:       Config{},
+			notification: notification("firing", "abc"),
+			wantRequests: []received{
+				{method: http.MethodGet, uri: "/api/now/table/incident?sysparm_fields=sys_id&sysparm_limit=1&sysparm_query=correlation_id%3Dobservability-abc%5Eactive%3Dtrue"},
+				{method: http.MethodPost, uri: "/api/now/table/incident", body: map[string]interface{}{
+					"correlation_id":    "observability-abc",
+					"short_description": "[FIRING] TargetDown: api is down",
+					"description":       "Labels: {\"alertname\":\"TargetDown\",\"job\":\"api\"}\nStarted: 2021-08-04 12:00:00 +0000 UTC\nSource: ",
+				}},
+			},
+		},
+		{
+			name: "fields and table of the config",
+			config: Config{
+				Fields:     map[string]string{"short_description": "{{ .Labels.job }}", "cmdb_ci": `{"value": "{{ .Labels.job }}"}`},
+				ServiceNow: &ServiceNow{Table: "em_event"},
+			},
+			notification: notification("firing", "abc"),
+			wantRequests: []received{
+				{method: http.MethodGet, uri: "/api/now/table/em_event?sysparm_fields=sys_id&sysparm_limit=1&sysparm_query=correlation_id%3Dobservability-abc%5Eactive%3Dtrue"},
+				{method: http.MethodPost, uri: "/api/now/table/em_event", body: map[string]interface{}{
+					"correlation_id":    "observability-abc",
+					"short_description": "api",
+					"cmdb_ci":           map[string]interface{}{"value": "api"},
+				}},
+			},
+		},
+		{
+			name:         "retried alert with an open incident",
+			config:       Config{},
+			notification: notification("firing", "abc"),
+			open:         "sys1",
+			wantRequests: []received{
+				{method: http.MethodGet, uri: "/api/now/table/incident?sysparm_fields=sys_id&sysparm_limit=1&sysparm_query=correlation_id%3Dobservability-abc%5Eactive%3Dtrue"},
+			},
+		},
+		{
+			name:         "resolved alert resolves the incident",
+			config:       Config{AutoResolve: true},
+			notification: notification("resolved", "abc"),
+			open:         "sys1",
+			wantRequests: []received{
+				{method: http.MethodGet, uri: "/api/now/table/incident?sysparm_fields=sys_id&sysparm_limit=1&sysparm_query=correlation_id%3Dobservability-abc%5Eactive%3Dtrue"},
+				{method: http.MethodPatch, uri: "/api/now/table/incident/sys1", body: map[string]interface{}{
+					"state":       "6",
+					"close_code":  "Resolved by caller",
+					"close_notes": "The alert TargetDown resolved at 2021-08-04 13:00:00 +0000 UTC",
+				}},
+			},
+		},
+		{
+			name:         "resolved alert without auto resolve",
+			config:       Config{},
+			notification: notification("resolved", "abc"),
+			open:         "sys1",
+			wantRequests: []received{
+				{method: http.MethodGet, uri: "/api/now/table/incident?sysparm_fields=sys_id&sysparm_limit=1&sysparm_query=correlation_id%3Dobservability-abc%5Eactive%3Dtrue"},
+			},
+		},
+		{
+			name:         "resolved alert without incident",
+			config:       Config{AutoResolve: true},
+			notification: notification("resolved", "abc"),
+			wantRequests: []received{
+				{method: http.MethodGet, uri: "/api/now/table/incident?sysparm_fields=sys_id&sysparm_limit=1&sysparm_query=correlation_id%3Dobservability-abc%5Eactive%3Dtrue"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			system, requests := newTicketingSystem(t, func(r *http.Request) string {
+				if r.Method == http.MethodGet && tt.open != "" {
+					return `{"result": [{"sys_id": "` + tt.open + `"}]}`
+				}
+				return `{"result": []}`
+			})
+			config := tt.config
+			config.Type = v1.AlertTicketingTypeServiceNow
+			config.URL = system.URL + "/"
+			config.Username = "user"
+			config.Password = "password"
+
+			if code := post(newTestServer(t, &config), tt.notification); code != http.StatusOK {
+				t.Fatalf("ServeHTTP() code = %v, want %v", code, http.StatusOK)
+			}
+			if !reflect.DeepEqual(*requests, tt.wantRequests) {
+				t.Errorf("requests = %+v, want %+v", *requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestServer_jira(t *testing.T) {
+	const search = "/rest/api/2/search?fields=key&jql=project+%3D+%22OPS%22+AND+labels+%3D+%22alert-abc%22+AND+statusCategory+%21%3D+Done&maxResults=1"
+	transitions := `{"transitions": [{"id": "11", "name": "In Progress"}, {"id": "31", "name": "Done"}]}`
+
+	tests := []struct {
+		name         string
+		config       Config
+		notification string
+		// key of the open issue, if any
+		open         string
+		wantCode     int
+		wantRequests []received
+	}{
+		{
+			name:         "firing alert opens an issue",
+			config:       Config{Jira: &Jira{Project: "OPS"}},
+			notification: notification("firing", "abc"),
+			wantCode:     http.StatusOK,
+			wantRequests: []received{
+				{method: http.MethodGet, uri: search},
+				{method: http.MethodPost, uri: "/rest/api/2/issue", body: map[string]interface{}{
+					"fields": map[string]interface{}{
+						"project":     map[string]interface{}{"key": "OPS"},
+						"issuetype":   map[string]interface{}{"name": "Task"},
+						"labels":      []interface{}{"alert-abc"},
+						"summary":     "[FIRING] TargetDown: api is down",
+						"description": "Labels: {\"alertname\":\"TargetDown\",\"job\":\"api\"}\nStarted: 2021-08-04 12:00:00 +0000 UTC\nSource: ",
+					},
+				}},
+			},
+		},
+		{
+			name: "labels of the fields are kept",
+			config: Config{
+				Jira:   &Jira{Project: "OPS", IssueType: "Bug"},
+				Fields: map[string]string{"summary": "{{ .Labels.alertname }}", "labels": `["{{ .Labels.job }}"]`},
+			},
+			notification: notification("firing", "abc"),
+			wantCode:     http.StatusOK,
+			wantRequests: []received{
+				{method: http.MethodGet, uri: search},
+				{method: http.MethodPost, uri: "/rest/api/2/issue", body: map[string]interface{}{
+					"fields": map[string]interface{}{
+						"project":   map[string]interface{}{"key": "OPS"},
+						"issuetype": map[string]interface{}{"name": "Bug"},
+						"labels":    []interface{}{"api", "alert-abc"},
+						"summary":   "TargetDown",
+					},
+				}},
+			},
+		},
+		{
+			name:         "resolved alert transitions the issue",
+			config:       Config{Jira: &Jira{Project: "OPS"}, AutoResolve: true},
+			notification: notification("resolved", "abc"),
+			open:         "OPS-1",
+			wantCode:     http.StatusOK,
+			wantRequests: []received{
+				{method: http.MethodGet, uri: search},
+				{method: http.MethodGet, uri: "/rest/api/2/issue/OPS-1/transitions"},
+				{method: http.MethodPost, uri: "/rest/api/2/issue/OPS-1/transitions", body: map[string]interface{}{
+					"transition": map[string]interface{}{"id": "31"},
+				}},
+			},
+		},
+		{
+			name:         "resolve transition of the config",
+			config:       Config{Jira: &Jira{Project: "OPS", ResolveTransition: "Closed"}, AutoResolve: true},
+			notification: notification("resolved", "abc"),
+			open:         "OPS-1",
+			wantCode:     http.StatusBadGateway,
+			wantRequests: []received{
+				{method: http.MethodGet, uri: search},
+				{method: http.MethodGet, uri: "/rest/api/2/issue/OPS-1/transitions"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			system, requests := newTicketingSystem(t, func(r *http.Request) string {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/transitions"):
+					return transitions
+				case r.URL.Path == "/rest/api/2/search" && tt.open != "":
+					return `{"issues": [{"key": "` + tt.open + `"}]}`
+				case r.URL.Path == "/rest/api/2/search":
+					return `{"issues": []}`
+				default:
+					return `{"key": "OPS-2"}`
+				}
+			})
+			config := tt.config
+			config.Type = v1.AlertTicketingTypeJira
+			config.URL = system.URL
+			config.Username = "user"
+			config.Password = "password"
+
+			if code := post(newTestServer(t, &config), tt.notification); code != tt.wantCode {
+				t.Fatalf("ServeHTTP() code = %v, want %v", code, tt.wantCode)
+			}
+			if !reflect.DeepEqual(*requests, tt.wantRequests) {
+				t.Errorf("requests = %+v, want %+v", *requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package ticketing
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/redhat-developer/observability-operator/v3/forwarder"
+)
+
+const (
+	DefaultServiceNowTable = "incident"
+	// Prefix of the correlation id of the incidents, followed by the fingerprint of the alert
+	serviceNowCorrelationPrefix = "observability-"
+)
+
+// Resolved incident with the close fields that most instances require
+var DefaultServiceNowResolveFields = map[string]string{
+	"state":       "6",
+	"close_code":  "Resolved by caller",
+	"close_notes": "The alert {{ .Labels.alertname }} resolved at {{ .EndsAt }}",
+}
+
+// Incidents are records of the table API, found again by their correlation id
+type serviceNow struct {
+	server *Server
+	config *Config
+}
+
+func (t *serviceNow) table() string {
+	if t.config.ServiceNow != nil && t.config.ServiceNow.Table != "" {
+		return t.config.ServiceNow.Table
+	}
+	return DefaultServiceNowTable
+}
+
+func (t *serviceNow) FindOpen(id string) (string, error) {
+	query := url.Values{}
+	query.Set("sysparm_query", fmt.Sprintf("correlation_id=%v%v^active=true", serviceNowCorrelationPrefix, id))
+	query.Set("sysparm_fields", "sys_id")
+	query.Set("sysparm_limit", "1")
+
+	result := struct {
+		Result []struct {
+			SysId string `json:"sys_id"`
+		} `json:"result"`
+	}{}
+	err := t.server.request(t.config, http.MethodGet, fmt.Sprintf("/api/now/table/%v?%v", t.table(), query.Encode()), nil, &result)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Result) == 0 {
+		return "", nil
+	}
+	return result.Result[0].SysId, nil
+}
+
+func (t *serviceNow) Open(id string, alert *forwarder.Alert) error {
+	templates := t.config.Fields
+	if len(templates) == 0 {
+		templates = map[string]string{
+			"short_description": DefaultSummaryTemplate,
+			"description":       DefaultDescriptionTemplate,
+		}
+	}
+	fields, err := renderFields(templates, alert)
+	if err != nil {
+		return err
+	}
+	fields["correlation_id"] = serviceNowCorrelationPrefix + id
+
+	return t.server.request(t.config, http.MethodPost, fmt.Sprintf("/api/now/table/%v", t.table()), fields, nil)
+}
+
+func (t *serviceNow) Resolve(ref string, alert *forwarder.Alert) error {
+	templates := DefaultServiceNowResolveFields
+	if t.config.ServiceNow != nil && len(t.config.ServiceNow.ResolveFields) > 0 {
+		templates = t.config.ServiceNow.ResolveFields
+	}
+	fields, err := renderFields(templates, alert)
+	if err != nil {
+		return err
+	}
+
+	return t.server.request(t.config, http.MethodPatch, fmt.Sprintf("/api/now/table/%v/%v", t.table(), ref), fields, nil)
+}